@@ -0,0 +1,144 @@
+// Package broadcast tells the other server instances about changes to state
+// they keep in memory, such as cached feature flags, over Postgres
+// LISTEN/NOTIFY, so instances stay consistent without sticky sessions.
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// channel is the Postgres notification channel all instances listen on.
+const channel = "eventplanner_broadcast"
+
+// Topics broadcast between instances.
+const (
+	// TopicFlags says feature flags in the database changed.
+	TopicFlags = "flags.changed"
+	// TopicConfigReload asks every instance to reload its configuration.
+	TopicConfigReload = "config.reload"
+)
+
+type message struct {
+	Origin  string          `json:"origin"`
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Broadcaster sends and receives broadcasts. Notifications sent while an
+// instance is not listening, e.g. during a reconnect, are lost to it, so
+// caches kept consistent this way should still expire.
+type Broadcaster struct {
+	pool   *pgxpool.Pool
+	origin string
+
+	mu       sync.RWMutex
+	handlers map[string][]func(ctx context.Context, payload json.RawMessage)
+}
+
+func New(pool *pgxpool.Pool) *Broadcaster {
+	// pid and start time tell instances apart, even containers that all run as pid 1
+	origin := strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	return &Broadcaster{pool: pool, origin: origin, handlers: map[string][]func(context.Context, json.RawMessage){}}
+}
+
+// Subscribe runs fn when another instance broadcasts on topic. The sender
+// applies its own changes and is not told about them.
+func (b *Broadcaster) Subscribe(topic string, fn func(ctx context.Context, payload json.RawMessage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], fn)
+}
+
+// Broadcast tells the other instances about a change on topic, with an
+// optional payload, which must stay well below Postgres' 8000 byte limit.
+// Failures are logged, since the change itself has already been made.
+func (b *Broadcaster) Broadcast(ctx context.Context, topic string, payload any) {
+	msg := message{Origin: b.origin, Topic: topic}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			log.Printf("broadcast %s: %v", topic, err)
+			return
+		}
+		msg.Payload = raw
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("broadcast %s: %v", topic, err)
+		return
+	}
+	if _, err := b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, string(body)); err != nil {
+		log.Printf("broadcast %s: %v", topic, err)
+	}
+}
+
+// Start listens for broadcasts until ctx is done, on a connection of its
+// own so it does not take one from the pool. A lost connection is opened
+// again with exponential backoff up to a minute.
+func (b *Broadcaster) Start(ctx context.Context) {
+	go func() {
+		delay := time.Second
+		for {
+			listening, err := b.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if listening {
+				delay = time.Second
+			}
+			log.Printf("broadcast: listening failed, retrying in %s: %v", delay, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, time.Minute)
+		}
+	}()
+}
+
+// listen connects and dispatches notifications until the connection fails.
+// It reports whether it got as far as listening.
+func (b *Broadcaster) listen(ctx context.Context) (bool, error) {
+	conn, err := pgx.ConnectConfig(ctx, b.pool.Config().ConnConfig)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return false, err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		b.dispatch(ctx, n.Payload)
+	}
+}
+
+func (b *Broadcaster) dispatch(ctx context.Context, raw string) {
+	var msg message
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		log.Printf("broadcast: undecodable notification: %v", err)
+		return
+	}
+	if msg.Origin == b.origin {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[msg.Topic]
+	b.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(ctx, msg.Payload)
+	}
+}
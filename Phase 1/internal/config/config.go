@@ -0,0 +1,166 @@
+// Package config prepares the environment the server reads its settings
+// from: the defaults of the selected profile, and secrets kept in files or
+// Vault instead of plain variables.
+package config
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Profiles, selected with APP_ENV.
+const (
+	Dev     = "dev"
+	Staging = "staging"
+	Prod    = "prod"
+)
+
+const defaultDir = "config"
+
+// Config is the loaded profile.
+type Config struct {
+	Profile string
+
+	mu       sync.Mutex
+	path     string
+	environ  map[string]bool // variables set before the profile was read
+	fromFile map[string]bool
+}
+
+// Load reads the profile named by APP_ENV (dev by default) from
+// CONFIG_DIR/<profile>.env and sets the variables it lists that are not set
+// already, so the environment overrides the profile. Then every variable
+// whose value is a secret reference is replaced by the secret:
+//
+//	file:/run/secrets/db_url          the file's content
+//	vault:secret/data/eventplanner#db the field of a Vault secret
+func Load(ctx context.Context) (*Config, error) {
+	profile := os.Getenv("APP_ENV")
+	if profile == "" {
+		profile = Dev
+	}
+	if profile != Dev && profile != Staging && profile != Prod {
+		return nil, fmt.Errorf("unknown APP_ENV %q (dev, staging or prod)", profile)
+	}
+	dir := os.Getenv("CONFIG_DIR")
+	if dir == "" {
+		dir = defaultDir
+	}
+	c := &Config{Profile: profile, path: filepath.Join(dir, profile+".env"), environ: map[string]bool{}, fromFile: map[string]bool{}}
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		c.environ[key] = true
+	}
+	if err := c.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the profile file again: variables it sets are updated and
+// those it no longer lists are unset, except for the ones set in the
+// environment at startup. Settings read once at startup keep their values
+// until the server restarts.
+func (c *Config) Reload(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values, err := ReadFile(c.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	listed := map[string]bool{}
+	for _, v := range values {
+		if c.environ[v.Key] {
+			continue
+		}
+		os.Setenv(v.Key, v.Value)
+		listed[v.Key] = true
+	}
+	for key := range c.fromFile {
+		if !listed[key] {
+			os.Unsetenv(key)
+		}
+	}
+	c.fromFile = listed
+	return resolveSecrets(ctx)
+}
+
+// Value is a variable set in a profile file.
+type Value struct {
+	Key   string
+	Value string
+}
+
+// ReadFile parses a profile file: KEY=value lines, optionally prefixed with
+// export and quoted, blank lines and # comments.
+func ReadFile(path string) ([]Value, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []Value
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		res = append(res, Value{Key: key, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return res, nil
+}
+
+// resolveSecrets replaces secret references in the environment. The Vault
+// token may be a file reference itself, so file references go first.
+func resolveSecrets(ctx context.Context) error {
+	var vaultKeys []string
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		switch {
+		case strings.HasPrefix(value, "file:"):
+			secret, err := os.ReadFile(strings.TrimPrefix(value, "file:"))
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			os.Setenv(key, strings.TrimRight(string(secret), "\r\n"))
+		case strings.HasPrefix(value, "vault:"):
+			vaultKeys = append(vaultKeys, key)
+		}
+	}
+	if len(vaultKeys) == 0 {
+		return nil
+	}
+	vault, err := NewVaultFromEnv()
+	if err != nil {
+		return err
+	}
+	for _, key := range vaultKeys {
+		secret, err := vault.Secret(ctx, strings.TrimPrefix(os.Getenv(key), "vault:"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		os.Setenv(key, secret)
+	}
+	return nil
+}
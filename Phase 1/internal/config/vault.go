@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Vault reads secrets from HashiCorp Vault's KV secrets engine, version 1
+// or 2, over its HTTP API.
+type Vault struct {
+	addr   string
+	token  string
+	client *http.Client
+	read   map[string]map[string]any
+}
+
+// NewVault creates a Vault client authenticating with token.
+func NewVault(addr, token string) *Vault {
+	return &Vault{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+		read:   map[string]map[string]any{},
+	}
+}
+
+// NewVaultFromEnv creates a Vault client from VAULT_ADDR and VAULT_TOKEN.
+func NewVaultFromEnv() (*Vault, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault references need VAULT_ADDR and VAULT_TOKEN")
+	}
+	return NewVault(addr, token), nil
+}
+
+// Secret returns a field of a secret, referenced as path#field, e.g.
+// secret/data/eventplanner#database_url. Each path is read once.
+func (v *Vault) Secret(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault: reference %q is not path#field", ref)
+	}
+	data, ok := v.read[path]
+	if !ok {
+		var err error
+		if data, err = v.get(ctx, path); err != nil {
+			return "", err
+		}
+		v.read[path] = data
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault: %s has no field %s", path, field)
+	}
+	return value, nil
+}
+
+func (v *Vault) get(ctx context.Context, path string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault: reading %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	// KV version 2 nests the secret in data.data, next to its metadata.
+	if nested, ok := body.Data["data"].(map[string]any); ok {
+		if _, versioned := body.Data["metadata"]; versioned {
+			return nested, nil
+		}
+	}
+	return body.Data, nil
+}
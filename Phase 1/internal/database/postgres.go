@@ -2,23 +2,94 @@ package database
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// NewPostgresPool creates a pgx connection pool with sane defaults.
-func NewPostgresPool(databaseURL string) (*pgxpool.Pool, error) {
+// PoolOptions size the connection pool and time its connections.
+type PoolOptions struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	ConnectTimeout    time.Duration
+}
+
+// DefaultPoolOptions suit a single small instance.
+var DefaultPoolOptions = PoolOptions{
+	MaxConns:          10,
+	MinConns:          1,
+	MaxConnLifetime:   time.Hour,
+	MaxConnIdleTime:   5 * time.Minute,
+	HealthCheckPeriod: 30 * time.Second,
+	ConnectTimeout:    5 * time.Second,
+}
+
+// PoolOptionsFromEnv reads DB_MAX_CONNS, DB_MIN_CONNS and, as durations such
+// as 30s or 5m, DB_MAX_CONN_LIFETIME, DB_MAX_CONN_IDLE_TIME,
+// DB_HEALTH_CHECK_PERIOD and DB_CONNECT_TIMEOUT. Unset ones keep their
+// default.
+func PoolOptionsFromEnv() (PoolOptions, error) {
+	opts := DefaultPoolOptions
+	for _, v := range []struct {
+		name string
+		dest *int32
+	}{{"DB_MAX_CONNS", &opts.MaxConns}, {"DB_MIN_CONNS", &opts.MinConns}} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid %s %q", v.name, raw)
+		}
+		*v.dest = int32(n)
+	}
+	for _, v := range []struct {
+		name string
+		dest *time.Duration
+	}{
+		{"DB_MAX_CONN_LIFETIME", &opts.MaxConnLifetime},
+		{"DB_MAX_CONN_IDLE_TIME", &opts.MaxConnIdleTime},
+		{"DB_HEALTH_CHECK_PERIOD", &opts.HealthCheckPeriod},
+		{"DB_CONNECT_TIMEOUT", &opts.ConnectTimeout},
+	} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return opts, fmt.Errorf("invalid %s %q", v.name, raw)
+		}
+		*v.dest = d
+	}
+	if opts.MaxConns < 1 || opts.MinConns > opts.MaxConns {
+		return opts, fmt.Errorf("DB_MAX_CONNS must be at least 1 and at least DB_MIN_CONNS")
+	}
+	return opts, nil
+}
+
+// NewPostgresPool creates a pgx connection pool and checks that it connects.
+func NewPostgresPool(databaseURL string, opts PoolOptions) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
 		return nil, err
 	}
-	config.MaxConns = 10
-	config.MinConns = 1
-	config.MaxConnIdleTime = 5 * time.Minute
-	config.HealthCheckPeriod = 30 * time.Second
+	config.MaxConns = opts.MaxConns
+	config.MinConns = opts.MinConns
+	config.MaxConnLifetime = opts.MaxConnLifetime
+	config.MaxConnIdleTime = opts.MaxConnIdleTime
+	config.HealthCheckPeriod = opts.HealthCheckPeriod
+	config.ConnConfig.ConnectTimeout = opts.ConnectTimeout
+	config.ConnConfig.Tracer = &QueryLogger{Threshold: SlowQueryFromEnv()}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ConnectTimeout)
 	defer cancel()
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
@@ -0,0 +1,312 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"eventplanner-backend/internal/metrics"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrUnavailable is returned without touching the database while the
+// circuit breaker is open.
+var ErrUnavailable = errors.New("database unavailable")
+
+var (
+	retriesTotal  = metrics.NewCounter("db_retries_total", "Statements retried after a transient error.")
+	circuitOpened = metrics.NewCounter("db_circuit_opened_total", "Times the database circuit breaker opened.")
+	circuitShed   = metrics.NewCounter("db_circuit_rejected_total", "Statements refused while the circuit breaker was open.")
+)
+
+// ResilienceOptions control retries and the circuit breaker.
+type ResilienceOptions struct {
+	Retries          int           // extra attempts after a transient error
+	Backoff          time.Duration // wait before the first retry, doubled after each
+	BreakerThreshold int           // consecutive connection failures that open the breaker; 0 disables it
+	BreakerCooldown  time.Duration // how long the breaker stays open
+}
+
+// DefaultResilienceOptions retry twice and give the database ten seconds
+// after five failures in a row.
+var DefaultResilienceOptions = ResilienceOptions{
+	Retries:          2,
+	Backoff:          50 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  10 * time.Second,
+}
+
+// ResilienceOptionsFromEnv reads DB_RETRIES, DB_BREAKER_THRESHOLD and, as
+// durations, DB_RETRY_BACKOFF and DB_BREAKER_COOLDOWN. Unset ones keep their
+// default.
+func ResilienceOptionsFromEnv() (ResilienceOptions, error) {
+	opts := DefaultResilienceOptions
+	for _, v := range []struct {
+		name string
+		dest *int
+	}{{"DB_RETRIES", &opts.Retries}, {"DB_BREAKER_THRESHOLD", &opts.BreakerThreshold}} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid %s %q", v.name, raw)
+		}
+		*v.dest = n
+	}
+	for _, v := range []struct {
+		name string
+		dest *time.Duration
+	}{{"DB_RETRY_BACKOFF", &opts.Backoff}, {"DB_BREAKER_COOLDOWN", &opts.BreakerCooldown}} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return opts, fmt.Errorf("invalid %s %q", v.name, raw)
+		}
+		*v.dest = d
+	}
+	return opts, nil
+}
+
+// Breaker opens after a run of failures that mean the database cannot be
+// reached, and refuses calls until its cooldown has passed. The first call
+// after that decides: a success closes it, another failure reopens it.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether calls may go to the database and, if not, how long
+// until they may again.
+func (b *Breaker) Allow() (bool, time.Duration) {
+	if b == nil || b.threshold == 0 {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return false, wait
+	}
+	return true, 0
+}
+
+// Record counts the outcome of a call. Errors the server answered with,
+// such as constraint violations or no rows, show the database is up.
+func (b *Breaker) Record(err error) {
+	if b == nil || b.threshold == 0 {
+		return
+	}
+	down := unreachable(err)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !down {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	b.openUntil = time.Now().Add(b.cooldown)
+	circuitOpened.Inc()
+	log.Printf("database: circuit open for %s after %d failures: %v", b.cooldown, b.failures, err)
+}
+
+// DB wraps the pool so that Query, QueryRow, Exec and Begin retry
+// transient errors and respect the breaker. Statements inside a
+// transaction are not retried; the transaction's caller owns those.
+type DB struct {
+	*pgxpool.Pool
+	opts    ResilienceOptions
+	breaker *Breaker
+}
+
+// NewDB wraps pool and exports its breaker's state as a metric. Call it
+// once.
+func NewDB(pool *pgxpool.Pool, opts ResilienceOptions) *DB {
+	breaker := NewBreaker(opts.BreakerThreshold, opts.BreakerCooldown)
+	metrics.NewGaugeFunc("db_circuit_open", "1 while the database circuit breaker is open.", func() float64 {
+		if ok, _ := breaker.Allow(); ok {
+			return 0
+		}
+		return 1
+	})
+	return &DB{Pool: pool, opts: opts, breaker: breaker}
+}
+
+// Breaker returns the breaker guarding db.
+func (db *DB) Breaker() *Breaker {
+	return db.breaker
+}
+
+func (db *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := db.retry(ctx, func() (err error) {
+		rows, err = db.Pool.Query(ctx, sql, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &recordedRows{Rows: rows, breaker: db.breaker}, nil
+}
+
+// recordedRows reports the error that ends the result, which pgx returns
+// from Err rather than from Query, to the breaker. Rows that were partly
+// read cannot be retried.
+type recordedRows struct {
+	pgx.Rows
+	breaker  *Breaker
+	recorded bool
+}
+
+func (r *recordedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.record()
+	return false
+}
+
+func (r *recordedRows) Close() {
+	r.Rows.Close()
+	r.record()
+}
+
+func (r *recordedRows) record() {
+	if r.recorded {
+		return
+	}
+	r.recorded = true
+	if err := r.Rows.Err(); err != nil {
+		r.breaker.Record(err)
+	}
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &retryRow{db: db, ctx: ctx, sql: sql, args: args}
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := db.retry(ctx, func() (err error) {
+		tag, err = db.Pool.Exec(ctx, sql, args...)
+		return err
+	})
+	return tag, err
+}
+
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (db *DB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	var tx pgx.Tx
+	err := db.retry(ctx, func() (err error) {
+		tx, err = db.Pool.BeginTx(ctx, txOptions)
+		return err
+	})
+	return tx, err
+}
+
+// retryRow defers the query to Scan, where QueryRow reports its errors.
+type retryRow struct {
+	db   *DB
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (r *retryRow) Scan(dest ...any) error {
+	return r.db.retry(r.ctx, func() error {
+		return r.db.Pool.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
+
+// retry runs fn, again after a jittered exponential backoff while it fails
+// transiently and attempts remain.
+func (db *DB) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if ok, _ := db.breaker.Allow(); !ok {
+			circuitShed.Inc()
+			return ErrUnavailable
+		}
+		err := fn()
+		db.breaker.Record(err)
+		if err == nil || attempt >= db.opts.Retries || ctx.Err() != nil || !transient(err) {
+			return err
+		}
+		retriesTotal.Inc()
+		delay := db.opts.Backoff << attempt
+		delay += rand.N(delay/2 + 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// transient reports whether err may go away when the statement is run
+// again: serialization failures and deadlocks, which roll the statement
+// back, and connection failures that happened before it was sent.
+func transient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr) || pgconn.SafeToRetry(err)
+}
+
+// unreachable reports whether err means the database could not be reached
+// or refused work, as opposed to rejecting the statement. Statements that
+// ran out of time say nothing about the database being up: slow queries
+// must not open the breaker.
+func unreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// connection exceptions, insufficient resources, shutdowns
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") ||
+			pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestUnreachable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"own timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{"read timeout", &net.OpError{Op: "read", Net: "tcp", Err: context.DeadlineExceeded}, false},
+		{"no rows", pgx.ErrNoRows, false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"statement timeout", &pgconn.PgError{Code: "57014"}, false},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, true},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"dial", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}, true},
+		{"refused", fmt.Errorf("write: %w", syscall.ECONNREFUSED), true},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+	}
+	for _, tt := range tests {
+		if got := unreachable(tt.err); got != tt.want {
+			t.Errorf("%s: unreachable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBreakerIgnoresTimeouts(t *testing.T) {
+	b := NewBreaker(2, time.Minute)
+	for i := 0; i < 5; i++ {
+		b.Record(context.DeadlineExceeded)
+	}
+	if ok, _ := b.Allow(); !ok {
+		t.Fatal("breaker opened after timeouts")
+	}
+	b.Record(&pgconn.PgError{Code: "08006"})
+	b.Record(&pgconn.PgError{Code: "08006"})
+	if ok, _ := b.Allow(); ok {
+		t.Fatal("breaker still closed after connection failures")
+	}
+}
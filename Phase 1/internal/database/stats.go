@@ -0,0 +1,56 @@
+package database
+
+import (
+	"eventplanner-backend/internal/metrics"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Stats reads the pool's current statistics.
+func Stats(pool *pgxpool.Pool) models.PoolStats {
+	s := pool.Stat()
+	return models.PoolStats{
+		MaxConns:                s.MaxConns(),
+		TotalConns:              s.TotalConns(),
+		AcquiredConns:           s.AcquiredConns(),
+		IdleConns:               s.IdleConns(),
+		ConstructingConns:       s.ConstructingConns(),
+		AcquireCount:            s.AcquireCount(),
+		EmptyAcquireCount:       s.EmptyAcquireCount(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		AcquireDurationSeconds:  s.AcquireDuration().Seconds(),
+		NewConnsCount:           s.NewConnsCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
+	}
+}
+
+// RegisterPoolMetrics exposes the pool's statistics as metrics. Call it once.
+func RegisterPoolMetrics(pool *pgxpool.Pool) {
+	gauges := []struct {
+		name, help string
+		value      func(*pgxpool.Stat) float64
+	}{
+		{"db_pool_max_conns", "Largest number of connections the pool opens.", func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }},
+		{"db_pool_total_conns", "Open connections.", func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }},
+		{"db_pool_acquired_conns", "Connections in use.", func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }},
+		{"db_pool_idle_conns", "Idle connections.", func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }},
+	}
+	for _, g := range gauges {
+		metrics.NewGaugeFunc(g.name, g.help, func() float64 { return g.value(pool.Stat()) })
+	}
+	counters := []struct {
+		name, help string
+		value      func(*pgxpool.Stat) float64
+	}{
+		{"db_pool_acquires_total", "Connections taken from the pool.", func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }},
+		{"db_pool_empty_acquires_total", "Acquires that waited because no connection was idle.", func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }},
+		{"db_pool_canceled_acquires_total", "Acquires canceled while waiting.", func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) }},
+		{"db_pool_acquire_seconds_total", "Time spent acquiring connections.", func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }},
+		{"db_pool_new_conns_total", "Connections opened.", func(s *pgxpool.Stat) float64 { return float64(s.NewConnsCount()) }},
+	}
+	for _, c := range counters {
+		metrics.NewCounterFunc(c.name, c.help, func() float64 { return c.value(pool.Stat()) })
+	}
+}
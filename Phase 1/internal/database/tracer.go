@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/metrics"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultSlowQuery = 200 * time.Millisecond
+
+var (
+	queriesTotal = metrics.NewCounter("db_queries_total", "Queries run against the database.")
+	queryErrors  = metrics.NewCounter("db_query_errors_total", "Queries that failed.")
+	slowQueries  = metrics.NewCounter("db_slow_queries_total", "Queries slower than DB_SLOW_QUERY_MS, by statement.", "statement")
+)
+
+// SlowQueryFromEnv reads DB_SLOW_QUERY_MS, the latency above which queries
+// are logged (default 200). 0 logs every query, a negative value none.
+func SlowQueryFromEnv() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("DB_SLOW_QUERY_MS")); err == nil {
+		if v < 0 {
+			return -1
+		}
+		return time.Duration(v) * time.Millisecond
+	}
+	return defaultSlowQuery
+}
+
+// QueryLogger is a pgx tracer counting queries and logging those slower
+// than Threshold. Logged parameters show their type only, since they hold
+// user data such as emails and search terms.
+type QueryLogger struct {
+	Threshold time.Duration // negative logs nothing
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+func (l *QueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+func (l *QueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+	queriesTotal.Inc()
+	if data.Err != nil {
+		queryErrors.Inc()
+	}
+	if l.Threshold < 0 || elapsed < l.Threshold {
+		return
+	}
+	slowQueries.Inc(statement(start.sql))
+	log.Printf("slow query (%s): %s args: %s", elapsed.Round(time.Millisecond), compact(start.sql), redact(start.args))
+}
+
+// statement returns the lowercase first keyword of the SQL, e.g. select,
+// which keeps the metric's labels few.
+func statement(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other"
+	}
+	switch word := strings.ToLower(fields[0]); word {
+	case "select", "insert", "update", "delete", "with":
+		return word
+	}
+	return "other"
+}
+
+// compact puts the SQL on one line.
+func compact(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// redact describes the arguments without their values, e.g.
+// [$1 int, $2 string(12)].
+func redact(args []any) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		desc := fmt.Sprintf("%T", arg)
+		switch v := arg.(type) {
+		case nil:
+			desc = "null"
+		case string:
+			desc = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			desc = fmt.Sprintf("bytes(%d)", len(v))
+		}
+		parts[i] = fmt.Sprintf("$%d %s", i+1, desc)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,14 @@
+// Package docs embeds the OpenAPI document generated from the handler annotations.
+package docs
+
+import _ "embed"
+
+//go:generate go run ./gen -handlers ../handlers -types ../models,../graph -router ../router/router.go -out openapi.json
+
+//go:embed openapi.json
+var spec []byte
+
+// OpenAPI returns the generated OpenAPI 3 document as JSON.
+func OpenAPI() []byte {
+	return spec
+}
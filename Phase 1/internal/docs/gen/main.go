@@ -0,0 +1,499 @@
+// Command gen builds the OpenAPI 3 document served at /openapi.json from the
+// swag-style annotations on the HTTP handlers. It fails when the router
+// registers a route that has no matching @Router annotation (or vice versa),
+// so the spec cannot silently drift from the code.
+//
+// Run it through `go generate ./internal/docs`.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type generator struct {
+	// structs maps qualified type names (e.g. "models.Event") to their definitions.
+	structs map[string]*ast.StructType
+	// named maps non-struct named types (e.g. "models.Permission") to their underlying type.
+	named      map[string]ast.Expr
+	schemas    map[string]any
+	paths      map[string]map[string]any
+	documented map[string]bool
+}
+
+func main() {
+	handlersDir := flag.String("handlers", "../handlers", "directory containing the annotated handlers")
+	typeDirs := flag.String("types", "../models", "comma-separated directories of packages whose types appear in annotations")
+	routerFile := flag.String("router", "../router/router.go", "router file registering the routes")
+	out := flag.String("out", "openapi.json", "output file")
+	flag.Parse()
+
+	g := &generator{
+		structs:    map[string]*ast.StructType{},
+		named:      map[string]ast.Expr{},
+		schemas:    map[string]any{},
+		paths:      map[string]map[string]any{},
+		documented: map[string]bool{},
+	}
+
+	handlerFiles, err := parseDir(*handlersDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g.collectStructs(handlerFiles)
+	for _, dir := range strings.Split(*typeDirs, ",") {
+		files, err := parseDir(strings.TrimSpace(dir))
+		if err != nil {
+			log.Fatal(err)
+		}
+		g.collectStructs(files)
+	}
+
+	for _, f := range handlerFiles {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			if err := g.addOperation(fn); err != nil {
+				log.Fatalf("%s: %v", fn.Name.Name, err)
+			}
+		}
+	}
+
+	routes, err := registeredRoutes(*routerFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var problems []string
+	for _, r := range routes {
+		if !g.documented[r] {
+			problems = append(problems, "route without @Router annotation: "+r)
+		}
+		delete(g.documented, r)
+	}
+	for r := range g.documented {
+		problems = append(problems, "annotated route not registered in router: "+r)
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		log.Fatal(strings.Join(problems, "\n"))
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "EventPlanner API",
+			"description": "REST API for the EventPlanner backend. Authenticated endpoints expect the caller's user id in the X-User-ID header.",
+			"version":     "1.0.0",
+		},
+		"servers": []any{map[string]any{"url": "http://localhost:8080"}},
+		"paths":   g.paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"ApiKeyAuth": map[string]any{"type": "apiKey", "in": "header", "name": "X-User-ID"},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseDir(dir string) ([]*ast.File, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, m := range matches {
+		if strings.HasSuffix(m, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, m, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func (g *generator) collectStructs(files []*ast.File) {
+	for _, f := range files {
+		pkg := f.Name.Name
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				g.structs[pkg+"."+ts.Name.Name] = st
+			} else {
+				g.named[pkg+"."+ts.Name.Name] = ts.Type
+			}
+			return true
+		})
+	}
+}
+
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// registeredRoutes returns "METHOD /path" for every literal route registered in the router file.
+func registeredRoutes(file string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var routes []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		path, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		routes = append(routes, sel.Sel.Name+" "+ginParam.ReplaceAllString(path, "{$1}"))
+		return true
+	})
+	return routes, nil
+}
+
+func (g *generator) addOperation(fn *ast.FuncDecl) error {
+	op := map[string]any{}
+	var path, method string
+	var params []any
+	responses := map[string]any{}
+	produce := "application/json"
+	var security []any
+
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		key, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		switch strings.ToLower(key) {
+		case "@summary":
+			op["summary"] = rest
+		case "@description":
+			if d, ok := op["description"].(string); ok {
+				rest = d + "\n" + rest
+			}
+			op["description"] = rest
+		case "@tags":
+			var tags []any
+			for _, t := range strings.Split(rest, ",") {
+				tags = append(tags, strings.TrimSpace(t))
+			}
+			op["tags"] = tags
+		case "@produce":
+			produce = mimeType(rest)
+		case "@security":
+			security = append(security, map[string]any{rest: []any{}})
+		case "@param":
+			p, body, err := g.parseParam(rest)
+			if err != nil {
+				return err
+			}
+			if body != nil {
+				op["requestBody"] = body
+			} else {
+				params = append(params, p)
+			}
+		case "@success", "@failure":
+			code, resp, err := g.parseResponse(rest, produce)
+			if err != nil {
+				return err
+			}
+			responses[code] = resp
+		case "@router":
+			fields := strings.Fields(rest)
+			if len(fields) != 2 {
+				return fmt.Errorf("malformed @Router %q", rest)
+			}
+			path = fields[0]
+			method = strings.ToLower(strings.Trim(fields[1], "[]"))
+		}
+	}
+	if path == "" {
+		return nil
+	}
+	if recv := receiverName(fn); recv != "" {
+		op["operationId"] = recv + "." + fn.Name.Name
+	} else {
+		op["operationId"] = fn.Name.Name
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]any{"description": "OK"}
+	}
+	op["responses"] = responses
+	if len(security) > 0 {
+		op["security"] = security
+	}
+	if g.paths[path] == nil {
+		g.paths[path] = map[string]any{}
+	}
+	g.paths[path][method] = op
+	g.documented[strings.ToUpper(method)+" "+path] = true
+	return nil
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func mimeType(s string) string {
+	switch s {
+	case "json":
+		return "application/json"
+	case "html":
+		return "text/html"
+	case "plain":
+		return "text/plain"
+	}
+	return s
+}
+
+// parseParam handles `name in type required "description"`.
+func (g *generator) parseParam(s string) (map[string]any, map[string]any, error) {
+	fields, desc := splitDescription(s)
+	if len(fields) < 4 {
+		return nil, nil, fmt.Errorf("malformed @Param %q", s)
+	}
+	name, in, typ := fields[0], fields[1], fields[2]
+	required := fields[3] == "true"
+	if in == "body" {
+		schema, err := g.schemaFromString(typ)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, map[string]any{
+			"description": desc,
+			"required":    required,
+			"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+		}, nil
+	}
+	p := map[string]any{
+		"name":     name,
+		"in":       in,
+		"required": required || in == "path",
+		"schema":   primitiveSchema(typ),
+	}
+	if desc != "" {
+		p["description"] = desc
+	}
+	return p, nil, nil
+}
+
+// parseResponse handles `code {kind} Type "description"`.
+func (g *generator) parseResponse(s, produce string) (string, map[string]any, error) {
+	fields, desc := splitDescription(s)
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("malformed response %q", s)
+	}
+	code := fields[0]
+	if desc == "" {
+		n, _ := strconv.Atoi(code)
+		desc = http.StatusText(n)
+	}
+	resp := map[string]any{"description": desc}
+	if len(fields) >= 3 {
+		schema, err := g.schemaFromString(fields[2])
+		if err != nil {
+			return "", nil, err
+		}
+		if fields[1] == "{array}" {
+			schema = map[string]any{"type": "array", "items": schema}
+		}
+		resp["content"] = map[string]any{produce: map[string]any{"schema": schema}}
+	}
+	return code, resp, nil
+}
+
+func splitDescription(s string) ([]string, string) {
+	if i := strings.Index(s, `"`); i >= 0 {
+		desc := strings.TrimSpace(s[i:])
+		if j := strings.LastIndex(desc, `"`); j > 0 {
+			desc = desc[1:j]
+		}
+		return strings.Fields(s[:i]), desc
+	}
+	return strings.Fields(s), ""
+}
+
+func primitiveSchema(typ string) map[string]any {
+	switch typ {
+	case "int", "integer":
+		return map[string]any{"type": "integer"}
+	case "number", "float", "float64":
+		return map[string]any{"type": "number"}
+	case "bool", "boolean":
+		return map[string]any{"type": "boolean"}
+	}
+	return map[string]any{"type": "string"}
+}
+
+func (g *generator) schemaFromString(typ string) (map[string]any, error) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil, fmt.Errorf("bad type %q: %w", typ, err)
+	}
+	return g.schemaFor(expr, "handlers"), nil
+}
+
+func (g *generator) schemaFor(expr ast.Expr, pkg string) map[string]any {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return map[string]any{"type": "string"}
+		case "int", "int32", "int64", "uint", "uint32", "uint64":
+			return map[string]any{"type": "integer"}
+		case "float32", "float64":
+			return map[string]any{"type": "number"}
+		case "bool":
+			return map[string]any{"type": "boolean"}
+		case "any":
+			return map[string]any{}
+		}
+		return g.ref(pkg + "." + t.Name)
+	case *ast.StarExpr:
+		return g.schemaFor(t.X, pkg)
+	case *ast.ArrayType:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elt, pkg)}
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Value, pkg)}
+	case *ast.InterfaceType:
+		return map[string]any{}
+	case *ast.StructType:
+		return g.structSchema(t, pkg)
+	case *ast.SelectorExpr:
+		x, _ := t.X.(*ast.Ident)
+		if x == nil {
+			return map[string]any{}
+		}
+		switch x.Name + "." + t.Sel.Name {
+		case "time.Time":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case "json.RawMessage":
+			return map[string]any{}
+		}
+		return g.ref(x.Name + "." + t.Sel.Name)
+	}
+	return map[string]any{}
+}
+
+func (g *generator) ref(name string) map[string]any {
+	st, ok := g.structs[name]
+	if !ok {
+		if under, ok := g.named[name]; ok {
+			pkg, _, _ := strings.Cut(name, ".")
+			return g.schemaFor(under, pkg)
+		}
+		return map[string]any{}
+	}
+	if _, done := g.schemas[name]; !done {
+		g.schemas[name] = map[string]any{} // placeholder guards against recursive types
+		pkg, _, _ := strings.Cut(name, ".")
+		g.schemas[name] = g.structSchema(st, pkg)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *generator) structSchema(st *ast.StructType, pkg string) map[string]any {
+	props := map[string]any{}
+	var required []any
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			raw, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+		jsonName, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if len(f.Names) == 0 {
+			// Embedded struct: inline its fields.
+			if emb, ok := g.schemaFor(f.Type, pkg)["$ref"].(string); ok {
+				name := strings.TrimPrefix(emb, "#/components/schemas/")
+				if s, ok := g.schemas[name].(map[string]any); ok {
+					if p, ok := s["properties"].(map[string]any); ok {
+						for k, v := range p {
+							props[k] = v
+						}
+					}
+				}
+			}
+			continue
+		}
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			name := jsonName
+			if name == "" {
+				name = n.Name
+			}
+			props[name] = g.schemaFor(f.Type, pkg)
+			if strings.Contains(tag.Get("binding"), "required") && !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
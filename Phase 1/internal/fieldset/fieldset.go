@@ -0,0 +1,177 @@
+// Package fieldset encodes responses with only the fields a client asked for,
+// like the fields parameter of JSON:API, so clients on slow connections can
+// skip what they do not show.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Fields is a set of JSON field names to keep. A nil set keeps every field.
+type Fields map[string]bool
+
+// Selection maps where fields are selected to the fields kept there: "" is
+// the response itself, any other key a member of the response object. Where
+// the value is an array, the fields are selected in each of its elements.
+type Selection map[string]Fields
+
+// Parse reads a comma-separated list of field names, checking each against
+// the JSON fields of model, a struct or a pointer or slice of one. An empty
+// list gives a nil set.
+func Parse(list string, model any) (Fields, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	known := Fields{}
+	for _, name := range Names(reflect.TypeOf(model)) {
+		known[name] = true
+	}
+	fields := Fields{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// Names lists the JSON field names of a struct type, including those of
+// embedded structs, in the order they are encoded.
+func Names(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case tag == "-" || !f.IsExported() && !f.Anonymous:
+		case f.Anonymous && tag == "":
+			names = append(names, Names(f.Type)...)
+		case tag != "":
+			names = append(names, tag)
+		default:
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// Marshal encodes v as JSON with only the selected fields, keeping their
+// order.
+func Marshal(v any, sel Selection) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if fields := sel[""]; fields != nil {
+		return pick(data, fields)
+	}
+	members := Selection{}
+	for key, fields := range sel {
+		if fields != nil {
+			members[key] = fields
+		}
+	}
+	if len(members) == 0 {
+		return data, nil
+	}
+	return eachMember(data, func(key string, value json.RawMessage) (json.RawMessage, bool, error) {
+		if fields, ok := members[key]; ok {
+			value, err := pick(value, fields)
+			return value, true, err
+		}
+		return value, true, nil
+	})
+}
+
+// pick keeps only the given fields of an object, or of each object in an
+// array. Other values are returned as they are.
+func pick(data json.RawMessage, fields Fields) (json.RawMessage, error) {
+	switch firstByte(data) {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range items {
+			picked, err := pick(item, fields)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(picked)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	case '{':
+		return eachMember(data, func(key string, value json.RawMessage) (json.RawMessage, bool, error) {
+			return value, fields[key], nil
+		})
+	default:
+		return data, nil
+	}
+}
+
+// eachMember rewrites the members of a JSON object in order: f returns the
+// new value and whether to keep the member.
+func eachMember(data json.RawMessage, f func(key string, value json.RawMessage) (json.RawMessage, bool, error)) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		value, keep, err := f(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		encodedKey, _ := json.Marshal(key)
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// firstByte returns the first non-space byte of data.
+func firstByte(data []byte) byte {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	if len(data) == 0 {
+		return 0
+	}
+	return data[0]
+}
@@ -0,0 +1,256 @@
+// Package flags turns features on per user, so risky ones can be rolled out
+// gradually without a deployment. Flags come from FEATURE_FLAGS and from the
+// database, where admins can change them at runtime; a database flag
+// overrides the configured one of the same name.
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"eventplanner-backend/internal/models"
+)
+
+// Features the code consults.
+const (
+	// Payments allows creating ticket tiers with a price.
+	Payments = "payments"
+	// FuzzySearch matches misspelled search terms.
+	FuzzySearch = "search.fuzzy"
+)
+
+// defaults says which features are on for everyone until a flag says
+// otherwise. Other features are off.
+var defaults = map[string]bool{
+	Payments:    true,
+	FuzzySearch: true,
+}
+
+// cacheTTL is how long flags read from the database are used before they
+// are read again. Other instances are told about changes made through the
+// API, so this only bounds how long a missed notice or a direct database
+// edit goes unnoticed.
+const cacheTTL = 30 * time.Second
+
+// Store loads the flags kept in the database.
+type Store interface {
+	ListFlags(ctx context.Context) ([]models.FeatureFlag, error)
+}
+
+// Flags answers whether a feature is on for a user.
+type Flags struct {
+	configured atomic.Pointer[map[string]models.FeatureFlag]
+	store      Store
+
+	mu       sync.Mutex
+	stored   map[string]models.FeatureFlag
+	loadedAt time.Time
+}
+
+// New returns the configured flags, overridden by those in store when it is
+// not nil.
+func New(configured []models.FeatureFlag, store Store) *Flags {
+	f := &Flags{store: store}
+	f.Configure(configured)
+	return f
+}
+
+// Configure replaces the configured flags, e.g. when the configuration is
+// reloaded, and reads the database flags again on the next check.
+func (f *Flags) Configure(configured []models.FeatureFlag) {
+	byName := make(map[string]models.FeatureFlag, len(configured))
+	for _, flag := range configured {
+		byName[flag.Name] = flag
+	}
+	f.configured.Store(&byName)
+	f.Invalidate()
+}
+
+// Enabled reports whether the feature is on for the user. When the flags
+// cannot be loaded, the last ones loaded are used.
+func (f *Flags) Enabled(ctx context.Context, name string, userID int) bool {
+	flag, ok := f.lookup(ctx, name)
+	if !ok {
+		return defaults[name]
+	}
+	return enabledFor(flag, name, userID)
+}
+
+// List returns every flag that is set, configured or in the database, and
+// the defaults of the features the code consults, by name.
+func (f *Flags) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	stored, err := f.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]models.FeatureFlag{}
+	for name, on := range defaults {
+		all[name] = models.FeatureFlag{Name: name, Enabled: on, UserIDs: []int{}, Source: models.FlagSourceDefault}
+	}
+	for name, flag := range *f.configured.Load() {
+		all[name] = flag
+	}
+	for name, flag := range stored {
+		all[name] = flag
+	}
+	res := make([]models.FeatureFlag, 0, len(all))
+	for _, flag := range all {
+		res = append(res, flag)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// For returns whether each flag List returns is on for the user.
+func (f *Flags) For(ctx context.Context, userID int) (map[string]bool, error) {
+	list, err := f.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]bool, len(list))
+	for _, flag := range list {
+		res[flag.Name] = enabledFor(flag, flag.Name, userID)
+	}
+	return res, nil
+}
+
+// Invalidate makes the next check read the flags from the database again.
+func (f *Flags) Invalidate() {
+	f.mu.Lock()
+	f.loadedAt = time.Time{}
+	f.mu.Unlock()
+}
+
+func (f *Flags) lookup(ctx context.Context, name string) (models.FeatureFlag, bool) {
+	stored, err := f.load(ctx)
+	if err != nil {
+		log.Printf("feature flags: %v", err)
+	}
+	if flag, ok := stored[name]; ok {
+		return flag, true
+	}
+	flag, ok := (*f.configured.Load())[name]
+	return flag, ok
+}
+
+// load returns the database flags, read again once the cache expired. On
+// error it returns the previous ones with the error.
+func (f *Flags) load(ctx context.Context) (map[string]models.FeatureFlag, error) {
+	if f.store == nil {
+		return nil, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if time.Since(f.loadedAt) < cacheTTL {
+		return f.stored, nil
+	}
+	list, err := f.store.ListFlags(ctx)
+	if err != nil {
+		return f.stored, fmt.Errorf("loading flags: %w", err)
+	}
+	stored := make(map[string]models.FeatureFlag, len(list))
+	for _, flag := range list {
+		stored[flag.Name] = flag
+	}
+	f.stored, f.loadedAt = stored, time.Now()
+	return stored, nil
+}
+
+// enabledFor applies a flag to a user. Anonymous callers (user 0) only get
+// features that are on for everyone.
+func enabledFor(flag models.FeatureFlag, name string, userID int) bool {
+	if flag.Enabled {
+		return true
+	}
+	if userID == 0 {
+		return false
+	}
+	if slices.Contains(flag.UserIDs, userID) {
+		return true
+	}
+	return bucket(name, userID) < flag.Percent
+}
+
+// bucket places a user between 0 and 99 for a flag.
+func bucket(name string, userID int) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + strconv.Itoa(userID)))
+	return int(h.Sum32() % 100)
+}
+
+// ConfiguredFromEnv reads FEATURE_FLAGS: comma-separated name=setting pairs,
+// where the setting is on, off, a rollout percentage such as 25%, or
+// users:4;7, and several settings of one flag are joined with +, e.g.
+// "search.fuzzy=off,payments=10%+users:4;7".
+func ConfiguredFromEnv() ([]models.FeatureFlag, error) {
+	res := []models.FeatureFlag{}
+	for _, pair := range strings.Split(os.Getenv("FEATURE_FLAGS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, settings, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !ValidName(name) {
+			return nil, fmt.Errorf("invalid flag %q", pair)
+		}
+		flag := models.FeatureFlag{Name: name, UserIDs: []int{}, Source: models.FlagSourceConfig}
+		for _, s := range strings.Split(settings, "+") {
+			if err := applySetting(&flag, strings.TrimSpace(s)); err != nil {
+				return nil, fmt.Errorf("flag %s: %w", name, err)
+			}
+		}
+		res = append(res, flag)
+	}
+	return res, nil
+}
+
+func applySetting(flag *models.FeatureFlag, s string) error {
+	switch {
+	case s == "on":
+		flag.Enabled = true
+	case s == "off":
+		flag.Enabled = false
+	case strings.HasSuffix(s, "%"):
+		p, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if err != nil || p < 0 || p > 100 {
+			return fmt.Errorf("invalid percentage %q", s)
+		}
+		flag.Percent = p
+	case strings.HasPrefix(s, "users:"):
+		for _, raw := range strings.Split(strings.TrimPrefix(s, "users:"), ";") {
+			id, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid user id %q", raw)
+			}
+			flag.UserIDs = append(flag.UserIDs, id)
+		}
+	default:
+		return fmt.Errorf("invalid setting %q", s)
+	}
+	return nil
+}
+
+// ValidName reports whether name can name a flag: up to 64 lowercase
+// letters, digits, dots, dashes and underscores, starting with a letter.
+func ValidName(name string) bool {
+	if name == "" || len(name) > 64 || name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
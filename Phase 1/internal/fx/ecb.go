@@ -0,0 +1,67 @@
+package fx
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECB reads the European Central Bank's daily reference rates against EUR.
+// They are published once per working day and need no API key.
+type ECB struct {
+	url    string
+	client *http.Client
+}
+
+// NewECB creates an ECB provider; an empty url uses the public feed.
+func NewECB(url string) *ECB {
+	if url == "" {
+		url = defaultECBURL
+	}
+	return &ECB{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (e *ECB) Latest(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ecb: unexpected status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Day struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube>Cube"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+	if len(doc.Day.Rates) == 0 {
+		return nil, fmt.Errorf("ecb: no rates in feed")
+	}
+	asOf, err := time.Parse("2006-01-02", doc.Day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("ecb: bad date %q", doc.Day.Time)
+	}
+	rates := &Rates{Base: "EUR", Rates: make(map[string]float64, len(doc.Day.Rates)), AsOf: asOf, Source: "ecb"}
+	for _, r := range doc.Day.Rates {
+		rates.Rates[strings.ToUpper(r.Currency)] = r.Rate
+	}
+	return rates, nil
+}
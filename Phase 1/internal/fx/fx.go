@@ -0,0 +1,160 @@
+// Package fx provides currency exchange rates through a pluggable provider.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotConfigured is returned when a conversion is needed but no rate
+// provider is configured.
+var ErrNotConfigured = errors.New("no exchange rate provider configured")
+
+// ErrUnsupportedCurrency is returned for currencies the provider has no rate for.
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Rates are exchange rates against Base: one unit of Base buys Rates[code]
+// units of code.
+type Rates struct {
+	Base   string
+	Rates  map[string]float64
+	AsOf   time.Time
+	Source string
+}
+
+// Rate returns how many units of to one unit of from buys, crossing through
+// the base currency when neither side is the base.
+func (r *Rates) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+	per := func(code string) (float64, error) {
+		if code == r.Base {
+			return 1, nil
+		}
+		v, ok := r.Rates[code]
+		if !ok || v <= 0 {
+			return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, code)
+		}
+		return v, nil
+	}
+	f, err := per(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := per(to)
+	if err != nil {
+		return 0, err
+	}
+	return t / f, nil
+}
+
+// Provider returns the latest exchange rates.
+type Provider interface {
+	Latest(ctx context.Context) (*Rates, error)
+}
+
+// NewFromEnv selects a provider from FX_PROVIDER ("ecb", "static" or "none")
+// and caches its rates for an hour. Conversion is disabled unless a provider
+// is configured.
+func NewFromEnv() Provider {
+	switch os.Getenv("FX_PROVIDER") {
+	case "ecb":
+		return NewCache(NewECB(os.Getenv("FX_ECB_URL")), time.Hour)
+	case "static":
+		rates, err := ParseStatic(os.Getenv("FX_BASE"), os.Getenv("FX_RATES"))
+		if err != nil {
+			return failing{err}
+		}
+		return rates
+	default:
+		return Noop{}
+	}
+}
+
+// Static serves fixed rates, e.g. for development or rates agreed with a
+// finance team.
+type Static Rates
+
+// ParseStatic reads rates written as "EUR=0.92,GBP=0.79" against base
+// (default USD).
+func ParseStatic(base, spec string) (*Static, error) {
+	if base == "" {
+		base = "USD"
+	}
+	s := &Static{Base: strings.ToUpper(base), Rates: map[string]float64{}, Source: "static"}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(pair, "=")
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || v <= 0 {
+			return nil, fmt.Errorf("fx: invalid rate %q in FX_RATES", pair)
+		}
+		s.Rates[strings.ToUpper(strings.TrimSpace(code))] = v
+	}
+	return s, nil
+}
+
+func (s *Static) Latest(ctx context.Context) (*Rates, error) {
+	r := Rates(*s)
+	r.AsOf = time.Now()
+	return &r, nil
+}
+
+// Noop has no rates; only amounts already in the requested currency can be
+// reported.
+type Noop struct{}
+
+func (Noop) Latest(ctx context.Context) (*Rates, error) {
+	return nil, ErrNotConfigured
+}
+
+// failing reports a configuration error on every call.
+type failing struct{ err error }
+
+func (f failing) Latest(ctx context.Context) (*Rates, error) {
+	return nil, f.err
+}
+
+// Cache keeps a provider's rates for ttl, so reports do not hit the provider
+// on every request.
+type Cache struct {
+	provider Provider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	rates   *Rates
+	fetched time.Time
+}
+
+func NewCache(provider Provider, ttl time.Duration) *Cache {
+	return &Cache{provider: provider, ttl: ttl}
+}
+
+// Latest returns the cached rates, refreshing them when older than the ttl.
+// When a refresh fails, stale rates are returned rather than none.
+func (c *Cache) Latest(ctx context.Context) (*Rates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rates != nil && time.Since(c.fetched) < c.ttl {
+		return c.rates, nil
+	}
+	rates, err := c.provider.Latest(ctx)
+	if err != nil {
+		if c.rates != nil {
+			return c.rates, nil
+		}
+		return nil, err
+	}
+	c.rates, c.fetched = rates, time.Now()
+	return rates, nil
+}
@@ -0,0 +1,31 @@
+package geocoding
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle (haversine) distance between two points.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// BoundingBox returns a lat/lng box containing every point within radiusKm of
+// the center. It is used as an index-friendly prefilter before the exact
+// distance check. wrapsLng reports that the box crosses the antimeridian or a
+// pole, in which case the longitude bounds should not be used.
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64, wrapsLng bool) {
+	dLat := radiusKm / (earthRadiusKm * math.Pi / 180)
+	minLat, maxLat = lat-dLat, lat+dLat
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180, true
+	}
+	dLng := dLat / math.Cos(radians(lat))
+	minLng, maxLng = lng-dLng, lng+dLng
+	return minLat, maxLat, minLng, maxLng, minLng < -180 || maxLng > 180
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
@@ -0,0 +1,42 @@
+// Package geocoding resolves free-text addresses to map coordinates through a
+// pluggable provider.
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// ErrNotFound is returned when the provider has no match for an address.
+var ErrNotFound = errors.New("address not found")
+
+// Result is a geocoded address.
+type Result struct {
+	Latitude         float64
+	Longitude        float64
+	FormattedAddress string
+}
+
+// Provider turns an address into coordinates.
+type Provider interface {
+	Geocode(ctx context.Context, address string) (*Result, error)
+}
+
+// NewFromEnv selects a provider from GEOCODER_PROVIDER ("nominatim" or "none").
+// Geocoding is disabled unless a provider is configured.
+func NewFromEnv() Provider {
+	switch os.Getenv("GEOCODER_PROVIDER") {
+	case "nominatim":
+		return NewNominatim(os.Getenv("NOMINATIM_URL"), os.Getenv("GEOCODER_USER_AGENT"))
+	default:
+		return Noop{}
+	}
+}
+
+// Noop never resolves addresses; venues keep whatever coordinates the client sent.
+type Noop struct{}
+
+func (Noop) Geocode(ctx context.Context, address string) (*Result, error) {
+	return nil, ErrNotFound
+}
@@ -0,0 +1,78 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const defaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// Nominatim geocodes addresses with an OpenStreetMap Nominatim server.
+type Nominatim struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+// NewNominatim creates a Nominatim provider. The public server requires an
+// identifying User-Agent; an empty baseURL uses the public server.
+func NewNominatim(baseURL, userAgent string) *Nominatim {
+	if baseURL == "" {
+		baseURL = defaultNominatimURL
+	}
+	if userAgent == "" {
+		userAgent = "eventplanner-backend"
+	}
+	return &Nominatim{
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *Nominatim) Geocode(ctx context.Context, address string) (*Result, error) {
+	q := url.Values{}
+	q.Set("q", address)
+	q.Set("format", "jsonv2")
+	q.Set("limit", "1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim: unexpected status %d", resp.StatusCode)
+	}
+
+	var places []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("nominatim: %w", err)
+	}
+	if len(places) == 0 {
+		return nil, ErrNotFound
+	}
+	lat, err := strconv.ParseFloat(places[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim: bad latitude: %w", err)
+	}
+	lng, err := strconv.ParseFloat(places[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim: bad longitude: %w", err)
+	}
+	return &Result{Latitude: lat, Longitude: lng, FormattedAddress: places[0].DisplayName}, nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// loader is a request-scoped dataloader keyed by ID. Resolvers announce the
+// keys a query may load with expect; the first load fetches every announced
+// key not fetched yet with a single call, and later loads are served from the
+// cache. gqlgen resolves list elements concurrently, so loads are serialized.
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []int) (map[int]V, error)
+
+	mu       sync.Mutex
+	expected []int
+	cache    map[int]V
+	errs     map[int]error
+	loaded   map[int]bool
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []int) (map[int]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, cache: map[int]V{}, errs: map[int]error{}, loaded: map[int]bool{}}
+}
+
+func (l *loader[V]) expect(keys []int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expected = append(l.expected, keys...)
+}
+
+// load returns the value for key. found reports whether the fetch produced a
+// value for it; a failed fetch fails every key it covered.
+func (l *loader[V]) load(ctx context.Context, key int) (value V, found bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loaded[key] {
+		missing := []int{key}
+		seen := map[int]bool{key: true}
+		for _, k := range l.expected {
+			if !l.loaded[k] && !seen[k] {
+				missing = append(missing, k)
+				seen[k] = true
+			}
+		}
+		l.expected = nil
+		res, err := l.fetch(ctx, missing)
+		for _, k := range missing {
+			l.loaded[k] = true
+			if err != nil {
+				l.errs[k] = err
+			} else if v, ok := res[k]; ok {
+				l.cache[k] = v
+			}
+		}
+	}
+	if err := l.errs[key]; err != nil {
+		return value, false, err
+	}
+	value, found = l.cache[key]
+	return value, found, nil
+}
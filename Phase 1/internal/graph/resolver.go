@@ -0,0 +1,14 @@
+package graph
+
+// The generator runs on the toolchain go.mod names: the x/tools it depends
+// on cannot load packages with newer ones.
+//go:generate env GOTOOLCHAIN=go1.23.12 go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
+import "eventplanner-backend/internal/services"
+
+// Resolver is the root resolver. Resolvers act for the user whose ID the
+// request context carries, and share the request's dataloaders.
+type Resolver struct {
+	events   services.EventService
+	comments services.CommentService
+}
@@ -0,0 +1,207 @@
+// Package graph implements the /graphql endpoint: the schema described in
+// schema.graphqls, executed by gqlgen and resolved through the service layer
+// with request-scoped dataloaders.
+//
+// generated.go and models_gen.go are generated, as are the resolver stubs in
+// schema.resolvers.go; after changing the schema or gqlgen.yml, run
+//
+//	go generate ./internal/graph
+package graph
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// maxTokens bounds how many tokens a query document may contain, so the
+	// parser's work and recursion are bounded before anything else runs.
+	maxTokens = 10000
+	// maxDepth bounds how deeply field selections may nest, expanding
+	// fragments where they are spread. Introspection queries nest about a
+	// dozen levels; the schema's own types fewer than five.
+	maxDepth = 16
+	// maxCost bounds how many fields a query may select, counting the fields
+	// of a fragment again wherever it is spread. Each selected field costs
+	// one resolver call per level, and aliases and nested spreads otherwise
+	// let a small document fan out into an exponential number of them.
+	maxCost = 500
+)
+
+// Request is a GraphQL-over-HTTP request body.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL-over-HTTP response body.
+type Response struct {
+	Data   any     `json:"data"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error entry.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Schema is the executable GraphQL schema.
+type Schema struct {
+	resolver *Resolver
+	exec     *executor.Executor
+}
+
+func NewSchema(events services.EventService, comments services.CommentService) *Schema {
+	resolver := &Resolver{events: events, comments: comments}
+	exec := executor.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	exec.Use(extension.Introspection{})
+	exec.Use(depthLimit(maxDepth))
+	exec.Use(extension.FixedComplexityLimit(maxCost))
+	exec.SetParserTokenLimit(maxTokens)
+	return &Schema{resolver: resolver, exec: exec}
+}
+
+// Execute runs a GraphQL request on behalf of userID.
+func (s *Schema) Execute(ctx context.Context, userID int, req Request) *Response {
+	ctx = context.WithValue(ctx, requestKey{}, s.resolver.newRequest(userID))
+	ctx = graphql.StartOperationTrace(ctx)
+	now := graphql.Now()
+	params := &graphql.RawParams{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		ReadTime:      graphql.TraceTiming{Start: now, End: now},
+	}
+	opCtx, errs := s.exec.CreateOperationContext(ctx, params)
+	if errs != nil {
+		return toResponse(s.exec.DispatchError(graphql.WithOperationContext(ctx, opCtx), errs))
+	}
+	handle, ctx := s.exec.DispatchOperation(ctx, opCtx)
+	return toResponse(handle(ctx))
+}
+
+// requestKey is the context key of the per-request state resolvers share.
+type requestKey struct{}
+
+// request is the state of one GraphQL request: the caller and the loaders
+// that batch nested fields across the events the query returned.
+type request struct {
+	userID       int
+	participants *loader[[]models.Participant]
+	tasks        *loader[[]models.Task]
+	comments     *loader[[]models.Comment]
+}
+
+func (r *Resolver) newRequest(userID int) *request {
+	return &request{
+		userID: userID,
+		participants: newLoader(func(ctx context.Context, ids []int) (map[int][]models.Participant, error) {
+			return r.events.ParticipantsByEvents(ctx, userID, ids)
+		}),
+		tasks: newLoader(func(ctx context.Context, ids []int) (map[int][]models.Task, error) {
+			return r.events.TasksByEvents(ctx, userID, ids)
+		}),
+		comments: newLoader(func(ctx context.Context, ids []int) (map[int][]models.Comment, error) {
+			return r.comments.CommentsByEvents(ctx, userID, ids)
+		}),
+	}
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// expect announces events whose nested fields may be resolved, so the first
+// load of any of them fetches them all.
+func (r *request) expect(events []models.Event) {
+	ids := make([]int, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	r.participants.expect(ids)
+	r.tasks.expect(ids)
+	r.comments.expect(ids)
+}
+
+// summaryEvents unwraps listed events and expects their nested fields.
+func (r *request) summaryEvents(items []models.EventSummary) []models.Event {
+	events := make([]models.Event, len(items))
+	for i, item := range items {
+		events[i] = item.Event
+	}
+	r.expect(events)
+	return events
+}
+
+func toResponse(resp *graphql.Response) *Response {
+	out := &Response{}
+	if resp.Data != nil {
+		out.Data = resp.Data
+	}
+	for _, e := range resp.Errors {
+		entry := Error{Message: e.Message}
+		for _, p := range e.Path {
+			switch p := p.(type) {
+			case ast.PathName:
+				entry.Path = append(entry.Path, string(p))
+			case ast.PathIndex:
+				entry.Path = append(entry.Path, int(p))
+			}
+		}
+		out.Errors = append(out.Errors, entry)
+	}
+	return out
+}
+
+// depthLimit rejects operations whose selections nest deeper than its value.
+type depthLimit int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit(0)
+
+func (depthLimit) ExtensionName() string { return "DepthLimit" }
+
+func (depthLimit) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (d depthLimit) MutateOperationContext(_ context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if selectionDepth(opCtx.Operation.SelectionSet, map[string]int{}) > int(d) {
+		return gqlerror.Errorf("query is nested deeper than %d levels", int(d))
+	}
+	return nil
+}
+
+// selectionDepth is how many levels of fields set nests. Fragment depths are
+// memoized by name, since fragments that spread others several times would
+// otherwise be walked an exponential number of times; validation has already
+// rejected fragment cycles.
+func selectionDepth(set ast.SelectionSet, fragments map[string]int) int {
+	depth := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			depth = max(depth, 1+selectionDepth(sel.SelectionSet, fragments))
+		case *ast.InlineFragment:
+			depth = max(depth, selectionDepth(sel.SelectionSet, fragments))
+		case *ast.FragmentSpread:
+			d, ok := fragments[sel.Name]
+			if !ok && sel.Definition != nil {
+				d = selectionDepth(sel.Definition.SelectionSet, fragments)
+				fragments[sel.Name] = d
+			}
+			depth = max(depth, d)
+		}
+	}
+	return depth
+}
@@ -0,0 +1,90 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"errors"
+	"eventplanner-backend/internal/models"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// Permissions is the resolver for the permissions field.
+func (r *eventResolver) Permissions(ctx context.Context, obj *models.Event) ([]string, error) {
+	perms := make([]string, len(obj.Permissions))
+	for i, p := range obj.Permissions {
+		perms[i] = string(p)
+	}
+	return perms, nil
+}
+
+// Participants is the resolver for the participants field.
+func (r *eventResolver) Participants(ctx context.Context, obj *models.Event) ([]models.Participant, error) {
+	// Events the caller may not see the participants of have no entry: null.
+	participants, _, err := requestFrom(ctx).participants.load(ctx, obj.ID)
+	return participants, err
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *eventResolver) Tasks(ctx context.Context, obj *models.Event) ([]models.Task, error) {
+	tasks, _, err := requestFrom(ctx).tasks.load(ctx, obj.ID)
+	if tasks == nil && err == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, err
+}
+
+// Comments is the resolver for the comments field.
+func (r *eventResolver) Comments(ctx context.Context, obj *models.Event) ([]models.Comment, error) {
+	comments, _, err := requestFrom(ctx).comments.load(ctx, obj.ID)
+	if comments == nil && err == nil {
+		comments = []models.Comment{}
+	}
+	return comments, err
+}
+
+// OrganizedEvents is the resolver for the organizedEvents field.
+func (r *queryResolver) OrganizedEvents(ctx context.Context) ([]models.Event, error) {
+	req := requestFrom(ctx)
+	items, err := r.events.ListOrganized(ctx, req.userID, models.EventListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return req.summaryEvents(items), nil
+}
+
+// InvitedEvents is the resolver for the invitedEvents field.
+func (r *queryResolver) InvitedEvents(ctx context.Context) ([]models.Event, error) {
+	req := requestFrom(ctx)
+	items, err := r.events.ListInvited(ctx, req.userID, models.EventListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return req.summaryEvents(items), nil
+}
+
+// Event is the resolver for the event field.
+func (r *queryResolver) Event(ctx context.Context, id int) (*models.Event, error) {
+	req := requestFrom(ctx)
+	e, err := r.events.Get(ctx, id, req.userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.expect([]models.Event{*e})
+	return e, nil
+}
+
+// Event returns EventResolver implementation.
+func (r *Resolver) Event() EventResolver { return &eventResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type eventResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
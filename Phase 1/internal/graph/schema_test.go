@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeEvents serves three organized events; the caller may see the
+// participants of event 1 only. Methods the schema does not call are left to
+// the nil embedded interface.
+type fakeEvents struct {
+	services.EventService
+	participantCalls atomic.Int32
+	taskCalls        atomic.Int32
+}
+
+func (f *fakeEvents) ListOrganized(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error) {
+	return []models.EventSummary{
+		{Event: models.Event{ID: 1, Title: "One"}},
+		{Event: models.Event{ID: 2, Title: "Two"}},
+		{Event: models.Event{ID: 3, Title: "Three"}},
+	}, nil
+}
+
+func (f *fakeEvents) ListInvited(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error) {
+	return nil, nil
+}
+
+func (f *fakeEvents) Get(ctx context.Context, eventID, userID int) (*models.Event, error) {
+	if eventID != 1 {
+		return nil, pgx.ErrNoRows
+	}
+	return &models.Event{ID: 1, Title: "One", Permissions: []models.Permission{"edit_event"}}, nil
+}
+
+func (f *fakeEvents) ParticipantsByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Participant, error) {
+	f.participantCalls.Add(1)
+	out := map[int][]models.Participant{}
+	for _, id := range eventIDs {
+		if id == 1 {
+			out[id] = []models.Participant{{EventID: id, UserID: requesterID, UserName: "Ada"}}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEvents) TasksByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Task, error) {
+	f.taskCalls.Add(1)
+	out := map[int][]models.Task{}
+	for _, id := range eventIDs {
+		out[id] = []models.Task{{ID: id * 10, EventID: id, Title: "Task"}}
+	}
+	return out, nil
+}
+
+type fakeComments struct {
+	services.CommentService
+}
+
+func (fakeComments) CommentsByEvents(ctx context.Context, userID int, eventIDs []int) (map[int][]models.Comment, error) {
+	return map[int][]models.Comment{}, nil
+}
+
+func execute(t *testing.T, events *fakeEvents, query string, vars map[string]any) *Response {
+	t.Helper()
+	schema := NewSchema(events, fakeComments{})
+	return schema.Execute(context.Background(), 7, Request{Query: query, Variables: vars})
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "nested fields",
+			query: `{ organizedEvents { id participants { userId userName } tasks { id } comments { id } } }`,
+			want: `{"organizedEvents":[` +
+				`{"id":1,"participants":[{"userId":7,"userName":"Ada"}],"tasks":[{"id":10}],"comments":[]},` +
+				`{"id":2,"participants":null,"tasks":[{"id":20}],"comments":[]},` +
+				`{"id":3,"participants":null,"tasks":[{"id":30}],"comments":[]}]}`,
+		},
+		{
+			name:  "event by variable",
+			query: `query($id: Int!) { event(id: $id) { title permissions } }`,
+			vars:  map[string]any{"id": 1},
+			want:  `{"event":{"title":"One","permissions":["edit_event"]}}`,
+		},
+		{
+			name:  "missing event",
+			query: `{ event(id: 2) { title } }`,
+			want:  `{"event":null}`,
+		},
+		{
+			name:  "fragments and aliases",
+			query: `{ a: event(id: 1) { ...f } b: invitedEvents { ...f } } fragment f on Event { id }`,
+			want:  `{"a":{"id":1},"b":[]}`,
+		},
+		{
+			name:  "introspection",
+			query: `{ __type(name: "Venue") { name } }`,
+			want:  `{"__type":{"name":"Venue"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execute(t, &fakeEvents{}, tt.query, tt.vars)
+			if len(resp.Errors) > 0 {
+				t.Fatalf("errors: %+v", resp.Errors)
+			}
+			data, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("data = %s\nwant   %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteBatchesNestedFields(t *testing.T) {
+	events := &fakeEvents{}
+	resp := execute(t, events, `{
+		organizedEvents { participants { userId } tasks { id } }
+		invitedEvents { participants { userId } }
+	}`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	if n := events.participantCalls.Load(); n != 1 {
+		t.Errorf("ParticipantsByEvents called %d times, want 1", n)
+	}
+	if n := events.taskCalls.Load(); n != 1 {
+		t.Errorf("TasksByEvents called %d times, want 1", n)
+	}
+}
+
+func TestExecuteRejects(t *testing.T) {
+	// __schema, types, fields and type, then ofType down to one level past
+	// the limit.
+	deep := "{ __schema { types { fields { type { " + strings.Repeat("ofType { ", maxDepth-3) + "name" + strings.Repeat(" }", maxDepth+1) + " }"
+	// Each alias costs six fields.
+	var aliases []string
+	for i := range maxCost/6 + 1 {
+		aliases = append(aliases, "e"+strconv.Itoa(i)+": organizedEvents { tasks { id title } participants { userId } }")
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{"syntax error", `{ organizedEvents { id }`, nil, "Expected Name"},
+		{"unknown field", `{ organizedEvents { secret } }`, nil, "Cannot query field"},
+		{"mutation", `mutation { deleteEvent(id: 1) }`, nil, "Schema does not support operation type"},
+		{"variable type", `query($id: Int!) { event(id: $id) { id } }`, map[string]any{"id": "one"}, "cannot use"},
+		{"fragment cycle", `{ organizedEvents { ...a } } fragment a on Event { tasks { id } ...b } fragment b on Event { ...a }`, nil, "Cannot spread fragment"},
+		{"too deep", deep, nil, "nested deeper than"},
+		{"too many fields", "{ " + strings.Join(aliases, " ") + " }", nil, "operation has complexity"},
+		{"too many tokens", "{ " + strings.Repeat("__typename ", maxTokens) + "}", nil, "token limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execute(t, &fakeEvents{}, tt.query, tt.vars)
+			if resp.Data != nil {
+				t.Errorf("got data, want none")
+			}
+			if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.want) {
+				t.Errorf("errors = %+v, want one containing %q", resp.Errors, tt.want)
+			}
+		})
+	}
+}
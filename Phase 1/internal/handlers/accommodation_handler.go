@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type AccommodationHandler struct {
+	accommodation services.AccommodationService
+}
+
+func NewAccommodationHandler(accommodation services.AccommodationService) *AccommodationHandler {
+	return &AccommodationHandler{accommodation: accommodation}
+}
+
+// accommodationError writes the HTTP response for an accommodation service
+// error; notFound names what was missing.
+func accommodationError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrUnknownLodging), errors.Is(err, services.ErrInvalidStay):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// lodgingParams parses the event and lodging ids of a lodging route.
+func lodgingParams(c *gin.Context) (eventID, lodgingID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	lodgingID, err = strconv.Atoi(c.Param("lodgingId"))
+	if err != nil || lodgingID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lodging id"})
+		return 0, 0, false
+	}
+	return eventID, lodgingID, true
+}
+
+// CreateLodging adds a lodging option to an event
+// @Summary Add a lodging
+// @Description Add a place to stay, such as a hotel with a room block, with its nightly price and the date the block is released (requires edit_event)
+// @Tags accommodation
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.LodgingRequest true "Lodging"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Lodging
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lodgings [post]
+func (h *AccommodationHandler) CreateLodging(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.LodgingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lodging, err := h.accommodation.CreateLodging(c, eventID, userID, req)
+	if err != nil {
+		accommodationError(c, err, "lodging not found")
+		return
+	}
+	c.JSON(http.StatusCreated, lodging)
+}
+
+// ListLodgings returns an event's lodging options
+// @Summary List lodgings
+// @Description The event's lodging options with the number of participants staying at each (any participant)
+// @Tags accommodation
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Lodging
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lodgings [get]
+func (h *AccommodationHandler) ListLodgings(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.accommodation.ListLodgings(c, eventID, userID)
+	if err != nil {
+		accommodationError(c, err, "lodging not found")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// UpdateLodging changes a lodging option
+// @Summary Update a lodging
+// @Description Replace a lodging option (requires edit_event)
+// @Tags accommodation
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param lodgingId path int true "Lodging ID"
+// @Param request body models.LodgingRequest true "Lodging"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Lodging
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lodgings/{lodgingId} [put]
+func (h *AccommodationHandler) UpdateLodging(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, lodgingID, ok := lodgingParams(c)
+	if !ok {
+		return
+	}
+	var req models.LodgingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lodging, err := h.accommodation.UpdateLodging(c, eventID, lodgingID, userID, req)
+	if err != nil {
+		accommodationError(c, err, "lodging not found")
+		return
+	}
+	c.JSON(http.StatusOK, lodging)
+}
+
+// DeleteLodging removes a lodging option
+// @Summary Delete a lodging
+// @Description Remove a lodging option together with the stays chosen there (requires edit_event)
+// @Tags accommodation
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param lodgingId path int true "Lodging ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lodgings/{lodgingId} [delete]
+func (h *AccommodationHandler) DeleteLodging(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, lodgingID, ok := lodgingParams(c)
+	if !ok {
+		return
+	}
+	if err := h.accommodation.DeleteLodging(c, eventID, lodgingID, userID); err != nil {
+		accommodationError(c, err, "lodging not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lodging deleted successfully"})
+}
+
+// GetStay returns the caller's accommodation choice
+// @Summary My accommodation
+// @Description Where and when the caller stays for the event; 404 when they have not chosen yet
+// @Tags accommodation
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Stay
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/accommodation [get]
+func (h *AccommodationHandler) GetStay(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	stay, err := h.accommodation.GetStay(c, eventID, userID)
+	if err != nil {
+		accommodationError(c, err, "no accommodation chosen")
+		return
+	}
+	c.JSON(http.StatusOK, stay)
+}
+
+// SetStay records the caller's accommodation choice
+// @Summary Choose accommodation
+// @Description Record where and when the caller stays, replacing any earlier choice (participants). Leave out lodgingId when making your own arrangements.
+// @Tags accommodation
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.StayRequest true "Stay"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Stay
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/accommodation [put]
+func (h *AccommodationHandler) SetStay(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.StayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	stay, err := h.accommodation.SetStay(c, eventID, userID, req)
+	if err != nil {
+		accommodationError(c, err, "no accommodation chosen")
+		return
+	}
+	c.JSON(http.StatusOK, stay)
+}
+
+// DeleteStay clears the caller's accommodation choice
+// @Summary Clear my accommodation
+// @Description Remove the caller's accommodation choice
+// @Tags accommodation
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/accommodation [delete]
+func (h *AccommodationHandler) DeleteStay(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	if err := h.accommodation.DeleteStay(c, eventID, userID); err != nil {
+		accommodationError(c, err, "no accommodation chosen")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Accommodation removed successfully"})
+}
+
+// Occupancy summarizes an event's accommodation
+// @Summary Accommodation occupancy
+// @Description Guests per lodging and per night with the peak night, every participant's stay, and the number of going participants who have not chosen yet (requires edit_event)
+// @Tags accommodation
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.OccupancySummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/accommodation/occupancy [get]
+func (h *AccommodationHandler) Occupancy(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	summary, err := h.accommodation.Occupancy(c, eventID, userID)
+	if err != nil {
+		accommodationError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
@@ -17,6 +17,19 @@ func NewAuthHandler(users services.UserService) *AuthHandler {
 	return &AuthHandler{users: users}
 }
 
+// Signup registers a new user
+// @Summary Sign up
+// @Description Register a new user account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.SignupRequest true "Account details"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /signup [post]
 func (h *AuthHandler) Signup(c *gin.Context) {
 	var req models.SignupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -42,6 +55,19 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 	})
 }
 
+// Login authenticates a user with email and password
+// @Summary Log in
+// @Description Log in with email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "Credentials"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -65,6 +91,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// Health reports service liveness
+// @Summary Health check
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health [get]
 func (h *AuthHandler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
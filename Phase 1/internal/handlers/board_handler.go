@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BoardHandler struct {
+	boards services.BoardService
+}
+
+func NewBoardHandler(boards services.BoardService) *BoardHandler {
+	return &BoardHandler{boards: boards}
+}
+
+// boardError writes the HTTP response for a board service error.
+func boardError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidColumns):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrColumnNotFound), errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Get returns an event's task board
+// @Summary Get the task board
+// @Description The event's task board for drag-and-drop UIs: its columns in order, each with its tasks in their order (any participant). A board starts with the columns To Do, Doing and Done. Tasks never moved to a column, or whose column was removed, are in the first column, or in the last once completed.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Board
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/board [get]
+func (h *BoardHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	board, err := h.boards.Get(c, eventID, userID)
+	if err != nil {
+		boardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// SetColumns replaces an event's board columns
+// @Summary Set the board columns
+// @Description Replace the board's columns, 1 to 20 with distinct names of up to 50 characters (requires manage_tasks). Columns given with their id are kept, renamed and put in the listed order; columns without an id are added; columns not listed are removed, and their tasks go back to the first or last column. Returns the board.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.BoardColumnsRequest true "Columns in order"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Board
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/board/columns [put]
+func (h *BoardHandler) SetColumns(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.BoardColumnsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	board, err := h.boards.SetColumns(c, eventID, userID, req)
+	if err != nil {
+		boardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// MoveTask moves a task to a board column
+// @Summary Move a task on the board
+// @Description Put the task in a column (requires manage_tasks). position, when given, also moves it in the order of the event's tasks, as with PUT /events/{id}/tasks/order; e.g. 2.5 between the tasks at 2 and 3. Returns the board.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.BoardMoveRequest true "Target column and position"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Board
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/board/tasks/{taskId} [put]
+func (h *BoardHandler) MoveTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil || taskID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return
+	}
+	var req models.BoardMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	board, err := h.boards.MoveTask(c, eventID, taskID, userID, req)
+	if err != nil {
+		boardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
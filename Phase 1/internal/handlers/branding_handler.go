@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/i18n"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BrandingHandler struct {
+	branding services.BrandingService
+}
+
+func NewBrandingHandler(branding services.BrandingService) *BrandingHandler {
+	return &BrandingHandler{branding: branding}
+}
+
+// Get returns the caller's email branding
+// @Summary Get email branding
+// @Description The logo, accent color and reply-to address of emails about the events the caller organizes. Empty fields use the defaults.
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Branding
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/branding [get]
+func (h *BrandingHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	b, err := h.branding.Get(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// Set replaces the caller's email branding
+// @Summary Set email branding
+// @Description Emails about the events the caller organizes (invitations, announcements, reminders, receipts) show the logo and use the accent color, and replies to them go to replyTo. Omitted fields are reset to the defaults: no logo, the EventPlanner color and no reply-to address.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.BrandingRequest true "Branding; logoUrl must be https, color a hex color such as #0a7c59"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Branding
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/branding [put]
+func (h *BrandingHandler) Set(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.BrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	b, err := h.branding.Set(c, userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// PreviewInvitation shows the invitation email of an event
+// @Summary Preview invitation email
+// @Description The email an invitee gets when the caller invites them to the event, with the branding of the event's organizer and a stand-in invitee name. As JSON by default; format=html or format=text return the email body alone, e.g. to open it in a browser. Requires manage_participants.
+// @Tags events
+// @Produce json
+// @Produce html
+// @Produce plain
+// @Param id path int true "Event ID"
+// @Param role query string false "Role of the invitee (default attendee)"
+// @Param locale query string false "Language of the invitee (en or de); defaults to the language of the request"
+// @Param format query string false "Response format" Enums(json, html, text)
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EmailPreview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/invitation-preview [get]
+func (h *BrandingHandler) PreviewInvitation(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" && format != "text" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format"})
+		return
+	}
+	locale := c.Query("locale")
+	if locale == "" {
+		locale = c.GetString("locale")
+	}
+	if locale == "" {
+		locale = i18n.Default
+	}
+	preview, err := h.branding.PreviewInvitation(c, eventID, userID, c.Query("role"), locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnsupportedLocale):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	switch format {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(preview.HTML))
+	case "text":
+		c.String(http.StatusOK, preview.Text)
+	default:
+		c.JSON(http.StatusOK, preview)
+	}
+}
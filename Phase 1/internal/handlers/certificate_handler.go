@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type CertificateHandler struct {
+	certificates services.CertificateService
+}
+
+func NewCertificateHandler(certificates services.CertificateService) *CertificateHandler {
+	return &CertificateHandler{certificates: certificates}
+}
+
+// certificateError writes the HTTP response for a certificate service error.
+func certificateError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrNotCheckedIn):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrNoCheckIns):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// checkInParams parses the event and user ids of a check-in route.
+func checkInParams(c *gin.Context) (eventID, participantID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	participantID, err = strconv.Atoi(c.Param("userId"))
+	if err != nil || participantID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return 0, 0, false
+	}
+	return eventID, participantID, true
+}
+
+// CheckIn marks a participant as present
+// @Summary Check in a participant
+// @Description Mark a participant as present at the event; checking in again keeps the first time (requires manage_participants)
+// @Tags certificates
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param userId path int true "Participant user ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CheckIn
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/check-ins/{userId} [put]
+func (h *CertificateHandler) CheckIn(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, participantID, ok := checkInParams(c)
+	if !ok {
+		return
+	}
+	checkIn, err := h.certificates.CheckIn(c, eventID, participantID, userID)
+	if err != nil {
+		certificateError(c, err, "participant not found")
+		return
+	}
+	c.JSON(http.StatusOK, checkIn)
+}
+
+// UndoCheckIn clears a participant's check-in
+// @Summary Undo a check-in
+// @Description Clear a participant's check-in, e.g. after checking in the wrong person (requires manage_participants)
+// @Tags certificates
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param userId path int true "Participant user ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/check-ins/{userId} [delete]
+func (h *CertificateHandler) UndoCheckIn(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, participantID, ok := checkInParams(c)
+	if !ok {
+		return
+	}
+	if err := h.certificates.UndoCheckIn(c, eventID, participantID, userID); err != nil {
+		certificateError(c, err, "participant is not checked in")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Check-in removed successfully"})
+}
+
+// ListCheckIns returns an event's checked-in participants
+// @Summary List check-ins
+// @Description The participants checked in at the event, by name (requires manage_participants)
+// @Tags certificates
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.CheckIn
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/check-ins [get]
+func (h *CertificateHandler) ListCheckIns(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	checkIns, err := h.certificates.ListCheckIns(c, eventID, userID)
+	if err != nil {
+		certificateError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, checkIns)
+}
+
+// SetTemplate replaces an event's certificate wording
+// @Summary Set the certificate template
+// @Description Set the title, body and signer printed on the event's attendance certificates. Title and body may use {name}, {event} and {date} (requires edit_event)
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.CertificateTemplateRequest true "Template"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CertificateTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/certificate-template [put]
+func (h *CertificateHandler) SetTemplate(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.CertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.certificates.SetTemplate(c, eventID, userID, req)
+	if err != nil {
+		certificateError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Template returns an event's certificate wording
+// @Summary Get the certificate template
+// @Description The wording of the event's certificates; the default one while none was set (requires edit_event)
+// @Tags certificates
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CertificateTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/certificate-template [get]
+func (h *CertificateHandler) Template(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	t, err := h.certificates.Template(c, eventID, userID)
+	if err != nil {
+		certificateError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Certificate downloads the caller's certificate
+// @Summary Download my certificate
+// @Description The caller's certificate of attendance as a PDF; only participants who were checked in get one
+// @Tags certificates
+// @Produce application/pdf
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/certificate [get]
+func (h *CertificateHandler) Certificate(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	doc, err := h.certificates.Certificate(c, eventID, userID)
+	if err != nil {
+		certificateError(c, err, "event not found")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="certificate-%d-%d.pdf"`, eventID, userID))
+	c.Data(http.StatusOK, "application/pdf", doc)
+}
+
+// Certificates downloads every certificate of an event
+// @Summary Export all certificates
+// @Description One PDF with a certificate page for every checked-in participant, for printing or sending out (requires edit_event)
+// @Tags certificates
+// @Produce application/pdf
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/certificates [get]
+func (h *CertificateHandler) Certificates(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	doc, err := h.certificates.Certificates(c, eventID, userID)
+	if err != nil {
+		certificateError(c, err, "event not found")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-certificates.pdf"`, eventID))
+	c.Data(http.StatusOK, "application/pdf", doc)
+}
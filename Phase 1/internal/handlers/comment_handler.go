@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	comments services.CommentService
+}
+
+func NewCommentHandler(comments services.CommentService) *CommentHandler {
+	return &CommentHandler{comments: comments}
+}
+
+// commentError writes the HTTP response for a comment service error.
+func commentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidComment), errors.Is(err, services.ErrInvalidEmoji):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCommentNotFound), errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyReactions):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// commentPathIDs reads the event ID and the commentId path parameter,
+// writing a 400 response when either is invalid.
+func commentPathIDs(c *gin.Context) (int, int, bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	commentID, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil || commentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id"})
+		return 0, 0, false
+	}
+	return eventID, commentID, true
+}
+
+// List returns an event's comments
+// @Summary List event comments
+// @Description The comments on the event itself, oldest first (any participant). Comments on its tasks are listed per task.
+// @Tags comments
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	comments, err := h.comments.List(c, eventID, nil, userID)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+// Create comments on an event
+// @Summary Comment on an event
+// @Description Post a comment on the event (any participant). Participants mentioned with @ and their email, or their name without spaces (e.g. @JaneDoe), are notified with a link to the comment; mentions of anyone else are left as text.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.CommentRequest true "Comment"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comment, err := h.comments.Create(c, eventID, nil, userID, req)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// ListTask returns a task's comments
+// @Summary List task comments
+// @Description The comments on the task, oldest first (any participant).
+// @Tags comments
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/comments [get]
+func (h *CommentHandler) ListTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	comments, err := h.comments.List(c, eventID, &taskID, userID)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+// CreateTask comments on a task
+// @Summary Comment on a task
+// @Description Post a comment on the task (any participant), notifying the participants it mentions as for event comments.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.CommentRequest true "Comment"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/comments [post]
+func (h *CommentHandler) CreateTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	var req models.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comment, err := h.comments.Create(c, eventID, &taskID, userID, req)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// Get returns one comment
+// @Summary Get a comment
+// @Description A comment on the event or one of its tasks (any participant). Mention notices link here.
+// @Tags comments
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param commentId path int true "Comment ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments/{commentId} [get]
+func (h *CommentHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, commentID, ok := commentPathIDs(c)
+	if !ok {
+		return
+	}
+	comment, err := h.comments.Get(c, eventID, commentID, userID)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete removes a comment
+// @Summary Delete a comment
+// @Description Delete a comment on the event or one of its tasks: the caller's own, or anyone's with edit_event.
+// @Tags comments
+// @Param id path int true "Event ID"
+// @Param commentId path int true "Comment ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, commentID, ok := commentPathIDs(c)
+	if !ok {
+		return
+	}
+	if err := h.comments.Delete(c, eventID, commentID, userID); err != nil {
+		commentError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddReaction reacts to a comment with an emoji
+// @Summary React to a comment
+// @Description React to a comment on the event or one of its tasks with an emoji, URL-encoded in the path (any participant). Reacting again with the same emoji changes nothing; each participant can react with up to 20 different emoji.
+// @Tags comments
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param commentId path int true "Comment ID"
+// @Param emoji path string true "Emoji, e.g. %F0%9F%91%8D for 👍"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Reaction
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments/{commentId}/reactions/{emoji} [put]
+func (h *CommentHandler) AddReaction(c *gin.Context) {
+	h.react(c, h.comments.AddReaction)
+}
+
+// RemoveReaction takes back a reaction to a comment
+// @Summary Remove a reaction to a comment
+// @Description Take back the caller's reaction with the emoji, if any.
+// @Tags comments
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param commentId path int true "Comment ID"
+// @Param emoji path string true "Emoji, URL-encoded"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Reaction
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/comments/{commentId}/reactions/{emoji} [delete]
+func (h *CommentHandler) RemoveReaction(c *gin.Context) {
+	h.react(c, h.comments.RemoveReaction)
+}
+
+func (h *CommentHandler) react(c *gin.Context, react func(ctx context.Context, eventID, commentID, userID int, emoji string) ([]models.Reaction, error)) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, commentID, ok := commentPathIDs(c)
+	if !ok {
+		return
+	}
+	reactions, err := react(c, eventID, commentID, userID, c.Param("emoji"))
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reactions)
+}
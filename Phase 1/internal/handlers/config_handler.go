@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigHandler struct {
+	config services.ConfigService
+}
+
+func NewConfigHandler(config services.ConfigService) *ConfigHandler {
+	return &ConfigHandler{config: config}
+}
+
+// Reload applies the reloadable configuration again
+// @Summary Reload the configuration
+// @Description Read CORS_ORIGINS, RATE_LIMITS and FEATURE_FLAGS from the profile file again and apply them without restarting, like sending the server SIGHUP. Only the instance answering reloads. Returns the configuration now in use (admins only)
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RuntimeConfig
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/config/reload [post]
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	cfg, err := h.config.Reload(c, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
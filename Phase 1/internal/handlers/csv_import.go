@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes caps the size of an uploaded CSV file.
+const maxImportBytes = 10 << 20
+
+// csvTable is an uploaded CSV file whose first row names the columns.
+type csvTable struct {
+	columns map[string]int // lowercase name to index
+	rows    [][]string
+}
+
+// readCSV reads the request body as CSV with a header row.
+func readCSV(c *gin.Context) (*csvTable, error) {
+	r := csv.NewReader(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	t := &csvTable{columns: map[string]int{}}
+	for i, name := range header {
+		t.columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if t.rows, err = r.ReadAll(); err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	return t, nil
+}
+
+// value returns the row's trimmed value in the named column, "" without one.
+func (t *csvTable) value(row []string, column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func (t *csvTable) has(column string) bool {
+	_, ok := t.columns[column]
+	return ok
+}
+
+// inviteesFromCSV reads the email and/or user_id columns.
+func inviteesFromCSV(t *csvTable, req *models.BulkInviteRequest) error {
+	if !t.has("email") && !t.has("user_id") {
+		return errors.New("the CSV file needs an email or user_id column")
+	}
+	for n, row := range t.rows {
+		if email := t.value(row, "email"); email != "" {
+			req.Emails = append(req.Emails, email)
+		} else if raw := t.value(row, "user_id"); raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("row %d: invalid user_id %q", n+2, raw)
+			}
+			req.UserIDs = append(req.UserIDs, id)
+		}
+	}
+	return nil
+}
+
+// tasksFromCSV reads the title, description, due_date (RFC 3339 or
+// YYYY-MM-DD), due_offset and assignee_id columns.
+func tasksFromCSV(t *csvTable) ([]models.TaskInput, error) {
+	if !t.has("title") {
+		return nil, errors.New("the CSV file needs a title column")
+	}
+	tasks := make([]models.TaskInput, 0, len(t.rows))
+	for n, row := range t.rows {
+		task := models.TaskInput{
+			Title:       t.value(row, "title"),
+			Description: t.value(row, "description"),
+			DueOffset:   t.value(row, "due_offset"),
+		}
+		if task.Title == "" && task.Description == "" {
+			continue // blank line
+		}
+		if raw := t.value(row, "due_date"); raw != "" {
+			due, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				if due, err = time.Parse("2006-01-02", raw); err != nil {
+					return nil, fmt.Errorf("row %d: invalid due_date %q", n+2, raw)
+				}
+			}
+			task.DueDate = &due
+		}
+		if raw := t.value(row, "assignee_id"); raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("row %d: invalid assignee_id %q", n+2, raw)
+			}
+			task.AssigneeID = &id
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
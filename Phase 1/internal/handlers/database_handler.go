@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DatabaseHandler struct {
+	database services.DatabaseService
+}
+
+func NewDatabaseHandler(database services.DatabaseService) *DatabaseHandler {
+	return &DatabaseHandler{database: database}
+}
+
+// PoolStats returns the connection pool statistics
+// @Summary Get connection pool statistics
+// @Description Connections of the instance answering: in use, idle and open against the maximum, and counts since startup, e.g. how often a request waited for a connection (emptyAcquireCount). For capacity planning; the same values are in /metrics (admins only)
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.PoolStats
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/database/pool [get]
+func (h *DatabaseHandler) PoolStats(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	stats, err := h.database.PoolStats(c, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
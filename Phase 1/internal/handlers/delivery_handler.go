@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReportBody caps a batch of delivery reports in a provider's format.
+const maxReportBody = 5 << 20
+
+// pixel is a transparent 1x1 GIF.
+var pixel = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\x00\x00\x00!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+type DeliveryHandler struct {
+	deliveries services.DeliveryService
+}
+
+func NewDeliveryHandler(deliveries services.DeliveryService) *DeliveryHandler {
+	return &DeliveryHandler{deliveries: deliveries}
+}
+
+// List returns the emails sent about an event
+// @Summary List email deliveries
+// @Description Every email sent about the event (invitations, announcements, reminders...), newest first and at most 500: to whom, how many attempts it took and its status. failed means the mail server refused it (lastError says why; it is retried), sent that the server accepted it, delivered, bounced and opened what the mail provider or the tracking pixel reported since. Requires manage_participants.
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param kind query string false "Only emails of this notification kind, e.g. invite"
+// @Param status query string false "Only emails with this status" Enums(pending, failed, sent, delivered, bounced, opened)
+// @Param userId query int false "Only emails to this user"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EmailDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/deliveries [get]
+func (h *DeliveryHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	filter := models.DeliveryFilter{Kind: c.Query("kind"), Status: c.Query("status")}
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliveryFailed, models.DeliverySent, models.DeliveryDelivered, models.DeliveryBounced, models.DeliveryOpened:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	if v := c.Query("userId"); v != "" {
+		if filter.UserID, err = strconv.Atoi(v); err != nil || filter.UserID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+	}
+	items, err := h.deliveries.ListForEvent(c, eventID, userID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Open records that an email was opened
+// @Summary Email open pixel
+// @Description Loaded by HTML emails when they are displayed; answers a transparent 1x1 GIF whatever the token. No authentication.
+// @Tags events
+// @Produce gif
+// @Param token path string true "Delivery token"
+// @Success 200 {file} binary
+// @Router /email/open/{token} [get]
+func (h *DeliveryHandler) Open(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".gif")
+	if err := h.deliveries.Opened(c, token); err != nil {
+		log.Printf("recording email open: %v", err)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/gif", pixel)
+}
+
+// Report takes the mail provider's delivery reports
+// @Summary Email delivery reports
+// @Description Called by the mail provider with what became of sent emails: delivered, bounced (reason says why), opened or failed. Each report names its email by the Message-ID or the X-Delivery-Token header it was sent with. With provider=sendgrid or provider=mailgun, the body is that provider's event webhook payload instead. Reports of unknown emails are skipped. No authentication; the provider passes EMAIL_WEBHOOK_TOKEN as the token query parameter.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param token query string true "Webhook token"
+// @Param provider query string false "Format of the body, when it is a provider's own" Enums(sendgrid, mailgun)
+// @Param request body models.DeliveryReportsRequest true "Reports"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /email/reports [post]
+func (h *DeliveryHandler) Report(c *gin.Context) {
+	var reports []models.DeliveryReport
+	if provider := c.Query("provider"); provider != "" {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxReportBody))
+		if err == nil {
+			reports, err = notifications.ParseReports(provider, body)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		var req models.DeliveryReportsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		reports = req.Events
+	}
+	matched, err := h.deliveries.Report(c, c.Query("token"), reports)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrackingDisabled):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidReportToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"matched": matched})
+}
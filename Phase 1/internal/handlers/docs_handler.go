@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eventplanner-backend/internal/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// OpenAPI serves the generated OpenAPI document
+// @Summary OpenAPI document
+// @Description Returns the OpenAPI 3 description of this API
+// @Tags docs
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /openapi.json [get]
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", docs.OpenAPI())
+}
+
+// UI serves a Swagger UI page backed by /openapi.json
+// @Summary Swagger UI
+// @Description Interactive API documentation
+// @Tags docs
+// @Produce html
+// @Success 200 {string} string
+// @Router /docs [get]
+func (h *DocsHandler) UI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>EventPlanner API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: '/openapi.json', dom_id: '#swagger-ui' });
+    };
+  </script>
+</body>
+</html>
+`
@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"eventplanner-backend/internal/fieldset"
+	"eventplanner-backend/internal/meetings"
 	"eventplanner-backend/internal/models"
 	"eventplanner-backend/internal/services"
 
@@ -22,13 +29,43 @@ type createTaskRequest struct {
 	Title       string     `json:"title" binding:"required"`
 	Description string     `json:"description,omitempty"`
 	DueDate     *time.Time `json:"dueDate,omitempty"`
+	DueOffset   string     `json:"dueOffset,omitempty"`
 	AssigneeID  *int       `json:"assigneeId,omitempty"`
 }
 
+// updateTaskRequest changes only the fields that are present. An empty
+// dueDate or dueOffset clears the due date, an assigneeId of 0 unassigns
+// the task and an estimatedMinutes of 0 clears the estimate.
+type updateTaskRequest struct {
+	Title            *string `json:"title"`
+	Description      *string `json:"description"`
+	DueDate          *string `json:"dueDate"`
+	DueOffset        *string `json:"dueOffset"`
+	AssigneeID       *int    `json:"assigneeId"`
+	Completed        *bool   `json:"completed"`
+	EstimatedMinutes *int    `json:"estimatedMinutes" binding:"omitempty,min=0,max=100000"`
+}
+
 func NewEventHandler(events services.EventService) *EventHandler {
 	return &EventHandler{events: events}
 }
 
+// Create creates a new event organized by the caller
+// @Summary Create an event
+// @Description Create a new event; the caller becomes its organizer. Virtual and hybrid events may carry a meetingUrl, or set createMeeting to have one generated by the configured meeting provider. taskTemplate (built-in) or taskTemplateId (saved) creates the template's tasks with due dates relative to the start time. An event overlapping one of the caller's with a similar title is answered with 409 and the likely duplicates; set allowDuplicate to create it anyway.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param request body models.CreateEventRequest true "Event details"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 402 {object} map[string]string
+// @Failure 409 {object} models.DuplicateWarning
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /events [post]
 func (h *EventHandler) Create(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
@@ -45,42 +82,345 @@ func (h *EventHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid startTime, use RFC3339"})
 		return
 	}
-	e, err := h.events.Create(c, req.Title, req.Description, req.Location, start, userID)
+	var end *time.Time
+	if req.EndTime != "" {
+		t, err := time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid endTime, use RFC3339"})
+			return
+		}
+		end = &t
+	}
+	var meetingURL *string
+	if req.MeetingURL != "" {
+		meetingURL = &req.MeetingURL
+	}
+	e, err := h.events.Create(c, models.Event{
+		Title:          req.Title,
+		Description:    req.Description,
+		Location:       req.Location,
+		VenueID:        req.VenueID,
+		StartTime:      start,
+		EndTime:        end,
+		Type:           req.Type,
+		MeetingURL:     meetingURL,
+		AllowTransfers: req.AllowTransfers,
+		OrganizerID:    userID,
+	}, req.CreateMeeting, models.TaskTemplateRef{Name: req.TaskTemplate, ID: req.TaskTemplateID}, req.AllowDuplicate)
+	if quotaError(c, err) {
+		return
+	}
+	var duplicate *services.DuplicateEventError
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, models.DuplicateWarning{Error: err.Error(), Duplicates: duplicate.Candidates})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		errMsg := err.Error()
+		if errors.Is(err, services.ErrInvalidTimeRange) || errors.Is(err, services.ErrMeetingNotAllowed) || errors.Is(err, services.ErrUnknownTemplate) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, meetings.ErrNotConfigured) {
+			status = http.StatusBadRequest
+			errMsg = "automatic meeting creation is not configured"
+		} else if errors.Is(err, services.ErrMeetingCreation) {
+			status = http.StatusBadGateway
+			errMsg = services.ErrMeetingCreation.Error()
+		} else if strings.Contains(errMsg, "violates foreign key constraint") {
+			status = http.StatusBadRequest
+			errMsg = "venue not found"
+		}
+		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 	c.JSON(http.StatusOK, e)
 }
 
+// maxBatchEventIDs caps the number of IDs accepted by GET /events?ids=.
+const maxBatchEventIDs = 100
+
+// parseFields reads a sparse fieldset from the query parameter, e.g.
+// fields=id,title,startTime, checking the names against model.
+func parseFields(c *gin.Context, param string, model any) (fieldset.Fields, bool) {
+	fields, err := fieldset.Parse(c.Query(param), model)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + ": " + err.Error()})
+		return nil, false
+	}
+	return fields, true
+}
+
+// renderFields writes v as JSON with only the selected fields.
+func renderFields(c *gin.Context, v any, sel fieldset.Selection) {
+	data, err := fieldset.Marshal(v, sel)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// List returns the caller's events hydrated with related data
+// @Summary List events with related data
+// @Description List events the caller participates in, optionally restricted to ids and hydrated with participants (events whose participants the caller may list, see GET /events/{id}/attendees), tasks and previews of the pages their descriptions link to in a single round trip
+// @Tags events
+// @Produce json
+// @Param ids query string false "Comma-separated event IDs (max 100)"
+// @Param include query string false "Comma-separated relations to include: participants, tasks, links"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title,startTime (default all)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventDetails
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events [get]
+func (h *EventHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var includeParticipants, includeTasks, includeLinks bool
+	if include := c.Query("include"); include != "" {
+		for _, rel := range strings.Split(include, ",") {
+			switch strings.TrimSpace(rel) {
+			case "participants":
+				includeParticipants = true
+			case "tasks":
+				includeTasks = true
+			case "links":
+				includeLinks = true
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include, must be any of 'participants', 'tasks' and 'links'"})
+				return
+			}
+		}
+	}
+
+	var ids []int
+	if raw := c.Query("ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || id <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id in ids"})
+				return
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) > maxBatchEventIDs {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "too many ids, max 100"})
+			return
+		}
+	}
+
+	fields, ok := parseFields(c, "fields", models.EventDetails{})
+	if !ok {
+		return
+	}
+
+	items, err := h.events.List(c, userID, ids, includeParticipants, includeTasks, includeLinks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if items == nil {
+		items = []models.EventDetails{}
+	}
+	renderFields(c, items, fieldset.Selection{"": fields})
+}
+
+// GetBySlug returns an event by its slug
+// @Summary Get an event by slug
+// @Description Look up an event the caller participates in by its slug, for share links
+// @Tags events
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title,startTime (default all)"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/by-slug/{slug} [get]
+func (h *EventHandler) GetBySlug(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	fields, ok := parseFields(c, "fields", models.Event{})
+	if !ok {
+		return
+	}
+	event, err := h.events.GetBySlug(c, c.Param("slug"), userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	renderFields(c, event, fieldset.Selection{"": fields})
+}
+
+// maxCalendarDays caps the range accepted by GET /calendar.
+const maxCalendarDays = 366
+
+// Calendar returns the caller's events bucketed by day
+// @Summary Calendar view
+// @Description All of the caller's events (organized and invited, any attendance) between from and to inclusive, bucketed by day in the given time zone. Multi-day events appear on each day they span.
+// @Tags events
+// @Produce json
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, inclusive (YYYY-MM-DD)"
+// @Param tz query string false "IANA time zone for day boundaries (default UTC)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.CalendarDay
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /calendar [get]
+func (h *EventHandler) Calendar(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz, use an IANA time zone name"})
+			return
+		}
+		loc = l
+	}
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'from' date format, use YYYY-MM-DD"})
+		return
+	}
+	last, err := time.ParseInLocation("2006-01-02", c.Query("to"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'to' date format, use YYYY-MM-DD"})
+		return
+	}
+	to := last.AddDate(0, 0, 1)
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+		return
+	}
+	if to.After(from.AddDate(0, 0, maxCalendarDays)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date range too large, max 366 days"})
+		return
+	}
+
+	days, err := h.events.Calendar(c, userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, days)
+}
+
+// ListOrganized lists events the caller organizes
+// @Summary List organized events
+// @Tags events
+// @Produce json
+// @Param window query string false "Time window: upcoming or past"
+// @Param sort query string false "Sort key: start_time (default), created_at or title"
+// @Param order query string false "Sort order: asc (default) or desc"
+// @Param attendance query string false "Caller's attendance: going, maybe, not_going or pending"
+// @Param archived query bool false "List archived events instead of active ones"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title,startTime (default all)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/organized [get]
 func (h *EventHandler) ListOrganized(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-	items, err := h.events.ListOrganized(c, userID)
+	var filter models.EventListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fields, ok := parseFields(c, "fields", models.EventSummary{})
+	if !ok {
+		return
+	}
+	items, err := h.events.ListOrganized(c, userID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, items)
+	renderFields(c, items, fieldset.Selection{"": fields})
 }
 
+// ListInvited lists events the caller was invited to as an attendee
+// @Summary List invited events
+// @Tags events
+// @Produce json
+// @Param window query string false "Time window: upcoming or past"
+// @Param sort query string false "Sort key: start_time (default), created_at or title"
+// @Param order query string false "Sort order: asc (default) or desc"
+// @Param attendance query string false "Caller's attendance: going, maybe, not_going or pending"
+// @Param archived query bool false "List archived events instead of active ones"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title,startTime (default all)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/invited [get]
 func (h *EventHandler) ListInvited(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-	items, err := h.events.ListInvited(c, userID)
+	var filter models.EventListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fields, ok := parseFields(c, "fields", models.EventSummary{})
+	if !ok {
+		return
+	}
+	items, err := h.events.ListInvited(c, userID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, items)
+	renderFields(c, items, fieldset.Selection{"": fields})
 }
 
+// Invite adds a user to an event with the given role
+// @Summary Invite a user
+// @Description Invite a user to an event with a built-in or custom role (requires manage_participants; the caller must also hold every permission of the granted role). With expiresAt, the invitation can no longer be answered after that time. New invitations count against the daily invitation quota, answered with 429 and Retry-After once used up.
+// @Tags participants
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.InviteRequest true "Invitee and role"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/invite [post]
 func (h *EventHandler) Invite(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
@@ -102,10 +442,15 @@ func (h *EventHandler) Invite(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot invite yourself"})
 		return
 	}
-	if err := h.events.Invite(c, eventID, userID, req.UserID, req.Role); err != nil {
+	if err := h.events.Invite(c, eventID, userID, req.UserID, req.Role, req.ExpiresAt); err != nil {
+		if quotaError(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
 			status = http.StatusForbidden
+		} else if errors.Is(err, services.ErrUnknownRole) || errors.Is(err, services.ErrExpiryInPast) {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -113,7 +458,26 @@ func (h *EventHandler) Invite(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User invited successfully"})
 }
 
-func (h *EventHandler) Delete(c *gin.Context) {
+// InviteMany invites many users at once
+// @Summary Invite users in bulk
+// @Description Invite up to 5000 users with the same role, by id or account email (requires manage_participants and every permission of the role). Send JSON, or a CSV file (Content-Type text/csv) with an email and/or user_id column, with role and expiresAt as query parameters. Users who already participate keep their role and are listed in alreadyParticipating; ids and emails without an account are listed too. The new invitations must fit in the daily invitation quota (429 with Retry-After otherwise).
+// @Tags participants
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.BulkInviteRequest false "Invitees and role (JSON)"
+// @Param role query string false "Role, for CSV files"
+// @Param expiresAt query string false "Invitation expiry (RFC 3339), for CSV files"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.BulkInviteResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/invites/bulk [post]
+func (h *EventHandler) InviteMany(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -124,46 +488,162 @@ func (h *EventHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
 		return
 	}
-	if err := h.events.Delete(c, eventID, userID); err != nil {
+	var req models.BulkInviteRequest
+	if c.ContentType() == "text/csv" {
+		req.Role = c.Query("role")
+		if req.Role == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role is required"})
+			return
+		}
+		if raw := c.Query("expiresAt"); raw != "" {
+			expiresAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiresAt"})
+				return
+			}
+			req.ExpiresAt = &expiresAt
+		}
+		table, err := readCSV(c)
+		if err == nil {
+			err = inviteesFromCSV(table, &req)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.events.InviteMany(c, eventID, userID, req)
+	if err != nil {
+		if quotaError(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
-		if errors.Is(err, pgx.ErrNoRows) {
+		switch {
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, pgx.ErrNoRows):
 			status = http.StatusForbidden
+		case errors.Is(err, services.ErrUnknownRole), errors.Is(err, services.ErrExpiryInPast),
+			errors.Is(err, services.ErrNoInvitees), errors.Is(err, services.ErrTooManyInvitees):
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Event deleted successfully"})
+	c.JSON(http.StatusOK, res)
 }
 
-// AcceptInvite handles accepting an event invitation
-func (h *EventHandler) AcceptInvite(c *gin.Context) {
+// RevokeInvite withdraws a pending invitation
+// @Summary Revoke an invitation
+// @Description Withdraw an invitation the invitee has not answered yet (requires manage_participants and every permission of the invitee's role). Until invited again, the user gets 410 with code invite_revoked from the accept and attendance endpoints. The response carries an undo token that restores the invitation with POST /undo/{token}.
+// @Tags participants
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param userId path int true "Invitee user ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/invites/{userId} [delete]
+func (h *EventHandler) RevokeInvite(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-
 	eventID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || eventID <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
 		return
 	}
-
-	// Use the service layer to update attendance
-	err = h.events.SetAttendance(c, eventID, userID, "going")
+	inviteeID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil || inviteeID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	undo, err := h.events.RevokeInvite(c, eventID, userID, inviteeID)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, pgx.ErrNoRows) {
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrNoPendingInvite):
 			status = http.StatusNotFound
+		case errors.Is(err, services.ErrInviteAnswered):
+			status = http.StatusConflict
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked", "undo": undo})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+// inviteError responds 410 when err is an invitation that can no longer be
+// answered and reports whether it did. The code tells clients whether the
+// invitation expired or was revoked.
+func inviteError(c *gin.Context, err error) bool {
+	var code string
+	switch {
+	case errors.Is(err, services.ErrInviteExpired):
+		code = "invite_expired"
+	case errors.Is(err, services.ErrInviteRevoked):
+		code = "invite_revoked"
+	default:
+		return false
+	}
+	c.JSON(http.StatusGone, gin.H{"error": err.Error(), "code": code})
+	return true
 }
 
-func (h *EventHandler) Participants(c *gin.Context) {
+// lockedError responds 423 with the lock when a co-organizer holds the
+// event's edit lock, and reports whether it did.
+func lockedError(c *gin.Context, err error) bool {
+	var locked *services.EditLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	c.JSON(http.StatusLocked, gin.H{"error": err.Error(), "lock": locked.Lock})
+	return true
+}
+
+// quotaError writes the response for an exhausted quota and reports whether
+// err was one: 402 for the active event quota, which only an operator can
+// raise, and 429 with Retry-After for the daily invitation quota.
+func quotaError(c *gin.Context, err error) bool {
+	var quota *services.QuotaError
+	if !errors.As(err, &quota) {
+		return false
+	}
+	status := http.StatusPaymentRequired
+	if errors.Is(err, services.ErrInviteQuota) {
+		status = http.StatusTooManyRequests
+		if quota.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(quota.RetryAfter.Seconds()))))
+		}
+	}
+	c.JSON(status, gin.H{"error": err.Error(), "limit": quota.Limit})
+	return true
+}
+
+// Delete removes an event
+// @Summary Delete an event
+// @Description Delete an event (requires delete_event). The response carries an undo token that restores the event with POST /undo/{token} until it expires; the event is purged afterwards.
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id} [delete]
+func (h *EventHandler) Delete(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -174,122 +654,1531 @@ func (h *EventHandler) Participants(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
 		return
 	}
-	items, err := h.events.Participants(c, eventID, userID)
+	undo, err := h.events.Delete(c, eventID, userID)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
 			status = http.StatusForbidden
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, items)
+	c.JSON(http.StatusOK, gin.H{"message": "Event deleted successfully", "undo": undo})
 }
 
-// CreateTask creates a new task for an event
-// @Summary Create a task
-// @Description Create a new task for an event (organizer only)
-// @Tags tasks
+// Bulk deletes, archives or cancels several events
+// @Summary Bulk delete, archive or cancel events
+// @Description Apply one action to up to 100 events. Each event is authorized on its own (delete_event to delete, edit_event to archive or cancel); the permitted ones are changed together in one transaction. The response lists the outcome per ID: done, forbidden (no permission or not a participant) or not_found. Participants of cancelled events are notified. Deleting and cancelling return an undo token for POST /undo/{token}.
+// @Tags events
 // @Accept json
 // @Produce json
-// @Param id path int true "Event ID"
-// @Param request body createTaskRequest true "Task details"
+// @Param request body models.BulkEventRequest true "Action and event IDs"
 // @Security ApiKeyAuth
-// @Success 201 {object} models.Task
+// @Success 200 {object} models.BulkEventResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /events/{id}/tasks [post]
-func (h *EventHandler) CreateTask(c *gin.Context) {
+// @Router /events/bulk [post]
+func (h *EventHandler) Bulk(c *gin.Context) {
 	userID := c.GetInt("userID")
 	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-
-	eventID, err := strconv.Atoi(c.Param("id"))
+	var req models.BulkEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.events.Bulk(c, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, res)
+}
 
-	var req createTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// Publish gives an event a public landing page
+// @Summary Publish an event
+// @Description Publish the event's landing page at /public/events/{slug} (requires edit_event). The title, description and location are screened by content moderation first: prohibited content is refused with 422, flagged content is published with hiddenAt set and waits for admin review.
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /events/{id}/publish [post]
+func (h *EventHandler) Publish(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-
-	task, err := h.events.CreateTask(
-		c.Request.Context(),
-		eventID,
-		userID,
-		req.Title,
-		req.Description,
-		req.DueDate,
-		req.AssigneeID,
-	)
-
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	event, err := h.events.Publish(c, eventID, userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		errMsg := err.Error()
-		
-		if errMsg == "only organizers can create tasks" {
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
 			status = http.StatusForbidden
-		} else if strings.Contains(errMsg, "violates foreign key constraint") {
-			status = http.StatusNotFound
-			errMsg = "event or assignee not found"
+		} else if errors.Is(err, services.ErrContentBlocked) {
+			status = http.StatusUnprocessableEntity
+		} else if errors.Is(err, services.ErrModerationFailed) {
+			status = http.StatusBadGateway
+			errMsg = services.ErrModerationFailed.Error()
 		}
-
 		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
+	c.JSON(http.StatusOK, event)
+}
 
-	c.JSON(http.StatusCreated, task)
+// Archive hides an event from the default listings
+// @Summary Archive an event
+// @Description Archived events only show up in listings with archived=true. Events are also archived automatically some days after they end (requires edit_event).
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/archive [post]
+func (h *EventHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
 }
 
-func (h *EventHandler) SetAttendance(c *gin.Context) {
-	requesterID := c.GetInt("userID")
-	if requesterID == 0 {
+// Unarchive brings an archived event back to the default listings
+// @Summary Unarchive an event
+// @Description The event is not archived automatically again, and counts against the organizer's active event quota (requires edit_event).
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 402 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/archive [delete]
+func (h *EventHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *EventHandler) setArchived(c *gin.Context, archived bool) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	event, err := h.events.SetArchived(c, eventID, userID, archived)
+	if err != nil {
+		if quotaError(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
 
+// Lock takes or renews the edit lock of an event
+// @Summary Lock an event for editing
+// @Description Tell co-organizers you are editing the event (requires edit_event). The lock lasts ttlSeconds (10-600, default 120); call again before it runs out to keep it. While it lasts, PATCH /events/{id} and rescheduling by anyone else get 423 with the lock. If someone else holds it, this returns 423 with their lock.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.EditLockRequest false "Lock duration"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EditLock
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 423 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lock [post]
+func (h *EventHandler) Lock(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 	eventID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || eventID <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
 		return
 	}
+	var req models.EditLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lock, err := h.events.LockForEditing(c, eventID, userID, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		if lockedError(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lock)
+}
 
-	var req struct {
-		UserID int    `json:"userId"`
-		Status string `json:"status" binding:"required,oneof=going maybe not_going"`
+// GetLock returns the edit lock of an event
+// @Summary Get an event's edit lock
+// @Description Who is editing the event and until when (requires edit_event); 404 when nobody is.
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EditLock
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lock [get]
+func (h *EventHandler) GetLock(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	lock, err := h.events.EditLock(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusNotFound
+			err = errors.New("nobody is editing this event")
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lock)
+}
+
+// Unlock releases the caller's edit lock of an event
+// @Summary Unlock an event
+// @Description Release the edit lock you hold on the event, e.g. when closing the editor
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/lock [delete]
+func (h *EventHandler) Unlock(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
 	}
+	if err := h.events.Unlock(c, eventID, userID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusNotFound
+			err = errors.New("you do not hold the edit lock of this event")
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event unlocked"})
+}
 
+// Update changes some of an event's fields
+// @Summary Update an event
+// @Description Change only the fields present in the body (requires edit_event). An empty meetingUrl removes the link, venueId 0 removes the venue. Use POST /events/{id}/reschedule to change times. Only organizers may set requireChangeApproval; while it is on, everyone else gets 403 and proposes changes with POST /events/{id}/proposals. rsvpVisibility (full, counts or none) requires manage_participants.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.UpdateEventRequest true "Fields to change"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 423 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /events/{id} [patch]
+func (h *EventHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.UpdateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	event, err := h.events.Update(c, eventID, userID, req)
+	if err != nil {
+		if lockedError(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		errMsg := err.Error()
+		switch {
+		case errors.Is(err, services.ErrTitleRequired), errors.Is(err, services.ErrInvalidEventType), errors.Is(err, services.ErrMeetingNotAllowed):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrApprovalRequired), errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusForbidden
+		case strings.Contains(errMsg, "violates foreign key constraint"):
+			status = http.StatusBadRequest
+			errMsg = "venue not found"
+		}
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
 
-	targetUserID := req.UserID
-	if targetUserID == 0 {
-		targetUserID = requesterID
+// Nudge reminds invitees who have not responded
+// @Summary Nudge pending invitees
+// @Description Remind invitees who have not responded within afterDays (default 3) of their invitation or last nudge (requires manage_participants). Each invitee is nudged at most 3 times per event, and only before the event starts.
+// @Tags participants
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.NudgeRequest false "Nudge options"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Participant
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/nudges [post]
+func (h *EventHandler) Nudge(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
 	}
+	var req models.NudgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	nudged, err := h.events.Nudge(c, eventID, userID, req.AfterDays)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, nudged)
+}
 
-	// Users can only update their own attendance
-	if targetUserID != requesterID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only update your own attendance"})
+// Reschedule moves an event to a new time
+// @Summary Reschedule an event
+// @Description Move the event to a new start (and optionally end) time (requires edit_event). Sessions shift by the same amount, tasks with a dueOffset get new due dates, resetRsvps clears every attendee's attendance, and participants are notified of the old and new times.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.RescheduleRequest true "New time"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 423 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/reschedule [post]
+func (h *EventHandler) Reschedule(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid startTime, use RFC3339"})
+		return
+	}
+	var end *time.Time
+	if req.EndTime != "" {
+		t, err := time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid endTime, use RFC3339"})
+			return
+		}
+		end = &t
+	}
+	event, err := h.events.Reschedule(c, eventID, userID, start, end, req.ResetRSVPs)
+	if err != nil {
+		if lockedError(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidTimeRange), errors.Is(err, services.ErrSessionOutOfRange), errors.Is(err, services.ErrInvalidDueOffset):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, event)
+}
 
-	if err := h.events.SetAttendance(c, eventID, targetUserID, req.Status); err != nil {
+// Unpublish takes an event's landing page down
+// @Summary Unpublish an event
+// @Description Take the public landing page down (requires edit_event)
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/publish [delete]
+func (h *EventHandler) Unpublish(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	if err := h.events.Unpublish(c, eventID, userID); err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, pgx.ErrNoRows) {
-			status = http.StatusNotFound
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusForbidden
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event unpublished successfully"})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Attendance updated successfully"})
+// AcceptInvite handles accepting an event invitation
+// @Summary Accept an invitation
+// @Description Mark the caller as going to the event. Required RSVP questions must be answered in the body.
+// @Tags participants
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.AcceptRequest false "Answers to the event's RSVP questions"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string "code invite_expired or invite_revoked"
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/accept [put]
+func (h *EventHandler) AcceptInvite(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	// The body is optional; events without questions accept an empty request
+	var req models.AcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Use the service layer to update attendance
+	err = h.events.SetAttendance(c, eventID, userID, "going", req.Answers)
+	if inviteError(c, err) {
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidAnswer) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+}
+
+// Mute stops announcements and reminders about an event
+// @Summary Mute an event
+// @Description The caller stays a participant but no longer receives announcements or RSVP reminders about the event. Invitations, schedule changes and transfers are still delivered.
+// @Tags participants
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/mute [put]
+func (h *EventHandler) Mute(c *gin.Context) {
+	h.setMuted(c, true)
+}
+
+// Unmute resumes announcements and reminders about an event
+// @Summary Unmute an event
+// @Tags participants
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/mute [delete]
+func (h *EventHandler) Unmute(c *gin.Context) {
+	h.setMuted(c, false)
+}
+
+func (h *EventHandler) setMuted(c *gin.Context, muted bool) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	if err := h.events.SetMuted(c, eventID, userID, muted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "you do not participate in this event"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"muted": muted})
+}
+
+// Participants lists the participants of an event
+// @Summary List attendees
+// @Description List participants of an event: with manage_participants, or as any participant when the event's rsvpVisibility is full, without emails then
+// @Tags participants
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Participant
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/attendees [get]
+func (h *EventHandler) Participants(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.events.Participants(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) || errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// AttendanceCounts counts an event's participants by answer
+// @Summary Count attendees
+// @Description How many participants are going, maybe, not going or have not answered: with manage_participants, or as any participant unless the event's rsvpVisibility is none
+// @Tags participants
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.AttendanceCounts
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/attendees/counts [get]
+func (h *EventHandler) AttendanceCounts(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	counts, err := h.events.AttendanceCounts(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// CreateTask creates a new task for an event
+// @Summary Create a task
+// @Description Create a new task for an event (requires manage_tasks). dueOffset (e.g. "-7d") makes the due date relative to the event start, so it moves when the event is rescheduled.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body createTaskRequest true "Task details"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks [post]
+func (h *EventHandler) CreateTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req createTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.events.CreateTask(
+		c.Request.Context(),
+		eventID,
+		userID,
+		req.Title,
+		req.Description,
+		req.DueDate,
+		req.DueOffset,
+		req.AssigneeID,
+	)
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		errMsg := err.Error()
+		
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		} else if errors.Is(err, services.ErrInvalidDueOffset) || errors.Is(err, services.ErrDueDateConflict) {
+			status = http.StatusBadRequest
+		} else if strings.Contains(errMsg, "violates foreign key constraint") {
+			status = http.StatusNotFound
+			errMsg = "event or assignee not found"
+		}
+
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// UpdateTask changes some of a task's fields
+// @Summary Update a task
+// @Description Change only the fields present in the body (requires manage_tasks). dueDate (RFC3339) sets an absolute due date, dueOffset (e.g. "-7d") one relative to the event start; an empty value clears the due date. assigneeId 0 unassigns the task. completed marks the task done or open again. estimatedMinutes sets how long the task should take; 0 clears it.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body updateTaskRequest true "Fields to change"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId} [patch]
+func (h *EventHandler) UpdateTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil || taskID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return
+	}
+	var req updateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	patch := models.TaskPatch{Title: req.Title, Description: req.Description, AssigneeID: req.AssigneeID, Completed: req.Completed, EstimatedMinutes: req.EstimatedMinutes}
+	if req.DueDate != nil {
+		patch.Due = &models.TaskDue{}
+		if *req.DueDate != "" {
+			t, err := time.Parse(time.RFC3339, *req.DueDate)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dueDate, use RFC3339"})
+				return
+			}
+			patch.Due.Date = &t
+		}
+	}
+	if req.DueOffset != nil {
+		if patch.Due == nil {
+			patch.Due = &models.TaskDue{}
+		}
+		patch.Due.Offset = req.DueOffset
+	}
+	task, err := h.events.UpdateTask(c, eventID, taskID, userID, patch)
+	if err != nil {
+		status := http.StatusInternalServerError
+		errMsg := err.Error()
+		switch {
+		case errors.Is(err, services.ErrTaskTitleRequired), errors.Is(err, services.ErrInvalidDueOffset), errors.Is(err, services.ErrDueDateConflict):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusNotFound
+			errMsg = "task not found"
+		case strings.Contains(errMsg, "violates foreign key constraint"):
+			status = http.StatusBadRequest
+			errMsg = "assignee not found"
+		}
+		c.JSON(status, gin.H{"error": errMsg})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// ListTasks lists an event's tasks
+// @Summary List tasks
+// @Description The event's tasks in their order, each with its labels (any participant). With labels, only tasks with all of the named labels are listed, matched regardless of case.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param labels query string false "Comma-separated label names, e.g. catering,urgent"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks [get]
+func (h *EventHandler) ListTasks(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	tasks, err := h.events.ListTasks(c, eventID, userID, labelsParam(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// OrderTasks arranges an event's tasks
+// @Summary Arrange tasks
+// @Description Put the event's tasks in execution order (requires manage_tasks). Either taskIds lists tasks in their new order, ahead of those not listed, which keep their order and all are numbered from 1; or positions moves single tasks, e.g. position 2.5 between the tasks at 2 and 3. Tasks are listed by position, those never arranged after the others by due date. Returns all of the event's tasks in their new order.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.TaskOrderRequest true "New order"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/order [put]
+func (h *EventHandler) OrderTasks(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.TaskOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tasks, err := h.events.OrderTasks(c, eventID, userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidTaskOrder), errors.Is(err, services.ErrTooManyTasks):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrTaskNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// CreateTasks creates many tasks for an event at once
+// @Summary Create tasks in bulk
+// @Description Create up to 200 tasks in one transaction (requires manage_tasks). With template (built-in: meetup, conference, wedding) or templateId (a saved task template), that checklist's tasks come first, due relative to the event start.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.BulkTaskRequest true "Tasks and/or template"
+// @Security ApiKeyAuth
+// @Success 201 {array} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/bulk [post]
+func (h *EventHandler) CreateTasks(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tasks, err := h.events.CreateTasks(c.Request.Context(), eventID, userID, req)
+	if err != nil {
+		bulkTaskError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, tasks)
+}
+
+// ImportTasks creates tasks from a CSV file
+// @Summary Import tasks from CSV
+// @Description Create up to 5000 tasks from a CSV file in one transaction (requires manage_tasks). The first row names the columns: title (required), description, due_date (RFC 3339 or YYYY-MM-DD), due_offset (e.g. -2w, relative to the event start) and assignee_id; other columns are ignored. Rows are written with COPY, so thousands import in seconds.
+// @Tags tasks
+// @Accept text/csv
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 201 {array} models.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/import [post]
+func (h *EventHandler) ImportTasks(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	table, err := readCSV(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	inputs, err := tasksFromCSV(table)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tasks, err := h.events.ImportTasks(c.Request.Context(), eventID, userID, inputs)
+	if err != nil {
+		bulkTaskError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, tasks)
+}
+
+func bulkTaskError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	errMsg := err.Error()
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrUnknownTemplate), errors.Is(err, services.ErrNoTasks),
+		errors.Is(err, services.ErrTooManyTasks), errors.Is(err, services.ErrTaskTitleRequired),
+		errors.Is(err, services.ErrInvalidDueOffset), errors.Is(err, services.ErrDueDateConflict):
+		status = http.StatusBadRequest
+	case strings.Contains(errMsg, "violates foreign key constraint"):
+		status = http.StatusNotFound
+		errMsg = "event or assignee not found"
+	}
+	c.JSON(status, gin.H{"error": errMsg})
+}
+
+// SetAttendance updates the caller's attendance status
+// @Summary Update attendance
+// @Description Update the caller's attendance. Going requires answers to the event's required RSVP questions.
+// @Tags participants
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.AttendanceRequest true "Attendance status"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string "code invite_expired or invite_revoked"
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/attendance [put]
+func (h *EventHandler) SetAttendance(c *gin.Context) {
+	requesterID := c.GetInt("userID")
+	if requesterID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	var req struct {
+		UserID  int                 `json:"userId"`
+		Status  string              `json:"status" binding:"required,oneof=going maybe not_going"`
+		Answers []models.RSVPAnswer `json:"answers" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	targetUserID := req.UserID
+	if targetUserID == 0 {
+		targetUserID = requesterID
+	}
+
+	// Users can only update their own attendance
+	if targetUserID != requesterID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only update your own attendance"})
+		return
+	}
+
+	if err := h.events.SetAttendance(c, eventID, targetUserID, req.Status, req.Answers); err != nil {
+		if inviteError(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidAnswer) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attendance updated successfully"})
+}
+
+// ListRoles lists the roles available on an event
+// @Summary List event roles
+// @Description List the built-in roles and the event's custom roles with their permissions (any participant)
+// @Tags roles
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventRole
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/roles [get]
+func (h *EventHandler) ListRoles(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	roles, err := h.events.ListRoles(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// CreateRole defines a custom role on an event
+// @Summary Create a custom role
+// @Description Define a custom role (e.g. volunteer, speaker) with permissions from the matrix. Requires edit_event, and the caller may only grant permissions they hold.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.EventRoleRequest true "Role name and permissions"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.EventRole
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/roles [post]
+func (h *EventHandler) CreateRole(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.EventRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := h.events.CreateRole(c, eventID, userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrRoleExists):
+			status = http.StatusConflict
+		case errors.Is(err, services.ErrUnknownRole):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// DeleteRole removes a custom role from an event
+// @Summary Delete a custom role
+// @Description Delete a custom role that no participant holds (requires edit_event)
+// @Tags roles
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param name path string true "Role name"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/roles/{name} [delete]
+func (h *EventHandler) DeleteRole(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	if err := h.events.DeleteRole(c, eventID, userID, c.Param("name")); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrRoleInUse):
+			status = http.StatusConflict
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusNotFound
+			err = errors.New("role not found")
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// ListQuestions lists the RSVP questions of an event
+// @Summary List RSVP questions
+// @Description List the questions invitees answer when accepting (any participant)
+// @Tags rsvp
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.RSVPQuestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/questions [get]
+func (h *EventHandler) ListQuestions(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.events.ListQuestions(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// CreateQuestion attaches an RSVP question to an event
+// @Summary Create an RSVP question
+// @Description Add a text or choice question invitees answer when accepting (requires edit_event). Questions are required unless required is false.
+// @Tags rsvp
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.RSVPQuestionRequest true "Question"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.RSVPQuestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/questions [post]
+func (h *EventHandler) CreateQuestion(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.RSVPQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q, err := h.events.CreateQuestion(c, eventID, userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrInvalidQuestion):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, q)
+}
+
+// DeleteQuestion removes an RSVP question
+// @Summary Delete an RSVP question
+// @Description Delete a question and every answer to it (requires edit_event)
+// @Tags rsvp
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param questionId path int true "Question ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/questions/{questionId} [delete]
+func (h *EventHandler) DeleteQuestion(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	questionID, err := strconv.Atoi(c.Param("questionId"))
+	if err != nil || questionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid question id"})
+		return
+	}
+	if err := h.events.DeleteQuestion(c, eventID, userID, questionID); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusNotFound
+			err = errors.New("question not found")
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// ExportResponses exports every participant's RSVP answers
+// @Summary Export RSVP responses
+// @Description Every participant with their attendance and answers (requires manage_participants). format=csv returns a spreadsheet with one column per question.
+// @Tags rsvp
+// @Produce json
+// @Produce text/csv
+// @Param id path int true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RSVPExport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/responses [get]
+func (h *EventHandler) ExportResponses(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, must be 'json' or 'csv'"})
+		return
+	}
+	export, err := h.events.ExportResponses(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-responses.csv"`, eventID))
+	w := csv.NewWriter(c.Writer)
+	header := []string{"user_id", "name", "email", "role", "attendance"}
+	for _, q := range export.Questions {
+		header = append(header, q.Prompt)
+	}
+	_ = w.Write(header)
+	for _, r := range export.Responses {
+		attendance := ""
+		if r.Attendance != nil {
+			attendance = *r.Attendance
+		}
+		row := []string{strconv.Itoa(r.UserID), r.UserName, r.UserEmail, r.Role, attendance}
+		for _, q := range export.Questions {
+			row = append(row, r.Answers[q.ID])
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+}
+
+// Announce sends an announcement to the event's participants
+// @Summary Send an announcement
+// @Description Store an announcement and notify participants in-app and by email, optionally only those with the given attendance statuses (requires manage_participants)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.AnnouncementRequest true "Announcement"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Announcement
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/announcements [post]
+func (h *EventHandler) Announce(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	a, err := h.events.Announce(c, eventID, userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// ListAnnouncements returns an event's announcement history
+// @Summary List announcements
+// @Description Announcements of the event, newest first. Participants only see those addressed to their attendance status; managers see all.
+// @Tags announcements
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Announcement
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/announcements [get]
+func (h *EventHandler) ListAnnouncements(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.events.ListAnnouncements(c, eventID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// AddAnnouncementReaction reacts to an announcement with an emoji
+// @Summary React to an announcement
+// @Description React to an announcement the caller was sent, or can see as a manager, with an emoji (URL-encoded in the path). Reacting again with the same emoji changes nothing; each participant can react with up to 20 different emoji.
+// @Tags announcements
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param announcementId path int true "Announcement ID"
+// @Param emoji path string true "Emoji, e.g. %F0%9F%91%8D for 👍"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Reaction
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/announcements/{announcementId}/reactions/{emoji} [put]
+func (h *EventHandler) AddAnnouncementReaction(c *gin.Context) {
+	h.reactToAnnouncement(c, h.events.AddAnnouncementReaction)
+}
+
+// RemoveAnnouncementReaction takes back a reaction to an announcement
+// @Summary Remove a reaction to an announcement
+// @Description Take back the caller's reaction with the emoji, if any.
+// @Tags announcements
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param announcementId path int true "Announcement ID"
+// @Param emoji path string true "Emoji, URL-encoded"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Reaction
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/announcements/{announcementId}/reactions/{emoji} [delete]
+func (h *EventHandler) RemoveAnnouncementReaction(c *gin.Context) {
+	h.reactToAnnouncement(c, h.events.RemoveAnnouncementReaction)
+}
+
+func (h *EventHandler) reactToAnnouncement(c *gin.Context, react func(ctx context.Context, eventID, announcementID, userID int, emoji string) ([]models.Reaction, error)) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	announcementID, err := strconv.Atoi(c.Param("announcementId"))
+	if err != nil || announcementID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid announcement id"})
+		return
+	}
+	reactions, err := react(c, eventID, announcementID, userID, c.Param("emoji"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrInvalidEmoji):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrNoAnnouncement):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrTooManyReactions):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reactions)
 }
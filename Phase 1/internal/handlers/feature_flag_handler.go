@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type FeatureFlagHandler struct {
+	flags services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(flags services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags}
+}
+
+func flagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidFlagName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "flag not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// List returns every feature flag
+// @Summary List feature flags
+// @Description Every flag set in FEATURE_FLAGS or the database, and the defaults of the features the server consults, by name. source says where each flag's settings come from: default, config or database (admins only)
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.FeatureFlag
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/flags [get]
+func (h *FeatureFlagHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	list, err := h.flags.List(c, userID)
+	if err != nil {
+		flagError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Set creates or replaces a feature flag
+// @Summary Set a feature flag
+// @Description Turn a feature on for everyone (enabled), for the listed users, and for a percentage of the other users. The flag is stored in the database and overrides the one in FEATURE_FLAGS; other server instances apply it within 30 seconds (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Flag name, e.g. payments"
+// @Param request body models.FeatureFlagRequest true "Flag"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.FeatureFlag
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/flags/{name} [put]
+func (h *FeatureFlagHandler) Set(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	flag, err := h.flags.Set(c, userID, c.Param("name"), req)
+	if err != nil {
+		flagError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, flag)
+}
+
+// Delete removes a feature flag from the database
+// @Summary Delete a feature flag
+// @Description Remove the flag set in the database, so the one in FEATURE_FLAGS, or the feature's default, applies again (admins only)
+// @Tags admin
+// @Param name path string true "Flag name"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/flags/{name} [delete]
+func (h *FeatureFlagHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if err := h.flags.Delete(c, userID, c.Param("name")); err != nil {
+		flagError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Mine returns the features that are on for the caller
+// @Summary Get my feature flags
+// @Description Whether each flag is on for the caller, so clients can show or hide the features behind them
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]bool
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/flags [get]
+func (h *FeatureFlagHandler) Mine(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	flags, err := h.flags.Mine(c, userID)
+	if err != nil {
+		flagError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, flags)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type FeedbackHandler struct {
+	feedback services.FeedbackService
+}
+
+func NewFeedbackHandler(feedback services.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedback: feedback}
+}
+
+// feedbackError writes the HTTP response for a feedback service error.
+func feedbackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrNotAttendee):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+	case errors.Is(err, services.ErrPromptRequired), errors.Is(err, services.ErrInvalidFeedback):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrFeedbackNotOpen), errors.Is(err, services.ErrFeedbackGiven):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// CreateQuestion adds a question to an event's feedback survey
+// @Summary Create a feedback question
+// @Description Add a rating (1-5) or free text question to the post-event survey; questions are optional unless required is true (requires edit_event)
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.FeedbackQuestionRequest true "Question"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.FeedbackQuestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/feedback/questions [post]
+func (h *FeedbackHandler) CreateQuestion(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.FeedbackQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q, err := h.feedback.CreateQuestion(c, eventID, userID, req)
+	if err != nil {
+		feedbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, q)
+}
+
+// ListQuestions returns an event's feedback survey
+// @Summary List feedback questions
+// @Description The post-event survey questions in display order (any participant)
+// @Tags feedback
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.FeedbackQuestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/feedback/questions [get]
+func (h *FeedbackHandler) ListQuestions(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	questions, err := h.feedback.ListQuestions(c, eventID, userID)
+	if err != nil {
+		feedbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, questions)
+}
+
+// DeleteQuestion removes a feedback question
+// @Summary Delete a feedback question
+// @Description Remove a question from the survey together with its answers (requires edit_event)
+// @Tags feedback
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param questionId path int true "Question ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/feedback/questions/{questionId} [delete]
+func (h *FeedbackHandler) DeleteQuestion(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	questionID, err := strconv.Atoi(c.Param("questionId"))
+	if err != nil || questionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid question id"})
+		return
+	}
+	if err := h.feedback.DeleteQuestion(c, eventID, questionID, userID); err != nil {
+		feedbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// Submit records the caller's feedback
+// @Summary Submit feedback
+// @Description Answer the post-event survey once the event has ended (its start time when it has no end). Only participants whose attendance is going can answer, and only once
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.FeedbackRequest true "Answers"
+// @Security ApiKeyAuth
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/feedback [post]
+func (h *FeedbackHandler) Submit(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.feedback.Submit(c, eventID, userID, req); err != nil {
+		feedbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Thanks for your feedback"})
+}
+
+// Summary aggregates an event's feedback
+// @Summary Get the feedback summary
+// @Description Number of responses, average and score distribution of each rating question, and the comments of each text question without their authors (requires edit_event)
+// @Tags feedback
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.FeedbackSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/feedback/summary [get]
+func (h *FeedbackHandler) Summary(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	summary, err := h.feedback.Summary(c, eventID, userID)
+	if err != nil {
+		feedbackError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type FollowHandler struct {
+	follows services.FollowService
+}
+
+func NewFollowHandler(follows services.FollowService) *FollowHandler {
+	return &FollowHandler{follows: follows}
+}
+
+// followError writes the HTTP response for a follow service error.
+func followError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrFollowSelf), errors.Is(err, services.ErrUnknownUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// followTargetID parses the id of the followed user or series.
+func followTargetID(c *gin.Context, what string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + what + " id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// FollowOrganizer follows an organizer
+// @Summary Follow an organizer
+// @Description Get notified when the organizer publishes a new event
+// @Tags follows
+// @Produce json
+// @Param id path int true "Organizer user ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/following/organizers/{id} [put]
+func (h *FollowHandler) FollowOrganizer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	organizerID, ok := followTargetID(c, "user")
+	if !ok {
+		return
+	}
+	if err := h.follows.FollowOrganizer(c, userID, organizerID); err != nil {
+		followError(c, err, "user not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Following organizer"})
+}
+
+// UnfollowOrganizer stops following an organizer
+// @Summary Unfollow an organizer
+// @Description Stop getting notified about the organizer's new events
+// @Tags follows
+// @Produce json
+// @Param id path int true "Organizer user ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/following/organizers/{id} [delete]
+func (h *FollowHandler) UnfollowOrganizer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	organizerID, ok := followTargetID(c, "user")
+	if !ok {
+		return
+	}
+	if err := h.follows.UnfollowOrganizer(c, userID, organizerID); err != nil {
+		followError(c, err, "you do not follow this organizer")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed organizer"})
+}
+
+// FollowSeries follows a series
+// @Summary Follow a series
+// @Description Get notified when an event of the series is published. Series of others can be followed once one of their events was published, or by their members
+// @Tags follows
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/following/series/{id} [put]
+func (h *FollowHandler) FollowSeries(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	seriesID, ok := followTargetID(c, "series")
+	if !ok {
+		return
+	}
+	if err := h.follows.FollowSeries(c, userID, seriesID); err != nil {
+		followError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Following series"})
+}
+
+// UnfollowSeries stops following a series
+// @Summary Unfollow a series
+// @Description Stop getting notified about the series' new events
+// @Tags follows
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/following/series/{id} [delete]
+func (h *FollowHandler) UnfollowSeries(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	seriesID, ok := followTargetID(c, "series")
+	if !ok {
+		return
+	}
+	if err := h.follows.UnfollowSeries(c, userID, seriesID); err != nil {
+		followError(c, err, "you do not follow this series")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed series"})
+}
+
+// Following lists what the caller follows
+// @Summary List follows
+// @Description The organizers and series the caller follows, by name
+// @Tags follows
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Following
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/following [get]
+func (h *FollowHandler) Following(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	following, err := h.follows.Following(c, userID)
+	if err != nil {
+		followError(c, err, "not found")
+		return
+	}
+	c.JSON(http.StatusOK, following)
+}
+
+// Followers counts the caller's followers
+// @Summary Count my followers
+// @Description How many users follow the caller as an organizer
+// @Tags follows
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.FollowerCount
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/followers [get]
+func (h *FollowHandler) Followers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	count, err := h.follows.Followers(c, userID)
+	if err != nil {
+		followError(c, err, "not found")
+		return
+	}
+	c.JSON(http.StatusOK, count)
+}
+
+// SeriesFollowers counts a series' followers
+// @Summary Count series followers
+// @Description How many users follow the series (owner only)
+// @Tags follows
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.FollowerCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id}/followers [get]
+func (h *FollowHandler) SeriesFollowers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	seriesID, ok := followTargetID(c, "series")
+	if !ok {
+		return
+	}
+	count, err := h.follows.SeriesFollowers(c, seriesID, userID)
+	if err != nil {
+		followError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, count)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eventplanner-backend/internal/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxGraphQLBody caps the body of a GraphQL request.
+const maxGraphQLBody = 1 << 20
+
+type GraphQLHandler struct {
+	schema *graph.Schema
+}
+
+func NewGraphQLHandler(schema *graph.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// Query executes a GraphQL query
+// @Summary GraphQL endpoint
+// @Description Execute a GraphQL query against the schema in internal/graph/schema.graphqls (events with nested participants, tasks and comments)
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graph.Request true "GraphQL request"
+// @Security ApiKeyAuth
+// @Success 200 {object} graph.Response
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLBody)
+	var req graph.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query is required"})
+		return
+	}
+	c.JSON(http.StatusOK, h.schema.Execute(c.Request.Context(), userID, req))
+}
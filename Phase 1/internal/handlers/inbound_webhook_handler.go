@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxInboundPayload caps the body of an inbound webhook delivery.
+const maxInboundPayload = 1 << 20
+
+type InboundWebhookHandler struct {
+	webhooks services.InboundWebhookService
+}
+
+func NewInboundWebhookHandler(webhooks services.InboundWebhookService) *InboundWebhookHandler {
+	return &InboundWebhookHandler{webhooks: webhooks}
+}
+
+// inboundWebhookError writes the HTTP response for an inbound webhook service
+// error.
+func inboundWebhookError(c *gin.Context, err error) {
+	if quotaError(c, err) {
+		return
+	}
+	var duplicate *services.DuplicateEventError
+	switch {
+	case errors.As(err, &duplicate):
+		c.JSON(http.StatusConflict, models.DuplicateWarning{Error: err.Error(), Duplicates: duplicate.Candidates})
+	case errors.Is(err, services.ErrDuplicateDelivery):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+	case errors.Is(err, services.ErrInvalidMapping), errors.Is(err, services.ErrInvalidPayload),
+		errors.Is(err, services.ErrInvalidTimeRange), errors.Is(err, services.ErrMeetingNotAllowed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// inboundWebhookParams reads the caller and the webhook id, writing the error
+// response when either is missing or invalid.
+func inboundWebhookParams(c *gin.Context) (userID, id int, ok bool) {
+	userID = c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+// Create adds an inbound webhook
+// @Summary Create an inbound webhook
+// @Description Create an endpoint external systems call to create events, or tasks of eventId (requires manage_tasks). mapping maps the fields to create to dotted paths in the request JSON: title and startTime (RFC3339), plus optionally endTime, description, location, type and meetingUrl for events; title, plus optionally description and dueDate, for tasks. Requests are signed with the caller's webhook secret.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.InboundWebhookRequest true "Webhook"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.InboundWebhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/inbound-webhooks [post]
+func (h *InboundWebhookHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.InboundWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	w, err := h.webhooks.Create(c, userID, req)
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// List returns the caller's inbound webhooks
+// @Summary List inbound webhooks
+// @Description The caller's inbound webhooks with when each last received a delivery and its error, if any
+// @Tags webhooks
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.InboundWebhook
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/inbound-webhooks [get]
+func (h *InboundWebhookHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.webhooks.List(c, userID)
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Update replaces an inbound webhook
+// @Summary Update an inbound webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body models.InboundWebhookRequest true "Webhook"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.InboundWebhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/inbound-webhooks/{id} [put]
+func (h *InboundWebhookHandler) Update(c *gin.Context) {
+	userID, id, ok := inboundWebhookParams(c)
+	if !ok {
+		return
+	}
+	var req models.InboundWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	w, err := h.webhooks.Update(c, id, userID, req)
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// Delete removes an inbound webhook
+// @Summary Delete an inbound webhook
+// @Tags webhooks
+// @Param id path int true "Webhook ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/inbound-webhooks/{id} [delete]
+func (h *InboundWebhookHandler) Delete(c *gin.Context) {
+	userID, id, ok := inboundWebhookParams(c)
+	if !ok {
+		return
+	}
+	if err := h.webhooks.Delete(c, id, userID); err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Secret returns the caller's webhook signing secret
+// @Summary Get the webhook secret
+// @Description The secret signing requests to all of the caller's inbound webhooks. It is created with the first webhook or by rotating; 404 before that
+// @Tags webhooks
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.WebhookSecret
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/webhook-secret [get]
+func (h *InboundWebhookHandler) Secret(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	secret, err := h.webhooks.Secret(c, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no webhook secret yet; create a webhook or rotate to create one"})
+		return
+	}
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.WebhookSecret{Secret: secret})
+}
+
+// RotateSecret replaces the caller's webhook signing secret
+// @Summary Rotate the webhook secret
+// @Description Replace the caller's webhook secret, or create their first; requests signed with the old one are rejected from then on
+// @Tags webhooks
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.WebhookSecret
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/webhook-secret [post]
+func (h *InboundWebhookHandler) RotateSecret(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	secret, err := h.webhooks.RotateSecret(c, userID)
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.WebhookSecret{Secret: secret})
+}
+
+// Receive takes a delivery from an external system
+// @Summary Inbound webhook
+// @Description Create an event or task from the JSON body using the webhook's mapping, as the webhook's owner. No authentication; requests carry an X-Eventplanner-Signature header "t=<unix>,v1=<hex>", the HMAC-SHA256 of "<t>.<body>" under the owner's webhook secret, and are rejected 5 minutes after t. A request whose signature was already delivered successfully is rejected as a replay (409); a failed one may be retried as is. Events that look like one the owner already has are not created again (409), so retried deliveries are safe.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 201 {object} models.InboundDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 402 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} models.DuplicateWarning
+// @Failure 500 {object} map[string]string
+// @Router /hooks/{id} [post]
+func (h *InboundWebhookHandler) Receive(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundPayload)
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	res, err := h.webhooks.Receive(c, id, payload, c.Request.Header)
+	if err != nil {
+		inboundWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
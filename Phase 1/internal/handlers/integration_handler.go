@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// APIKeyHeader carries an API key in place of a login.
+const APIKeyHeader = "X-API-Key"
+
+type IntegrationHandler struct {
+	integrations services.IntegrationService
+}
+
+func NewIntegrationHandler(integrations services.IntegrationService) *IntegrationHandler {
+	return &IntegrationHandler{integrations: integrations}
+}
+
+// integrationError writes the HTTP response for an integration service error.
+func integrationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownTrigger):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Authenticate is middleware that signs in requests carrying an API key as
+// the key's owner. Requests with an unknown key are rejected; requests
+// without one pass through unchanged.
+func (h *IntegrationHandler) Authenticate(c *gin.Context) {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	userID, err := h.integrations.Authenticate(c, key)
+	if errors.Is(err, services.ErrInvalidAPIKey) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Set("userID", userID)
+	c.Set("apiKey", true)
+	c.Next()
+}
+
+// CreateAPIKey issues an API key
+// @Summary Create an API key
+// @Description Issue a key integrations send in the X-API-Key header to call the API as the caller. The key is only shown in this response. Keys cannot create other keys.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param request body models.APIKeyRequest true "Key name"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.CreatedAPIKey
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/api-keys [post]
+func (h *IntegrationHandler) CreateAPIKey(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if c.GetBool("apiKey") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot create API keys"})
+		return
+	}
+	var req models.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	k, err := h.integrations.CreateAPIKey(c, userID, req)
+	if err != nil {
+		integrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, k)
+}
+
+// ListAPIKeys returns the caller's API keys
+// @Summary List API keys
+// @Description The caller's API keys with their prefix and when they were last used, not the keys themselves
+// @Tags integrations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/api-keys [get]
+func (h *IntegrationHandler) ListAPIKeys(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	keys, err := h.integrations.ListAPIKeys(c, userID)
+	if err != nil {
+		integrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// DeleteAPIKey revokes an API key
+// @Summary Revoke an API key
+// @Tags integrations
+// @Param id path int true "API key ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/api-keys/{id} [delete]
+func (h *IntegrationHandler) DeleteAPIKey(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key id"})
+		return
+	}
+	if err := h.integrations.DeleteAPIKey(c, id, userID); err != nil {
+		integrationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Triggers lists the trigger feeds
+// @Summary List triggers
+// @Description The feeds integrations can poll for new items
+// @Tags integrations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Trigger
+// @Failure 401 {object} map[string]string
+// @Router /integrations/triggers [get]
+func (h *IntegrationHandler) Triggers(c *gin.Context) {
+	if c.GetInt("userID") == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.JSON(http.StatusOK, services.Triggers)
+}
+
+// Poll returns a trigger's new items
+// @Summary Poll a trigger
+// @Description The trigger's items after cursor, oldest first, for events the caller organizes. Without a cursor, the newest items. Pass the returned cursor to the next poll; it stays valid, so polls never skip or repeat items. Item ids are stable for deduplication. Feeds run a few seconds behind.
+// @Tags integrations
+// @Produce json
+// @Param key path string true "Trigger" Enums(new_event, new_rsvp, task_completed)
+// @Param cursor query string false "Cursor from the previous poll"
+// @Param limit query int false "Items per poll (default 50, max 100)"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TriggerPage
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /integrations/triggers/{key} [get]
+func (h *IntegrationHandler) Poll(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit := services.DefaultTriggerLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > services.MaxTriggerLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = n
+	}
+	page, err := h.integrations.Poll(c, userID, c.Param("key"), c.Query("cursor"), limit)
+	if err != nil {
+		integrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxInboundEmail caps an inbound email, attachments included.
+const maxInboundEmail = 10 << 20
+
+type MailInHandler struct {
+	mailIn services.MailInService
+}
+
+func NewMailInHandler(mailIn services.MailInService) *MailInHandler {
+	return &MailInHandler{mailIn: mailIn}
+}
+
+// Receive takes an email forwarded by the mail provider
+// @Summary Inbound email
+// @Description Called by the mail provider for each email sent to INBOUND_EMAIL_ADDRESS, with the raw MIME message as the body or as the "email" field of a multipart form. The email becomes an event organized by the user with the sender's address: the subject is the title, the first date and time in the body (or the subject) the start time and the rest of the body the description. The sender is notified once it is created or why it could not be; mail from unknown addresses, or without a DMARC or aligned DKIM pass from the provider (INBOUND_EMAIL_AUTHSERV_ID), is dropped. No authentication; the provider passes INBOUND_EMAIL_TOKEN as the token query parameter.
+// @Tags events
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param token query string true "Inbound email token"
+// @Success 202 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /inbound/email [post]
+func (h *MailInHandler) Receive(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundEmail)
+	var raw []byte
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		raw = []byte(c.PostForm("email"))
+		if len(raw) == 0 {
+			err = errors.New("missing email field")
+		}
+	} else {
+		raw, err = io.ReadAll(c.Request.Body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	if err := h.mailIn.Receive(c, c.Query("token"), raw); err != nil {
+		switch {
+		case errors.Is(err, services.ErrMailInDisabled):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidMailInToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidPayload):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			// A 5xx makes the provider retry the delivery.
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"received": true})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"eventplanner-backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsHandler struct {
+	token func() string
+}
+
+// NewMetricsHandler serves the metrics, to scrapers sending the bearer token
+// returned by token when it is not empty.
+func NewMetricsHandler(token func() string) *MetricsHandler {
+	return &MetricsHandler{token: token}
+}
+
+// Metrics writes the metrics for Prometheus
+// @Summary Get metrics
+// @Description Counters of this server instance in the Prometheus text format. With METRICS_TOKEN set, send it as a bearer token in Authorization.
+// @Tags health
+// @Produce text/plain
+// @Success 200 {string} string
+// @Failure 401 {object} map[string]string
+// @Router /metrics [get]
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	if token := h.token(); token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
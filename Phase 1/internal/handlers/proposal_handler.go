@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProposalHandler struct {
+	proposals services.ProposalService
+}
+
+func NewProposalHandler(proposals services.ProposalService) *ProposalHandler {
+	return &ProposalHandler{proposals: proposals}
+}
+
+// proposalError writes the HTTP response for a proposal service error.
+func proposalError(c *gin.Context, err error) {
+	if lockedError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "proposal not found"})
+	case errors.Is(err, services.ErrNotProposable), errors.Is(err, services.ErrNoChanges),
+		errors.Is(err, services.ErrTitleRequired), errors.Is(err, services.ErrInvalidEventType),
+		errors.Is(err, services.ErrMeetingNotAllowed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrProposalDecided):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "violates foreign key constraint"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "venue not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// proposalParams parses the event and proposal ids of a proposal route.
+func proposalParams(c *gin.Context) (eventID, proposalID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	proposalID, err = strconv.Atoi(c.Param("proposalId"))
+	if err != nil || proposalID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid proposal id"})
+		return 0, 0, false
+	}
+	return eventID, proposalID, true
+}
+
+// Propose suggests a change to an event
+// @Summary Propose an event change
+// @Description Propose changes to an event, with the same fields as PATCH /events/{id}, for an organizer to approve (requires edit_event). The proposal stores what each field changes from and to, and the organizers are notified. requireChangeApproval cannot be proposed.
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.ProposalRequest true "Proposal"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals [post]
+func (h *ProposalHandler) Propose(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.ProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p, err := h.proposals.Propose(c, eventID, userID, req)
+	if err != nil {
+		proposalError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// List returns an event's change proposals
+// @Summary List change proposals
+// @Description The event's change proposals, newest first (requires edit_event)
+// @Tags proposals
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param status query string false "Only proposals with this status" Enums(pending, approved, rejected, withdrawn)
+// @Security ApiKeyAuth
+// @Success 200 {array} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals [get]
+func (h *ProposalHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", models.ProposalPending, models.ProposalApproved, models.ProposalRejected, models.ProposalWithdrawn:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	items, err := h.proposals.List(c, eventID, userID, status)
+	if err != nil {
+		proposalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns one change proposal
+// @Summary Get a change proposal
+// @Description One change proposal with its diff and decision (requires edit_event)
+// @Tags proposals
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param proposalId path int true "Proposal ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals/{proposalId} [get]
+func (h *ProposalHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, proposalID, ok := proposalParams(c)
+	if !ok {
+		return
+	}
+	p, err := h.proposals.Get(c, eventID, proposalID, userID)
+	if err != nil {
+		proposalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Approve applies a change proposal
+// @Summary Approve a change proposal
+// @Description Apply a pending proposal's changes to the event and notify the proposer (organizers only). Changes that no longer apply fail like the equivalent PATCH and leave the proposal pending.
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param proposalId path int true "Proposal ID"
+// @Param request body models.ProposalDecision false "Comment for the proposer"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 423 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals/{proposalId}/approve [post]
+func (h *ProposalHandler) Approve(c *gin.Context) {
+	h.decide(c, h.proposals.Approve)
+}
+
+// Reject turns down a change proposal
+// @Summary Reject a change proposal
+// @Description Reject a pending proposal and notify the proposer (organizers only)
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param proposalId path int true "Proposal ID"
+// @Param request body models.ProposalDecision false "Comment for the proposer"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals/{proposalId}/reject [post]
+func (h *ProposalHandler) Reject(c *gin.Context) {
+	h.decide(c, h.proposals.Reject)
+}
+
+// decide runs Approve or Reject with the optional decision comment.
+func (h *ProposalHandler) decide(c *gin.Context, decide func(ctx context.Context, eventID, proposalID, userID int, comment string) (*models.ChangeProposal, error)) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, proposalID, ok := proposalParams(c)
+	if !ok {
+		return
+	}
+	var req models.ProposalDecision
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p, err := decide(c, eventID, proposalID, userID, req.Comment)
+	if err != nil {
+		proposalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Withdraw takes back a change proposal
+// @Summary Withdraw a change proposal
+// @Description Withdraw a pending proposal (proposer only)
+// @Tags proposals
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param proposalId path int true "Proposal ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.ChangeProposal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/proposals/{proposalId} [delete]
+func (h *ProposalHandler) Withdraw(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, proposalID, ok := proposalParams(c)
+	if !ok {
+		return
+	}
+	p, err := h.proposals.Withdraw(c, eventID, proposalID, userID)
+	if err != nil {
+		proposalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
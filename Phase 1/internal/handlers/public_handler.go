@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type PublicHandler struct {
+	public services.PublicService
+}
+
+func NewPublicHandler(public services.PublicService) *PublicHandler {
+	return &PublicHandler{public: public}
+}
+
+// Event returns the landing page of a published event
+// @Summary Get a public event page
+// @Description The public landing page of a published event: details, agenda, speakers and ticket tiers with remaining capacity. No authentication; participant data is never included.
+// @Tags public
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Success 200 {object} models.PublicEvent
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /public/events/{slug} [get]
+func (h *PublicHandler) Event(c *gin.Context) {
+	page, err := h.public.Event(c, c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
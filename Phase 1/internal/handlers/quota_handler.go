@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuotaHandler struct {
+	quotas services.QuotaService
+}
+
+func NewQuotaHandler(quotas services.QuotaService) *QuotaHandler {
+	return &QuotaHandler{quotas: quotas}
+}
+
+// Usage returns the caller's quotas
+// @Summary Get quota usage
+// @Description The caller's limits of active (not archived) events and invitations per 24 hours, and how much of them is used. A limit of 0 means unlimited; resetsAt says when the next invitation can go out once the daily quota is used up.
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Quotas
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/quotas [get]
+func (h *QuotaHandler) Usage(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	quotas, err := h.quotas.Usage(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quotas)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportHandler struct {
+	reports services.ReportService
+}
+
+func NewReportHandler(reports services.ReportService) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// reportError writes the HTTP response for a report service error.
+func reportError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, services.ErrUnknownUser):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrReportSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyReported):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// reportTargetID parses the id of the reported event or user, or of the report.
+func reportTargetID(c *gin.Context, what string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + what + " id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ReportEvent reports an event as spam or abuse
+// @Summary Report an event
+// @Description Flag a published event, or one the caller takes part in, for admin review. Published events with enough pending reports are hidden from their public page until an admin dismisses the reports.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.ReportRequest true "Reason"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/report [post]
+func (h *ReportHandler) ReportEvent(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, ok := reportTargetID(c, "event")
+	if !ok {
+		return
+	}
+	var req models.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.reports.ReportEvent(c, eventID, userID, req)
+	if err != nil {
+		reportError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// ReportUser reports a user as spam or abuse
+// @Summary Report a user
+// @Description Flag a user for admin review, e.g. for spam invitations
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body models.ReportRequest true "Reason"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/report [post]
+func (h *ReportHandler) ReportUser(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	reportedID, ok := reportTargetID(c, "user")
+	if !ok {
+		return
+	}
+	var req models.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.reports.ReportUser(c, reportedID, userID, req)
+	if err != nil {
+		reportError(c, err, "user not found")
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// Queue lists reports for review
+// @Summary Report review queue
+// @Description Reports with the given status, oldest first (admins only)
+// @Tags reports
+// @Produce json
+// @Param status query string false "pending (default), dismissed or actioned"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/reports [get]
+func (h *ReportHandler) Queue(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var filter models.ReportQueueFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reports, err := h.reports.Queue(c, userID, filter)
+	if err != nil {
+		reportError(c, err, "report not found")
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// Review records the decision on a report
+// @Summary Review a report
+// @Description Dismiss or action a report (admins only). Actioning an event report hides the event from its public page; dismissing shows it again once no report of it was actioned and it is below the report threshold.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Param request body models.ReportReview true "Decision"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/reports/{id} [put]
+func (h *ReportHandler) Review(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	reportID, ok := reportTargetID(c, "report")
+	if !ok {
+		return
+	}
+	var req models.ReportReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.reports.Review(c, reportID, userID, req)
+	if err != nil {
+		reportError(c, err, "report not found")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type RideHandler struct {
+	rides services.RideService
+}
+
+func NewRideHandler(rides services.RideService) *RideHandler {
+	return &RideHandler{rides: rides}
+}
+
+// rideError writes the HTTP response for a ride service error.
+func rideError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "ride not found"})
+	case errors.Is(err, services.ErrOwnRide):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRideFull), errors.Is(err, services.ErrAlreadyRiding), errors.Is(err, services.ErrSeatsBelowTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// rideParams parses the event and ride ids of a ride route.
+func rideParams(c *gin.Context) (eventID, rideID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	rideID, err = strconv.Atoi(c.Param("rideId"))
+	if err != nil || rideID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ride id"})
+		return 0, 0, false
+	}
+	return eventID, rideID, true
+}
+
+// Offer offers seats in the caller's car
+// @Summary Offer a ride
+// @Description Offer seats in the caller's car to the event, with where and when it leaves (participants)
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.RideRequest true "Ride"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Ride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides [post]
+func (h *RideHandler) Offer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.RideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ride, err := h.rides.Offer(c, eventID, userID, req)
+	if err != nil {
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ride)
+}
+
+// List returns an event's rides
+// @Summary List rides
+// @Description The rides offered to the event by departure time, with seats left and passengers (participants)
+// @Tags rides
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Ride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides [get]
+func (h *RideHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	rides, err := h.rides.List(c, eventID, userID)
+	if err != nil {
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rides)
+}
+
+// Update changes a ride
+// @Summary Update a ride
+// @Description Change the caller's ride; seats cannot drop below the passengers already riding, who are notified (driver only)
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param rideId path int true "Ride ID"
+// @Param request body models.RideRequest true "Ride"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Ride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides/{rideId} [put]
+func (h *RideHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, rideID, ok := rideParams(c)
+	if !ok {
+		return
+	}
+	var req models.RideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ride, err := h.rides.Update(c, eventID, rideID, userID, req)
+	if err != nil {
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ride)
+}
+
+// Delete cancels a ride
+// @Summary Cancel a ride
+// @Description Cancel a ride and notify its passengers (the driver, or manage_participants)
+// @Tags rides
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param rideId path int true "Ride ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides/{rideId} [delete]
+func (h *RideHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, rideID, ok := rideParams(c)
+	if !ok {
+		return
+	}
+	if err := h.rides.Delete(c, eventID, rideID, userID); err != nil {
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ride cancelled successfully"})
+}
+
+// Join takes a seat in a ride
+// @Summary Take a seat
+// @Description Take a seat in the ride and notify the driver (participants). A participant rides with one driver per event; 409 when the ride is full or the caller already has a seat.
+// @Tags rides
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param rideId path int true "Ride ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Ride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides/{rideId}/seat [post]
+func (h *RideHandler) Join(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, rideID, ok := rideParams(c)
+	if !ok {
+		return
+	}
+	ride, err := h.rides.Join(c, eventID, rideID, userID)
+	if err != nil {
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ride)
+}
+
+// Leave gives up a seat
+// @Summary Give up a seat
+// @Description Give up the caller's seat in the ride and notify the driver
+// @Tags rides
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param rideId path int true "Ride ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/rides/{rideId}/seat [delete]
+func (h *RideHandler) Leave(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, rideID, ok := rideParams(c)
+	if !ok {
+		return
+	}
+	if err := h.rides.Leave(c, eventID, rideID, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "you have no seat in this ride"})
+			return
+		}
+		rideError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Seat given up successfully"})
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type SavedSearchHandler struct {
+	searches services.SavedSearchService
+}
+
+func NewSavedSearchHandler(searches services.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{searches: searches}
+}
+
+// savedSearchError writes the HTTP response for a saved search service error.
+func savedSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTimeRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSavedSearchExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// savedSearchParams reads the caller and the saved search id, writing the
+// error response when either is missing or invalid.
+func savedSearchParams(c *gin.Context) (userID, id int, ok bool) {
+	userID = c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search id"})
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+// Create saves a named search filter
+// @Summary Save a search
+// @Description Save a named search filter. With alerts on, the caller is notified when newly published events match its query and dates.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body models.SavedSearchRequest true "Search filter"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.SavedSearch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches [post]
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.searches.Create(c, userID, req)
+	if err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// List returns the caller's saved searches
+// @Summary List saved searches
+// @Tags search
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.SavedSearch
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches [get]
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.searches.List(c, userID)
+	if err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns one of the caller's saved searches
+// @Summary Get a saved search
+// @Tags search
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SavedSearch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches/{id} [get]
+func (h *SavedSearchHandler) Get(c *gin.Context) {
+	userID, id, ok := savedSearchParams(c)
+	if !ok {
+		return
+	}
+	s, err := h.searches.Get(c, id, userID)
+	if err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Update replaces a saved search
+// @Summary Update a saved search
+// @Description Replace the filter of a saved search. Turning alerts on only reports events published from then on.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Param request body models.SavedSearchRequest true "Search filter"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SavedSearch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches/{id} [put]
+func (h *SavedSearchHandler) Update(c *gin.Context) {
+	userID, id, ok := savedSearchParams(c)
+	if !ok {
+		return
+	}
+	var req models.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.searches.Update(c, id, userID, req)
+	if err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Delete removes a saved search
+// @Summary Delete a saved search
+// @Tags search
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches/{id} [delete]
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	userID, id, ok := savedSearchParams(c)
+	if !ok {
+		return
+	}
+	if err := h.searches.Delete(c, id, userID); err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// Run executes a saved search
+// @Summary Run a saved search
+// @Description The events and tasks currently matching a saved search
+// @Tags search
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SearchResults
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/saved-searches/{id}/results [get]
+func (h *SavedSearchHandler) Run(c *gin.Context) {
+	userID, id, ok := savedSearchParams(c)
+	if !ok {
+		return
+	}
+	res, err := h.searches.Run(c, id, userID)
+	if err != nil {
+		savedSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
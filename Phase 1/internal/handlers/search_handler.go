@@ -3,30 +3,65 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"eventplanner-backend/internal/fieldset"
+	"eventplanner-backend/internal/geocoding"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/naturaldate"
 	"eventplanner-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Radius limits for nearby search, in kilometres.
+const (
+	defaultSearchRadiusKm = 10
+	maxSearchRadiusKm     = 500
+)
+
+// maxSearchQueryLength caps the search term, in characters. Longer terms only
+// make the substring and similarity matching slower.
+const maxSearchQueryLength = 200
+
 type SearchHandler struct {
 	search services.SearchService
 }
 
-// SearchResponse represents the enhanced search response structure
+// Page sizes for search results, applied to events and tasks separately.
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// SearchResponse is the envelope returned by GET /search
 type SearchResponse struct {
-	Meta      SearchMeta       `json:"meta"`
-	Events    []EventResponse  `json:"events,omitempty"`
-	Tasks     []TaskResponse   `json:"tasks,omitempty"`
+	Meta   SearchMeta      `json:"meta"`
+	Events []EventResponse `json:"events"`
+	Tasks  []TaskResponse  `json:"tasks"`
 }
 
+// SearchMeta echoes the request and describes the returned page
 type SearchMeta struct {
-	Query       string     `json:"query,omitempty"`
-	Role        string     `json:"role,omitempty"`
-	DateRange   DateRange  `json:"dateRange,omitempty"`
-	ResultCount int        `json:"resultCount"`
+	Query      string           `json:"query,omitempty"`
+	Filters    SearchFilters    `json:"filters"`
+	Counts     SearchCounts     `json:"counts"`
+	Pagination SearchPagination `json:"pagination"`
+}
+
+// SearchFilters are the filters applied to the search, after defaults
+type SearchFilters struct {
+	Role      string    `json:"role,omitempty"`
+	DateRange DateRange `json:"dateRange"`
+	TimeZone  string    `json:"tz"`
+	Near      *NearBy   `json:"near,omitempty"`
+	Labels    []string  `json:"labels,omitempty"`
+	Sort      string    `json:"sort"`
+	Order     string    `json:"order"`
 }
 
 type DateRange struct {
@@ -34,15 +69,37 @@ type DateRange struct {
 	To   *time.Time `json:"to,omitempty"`
 }
 
+type NearBy struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+	RadiusKm  float64 `json:"radiusKm"`
+}
+
+// SearchCounts are the total number of matches, before pagination
+type SearchCounts struct {
+	Events int `json:"events"`
+	Tasks  int `json:"tasks"`
+}
+
+// SearchPagination describes the page returned of each result list.
+// HasMore is set when either list has matches after this page.
+type SearchPagination struct {
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
+
 type EventResponse struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description,omitempty"`
-	Location    string     `json:"location,omitempty"`
-	StartTime   time.Time  `json:"startTime"`
-	OrganizerID int        `json:"organizerId"`
-	TimeUntil   string     `json:"timeUntil,omitempty"`
-	IsUpcoming  bool       `json:"isUpcoming"`
+	ID              int       `json:"id"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description,omitempty"`
+	DescriptionHTML string    `json:"descriptionHtml,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	StartTime       time.Time `json:"startTime"`
+	OrganizerID     int       `json:"organizerId"`
+	DistanceKm      *float64  `json:"distanceKm,omitempty"`
+	TimeUntil       string    `json:"timeUntil,omitempty"`
+	IsUpcoming      bool      `json:"isUpcoming"`
 }
 
 type TaskResponse struct {
@@ -51,7 +108,9 @@ type TaskResponse struct {
 	Title       string     `json:"title"`
 	Description string     `json:"description,omitempty"`
 	DueDate     *time.Time `json:"dueDate,omitempty"`
-	Status      string     `json:"status"` // "upcoming", "today", "overdue"
+	AssigneeID  *int       `json:"assigneeId,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	Status      string     `json:"status"` // "upcoming", "today", "overdue", "no-due-date"
 }
 
 func NewSearchHandler(search services.SearchService) *SearchHandler {
@@ -59,18 +118,29 @@ func NewSearchHandler(search services.SearchService) *SearchHandler {
 }
 
 // @Summary Search events and tasks (Public)
-// @Description Public search for events and tasks with filters. Supports special date values: 'today', 'tomorrow', 'nextweek'.
+// @Description Public search for events and tasks with filters. start and end also take dates written in words, resolved in the tz time zone: 'today', 'tomorrow', 'yesterday', weekdays ('friday', 'next monday', 'last sunday'), relative days ('in 2 weeks', '3 days ago'), dates without a year ('march 3'), months ('march 2027'), 'this/next/last week', 'this/next/last month', 'this/next/last year' and 'weekend'. A period such as 'next week' starts at its first day as start and ends with its last day as end.
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param query query string false "Search query (searches in title, description, location)"
+// @Param query query string false "Search query (searches in title, description, location; max 200 characters, matched literally)"
 // @Param q query string false "Legacy parameter, use 'query' instead"
-// @Param start query string false "Start date (format: YYYY-MM-DD or 'today')"
+// @Param start query string false "Start date (format: YYYY-MM-DD or a date in words, e.g. next monday)"
 // @Param from query string false "Legacy parameter, use 'start' instead"
-// @Param end query string false "End date (format: YYYY-MM-DD or 'today')"
+// @Param end query string false "End date, inclusive (format: YYYY-MM-DD or a date in words, e.g. in 2 weeks)"
 // @Param to query string false "Legacy parameter, use 'end' instead"
+// @Param tz query string false "IANA time zone for dates and dates in words (default UTC)"
 // @Param userRole query string false "Filter by role (organizer, attendee, collaborator)"
-// @Success 200 {object} map[string]interface{}
+// @Param lat query number false "Latitude of the search center; requires lng"
+// @Param lng query number false "Longitude of the search center; requires lat"
+// @Param radius query number false "Search radius in km around lat/lng (default 10, max 500)"
+// @Param labels query string false "Comma-separated task label names; only tasks with all of them are returned, events are not filtered"
+// @Param sort query string false "Sort by relevance (requires query), date (default) or created"
+// @Param order query string false "asc or desc (default asc; desc for relevance)"
+// @Param limit query int false "Page size for events and for tasks (default 50, max 200)"
+// @Param offset query int false "Number of events and of tasks to skip (default 0)"
+// @Param fields[events] query string false "Comma-separated event fields to return, e.g. id,title,startTime (default all)"
+// @Param fields[tasks] query string false "Comma-separated task fields to return (default all)"
+// @Success 200 {object} SearchResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -82,55 +152,55 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	// Parse query parameters (support both new and legacy parameter names)
 	q := strings.TrimSpace(c.DefaultQuery("query", c.Query("q")))
 	role := c.DefaultQuery("userRole", c.Query("role"))
-	
-	// Parse date range with support for special values
-	now := time.Now()
-	truncateToDay := func(t time.Time) time.Time {
-		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
-	}
-
-	// Helper function to parse date with special values
-	parseDate := func(dateStr string) (*time.Time, error) {
-		switch strings.ToLower(dateStr) {
-		case "today":
-			t := truncateToDay(now)
-			return &t, nil
-		case "tomorrow":
-			t := truncateToDay(now.Add(24 * time.Hour))
-			return &t, nil
-		case "nextweek":
-			t := truncateToDay(now.Add(7 * 24 * time.Hour))
-			return &t, nil
-		default:
-			t, err := time.Parse("2006-01-02", dateStr)
-			if err != nil {
-				return nil, err
-			}
-			return &t, nil
+	if utf8.RuneCountInString(q) > maxSearchQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("query too long, max %d characters", maxSearchQueryLength)})
+		return
+	}
+
+	// Sparse fieldsets apply to each event and each task; meta is always sent
+	eventFields, ok := parseFields(c, "fields[events]", EventResponse{})
+	if !ok {
+		return
+	}
+	taskFields, ok := parseFields(c, "fields[tasks]", TaskResponse{})
+	if !ok {
+		return
+	}
+
+	// Parse date range, which may be written in words. Day boundaries are
+	// computed in the requested time zone, UTC by default.
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz, use an IANA time zone name"})
+			return
 		}
+		loc = l
 	}
+	now := time.Now().In(loc)
 
 	// Parse start date (from query parameter or legacy 'from' parameter)
 	var fromPtr, toPtr *time.Time
 	if startParam := c.DefaultQuery("start", c.Query("from")); startParam != "" {
-		t, err := parseDate(startParam)
+		from, _, err := naturaldate.Parse(startParam, now)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'start' date format, use YYYY-MM-DD or 'today'"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'start' date format, " + searchDateHint})
 			return
 		}
-		fromPtr = t
+		fromPtr = &from
 	}
 
-	// Parse end date (from query parameter or legacy 'to' parameter)
+	// Parse end date (from query parameter or legacy 'to' parameter); the
+	// range includes the whole last day
 	if endParam := c.DefaultQuery("end", c.Query("to")); endParam != "" {
-		t, err := parseDate(endParam)
+		_, to, err := naturaldate.Parse(endParam, now)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'end' date format, use YYYY-MM-DD or 'today'"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'end' date format, " + searchDateHint})
 			return
 		}
-		// Set to end of day
-		truncated := t.Truncate(24 * time.Hour).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-		toPtr = &truncated
+		last := to.Add(-time.Microsecond)
+		toPtr = &last
 	}
 
 	// Validate role if provided
@@ -139,86 +209,218 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
+	// Validate sorting; relevance ranks matches of the query text
+	sortBy := c.Query("sort")
+	if sortBy != "" && sortBy != models.SearchSortRelevance && sortBy != models.SearchSortDate && sortBy != models.SearchSortCreated {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort, must be 'relevance', 'date' or 'created'"})
+		return
+	}
+	if sortBy == models.SearchSortRelevance && q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort=relevance requires a search query"})
+		return
+	}
+	order := strings.ToLower(c.Query("order"))
+	if order != "" && order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order, must be 'asc' or 'desc'"})
+		return
+	}
+
+	// Parse nearby filter; lat and lng must be given together
+	near, err := parseNear(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Parse pagination; limit and offset apply to events and tasks separately
+	limit, offset := defaultSearchLimit, 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid limit, must be between 1 and %d", maxSearchLimit)})
+			return
+		}
+		limit = n
+	}
+	if raw := c.Query("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset, must be 0 or more"})
+			return
+		}
+		offset = n
+	}
+
+	labels := labelsParam(c)
+
 	// Execute search
-	events, tasks, err := h.search.Search(c, userID, q, fromPtr, toPtr, role)
+	events, tasks, err := h.search.Search(c, userID, models.SearchFilter{
+		Query:  q,
+		From:   fromPtr,
+		To:     toPtr,
+		Role:   role,
+		Near:   near,
+		Sort:   sortBy,
+		Order:  order,
+		Labels: labels,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to perform search"})
 		return
 	}
 
-	// Prepare response with enhanced format
-	var eventResults []map[string]interface{}
-	var taskResults []map[string]interface{}
-
-	// Process events
+	// Build the typed results
+	eventResults := make([]EventResponse, 0, len(events))
 	for _, e := range events {
-		eventData := map[string]interface{}{
-			"id":          e.ID,
-			"title":       e.Title,
-			"description": e.Description,
-			"location":    e.Location,
-			"startTime":   e.StartTime,
-			"organizerId": e.OrganizerID,
-			"isUpcoming":  e.StartTime.After(now),
+		event := EventResponse{
+			ID:              e.ID,
+			Title:           e.Title,
+			Description:     e.Description,
+			DescriptionHTML: e.DescriptionHTML,
+			Location:        e.Location,
+			StartTime:       e.StartTime,
+			OrganizerID:     e.OrganizerID,
+			IsUpcoming:      e.StartTime.After(now),
+		}
+
+		if near != nil && e.Venue != nil && e.Venue.Latitude != nil && e.Venue.Longitude != nil {
+			d := geocoding.DistanceKm(near.Latitude, near.Longitude, *e.Venue.Latitude, *e.Venue.Longitude)
+			event.DistanceKm = &d
 		}
 
 		// Add time until event if it's upcoming
-		if e.StartTime.After(now) {
+		if event.IsUpcoming {
 			duration := e.StartTime.Sub(now)
 			hours := int(duration.Hours())
 			days := hours / 24
 
 			switch {
 			case days > 30:
-				eventData["timeUntil"] = "in more than a month"
+				event.TimeUntil = "in more than a month"
 			case days > 1:
-				eventData["timeUntil"] = "in " + fmt.Sprintf("%d days", days)
+				event.TimeUntil = fmt.Sprintf("in %d days", days)
 			case hours >= 1:
-				eventData["timeUntil"] = "in " + fmt.Sprintf("%d hours", hours)
+				event.TimeUntil = fmt.Sprintf("in %d hours", hours)
 			default:
-				eventData["timeUntil"] = "very soon"
+				event.TimeUntil = "very soon"
 			}
 		}
 
-		eventResults = append(eventResults, eventData)
+		eventResults = append(eventResults, event)
 	}
 
-	// Process tasks
+	// Nearby searches list the closest events first unless a sort was requested
+	if near != nil && sortBy == "" {
+		sort.SliceStable(eventResults, func(i, j int) bool {
+			return *eventResults[i].DistanceKm < *eventResults[j].DistanceKm
+		})
+	}
+
+	today, tomorrow, _ := naturaldate.Parse("today", now)
+	taskResults := make([]TaskResponse, 0, len(tasks))
 	for _, t := range tasks {
-		taskData := map[string]interface{}{
-			"id":          t.ID,
-			"eventId":     t.EventID,
-			"title":       t.Title,
-			"description": t.Description,
-			"dueDate":     t.DueDate,
-			"assigneeId":  t.AssigneeID,
+		task := TaskResponse{
+			ID:          t.ID,
+			EventID:     t.EventID,
+			Title:       t.Title,
+			Description: t.Description,
+			DueDate:     t.DueDate,
+			AssigneeID:  t.AssigneeID,
+		}
+		for _, l := range t.Labels {
+			task.Labels = append(task.Labels, l.Name)
 		}
 
 		// Set task status
-		if t.DueDate != nil {
-			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-			tomorrow := today.Add(24 * time.Hour)
-
-			switch {
-			case t.DueDate.Before(today):
-				taskData["status"] = "overdue"
-			case t.DueDate.Before(tomorrow):
-				taskData["status"] = "today"
-			default:
-				taskData["status"] = "upcoming"
-			}
-		} else {
-			taskData["status"] = "no-due-date"
+		switch {
+		case t.DueDate == nil:
+			task.Status = "no-due-date"
+		case t.DueDate.Before(today):
+			task.Status = "overdue"
+		case t.DueDate.Before(tomorrow):
+			task.Status = "today"
+		default:
+			task.Status = "upcoming"
 		}
 
-		taskResults = append(taskResults, taskData)
+		taskResults = append(taskResults, task)
+	}
+
+	filters := SearchFilters{
+		Role:      role,
+		DateRange: DateRange{From: fromPtr, To: toPtr},
+		TimeZone:  loc.String(),
+		Labels:    labels,
+		Sort:      sortBy,
+		Order:     order,
 	}
+	if filters.Sort == "" {
+		filters.Sort = models.SearchSortDate
+	}
+	if filters.Order == "" {
+		filters.Order = "asc"
+		if filters.Sort == models.SearchSortRelevance {
+			filters.Order = "desc"
+		}
+	}
+	if near != nil {
+		filters.Near = &NearBy{Latitude: near.Latitude, Longitude: near.Longitude, RadiusKm: near.RadiusKm}
+	}
+
+	renderFields(c, SearchResponse{
+		Meta: SearchMeta{
+			Query:   q,
+			Filters: filters,
+			Counts:  SearchCounts{Events: len(eventResults), Tasks: len(taskResults)},
+			Pagination: SearchPagination{
+				Limit:   limit,
+				Offset:  offset,
+				HasMore: offset+limit < len(eventResults) || offset+limit < len(taskResults),
+			},
+		},
+		Events: page(eventResults, offset, limit),
+		Tasks:  page(taskResults, offset, limit),
+	}, fieldset.Selection{"events": eventFields, "tasks": taskFields})
+}
 
-	// Return response in the expected format
-	response := map[string]interface{}{
-		"events": eventResults,
-		"tasks":  taskResults,
+// page returns the items from offset, at most limit of them.
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
 	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+// searchDateHint lists the accepted start and end values for error messages.
+const searchDateHint = "use YYYY-MM-DD or a date such as 'today', 'next monday', 'in 2 weeks', 'march 3' or 'this month'"
 
-	c.JSON(http.StatusOK, response)
+// parseNear reads the lat, lng and radius query parameters. It returns nil
+// when no location was given.
+func parseNear(c *gin.Context) (*models.GeoFilter, error) {
+	latParam, lngParam := c.Query("lat"), c.Query("lng")
+	if latParam == "" && lngParam == "" {
+		if c.Query("radius") != "" {
+			return nil, fmt.Errorf("'radius' requires 'lat' and 'lng'")
+		}
+		return nil, nil
+	}
+	if latParam == "" || lngParam == "" {
+		return nil, fmt.Errorf("'lat' and 'lng' must be provided together")
+	}
+	lat, err := strconv.ParseFloat(latParam, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid 'lat', must be a number between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(lngParam, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("invalid 'lng', must be a number between -180 and 180")
+	}
+	radius := float64(defaultSearchRadiusKm)
+	if radiusParam := c.Query("radius"); radiusParam != "" {
+		radius, err = strconv.ParseFloat(radiusParam, 64)
+		if err != nil || radius <= 0 || radius > maxSearchRadiusKm {
+			return nil, fmt.Errorf("invalid 'radius', must be a number of km between 0 and %d", maxSearchRadiusKm)
+		}
+	}
+	return &models.GeoFilter{Latitude: lat, Longitude: lng, RadiusKm: radius}, nil
 }
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type SeriesHandler struct {
+	series services.SeriesService
+}
+
+func NewSeriesHandler(series services.SeriesService) *SeriesHandler {
+	return &SeriesHandler{series: series}
+}
+
+// seriesError writes the HTTP response for a series service error.
+func seriesError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, services.ErrNameRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// seriesID parses the id of a series route.
+func seriesID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// Create starts a series
+// @Summary Create a series
+// @Description Start a named series of events owned by the caller. defaultRole (attendee or collaborator) and inviteExpiryDays apply to the invitations subscribers get for new occurrences
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param request body models.SeriesRequest true "Series"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series [post]
+func (h *SeriesHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	series, err := h.series.Create(c, userID, req)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusCreated, series)
+}
+
+// List returns the caller's series
+// @Summary List my series
+// @Description The series the caller owns or subscribes to, by name
+// @Tags series
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Series
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series [get]
+func (h *SeriesHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	series, err := h.series.List(c, userID)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Get returns a series with its occurrences
+// @Summary Get a series
+// @Description A series with all its occurrences by start time, for its owner, subscribers and the participants of any occurrence
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id} [get]
+func (h *SeriesHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	series, err := h.series.Get(c, id, userID)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Update changes a series
+// @Summary Update a series
+// @Description Replace the name, description and invitation defaults of a series (owner only)
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body models.SeriesRequest true "Series"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id} [put]
+func (h *SeriesHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	var req models.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	series, err := h.series.Update(c, id, userID, req)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Delete removes a series
+// @Summary Delete a series
+// @Description Delete a series; its events are kept (owner only)
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	if err := h.series.Delete(c, id, userID); err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Series deleted successfully"})
+}
+
+// Subscribe subscribes the caller to a series
+// @Summary Subscribe to a series
+// @Description Get invited to every occurrence linked to the series from now on. Open to the series' members, such as participants of an earlier occurrence
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id}/subscription [put]
+func (h *SeriesHandler) Subscribe(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	series, err := h.series.Subscribe(c, id, userID)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Unsubscribe ends the caller's subscription
+// @Summary Unsubscribe from a series
+// @Description Stop getting invited to new occurrences; existing invitations are kept
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id}/subscription [delete]
+func (h *SeriesHandler) Unsubscribe(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	if err := h.series.Unsubscribe(c, id, userID); err != nil {
+		seriesError(c, err, "subscription not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
+}
+
+// Subscribers lists a series' subscribers
+// @Summary List subscribers
+// @Description Who subscribes to the series, in the order they subscribed (owner only)
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.SeriesSubscriber
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /series/{id}/subscribers [get]
+func (h *SeriesHandler) Subscribers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := seriesID(c)
+	if !ok {
+		return
+	}
+	subscribers, err := h.series.Subscribers(c, id, userID)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, subscribers)
+}
+
+// Link adds an event to a series
+// @Summary Add an event to a series
+// @Description Make the event an occurrence of the series, moving it out of any other, and invite the series' subscribers with its default role. Requires edit_event on the event, ownership of the series and every permission of the default role
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.SeriesLinkRequest true "Series"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Series
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/series [put]
+func (h *SeriesHandler) Link(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.SeriesLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	series, err := h.series.Link(c, eventID, userID, req.SeriesID)
+	if err != nil {
+		seriesError(c, err, "series not found")
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// Unlink takes an event out of its series
+// @Summary Remove an event from its series
+// @Description Take the event out of its series; its participants stay invited (requires edit_event)
+// @Tags series
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/series [delete]
+func (h *SeriesHandler) Unlink(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	if err := h.series.Unlink(c, eventID, userID); err != nil {
+		seriesError(c, err, "event is not in a series")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event removed from its series"})
+}
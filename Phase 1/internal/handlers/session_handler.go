@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionHandler struct {
+	sessions services.SessionService
+}
+
+func NewSessionHandler(sessions services.SessionService) *SessionHandler {
+	return &SessionHandler{sessions: sessions}
+}
+
+// sessionError writes the HTTP response for a session service error.
+func sessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+	case errors.Is(err, services.ErrInvalidTimeRange), errors.Is(err, services.ErrSessionOutOfRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCapacityTooLow), errors.Is(err, services.ErrSessionFull), errors.Is(err, services.ErrAlreadyInAgenda):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// sessionParams parses the event and session ids of a session route.
+func sessionParams(c *gin.Context) (eventID, sessionID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	sessionID, err = strconv.Atoi(c.Param("sessionId"))
+	if err != nil || sessionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return 0, 0, false
+	}
+	return eventID, sessionID, true
+}
+
+// Create adds a session to an event's agenda
+// @Summary Create a session
+// @Description Add a session (talk, workshop, day of a multi-day event) with its own time, room, speakers and optional capacity. It must fall within the event's time (requires edit_event).
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.EventSessionRequest true "Session"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.EventSession
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/sessions [post]
+func (h *SessionHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.EventSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.sessions.Create(c, eventID, userID, req)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// List returns an event's agenda
+// @Summary List sessions
+// @Description The event's sessions in chronological order, with attendee counts and whether the caller attends each (any participant)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventSession
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.sessions.List(c, eventID, userID)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Update changes a session
+// @Summary Update a session
+// @Description Change a session (requires edit_event). The capacity cannot drop below the number of attendees.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param sessionId path int true "Session ID"
+// @Param request body models.EventSessionRequest true "Session"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EventSession
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/sessions/{sessionId} [put]
+func (h *SessionHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, sessionID, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+	var req models.EventSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.sessions.Update(c, eventID, sessionID, userID, req)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Delete removes a session
+// @Summary Delete a session
+// @Description Remove a session from the agenda, and from every personal agenda (requires edit_event)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param sessionId path int true "Session ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/sessions/{sessionId} [delete]
+func (h *SessionHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, sessionID, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+	if err := h.sessions.Delete(c, eventID, sessionID, userID); err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
+}
+
+// Agenda returns the caller's personal agenda
+// @Summary My agenda
+// @Description The sessions of the event the caller added to their personal agenda, in chronological order
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventSession
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/agenda [get]
+func (h *SessionHandler) Agenda(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.sessions.Agenda(c, eventID, userID)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Attend adds a session to the caller's agenda
+// @Summary Add a session to my agenda
+// @Description Add the session to the caller's personal agenda, taking one of its seats if it has a capacity (participants)
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param sessionId path int true "Session ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/agenda/{sessionId} [put]
+func (h *SessionHandler) Attend(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, sessionID, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+	if err := h.sessions.Attend(c, eventID, sessionID, userID); err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session added to your agenda"})
+}
+
+// Leave removes a session from the caller's agenda
+// @Summary Remove a session from my agenda
+// @Description Remove the session from the caller's personal agenda, freeing its seat
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param sessionId path int true "Session ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/agenda/{sessionId} [delete]
+func (h *SessionHandler) Leave(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, sessionID, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+	if err := h.sessions.Leave(c, eventID, sessionID, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session is not in your agenda"})
+			return
+		}
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session removed from your agenda"})
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type SpeakerHandler struct {
+	speakers services.SpeakerService
+}
+
+func NewSpeakerHandler(speakers services.SpeakerService) *SpeakerHandler {
+	return &SpeakerHandler{speakers: speakers}
+}
+
+// speakerError writes the HTTP response for a speaker service error; notFound
+// is the message for pgx.ErrNoRows.
+func speakerError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownSpeaker):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Create stores a reusable speaker profile
+// @Summary Create a speaker
+// @Description Create a speaker or host profile that can be attached to events and sessions
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param request body models.SpeakerRequest true "Speaker profile"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /speakers [post]
+func (h *SpeakerHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.SpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.speakers.Create(c, userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// List returns the speaker profiles created by the caller
+// @Summary List my speakers
+// @Tags speakers
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Speaker
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /speakers [get]
+func (h *SpeakerHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.speakers.ListMine(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns a single speaker profile
+// @Summary Get a speaker
+// @Tags speakers
+// @Produce json
+// @Param id path int true "Speaker ID"
+// @Success 200 {object} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /speakers/{id} [get]
+func (h *SpeakerHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid speaker id"})
+		return
+	}
+	s, err := h.speakers.Get(c, id)
+	if err != nil {
+		speakerError(c, err, "speaker not found")
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Update changes a speaker profile
+// @Summary Update a speaker
+// @Description Change a speaker profile; only its creator may
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path int true "Speaker ID"
+// @Param request body models.SpeakerRequest true "Speaker profile"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /speakers/{id} [put]
+func (h *SpeakerHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid speaker id"})
+		return
+	}
+	var req models.SpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.speakers.Update(c, id, userID, req)
+	if err != nil {
+		speakerError(c, err, "speaker not found")
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Delete removes a speaker profile
+// @Summary Delete a speaker
+// @Description Delete a speaker profile and detach it from every event and session; only its creator may
+// @Tags speakers
+// @Produce json
+// @Param id path int true "Speaker ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /speakers/{id} [delete]
+func (h *SpeakerHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid speaker id"})
+		return
+	}
+	if err := h.speakers.Delete(c, id, userID); err != nil {
+		speakerError(c, err, "speaker not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Speaker deleted successfully"})
+}
+
+// ListForEvent returns an event's speakers
+// @Summary List event speakers
+// @Description The event's speakers in display order (any participant)
+// @Tags speakers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/speakers [get]
+func (h *SpeakerHandler) ListForEvent(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.speakers.ListForEvent(c, eventID, userID)
+	if err != nil {
+		speakerError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// SetEventSpeakers sets an event's speakers
+// @Summary Set event speakers
+// @Description Replace the event's speakers; the order of speakerIds is the display order (requires edit_event)
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.SpeakerOrderRequest true "Ordered speaker IDs"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Speaker
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/speakers [put]
+func (h *SpeakerHandler) SetEventSpeakers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.SpeakerOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.speakers.SetEventSpeakers(c, eventID, userID, req.SpeakerIDs)
+	if err != nil {
+		speakerError(c, err, "event not found")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// SetSessionSpeakers sets a session's speakers
+// @Summary Set session speakers
+// @Description Replace the session's speaker profiles; the order of speakerIds is the display order (requires edit_event)
+// @Tags speakers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param sessionId path int true "Session ID"
+// @Param request body models.SpeakerOrderRequest true "Ordered speaker IDs"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/sessions/{sessionId}/speakers [put]
+func (h *SpeakerHandler) SetSessionSpeakers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, sessionID, ok := sessionParams(c)
+	if !ok {
+		return
+	}
+	var req models.SpeakerOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.speakers.SetSessionSpeakers(c, eventID, sessionID, userID, req.SpeakerIDs); err != nil {
+		speakerError(c, err, "session not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session speakers updated successfully"})
+}
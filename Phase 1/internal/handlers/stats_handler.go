@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	stats services.StatsService
+}
+
+func NewStatsHandler(stats services.StatsService) *StatsHandler {
+	return &StatsHandler{stats: stats}
+}
+
+// Me returns the caller's participation stats
+// @Summary Get participation stats
+// @Description How the caller answered invitations to events they did not organize, how often they were checked in at ended events they said they were going to (only events that checked people in count), how many events they organize and how many of their assigned tasks they completed. Rates are between 0 and 1, null when there is nothing to rate.
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.UserStats
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/stats [get]
+func (h *StatsHandler) Me(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	stats, err := h.stats.UserStats(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// Reliability rates how reliably an event's invitees show up
+// @Summary Get invitee reliability
+// @Description For each participant but the organizer, by name: how many other ended events that checked people in they said they were going to, at how many of them they were checked in, and the resulting level: high (80% or more), medium (50% or more), low, or unknown below 3 such events (requires manage_participants)
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Reliability
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/attendees/reliability [get]
+func (h *StatsHandler) Reliability(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.stats.Reliability(c, eventID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Report renders the post-event report as a PDF
+// @Summary Download the event report
+// @Description A post-event summary for sponsors or management (requires edit_event): attendance (RSVPs against check-ins), budget against actual spending (the vendors' contracted prices against what was paid, per currency), ticket sales, task completion and feedback scores
+// @Tags events
+// @Produce application/pdf
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/report.pdf [get]
+func (h *StatsHandler) Report(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	doc, err := h.stats.EventReport(c, eventID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-report.pdf"`, eventID))
+	c.Data(http.StatusOK, "application/pdf", doc)
+}
+
+// Funnel returns an event's RSVP funnel
+// @Summary Get the RSVP funnel
+// @Description How many invitees (everyone but the organizer) were invited, viewed the event, answered the invitation and were checked in, the share of each stage that reached the next, and the same counts at the end of each day or week from the first invitation to the last change, at most 366 points (requires manage_participants). Answering counts as viewing for invitees who answered without opening the event.
+// @Tags events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param interval query string false "Series interval, day by default" Enums(day, week)
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Funnel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/funnel [get]
+func (h *StatsHandler) Funnel(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	funnel, err := h.stats.Funnel(c, eventID, userID, c.Query("interval"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidInterval):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, funnel)
+}
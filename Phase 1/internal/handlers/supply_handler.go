@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type SupplyHandler struct {
+	supplies services.SupplyService
+}
+
+func NewSupplyHandler(supplies services.SupplyService) *SupplyHandler {
+	return &SupplyHandler{supplies: supplies}
+}
+
+// supplyError writes the HTTP response for a supply service error.
+func supplyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrNotClaimer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "supply not found"})
+	case errors.Is(err, services.ErrSupplyClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// supplyParams parses the event and supply ids of a supply route.
+func supplyParams(c *gin.Context) (eventID, supplyID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	supplyID, err = strconv.Atoi(c.Param("supplyId"))
+	if err != nil || supplyID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid supply id"})
+		return 0, 0, false
+	}
+	return eventID, supplyID, true
+}
+
+// Create adds an item to an event's supplies checklist
+// @Summary Add a supply
+// @Description Add an item (e.g. drinks, chairs, a dish for a potluck) to the event's supplies checklist. quantity defaults to 1 (requires manage_tasks).
+// @Tags supplies
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.SupplyRequest true "Item"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies [post]
+func (h *SupplyHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.SupplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := h.supplies.Create(c, eventID, userID, req)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// List returns an event's supplies checklist
+// @Summary List supplies
+// @Description The event's supplies checklist, unclaimed items first, with who claimed each item (any participant)
+// @Tags supplies
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies [get]
+func (h *SupplyHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.supplies.List(c, eventID, userID)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Update changes a supply
+// @Summary Update a supply
+// @Description Change an item's name, quantity and notes; its claim is kept (requires manage_tasks)
+// @Tags supplies
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param supplyId path int true "Supply ID"
+// @Param request body models.SupplyRequest true "Item"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies/{supplyId} [put]
+func (h *SupplyHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, supplyID, ok := supplyParams(c)
+	if !ok {
+		return
+	}
+	var req models.SupplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := h.supplies.Update(c, eventID, supplyID, userID, req)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// Delete removes a supply
+// @Summary Delete a supply
+// @Description Remove an item from the checklist (requires manage_tasks)
+// @Tags supplies
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param supplyId path int true "Supply ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies/{supplyId} [delete]
+func (h *SupplyHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, supplyID, ok := supplyParams(c)
+	if !ok {
+		return
+	}
+	if err := h.supplies.Delete(c, eventID, supplyID, userID); err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Supply deleted successfully"})
+}
+
+// Claim signs the caller up to bring an item
+// @Summary Claim a supply
+// @Description Sign up to bring the item (any participant). Each item has one claimer; 409 when someone else claimed it first.
+// @Tags supplies
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param supplyId path int true "Supply ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies/{supplyId}/claim [post]
+func (h *SupplyHandler) Claim(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, supplyID, ok := supplyParams(c)
+	if !ok {
+		return
+	}
+	item, err := h.supplies.Claim(c, eventID, supplyID, userID)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// Unclaim releases a claim on an item
+// @Summary Release a supply
+// @Description Free the item again and clear its purchased flag (its claimer, or manage_tasks)
+// @Tags supplies
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param supplyId path int true "Supply ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies/{supplyId}/claim [delete]
+func (h *SupplyHandler) Unclaim(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, supplyID, ok := supplyParams(c)
+	if !ok {
+		return
+	}
+	item, err := h.supplies.Unclaim(c, eventID, supplyID, userID)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// SetPurchased marks an item bought
+// @Summary Mark a supply purchased
+// @Description Mark the item as bought, or not (its claimer, or manage_tasks)
+// @Tags supplies
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param supplyId path int true "Supply ID"
+// @Param request body models.PurchasedRequest true "Purchased"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Supply
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/supplies/{supplyId}/purchased [put]
+func (h *SupplyHandler) SetPurchased(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, supplyID, ok := supplyParams(c)
+	if !ok {
+		return
+	}
+	var req models.PurchasedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := h.supplies.SetPurchased(c, eventID, supplyID, userID, *req.Purchased)
+	if err != nil {
+		supplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskLabelHandler struct {
+	labels services.TaskLabelService
+}
+
+func NewTaskLabelHandler(labels services.TaskLabelService) *TaskLabelHandler {
+	return &TaskLabelHandler{labels: labels}
+}
+
+// taskLabelError writes the HTTP response for a task label service error.
+func taskLabelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidLabelName), errors.Is(err, services.ErrInvalidLabelColor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLabelNotFound), errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLabelExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// labelPathIDs reads the event ID and the labelId path parameter, writing a
+// 400 response when either is invalid.
+func labelPathIDs(c *gin.Context) (int, int, bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	labelID, err := strconv.Atoi(c.Param("labelId"))
+	if err != nil || labelID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid label id"})
+		return 0, 0, false
+	}
+	return eventID, labelID, true
+}
+
+// labelsParam reads the comma-separated label names of the labels query
+// parameter.
+func labelsParam(c *gin.Context) []string {
+	var labels []string
+	for _, name := range strings.Split(c.Query("labels"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			labels = append(labels, name)
+		}
+	}
+	return labels
+}
+
+// List returns an event's task labels
+// @Summary List task labels
+// @Description The event's task labels by name, each with its number of tasks (any participant).
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.TaskLabel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/labels [get]
+func (h *TaskLabelHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	labels, err := h.labels.List(c, eventID, userID)
+	if err != nil {
+		taskLabelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+// Create adds a task label to an event
+// @Summary Create a task label
+// @Description Add a label of up to 40 characters without commas, unique in the event regardless of case, with a hex color such as #e53935 (requires manage_tasks).
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.TaskLabelRequest true "Label"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TaskLabel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/labels [post]
+func (h *TaskLabelHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.TaskLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	label, err := h.labels.Create(c, eventID, userID, req)
+	if err != nil {
+		taskLabelError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, label)
+}
+
+// Update renames or recolors a task label
+// @Summary Update a task label
+// @Description Change a label's name and/or color (requires manage_tasks). Tasks keep the label under its new name.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param labelId path int true "Label ID"
+// @Param request body models.TaskLabelPatch true "Fields to change"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TaskLabel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/labels/{labelId} [patch]
+func (h *TaskLabelHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, labelID, ok := labelPathIDs(c)
+	if !ok {
+		return
+	}
+	var patch models.TaskLabelPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	label, err := h.labels.Update(c, eventID, labelID, userID, patch)
+	if err != nil {
+		taskLabelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, label)
+}
+
+// Delete removes a task label
+// @Summary Delete a task label
+// @Description Remove the label from the event and from all its tasks (requires manage_tasks).
+// @Tags tasks
+// @Param id path int true "Event ID"
+// @Param labelId path int true "Label ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/labels/{labelId} [delete]
+func (h *TaskLabelHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, labelID, ok := labelPathIDs(c)
+	if !ok {
+		return
+	}
+	if err := h.labels.Delete(c, eventID, labelID, userID); err != nil {
+		taskLabelError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetTaskLabels replaces a task's labels
+// @Summary Set a task's labels
+// @Description Replace the task's labels with up to 20 named ones, matched regardless of case (requires manage_tasks). Names the event has no label for yet become new labels in gray (#9e9e9e); an empty list removes all labels. Returns the task's labels.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.TaskLabelsRequest true "Label names"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.TaskLabel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/labels [put]
+func (h *TaskLabelHandler) SetTaskLabels(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil || taskID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return
+	}
+	var req models.TaskLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	labels, err := h.labels.SetTaskLabels(c, eventID, taskID, userID, req)
+	if err != nil {
+		taskLabelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type TaskTemplateHandler struct {
+	templates services.TaskTemplateService
+}
+
+func NewTaskTemplateHandler(templates services.TaskTemplateService) *TaskTemplateHandler {
+	return &TaskTemplateHandler{templates: templates}
+}
+
+// taskTemplateError writes the HTTP response for a task template service error.
+func taskTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidDueOffset), errors.Is(err, services.ErrTaskTitleRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTemplateExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "task template not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// taskTemplateParams reads the caller and the template id, writing the error
+// response when either is missing or invalid.
+func taskTemplateParams(c *gin.Context) (userID, id int, ok bool) {
+	userID = c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task template id"})
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+// Create saves a reusable task checklist
+// @Summary Create a task template
+// @Description Save a reusable task checklist. Each task's dueOffset ("-14d", "-1d12h", "2h") is relative to the start of the event the template is applied to.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.TaskTemplateRequest true "Task template"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TaskTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/task-templates [post]
+func (h *TaskTemplateHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.TaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.templates.Create(c, userID, req)
+	if err != nil {
+		taskTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// List returns the caller's task templates
+// @Summary List task templates
+// @Tags tasks
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.TaskTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/task-templates [get]
+func (h *TaskTemplateHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.templates.List(c, userID)
+	if err != nil {
+		taskTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns one of the caller's task templates
+// @Summary Get a task template
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task template ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TaskTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/task-templates/{id} [get]
+func (h *TaskTemplateHandler) Get(c *gin.Context) {
+	userID, id, ok := taskTemplateParams(c)
+	if !ok {
+		return
+	}
+	t, err := h.templates.Get(c, id, userID)
+	if err != nil {
+		taskTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Update replaces a task template
+// @Summary Update a task template
+// @Description Replace a task template. Events it was already applied to keep their tasks.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task template ID"
+// @Param request body models.TaskTemplateRequest true "Task template"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TaskTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/task-templates/{id} [put]
+func (h *TaskTemplateHandler) Update(c *gin.Context) {
+	userID, id, ok := taskTemplateParams(c)
+	if !ok {
+		return
+	}
+	var req models.TaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.templates.Update(c, id, userID, req)
+	if err != nil {
+		taskTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Delete removes a task template
+// @Summary Delete a task template
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task template ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/task-templates/{id} [delete]
+func (h *TaskTemplateHandler) Delete(c *gin.Context) {
+	userID, id, ok := taskTemplateParams(c)
+	if !ok {
+		return
+	}
+	if err := h.templates.Delete(c, id, userID); err != nil {
+		taskTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Task template deleted successfully"})
+}
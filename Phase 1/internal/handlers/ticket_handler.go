@@ -0,0 +1,872 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/payments"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type TicketHandler struct {
+	tickets services.TicketService
+}
+
+func NewTicketHandler(tickets services.TicketService) *TicketHandler {
+	return &TicketHandler{tickets: tickets}
+}
+
+// ticketErrorStatus maps ticketing errors to HTTP statuses.
+func ticketErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrForbidden),
+		errors.Is(err, services.ErrTransfersDisabled),
+		errors.Is(err, services.ErrPaymentsDisabled):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, pgx.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrTierExists),
+		errors.Is(err, services.ErrQuantityBelowSold),
+		errors.Is(err, services.ErrSoldOut),
+		errors.Is(err, services.ErrAlreadyHasTicket),
+		errors.Is(err, services.ErrRefundRequired),
+		errors.Is(err, services.ErrNotRefundable),
+		errors.Is(err, services.ErrRefundClosed),
+		errors.Is(err, services.ErrPromoCodeExists),
+		errors.Is(err, services.ErrPromoCodeUsedUp),
+		errors.Is(err, services.ErrAlreadyParticipant),
+		errors.Is(err, services.ErrTicketPending):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidDiscount),
+		errors.Is(err, services.ErrPromoCodeInvalid),
+		errors.Is(err, services.ErrPromoCodeExpired),
+		errors.Is(err, services.ErrOrganizerTransfer),
+		errors.Is(err, services.ErrTransferToSelf),
+		errors.Is(err, services.ErrDuplicateRefundDay):
+		return http.StatusBadRequest
+	case errors.Is(err, payments.ErrNotConfigured):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrPaymentFailed):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// CreateTier adds a ticket tier to an event
+// @Summary Create a ticket tier
+// @Description Add a ticket tier with its own price and capacity (requires edit_event)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.TicketTierRequest true "Tier"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TicketTier
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tiers [post]
+func (h *TicketHandler) CreateTier(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.TicketTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tier, err := h.tickets.CreateTier(c, eventID, userID, req)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, tier)
+}
+
+// UpdateTier changes a ticket tier
+// @Summary Update a ticket tier
+// @Description Change a tier's name, price or quantity (requires edit_event). The quantity cannot drop below the tickets already claimed.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tierId path int true "Tier ID"
+// @Param request body models.TicketTierRequest true "Tier"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TicketTier
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tiers/{tierId} [put]
+func (h *TicketHandler) UpdateTier(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	tierID, err := strconv.Atoi(c.Param("tierId"))
+	if err != nil || tierID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tier id"})
+		return
+	}
+	var req models.TicketTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tier, err := h.tickets.UpdateTier(c, eventID, tierID, userID, req)
+	if err != nil {
+		status := ticketErrorStatus(err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("ticket tier not found")
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tier)
+}
+
+// ListTiers lists an event's ticket tiers
+// @Summary List ticket tiers
+// @Description Ticket tiers of the event with remaining capacity (any participant)
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.TicketTier
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tiers [get]
+func (h *TicketHandler) ListTiers(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	tiers, err := h.tickets.ListTiers(c, eventID, userID)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tiers)
+}
+
+// SalesSummary reports an event's ticket sales
+// @Summary Ticket sales summary
+// @Description Ticket sales per currency, and totalled in one currency with the exchange rates used (requires edit_event). The report currency is the currency query param, else the organizer's preferred currency, else the only currency sold in, else USD. When rates are unavailable, total is null and conversionError explains why.
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param currency query string false "Report currency (ISO 4217 code)"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SalesSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tickets/summary [get]
+func (h *TicketHandler) SalesSummary(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	currency := c.Query("currency")
+	if currency != "" && !validCurrency(currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency, use a three-letter ISO 4217 code"})
+		return
+	}
+	summary, err := h.tickets.SalesSummary(c, eventID, userID, currency)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// validCurrency reports whether code looks like an ISO 4217 code.
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Receipt downloads a paid ticket's receipt
+// @Summary Download a receipt
+// @Description The receipt of a paid ticket as an HTML document, for its buyer or anyone with edit_event on the event. format=json returns the receipt data instead.
+// @Tags tickets
+// @Produce html
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param format query string false "html (default) or json"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Receipt
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tickets/{id}/receipt [get]
+func (h *TicketHandler) Receipt(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || ticketID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket id"})
+		return
+	}
+	format := c.DefaultQuery("format", "html")
+	if format != "html" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html or json"})
+		return
+	}
+	receipt, err := h.tickets.Receipt(c, ticketID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "receipt not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, receipt)
+		return
+	}
+	doc, err := services.RenderReceipt(receipt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+services.ReceiptFilename(receipt)+`"`)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", doc)
+}
+
+// Claim issues the caller a ticket
+// @Summary Claim a ticket
+// @Description Claim a ticket in a tier (participants only, one active ticket per event), optionally with a promo code. Tickets that cost nothing after discounts are claimed at once and mark the caller as going. Others are pending, holding the seat, until the payment at checkoutUrl is confirmed.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tierId path int true "Tier ID"
+// @Param request body models.ClaimRequest false "Promo code"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Ticket
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /events/{id}/tiers/{tierId}/claim [post]
+func (h *TicketHandler) Claim(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	tierID, err := strconv.Atoi(c.Param("tierId"))
+	if err != nil || tierID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tier id"})
+		return
+	}
+	// The body is optional; it only carries a promo code
+	var req models.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.tickets.Claim(c, eventID, tierID, userID, req.PromoCode)
+	if err != nil {
+		status := ticketErrorStatus(err)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			err = errors.New("ticket tier not found")
+		case errors.Is(err, payments.ErrNotConfigured):
+			err = errors.New("payments are not configured")
+		case errors.Is(err, services.ErrPaymentFailed):
+			err = services.ErrPaymentFailed
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// ListMine returns the caller's tickets
+// @Summary List my tickets
+// @Tags tickets
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Ticket
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tickets [get]
+func (h *TicketHandler) ListMine(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.tickets.ListMine(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Cancel releases one of the caller's tickets
+// @Summary Cancel a ticket
+// @Description Cancel a ticket and return its seat to the tier. Paid tickets are refunded as far as the event's refund policy allows; without a policy, or once the last refund deadline has passed, the request fails with 409.
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /tickets/{id} [delete]
+func (h *TicketHandler) Cancel(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || ticketID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket id"})
+		return
+	}
+	if err := h.tickets.Cancel(c, ticketID, userID); err != nil {
+		refundError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket cancelled successfully"})
+}
+
+// Refund refunds a paid ticket
+// @Summary Refund a ticket
+// @Description Refund a paid ticket through the payment provider and return its seat to the tier. Callers with edit_event on the ticket's event refund the full amount; the ticket holder gets what the event's refund policy allows (see GET /tickets/{id}/refund-quote).
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /tickets/{id}/refund [post]
+func (h *TicketHandler) Refund(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || ticketID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket id"})
+		return
+	}
+	if err := h.tickets.Refund(c, ticketID, userID); err != nil {
+		refundError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket refunded successfully"})
+}
+
+// refundError writes the response for a failed cancellation or refund.
+func refundError(c *gin.Context, err error) {
+	status := ticketErrorStatus(err)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = errors.New("ticket not found")
+	case errors.Is(err, payments.ErrNotConfigured):
+		err = errors.New("payments are not configured")
+	case errors.Is(err, services.ErrPaymentFailed):
+		err = services.ErrPaymentFailed
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// RefundQuote previews a refund
+// @Summary Preview a refund
+// @Description What cancelling one of the caller's paid tickets now would refund under the event's refund policy, and until when that applies
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RefundQuote
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tickets/{id}/refund-quote [get]
+func (h *TicketHandler) RefundQuote(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || ticketID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket id"})
+		return
+	}
+	quote, err := h.tickets.RefundQuote(c, ticketID, userID)
+	if err != nil {
+		refundError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// SetRefundPolicy replaces an event's refund rules
+// @Summary Set the refund policy
+// @Description Replace the event's refund rules (requires edit_event). A ticket cancelled at least daysBefore days before the event starts gets percent of its price back; the rule with the most days that has not passed applies. An empty list removes the policy, leaving refunds to organizers.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.RefundPolicyRequest true "Rules"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RefundPolicy
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/refund-policy [put]
+func (h *TicketHandler) SetRefundPolicy(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.RefundPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	policy, err := h.tickets.SetRefundPolicy(c, eventID, userID, req.Rules)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// RefundPolicy shows an event's refund rules
+// @Summary Get the refund policy
+// @Description The event's refund rules, most days before the start first (any participant). An empty list means refunds are left to organizers.
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RefundPolicy
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/refund-policy [get]
+func (h *TicketHandler) RefundPolicy(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	policy, err := h.tickets.RefundPolicy(c, eventID, userID)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// maxPaymentWebhook caps the body of a payment provider event.
+const maxPaymentWebhook = 1 << 20
+
+// PaymentWebhook receives payment provider events
+// @Summary Payment provider webhook
+// @Description Receives signed events from the payment provider (Stripe: checkout.session.completed, checkout.session.async_payment_succeeded, checkout.session.async_payment_failed, checkout.session.expired, charge.refunded) and updates the tickets they concern. No authentication; requests are verified by signature.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /payments/webhook [post]
+func (h *TicketHandler) PaymentWebhook(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPaymentWebhook)
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	if err := h.tickets.HandleWebhook(c, payload, c.Request.Header); err != nil {
+		switch {
+		case errors.Is(err, payments.ErrInvalidSignature):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, payments.ErrNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "payments are not configured"})
+		default:
+			// A 5xx makes the provider retry the delivery.
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// promoParams parses the event and promo code ids of a promo code route.
+func promoParams(c *gin.Context) (eventID, promoCodeID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	promoCodeID, err = strconv.Atoi(c.Param("codeId"))
+	if err != nil || promoCodeID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid promo code id"})
+		return 0, 0, false
+	}
+	return eventID, promoCodeID, true
+}
+
+func promoError(c *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "promo code not found"})
+		return
+	}
+	c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+// CreatePromoCode adds a discount code to an event
+// @Summary Create a promo code
+// @Description Add a percentage or fixed discount code, with optional usage limit and expiry (requires edit_event). Codes are case-insensitive.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.PromoCodeRequest true "Promo code"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/promo-codes [post]
+func (h *TicketHandler) CreatePromoCode(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p, err := h.tickets.CreatePromoCode(c, eventID, userID, req)
+	if err != nil {
+		promoError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// ListPromoCodes lists an event's discount codes
+// @Summary List promo codes
+// @Description Promo codes of the event with their current uses (requires edit_event)
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/promo-codes [get]
+func (h *TicketHandler) ListPromoCodes(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	codes, err := h.tickets.ListPromoCodes(c, eventID, userID)
+	if err != nil {
+		promoError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// UpdatePromoCode changes a discount code
+// @Summary Update a promo code
+// @Description Change a promo code's terms (requires edit_event). Tickets that already redeemed it keep their discount.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param codeId path int true "Promo code ID"
+// @Param request body models.PromoCodeRequest true "Promo code"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/promo-codes/{codeId} [put]
+func (h *TicketHandler) UpdatePromoCode(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, promoCodeID, ok := promoParams(c)
+	if !ok {
+		return
+	}
+	var req models.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p, err := h.tickets.UpdatePromoCode(c, eventID, promoCodeID, userID, req)
+	if err != nil {
+		promoError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// DeletePromoCode removes a discount code
+// @Summary Delete a promo code
+// @Description Delete a promo code and its redemption history (requires edit_event). Tickets keep their discounted price.
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param codeId path int true "Promo code ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/promo-codes/{codeId} [delete]
+func (h *TicketHandler) DeletePromoCode(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, promoCodeID, ok := promoParams(c)
+	if !ok {
+		return
+	}
+	if err := h.tickets.DeletePromoCode(c, eventID, promoCodeID, userID); err != nil {
+		promoError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Promo code deleted successfully"})
+}
+
+// ListRedemptions lists who redeemed a discount code
+// @Summary List promo code redemptions
+// @Description Tickets that redeemed the promo code, newest first; releasedAt is set when the ticket was later cancelled or refunded (requires edit_event)
+// @Tags tickets
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param codeId path int true "Promo code ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.PromoRedemption
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/promo-codes/{codeId}/redemptions [get]
+func (h *TicketHandler) ListRedemptions(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, promoCodeID, ok := promoParams(c)
+	if !ok {
+		return
+	}
+	items, err := h.tickets.ListRedemptions(c, eventID, promoCodeID, userID)
+	if err != nil {
+		promoError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// SetTransferPolicy allows or forbids spot transfers
+// @Summary Set the transfer policy
+// @Description Allow or forbid participants to transfer their spot and ticket to another user (requires edit_event)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.TransferPolicyRequest true "Policy"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/transfers [put]
+func (h *TicketHandler) SetTransferPolicy(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.TransferPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.tickets.SetTransferPolicy(c, eventID, userID, *req.Allowed); err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"allowTransfers": *req.Allowed})
+}
+
+// Transfer hands the caller's spot to another user
+// @Summary Transfer my spot
+// @Description Transfer the caller's spot, and claimed ticket if any, to the user with the given email. The recipient joins as an attendee with the same attendance; a moved ticket gets a new code. Requires the event to allow transfers. Both users are notified.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.TransferRequest true "Recipient"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Transfer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/transfer [post]
+func (h *TicketHandler) Transfer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.tickets.Transfer(c, eventID, userID, req.Email)
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimeEntryHandler struct {
+	entries services.TimeEntryService
+}
+
+func NewTimeEntryHandler(entries services.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{entries: entries}
+}
+
+// timeEntryError writes the HTTP response for a time entry service error.
+func timeEntryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTimeEntry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTaskNotFound), errors.Is(err, services.ErrTimeEntryNotFound), errors.Is(err, services.ErrTimerNotRunning):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTimerRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// taskPathIDs reads the event ID and the taskId path parameter, writing a
+// 400 response when either is invalid.
+func taskPathIDs(c *gin.Context) (int, int, bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil || taskID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return 0, 0, false
+	}
+	return eventID, taskID, true
+}
+
+// StartTimer starts tracking time on a task
+// @Summary Start a task timer
+// @Description Start tracking the caller's time on the task (the task's assignee, or manage_tasks). A timer the caller had running, on any task, is stopped first.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.TimerRequest false "Optional note"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	var req models.TimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.entries.StartTimer(c, eventID, taskID, userID, req)
+	if err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// StopTimer stops tracking time on a task
+// @Summary Stop a task timer
+// @Description Stop the caller's timer on the task, returning the finished time entry.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	entry, err := h.entries.StopTimer(c, eventID, taskID, userID)
+	if err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Add logs time spent on a task
+// @Summary Log time on a task
+// @Description Log time the caller spent on the task by hand (the task's assignee, or manage_tasks): 1 to 1440 minutes from startedAt, which defaults to that many minutes ago. Entries cannot end in the future.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param request body models.TimeEntryRequest true "Time spent"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/time-entries [post]
+func (h *TimeEntryHandler) Add(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	var req models.TimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.entries.Add(c, eventID, taskID, userID, req)
+	if err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// List returns the time tracked on a task
+// @Summary List a task's time entries
+// @Description Everyone's time entries on the task, most recent first (any participant). Running timers have no endedAt and count their minutes up to now.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/time-entries [get]
+func (h *TimeEntryHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	entries, err := h.entries.List(c, eventID, taskID, userID)
+	if err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// Delete removes a time entry
+// @Summary Delete a time entry
+// @Description Delete one of the caller's time entries on the task, or anyone's with manage_tasks.
+// @Tags tasks
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Param entryId path int true "Time entry ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/time-entries/{entryId} [delete]
+func (h *TimeEntryHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	entryID, err := strconv.Atoi(c.Param("entryId"))
+	if err != nil || entryID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time entry id"})
+		return
+	}
+	if err := h.entries.Delete(c, eventID, taskID, entryID, userID); err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Totals sums up the time estimated and spent on an event's tasks
+// @Summary Time totals of an event
+// @Description Sums of the estimated and tracked minutes of the event's tasks, per task in their order and per user with the most time first (requires manage_tasks). Running timers count up to now.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EventTimeTotals
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/time [get]
+func (h *TimeEntryHandler) Totals(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	totals, err := h.entries.Totals(c, eventID, userID)
+	if err != nil {
+		timeEntryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, totals)
+}
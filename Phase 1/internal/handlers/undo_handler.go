@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type UndoHandler struct {
+	undo services.UndoService
+}
+
+func NewUndoHandler(undo services.UndoService) *UndoHandler {
+	return &UndoHandler{undo: undo}
+}
+
+// Undo reverses a destructive action
+// @Summary Undo an action
+// @Description Reverse an event deletion, a cancellation or a revoked invitation with the undo token its response carried. Tokens work once, only for the user who received them, and expire after UNDO_WINDOW_MINUTES (default 10). Notifications already sent, e.g. about a cancellation, are not taken back.
+// @Tags undo
+// @Produce json
+// @Param token path string true "Undo token"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.UndoResult
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /undo/{token} [post]
+func (h *UndoHandler) Undo(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	res, err := h.undo.Undo(c, userID, c.Param("token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusNotFound
+			err = errors.New("undo token not found or expired")
+		case errors.Is(err, services.ErrUndoConflict):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
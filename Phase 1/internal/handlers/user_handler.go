@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Result limits for user search.
+const (
+	defaultUserSearchLimit = 10
+	maxUserSearchLimit     = 25
+)
+
+type UserHandler struct {
+	users services.UserService
+}
+
+func NewUserHandler(users services.UserService) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+// Search finds users to invite
+// @Summary Search users
+// @Description Typeahead for the invite dialog. Matches users by exact email (case-insensitive), or by name among people who share an event with the caller. Emails are only returned for exact email matches. Rate limited per user.
+// @Tags users
+// @Produce json
+// @Param q query string true "Name fragment or exact email (2-100 characters)"
+// @Param limit query int false "Maximum number of results (default 10, max 25)"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.UserSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/search [get]
+func (h *UserHandler) Search(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit := defaultUserSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxUserSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit, must be between 1 and 25"})
+			return
+		}
+		limit = n
+	}
+	users, err := h.users.Search(c, userID, c.Query("q"), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidUserQuery) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// Block stops invitations from a user or an email domain
+// @Summary Block a user or domain
+// @Description Give either userId or domain (e.g. "example.com"). New invitations from a blocked sender are dropped without telling the sender, and no notifications or nudges are sent for invitations they made earlier.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.BlockRequest true "User or domain to block"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.UserBlock
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/blocks [post]
+func (h *UserHandler) Block(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.BlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	b, err := h.users.Block(c, userID, req)
+	if err != nil {
+		blockError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, b)
+}
+
+// ListBlocks returns the caller's blocked users and domains
+// @Summary List blocked users and domains
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.UserBlock
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/blocks [get]
+func (h *UserHandler) ListBlocks(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	blocks, err := h.users.ListBlocks(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, blocks)
+}
+
+// Unblock removes a block
+// @Summary Remove a block
+// @Tags users
+// @Produce json
+// @Param id path int true "Block ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/blocks/{id} [delete]
+func (h *UserHandler) Unblock(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid block id"})
+		return
+	}
+	if err := h.users.Unblock(c, id, userID); err != nil {
+		blockError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Block removed successfully"})
+}
+
+func blockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidBlock), errors.Is(err, services.ErrBlockSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownUser):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrBlockExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "block not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// SetCurrency sets the caller's preferred currency
+// @Summary Set preferred currency
+// @Description Ticket sales of events the caller organizes are reported in this currency by default.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.CurrencyRequest true "ISO 4217 currency code"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/currency [put]
+func (h *UserHandler) SetCurrency(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.CurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	currency, err := h.users.SetPreferredCurrency(c, userID, req.Currency)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"currency": currency})
+}
+
+// SetLocale sets the caller's language
+// @Summary Set language
+// @Description Notifications and emails are sent to the caller in this language (en or de). API responses follow the Accept-Language header of each request instead.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.LocaleRequest true "Language"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/me/locale [put]
+func (h *UserHandler) SetLocale(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.LocaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	locale, err := h.users.SetLocale(c, userID, req.Locale)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrUnsupportedLocale):
+			status = http.StatusBadRequest
+		case errors.Is(err, pgx.ErrNoRows):
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"locale": locale})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type VendorHandler struct {
+	vendors services.VendorService
+}
+
+func NewVendorHandler(vendors services.VendorService) *VendorHandler {
+	return &VendorHandler{vendors: vendors}
+}
+
+// vendorError writes the HTTP response for a vendor service error.
+func vendorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor not found"})
+	case errors.Is(err, services.ErrUnknownTask), errors.Is(err, services.ErrPaidOverAmount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// vendorParams parses the event and vendor ids of a vendor route.
+func vendorParams(c *gin.Context) (eventID, vendorID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return 0, 0, false
+	}
+	vendorID, err = strconv.Atoi(c.Param("vendorId"))
+	if err != nil || vendorID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor id"})
+		return 0, 0, false
+	}
+	return eventID, vendorID, true
+}
+
+// Create adds a vendor to an event
+// @Summary Create a vendor
+// @Description Add a vendor or supplier (caterer, AV, ...) with contact details, contract and payment status, and optionally link tasks of the event to it (requires edit_event)
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param request body models.VendorRequest true "Vendor"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Vendor
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/vendors [post]
+func (h *VendorHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	var req models.VendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	v, err := h.vendors.Create(c, eventID, userID, req)
+	if err != nil {
+		vendorError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, v)
+}
+
+// List returns an event's vendors
+// @Summary List vendors
+// @Description The event's vendors ordered by category and name (requires edit_event)
+// @Tags vendors
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Vendor
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/vendors [get]
+func (h *VendorHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	items, err := h.vendors.List(c, eventID, userID)
+	if err != nil {
+		vendorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns one vendor
+// @Summary Get a vendor
+// @Description One vendor of the event with its linked tasks (requires edit_event)
+// @Tags vendors
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param vendorId path int true "Vendor ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Vendor
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/vendors/{vendorId} [get]
+func (h *VendorHandler) Get(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, vendorID, ok := vendorParams(c)
+	if !ok {
+		return
+	}
+	v, err := h.vendors.Get(c, eventID, vendorID, userID)
+	if err != nil {
+		vendorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// Update changes a vendor
+// @Summary Update a vendor
+// @Description Replace a vendor's details and linked tasks (requires edit_event)
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param vendorId path int true "Vendor ID"
+// @Param request body models.VendorRequest true "Vendor"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Vendor
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/vendors/{vendorId} [put]
+func (h *VendorHandler) Update(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, vendorID, ok := vendorParams(c)
+	if !ok {
+		return
+	}
+	var req models.VendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	v, err := h.vendors.Update(c, eventID, vendorID, userID, req)
+	if err != nil {
+		vendorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// Delete removes a vendor
+// @Summary Delete a vendor
+// @Description Remove a vendor from the event; its linked tasks are kept (requires edit_event)
+// @Tags vendors
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param vendorId path int true "Vendor ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/vendors/{vendorId} [delete]
+func (h *VendorHandler) Delete(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, vendorID, ok := vendorParams(c)
+	if !ok {
+		return
+	}
+	if err := h.vendors.Delete(c, eventID, vendorID, userID); err != nil {
+		vendorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vendor deleted successfully"})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type VenueHandler struct {
+	venues services.VenueService
+}
+
+func NewVenueHandler(venues services.VenueService) *VenueHandler {
+	return &VenueHandler{venues: venues}
+}
+
+// Create stores a reusable venue
+// @Summary Create a venue
+// @Description Create a venue; missing coordinates are filled in by the configured geocoder when an address is given
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param request body models.VenueRequest true "Venue details"
+// @Security ApiKeyAuth
+// @Success 201 {object} models.Venue
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /venues [post]
+func (h *VenueHandler) Create(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req models.VenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	v, err := h.venues.Create(c, userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, v)
+}
+
+// List returns the venues created by the caller
+// @Summary List my venues
+// @Tags venues
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Venue
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /venues [get]
+func (h *VenueHandler) List(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	items, err := h.venues.ListMine(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// Get returns a single venue
+// @Summary Get a venue
+// @Tags venues
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} models.Venue
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /venues/{id} [get]
+func (h *VenueHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid venue id"})
+		return
+	}
+	v, err := h.venues.Get(c, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "venue not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WatchHandler struct {
+	watches services.WatchService
+}
+
+func NewWatchHandler(watches services.WatchService) *WatchHandler {
+	return &WatchHandler{watches: watches}
+}
+
+// watchError writes the HTTP response for a watch service error.
+func watchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Watching returns what the caller watches in an event
+// @Summary Get watched items
+// @Description Whether the caller watches the event, and the IDs of the event's tasks they watch (any participant).
+// @Tags watchers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Watching
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/watch [get]
+func (h *WatchHandler) Watching(c *gin.Context) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	watching, err := h.watches.Watching(c, eventID, userID)
+	if err != nil {
+		watchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, watching)
+}
+
+// WatchEvent watches an event
+// @Summary Watch an event
+// @Description Get notified when the event's details change, as kind event_changed (any participant). Returns what the caller watches in the event.
+// @Tags watchers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Watching
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/watch [put]
+func (h *WatchHandler) WatchEvent(c *gin.Context) {
+	h.watchEvent(c, true)
+}
+
+// UnwatchEvent stops watching an event
+// @Summary Unwatch an event
+// @Description Stop getting notified of the event's changes. Returns what the caller watches in the event.
+// @Tags watchers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Watching
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/watch [delete]
+func (h *WatchHandler) UnwatchEvent(c *gin.Context) {
+	h.watchEvent(c, false)
+}
+
+func (h *WatchHandler) watchEvent(c *gin.Context, watch bool) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || eventID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+	watching, err := h.watches.WatchEvent(c, eventID, userID, watch)
+	if err != nil {
+		watchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, watching)
+}
+
+// WatchTask watches a task
+// @Summary Watch a task
+// @Description Get notified when the task changes, as kind task_changed, without being its assignee (any participant). Returns what the caller watches in the event.
+// @Tags watchers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Watching
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/watch [put]
+func (h *WatchHandler) WatchTask(c *gin.Context) {
+	h.watchTask(c, true)
+}
+
+// UnwatchTask stops watching a task
+// @Summary Unwatch a task
+// @Description Stop getting notified of the task's changes. Its assignee still is. Returns what the caller watches in the event.
+// @Tags watchers
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param taskId path int true "Task ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Watching
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /events/{id}/tasks/{taskId}/watch [delete]
+func (h *WatchHandler) UnwatchTask(c *gin.Context) {
+	h.watchTask(c, false)
+}
+
+func (h *WatchHandler) watchTask(c *gin.Context, watch bool) {
+	userID := c.GetInt("userID")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	eventID, taskID, ok := taskPathIDs(c)
+	if !ok {
+		return
+	}
+	watching, err := h.watches.WatchTask(c, eventID, taskID, userID, watch)
+	if err != nil {
+		watchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, watching)
+}
@@ -0,0 +1,223 @@
+// Package i18n translates the English texts of the API (error messages,
+// notifications and emails) into the user's language.
+//
+// Catalogs are gettext-style: each maps an English text, as written in the
+// code, to its translation. Texts built with fmt are listed by their format,
+// e.g. "You're invited to %s", and are recognized in the formatted text; the
+// values filled in are kept, and translated too when the catalog has them, so
+// "approved" in "Your change was approved" can be translated on its own. A
+// translation may reorder values with explicit indexes such as %[2]s.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language texts are written in, which needs no catalog.
+const Default = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+// verb matches the fmt verbs catalog formats may use.
+var verb = regexp.MustCompile(`%(\[\d+\])?[sdqv]`)
+
+// pattern is a catalog format with values, matched against formatted text.
+type pattern struct {
+	re          *regexp.Regexp
+	translation string // with all verbs turned into %s, for the matched strings
+}
+
+type catalog struct {
+	texts    map[string]string
+	patterns []pattern
+}
+
+var catalogs = load()
+
+// load reads the embedded catalogs. A malformed catalog is a programming
+// error and panics at startup.
+func load() map[string]*catalog {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	res := map[string]*catalog{}
+	for _, e := range entries {
+		data, err := files.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var texts map[string]string
+		if err := json.Unmarshal(data, &texts); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", e.Name(), err))
+		}
+		c := &catalog{texts: texts}
+		for msgid, translation := range texts {
+			verbs := verb.FindAllString(msgid, -1)
+			if len(verbs) == 0 {
+				continue
+			}
+			if n := len(verb.FindAllString(translation, -1)); n != len(verbs) {
+				panic(fmt.Sprintf("i18n: %s: %q has %d values but its translation %d", e.Name(), msgid, len(verbs), n))
+			}
+			parts := verb.Split(msgid, -1)
+			expr := "^"
+			for i, part := range parts {
+				expr += regexp.QuoteMeta(part)
+				if i < len(verbs) {
+					if strings.HasSuffix(verbs[i], "q") {
+						expr += `("(?:[^"\\]|\\.)*")`
+					} else {
+						expr += `([^\n]+?)`
+					}
+				}
+			}
+			c.patterns = append(c.patterns, pattern{
+				re:          regexp.MustCompile(expr + "$"),
+				translation: verb.ReplaceAllString(translation, "%${1}s"),
+			})
+		}
+		// Longer formats are more specific, so they are tried first.
+		sort.Slice(c.patterns, func(i, j int) bool {
+			a, b := c.patterns[i].re.String(), c.patterns[j].re.String()
+			if len(a) != len(b) {
+				return len(a) > len(b)
+			}
+			return a < b
+		})
+		res[strings.TrimSuffix(e.Name(), ".json")] = c
+	}
+	return res
+}
+
+// Supported returns the available languages, Default first.
+func Supported() []string {
+	res := []string{Default}
+	for locale := range catalogs {
+		res = append(res, locale)
+	}
+	sort.Strings(res[1:])
+	return res
+}
+
+// IsSupported reports whether texts can be given in locale.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok || locale == Default
+}
+
+// Negotiate picks the language to answer in from an Accept-Language header,
+// such as "de-CH, de;q=0.9, en;q=0.8": the most preferred one supported,
+// matching regional variants by their language. Default when none is.
+func Negotiate(acceptLanguage string) string {
+	type choice struct {
+		tag string
+		q   float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if tag != "" && q > 0 {
+			choices = append(choices, choice{strings.ToLower(tag), q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	for _, c := range choices {
+		if c.tag == "*" {
+			return Default
+		}
+		lang, _, _ := strings.Cut(c.tag, "-")
+		if IsSupported(c.tag) {
+			return c.tag
+		}
+		if IsSupported(lang) {
+			return lang
+		}
+	}
+	return Default
+}
+
+// Translate returns text in locale. Text the catalog does not know as a
+// whole is translated paragraph by paragraph, line by line and sentence by
+// sentence, and "message: detail" errors by their message; what is still
+// unknown stays in English.
+func Translate(locale, text string) string {
+	c := catalogs[locale]
+	if c == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+	return c.translate(text)
+}
+
+func (c *catalog) translate(text string) string {
+	if t, ok := c.lookup(text); ok {
+		return t
+	}
+	for _, sep := range []string{"\n\n", "\n", ". "} {
+		parts := strings.Split(text, sep)
+		if len(parts) == 1 {
+			continue
+		}
+		for i := range parts {
+			if sep == ". " && i < len(parts)-1 {
+				parts[i] = strings.TrimSuffix(c.translate(parts[i]+"."), ".")
+			} else {
+				parts[i] = c.translate(parts[i])
+			}
+		}
+		return strings.Join(parts, sep)
+	}
+	if msg, detail, ok := strings.Cut(text, ": "); ok {
+		if t, ok := c.lookup(msg); ok {
+			return t + ": " + c.translate(detail)
+		}
+	}
+	return text
+}
+
+// lookup translates a whole text, or reports that the catalog has no entry
+// for it.
+func (c *catalog) lookup(text string) (string, bool) {
+	if t, ok := c.texts[text]; ok && !verb.MatchString(text) {
+		return t, true
+	}
+	for _, p := range c.patterns {
+		m := p.re.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		args := make([]any, len(m)-1)
+		for i, v := range m[1:] {
+			// A value running into the next sentence means the format only
+			// matched the start of the text.
+			if strings.Contains(v, ". ") {
+				args = nil
+				break
+			}
+			if t, ok := c.texts[v]; ok {
+				v = t
+			}
+			args[i] = v
+		}
+		if args != nil {
+			return fmt.Sprintf(p.translation, args...), true
+		}
+	}
+	return "", false
+}
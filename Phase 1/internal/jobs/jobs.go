@@ -0,0 +1,88 @@
+// Package jobs runs side effects such as email delivery and webhook
+// processing in the background, with retries and dead-lettering, so request
+// handlers never wait on them. Pool is an in-process implementation; a
+// Redis- or database-backed Queue can replace it without touching callers.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when the queue cannot take more jobs.
+	ErrQueueFull = errors.New("jobs: queue is full")
+	// ErrUnknownKind is returned by Enqueue for kinds without a handler.
+	ErrUnknownKind = errors.New("jobs: no handler registered for job kind")
+)
+
+// Job is a unit of background work. Payload is the JSON-encoded argument of
+// the handler registered for Kind.
+type Job struct {
+	Kind       string
+	Payload    json.RawMessage
+	Attempts   int
+	EnqueuedAt time.Time
+}
+
+// Handler processes the payload of one job. Returning an error schedules a
+// retry unless the error is Permanent or the job is out of attempts.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Queue accepts jobs for background processing. Handlers must be registered
+// before jobs of their kind are enqueued.
+type Queue interface {
+	Register(kind string, h Handler)
+	Enqueue(ctx context.Context, kind string, payload any) error
+}
+
+// DeadLetterStore keeps jobs that failed every attempt for later inspection.
+type DeadLetterStore interface {
+	AddDeadJob(ctx context.Context, kind string, payload []byte, attempts int, lastError string, enqueuedAt time.Time) error
+}
+
+// Type is a job kind with a typed payload. Declaring one per kind keeps the
+// enqueuing side and the handler in agreement on the payload type.
+type Type[T any] struct {
+	Kind string
+}
+
+// NewType declares a job kind whose payload is a T.
+func NewType[T any](kind string) Type[T] {
+	return Type[T]{Kind: kind}
+}
+
+// Handle registers fn as the handler of t's jobs on q.
+func (t Type[T]) Handle(q Queue, fn func(ctx context.Context, payload T) error) {
+	q.Register(t.Kind, func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", t.Kind, err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Enqueue adds a job of kind t with the given payload to q.
+func (t Type[T]) Enqueue(ctx context.Context, q Queue, payload T) error {
+	return q.Enqueue(ctx, t.Kind, payload)
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying; the job is dead-lettered at once.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
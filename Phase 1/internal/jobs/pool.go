@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Options tune a Pool. Zero values fall back to the defaults below.
+type Options struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles on each attempt.
+	Backoff time.Duration
+	// Timeout bounds a single attempt.
+	Timeout time.Duration
+}
+
+const (
+	defaultWorkers     = 4
+	defaultQueueSize   = 1000
+	defaultMaxAttempts = 5
+	defaultBackoff     = 2 * time.Second
+	defaultTimeout     = time.Minute
+)
+
+// Pool is an in-process Queue served by a fixed number of workers. Jobs live
+// in memory, so jobs still queued when the process exits are lost.
+type Pool struct {
+	opts        Options
+	deadLetters DeadLetterStore
+	jobs        chan Job
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewPool returns a pool that records exhausted jobs in deadLetters. Call
+// Start to begin processing.
+func NewPool(opts Options, deadLetters DeadLetterStore) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	return &Pool{
+		opts:        opts,
+		deadLetters: deadLetters,
+		jobs:        make(chan Job, opts.QueueSize),
+		handlers:    map[string]Handler{},
+	}
+}
+
+func (p *Pool) Register(kind string, h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[kind] = h
+}
+
+// Enqueue never blocks: it fails with ErrQueueFull when the buffer is full.
+func (p *Pool) Enqueue(ctx context.Context, kind string, payload any) error {
+	if p.handler(kind) == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("jobs: encode %s payload: %w", kind, err)
+	}
+	return p.push(Job{Kind: kind, Payload: raw, EnqueuedAt: time.Now()})
+}
+
+// Start runs the workers until ctx is cancelled.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.opts.Workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-p.jobs:
+					p.run(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+func (p *Pool) handler(kind string) Handler {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.handlers[kind]
+}
+
+func (p *Pool) push(job Job) error {
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// run makes one attempt at job and schedules a retry with exponential
+// backoff when it fails.
+func (p *Pool) run(ctx context.Context, job Job) {
+	job.Attempts++
+	attemptCtx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	err := p.attempt(attemptCtx, job)
+	cancel()
+	if err == nil {
+		return
+	}
+	if IsPermanent(err) || job.Attempts >= p.opts.MaxAttempts {
+		p.deadLetter(job, err)
+		return
+	}
+	delay := p.opts.Backoff << (job.Attempts - 1)
+	log.Printf("job %s: attempt %d failed, retrying in %s: %v", job.Kind, job.Attempts, delay, err)
+	time.AfterFunc(delay, func() {
+		if err := p.push(job); err != nil {
+			p.deadLetter(job, err)
+		}
+	})
+}
+
+// attempt calls the job's handler. A handler that panics fails the job
+// permanently instead of taking the process down; the panic would most likely
+// recur on a retry.
+func (p *Pool) attempt(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job %s: panic: %v\n%s", job.Kind, r, debug.Stack())
+			err = Permanent(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return p.handler(job.Kind)(ctx, job.Payload)
+}
+
+func (p *Pool) deadLetter(job Job, err error) {
+	log.Printf("job %s: giving up after %d attempts: %v", job.Kind, job.Attempts, err)
+	if p.deadLetters == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if dlErr := p.deadLetters.AddDeadJob(ctx, job.Kind, job.Payload, job.Attempts, err.Error(), job.EnqueuedAt); dlErr != nil {
+		log.Printf("job %s: dead-lettering failed: %v", job.Kind, dlErr)
+	}
+}
@@ -0,0 +1,155 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxBody is how much of a page is read; the metadata is in its head.
+	maxBody      = 512 << 10
+	maxRedirects = 3
+	fetchTimeout = 10 * time.Second
+)
+
+// blockedPrefixes are the ranges that net/netip does not already consider
+// private or special but that must not be reached either: "this network",
+// carrier-grade NAT, IETF protocol assignments, benchmarking, reserved,
+// and IPv6 translations and tunnels that can embed any IPv4 address.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// HTTP fetches pages over the internet. The address check runs when each
+// connection is made, on the address actually dialed, so a name that
+// resolves to a public address when checked and to a private one when
+// used, or a redirect to a private host, gets nowhere. Proxies from the
+// environment are not used, as they would be dialed instead of the host.
+type HTTP struct {
+	userAgent string
+	client    *http.Client
+}
+
+// NewHTTP creates a fetcher identifying as userAgent, or as
+// eventplanner-backend when it is empty.
+func NewHTTP(userAgent string) *HTTP {
+	if userAgent == "" {
+		userAgent = "eventplanner-backend"
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: checkDial}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &HTTP{
+		userAgent: userAgent,
+		client: &http.Client{
+			Timeout:   fetchTimeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return errors.New("linkpreview: too many redirects")
+				}
+				return checkURL(req.URL)
+			},
+		},
+	}
+}
+
+func (h *HTTP) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("linkpreview: %w", err)
+	}
+	if err := checkURL(u); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", h.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("linkpreview: unexpected status %d", resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNoPreview
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("linkpreview: %w", err)
+	}
+	p := parse(strings.ToValidUTF8(string(body), "�"), resp.Request.URL)
+	if p.Title == "" {
+		return nil, ErrNoPreview
+	}
+	return p, nil
+}
+
+// checkURL refuses URLs other than http(s) on the default ports, with no
+// credentials.
+func checkURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrBlocked
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// checkDial refuses connections to anything but a public address on port
+// 80 or 443.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !public(ip) || (port != "80" && port != "443") {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// public reports whether ip is a unicast address on the public internet.
+func public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
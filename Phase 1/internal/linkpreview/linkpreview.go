@@ -0,0 +1,53 @@
+// Package linkpreview reads the Open Graph metadata of web pages linked from
+// user content, such as their title and image, for link cards. Pages are
+// fetched by the server at URLs users chose, so fetching refuses anything
+// but web servers on the public internet.
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+var (
+	// ErrBlocked is returned for URLs that are not http(s) on the default
+	// ports, and hosts that resolve to private, loopback or other
+	// non-public addresses.
+	ErrBlocked = errors.New("linkpreview: address not allowed")
+	// ErrNoPreview is returned for pages without a title to show, and for
+	// responses that are not HTML pages.
+	ErrNoPreview = errors.New("linkpreview: no preview")
+	// ErrDisabled is returned when link previews are turned off.
+	ErrDisabled = errors.New("linkpreview: link previews are disabled")
+)
+
+// Preview is what a page says about itself. ImageURL, when set, is an
+// absolute http(s) URL.
+type Preview struct {
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// Fetcher reads the preview of the page at a URL.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (*Preview, error)
+}
+
+// NewFromEnv fetches pages itself, identifying as LINK_PREVIEW_USER_AGENT,
+// unless LINK_PREVIEWS is "off".
+func NewFromEnv() Fetcher {
+	if os.Getenv("LINK_PREVIEWS") == "off" {
+		return Noop{}
+	}
+	return NewHTTP(os.Getenv("LINK_PREVIEW_USER_AGENT"))
+}
+
+// Noop previews no page.
+type Noop struct{}
+
+func (Noop) Fetch(ctx context.Context, url string) (*Preview, error) {
+	return nil, ErrDisabled
+}
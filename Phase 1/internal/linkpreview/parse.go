@@ -0,0 +1,82 @@
+package linkpreview
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	metaTag   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attribute = regexp.MustCompile(`(?s)([a-zA-Z_:.-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titleTag  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	headEnd   = regexp.MustCompile(`(?i)</head>`)
+)
+
+// parse reads the preview from a page's Open Graph tags, falling back to
+// Twitter card tags, the <title> and the description meta tag.
+func parse(page string, base *url.URL) *Preview {
+	if loc := headEnd.FindStringIndex(page); loc != nil {
+		page = page[:loc[0]]
+	}
+	meta := map[string]string{}
+	for _, tag := range metaTag.FindAllString(page, -1) {
+		attrs := map[string]string{}
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+		}
+		key := strings.ToLower(attrs["property"])
+		if key == "" {
+			key = strings.ToLower(attrs["name"])
+		}
+		if _, seen := meta[key]; key != "" && !seen {
+			meta[key] = clean(attrs["content"], 1000)
+		}
+	}
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	p := &Preview{
+		Title:       truncate(first("og:title", "twitter:title"), 300),
+		Description: truncate(first("og:description", "twitter:description", "description"), 500),
+		SiteName:    truncate(first("og:site_name"), 100),
+	}
+	if p.Title == "" {
+		if m := titleTag.FindStringSubmatch(page); m != nil {
+			p.Title = truncate(clean(m[1], 1000), 300)
+		}
+	}
+	if p.SiteName == "" {
+		p.SiteName = strings.TrimPrefix(base.Hostname(), "www.")
+	}
+	if image := first("og:image", "og:image:url", "og:image:secure_url", "twitter:image"); image != "" {
+		if u, err := base.Parse(image); err == nil && (u.Scheme == "http" || u.Scheme == "https") && len(u.String()) <= 2000 {
+			p.ImageURL = u.String()
+		}
+	}
+	return p
+}
+
+// clean decodes entities and collapses whitespace, first cutting s to at
+// most limit bytes.
+func clean(s string, limit int) string {
+	if len(s) > limit {
+		s = strings.ToValidUTF8(s[:limit], "")
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// truncate cuts s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n-1])) + "…"
+}
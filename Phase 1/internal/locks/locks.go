@@ -0,0 +1,25 @@
+// Package locks provides named mutexes shared by every server instance, for
+// work that must not run twice at once when the service is scaled out.
+// Postgres implements them with session-level advisory locks.
+package locks
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotAcquired is returned by TryLock when another holder has the lock.
+var ErrNotAcquired = errors.New("locks: lock is held elsewhere")
+
+// Locker hands out named locks.
+type Locker interface {
+	// TryLock acquires the lock without waiting.
+	TryLock(ctx context.Context, name string) (Lock, error)
+	// Lock waits for the lock until it is free or ctx is done.
+	Lock(ctx context.Context, name string) (Lock, error)
+}
+
+// Lock is a held lock. Unlock releases it; it must be called exactly once.
+type Lock interface {
+	Unlock() error
+}
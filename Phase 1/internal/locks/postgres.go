@@ -0,0 +1,79 @@
+package locks
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres takes advisory locks on connections from pool. Each held lock pins
+// one pooled connection until it is unlocked, and the lock is released by the
+// database if the holder's connection dies.
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+func (p *Postgres) TryLock(ctx context.Context, name string) (Lock, error) {
+	return p.acquire(ctx, name, `SELECT pg_try_advisory_lock($1)`)
+}
+
+func (p *Postgres) Lock(ctx context.Context, name string) (Lock, error) {
+	return p.acquire(ctx, name, `SELECT true FROM pg_advisory_lock($1)`)
+}
+
+func (p *Postgres) acquire(ctx context.Context, name, q string) (Lock, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := lockKey(name)
+	var ok bool
+	if err := conn.QueryRow(ctx, q, key).Scan(&ok); err != nil {
+		// The lock may have been granted before the error surfaced, so don't
+		// hand this session back to the pool.
+		conn.Hijack().Close(context.Background())
+		return nil, err
+	}
+	if !ok {
+		conn.Release()
+		return nil, ErrNotAcquired
+	}
+	return &pgLock{conn: conn, key: key, name: name}, nil
+}
+
+type pgLock struct {
+	conn *pgxpool.Conn
+	key  int64
+	name string
+}
+
+func (l *pgLock) Unlock() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var released bool
+	err := l.conn.QueryRow(ctx, `SELECT pg_advisory_unlock($1)`, l.key).Scan(&released)
+	if err != nil {
+		// Closing the session is the only other way to release the lock.
+		l.conn.Hijack().Close(ctx)
+		return err
+	}
+	if !released {
+		log.Printf("locks: %s was not held at unlock", l.name)
+	}
+	l.conn.Release()
+	return nil
+}
+
+// lockKey maps a lock name onto the 64-bit advisory lock key space.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("eventplanner:" + name))
+	return int64(h.Sum64())
+}
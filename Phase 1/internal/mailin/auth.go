@@ -0,0 +1,98 @@
+package mailin
+
+import "strings"
+
+// Authenticated reports whether the receiving provider vouched for the From
+// address of m: in an Authentication-Results header under the provider's
+// authserv-id, DMARC passed for the From domain, or a DKIM signature by that
+// domain or a parent of it verified. Results under other authserv-ids may
+// have been written by the sender and are ignored, as is anything short of
+// a pass, including mail from domains without a DMARC policy.
+func (c Config) Authenticated(m *Message) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(m.From), "@")
+	if !ok || c.AuthServID == "" {
+		return false
+	}
+	for _, header := range m.AuthResults {
+		servID, results := parseAuthResults(header)
+		if servID != c.AuthServID {
+			continue
+		}
+		for _, r := range results {
+			if r.result != "pass" {
+				continue
+			}
+			switch r.method {
+			case "dmarc":
+				if r.props["header.from"] == domain {
+					return true
+				}
+			case "dkim":
+				d := r.props["header.d"]
+				if d == "" {
+					_, d, _ = strings.Cut(r.props["header.i"], "@")
+				}
+				if d != "" && (domain == d || strings.HasSuffix(domain, "."+d)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// authResult is one method's result in an Authentication-Results header,
+// with its properties such as header.from. Names and values are lowercase.
+type authResult struct {
+	method string
+	result string
+	props  map[string]string
+}
+
+// parseAuthResults splits an Authentication-Results header (RFC 8601) into
+// its authserv-id and results.
+func parseAuthResults(header string) (string, []authResult) {
+	parts := strings.Split(strings.ToLower(stripComments(header)), ";")
+	head := strings.Fields(parts[0])
+	if len(head) == 0 {
+		return "", nil
+	}
+	var results []authResult
+	for _, part := range parts[1:] {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		method, result, ok := strings.Cut(fields[0], "=")
+		if !ok {
+			continue
+		}
+		method, _, _ = strings.Cut(method, "/")
+		r := authResult{method: method, result: result, props: map[string]string{}}
+		for _, f := range fields[1:] {
+			if k, v, ok := strings.Cut(f, "="); ok {
+				r.props[k] = strings.Trim(v, `"`)
+			}
+		}
+		results = append(results, r)
+	}
+	return head[0], results
+}
+
+// stripComments removes parenthesized comments, which may nest and contain
+// semicolons.
+func stripComments(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
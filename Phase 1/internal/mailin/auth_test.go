@@ -0,0 +1,42 @@
+package mailin
+
+import "testing"
+
+func TestAuthenticated(t *testing.T) {
+	c := Config{AuthServID: "mx.example.net"}
+	tests := []struct {
+		name    string
+		from    string
+		results []string
+		want    bool
+	}{
+		{"no header", "ann@example.com", nil, false},
+		{"dmarc pass", "ann@example.com", []string{"mx.example.net; spf=pass smtp.mailfrom=example.com; dmarc=pass (p=REJECT) header.from=example.com"}, true},
+		{"dmarc pass, from domain differs", "ann@example.com", []string{"mx.example.net; dmarc=pass header.from=evil.test"}, false},
+		{"dmarc pass without header.from", "ann@example.com", []string{"mx.example.net; dmarc=pass"}, false},
+		{"dmarc none", "ann@example.com", []string{"mx.example.net; dmarc=none header.from=example.com"}, false},
+		{"dmarc fail", "ann@example.com", []string{"mx.example.net; dmarc=fail header.from=example.com"}, false},
+		{"spf pass only", "ann@example.com", []string{"mx.example.net; spf=pass smtp.mailfrom=example.com"}, false},
+		{"other authserv-id", "ann@example.com", []string{"attacker.test; dmarc=pass header.from=example.com"}, false},
+		{"authserv-id in a comment", "ann@example.com", []string{"attacker.test (mx.example.net); dmarc=pass header.from=example.com"}, false},
+		{"forged header below the provider's", "ann@example.com", []string{
+			"mx.example.net; dmarc=none header.from=example.com",
+			"attacker.test; dmarc=pass header.from=example.com",
+		}, false},
+		{"dkim pass, same domain", "ann@example.com", []string{"mx.example.net; dkim=pass header.d=example.com header.s=s1"}, true},
+		{"dkim pass, parent domain", "ann@mail.example.com", []string{"MX.Example.NET; DKIM=pass header.d=example.com"}, true},
+		{"dkim pass, unrelated domain", "ann@example.com", []string{"mx.example.net; dkim=pass header.d=notexample.com"}, false},
+		{"dkim pass by header.i", "ann@example.com", []string{`mx.example.net; dkim=pass header.i="@example.com"`}, true},
+		{"dkim fail", "ann@example.com", []string{"mx.example.net; dkim=fail (bad signature) header.d=example.com"}, false},
+		{"semicolon in a comment", "ann@example.com", []string{"mx.example.net; dkim=fail (x; dmarc=pass header.from=example.com) header.d=example.com"}, false},
+	}
+	for _, tt := range tests {
+		m := &Message{From: tt.from, AuthResults: tt.results}
+		if got := c.Authenticated(m); got != tt.want {
+			t.Errorf("%s: Authenticated = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if (Config{}).Authenticated(&Message{From: "ann@example.com", AuthResults: []string{"mx.example.net; dmarc=pass header.from=example.com"}}) {
+		t.Error("authenticated without an authserv-id configured")
+	}
+}
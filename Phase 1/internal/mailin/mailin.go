@@ -0,0 +1,210 @@
+// Package mailin reads emails sent to the inbound address, which the mail
+// provider forwards to the API as raw MIME messages.
+package mailin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxParts caps the MIME parts walked looking for the text of a message.
+const maxParts = 50
+
+// Config is the inbound address, the token the provider calls the API with,
+// the authserv-id the provider stamps its Authentication-Results with and the
+// time zone of times written without one.
+type Config struct {
+	Address    string
+	Token      string
+	AuthServID string
+	Location   *time.Location
+}
+
+// ConfigFromEnv reads INBOUND_EMAIL_ADDRESS, INBOUND_EMAIL_TOKEN,
+// INBOUND_EMAIL_AUTHSERV_ID and INBOUND_EMAIL_TIMEZONE (an IANA name, default
+// UTC). An unknown time zone is an error, so a typo does not shift every
+// event, and so is a token without an authserv-id, since no sender could be
+// trusted.
+func ConfigFromEnv() (Config, error) {
+	c := Config{
+		Address:    strings.ToLower(strings.TrimSpace(os.Getenv("INBOUND_EMAIL_ADDRESS"))),
+		Token:      os.Getenv("INBOUND_EMAIL_TOKEN"),
+		AuthServID: strings.ToLower(strings.TrimSpace(os.Getenv("INBOUND_EMAIL_AUTHSERV_ID"))),
+		Location:   time.UTC,
+	}
+	if c.Token != "" && c.AuthServID == "" {
+		return c, errors.New("INBOUND_EMAIL_AUTHSERV_ID is required with INBOUND_EMAIL_TOKEN")
+	}
+	if tz := os.Getenv("INBOUND_EMAIL_TIMEZONE"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return c, fmt.Errorf("INBOUND_EMAIL_TIMEZONE: %w", err)
+		}
+		c.Location = loc
+	}
+	return c, nil
+}
+
+// Enabled reports whether email-in is configured.
+func (c Config) Enabled() bool {
+	return c.Token != ""
+}
+
+// AddressedTo reports whether the message was sent to the inbound address;
+// any message is when no address is set.
+func (c Config) AddressedTo(m *Message) bool {
+	if c.Address == "" {
+		return true
+	}
+	for _, r := range m.Recipients {
+		if r == c.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// Message is what matters of an inbound email. Recipients are lowercase; From
+// is as written, since that is how users signed up with it. Text is the
+// plain-text body, or the HTML one stripped of tags when there is no
+// plain-text part. AuthResults are the message's Authentication-Results
+// headers, whoever added them; see Config.Authenticated.
+type Message struct {
+	From        string
+	Recipients  []string
+	Subject     string
+	Text        string
+	AuthResults []string
+}
+
+// Parse reads a raw MIME message.
+func Parse(raw []byte) (*Message, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("mailin: %w", err)
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("mailin: From: %w", err)
+	}
+	m := &Message{From: from.Address}
+	for _, h := range []string{"To", "Cc", "Delivered-To", "X-Original-To"} {
+		list, err := msg.Header.AddressList(h)
+		if err != nil {
+			continue
+		}
+		for _, a := range list {
+			m.Recipients = append(m.Recipients, strings.ToLower(a.Address))
+		}
+	}
+	dec := new(mime.WordDecoder)
+	if m.Subject, err = dec.DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		m.Subject = msg.Header.Get("Subject")
+	}
+	m.Subject = strings.TrimSpace(m.Subject)
+	m.AuthResults = msg.Header["Authentication-Results"]
+
+	plain, html, err := bodyText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body, 0)
+	if err != nil {
+		return nil, fmt.Errorf("mailin: body: %w", err)
+	}
+	m.Text = plain
+	if m.Text == "" {
+		m.Text = stripTags(html)
+	}
+	m.Text = strings.TrimSpace(strings.ReplaceAll(m.Text, "\r\n", "\n"))
+	return m, nil
+}
+
+// bodyText returns the first text/plain and text/html parts of a body,
+// descending into multipart bodies.
+func bodyText(contentType, encoding string, body io.Reader, depth int) (plain, html string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth > 5 {
+			return "", "", errors.New("too deeply nested")
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for i := 0; i < maxParts; i++ {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return plain, html, err
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			p, h, err := bodyText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part, depth+1)
+			if err != nil {
+				return plain, html, err
+			}
+			if plain == "" {
+				plain = p
+			}
+			if html == "" {
+				html = h
+			}
+		}
+		return plain, html, nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+	// multipart.Reader already decodes quoted-printable parts.
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", err
+	}
+	if mediaType == "text/html" {
+		return "", string(b), nil
+	}
+	return string(b), "", nil
+}
+
+// stripTags reduces HTML to its text, with block ends as line breaks.
+func stripTags(html string) string {
+	var b strings.Builder
+	inTag := false
+	var tag strings.Builder
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+			tag.Reset()
+		case r == '>' && inTag:
+			inTag = false
+			name, _, _ := strings.Cut(strings.Trim(tag.String(), "/"), " ")
+			switch strings.ToLower(name) {
+			case "br", "p", "div", "li", "tr", "h1", "h2", "h3":
+				b.WriteByte('\n')
+			}
+		case inTag:
+			tag.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	r := strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'")
+	return r.Replace(b.String())
+}
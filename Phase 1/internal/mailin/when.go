@@ -0,0 +1,97 @@
+package mailin
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// whenLabel is an optional label in front of the date of an email.
+	whenLabel = regexp.MustCompile(`^(when|date|start|starts|time)\s*:\s*`)
+	// ordinal matches day suffixes such as the "th" of "20th".
+	ordinal = regexp.MustCompile(`\b(\d{1,2})(st|nd|rd|th)\b`)
+	// spacedMeridiem matches "7 pm" so it can be written "7pm".
+	spacedMeridiem = regexp.MustCompile(`\b(\d{1,2}(:\d{2})?) (am|pm)\b`)
+	weekdays       = strings.NewReplacer("monday", "", "tuesday", "", "wednesday", "", "thursday", "", "friday", "", "saturday", "", "sunday", "",
+		"mon ", "", "tue ", "", "wed ", "", "thu ", "", "fri ", "", "sat ", "", "sun ", "")
+	filler = strings.NewReplacer(",", " ", " at ", " ", " on ", " ", "@", " ")
+)
+
+var (
+	// datedLayouts and yearlessLayouts are the dates FindTime reads; month
+	// names match in any case. Slashed dates are left out since 3/4 is March
+	// 4th to some and April 3rd to others.
+	datedLayouts    = []string{"2006-01-02", "Jan 2 2006", "January 2 2006", "2 Jan 2006", "2 January 2006"}
+	yearlessLayouts = []string{"Jan 2", "January 2", "2 Jan", "2 January"}
+	timeLayouts     = []string{"15:04", "3:04pm", "3pm"}
+)
+
+// FindTime returns the first date and time written in text, one line at a
+// time, with the line it was found on. Both a date and a time of day are
+// needed. Times without a zone are in loc; dates without a year are the next
+// such date after now.
+func FindTime(text string, loc *time.Location, now time.Time) (time.Time, string, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		if t, ok := parseLine(line, loc, now); ok {
+			return t, line, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseLine looks for a date and time among the words of a line, longest
+// run of words first so "jan 2 2027 7pm" is not read as "jan 2 7pm".
+func parseLine(line string, loc *time.Location, now time.Time) (time.Time, bool) {
+	s := strings.ToLower(strings.TrimSpace(line))
+	s = whenLabel.ReplaceAllString(s, "")
+	for _, token := range strings.Fields(s) {
+		if t, err := time.Parse(time.RFC3339, strings.ToUpper(token)); err == nil {
+			return t, true
+		}
+		if t, err := time.ParseInLocation("2006-01-02T15:04", strings.ToUpper(token), loc); err == nil {
+			return t, true
+		}
+	}
+	s = " " + s + " "
+	s = filler.Replace(weekdays.Replace(s))
+	s = ordinal.ReplaceAllString(s, "$1")
+	s = spacedMeridiem.ReplaceAllString(s, "$1$3")
+	words := strings.Fields(s)
+	for n := min(5, len(words)); n >= 2; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			if t, ok := parseWords(strings.Join(words[i:i+n], " "), loc, now); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseWords parses a date and a time of day, in either order.
+func parseWords(s string, loc *time.Location, now time.Time) (time.Time, bool) {
+	for _, tl := range timeLayouts {
+		for _, dl := range datedLayouts {
+			for _, layout := range []string{dl + " " + tl, tl + " " + dl} {
+				if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+					return t, true
+				}
+			}
+		}
+		for _, dl := range yearlessLayouts {
+			for _, layout := range []string{dl + " " + tl, tl + " " + dl} {
+				t, err := time.ParseInLocation(layout, s, loc)
+				if err != nil {
+					continue
+				}
+				now := now.In(loc)
+				t = time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+				if t.Before(now) {
+					t = t.AddDate(1, 0, 0)
+				}
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,633 @@
+// Package markdown renders the Markdown users write in event descriptions,
+// announcements and comments as HTML that is safe to put into a page, so
+// clients need not each render, and sanitize, it themselves.
+//
+// It reads the common part of CommonMark: paragraphs, # headings, *emphasis*,
+// **strong**, ~~strikethrough~~, `code` and fenced code blocks, > quotes,
+// bullet and numbered lists, which may nest, --- rules, [links](url) and
+// <autolinks>, and bare http(s) URLs. The output is safe by construction
+// rather than filtered: all text is escaped, so HTML in the source shows as
+// written, only the tags of those elements are produced, and links only
+// lead to http, https and mailto URLs or relative ones. Images become links
+// to the image, so that reading a comment does not load anything from a
+// server its author picked.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxDepth bounds how deeply quotes and lists nest; deeper ones are read
+// as text.
+const maxDepth = 16
+
+// maxLinkText and maxLinkURL bound how far a [ is searched for the rest
+// of a link.
+const (
+	maxLinkText = 1000
+	maxLinkURL  = 2000
+)
+
+// linkRel keeps rendered user links from passing on ranking, the referring
+// page or a handle on the window.
+const linkRel = `rel="nofollow ugc noopener noreferrer"`
+
+var (
+	headingLine = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))??(?:[ \t]+#+)?[ \t]*$`)
+	ruleLine    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceLine   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*?)[ \t]*$")
+	quoteLine   = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	itemLine    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])(?:[ \t]+(.*))?$`)
+	// codeLanguage is an info string usable as a class name.
+	codeLanguage = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+	autolink     = regexp.MustCompile(`^<((?i:https?|mailto):[^\s<>]*|[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})>`)
+	bareURL      = regexp.MustCompile(`^(?i:https?)://[^\s<>"]+`)
+)
+
+// Render returns src as HTML, or "" for blank src.
+func Render(src string) string {
+	src = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\x00", "�").Replace(src)
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	var b strings.Builder
+	renderBlocks(&b, lines, false, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderedLink finds the http(s) links in Render's output.
+var renderedLink = regexp.MustCompile(`<a href="((?i:https?)://[^"]*)"`)
+
+// Links returns the distinct http(s) URLs src links to, in order: those of
+// links, autolinks and bare URLs, not of URLs in code.
+func Links(src string) []string {
+	var links []string
+	seen := map[string]bool{}
+	for _, m := range renderedLink.FindAllStringSubmatch(Render(src), -1) {
+		u := html.UnescapeString(m[1])
+		if !seen[u] {
+			seen[u] = true
+			links = append(links, u)
+		}
+	}
+	return links
+}
+
+// expandTabs turns the tabs indenting a line into spaces, to the next
+// multiple of four.
+func expandTabs(line string) string {
+	n := 0
+	for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
+	}
+	if !strings.Contains(line[:n], "\t") {
+		return line
+	}
+	width := 0
+	for _, c := range line[:n] {
+		if c == '\t' {
+			width += 4 - width%4
+		} else {
+			width++
+		}
+	}
+	return strings.Repeat(" ", width) + line[n:]
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// interrupts reports whether line starts a block that ends a paragraph. As
+// in CommonMark, a numbered list must start at 1 to do so, so that a line
+// such as "2024. What a year." stays in its paragraph.
+func interrupts(line string) bool {
+	if fenceLine.MatchString(line) || headingLine.MatchString(line) || ruleLine.MatchString(line) || quoteLine.MatchString(line) {
+		return true
+	}
+	m := itemLine.FindStringSubmatch(line)
+	if m == nil || strings.TrimSpace(m[3]) == "" {
+		return false
+	}
+	n, err := strconv.Atoi(strings.TrimRight(m[2], ".)"))
+	return err != nil || n == 1
+}
+
+// renderBlocks writes lines as blocks. Paragraphs of tight list items are
+// written without <p>.
+func renderBlocks(b *strings.Builder, lines []string, tight bool, depth int) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fenceLine.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingLine.MatchString(line):
+			m := headingLine.FindStringSubmatch(line)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			b.WriteString("<" + tag + ">")
+			renderInline(b, strings.TrimSpace(m[2]), true)
+			b.WriteString("</" + tag + ">\n")
+			i++
+		case ruleLine.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case depth < maxDepth && quoteLine.MatchString(line):
+			i = renderQuote(b, lines, i, depth)
+		case depth < maxDepth && itemLine.MatchString(line) && !isBlank(itemLine.FindStringSubmatch(line)[3]):
+			i = renderList(b, lines, i, depth)
+		default:
+			i = renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+// renderFence writes the fenced code block starting at lines[i], which
+// runs to its closing fence or the end, and returns the line after it.
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceLine.FindStringSubmatch(lines[i])
+	open, fence := len(m[1]), m[2]
+	b.WriteString("<pre><code")
+	if info := strings.Fields(m[3]); len(info) > 0 && codeLanguage.MatchString(info[0]) {
+		b.WriteString(` class="language-` + html.EscapeString(info[0]) + `"`)
+	}
+	b.WriteString(">")
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if close := strings.TrimSpace(line); indent(line) <= 3 && len(close) >= len(fence) && strings.Trim(close, fence[:1]) == "" {
+			i++
+			break
+		}
+		b.WriteString(html.EscapeString(line[min(open, indent(line)):]) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderQuote writes the block quote starting at lines[i], including the
+// unmarked lines that continue its last paragraph, and returns the line
+// after it.
+func renderQuote(b *strings.Builder, lines []string, i, depth int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		if m := quoteLine.FindStringSubmatch(lines[i]); m != nil {
+			inner = append(inner, m[1])
+			continue
+		}
+		if isBlank(lines[i]) || isBlank(inner[len(inner)-1]) || interrupts(lines[i]) {
+			break
+		}
+		inner = append(inner, lines[i])
+	}
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner, false, depth+1)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// renderList writes the list starting at lines[i] and returns the line
+// after it. Its items go on with lines indented by two spaces or more, and
+// with unindented lines continuing their last paragraph. A blank line
+// between items, or between the blocks of one, makes the list loose: its
+// paragraphs are written with <p>.
+func renderList(b *strings.Builder, lines []string, i, depth int) int {
+	first := itemLine.FindStringSubmatch(lines[i])
+	kind := first[2][len(first[2])-1:]
+	tag, start := "ul", ""
+	if n, err := strconv.Atoi(strings.TrimRight(first[2], ".)")); err == nil {
+		tag = "ol"
+		if n != 1 {
+			start = ` start="` + strconv.Itoa(n) + `"`
+		}
+	}
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		m := itemLine.FindStringSubmatch(lines[i])
+		if m == nil || ruleLine.MatchString(lines[i]) || m[2][len(m[2])-1:] != kind || isBlank(m[3]) {
+			break
+		}
+		base, width := len(m[1]), len(m[1])+len(m[2])+1
+		item := []string{m[3]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				j := i
+				for j < len(lines) && isBlank(lines[j]) {
+					j++
+				}
+				if j < len(lines) && indent(lines[j]) >= base+2 {
+					loose = true
+					item = append(item, lines[i:j]...)
+					i = j - 1
+					continue
+				}
+				if j < len(lines) {
+					if next := itemLine.FindStringSubmatch(lines[j]); next != nil && next[2][len(next[2])-1:] == kind && !isBlank(next[3]) && !ruleLine.MatchString(lines[j]) {
+						loose = true
+						i = j
+					}
+				}
+				break
+			}
+			if n := indent(line); n >= base+2 {
+				item = append(item, line[min(n, width):])
+				continue
+			}
+			if itemLine.MatchString(line) || interrupts(line) || isBlank(item[len(item)-1]) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+		items = append(items, item)
+		if i < len(lines) && isBlank(lines[i]) {
+			break
+		}
+	}
+	b.WriteString("<" + tag + start + ">\n")
+	for _, item := range items {
+		var inner strings.Builder
+		renderBlocks(&inner, item, !loose, depth+1)
+		b.WriteString("<li>" + strings.TrimSuffix(inner.String(), "\n") + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderParagraph writes the paragraph starting at lines[i] and returns
+// the line after it. Lines ending in two spaces or a backslash break.
+func renderParagraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	j := i
+	for j < len(lines) && !isBlank(lines[j]) && (j == i || !interrupts(lines[j])) {
+		j++
+	}
+	text := make([]string, 0, j-i)
+	for k, line := range lines[i:j] {
+		line = strings.TrimLeft(line, " ")
+		trimmed := strings.TrimRight(line, " ")
+		if len(line)-len(trimmed) >= 2 && k < j-i-1 {
+			trimmed += `\`
+		}
+		text = append(text, trimmed)
+	}
+	if !tight {
+		b.WriteString("<p>")
+	}
+	renderInline(b, strings.Join(text, "\n"), true)
+	if !tight {
+		b.WriteString("</p>")
+	}
+	b.WriteString("\n")
+	return j
+}
+
+// renderInline writes the spans of s, escaping the text between them. Link
+// text is written with links false, as links do not nest.
+func renderInline(b *strings.Builder, s string, links bool) {
+	plain := 0
+	unclosed := map[string]int{}
+	for i := 0; i < len(s); {
+		out, end := span(s, i, links, unclosed)
+		if end == 0 {
+			i += skip(s, i)
+			continue
+		}
+		b.WriteString(html.EscapeString(s[plain:i]))
+		b.WriteString(out)
+		i, plain = end, end
+	}
+	b.WriteString(html.EscapeString(s[plain:]))
+}
+
+// skip returns how far to move on from s[i] when no span starts there:
+// past the whole run of a delimiter, so that its second character is not
+// taken for one of its own.
+func skip(s string, i int) int {
+	switch s[i] {
+	case '*', '_', '~', '`':
+		return runLength(s, i)
+	}
+	return 1
+}
+
+// span renders the span starting at s[i], if any, and returns it with the
+// index after it, or 0 when none starts there. unclosed remembers, by
+// delimiter run, from where on nothing closes it, so that text full of
+// stray asterisks is not searched to its end for each one.
+func span(s string, i int, links bool, unclosed map[string]int) (string, int) {
+	switch c := s[i]; {
+	case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+		return "<br>\n", i + 2
+	case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+		return html.EscapeString(s[i+1 : i+2]), i + 2
+	case c == '`':
+		run := runLength(s, i)
+		end := closingCode(s, i+run, run)
+		if end < 0 {
+			return "", 0
+		}
+		code := strings.ReplaceAll(s[i+run:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		return "<code>" + html.EscapeString(code) + "</code>", end + run
+	case c == '<' && links:
+		m := autolink.FindStringSubmatch(s[i:])
+		if m == nil {
+			return "", 0
+		}
+		href := m[1]
+		if !strings.Contains(href, ":") {
+			href = "mailto:" + href
+		}
+		return link(href, "", html.EscapeString(m[1])), i + len(m[0])
+	case c == '!' && links && i+1 < len(s) && s[i+1] == '[':
+		text, href, title, end, ok := parseLink(s, i+1)
+		if !ok {
+			return "", 0
+		}
+		if text == "" {
+			text = href
+		}
+		return link(href, title, html.EscapeString(text)), end
+	case c == '[' && links:
+		text, href, title, end, ok := parseLink(s, i)
+		if !ok {
+			return "", 0
+		}
+		var inner strings.Builder
+		renderInline(&inner, text, false)
+		return link(href, title, inner.String()), end
+	case c == '*' || c == '_' || c == '~':
+		return emphasis(s, i, links, unclosed)
+	case (c == 'h' || c == 'H') && links && !wordBefore(s, i):
+		m := bareURL.FindString(s[i:])
+		if m == "" {
+			return "", 0
+		}
+		m = trimURL(m)
+		return link(m, "", html.EscapeString(m)), i + len(m)
+	}
+	return "", 0
+}
+
+// emphasisTags are the tags around text between delimiter runs of each
+// length.
+var emphasisTags = map[int][2]string{
+	1: {"<em>", "</em>"},
+	2: {"<strong>", "</strong>"},
+	3: {"<em><strong>", "</strong></em>"},
+}
+
+// emphasis renders the text between the delimiter run at s[i] and the next
+// one like it: one * or _ for emphasis, two for strong, three for both and
+// ~~ for strikethrough. Runs must hug the text and _ must not be inside a
+// word, so that 2 * 3 * 4 and snake_case_names stay as written.
+func emphasis(s string, i int, links bool, unclosed map[string]int) (string, int) {
+	c, run := s[i], runLength(s, i)
+	tags, ok := emphasisTags[run]
+	if c == '~' {
+		tags, ok = [2]string{"<del>", "</del>"}, run == 2
+	}
+	start := i + run
+	if !ok || start >= len(s) || (c == '_' && wordBefore(s, i)) {
+		return "", 0
+	}
+	if r, _ := utf8.DecodeRuneInString(s[start:]); unicode.IsSpace(r) {
+		return "", 0
+	}
+	key := s[i:start]
+	if from, ok := unclosed[key]; ok && start >= from {
+		return "", 0
+	}
+	end := closingDelimiter(s, start, c, run)
+	if end < 0 {
+		unclosed[key] = start
+		return "", 0
+	}
+	var inner strings.Builder
+	renderInline(&inner, s[start:end], links)
+	return tags[0] + inner.String() + tags[1], end + run
+}
+
+// closingDelimiter returns the index of the first run of run c's after
+// from that can close emphasis, or -1. Runs inside code spans do not count.
+func closingDelimiter(s string, from int, c byte, run int) int {
+	for j := from; j < len(s); {
+		switch s[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			r := runLength(s, j)
+			if end := closingCode(s, j+r, r); end >= 0 {
+				j = end + r
+			} else {
+				j += r
+			}
+			continue
+		case c:
+			r := runLength(s, j)
+			prev, _ := utf8.DecodeLastRuneInString(s[:j])
+			if r == run && j > from && !unicode.IsSpace(prev) && (c != '_' || !wordAt(s, j+r)) {
+				return j
+			}
+			j += r
+			continue
+		}
+		j++
+	}
+	return -1
+}
+
+// closingCode returns the index of the run of exactly run backticks that
+// closes a code span opened before from, or -1.
+func closingCode(s string, from, run int) int {
+	for j := from; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		r := runLength(s, j)
+		if r == run {
+			return j
+		}
+		j += r
+	}
+	return -1
+}
+
+// parseLink reads the [text](destination "title") at s[i]. Reference links
+// are not supported and stay text.
+func parseLink(s string, i int) (text, href, title string, end int, ok bool) {
+	closeText := -1
+	for j, depth := i, 0; j < min(len(s), i+maxLinkText) && closeText < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				closeText = j
+			}
+		}
+	}
+	if closeText < 0 || closeText+1 >= len(s) || s[closeText+1] != '(' {
+		return "", "", "", 0, false
+	}
+	j := skipSpaces(s, closeText+2)
+	if j < len(s) && s[j] == '<' {
+		e := strings.IndexAny(s[j+1:], ">\n")
+		if e < 0 || e > maxLinkURL || s[j+1+e] != '>' {
+			return "", "", "", 0, false
+		}
+		href = s[j+1 : j+1+e]
+		j += e + 2
+	} else {
+		start, parens := j, 0
+		for ; j < len(s) && j-start <= maxLinkURL && s[j] != ' ' && s[j] != '\t' && s[j] != '\n'; j++ {
+			if s[j] == '\\' {
+				j++
+			} else if s[j] == '(' {
+				parens++
+			} else if s[j] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		if j-start > maxLinkURL {
+			return "", "", "", 0, false
+		}
+		href = s[start:min(j, len(s))]
+	}
+	j = skipSpaces(s, j)
+	if j < len(s) && (s[j] == '"' || s[j] == '\'') {
+		e := strings.IndexByte(s[j+1:], s[j])
+		if e < 0 || e > maxLinkText {
+			return "", "", "", 0, false
+		}
+		title = s[j+1 : j+1+e]
+		j = skipSpaces(s, j+e+2)
+	}
+	if j >= len(s) || s[j] != ')' {
+		return "", "", "", 0, false
+	}
+	return s[i+1 : closeText], unescape(href), unescape(title), j + 1, true
+}
+
+// link returns inner linked to href, or inner alone when href may not be
+// linked to.
+func link(href, title, inner string) string {
+	href, ok := safeURL(href)
+	if !ok {
+		return inner
+	}
+	a := `<a href="` + html.EscapeString(href) + `"`
+	if title != "" {
+		a += ` title="` + html.EscapeString(title) + `"`
+	}
+	return a + " " + linkRel + ">" + inner + "</a>"
+}
+
+// safeURL returns href if a link may lead there: to an http, https or
+// mailto URL, or a relative one. Control characters, which browsers drop
+// from "java\nscript:", are refused outright.
+func safeURL(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return "", false
+	}
+	for _, r := range href {
+		if r < 0x20 || r == 0x7f {
+			return "", false
+		}
+	}
+	if i := strings.IndexAny(href, ":/?#"); i >= 0 && href[i] == ':' {
+		switch strings.ToLower(href[:i]) {
+		case "http", "https", "mailto":
+		default:
+			return "", false
+		}
+	}
+	return strings.ReplaceAll(href, " ", "%20"), true
+}
+
+// trimURL drops the punctuation that ends a sentence, or closes the
+// parentheses around a bare URL, from its end.
+func trimURL(u string) string {
+	for len(u) > 0 {
+		last := u[len(u)-1]
+		if strings.IndexByte(".,:;!?'*_~", last) < 0 && (last != ')' || strings.Count(u, "(") >= strings.Count(u, ")")) {
+			break
+		}
+		u = u[:len(u)-1]
+	}
+	return u
+}
+
+// unescape removes the backslashes escaping punctuation.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func runLength(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordBefore reports whether s[i] follows a letter or digit.
+func wordBefore(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return i > 0 && isWord(r)
+}
+
+// wordAt reports whether s[i] starts with a letter or digit.
+func wordAt(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return i < len(s) && isWord(r)
+}
@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const rel = ` rel="nofollow ugc noopener noreferrer"`
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		// Links only lead to http, https and mailto URLs or relative ones.
+		{"javascript link", "[x](javascript:alert(1))", "<p>x</p>"},
+		{"javascript link, mixed case", "[x](JavaScript:alert(1))", "<p>x</p>"},
+		{"javascript link, padded", "[x](  javascript:alert(1))", "<p>x</p>"},
+		{"javascript link in brackets", "[x](<javascript:alert(1)>)", "<p>x</p>"},
+		{"javascript link split by a tab", "[x](<java\tscript:alert(1)>)", "<p>x</p>"},
+		{"javascript image", "![x](javascript:alert(1))", "<p>x</p>"},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>"},
+		{"data link", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>"},
+		{"vbscript link", "[x](vbscript:msgbox)", "<p>x</p>"},
+		{"entity in scheme", "[x](java&#115;cript:alert(1))", `<p><a href="java&amp;#115;cript:alert(1)"` + rel + `>x</a></p>`},
+		{"https link", "[x](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2"` + rel + `>x</a></p>`},
+		{"relative link", "[x](/events/1)", `<p><a href="/events/1"` + rel + `>x</a></p>`},
+		{"mailto link", "[x](mailto:ann@example.com)", `<p><a href="mailto:ann@example.com"` + rel + `>x</a></p>`},
+		{"image becomes a link", "![cat](https://example.com/cat.png)", `<p><a href="https://example.com/cat.png"` + rel + `>cat</a></p>`},
+
+		// HTML in the source shows as written.
+		{"script tag", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"event handler", "<img src=x onerror=alert(1)>", "<p>&lt;img src=x onerror=alert(1)&gt;</p>"},
+		{"anchor tag", `<a href="javascript:alert(1)">x</a>`, "<p>&lt;a href=&#34;javascript:alert(1)&#34;&gt;x&lt;/a&gt;</p>"},
+		{"html in link text", "[<b>x</b>](https://example.com)", `<p><a href="https://example.com"` + rel + `>&lt;b&gt;x&lt;/b&gt;</a></p>`},
+		{"html in code span", "`<script>`", "<p><code>&lt;script&gt;</code></p>"},
+		{"html in code block", "```\n<script>\n```", "<pre><code>&lt;script&gt;\n</code></pre>"},
+
+		// Nothing breaks out of an attribute.
+		{"quote in destination", `[x](<https://example.com/" onmouseover="alert(1)>)`,
+			`<p><a href="https://example.com/&#34;%20onmouseover=&#34;alert(1)"` + rel + `>x</a></p>`},
+		{"quote in title", `[x](https://example.com '" onclick="alert(1)')`,
+			`<p><a href="https://example.com" title="&#34; onclick=&#34;alert(1)"` + rel + `>x</a></p>`},
+		{"quote after bare url", `https://example.com/?q="<script>`,
+			`<p><a href="https://example.com/?q="` + rel + `>https://example.com/?q=</a>&#34;&lt;script&gt;</p>`},
+		{"quote in code language", "```js\" onload=\"alert(1)\nx\n```", "<pre><code>x\n</code></pre>"},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.src); got != tt.want {
+			t.Errorf("%s: Render(%q)\n got  %s\n want %s", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"blank", " \n\t\n", ""},
+		{"emphasis", "*a* **b** ***c*** ~~d~~", "<p><em>a</em> <strong>b</strong> <em><strong>c</strong></em> <del>d</del></p>"},
+		{"arithmetic", "2 * 3 * 4", "<p>2 * 3 * 4</p>"},
+		{"snake case", "snake_case_names", "<p>snake_case_names</p>"},
+		{"heading", "## Agenda ##", "<h2>Agenda</h2>"},
+		{"year does not start a list", "Looking back\n2024. What a year.", "<p>Looking back\n2024. What a year.</p>"},
+		{"tight list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>"},
+		{"numbered list", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"quote", "> a\nb", "<blockquote>\n<p>a\nb</p>\n</blockquote>"},
+		{"hard break", "a  \nb", "<p>a<br>\nb</p>"},
+		{"bare url before a period", "See https://example.com.", `<p>See <a href="https://example.com"` + rel + `>https://example.com</a>.</p>`},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.src); got != tt.want {
+			t.Errorf("%s: Render(%q)\n got  %s\n want %s", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRenderBoundsNesting(t *testing.T) {
+	src := strings.Repeat(">", 10000) + " deep"
+	if got := Render(src); strings.Count(got, "<blockquote>") != maxDepth {
+		t.Errorf("Render nested %d quotes, want %d", strings.Count(got, "<blockquote>"), maxDepth)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	src := "[a](https://example.com/a) <https://example.com/b> https://example.com/a `https://example.com/c` [d](javascript:x)"
+	got := Links(src)
+	want := []string{"https://example.com/a", "https://example.com/b"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Links = %q, want %q", got, want)
+	}
+}
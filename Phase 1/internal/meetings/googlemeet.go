@@ -0,0 +1,120 @@
+package meetings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoogleMeet creates Meet links by inserting a Google Calendar event with a
+// conference request. It authenticates with an OAuth refresh token for the
+// calendar owner; an empty calendarID uses "primary".
+type GoogleMeet struct {
+	clientID     string
+	clientSecret string
+	refreshToken string
+	calendarID   string
+	tokenURL     string
+	apiURL       string
+	client       *http.Client
+}
+
+func NewGoogleMeet(clientID, clientSecret, refreshToken, calendarID string) *GoogleMeet {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	return &GoogleMeet{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		refreshToken: refreshToken,
+		calendarID:   calendarID,
+		tokenURL:     "https://oauth2.googleapis.com/token",
+		apiURL:       "https://www.googleapis.com/calendar/v3",
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *GoogleMeet) CreateMeeting(ctx context.Context, m Meeting) (string, error) {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	end := m.Start.Add(durationOf(m))
+	body, err := json.Marshal(map[string]any{
+		"summary": m.Topic,
+		"start":   map[string]string{"dateTime": m.Start.Format(time.RFC3339)},
+		"end":     map[string]string{"dateTime": end.Format(time.RFC3339)},
+		"conferenceData": map[string]any{
+			"createRequest": map[string]any{
+				"requestId":             strconv.FormatInt(time.Now().UnixNano(), 36),
+				"conferenceSolutionKey": map[string]string{"type": "hangoutsMeet"},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	endpoint := g.apiURL + "/calendars/" + url.PathEscape(g.calendarID) + "/events?conferenceDataVersion=1"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("meet: unexpected status %d creating event", resp.StatusCode)
+	}
+	var created struct {
+		HangoutLink string `json:"hangoutLink"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("meet: %w", err)
+	}
+	if created.HangoutLink == "" {
+		return "", fmt.Errorf("meet: response has no hangoutLink")
+	}
+	return created.HangoutLink, nil
+}
+
+// accessToken exchanges the refresh token for a short-lived access token.
+func (g *GoogleMeet) accessToken(ctx context.Context) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("client_id", g.clientID)
+	form.Set("client_secret", g.clientSecret)
+	form.Set("refresh_token", g.refreshToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("meet: unexpected status %d requesting token", resp.StatusCode)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("meet: %w", err)
+	}
+	return tok.AccessToken, nil
+}
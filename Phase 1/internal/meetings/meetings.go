@@ -0,0 +1,54 @@
+// Package meetings creates online meeting rooms for virtual and hybrid events
+// through a pluggable provider.
+package meetings
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrNotConfigured is returned when automatic meeting creation is requested
+// but no provider is configured.
+var ErrNotConfigured = errors.New("no meeting provider configured")
+
+// Meeting describes the meeting to schedule. End is nil for open-ended events.
+type Meeting struct {
+	Topic string
+	Start time.Time
+	End   *time.Time
+}
+
+// Provider schedules a meeting and returns its join URL.
+type Provider interface {
+	CreateMeeting(ctx context.Context, m Meeting) (string, error)
+}
+
+// NewFromEnv selects a provider from MEETING_PROVIDER ("zoom", "meet" or "none").
+// Automatic meeting creation is disabled unless a provider is configured.
+func NewFromEnv() Provider {
+	switch os.Getenv("MEETING_PROVIDER") {
+	case "zoom":
+		return NewZoom(os.Getenv("ZOOM_ACCOUNT_ID"), os.Getenv("ZOOM_CLIENT_ID"), os.Getenv("ZOOM_CLIENT_SECRET"))
+	case "meet":
+		return NewGoogleMeet(os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET"), os.Getenv("GOOGLE_REFRESH_TOKEN"), os.Getenv("GOOGLE_CALENDAR_ID"))
+	default:
+		return Noop{}
+	}
+}
+
+// Noop refuses to create meetings; organizers must paste a meeting URL.
+type Noop struct{}
+
+func (Noop) CreateMeeting(ctx context.Context, m Meeting) (string, error) {
+	return "", ErrNotConfigured
+}
+
+// durationOf returns the meeting length, defaulting to one hour.
+func durationOf(m Meeting) time.Duration {
+	if m.End != nil && m.End.After(m.Start) {
+		return m.End.Sub(m.Start)
+	}
+	return time.Hour
+}
@@ -0,0 +1,154 @@
+// Package metrics keeps counters and gauges in memory and writes them in
+// the Prometheus text format, for GET /metrics. Values are per server
+// instance.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metric interface {
+	write(w io.Writer)
+}
+
+var (
+	mu         sync.Mutex
+	registered = map[string]metric{}
+)
+
+func register(name string, m metric) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registered[name]; ok {
+		panic("metrics: " + name + " registered twice")
+	}
+	registered[name] = m
+}
+
+// Counter is a value that only goes up, optionally split by labels.
+type Counter struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	values map[string]float64 // by label values joined with \x00
+}
+
+// NewCounter registers a counter. Inc and Add take a value for each label.
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: map[string]float64{}}
+	register(name, c)
+	return c
+}
+
+// Inc adds one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values", c.name, len(c.labels)))
+	}
+	c.mu.Lock()
+	c.values[strings.Join(labelValues, "\x00")] += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	if len(c.labels) == 0 {
+		fmt.Fprintf(w, "%s %s\n", c.name, formatValue(c.values[""]))
+		return
+	}
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s} %s\n", c.name, formatLabels(c.labels, strings.Split(key, "\x00")), formatValue(c.values[key]))
+	}
+}
+
+// GaugeFunc is a value read when the metrics are written.
+type GaugeFunc struct {
+	name, help string
+	value      func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from value.
+func NewGaugeFunc(name, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, value: value}
+	register(name, g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", g.name, g.help, g.name, g.name, formatValue(g.value()))
+}
+
+// CounterFunc is a counter kept elsewhere, read when the metrics are
+// written.
+type CounterFunc struct {
+	name, help string
+	value      func() float64
+}
+
+// NewCounterFunc registers a counter whose value is read from value.
+func NewCounterFunc(name, help string, value func() float64) *CounterFunc {
+	c := &CounterFunc{name: name, help: help, value: value}
+	register(name, c)
+	return c
+}
+
+func (c *CounterFunc) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %s\n", c.name, c.help, c.name, c.name, formatValue(c.value()))
+}
+
+// Write writes every metric, sorted by name.
+func Write(w io.Writer) {
+	mu.Lock()
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ms := make([]metric, len(names))
+	for i, name := range names {
+		ms[i] = registered[name]
+	}
+	mu.Unlock()
+	for _, m := range ms {
+		m.write(w)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatLabels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + strconv.Quote(values[i])
+	}
+	return strings.Join(pairs, ",")
+}
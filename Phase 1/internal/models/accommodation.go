@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Lodging is a place to stay offered for an event, such as a hotel with a
+// block of Rooms reserved. Guests counts the participants staying there.
+type Lodging struct {
+	ID              int        `json:"id"`
+	EventID         int        `json:"eventId"`
+	Name            string     `json:"name"`
+	Address         string     `json:"address"`
+	URL             *string    `json:"url,omitempty"`
+	Notes           string     `json:"notes"`
+	Rooms           *int       `json:"rooms,omitempty"`
+	PriceCents      *int       `json:"priceCents,omitempty"`
+	Currency        string     `json:"currency"`
+	BookingDeadline *time.Time `json:"bookingDeadline,omitempty"`
+	Guests          int        `json:"guests"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// LodgingRequest creates or replaces a lodging option. PriceCents is the
+// nightly rate; BookingDeadline is when the room block is released.
+type LodgingRequest struct {
+	Name            string `json:"name" binding:"required,max=200"`
+	Address         string `json:"address" binding:"max=500"`
+	URL             string `json:"url" binding:"omitempty,url"`
+	Notes           string `json:"notes" binding:"max=2000"`
+	Rooms           *int   `json:"rooms" binding:"omitempty,min=1"`
+	PriceCents      *int   `json:"priceCents" binding:"omitempty,min=0"`
+	Currency        string `json:"currency" binding:"omitempty,len=3,alpha"`
+	BookingDeadline string `json:"bookingDeadline" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// Stay is a participant's accommodation choice for an event. LodgingID is
+// nil when they make their own arrangements. CheckIn and CheckOut are whole
+// days; the participant stays the nights in between.
+type Stay struct {
+	EventID     int       `json:"eventId"`
+	UserID      int       `json:"userId"`
+	Name        string    `json:"name"`
+	LodgingID   *int      `json:"lodgingId"`
+	LodgingName *string   `json:"lodgingName,omitempty"`
+	CheckIn     time.Time `json:"checkIn"`
+	CheckOut    time.Time `json:"checkOut"`
+	Notes       string    `json:"notes"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type StayRequest struct {
+	LodgingID *int   `json:"lodgingId" binding:"omitempty,min=1"`
+	CheckIn   string `json:"checkIn" binding:"required,datetime=2006-01-02"`
+	CheckOut  string `json:"checkOut" binding:"required,datetime=2006-01-02"`
+	Notes     string `json:"notes" binding:"max=1000"`
+}
+
+// NightOccupancy is the number of guests staying on the night starting Date.
+type NightOccupancy struct {
+	Date   time.Time `json:"date"`
+	Guests int       `json:"guests"`
+}
+
+// LodgingOccupancy is how full one lodging is, night by night. LodgingID is
+// nil for participants making their own arrangements.
+type LodgingOccupancy struct {
+	LodgingID  *int             `json:"lodgingId"`
+	Name       string           `json:"name"`
+	Rooms      *int             `json:"rooms,omitempty"`
+	Guests     int              `json:"guests"`
+	PeakGuests int              `json:"peakGuests"`
+	Nights     []NightOccupancy `json:"nights"`
+}
+
+// OccupancySummary is the organizer's view of an event's accommodation.
+// Undecided counts going participants who have not chosen yet.
+type OccupancySummary struct {
+	EventID   int                `json:"eventId"`
+	Lodgings  []LodgingOccupancy `json:"lodgings"`
+	Stays     []Stay             `json:"stays"`
+	Undecided int                `json:"undecided"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Announcement is a message sent to an event's participants. Audience holds
+// the attendance statuses it targeted (going, maybe, not_going, pending);
+// empty means every participant. Body is Markdown, BodyHTML its rendering as
+// HTML that is safe to display. Reactions are the emoji participants reacted
+// with, in the order first used.
+type Announcement struct {
+	ID             int        `json:"id"`
+	EventID        int        `json:"eventId"`
+	AuthorID       int        `json:"authorId"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	BodyHTML       string     `json:"bodyHtml"`
+	Audience       []string   `json:"audience"`
+	RecipientCount int        `json:"recipientCount"`
+	Reactions      []Reaction `json:"reactions"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type AnnouncementRequest struct {
+	Title      string   `json:"title" binding:"required,max=200"`
+	Body       string   `json:"body" binding:"required,max=5000"`
+	Attendance []string `json:"attendance" binding:"omitempty,dive,oneof=going maybe not_going pending"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserBlock keeps invitations from one user, or from every user with an
+// email address at Domain, away from the blocking user.
+type UserBlock struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"-"`
+	BlockedUserID *int      `json:"blockedUserId,omitempty"`
+	BlockedName   string    `json:"blockedUserName,omitempty"`
+	Domain        *string   `json:"domain,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// BlockRequest blocks either a user or an email domain.
+type BlockRequest struct {
+	UserID *int   `json:"userId"`
+	Domain string `json:"domain" binding:"max=253"`
+}
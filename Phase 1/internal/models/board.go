@@ -0,0 +1,39 @@
+package models
+
+// DefaultBoardColumns are the columns an event's task board starts with.
+var DefaultBoardColumns = []string{"To Do", "Doing", "Done"}
+
+// Board is an event's task board: its columns in order, each with its
+// tasks.
+type Board struct {
+	EventID int           `json:"eventId"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardColumn is a column of a task board. Tasks are in their order within
+// the column.
+type BoardColumn struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Tasks    []Task `json:"tasks"`
+}
+
+// BoardColumnsRequest replaces a board's columns. Listed columns with an ID
+// are kept, renamed and put in the listed order, those without one are
+// added, and columns not listed are removed.
+type BoardColumnsRequest struct {
+	Columns []BoardColumnInput `json:"columns" binding:"required,min=1,max=20,dive"`
+}
+
+type BoardColumnInput struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// BoardMoveRequest moves a task to a column and, when Position is given, to
+// that place in the order of the event's tasks (see TaskPosition).
+type BoardMoveRequest struct {
+	ColumnID int      `json:"columnId" binding:"required"`
+	Position *float64 `json:"position"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Branding is how emails about the events a user organizes look to their
+// recipients. Empty fields fall back to the defaults.
+type Branding struct {
+	LogoURL   string     `json:"logoUrl"`
+	Color     string     `json:"color"`
+	ReplyTo   string     `json:"replyTo"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// BrandingRequest replaces the caller's branding; omitted fields are reset.
+type BrandingRequest struct {
+	LogoURL string `json:"logoUrl" binding:"omitempty,url,startswith=https://,max=2048"`
+	Color   string `json:"color" binding:"omitempty,hexcolor"`
+	ReplyTo string `json:"replyTo" binding:"omitempty,email"`
+}
+
+// EmailPreview is an email as its recipient would get it.
+type EmailPreview struct {
+	Subject string `json:"subject"`
+	ReplyTo string `json:"replyTo,omitempty"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// CalendarEntry is one of the caller's events as shown on the calendar.
+type CalendarEntry struct {
+	Event
+	Role       string  `json:"role"`
+	Attendance *string `json:"attendance"`
+}
+
+// CalendarDay holds the entries falling on one day. Multi-day events appear on
+// every day they span.
+type CalendarDay struct {
+	Date   string          `json:"date"`
+	Events []CalendarEntry `json:"events"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// CheckIn is a participant checked in at the event.
+type CheckIn struct {
+	UserID      int       `json:"userId"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	CheckedInAt time.Time `json:"checkedInAt"`
+}
+
+// CertificateTemplate is the wording of an event's attendance certificates.
+// Title and Body may contain {name}, {event} and {date}, which are filled in
+// per participant. UpdatedAt is nil while the event uses the default wording.
+type CertificateTemplate struct {
+	EventID     int        `json:"eventId"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	SignerName  string     `json:"signerName"`
+	SignerTitle string     `json:"signerTitle"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+type CertificateTemplateRequest struct {
+	Title       string `json:"title" binding:"required,max=100"`
+	Body        string `json:"body" binding:"required,max=1000"`
+	SignerName  string `json:"signerName" binding:"max=100"`
+	SignerTitle string `json:"signerTitle" binding:"max=100"`
+}
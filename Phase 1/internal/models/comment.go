@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Comment is a participant's comment on an event, or on one of its tasks
+// when TaskID is set. UserID is nil once the author's account is deleted.
+// Body is Markdown, BodyHTML its rendering as HTML that is safe to display,
+// LinkPreviews are cards for the pages it links to, and Reactions the emoji
+// participants reacted with, in the order first used.
+type Comment struct {
+	ID           int           `json:"id"`
+	EventID      int           `json:"eventId"`
+	TaskID       *int          `json:"taskId,omitempty"`
+	UserID       *int          `json:"userId"`
+	UserName     string        `json:"userName,omitempty"`
+	Body         string        `json:"body"`
+	BodyHTML     string        `json:"bodyHtml"`
+	Mentions     []Mention     `json:"mentions"`
+	LinkPreviews []LinkPreview `json:"linkPreviews"`
+	Reactions    []Reaction    `json:"reactions"`
+	CreatedAt    time.Time     `json:"createdAt"`
+}
+
+// Mention is a participant a comment mentions.
+type Mention struct {
+	UserID int    `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// CommentRequest posts a comment. Participants are mentioned with @ and
+// their email, or their name written without spaces.
+type CommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
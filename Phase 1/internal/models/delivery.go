@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Statuses of an email delivery, from least to most conclusive. A delivery
+// only moves to a later status, so a late "delivered" from the provider does
+// not hide that the email was already opened.
+const (
+	DeliveryPending   = "pending"
+	DeliveryFailed    = "failed"
+	DeliverySent      = "sent"
+	DeliveryDelivered = "delivered"
+	DeliveryBounced   = "bounced"
+	DeliveryOpened    = "opened"
+)
+
+// EmailDelivery is an email sent to one recipient for a notification: how
+// often sending was attempted and what became of it. Sent means the mail
+// server accepted it; delivered, bounced and opened are reported by the mail
+// provider or the tracking pixel.
+type EmailDelivery struct {
+	ID          int        `json:"id"`
+	MessageID   string     `json:"-"`
+	Token       string     `json:"-"`
+	EventID     *int       `json:"eventId,omitempty"`
+	UserID      *int       `json:"userId,omitempty"`
+	UserName    string     `json:"userName,omitempty"`
+	Email       string     `json:"email"`
+	Kind        string     `json:"kind"`
+	Subject     string     `json:"subject"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"lastError,omitempty"`
+	SentAt      *time.Time `json:"sentAt,omitempty"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+	BouncedAt   *time.Time `json:"bouncedAt,omitempty"`
+	OpenedAt    *time.Time `json:"openedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// DeliveryFilter narrows the deliveries of an event.
+type DeliveryFilter struct {
+	Kind   string
+	Status string
+	UserID int
+}
+
+// DeliveryReport is an outcome of an email reported by the mail provider. ID
+// is the email's Message-ID or its X-Delivery-Token header.
+type DeliveryReport struct {
+	Type   string     `json:"type" binding:"required,oneof=delivered bounced opened failed"`
+	ID     string     `json:"id" binding:"required"`
+	Reason string     `json:"reason"`
+	At     *time.Time `json:"at"`
+}
+
+type DeliveryReportsRequest struct {
+	Events []DeliveryReport `json:"events" binding:"required,dive"`
+}
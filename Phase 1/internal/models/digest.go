@@ -0,0 +1,10 @@
+package models
+
+import "encoding/json"
+
+// DigestBatch is the notifications waiting to be sent together to one
+// recipient on one channel, oldest first.
+type DigestBatch struct {
+	Key   string
+	Items []json.RawMessage
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// EditLock tells co-organizers that someone is editing the event. It lapses
+// at ExpiresAt unless its holder renews it.
+type EditLock struct {
+	EventID    int       `json:"eventId"`
+	UserID     int       `json:"userId"`
+	UserName   string    `json:"userName"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// EditLockRequest takes or renews an edit lock for TTLSeconds (default 120).
+type EditLockRequest struct {
+	TTLSeconds int `json:"ttlSeconds" binding:"omitempty,min=10,max=600"`
+}
@@ -2,20 +2,137 @@ package models
 
 import "time"
 
+// Event types. Virtual and hybrid events may carry a meeting URL.
+const (
+	EventTypeInPerson = "in_person"
+	EventTypeVirtual  = "virtual"
+	EventTypeHybrid   = "hybrid"
+)
+
+// Event is an event as its participants see it. Description is Markdown,
+// DescriptionHTML its rendering as HTML that is safe to display.
+// RSVPVisibility is what participants who cannot manage participants see of
+// its RSVPs: full, counts or none.
 type Event struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Location    string    `json:"location"`
-	StartTime   time.Time `json:"startTime"`
-	OrganizerID int       `json:"organizerId"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID                    int          `json:"id"`
+	Title                 string       `json:"title"`
+	Description           string       `json:"description"`
+	DescriptionHTML       string       `json:"descriptionHtml"`
+	Location              string       `json:"location"`
+	VenueID               *int         `json:"venueId"`
+	Venue                 *Venue       `json:"venue,omitempty"`
+	StartTime             time.Time    `json:"startTime"`
+	EndTime               *time.Time   `json:"endTime"`
+	Type                  string       `json:"type"`
+	MeetingURL            *string      `json:"meetingUrl,omitempty"`
+	Permissions           []Permission `json:"permissions,omitempty"`
+	AllowTransfers        bool         `json:"allowTransfers"`
+	AutoNudgeDays         *int         `json:"autoNudgeDays,omitempty"`
+	RequireChangeApproval bool         `json:"requireChangeApproval"`
+	RSVPVisibility        string       `json:"rsvpVisibility"`
+	Slug                  string       `json:"slug"`
+	PublishedAt           *time.Time   `json:"publishedAt,omitempty"`
+	ArchivedAt            *time.Time   `json:"archivedAt,omitempty"`
+	HiddenAt              *time.Time   `json:"hiddenAt,omitempty"`
+	CancelledAt           *time.Time   `json:"cancelledAt,omitempty"`
+	SeriesID              *int         `json:"seriesId,omitempty"`
+	OrganizerID           int          `json:"organizerId"`
+	CreatedAt             time.Time    `json:"createdAt"`
+	UpdatedAt             time.Time    `json:"updatedAt"`
+}
+
+// EventSummary is an event with aggregate counts, as returned by the dashboard listings.
+// ParticipantCount and GoingCount are nil when the event's RSVPs are hidden from the viewer.
+type EventSummary struct {
+	Event
+	ParticipantCount *int `json:"participantCount,omitempty"`
+	GoingCount       *int `json:"goingCount,omitempty"`
+	TaskCount        int  `json:"taskCount"`
+}
+
+// EventDetails is an event hydrated with related data requested via ?include=.
+// LinkPreviews are cards for the pages its description links to.
+type EventDetails struct {
+	Event
+	Participants []Participant `json:"participants,omitempty"`
+	Tasks        []Task        `json:"tasks,omitempty"`
+	LinkPreviews []LinkPreview `json:"linkPreviews,omitempty"`
+}
+
+// EventListFilter holds the query parameters accepted by the organized/invited listings.
+type EventListFilter struct {
+	Window     string `form:"window" binding:"omitempty,oneof=upcoming past"`
+	Sort       string `form:"sort" binding:"omitempty,oneof=start_time created_at title"`
+	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Attendance string `form:"attendance" binding:"omitempty,oneof=going maybe not_going pending"`
+	// Archived lists archived events instead of the active ones.
+	Archived bool `form:"archived"`
 }
 
 type CreateEventRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	Location    string `json:"location"`
-	StartTime   string `json:"startTime" binding:"required"`
+	Title          string `json:"title" binding:"required"`
+	Description    string `json:"description"`
+	Location       string `json:"location"`
+	VenueID        *int   `json:"venueId"`
+	StartTime      string `json:"startTime" binding:"required"`
+	EndTime        string `json:"endTime"`
+	Type           string `json:"type" binding:"omitempty,oneof=in_person virtual hybrid"`
+	MeetingURL     string `json:"meetingUrl" binding:"omitempty,url"`
+	CreateMeeting  bool   `json:"createMeeting"`
+	AllowTransfers bool   `json:"allowTransfers"`
+	TaskTemplate   string `json:"taskTemplate"`
+	TaskTemplateID *int   `json:"taskTemplateId"`
+	// AllowDuplicate creates the event even if it looks like one the
+	// organizer already has.
+	AllowDuplicate bool `json:"allowDuplicate"`
+}
+
+// DuplicateCandidate is an existing event of the organizer that a new one
+// may duplicate: its time overlaps and its title is Similarity alike (0-1).
+type DuplicateCandidate struct {
+	EventID    int        `json:"eventId"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	StartTime  time.Time  `json:"startTime"`
+	EndTime    *time.Time `json:"endTime"`
+	Similarity float64    `json:"similarity"`
+}
+
+// DuplicateWarning is the 409 response to creating a likely duplicate.
+type DuplicateWarning struct {
+	Error      string               `json:"error"`
+	Duplicates []DuplicateCandidate `json:"duplicates"`
+}
+
+// UpdateEventRequest changes only the fields that are present. An empty
+// meetingUrl removes the link, a venueId of 0 removes the venue and an
+// autoNudgeDays of 0 turns automatic nudges off. Times are changed through
+// RescheduleRequest. Only organizers may change requireChangeApproval, which
+// makes co-organizers propose changes instead of editing the event, and only
+// those who manage participants rsvpVisibility.
+type UpdateEventRequest struct {
+	Title                 *string `json:"title"`
+	Description           *string `json:"description"`
+	Location              *string `json:"location"`
+	VenueID               *int    `json:"venueId"`
+	Type                  *string `json:"type" binding:"omitempty,oneof=in_person virtual hybrid"`
+	MeetingURL            *string `json:"meetingUrl" binding:"omitempty,url"`
+	AllowTransfers        *bool   `json:"allowTransfers"`
+	AutoNudgeDays         *int    `json:"autoNudgeDays" binding:"omitempty,min=0,max=60"`
+	RequireChangeApproval *bool   `json:"requireChangeApproval,omitempty"`
+	RSVPVisibility        *string `json:"rsvpVisibility" binding:"omitempty,oneof=full counts none"`
+}
+
+// RescheduleRequest moves an event. ResetRSVPs clears every attendee's
+// attendance so they confirm again for the new time.
+type RescheduleRequest struct {
+	StartTime  string `json:"startTime" binding:"required"`
+	EndTime    string `json:"endTime"`
+	ResetRSVPs bool   `json:"resetRsvps"`
+}
+
+// NudgeRequest reminds invitees who have not responded AfterDays after their
+// invitation or their last reminder.
+type NudgeRequest struct {
+	AfterDays int `json:"afterDays" binding:"omitempty,min=1,max=60"`
 }
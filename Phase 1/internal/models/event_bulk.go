@@ -0,0 +1,38 @@
+package models
+
+// Bulk event actions.
+const (
+	BulkDelete  = "delete"
+	BulkArchive = "archive"
+	BulkCancel  = "cancel"
+)
+
+// BulkEventRequest applies Action to every event in IDs.
+type BulkEventRequest struct {
+	Action string `json:"action" binding:"required,oneof=delete archive cancel"`
+	IDs    []int  `json:"ids" binding:"required,min=1,max=100,dive,gt=0"`
+}
+
+// Per-event outcomes of a bulk action.
+const (
+	BulkDone      = "done"
+	BulkForbidden = "forbidden"
+	BulkNotFound  = "not_found"
+)
+
+// BulkEventResult is the outcome of a bulk action for one event.
+type BulkEventResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkEventResponse lists the outcome per requested event, in request order.
+type BulkEventResponse struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BulkEventResult `json:"results"`
+	// Undo reverses a delete or cancel of the events that were changed.
+	Undo *Undo `json:"undo,omitempty"`
+}
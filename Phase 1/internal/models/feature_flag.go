@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Where a feature flag's settings come from.
+const (
+	FlagSourceDefault  = "default"
+	FlagSourceConfig   = "config"
+	FlagSourceDatabase = "database"
+)
+
+// FeatureFlag turns a feature on for everyone when Enabled, otherwise for
+// the users in UserIDs and for Percent percent of the others, picked by a
+// stable hash of the flag name and user id so each user keeps their side as
+// the percentage grows.
+type FeatureFlag struct {
+	Name      string     `json:"name"`
+	Enabled   bool       `json:"enabled"`
+	Percent   int        `json:"percent"`
+	UserIDs   []int      `json:"userIds"`
+	Source    string     `json:"source"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type FeatureFlagRequest struct {
+	Enabled bool  `json:"enabled"`
+	Percent int   `json:"percent" binding:"min=0,max=100"`
+	UserIDs []int `json:"userIds" binding:"max=1000,dive,min=1"`
+}
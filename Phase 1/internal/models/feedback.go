@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Feedback question kinds.
+const (
+	FeedbackKindRating = "rating"
+	FeedbackKindText   = "text"
+)
+
+// FeedbackQuestion is part of an event's post-event survey. Rating questions
+// take a score from 1 to 5, text questions a free comment.
+type FeedbackQuestion struct {
+	ID        int       `json:"id"`
+	EventID   int       `json:"eventId"`
+	Prompt    string    `json:"prompt"`
+	Kind      string    `json:"kind"`
+	Required  bool      `json:"required"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FeedbackQuestionRequest struct {
+	Prompt   string `json:"prompt" binding:"required,max=500"`
+	Kind     string `json:"kind" binding:"required,oneof=rating text"`
+	Required *bool  `json:"required"`
+}
+
+// FeedbackAnswer answers one question: Rating for rating questions, Comment
+// for text questions.
+type FeedbackAnswer struct {
+	QuestionID int    `json:"questionId" binding:"required"`
+	Rating     *int   `json:"rating" binding:"omitempty,min=1,max=5"`
+	Comment    string `json:"comment" binding:"max=2000"`
+}
+
+// FeedbackRequest is the body of POST /events/:id/feedback.
+type FeedbackRequest struct {
+	Answers []FeedbackAnswer `json:"answers" binding:"required,min=1,dive"`
+}
+
+// FeedbackQuestionSummary aggregates the answers to one question. Rating
+// questions report the average and how many gave each score (Distribution[0]
+// counts ones); text questions list the comments without their authors.
+type FeedbackQuestionSummary struct {
+	QuestionID   int      `json:"questionId"`
+	Prompt       string   `json:"prompt"`
+	Kind         string   `json:"kind"`
+	Answers      int      `json:"answers"`
+	Average      *float64 `json:"average,omitempty"`
+	Distribution []int    `json:"distribution,omitempty"`
+	Comments     []string `json:"comments,omitempty"`
+}
+
+// FeedbackSummary is the organizer view of an event's survey.
+type FeedbackSummary struct {
+	EventID   int                       `json:"eventId"`
+	Responses int                       `json:"responses"`
+	Questions []FeedbackQuestionSummary `json:"questions"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// FollowedOrganizer is an organizer the user follows.
+type FollowedOrganizer struct {
+	UserID     int       `json:"userId"`
+	Name       string    `json:"name"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// FollowedSeries is a series the user follows.
+type FollowedSeries struct {
+	SeriesID   int       `json:"seriesId"`
+	Name       string    `json:"name"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// Following lists whom and what the user follows.
+type Following struct {
+	Organizers []FollowedOrganizer `json:"organizers"`
+	Series     []FollowedSeries    `json:"series"`
+}
+
+// FollowerCount is how many users follow an organizer or series. Organizers
+// only see the count, not who follows them.
+type FollowerCount struct {
+	Followers int `json:"followers"`
+}
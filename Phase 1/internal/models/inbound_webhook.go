@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Inbound webhook targets: what a delivery creates.
+const (
+	InboundTargetEvent = "event"
+	InboundTargetTask  = "task"
+)
+
+// InboundWebhook is an endpoint an external system, such as a form builder or
+// a CRM, calls to create events for its owner, or tasks in EventID. Mapping
+// maps the fields to create to dotted paths in the delivered JSON, e.g.
+// {"title": "data.fields.name"}. LastError is the error of the last
+// delivery, nil when it succeeded.
+type InboundWebhook struct {
+	ID             int               `json:"id"`
+	UserID         int               `json:"userId"`
+	Name           string            `json:"name"`
+	Target         string            `json:"target"`
+	EventID        *int              `json:"eventId"`
+	Mapping        map[string]string `json:"mapping"`
+	LastReceivedAt *time.Time        `json:"lastReceivedAt"`
+	LastError      *string           `json:"lastError"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+type InboundWebhookRequest struct {
+	Name    string            `json:"name" binding:"required,max=100"`
+	Target  string            `json:"target" binding:"required,oneof=event task"`
+	EventID *int              `json:"eventId"`
+	Mapping map[string]string `json:"mapping" binding:"required"`
+}
+
+// WebhookSecret signs the requests to an organizer's inbound webhooks.
+type WebhookSecret struct {
+	Secret string `json:"secret"`
+}
+
+// InboundDelivery is what a webhook delivery created.
+type InboundDelivery struct {
+	Target string `json:"target"`
+	ID     int    `json:"id"`
+}
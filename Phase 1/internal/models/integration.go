@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// APIKey lets an integration, such as a no-code tool, call the API as its
+// owner by sending the key in the X-API-Key header. Only the key's Prefix is
+// kept readable.
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// CreatedAPIKey is a new API key with the key itself, which is only shown
+// once.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Integration triggers: what the trigger feeds report.
+const (
+	TriggerNewEvent      = "new_event"
+	TriggerNewRSVP       = "new_rsvp"
+	TriggerTaskCompleted = "task_completed"
+)
+
+// Trigger describes a feed integrations poll for new items.
+type Trigger struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TriggerCursor is the position after the last item a poll returned: its time
+// and the ids ordering items at the same time (B only for RSVPs).
+type TriggerCursor struct {
+	At time.Time
+	A  int
+	B  int
+}
+
+// TriggerItem is one item of a trigger feed. ID stays the same across polls,
+// so integrations can deduplicate on it; Data is an Event, an RSVPTrigger or
+// a TaskCompletedTrigger.
+type TriggerItem struct {
+	ID         string    `json:"id"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+// TriggerPage is a page of a trigger feed, oldest first. Cursor continues
+// after its last item, or where the poll started when it is empty.
+type TriggerPage struct {
+	Items   []TriggerItem `json:"items"`
+	Cursor  string        `json:"cursor"`
+	HasMore bool          `json:"hasMore"`
+}
+
+// RSVPTrigger is a participant's answer to an invitation.
+type RSVPTrigger struct {
+	EventID     int       `json:"eventId"`
+	EventTitle  string    `json:"eventTitle"`
+	UserID      int       `json:"userId"`
+	UserName    string    `json:"userName"`
+	UserEmail   string    `json:"userEmail"`
+	Attendance  string    `json:"attendance"`
+	RespondedAt time.Time `json:"respondedAt"`
+}
+
+// TaskCompletedTrigger is a task marked done, with its event's title.
+type TaskCompletedTrigger struct {
+	Task
+	EventTitle string `json:"eventTitle"`
+}
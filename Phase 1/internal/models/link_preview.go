@@ -0,0 +1,13 @@
+package models
+
+// LinkPreview is a card for a page linked from an event description or a
+// comment: what the page says about itself in its Open Graph tags. ImageURL
+// points at the linked site; clients that load it reveal their readers to
+// that site.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
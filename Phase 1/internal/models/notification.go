@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification is an entry in a user's in-app inbox.
+type Notification struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"userId"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	EventID   *int       `json:"eventId"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event topics written to the outbox.
+const (
+	TopicEventCreated     = "event.created"
+	TopicInviteSent       = "invite.sent"
+	TopicEventRescheduled = "event.rescheduled"
+	TopicTicketPaid       = "ticket.paid"
+	TopicEventPublished   = "event.published"
+)
+
+// OutboxMessage is a domain event waiting in the outbox. Payload is the JSON
+// encoding of the topic's payload type below. DeliveredTo names the publishers
+// that already received it.
+type OutboxMessage struct {
+	ID          int64           `json:"id"`
+	Topic       string          `json:"topic"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Attempts    int             `json:"-"`
+	DeliveredTo []string        `json:"-"`
+}
+
+// EventCreated is the payload of event.created.
+type EventCreated struct {
+	EventID     int       `json:"eventId"`
+	OrganizerID int       `json:"organizerId"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Type        string    `json:"type"`
+	StartTime   time.Time `json:"startTime"`
+}
+
+// EventPublished is the payload of event.published, written the first time
+// an event's landing page goes up.
+type EventPublished struct {
+	EventID     int       `json:"eventId"`
+	OrganizerID int       `json:"organizerId"`
+	SeriesID    *int      `json:"seriesId,omitempty"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	StartTime   time.Time `json:"startTime"`
+}
+
+// InviteSent is the payload of invite.sent.
+type InviteSent struct {
+	EventID   int        `json:"eventId"`
+	InviterID int        `json:"inviterId"`
+	InviteeID int        `json:"inviteeId"`
+	Role      string     `json:"role"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// EventRescheduled is the payload of event.rescheduled.
+type EventRescheduled struct {
+	EventID       int        `json:"eventId"`
+	RescheduledBy int        `json:"rescheduledBy"`
+	Title         string     `json:"title"`
+	OldStart      time.Time  `json:"oldStart"`
+	OldEnd        *time.Time `json:"oldEnd,omitempty"`
+	NewStart      time.Time  `json:"newStart"`
+	NewEnd        *time.Time `json:"newEnd,omitempty"`
+	RSVPsReset    bool       `json:"rsvpsReset"`
+}
+
+// TicketPaid is the payload of ticket.paid, written when a ticket's payment
+// is confirmed and its receipt issued.
+type TicketPaid struct {
+	TicketID      int    `json:"ticketId"`
+	EventID       int    `json:"eventId"`
+	UserID        int    `json:"userId"`
+	InvoiceNumber string `json:"invoiceNumber"`
+	AmountCents   int    `json:"amountCents"`
+	Currency      string `json:"currency"`
+}
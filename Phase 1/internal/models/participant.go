@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Participant struct {
 	EventID    int     `json:"eventId"`
 	UserID     int     `json:"userId"`
@@ -7,13 +9,100 @@ type Participant struct {
 	UserEmail  string  `json:"userEmail"`
 	Role       string  `json:"role"`
 	Attendance *string `json:"attendance"`
+	// InviteExpiresAt is when a pending invitation expires, if ever.
+	InviteExpiresAt *time.Time `json:"inviteExpiresAt,omitempty"`
+}
+
+// RSVP visibilities: what participants who cannot manage participants see
+// of an event's RSVPs. Full shows them the participant list, without emails,
+// counts only how many gave each answer, and none nothing at all.
+const (
+	RSVPVisibilityFull   = "full"
+	RSVPVisibilityCounts = "counts"
+	RSVPVisibilityNone   = "none"
+)
+
+// Membership is a user's role and attendance in one event. Custom holds the
+// permissions of a custom event role; built-in roles use RolePermissions.
+// RSVPVisibility is the event's.
+type Membership struct {
+	Role            string
+	Attendance      *string
+	InviteExpiresAt *time.Time
+	Custom          []Permission
+	RSVPVisibility  string
+}
+
+// InviteExpired reports whether the membership is an unanswered invitation
+// past its expiry.
+func (m Membership) InviteExpired(now time.Time) bool {
+	return m.Attendance == nil && m.InviteExpiresAt != nil && !now.Before(*m.InviteExpiresAt)
+}
+
+// Permissions returns the permissions the membership grants, never nil.
+func (m Membership) Permissions() []Permission {
+	if _, ok := RolePermissions[m.Role]; ok || m.Custom == nil {
+		return PermissionsFor(m.Role)
+	}
+	return m.Custom
+}
+
+// Has reports whether the membership grants perm.
+func (m Membership) Has(perm Permission) bool {
+	for _, p := range m.Permissions() {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// SeesParticipants reports whether the member may list the event's
+// participants: always with manage_participants, otherwise when the event's
+// RSVP visibility is full.
+func (m Membership) SeesParticipants() bool {
+	return m.Has(PermManageParticipants) || m.RSVPVisibility == RSVPVisibilityFull
+}
+
+// SeesAttendanceCounts reports whether the member may see how many participants
+// gave each answer.
+func (m Membership) SeesAttendanceCounts() bool {
+	return m.SeesParticipants() || m.RSVPVisibility == RSVPVisibilityCounts
 }
 
 type InviteRequest struct {
-	UserID int    `json:"userId" binding:"required"`
-	Role   string `json:"role" binding:"required,oneof=organizer attendee collaborator"`
+	UserID    int        `json:"userId" binding:"required"`
+	Role      string     `json:"role" binding:"required,max=50"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// BulkInviteRequest invites many users at once, by id or by the email
+// address of their account, all with the same role.
+type BulkInviteRequest struct {
+	UserIDs   []int      `json:"userIds" binding:"dive,min=1"`
+	Emails    []string   `json:"emails" binding:"dive,email"`
+	Role      string     `json:"role" binding:"required,max=50"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// BulkInviteResult says what became of a bulk invitation. Users who already
+// participate keep their role.
+type BulkInviteResult struct {
+	Invited              int      `json:"invited"`
+	AlreadyParticipating []int    `json:"alreadyParticipating"`
+	UnknownUserIDs       []int    `json:"unknownUserIds"`
+	UnknownEmails        []string `json:"unknownEmails"`
+}
+
+// InviteCandidate is a user named in a bulk invitation.
+type InviteCandidate struct {
+	UserID      int
+	Email       string // lowercase
+	Participant bool
+	Blocked     bool // blocks the inviter
 }
 
 type AttendanceRequest struct {
-	Status string `json:"status" binding:"required,oneof=going maybe not_going"`
+	Status  string       `json:"status" binding:"required,oneof=going maybe not_going"`
+	Answers []RSVPAnswer `json:"answers" binding:"dive"`
 }
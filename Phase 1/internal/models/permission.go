@@ -0,0 +1,55 @@
+package models
+
+// Permission is a privileged action on an event.
+type Permission string
+
+const (
+	PermManageParticipants Permission = "manage_participants"
+	PermManageTasks        Permission = "manage_tasks"
+	PermEditEvent          Permission = "edit_event"
+	PermDeleteEvent        Permission = "delete_event"
+)
+
+// RolePermissions is the permission matrix: what each participant role may do
+// on an event. Roles not listed have no permissions.
+var RolePermissions = map[string][]Permission{
+	"organizer":    {PermManageParticipants, PermManageTasks, PermEditEvent, PermDeleteEvent},
+	"collaborator": {PermManageParticipants, PermManageTasks, PermEditEvent},
+	"attendee":     {},
+}
+
+// PermissionsFor returns the permissions granted to role, never nil.
+func PermissionsFor(role string) []Permission {
+	if perms, ok := RolePermissions[role]; ok {
+		return perms
+	}
+	return []Permission{}
+}
+
+// RoleHasPermission reports whether role grants perm.
+func RoleHasPermission(role string, perm Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBuiltInRole reports whether role is part of the permission matrix rather
+// than a custom event role.
+func IsBuiltInRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// RolesWith returns every built-in role that grants perm.
+func RolesWith(perm Permission) []string {
+	var roles []string
+	for role := range RolePermissions {
+		if RoleHasPermission(role, perm) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
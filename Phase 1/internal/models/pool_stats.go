@@ -0,0 +1,18 @@
+package models
+
+// PoolStats describe the database connection pool of one server instance.
+// The counts and durations since startup only grow.
+type PoolStats struct {
+	MaxConns                int32   `json:"maxConns"`
+	TotalConns              int32   `json:"totalConns"`
+	AcquiredConns           int32   `json:"acquiredConns"`
+	IdleConns               int32   `json:"idleConns"`
+	ConstructingConns       int32   `json:"constructingConns"`
+	AcquireCount            int64   `json:"acquireCount"`
+	EmptyAcquireCount       int64   `json:"emptyAcquireCount"`
+	CanceledAcquireCount    int64   `json:"canceledAcquireCount"`
+	AcquireDurationSeconds  float64 `json:"acquireDurationSeconds"`
+	NewConnsCount           int64   `json:"newConnsCount"`
+	MaxLifetimeDestroyCount int64   `json:"maxLifetimeDestroyCount"`
+	MaxIdleDestroyCount     int64   `json:"maxIdleDestroyCount"`
+}
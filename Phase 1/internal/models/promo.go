@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Promo code discount kinds. Percent amounts are 1-100; fixed amounts are in
+// the tier's currency minor units (cents).
+const (
+	PromoPercent = "percent"
+	PromoFixed   = "fixed"
+)
+
+// PromoCode is a discount code for an event's tickets. Uses counts the tickets
+// currently holding the code; releasing a ticket gives its use back.
+type PromoCode struct {
+	ID        int        `json:"id"`
+	EventID   int        `json:"eventId"`
+	Code      string     `json:"code"`
+	Kind      string     `json:"kind"`
+	Amount    int        `json:"amount"`
+	MaxUses   *int       `json:"maxUses,omitempty"`
+	Uses      int        `json:"uses"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Discount returns how much the code takes off the given price.
+func (p PromoCode) Discount(priceCents int) int {
+	d := p.Amount
+	if p.Kind == PromoPercent {
+		d = priceCents * p.Amount / 100
+	}
+	if d > priceCents {
+		return priceCents
+	}
+	return d
+}
+
+type PromoCodeRequest struct {
+	Code      string     `json:"code" binding:"required,max=50"`
+	Kind      string     `json:"kind" binding:"required,oneof=percent fixed"`
+	Amount    int        `json:"amount" binding:"required,min=1"`
+	MaxUses   *int       `json:"maxUses" binding:"omitempty,min=1"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// PromoRedemption records a promo code applied to a ticket. ReleasedAt is set
+// when the ticket was cancelled or refunded and the use given back.
+type PromoRedemption struct {
+	ID            int        `json:"id"`
+	PromoCodeID   int        `json:"promoCodeId"`
+	TicketID      int        `json:"ticketId"`
+	UserID        int        `json:"userId"`
+	UserName      string     `json:"userName"`
+	DiscountCents int        `json:"discountCents"`
+	RedeemedAt    time.Time  `json:"redeemedAt"`
+	ReleasedAt    *time.Time `json:"releasedAt,omitempty"`
+}
+
+// ClaimRequest is the optional body of a ticket claim.
+type ClaimRequest struct {
+	PromoCode string `json:"promoCode" binding:"max=50"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Change proposal statuses.
+const (
+	ProposalPending   = "pending"
+	ProposalApproved  = "approved"
+	ProposalRejected  = "rejected"
+	ProposalWithdrawn = "withdrawn"
+)
+
+// FieldChange is one field a proposal changes, with the value it had when
+// the change was proposed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// ChangeProposal is a change to an event that waits for an organizer's
+// approval. Changes is applied as a PATCH of the event when approved.
+type ChangeProposal struct {
+	ID              int                `json:"id"`
+	EventID         int                `json:"eventId"`
+	ProposedBy      int                `json:"proposedBy"`
+	ProposerName    string             `json:"proposerName"`
+	Changes         UpdateEventRequest `json:"changes"`
+	Diff            []FieldChange      `json:"diff"`
+	Note            string             `json:"note"`
+	Status          string             `json:"status"`
+	DecidedBy       *int               `json:"decidedBy,omitempty"`
+	DecisionComment string             `json:"decisionComment,omitempty"`
+	DecidedAt       *time.Time         `json:"decidedAt,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt"`
+}
+
+// ProposalRequest proposes Changes, with the same fields as
+// UpdateEventRequest, and an optional Note for the organizers.
+type ProposalRequest struct {
+	Changes UpdateEventRequest `json:"changes"`
+	Note    string             `json:"note" binding:"max=1000"`
+}
+
+// ProposalDecision approves or rejects a proposal, with an optional comment
+// for the proposer.
+type ProposalDecision struct {
+	Comment string `json:"comment" binding:"max=1000"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// PublicEvent is the landing page of a published event. It is served without
+// authentication, so it carries no participant data and no meeting URL.
+// Remaining is the number of tickets left across all tiers, or nil when the
+// event sells no tickets. DescriptionHTML is the Markdown Description
+// rendered as HTML that is safe to display.
+type PublicEvent struct {
+	Slug            string          `json:"slug"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	DescriptionHTML string          `json:"descriptionHtml"`
+	Location        string          `json:"location"`
+	Venue           *PublicVenue    `json:"venue,omitempty"`
+	StartTime       time.Time       `json:"startTime"`
+	EndTime         *time.Time      `json:"endTime"`
+	Type            string          `json:"type"`
+	Organizer       string          `json:"organizer"`
+	Speakers        []PublicSpeaker `json:"speakers"`
+	Agenda          []PublicSession `json:"agenda"`
+	Tiers           []PublicTier    `json:"tiers"`
+	Remaining       *int            `json:"remaining,omitempty"`
+	PublishedAt     time.Time       `json:"publishedAt"`
+}
+
+type PublicVenue struct {
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+type PublicSpeaker struct {
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Bio      string        `json:"bio"`
+	PhotoURL *string       `json:"photoUrl,omitempty"`
+	Links    []SpeakerLink `json:"links"`
+}
+
+// PublicSession is an agenda slot. Remaining is only set for sessions with a
+// capacity.
+type PublicSession struct {
+	ID              int             `json:"id"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	StartTime       time.Time       `json:"startTime"`
+	EndTime         time.Time       `json:"endTime"`
+	Room            string          `json:"room"`
+	Speakers        []string        `json:"speakers"`
+	SpeakerProfiles []PublicSpeaker `json:"speakerProfiles"`
+	Capacity        *int            `json:"capacity,omitempty"`
+	Remaining       *int            `json:"remaining,omitempty"`
+}
+
+type PublicTier struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	PriceCents int    `json:"priceCents"`
+	Currency   string `json:"currency"`
+	Quantity   int    `json:"quantity"`
+	Remaining  int    `json:"remaining"`
+}
+
+// NewPublicSpeaker strips a speaker profile down to its public fields.
+func NewPublicSpeaker(s Speaker) PublicSpeaker {
+	links := s.Links
+	if links == nil {
+		links = []SpeakerLink{}
+	}
+	return PublicSpeaker{ID: s.ID, Name: s.Name, Bio: s.Bio, PhotoURL: s.PhotoURL, Links: links}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// QuotaOverrides holds a user's per-user overrides of the deployment quotas.
+// A nil limit uses the deployment default.
+type QuotaOverrides struct {
+	ActiveEvents  *int
+	InvitesPerDay *int
+}
+
+// QuotaUsage is how much of one quota a user has used. A limit of 0 means
+// unlimited.
+type QuotaUsage struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// Quotas is the caller's usage of each quota. ResetsAt is set while the
+// invitation quota is exhausted and says when the next invitation can go out.
+type Quotas struct {
+	ActiveEvents  QuotaUsage `json:"activeEvents"`
+	InvitesPerDay QuotaUsage `json:"invitesPerDay"`
+	ResetsAt      *time.Time `json:"resetsAt,omitempty"`
+}
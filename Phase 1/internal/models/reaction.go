@@ -0,0 +1,9 @@
+package models
+
+// Reaction is an emoji some reacted to a comment or announcement with: how
+// many did, and whether the caller is one of them.
+type Reaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Receipt is the invoice for a paid ticket. InvoiceNumber counts up per
+// organizer without gaps, e.g. "7-000042" for the organizer with ID 7.
+// AmountCents is what the buyer paid, after DiscountCents.
+type Receipt struct {
+	ID            int       `json:"id"`
+	TicketID      int       `json:"ticketId"`
+	EventID       int       `json:"eventId"`
+	UserID        int       `json:"userId"`
+	InvoiceNumber string    `json:"invoiceNumber"`
+	SellerName    string    `json:"sellerName"`
+	BuyerName     string    `json:"buyerName"`
+	BuyerEmail    string    `json:"buyerEmail"`
+	EventTitle    string    `json:"eventTitle"`
+	EventStart    time.Time `json:"eventStart"`
+	TierName      string    `json:"tierName"`
+	AmountCents   int       `json:"amountCents"`
+	DiscountCents int       `json:"discountCents"`
+	Currency      string    `json:"currency"`
+	PaymentID     *string   `json:"paymentId,omitempty"`
+	IssuedAt      time.Time `json:"issuedAt"`
+}
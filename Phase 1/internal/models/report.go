@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Report reasons.
+const (
+	ReportSpam          = "spam"
+	ReportScam          = "scam"
+	ReportHarassment    = "harassment"
+	ReportInappropriate = "inappropriate"
+	ReportOther         = "other"
+	// ReportModeration is the reason of reports filed by content moderation
+	// for flagged events. They have no reporter.
+	ReportModeration = "moderation"
+)
+
+// Report statuses. Pending reports wait in the admin review queue.
+const (
+	ReportPending   = "pending"
+	ReportDismissed = "dismissed"
+	ReportActioned  = "actioned"
+)
+
+// Report flags an event or a user as spam or abuse. Exactly one of EventID
+// and UserID is set; TargetName is the event's title or the user's name.
+// ReporterID is nil for reports filed by content moderation.
+type Report struct {
+	ID         int        `json:"id"`
+	ReporterID *int       `json:"reporterId,omitempty"`
+	EventID    *int       `json:"eventId,omitempty"`
+	UserID     *int       `json:"userId,omitempty"`
+	TargetName string     `json:"targetName"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status"`
+	ReviewedBy *int       `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ReportRequest is the body of POST /events/:id/report and POST /users/:id/report.
+type ReportRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam scam harassment inappropriate other"`
+	Details string `json:"details" binding:"max=2000"`
+}
+
+// ReportReview is an admin's decision on a report. Actioning an event report
+// hides the event; dismissing it shows the event again once it no longer has
+// enough open reports.
+type ReportReview struct {
+	Status string `json:"status" binding:"required,oneof=dismissed actioned"`
+}
+
+// ReportQueueFilter selects the reports of the admin review queue.
+type ReportQueueFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending dismissed actioned"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
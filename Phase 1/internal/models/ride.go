@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Ride is a car a participant drives to an event, offering Seats seats to
+// other participants. SeatsLeft counts down as passengers join.
+type Ride struct {
+	ID          int             `json:"id"`
+	EventID     int             `json:"eventId"`
+	DriverID    int             `json:"driverId"`
+	DriverName  string          `json:"driverName"`
+	DriverEmail string          `json:"-"`
+	Origin      string          `json:"origin"`
+	DepartureAt time.Time       `json:"departureAt"`
+	Seats       int             `json:"seats"`
+	SeatsLeft   int             `json:"seatsLeft"`
+	Notes       string          `json:"notes"`
+	Passengers  []RidePassenger `json:"passengers"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// RidePassenger is a participant who took a seat in a ride.
+type RidePassenger struct {
+	UserID   int       `json:"userId"`
+	Name     string    `json:"name"`
+	Email    string    `json:"-"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type RideRequest struct {
+	Origin      string    `json:"origin" binding:"required,max=200"`
+	DepartureAt time.Time `json:"departureAt" binding:"required"`
+	Seats       int       `json:"seats" binding:"required,min=1,max=50"`
+	Notes       string    `json:"notes" binding:"max=1000"`
+}
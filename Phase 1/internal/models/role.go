@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// EventRole is a participant role available on an event: one of the built-in
+// roles of the permission matrix, or a custom role defined by the organizers.
+type EventRole struct {
+	ID          int          `json:"id,omitempty"`
+	EventID     int          `json:"eventId"`
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+	BuiltIn     bool         `json:"builtIn"`
+	CreatedAt   *time.Time   `json:"createdAt,omitempty"`
+}
+
+type EventRoleRequest struct {
+	Name        string       `json:"name" binding:"required,max=50"`
+	Permissions []Permission `json:"permissions" binding:"dive,oneof=manage_participants manage_tasks edit_event delete_event"`
+}
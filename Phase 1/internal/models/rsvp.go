@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// RSVP question kinds.
+const (
+	QuestionKindText   = "text"
+	QuestionKindChoice = "choice"
+)
+
+// RSVPQuestion is asked of invitees when they accept an event, e.g. dietary
+// restrictions or t-shirt size. Choice questions only accept one of Options.
+type RSVPQuestion struct {
+	ID        int       `json:"id"`
+	EventID   int       `json:"eventId"`
+	Prompt    string    `json:"prompt"`
+	Kind      string    `json:"kind"`
+	Options   []string  `json:"options"`
+	Required  bool      `json:"required"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RSVPQuestionRequest struct {
+	Prompt   string   `json:"prompt" binding:"required,max=500"`
+	Kind     string   `json:"kind" binding:"required,oneof=text choice"`
+	Options  []string `json:"options" binding:"omitempty,max=50,dive,required,max=200"`
+	Required *bool    `json:"required"`
+}
+
+// RSVPAnswer is a participant's answer to one question.
+type RSVPAnswer struct {
+	QuestionID int    `json:"questionId" binding:"required"`
+	Answer     string `json:"answer" binding:"max=2000"`
+}
+
+// AcceptRequest is the optional body of PUT /events/:id/accept.
+type AcceptRequest struct {
+	Answers []RSVPAnswer `json:"answers" binding:"dive"`
+}
+
+// RSVPResponse is one participant's row in the responses export. Answers are
+// keyed by question ID.
+type RSVPResponse struct {
+	UserID     int            `json:"userId"`
+	UserName   string         `json:"userName"`
+	UserEmail  string         `json:"userEmail"`
+	Role       string         `json:"role"`
+	Attendance *string        `json:"attendance"`
+	Answers    map[int]string `json:"answers"`
+}
+
+// RSVPExport is the organizer export of every participant's answers.
+type RSVPExport struct {
+	Questions []RSVPQuestion `json:"questions"`
+	Responses []RSVPResponse `json:"responses"`
+}
+
+// AttendanceCounts is how many of an event's participants gave each answer;
+// Pending counts those who have not answered their invitation.
+type AttendanceCounts struct {
+	Going    int `json:"going"`
+	Maybe    int `json:"maybe"`
+	NotGoing int `json:"notGoing"`
+	Pending  int `json:"pending"`
+	Total    int `json:"total"`
+}
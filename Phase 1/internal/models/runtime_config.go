@@ -0,0 +1,16 @@
+package models
+
+// RateLimit is a token bucket rate: PerSecond requests on average, in bursts
+// of up to Burst requests.
+type RateLimit struct {
+	PerSecond float64 `json:"perSecond"`
+	Burst     int     `json:"burst"`
+}
+
+// RuntimeConfig is the configuration that can be reloaded without restarting
+// the server.
+type RuntimeConfig struct {
+	CORSOrigins  []string             `json:"corsOrigins"`
+	RateLimits   map[string]RateLimit `json:"rateLimits"`
+	FeatureFlags []FeatureFlag        `json:"featureFlags"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// SavedSearch is a named search filter. From and To are whole days. With
+// Alerts on, the owner is notified when newly published events match.
+type SavedSearch struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"userId"`
+	Name      string     `json:"name"`
+	Query     string     `json:"query"`
+	From      *time.Time `json:"from"`
+	To        *time.Time `json:"to"`
+	Role      string     `json:"role"`
+	Alerts    bool       `json:"alerts"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type SavedSearchRequest struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	Query  string `json:"query" binding:"max=200"`
+	From   string `json:"from" binding:"omitempty,datetime=2006-01-02"`
+	To     string `json:"to" binding:"omitempty,datetime=2006-01-02"`
+	Role   string `json:"role" binding:"omitempty,oneof=organizer attendee collaborator"`
+	Alerts bool   `json:"alerts"`
+}
+
+// SavedSearchAlert is a saved search with alerts on, with its owner's contact
+// details and the time up to which published events were already matched.
+type SavedSearchAlert struct {
+	SavedSearch
+	UserName      string
+	UserEmail     string
+	LastCheckedAt time.Time
+}
+
+// SearchResults are the events and tasks matching a saved search.
+type SearchResults struct {
+	Events []Event `json:"events"`
+	Tasks  []Task  `json:"tasks"`
+}
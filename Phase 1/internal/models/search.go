@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// GeoFilter restricts search results to events whose venue lies within
+// RadiusKm of the given point.
+type GeoFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// Search sort keys. Relevance ranks matches of the query text and requires
+// a query; date sorts events by start time and tasks by due date.
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortDate      = "date"
+	SearchSortCreated   = "created"
+)
+
+// SearchFilter holds the criteria accepted by the search endpoint. An empty
+// Sort orders by date; Order is "asc" or "desc". Similarity is the pg_trgm
+// word similarity (0-1) above which misspelled terms still match; 0 only
+// matches exact substrings. PublishedAfter and PublishedBefore restrict the
+// results to events published in that window. Labels restrict the tasks
+// found to those with all of them.
+type SearchFilter struct {
+	Query           string
+	From            *time.Time
+	To              *time.Time
+	Role            string
+	Near            *GeoFilter
+	Sort            string
+	Order           string
+	Similarity      float64
+	PublishedAfter  *time.Time
+	PublishedBefore *time.Time
+	Labels          []string
+}
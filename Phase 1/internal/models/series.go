@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Series groups related events, such as the monthly occurrences of a meetup.
+// Subscribers are invited to every occurrence linked after they subscribe,
+// with DefaultRole and, if InviteExpiryDays is set, an invitation that
+// expires that many days after it is sent.
+type Series struct {
+	ID               int                `json:"id"`
+	OwnerID          int                `json:"ownerId"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	DefaultRole      string             `json:"defaultRole"`
+	InviteExpiryDays *int               `json:"inviteExpiryDays"`
+	Subscribers      int                `json:"subscribers"`
+	Subscribed       bool               `json:"subscribed"`
+	Occurrences      []SeriesOccurrence `json:"occurrences,omitempty"`
+	CreatedAt        time.Time          `json:"createdAt"`
+	UpdatedAt        time.Time          `json:"updatedAt"`
+}
+
+type SeriesRequest struct {
+	Name             string `json:"name" binding:"required,max=200"`
+	Description      string `json:"description" binding:"max=2000"`
+	DefaultRole      string `json:"defaultRole" binding:"omitempty,oneof=attendee collaborator"`
+	InviteExpiryDays *int   `json:"inviteExpiryDays" binding:"omitempty,min=1,max=365"`
+}
+
+// SeriesOccurrence is one event of a series.
+type SeriesOccurrence struct {
+	EventID   int        `json:"eventId"`
+	Title     string     `json:"title"`
+	Slug      string     `json:"slug"`
+	StartTime time.Time  `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+}
+
+// SeriesLinkRequest is the body of PUT /events/:id/series.
+type SeriesLinkRequest struct {
+	SeriesID int `json:"seriesId" binding:"required,min=1"`
+}
+
+type SeriesSubscriber struct {
+	UserID       int       `json:"userId"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	SubscribedAt time.Time `json:"subscribedAt"`
+}
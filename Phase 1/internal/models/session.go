@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// EventSession is one slot of an event's agenda, such as a talk or workshop.
+// Multi-day events are modelled as sessions spread over the event's days.
+// Speakers holds free-text names; SpeakerProfiles the attached speaker profiles.
+// Capacity is optional; AttendeeCount is the number of participants who added
+// the session to their agenda, and Attending whether the caller did.
+type EventSession struct {
+	ID              int       `json:"id"`
+	EventID         int       `json:"eventId"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+	Room            string    `json:"room"`
+	Speakers        []string  `json:"speakers"`
+	SpeakerProfiles []Speaker `json:"speakerProfiles"`
+	Capacity        *int      `json:"capacity,omitempty"`
+	AttendeeCount   int       `json:"attendeeCount"`
+	Attending       bool      `json:"attending"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type EventSessionRequest struct {
+	Title       string    `json:"title" binding:"required,max=200"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"startTime" binding:"required"`
+	EndTime     time.Time `json:"endTime" binding:"required"`
+	Room        string    `json:"room" binding:"max=100"`
+	Speakers    []string  `json:"speakers" binding:"max=20,dive,max=100"`
+	Capacity    *int      `json:"capacity" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Speaker is a reusable speaker or host profile. Speakers are attached, in
+// order, to events and to their sessions.
+type Speaker struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Bio       string        `json:"bio"`
+	PhotoURL  *string       `json:"photoUrl,omitempty"`
+	Links     []SpeakerLink `json:"links"`
+	CreatedBy int           `json:"createdBy"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// SpeakerLink is a labelled link on a speaker profile (website, social media).
+type SpeakerLink struct {
+	Label string `json:"label" binding:"required,max=50"`
+	URL   string `json:"url" binding:"required,url"`
+}
+
+type SpeakerRequest struct {
+	Name     string        `json:"name" binding:"required,max=200"`
+	Bio      string        `json:"bio" binding:"max=5000"`
+	PhotoURL string        `json:"photoUrl" binding:"omitempty,url"`
+	Links    []SpeakerLink `json:"links" binding:"max=10,dive"`
+}
+
+// SpeakerOrderRequest sets the speakers of an event or session; their order
+// in the list is the display order.
+type SpeakerOrderRequest struct {
+	SpeakerIDs []int `json:"speakerIds" binding:"max=50"`
+}
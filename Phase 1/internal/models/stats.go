@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Reliability levels of an invitee, from how often they showed up at past
+// events they said they were going to.
+const (
+	ReliabilityHigh    = "high"
+	ReliabilityMedium  = "medium"
+	ReliabilityLow     = "low"
+	ReliabilityUnknown = "unknown"
+)
+
+// RSVPCounts is how a user answered the invitations of events they did not
+// organize. Pending invitations have no answer yet.
+type RSVPCounts struct {
+	Invited  int `json:"invited"`
+	Going    int `json:"going"`
+	Maybe    int `json:"maybe"`
+	NotGoing int `json:"notGoing"`
+	Pending  int `json:"pending"`
+}
+
+// AttendanceStats compares RSVPs with check-ins. Only ended events that
+// checked people in count: Going is how many of them the user said they were
+// going to, Attended how many of those they were checked in at, and WalkIns
+// the check-ins without a going RSVP. Rate is Attended over Going, nil when
+// Going is 0.
+type AttendanceStats struct {
+	Going    int      `json:"going"`
+	Attended int      `json:"attended"`
+	NoShows  int      `json:"noShows"`
+	WalkIns  int      `json:"walkIns"`
+	Rate     *float64 `json:"rate"`
+}
+
+// TaskStats counts the tasks assigned to a user. CompletionRate is Completed
+// over Assigned, nil when no task is assigned.
+type TaskStats struct {
+	Assigned       int      `json:"assigned"`
+	Completed      int      `json:"completed"`
+	CompletionRate *float64 `json:"completionRate"`
+}
+
+// UserStats summarizes a user's participation across events.
+type UserStats struct {
+	RSVPs           RSVPCounts      `json:"rsvps"`
+	Attendance      AttendanceStats `json:"attendance"`
+	OrganizedEvents int             `json:"organizedEvents"`
+	Tasks           TaskStats       `json:"tasks"`
+}
+
+// Reliability is how often an invitee showed up at other ended events that
+// checked people in and they said they were going to. Level is unknown until
+// there are enough such events to tell.
+type Reliability struct {
+	UserID   int      `json:"userId"`
+	UserName string   `json:"userName"`
+	Going    int      `json:"going"`
+	Attended int      `json:"attended"`
+	Rate     *float64 `json:"rate"`
+	Level    string   `json:"level"`
+}
+
+// Funnel intervals.
+const (
+	FunnelDaily  = "day"
+	FunnelWeekly = "week"
+)
+
+// FunnelCounts is how many invitees reached each stage of the RSVP funnel:
+// invited, viewed the event, answered the invitation, and checked in.
+// Answering counts as viewing for invitees who answered without opening the
+// event, e.g. by email.
+type FunnelCounts struct {
+	Invited   int `json:"invited"`
+	Viewed    int `json:"viewed"`
+	Responded int `json:"responded"`
+	Attended  int `json:"attended"`
+}
+
+// FunnelConversion is the share of each stage's invitees who reached the
+// next one, nil when the previous stage is empty.
+type FunnelConversion struct {
+	Viewed    *float64 `json:"viewed"`
+	Responded *float64 `json:"responded"`
+	Attended  *float64 `json:"attended"`
+}
+
+// FunnelPoint is how many invitees had reached each stage by the end of the
+// interval starting at Date.
+type FunnelPoint struct {
+	Date time.Time `json:"date"`
+	FunnelCounts
+}
+
+// Funnel is an event's RSVP funnel now and over time.
+type Funnel struct {
+	EventID    int              `json:"eventId"`
+	Interval   string           `json:"interval"`
+	Totals     FunnelCounts     `json:"totals"`
+	Conversion FunnelConversion `json:"conversion"`
+	Series     []FunnelPoint    `json:"series"`
+}
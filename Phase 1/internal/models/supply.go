@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Supply is an item on an event's supplies checklist. ClaimedBy is the
+// participant bringing it, if anyone has claimed it yet.
+type Supply struct {
+	ID            int        `json:"id"`
+	EventID       int        `json:"eventId"`
+	Name          string     `json:"name"`
+	Quantity      int        `json:"quantity"`
+	Notes         string     `json:"notes"`
+	ClaimedBy     *int       `json:"claimedBy,omitempty"`
+	ClaimedByName *string    `json:"claimedByName,omitempty"`
+	ClaimedAt     *time.Time `json:"claimedAt,omitempty"`
+	Purchased     bool       `json:"purchased"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+type SupplyRequest struct {
+	Name     string `json:"name" binding:"required,max=200"`
+	Quantity int    `json:"quantity" binding:"omitempty,min=1,max=10000"`
+	Notes    string `json:"notes" binding:"max=1000"`
+}
+
+// PurchasedRequest marks a claimed supply as bought or not.
+type PurchasedRequest struct {
+	Purchased *bool `json:"purchased" binding:"required"`
+}
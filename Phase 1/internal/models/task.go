@@ -8,7 +8,41 @@ type Task struct {
 	Title      string    `json:"title"`
 	Description string   `json:"description"`
 	DueDate    *time.Time `json:"dueDate"`
+	// DueOffset is set for due dates relative to the event start, e.g. "-7d".
+	DueOffset  *string   `json:"dueOffset,omitempty"`
 	AssigneeID *int      `json:"assigneeId"`
+	// CompletedAt is when the task was marked done, nil while it is open.
+	CompletedAt *time.Time `json:"completedAt"`
+	// Position is the task's place in the order organizers arranged, nil
+	// for tasks not arranged yet, which are listed after the others.
+	Position   *float64  `json:"position"`
+	// ColumnID is the task board column the task was moved to, if any.
+	ColumnID   *int      `json:"columnId"`
+	// EstimatedMinutes is how long the task is expected to take, if known.
+	EstimatedMinutes *int `json:"estimatedMinutes"`
+	// Labels are the task's labels, by name.
+	Labels     []TaskLabel `json:"labels"`
 	CreatedAt  time.Time `json:"createdAt"`
 	UpdatedAt  time.Time `json:"updatedAt"`
 }
+
+// TaskPatch lists the task fields to change; nil fields are left alone. Due,
+// when set, replaces both the due date and the due offset, and an AssigneeID
+// of 0 unassigns the task. Completed marks the task done or open again. An
+// EstimatedMinutes of 0 clears the estimate.
+type TaskPatch struct {
+	Title            *string
+	Description      *string
+	Due              *TaskDue
+	AssigneeID       *int
+	Completed        *bool
+	EstimatedMinutes *int
+}
+
+// TaskDue is a task's due date and, for dates relative to the event start,
+// the offset it was computed from. Both are nil for tasks without a due date.
+// In an update, an Offset alone has its Date computed from the event start.
+type TaskDue struct {
+	Date   *time.Time
+	Offset *string
+}
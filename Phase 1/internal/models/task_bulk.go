@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// TaskInput is one task of a bulk creation request. DueOffset, e.g. "-7d",
+// sets the due date relative to the event start instead of DueDate.
+type TaskInput struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	DueOffset   string     `json:"dueOffset,omitempty"`
+	AssigneeID  *int       `json:"assigneeId,omitempty"`
+}
+
+// BulkTaskRequest creates Tasks, preceded by the tasks of a template when one
+// is given: the built-in Template, or the caller's saved template TemplateID.
+type BulkTaskRequest struct {
+	Template   string      `json:"template,omitempty"`
+	TemplateID *int        `json:"templateId,omitempty"`
+	Tasks      []TaskInput `json:"tasks" binding:"dive"`
+}
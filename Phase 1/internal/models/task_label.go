@@ -0,0 +1,32 @@
+package models
+
+// DefaultLabelColor is the color of labels created by putting a new name on
+// a task.
+const DefaultLabelColor = "#9e9e9e"
+
+// TaskLabel is a label an event puts on its tasks. TaskCount, the number of
+// tasks with the label, is only set when listing the event's labels.
+type TaskLabel struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	TaskCount *int   `json:"taskCount,omitempty"`
+}
+
+// TaskLabelRequest creates a label. Color is a hex color such as "#e53935".
+type TaskLabelRequest struct {
+	Name  string `json:"name" binding:"required,max=40"`
+	Color string `json:"color" binding:"required"`
+}
+
+// TaskLabelPatch renames or recolors a label; omitted fields are left alone.
+type TaskLabelPatch struct {
+	Name  *string `json:"name" binding:"omitempty,max=40"`
+	Color *string `json:"color"`
+}
+
+// TaskLabelsRequest replaces a task's labels with those named, creating the
+// event's labels that do not exist yet. An empty list removes them all.
+type TaskLabelsRequest struct {
+	Labels []string `json:"labels" binding:"required,max=20,dive,max=40"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// TaskOrderRequest arranges an event's tasks, in one of two ways: TaskIDs
+// lists tasks in their new order, ahead of those not listed, or Positions
+// moves single tasks.
+type TaskOrderRequest struct {
+	TaskIDs   []int          `json:"taskIds,omitempty"`
+	Positions []TaskPosition `json:"positions,omitempty"`
+}
+
+// TaskPosition places a task. Positions may be fractional, so a task can be
+// moved between those at 2 and 3 by giving it 2.5.
+type TaskPosition struct {
+	TaskID   int     `json:"taskId"`
+	Position float64 `json:"position"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// TemplateTask is one task of a template. DueOffset places its due date
+// relative to the event start: "-14d" is two weeks before, "-1d12h" a day and
+// a half before, "2h" two hours after. Tasks without an offset get no due date.
+type TemplateTask struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Description string `json:"description,omitempty"`
+	DueOffset   string `json:"dueOffset,omitempty"`
+}
+
+// TaskTemplate is a user's reusable task checklist.
+type TaskTemplate struct {
+	ID          int            `json:"id"`
+	UserID      int            `json:"userId"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Tasks       []TemplateTask `json:"tasks"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+type TaskTemplateRequest struct {
+	Name        string         `json:"name" binding:"required,max=100"`
+	Description string         `json:"description"`
+	Tasks       []TemplateTask `json:"tasks" binding:"required,min=1,max=200,dive"`
+}
+
+// TaskTemplateRef selects the template applied to an event: a built-in one by
+// Name, or one of the caller's saved templates by ID.
+type TaskTemplateRef struct {
+	Name string
+	ID   *int
+}
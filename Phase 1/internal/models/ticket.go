@@ -0,0 +1,146 @@
+package models
+
+import "time"
+
+// TicketTier is a kind of ticket for an event (e.g. "Early bird") with its own
+// price and capacity. Remaining is decremented atomically as tickets are claimed.
+type TicketTier struct {
+	ID         int       `json:"id"`
+	EventID    int       `json:"eventId"`
+	Name       string    `json:"name"`
+	PriceCents int       `json:"priceCents"`
+	Currency   string    `json:"currency"`
+	Quantity   int       `json:"quantity"`
+	Remaining  int       `json:"remaining"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type TicketTierRequest struct {
+	Name       string `json:"name" binding:"required,max=100"`
+	PriceCents int    `json:"priceCents" binding:"min=0"`
+	Currency   string `json:"currency" binding:"omitempty,len=3,alpha"`
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+}
+
+// Ticket statuses. Tickets in paid tiers are pending, holding their seat,
+// until the payment is confirmed. Paid tickets are refunding, still holding
+// it, while the payment provider is asked for their refund.
+const (
+	TicketPending   = "pending"
+	TicketClaimed   = "claimed"
+	TicketRefunding = "refunding"
+	TicketCancelled = "cancelled"
+	TicketRefunded  = "refunded"
+)
+
+// Ticket is a seat in a tier. Code is what attendees show at the door.
+// AmountCents is what the holder pays after any promo code discount.
+// CheckoutURL is where the buyer pays for a pending ticket. RefundedCents is
+// what was paid back for a ticket refunded through the API.
+type Ticket struct {
+	ID            int       `json:"id"`
+	TierID        int       `json:"tierId"`
+	TierName      string    `json:"tierName"`
+	EventID       int       `json:"eventId"`
+	UserID        int       `json:"userId"`
+	Code          string    `json:"code"`
+	Status        string    `json:"status"`
+	AmountCents   int       `json:"amountCents"`
+	DiscountCents int       `json:"discountCents"`
+	Currency      string    `json:"currency"`
+	CheckoutURL   *string   `json:"checkoutUrl,omitempty"`
+	RefundedCents *int      `json:"refundedCents,omitempty"`
+	SessionID     *string   `json:"-"`
+	PaymentID     *string   `json:"-"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// TransferRequest hands the caller's spot in an event to another user.
+type TransferRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// TransferPolicyRequest turns spot transfers on or off for an event.
+type TransferPolicyRequest struct {
+	Allowed *bool `json:"allowed" binding:"required"`
+}
+
+// Transfer is a completed spot transfer. TicketID is set when a ticket moved
+// with the spot; the ticket gets a new door code.
+type Transfer struct {
+	ID         int       `json:"id"`
+	EventID    int       `json:"eventId"`
+	FromUserID int       `json:"fromUserId"`
+	ToUserID   int       `json:"toUserId"`
+	TicketID   *int      `json:"ticketId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// SalesAmounts are an event's ticket sales in one currency, in its minor
+// units. Tickets and RevenueCents count claimed tickets.
+type SalesAmounts struct {
+	Currency      string `json:"currency"`
+	Tickets       int    `json:"tickets"`
+	RevenueCents  int64  `json:"revenueCents"`
+	PendingCents  int64  `json:"pendingCents"`
+	RefundedCents int64  `json:"refundedCents"`
+}
+
+// FXRate is the exchange rate used to convert From amounts into To.
+type FXRate struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Rate float64 `json:"rate"`
+}
+
+// Conversion describes the exchange rates a sales total was converted with.
+type Conversion struct {
+	Rates  []FXRate  `json:"rates"`
+	AsOf   time.Time `json:"asOf"`
+	Source string    `json:"source"`
+}
+
+// SalesSummary reports an event's ticket sales per currency and, converted,
+// in one report currency. Total is null when the amounts could not be
+// converted; ConversionError says why.
+type SalesSummary struct {
+	EventID         int            `json:"eventId"`
+	ByCurrency      []SalesAmounts `json:"byCurrency"`
+	Total           *SalesAmounts  `json:"total"`
+	Conversion      *Conversion    `json:"conversion,omitempty"`
+	ConversionError string         `json:"conversionError,omitempty"`
+}
+
+// RefundRule refunds Percent of a ticket's price when it is cancelled at
+// least DaysBefore days before the event starts.
+type RefundRule struct {
+	DaysBefore int `json:"daysBefore" binding:"min=0,max=365"`
+	Percent    int `json:"percent" binding:"min=0,max=100"`
+}
+
+// RefundPolicyRequest replaces an event's refund rules. An empty list removes
+// the policy, leaving refunds to organizers.
+type RefundPolicyRequest struct {
+	Rules []RefundRule `json:"rules" binding:"max=10,dive"`
+}
+
+// RefundPolicy is an event's refund rules, most days before first.
+type RefundPolicy struct {
+	EventID int          `json:"eventId"`
+	Rules   []RefundRule `json:"rules"`
+}
+
+// RefundQuote is what cancelling a paid ticket now would refund under the
+// event's policy. Rule is the rule that applies, if any, and Deadline is when
+// it stops applying.
+type RefundQuote struct {
+	TicketID    int         `json:"ticketId"`
+	Eligible    bool        `json:"eligible"`
+	Percent     int         `json:"percent"`
+	AmountCents int         `json:"amountCents"`
+	RefundCents int         `json:"refundCents"`
+	Currency    string      `json:"currency"`
+	Rule        *RefundRule `json:"rule,omitempty"`
+	Deadline    *time.Time  `json:"deadline,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// TimeEntry is time a user spent on a task, tracked with a timer or logged
+// by hand. EndedAt is nil while the timer runs; Minutes then counts up to
+// now.
+type TimeEntry struct {
+	ID        int        `json:"id"`
+	TaskID    int        `json:"taskId"`
+	UserID    int        `json:"userId"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// TimeEntryRequest logs time by hand. StartedAt defaults to Minutes before
+// now.
+type TimeEntryRequest struct {
+	StartedAt *time.Time `json:"startedAt"`
+	Minutes   int        `json:"minutes" binding:"required,min=1,max=1440"`
+	Note      string     `json:"note" binding:"max=500"`
+}
+
+// TimerRequest starts a timer on a task, with an optional note.
+type TimerRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// EventTimeTotals sum up the time estimated for and tracked on an event's
+// tasks, per task and per user. Running timers count up to now.
+type EventTimeTotals struct {
+	EventID          int             `json:"eventId"`
+	EstimatedMinutes int             `json:"estimatedMinutes"`
+	TrackedMinutes   int             `json:"trackedMinutes"`
+	Tasks            []TaskTimeTotal `json:"tasks"`
+	Users            []UserTimeTotal `json:"users"`
+}
+
+type TaskTimeTotal struct {
+	TaskID           int    `json:"taskId"`
+	Title            string `json:"title"`
+	EstimatedMinutes *int   `json:"estimatedMinutes"`
+	TrackedMinutes   int    `json:"trackedMinutes"`
+}
+
+type UserTimeTotal struct {
+	UserID         int    `json:"userId"`
+	Name           string `json:"name"`
+	TrackedMinutes int    `json:"trackedMinutes"`
+}
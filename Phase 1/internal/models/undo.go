@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actions that can be undone.
+const (
+	UndoDeleteEvents = "delete_events"
+	UndoCancelEvents = "cancel_events"
+	UndoRevokeInvite = "revoke_invite"
+)
+
+// Undo is handed out by a destructive action: POST /undo/{token} reverses it
+// until ExpiresAt.
+type Undo struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// UndoAction is a stored undo token. Payload holds what the action removed,
+// e.g. the participant row of a revoked invitation.
+type UndoAction struct {
+	UserID    int
+	Action    string
+	EventIDs  []int
+	Payload   json.RawMessage
+	ExpiresAt time.Time
+}
+
+// UndoResult describes the action that was reversed.
+type UndoResult struct {
+	Action   string `json:"action"`
+	EventIDs []int  `json:"eventIds"`
+}
@@ -21,3 +21,21 @@ type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+// UserSummary is a user as found by user search. Email is only included when
+// the search matched it exactly, so searching by name never reveals emails.
+type UserSummary struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// LocaleRequest sets the language notifications and emails are sent in.
+type LocaleRequest struct {
+	Locale string `json:"locale" binding:"required"`
+}
+
+// CurrencyRequest sets the currency reports are shown in.
+type CurrencyRequest struct {
+	Currency string `json:"currency" binding:"required,len=3,alpha"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Vendor contract statuses.
+const (
+	ContractNone      = "none"
+	ContractDraft     = "draft"
+	ContractSent      = "sent"
+	ContractSigned    = "signed"
+	ContractCancelled = "cancelled"
+)
+
+// Vendor payment statuses.
+const (
+	VendorUnpaid      = "unpaid"
+	VendorDepositPaid = "deposit_paid"
+	VendorPaid        = "paid"
+)
+
+// Vendor is a supplier an event works with, such as a caterer or AV company.
+// AmountCents is the contracted price, if agreed yet, and PaidCents what has
+// been paid so far, both in Currency. TaskIDs are the event's tasks linked to
+// the vendor.
+type Vendor struct {
+	ID             int        `json:"id"`
+	EventID        int        `json:"eventId"`
+	Name           string     `json:"name"`
+	Category       string     `json:"category"`
+	ContactName    string     `json:"contactName"`
+	Email          string     `json:"email"`
+	Phone          string     `json:"phone"`
+	Website        string     `json:"website"`
+	Notes          string     `json:"notes"`
+	ContractStatus string     `json:"contractStatus"`
+	ContractURL    *string    `json:"contractUrl,omitempty"`
+	AmountCents    *int       `json:"amountCents,omitempty"`
+	PaidCents      int        `json:"paidCents"`
+	Currency       string     `json:"currency"`
+	PaymentStatus  string     `json:"paymentStatus"`
+	PaymentDueAt   *time.Time `json:"paymentDueAt,omitempty"`
+	TaskIDs        []int      `json:"taskIds"`
+	CreatedBy      *int       `json:"createdBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+type VendorRequest struct {
+	Name           string     `json:"name" binding:"required,max=200"`
+	Category       string     `json:"category" binding:"max=50"`
+	ContactName    string     `json:"contactName" binding:"max=200"`
+	Email          string     `json:"email" binding:"omitempty,email"`
+	Phone          string     `json:"phone" binding:"max=50"`
+	Website        string     `json:"website" binding:"omitempty,url"`
+	Notes          string     `json:"notes" binding:"max=5000"`
+	ContractStatus string     `json:"contractStatus" binding:"omitempty,oneof=none draft sent signed cancelled"`
+	ContractURL    string     `json:"contractUrl" binding:"omitempty,url"`
+	AmountCents    *int       `json:"amountCents" binding:"omitempty,min=0"`
+	PaidCents      int        `json:"paidCents" binding:"min=0"`
+	Currency       string     `json:"currency" binding:"omitempty,len=3,alpha"`
+	PaymentStatus  string     `json:"paymentStatus" binding:"omitempty,oneof=unpaid deposit_paid paid"`
+	PaymentDueAt   *time.Time `json:"paymentDueAt"`
+	TaskIDs        []int      `json:"taskIds" binding:"max=100"`
+}
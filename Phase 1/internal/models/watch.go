@@ -0,0 +1,9 @@
+package models
+
+// Watching is what a participant watches in an event: the event itself
+// and/or some of its tasks.
+type Watching struct {
+	EventID int   `json:"eventId"`
+	Event   bool  `json:"event"`
+	TaskIDs []int `json:"taskIds"`
+}
@@ -0,0 +1,63 @@
+package moderation
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Keywords blocks or flags content containing listed words or phrases. Words
+// match whole and ignoring case, so "scam" does not match "scampi"; a phrase
+// matches its words in order, whatever punctuation separates them.
+type Keywords struct {
+	block []string
+	flag  []string
+}
+
+func NewKeywords(block, flag []string) *Keywords {
+	return &Keywords{block: normalizeAll(block), flag: normalizeAll(flag)}
+}
+
+// Review blocks content with any block word. Otherwise content with a flag
+// word is flagged.
+func (k *Keywords) Review(ctx context.Context, c Content) (Decision, error) {
+	text := normalize(c.Title + " " + c.Description + " " + c.Location)
+	if found := matches(text, k.block); len(found) > 0 {
+		return Decision{Verdict: Block, Reasons: found}, nil
+	}
+	if found := matches(text, k.flag); len(found) > 0 {
+		return Decision{Verdict: Flag, Reasons: found}, nil
+	}
+	return Decision{Verdict: Allow}, nil
+}
+
+// matches returns a reason for each listed word found in the normalized text.
+func matches(text string, words []string) []string {
+	var found []string
+	for _, w := range words {
+		if strings.Contains(text, " "+w+" ") {
+			found = append(found, "contains "+strconv.Quote(w))
+		}
+	}
+	return found
+}
+
+// normalize lowercases s and reduces it to its words separated by single
+// spaces, with a space at either end.
+func normalize(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+func normalizeAll(words []string) []string {
+	var out []string
+	for _, w := range words {
+		if n := normalize(w); n != "  " {
+			out = append(out, strings.TrimSpace(n))
+		}
+	}
+	return out
+}
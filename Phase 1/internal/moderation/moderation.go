@@ -0,0 +1,67 @@
+// Package moderation screens event content before it is made public, through
+// a pluggable moderator.
+package moderation
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// Verdicts a moderator can reach.
+const (
+	Allow = "allow"
+	// Flag publishes the event but holds it back from the public until an
+	// admin has reviewed it.
+	Flag = "flag"
+	// Block refuses to publish the event.
+	Block = "block"
+)
+
+// Content is the public text of an event.
+type Content struct {
+	Title       string
+	Description string
+	Location    string
+}
+
+// Decision is a moderator's verdict with the reasons for it, e.g. the
+// prohibited words found.
+type Decision struct {
+	Verdict string
+	Reasons []string
+}
+
+// Moderator reviews content before it is published.
+type Moderator interface {
+	Review(ctx context.Context, c Content) (Decision, error)
+}
+
+// NewFromEnv selects a moderator from MODERATION_PROVIDER ("keywords" or
+// "none"). Content is not screened unless a moderator is configured.
+func NewFromEnv() Moderator {
+	switch os.Getenv("MODERATION_PROVIDER") {
+	case "keywords":
+		return NewKeywords(splitList(os.Getenv("MODERATION_BLOCK_WORDS")), splitList(os.Getenv("MODERATION_FLAG_WORDS")))
+	default:
+		return Noop{}
+	}
+}
+
+// Noop allows everything.
+type Noop struct{}
+
+func (Noop) Review(ctx context.Context, c Content) (Decision, error) {
+	return Decision{Verdict: Allow}, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
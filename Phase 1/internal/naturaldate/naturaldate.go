@@ -0,0 +1,174 @@
+// Package naturaldate reads dates written the way people say them, such as
+// "next monday", "in 2 weeks" or "march 3", as periods of whole days.
+package naturaldate
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnrecognized is returned for values that name no date.
+var ErrUnrecognized = errors.New("unrecognized date")
+
+var (
+	// ordinal matches day suffixes such as the "rd" of "march 3rd".
+	ordinal = regexp.MustCompile(`\b(\d{1,2})(st|nd|rd|th)\b`)
+	// relative matches "in 3 days", "2 weeks ago" and "1 month from now".
+	relative = regexp.MustCompile(`^(?:in (\d+|a|an|one) (day|week|month|year)s?|(\d+|a|an|one) (day|week|month|year)s? (ago|from now))$`)
+	// dayLayouts, yearlessLayouts and monthLayouts are the calendar dates
+	// Parse reads; month names match in any case.
+	dayLayouts      = []string{"2006-01-02", "Jan 2 2006", "January 2 2006", "2 Jan 2006", "2 January 2006"}
+	yearlessLayouts = []string{"Jan 2", "January 2", "2 Jan", "2 January"}
+	monthLayouts    = []string{"2006-01", "Jan 2006", "January 2006"}
+	weekdays        = map[string]time.Weekday{
+		"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+		"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+		"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	}
+)
+
+// Parse returns the period [from, to) named by value, in now's location. It
+// reads:
+//
+//   - dates: "2027-03-04", "march 4", "4 march 2027"; without a year, the
+//     date in now's year, so "feb 29" is unrecognized outside leap years
+//   - months: "2027-03", "march 2027"
+//   - days relative to now: "today", "tomorrow", "yesterday", "in 2 weeks",
+//     "3 days ago", "1 month from now"
+//   - weekdays: "friday" or "this friday" is the coming Friday, today if it
+//     is one; "next friday" the first one after today; "last friday" the
+//     last one before today
+//   - calendar periods: "this week", "next week", "last week" and the same
+//     with month and year. Weeks start on Monday.
+//   - "weekend", the current or coming Saturday and Sunday
+//
+// The compact "nextweek", "thisweek" and "thismonth" are accepted too;
+// "nextweek" is the single day a week from today.
+//
+// Periods are built from calendar days, so they stay aligned to midnight
+// across DST changes.
+func Parse(value string, now time.Time) (from, to time.Time, err error) {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	day := func(offset int) time.Time {
+		return time.Date(today.Year(), today.Month(), today.Day()+offset, 0, 0, 0, 0, loc)
+	}
+	oneDay := func(t time.Time) (time.Time, time.Time, error) {
+		return t, t.AddDate(0, 0, 1), nil
+	}
+	s := strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(value, ",", " "))), " ")
+	s = ordinal.ReplaceAllString(s, "$1")
+
+	switch s {
+	case "today", "now":
+		return oneDay(today)
+	case "tomorrow":
+		return oneDay(day(1))
+	case "yesterday":
+		return oneDay(day(-1))
+	case "nextweek":
+		return oneDay(day(7))
+	case "weekend", "this weekend":
+		saturday := (int(time.Saturday) - int(today.Weekday()) + 7) % 7
+		if today.Weekday() == time.Sunday {
+			saturday = -1
+		}
+		return day(saturday), day(saturday + 2), nil
+	case "thisweek":
+		s = "this week"
+	case "thismonth":
+		s = "this month"
+	}
+
+	if m := relative.FindStringSubmatch(s); m != nil {
+		n, unit, sign := m[1], m[2], 1
+		if n == "" {
+			n, unit = m[3], m[4]
+			if m[5] == "ago" {
+				sign = -1
+			}
+		}
+		count, err := strconv.Atoi(n)
+		if err != nil {
+			count = 1 // "a", "an", "one"
+		}
+		count *= sign
+		switch unit {
+		case "day":
+			return oneDay(day(count))
+		case "week":
+			return oneDay(day(7 * count))
+		case "month":
+			return oneDay(addMonths(today, count))
+		default:
+			return oneDay(addMonths(today, 12*count))
+		}
+	}
+
+	if which, unit, ok := strings.Cut(s, " "); ok {
+		offset, known := map[string]int{"this": 0, "next": 1, "last": -1}[which]
+		if known {
+			if wd, ok := weekdays[unit]; ok {
+				ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+				switch which {
+				case "next":
+					if ahead == 0 {
+						ahead = 7
+					}
+				case "last":
+					ahead -= 7
+				}
+				return oneDay(day(ahead))
+			}
+			switch unit {
+			case "week":
+				monday := -((int(today.Weekday()) + 6) % 7) + 7*offset
+				return day(monday), day(monday + 7), nil
+			case "month":
+				first := time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, loc)
+				return first, first.AddDate(0, 1, 0), nil
+			case "year":
+				first := time.Date(today.Year()+offset, time.January, 1, 0, 0, 0, 0, loc)
+				return first, first.AddDate(1, 0, 0), nil
+			}
+		}
+	}
+	if wd, ok := weekdays[s]; ok {
+		return oneDay(day((int(wd) - int(today.Weekday()) + 7) % 7))
+	}
+
+	for _, layout := range dayLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return oneDay(t)
+		}
+	}
+	for _, layout := range yearlessLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			// Parsed without a year, "feb 29" is valid; it is not in every year.
+			date := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			if date.Day() != t.Day() {
+				return time.Time{}, time.Time{}, ErrUnrecognized
+			}
+			return oneDay(date)
+		}
+	}
+	for _, layout := range monthLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, t.AddDate(0, 1, 0), nil
+		}
+	}
+	return time.Time{}, time.Time{}, ErrUnrecognized
+}
+
+// addMonths moves t by n months, to the last day of the month when it has
+// fewer days than t's, so "in 1 month" from January 31st is February 28th and
+// not March 3rd.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), last), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,87 @@
+package naturaldate
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	// Sunday, the last day of January.
+	sunday := time.Date(2027, 1, 31, 15, 4, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		value    string
+		now      time.Time
+		from, to time.Time
+	}{
+		{"today", sunday, date(2027, 1, 31), date(2027, 2, 1)},
+		{"tomorrow", sunday, date(2027, 2, 1), date(2027, 2, 2)},
+		{"  In   2 WEEKS ", sunday, date(2027, 2, 14), date(2027, 2, 15)},
+		{"3 days ago", sunday, date(2027, 1, 28), date(2027, 1, 29)},
+		{"in 0 days", sunday, date(2027, 1, 31), date(2027, 2, 1)},
+		{"nextweek", sunday, date(2027, 2, 7), date(2027, 2, 8)},
+
+		// Month ends.
+		{"in 1 month", sunday, date(2027, 2, 28), date(2027, 3, 1)},
+		{"a month ago", date(2027, 3, 31), date(2027, 2, 28), date(2027, 3, 1)},
+		{"in a year", date(2028, 2, 29), date(2029, 2, 28), date(2029, 3, 1)},
+		{"next month", sunday, date(2027, 2, 1), date(2027, 3, 1)},
+		{"2027-02", sunday, date(2027, 2, 1), date(2027, 3, 1)},
+		{"feb 2028", sunday, date(2028, 2, 1), date(2028, 3, 1)},
+		{"feb 29 2028", sunday, date(2028, 2, 29), date(2028, 3, 1)},
+		{"feb 29", date(2028, 1, 1), date(2028, 2, 29), date(2028, 3, 1)},
+
+		// Weekdays and weeks, from a Sunday.
+		{"sunday", sunday, date(2027, 1, 31), date(2027, 2, 1)},
+		{"this sunday", sunday, date(2027, 1, 31), date(2027, 2, 1)},
+		{"next sunday", sunday, date(2027, 2, 7), date(2027, 2, 8)},
+		{"last sunday", sunday, date(2027, 1, 24), date(2027, 1, 25)},
+		{"next fri", sunday, date(2027, 2, 5), date(2027, 2, 6)},
+		{"this week", sunday, date(2027, 1, 25), date(2027, 2, 1)},
+		{"last week", sunday, date(2027, 1, 18), date(2027, 1, 25)},
+		{"weekend", sunday, date(2027, 1, 30), date(2027, 2, 1)},
+		{"weekend", date(2027, 1, 29), date(2027, 1, 30), date(2027, 2, 1)},
+		{"last year", sunday, date(2026, 1, 1), date(2027, 1, 1)},
+
+		// Dates.
+		{"March 3rd", sunday, date(2027, 3, 3), date(2027, 3, 4)},
+		{"3 March, 2027", sunday, date(2027, 3, 3), date(2027, 3, 4)},
+		{"2027-03-04", sunday, date(2027, 3, 4), date(2027, 3, 5)},
+
+		// DST: New York moves its clocks forward on 2026-03-08, so that day
+		// is 23 hours long.
+		{"tomorrow", time.Date(2026, 3, 7, 23, 0, 0, 0, newYork),
+			time.Date(2026, 3, 8, 0, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 0, 0, 0, newYork)},
+		{"this week", time.Date(2026, 3, 11, 12, 0, 0, 0, newYork),
+			time.Date(2026, 3, 9, 0, 0, 0, 0, newYork), time.Date(2026, 3, 16, 0, 0, 0, 0, newYork)},
+		{"last week", time.Date(2026, 3, 11, 12, 0, 0, 0, newYork),
+			time.Date(2026, 3, 2, 0, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		from, to, err := Parse(tt.value, tt.now)
+		if err != nil {
+			t.Errorf("Parse(%q, %s): %v", tt.value, tt.now, err)
+			continue
+		}
+		if !from.Equal(tt.from) || !to.Equal(tt.to) || from.Location() != tt.now.Location() {
+			t.Errorf("Parse(%q, %s) = %s .. %s, want %s .. %s", tt.value, tt.now, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	now := time.Date(2027, 1, 31, 15, 4, 0, 0, time.UTC)
+	for _, value := range []string{"", "soon", "next fortnight", "in -1 days", "31st", "feb 30", "feb 29", "feb 29 2027", "2027-13-01", "this monday morning"} {
+		if from, to, err := Parse(value, now); !errors.Is(err, ErrUnrecognized) {
+			t.Errorf("Parse(%q) = %s .. %s, %v, want ErrUnrecognized", value, from, to, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/i18n"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/ratelimit"
+)
+
+// DigestStore holds messages until the digest they are sent in is due.
+type DigestStore interface {
+	AddToDigest(ctx context.Context, key string, item []byte, due time.Time) error
+	TakeDueDigests(ctx context.Context, now time.Time) ([]models.DigestBatch, error)
+}
+
+// DigestOptions configures digests. Messages wait Window for others to be
+// sent along with them. A recipient sent PerHour messages in the last hour
+// gets the next ones in digests too; 0 means no limit.
+type DigestOptions struct {
+	Window  time.Duration
+	PerHour int
+}
+
+// DigestOptionsFromEnv reads NOTIFICATION_DIGEST_MINUTES (10 by default) and
+// NOTIFICATION_HOURLY_LIMIT (20 by default, 0 for no limit).
+func DigestOptionsFromEnv() DigestOptions {
+	opts := DigestOptions{Window: 10 * time.Minute, PerHour: 20}
+	if v, err := strconv.Atoi(os.Getenv("NOTIFICATION_DIGEST_MINUTES")); err == nil && v > 0 {
+		opts.Window = time.Duration(v) * time.Minute
+	}
+	if v, err := strconv.Atoi(os.Getenv("NOTIFICATION_HOURLY_LIMIT")); err == nil && v >= 0 {
+		opts.PerHour = v
+	}
+	return opts
+}
+
+// digestItem is a message waiting in a digest.
+type digestItem struct {
+	Channel   string
+	Recipient Recipient
+	Message   Message
+}
+
+// UseDigests makes Dispatch collect, on channels that send to each recipient
+// separately, digest messages and messages over a recipient's hourly limit,
+// and FlushDigests send each recipient's collected messages as one. Other
+// channels, such as the in-app inbox, still get every message at once.
+func (d *Dispatcher) UseDigests(store DigestStore, opts DigestOptions) {
+	d.digests = store
+	d.digestWindow = opts.Window
+	if opts.PerHour > 0 {
+		d.recipientLimit = ratelimit.New(float64(opts.PerHour)/3600, opts.PerHour)
+	}
+}
+
+// digestKey names the digest of recipient r on channel ch.
+func digestKey(ch Channel, r Recipient) string {
+	if r.UserID != 0 {
+		return fmt.Sprintf("%s:user:%d", ch.Name(), r.UserID)
+	}
+	return ch.Name() + ":email:" + strings.ToLower(r.Email)
+}
+
+// digest adds msg to the digests of the recipients who get it in one on ch,
+// and returns those to send it to now.
+func (d *Dispatcher) digest(ctx context.Context, ch Channel, recipients []Recipient, msg Message) ([]Recipient, error) {
+	if d.digests == nil {
+		return recipients, nil
+	}
+	if _, ok := ch.(PerRecipient); !ok {
+		return recipients, nil
+	}
+	now := make([]Recipient, 0, len(recipients))
+	due := time.Now().Add(d.digestWindow)
+	for _, r := range recipients {
+		key := digestKey(ch, r)
+		if !msg.Digest {
+			if d.recipientLimit == nil {
+				now = append(now, r)
+				continue
+			}
+			if ok, _ := d.recipientLimit.Allow(key); ok {
+				now = append(now, r)
+				continue
+			}
+		}
+		item, err := json.Marshal(digestItem{Channel: ch.Name(), Recipient: r, Message: msg})
+		if err != nil {
+			return nil, err
+		}
+		if err := d.digests.AddToDigest(ctx, key, item, due); err != nil {
+			return nil, fmt.Errorf("adding to digest: %w", err)
+		}
+	}
+	return now, nil
+}
+
+// FlushDigests sends every due digest, each recipient's collected messages as
+// one, and returns how many digests it sent.
+func (d *Dispatcher) FlushDigests(ctx context.Context) (int, error) {
+	if d.digests == nil {
+		return 0, nil
+	}
+	batches, err := d.digests.TakeDueDigests(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	sent := 0
+	for _, b := range batches {
+		items := make([]digestItem, 0, len(b.Items))
+		for _, raw := range b.Items {
+			var item digestItem
+			if err := json.Unmarshal(raw, &item); err != nil {
+				log.Printf("dropping unreadable digest item of %s: %v", b.Key, err)
+				continue
+			}
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		ch := d.channel(items[0].Channel)
+		if ch == nil {
+			errs = append(errs, fmt.Errorf("digest %s: unknown channel %q", b.Key, items[0].Channel))
+			continue
+		}
+		msg, err := digestMessage(items)
+		if err == nil {
+			err = d.deliver(ctx, ch, []Recipient{items[0].Recipient}, msg)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest %s: %w", b.Key, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// digestMessage combines the messages of a digest into one: the message
+// itself when there is only one, otherwise one listing each message's
+// subject and body, in the language of the first.
+func digestMessage(items []digestItem) (Message, error) {
+	if len(items) == 1 {
+		return items[0].Message, nil
+	}
+	first := items[0].Message
+	id, err := newID()
+	if err != nil {
+		return Message{}, err
+	}
+	msg := Message{
+		ID:      id,
+		Kind:    "digest",
+		EventID: first.EventID,
+		Subject: i18n.Translate(first.Locale, fmt.Sprintf("You have %d new notifications", len(items))),
+		Locale:  first.Locale,
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		m := item.Message
+		if msg.EventID != nil && (m.EventID == nil || *m.EventID != *msg.EventID) {
+			msg.EventID = nil
+		}
+		parts[i] = m.Subject + "\n" + m.Body
+		msg.Attachments = append(msg.Attachments, m.Attachments...)
+	}
+	msg.Body = strings.Join(parts, "\n\n")
+	return msg, nil
+}
@@ -0,0 +1,384 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+)
+
+// Mailer sends a rendered email.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// failoverCooldown is how long a failed email provider is tried last.
+const failoverCooldown = time.Minute
+
+// defaultSendRate is how many emails per second each provider is sent by
+// default.
+const defaultSendRate = 10
+
+// NewMailerFromEnv returns the mailers named by EMAIL_PROVIDER, a
+// comma-separated list of smtp, sendgrid, mailgun and log in order of
+// preference, failing over from one to the next. Without EMAIL_PROVIDER it
+// uses SMTP when SMTP_HOST is set, otherwise a mailer that only logs, so
+// development setups need no mail server. Emails are sent from EMAIL_FROM,
+// or SMTP_FROM. Each provider but log is sent at most EMAIL_RATE_LIMIT emails
+// per second (10 by default, 0 for no limit), or its own SMTP_RATE_LIMIT,
+// SENDGRID_RATE_LIMIT or MAILGUN_RATE_LIMIT.
+func NewMailerFromEnv() (Mailer, error) {
+	names := os.Getenv("EMAIL_PROVIDER")
+	if names == "" {
+		if os.Getenv("SMTP_HOST") == "" {
+			return LogMailer{}, nil
+		}
+		names = "smtp"
+	}
+	from := os.Getenv("EMAIL_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_FROM")
+	}
+	if from == "" {
+		from = "no-reply@eventplanner.local"
+	}
+	if _, err := netmail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	var providers []Provider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		m, err := mailerFromEnv(name, from)
+		if err != nil {
+			return nil, err
+		}
+		if name != "log" {
+			rate, err := sendRateFromEnv(name)
+			if err != nil {
+				return nil, err
+			}
+			if rate > 0 {
+				m = NewThrottled(m, rate)
+			}
+		}
+		providers = append(providers, Provider{Name: name, Mailer: m})
+	}
+	if len(providers) == 1 {
+		return providers[0].Mailer, nil
+	}
+	return NewFailover(failoverCooldown, providers...), nil
+}
+
+// sendRateFromEnv returns how many emails per second the named provider may
+// be sent, 0 meaning no limit.
+func sendRateFromEnv(name string) (float64, error) {
+	for _, key := range []string{strings.ToUpper(name) + "_RATE_LIMIT", "EMAIL_RATE_LIMIT"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return 0, fmt.Errorf("invalid %s %q", key, v)
+		}
+		return rate, nil
+	}
+	return defaultSendRate, nil
+}
+
+// mailerFromEnv returns the named provider configured from its variables.
+func mailerFromEnv(name, from string) (Mailer, error) {
+	switch name {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, errors.New("smtp needs SMTP_HOST")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTPMailer{
+			addr:     net.JoinHostPort(host, port),
+			host:     host,
+			username: os.Getenv("SMTP_USERNAME"),
+			password: os.Getenv("SMTP_PASSWORD"),
+			from:     from,
+		}, nil
+	case "sendgrid":
+		key := os.Getenv("SENDGRID_API_KEY")
+		if key == "" {
+			return nil, errors.New("sendgrid needs SENDGRID_API_KEY")
+		}
+		return NewSendGrid(key, from), nil
+	case "mailgun":
+		domain, key := os.Getenv("MAILGUN_DOMAIN"), os.Getenv("MAILGUN_API_KEY")
+		if domain == "" || key == "" {
+			return nil, errors.New("mailgun needs MAILGUN_DOMAIN and MAILGUN_API_KEY")
+		}
+		return NewMailgun(domain, key, from, os.Getenv("MAILGUN_API_URL")), nil
+	case "log":
+		return LogMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", name)
+	}
+}
+
+// SMTPMailer sends mail through an SMTP relay with PLAIN auth when credentials are set.
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	if strings.ContainsAny(mail.To+mail.ReplyTo+mail.Subject, "\r\n") {
+		return errors.New("smtp: header values must not contain line breaks")
+	}
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	msg := "From: " + m.from + "\r\n" +
+		"To: " + mail.To + "\r\n"
+	if mail.ReplyTo != "" {
+		msg += "Reply-To: " + mail.ReplyTo + "\r\n"
+	}
+	if mail.DeliveryToken != "" {
+		// Providers quote the Message-ID, or custom headers, in their
+		// delivery reports, which is how reports find their delivery.
+		msg += "Message-ID: <" + mail.DeliveryToken + "@" + m.domain() + ">\r\n" +
+			"X-Delivery-Token: " + mail.DeliveryToken + "\r\n"
+	}
+	msg += "Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n"
+	content, err := mimeBody(mail)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	msg += content
+	if err := smtp.SendMail(m.addr, auth, m.from, []string{mail.To}, []byte(msg)); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// domain is the domain of the sender address, for Message-IDs.
+func (m *SMTPMailer) domain() string {
+	if addr, err := netmail.ParseAddress(m.from); err == nil {
+		if _, domain, ok := strings.Cut(addr.Address, "@"); ok {
+			return domain
+		}
+	}
+	return "eventplanner.local"
+}
+
+// mimeBody returns the Content-Type header and the body of mail: its text,
+// with the HTML as an alternative when set, followed by the base64-encoded
+// attachments in a multipart/mixed body when there are any.
+func mimeBody(mail Mail) (string, error) {
+	contentType, body, err := alternativeBody(mail.Text, mail.HTML)
+	if err != nil {
+		return "", err
+	}
+	if len(mail.Attachments) == 0 {
+		return "Content-Type: " + contentType + "\r\n\r\n" + body, nil
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write([]byte(body)); err != nil {
+		return "", err
+	}
+	for _, a := range mail.Attachments {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return "", err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return "", err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded)); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return "Content-Type: multipart/mixed; boundary=" + w.Boundary() + "\r\n\r\n" + buf.String(), nil
+}
+
+// alternativeBody returns the content type and body of the text of an email:
+// plain text, or multipart/alternative when it has an HTML version.
+func alternativeBody(text, html string) (string, string, error) {
+	if html == "" {
+		return "text/plain; charset=utf-8", text, nil
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {p.contentType}})
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := part.Write([]byte(p.content)); err != nil {
+			return "", "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+	return "multipart/alternative; boundary=" + w.Boundary(), buf.String(), nil
+}
+
+// LogMailer writes emails to the log instead of sending them.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, mail Mail) error {
+	log.Printf("email to %s: %s (%d attachments)", mail.To, mail.Subject, len(mail.Attachments))
+	return nil
+}
+
+// BrandingStore finds the branding of emails about an event.
+type BrandingStore interface {
+	EventBranding(ctx context.Context, eventID int) (*models.Branding, error)
+}
+
+// DeliveryStore records the emails sent and what became of them.
+type DeliveryStore interface {
+	StartDelivery(ctx context.Context, d models.EmailDelivery) (*models.EmailDelivery, error)
+	Advance(ctx context.Context, token, status string, at time.Time, reason string) (bool, error)
+}
+
+// TrackingPixelURLFromEnv returns the base URL of the open-tracking pixel,
+// under PUBLIC_API_URL, the address the API is reachable at from recipients'
+// mail clients. Empty when that is not set, which turns open tracking off.
+func TrackingPixelURLFromEnv() string {
+	base := strings.TrimRight(os.Getenv("PUBLIC_API_URL"), "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/email/open"
+}
+
+// Email delivers messages by email, one mail per recipient, laid out with the
+// email templates.
+type Email struct {
+	mailer     Mailer
+	branding   BrandingStore
+	deliveries DeliveryStore
+	pixelURL   string
+}
+
+func NewEmail(mailer Mailer) *Email {
+	return &Email{mailer: mailer}
+}
+
+// UseBranding makes emails about an event carry the branding of its
+// organizer.
+func (c *Email) UseBranding(store BrandingStore) {
+	c.branding = store
+}
+
+// UseTracking records every email, its attempts and whether it was sent, in
+// store. With a pixelURL, HTML emails load an image from pixelURL/<token> so
+// the open is recorded too.
+func (c *Email) UseTracking(store DeliveryStore, pixelURL string) {
+	c.deliveries = store
+	c.pixelURL = pixelURL
+}
+
+func (c *Email) Name() string { return "email" }
+
+func (c *Email) DeliversPerRecipient() {}
+
+func (c *Email) Deliver(ctx context.Context, recipients []Recipient, msg Message) error {
+	var branding *models.Branding
+	if c.branding != nil && msg.EventID != nil {
+		var err error
+		if branding, err = c.branding.EventBranding(ctx, *msg.EventID); err != nil {
+			return fmt.Errorf("loading branding: %w", err)
+		}
+	}
+	var errs []error
+	for _, r := range recipients {
+		if r.Email == "" {
+			continue
+		}
+		if err := c.deliver(ctx, r, msg, branding); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends msg to r, recording the attempt and its outcome when tracking
+// is on. Failing to record the outcome is only logged, so a sent email is not
+// sent again.
+func (c *Email) deliver(ctx context.Context, r Recipient, msg Message, branding *models.Branding) error {
+	var delivery *models.EmailDelivery
+	if c.deliveries != nil && msg.ID != "" {
+		token, err := newID()
+		if err != nil {
+			return err
+		}
+		d := models.EmailDelivery{MessageID: msg.ID, Token: token, EventID: msg.EventID, Email: r.Email, Kind: msg.Kind, Subject: msg.Subject}
+		if r.UserID != 0 {
+			d.UserID = &r.UserID
+		}
+		if delivery, err = c.deliveries.StartDelivery(ctx, d); err != nil {
+			return fmt.Errorf("recording delivery: %w", err)
+		}
+	}
+	pixelURL := ""
+	if delivery != nil && c.pixelURL != "" {
+		pixelURL = c.pixelURL + "/" + delivery.Token
+	}
+	mail, err := renderEmail(r, msg, branding, pixelURL)
+	if err == nil {
+		if delivery != nil {
+			mail.DeliveryToken = delivery.Token
+		}
+		err = c.mailer.Send(ctx, mail)
+	}
+	if delivery != nil {
+		status, reason := models.DeliverySent, ""
+		if err != nil {
+			status, reason = models.DeliveryFailed, err.Error()
+		}
+		if _, terr := c.deliveries.Advance(ctx, delivery.Token, status, time.Now(), reason); terr != nil {
+			log.Printf("recording outcome of email delivery %d: %v", delivery.ID, terr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Provider is a mailer with the name it is configured by.
+type Provider struct {
+	Name   string
+	Mailer Mailer
+}
+
+// Failover sends each email through the first provider that accepts it. A
+// provider that fails is tried last for the cooldown that follows, so while
+// it is down emails do not wait for it to time out first.
+type Failover struct {
+	providers []Provider
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	downUntil map[string]time.Time
+}
+
+func NewFailover(cooldown time.Duration, providers ...Provider) *Failover {
+	return &Failover{providers: providers, cooldown: cooldown, downUntil: map[string]time.Time{}}
+}
+
+func (f *Failover) Send(ctx context.Context, mail Mail) error {
+	var errs []error
+	for i, p := range f.order(time.Now()) {
+		err := p.Mailer.Send(ctx, mail)
+		if err == nil {
+			f.mu.Lock()
+			delete(f.downUntil, p.Name)
+			f.mu.Unlock()
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		f.mu.Lock()
+		f.downUntil[p.Name] = time.Now().Add(f.cooldown)
+		f.mu.Unlock()
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(f.providers)-1 {
+			log.Printf("email provider %s failed, trying the next one: %v", p.Name, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// order returns the providers in their configured order, those cooling down
+// after a failure last.
+func (f *Failover) order(now time.Time) []Provider {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	up := make([]Provider, 0, len(f.providers))
+	var down []Provider
+	for _, p := range f.providers {
+		if now.Before(f.downUntil[p.Name]) {
+			down = append(down, p)
+		} else {
+			up = append(up, p)
+		}
+	}
+	return append(up, down...)
+}
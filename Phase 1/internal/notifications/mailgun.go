@@ -0,0 +1,98 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Mailgun sends mail through the Mailgun messages API of a sending domain.
+type Mailgun struct {
+	domain string
+	apiKey string
+	from   string
+	apiURL string
+	client *http.Client
+}
+
+// NewMailgun returns a Mailgun mailer for domain. apiURL is the API's base
+// URL, https://api.mailgun.net/v3 when empty; EU domains use
+// https://api.eu.mailgun.net/v3.
+func NewMailgun(domain, apiKey, from, apiURL string) *Mailgun {
+	if apiURL == "" {
+		apiURL = "https://api.mailgun.net/v3"
+	}
+	return &Mailgun{
+		domain: domain,
+		apiKey: apiKey,
+		from:   from,
+		apiURL: strings.TrimRight(apiURL, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (m *Mailgun) Send(ctx context.Context, mail Mail) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fields := [][2]string{
+		{"from", m.from},
+		{"to", mail.To},
+		{"subject", mail.Subject},
+		{"text", mail.Text},
+	}
+	if mail.HTML != "" {
+		fields = append(fields, [2]string{"html", mail.HTML})
+	}
+	if mail.ReplyTo != "" {
+		fields = append(fields, [2]string{"h:Reply-To", mail.ReplyTo})
+	}
+	if mail.DeliveryToken != "" {
+		// User variables come back with the email's events, which is how
+		// their delivery is found.
+		fields = append(fields, [2]string{"h:X-Delivery-Token", mail.DeliveryToken}, [2]string{"v:delivery_token", mail.DeliveryToken})
+	}
+	for _, f := range fields {
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return fmt.Errorf("mailgun: %w", err)
+		}
+	}
+	for _, a := range mail.Attachments {
+		part, err := w.CreateFormFile("attachment", a.Filename)
+		if err != nil {
+			return fmt.Errorf("mailgun: %w", err)
+		}
+		if _, err := part.Write(a.Data); err != nil {
+			return fmt.Errorf("mailgun: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailgun: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.apiURL+"/"+url.PathEscape(m.domain)+"/messages", &buf)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("api", m.apiKey)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailgun: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("mailgun: unexpected status %d: %s", resp.StatusCode, apiErr.Message)
+	}
+	return nil
+}
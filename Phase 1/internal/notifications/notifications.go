@@ -0,0 +1,302 @@
+// Package notifications fans messages out to users over several channels
+// (in-app inbox, email).
+package notifications
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/i18n"
+	"eventplanner-backend/internal/jobs"
+	"eventplanner-backend/internal/ratelimit"
+)
+
+// Recipient is a user a message is delivered to.
+type Recipient struct {
+	UserID int
+	Name   string
+	Email  string
+}
+
+// Message is a notification. EventID links it to an event when set. Mutable
+// messages about an event are not sent to participants who muted it.
+// Attachments are only delivered by email. ID identifies the message across
+// its channels and the retries of its deliveries, and Locale is the language
+// Subject and Body are in; Dispatch sets both. Digest messages are emailed
+// together with the recipient's other messages of the next minutes, when
+// digests are used.
+type Message struct {
+	ID          string
+	Kind        string
+	EventID     *int
+	Subject     string
+	Body        string
+	Mutable     bool
+	Attachments []Attachment
+	Locale      string
+	Digest      bool
+}
+
+// Attachment is a file sent along with a message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Channel delivers a message to recipients over one medium.
+type Channel interface {
+	Name() string
+	Deliver(ctx context.Context, recipients []Recipient, msg Message) error
+}
+
+// PerRecipient is implemented by channels that send to each recipient
+// separately, such as email. Queued deliveries on them are split into one job
+// per recipient, so a retry never repeats a send that already succeeded.
+type PerRecipient interface {
+	DeliversPerRecipient()
+}
+
+// MuteStore tells which users muted an event.
+type MuteStore interface {
+	MutedUsers(ctx context.Context, eventID int, userIDs []int) ([]int, error)
+}
+
+// LocaleStore tells which language users get their messages in.
+type LocaleStore interface {
+	Locales(ctx context.Context, userIDs []int) (map[int]string, error)
+}
+
+// Dispatcher sends each message over every configured channel.
+type Dispatcher struct {
+	channels []Channel
+	queue    jobs.Queue
+	mutes    MuteStore
+	locales  LocaleStore
+
+	digests        DigestStore
+	digestWindow   time.Duration
+	recipientLimit *ratelimit.Limiter
+}
+
+func NewDispatcher(channels ...Channel) *Dispatcher {
+	return &Dispatcher{channels: channels}
+}
+
+// delivery is a queued delivery of one message on one channel.
+type delivery struct {
+	Channel    string
+	Recipients []Recipient
+	Message    Message
+}
+
+var deliverJob = jobs.NewType[delivery]("notifications.deliver")
+
+// UseQueue makes Dispatch hand deliveries to q instead of delivering inline.
+// Each channel is a separate job, so a failing channel is retried on its own.
+func (d *Dispatcher) UseQueue(q jobs.Queue) {
+	deliverJob.Handle(q, func(ctx context.Context, job delivery) error {
+		if ch := d.channel(job.Channel); ch != nil {
+			return ch.Deliver(ctx, job.Recipients, job.Message)
+		}
+		return jobs.Permanent(fmt.Errorf("unknown channel %q", job.Channel))
+	})
+	d.queue = q
+}
+
+// UseMutes makes Dispatch leave out recipients of mutable messages who muted
+// the message's event.
+func (d *Dispatcher) UseMutes(store MuteStore) {
+	d.mutes = store
+}
+
+// UseLocales makes Dispatch translate messages into each recipient's
+// language. Without it, and for recipients without one, messages are sent as
+// written.
+func (d *Dispatcher) UseLocales(store LocaleStore) {
+	d.locales = store
+}
+
+// Dispatch delivers msg on every channel, or enqueues the deliveries when a
+// queue is set. A failing channel does not stop the others; all failures are
+// returned joined.
+func (d *Dispatcher) Dispatch(ctx context.Context, recipients []Recipient, msg Message) error {
+	recipients, err := d.unmuted(ctx, recipients, msg)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	groups, err := d.byLocale(ctx, recipients)
+	if err != nil {
+		return err
+	}
+	if msg.ID == "" {
+		if msg.ID, err = newID(); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for _, g := range groups {
+		localized := msg
+		localized.Subject = i18n.Translate(g.locale, msg.Subject)
+		localized.Body = i18n.Translate(g.locale, msg.Body)
+		localized.Locale = g.locale
+		if err := d.send(ctx, g.recipients, localized); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newID returns a random identifier for a message or an email delivery.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// localeGroup is recipients who get a message in the same language.
+type localeGroup struct {
+	locale     string
+	recipients []Recipient
+}
+
+// byLocale groups recipients by language, in the order each language first
+// appears.
+func (d *Dispatcher) byLocale(ctx context.Context, recipients []Recipient) ([]localeGroup, error) {
+	if d.locales == nil {
+		return []localeGroup{{i18n.Default, recipients}}, nil
+	}
+	ids := make([]int, len(recipients))
+	for i, r := range recipients {
+		ids[i] = r.UserID
+	}
+	locales, err := d.locales.Locales(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading locales: %w", err)
+	}
+	var groups []localeGroup
+	index := map[string]int{}
+	for _, r := range recipients {
+		locale := locales[r.UserID]
+		if locale == "" {
+			locale = i18n.Default
+		}
+		i, ok := index[locale]
+		if !ok {
+			i = len(groups)
+			index[locale] = i
+			groups = append(groups, localeGroup{locale: locale})
+		}
+		groups[i].recipients = append(groups[i].recipients, r)
+	}
+	return groups, nil
+}
+
+// send delivers msg to recipients on every channel, or enqueues the
+// deliveries, leaving out those who get it in a digest.
+func (d *Dispatcher) send(ctx context.Context, recipients []Recipient, msg Message) error {
+	var errs []error
+	for _, ch := range d.channels {
+		now, err := d.digest(ctx, ch, recipients, msg)
+		if err == nil && len(now) > 0 {
+			err = d.deliver(ctx, ch, now, msg)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver delivers msg to recipients on ch, or enqueues the delivery.
+func (d *Dispatcher) deliver(ctx context.Context, ch Channel, recipients []Recipient, msg Message) error {
+	if d.queue == nil {
+		return ch.Deliver(ctx, recipients, msg)
+	}
+	batches := [][]Recipient{recipients}
+	if _, ok := ch.(PerRecipient); ok {
+		batches = make([][]Recipient, len(recipients))
+		for i, r := range recipients {
+			batches[i] = []Recipient{r}
+		}
+	}
+	var errs []error
+	for _, batch := range batches {
+		if err := deliverJob.Enqueue(ctx, d.queue, delivery{Channel: ch.Name(), Recipients: batch, Message: msg}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// channel returns the channel with the given name, or nil.
+func (d *Dispatcher) channel(name string) Channel {
+	for _, ch := range d.channels {
+		if ch.Name() == name {
+			return ch
+		}
+	}
+	return nil
+}
+
+// unmuted drops the recipients who muted msg's event, if msg can be muted.
+func (d *Dispatcher) unmuted(ctx context.Context, recipients []Recipient, msg Message) ([]Recipient, error) {
+	if d.mutes == nil || !msg.Mutable || msg.EventID == nil || len(recipients) == 0 {
+		return recipients, nil
+	}
+	ids := make([]int, len(recipients))
+	for i, r := range recipients {
+		ids[i] = r.UserID
+	}
+	muted, err := d.mutes.MutedUsers(ctx, *msg.EventID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading mutes: %w", err)
+	}
+	if len(muted) == 0 {
+		return recipients, nil
+	}
+	skip := make(map[int]bool, len(muted))
+	for _, id := range muted {
+		skip[id] = true
+	}
+	kept := make([]Recipient, 0, len(recipients))
+	for _, r := range recipients {
+		if !skip[r.UserID] {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
+
+// InboxStore persists in-app notifications.
+type InboxStore interface {
+	AddToInbox(ctx context.Context, userIDs []int, kind, title, body string, eventID *int) error
+}
+
+// InApp stores messages in the recipients' notification inbox.
+type InApp struct {
+	store InboxStore
+}
+
+func NewInApp(store InboxStore) *InApp {
+	return &InApp{store: store}
+}
+
+func (c *InApp) Name() string { return "in-app" }
+
+func (c *InApp) Deliver(ctx context.Context, recipients []Recipient, msg Message) error {
+	ids := make([]int, len(recipients))
+	for i, r := range recipients {
+		ids[i] = r.UserID
+	}
+	return c.store.AddToInbox(ctx, ids, msg.Kind, msg.Subject, msg.Body, msg.EventID)
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"eventplanner-backend/internal/models"
+)
+
+// ErrUnknownProvider is returned for delivery reports of a provider
+// ParseReports does not read.
+var ErrUnknownProvider = errors.New("unknown email provider")
+
+// ParseReports reads the event webhook payload of a mail provider (sendgrid
+// or mailgun) as delivery reports. Events that say nothing about whether the
+// email arrived, such as clicks or deferrals, and events of emails sent
+// without a delivery token are left out.
+func ParseReports(provider string, body []byte) ([]models.DeliveryReport, error) {
+	switch provider {
+	case "sendgrid":
+		return parseSendGridReports(body)
+	case "mailgun":
+		return parseMailgunReports(body)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, provider)
+	}
+}
+
+func parseSendGridReports(body []byte) ([]models.DeliveryReport, error) {
+	var events []struct {
+		Event         string `json:"event"`
+		Timestamp     int64  `json:"timestamp"`
+		Reason        string `json:"reason"`
+		DeliveryToken string `json:"delivery_token"`
+	}
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("sendgrid events: %w", err)
+	}
+	types := map[string]string{
+		"delivered": models.DeliveryDelivered,
+		"bounce":    models.DeliveryBounced,
+		"dropped":   models.DeliveryFailed,
+		"open":      models.DeliveryOpened,
+	}
+	var res []models.DeliveryReport
+	for _, e := range events {
+		status, ok := types[e.Event]
+		if !ok || e.DeliveryToken == "" {
+			continue
+		}
+		r := models.DeliveryReport{Type: status, ID: e.DeliveryToken, Reason: e.Reason}
+		if e.Timestamp > 0 {
+			at := time.Unix(e.Timestamp, 0)
+			r.At = &at
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// parseMailgunReports reads a Mailgun webhook, which carries one event.
+// Temporary failures are retried by Mailgun and left out; permanent ones are
+// bounces.
+func parseMailgunReports(body []byte) ([]models.DeliveryReport, error) {
+	var hook struct {
+		EventData struct {
+			Event          string            `json:"event"`
+			Timestamp      float64           `json:"timestamp"`
+			Severity       string            `json:"severity"`
+			Reason         string            `json:"reason"`
+			UserVariables  map[string]string `json:"user-variables"`
+			DeliveryStatus struct {
+				Message     string `json:"message"`
+				Description string `json:"description"`
+			} `json:"delivery-status"`
+		} `json:"event-data"`
+	}
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("mailgun event: %w", err)
+	}
+	e := hook.EventData
+	token := e.UserVariables["delivery_token"]
+	var status string
+	switch {
+	case e.Event == "delivered":
+		status = models.DeliveryDelivered
+	case e.Event == "opened":
+		status = models.DeliveryOpened
+	case e.Event == "failed" && e.Severity == "permanent":
+		status = models.DeliveryBounced
+	}
+	if status == "" || token == "" {
+		return nil, nil
+	}
+	reason := e.DeliveryStatus.Message
+	if reason == "" {
+		reason = e.DeliveryStatus.Description
+	}
+	if reason == "" {
+		reason = e.Reason
+	}
+	r := models.DeliveryReport{Type: status, ID: token, Reason: reason}
+	if e.Timestamp > 0 {
+		sec, frac := math.Modf(e.Timestamp)
+		at := time.Unix(int64(sec), int64(frac*1e9))
+		r.At = &at
+	}
+	return []models.DeliveryReport{r}, nil
+}
@@ -0,0 +1,125 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	netmail "net/mail"
+	"strings"
+	"time"
+)
+
+// SendGrid sends mail through the SendGrid v3 Web API.
+type SendGrid struct {
+	apiKey string
+	from   string
+	apiURL string
+	client *http.Client
+}
+
+func NewSendGrid(apiKey, from string) *SendGrid {
+	return &SendGrid{
+		apiKey: apiKey,
+		from:   from,
+		apiURL: "https://api.sendgrid.com/v3",
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridMail struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	ReplyTo          *sendGridAddress          `json:"reply_to,omitempty"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+	// CustomArgs come back with the email's events, which is how their
+	// delivery is found.
+	CustomArgs map[string]string `json:"custom_args,omitempty"`
+}
+
+func (m *SendGrid) Send(ctx context.Context, mail Mail) error {
+	from, err := netmail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("sendgrid: sender: %w", err)
+	}
+	body := sendGridMail{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: mail.To}}}},
+		From:             sendGridAddress{Email: from.Address, Name: from.Name},
+		Subject:          mail.Subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: mail.Text}},
+	}
+	if mail.HTML != "" {
+		body.Content = append(body.Content, sendGridContent{Type: "text/html", Value: mail.HTML})
+	}
+	if mail.ReplyTo != "" {
+		body.ReplyTo = &sendGridAddress{Email: mail.ReplyTo}
+	}
+	for _, a := range mail.Attachments {
+		body.Attachments = append(body.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(a.Data),
+			Type:        a.ContentType,
+			Filename:    a.Filename,
+			Disposition: "attachment",
+		})
+	}
+	if mail.DeliveryToken != "" {
+		body.Headers = map[string]string{"X-Delivery-Token": mail.DeliveryToken}
+		body.CustomArgs = map[string]string{"delivery_token": mail.DeliveryToken}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.apiURL+"/mail/send", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		var msgs []string
+		for _, e := range apiErr.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return fmt.Errorf("sendgrid: unexpected status %d: %s", resp.StatusCode, strings.Join(msgs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"eventplanner-backend/internal/i18n"
+	"eventplanner-backend/internal/models"
+)
+
+// DefaultColor is the accent color of emails without branding.
+const DefaultColor = "#4f46e5"
+
+//go:embed templates/email.html templates/email.txt
+var templateFiles embed.FS
+
+var (
+	htmlEmail = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/email.html"))
+	textEmail = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/email.txt"))
+)
+
+// Mail is a rendered email. Text is always set; HTML is its rich version.
+// DeliveryToken identifies a tracked email in the mail provider's delivery
+// reports.
+type Mail struct {
+	To            string
+	ReplyTo       string
+	Subject       string
+	Text          string
+	HTML          string
+	Attachments   []Attachment
+	DeliveryToken string
+}
+
+// emailData is what the email templates are executed with.
+type emailData struct {
+	Locale     string
+	Name       string
+	Subject    string
+	Body       string
+	Paragraphs [][]string
+	LogoURL    string
+	Color      string
+	ReplyTo    string
+	PixelURL   string
+}
+
+// T translates a text of the template into the email's language.
+func (d emailData) T(text string) string {
+	return i18n.Translate(d.Locale, text)
+}
+
+// Greeting opens the email with the recipient's name.
+func (d emailData) Greeting() string {
+	if d.Name == "" {
+		return d.T("Hi,")
+	}
+	return d.T("Hi " + d.Name + ",")
+}
+
+// RenderEmail lays msg out as the email r gets, with branding when msg is
+// about an event whose organizer set one. The message is rendered as it is,
+// so it should already be in r's language.
+func RenderEmail(r Recipient, msg Message, branding *models.Branding) (Mail, error) {
+	return renderEmail(r, msg, branding, "")
+}
+
+// renderEmail is RenderEmail with the open-tracking pixel at pixelURL, if set.
+func renderEmail(r Recipient, msg Message, branding *models.Branding, pixelURL string) (Mail, error) {
+	locale := msg.Locale
+	if locale == "" {
+		locale = i18n.Default
+	}
+	data := emailData{
+		Locale:   locale,
+		Name:     r.Name,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Color:    DefaultColor,
+		PixelURL: pixelURL,
+	}
+	for _, p := range strings.Split(strings.TrimSpace(msg.Body), "\n\n") {
+		data.Paragraphs = append(data.Paragraphs, strings.Split(p, "\n"))
+	}
+	if branding != nil {
+		data.LogoURL = branding.LogoURL
+		data.ReplyTo = branding.ReplyTo
+		if branding.Color != "" {
+			data.Color = branding.Color
+		}
+	}
+	var text, html bytes.Buffer
+	if err := textEmail.Execute(&text, data); err != nil {
+		return Mail{}, err
+	}
+	if err := htmlEmail.Execute(&html, data); err != nil {
+		return Mail{}, err
+	}
+	return Mail{
+		To:          r.Email,
+		ReplyTo:     data.ReplyTo,
+		Subject:     msg.Subject,
+		Text:        text.String(),
+		HTML:        html.String(),
+		Attachments: msg.Attachments,
+	}, nil
+}
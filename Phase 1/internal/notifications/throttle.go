@@ -0,0 +1,38 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/ratelimit"
+)
+
+// Throttled sends at most a set number of emails per second through a
+// mailer, so a burst such as a bulk invite stays under the provider's sending
+// limit. Sends over the limit wait for their turn.
+type Throttled struct {
+	mailer  Mailer
+	limiter *ratelimit.Limiter
+}
+
+// NewThrottled limits m to perSecond emails per second, allowing bursts of as
+// many.
+func NewThrottled(m Mailer, perSecond float64) *Throttled {
+	return &Throttled{mailer: m, limiter: ratelimit.New(perSecond, max(1, int(perSecond)))}
+}
+
+func (t *Throttled) Send(ctx context.Context, mail Mail) error {
+	for {
+		ok, wait := t.limiter.Allow("")
+		if ok {
+			return t.mailer.Send(ctx, mail)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultBrokerPrefix = "eventplanner."
+
+// BrokerFromEnv returns the message broker publisher selected by BROKER
+// ("nats" or "kafka"), or nil when BROKER is unset or "none". NATS reads
+// NATS_URL; Kafka reads KAFKA_BROKERS, a comma-separated list of seed brokers. BROKER_TOPIC_PREFIX is prepended to
+// every topic and defaults to "eventplanner.".
+func BrokerFromEnv() (Publisher, error) {
+	prefix, ok := os.LookupEnv("BROKER_TOPIC_PREFIX")
+	if !ok {
+		prefix = defaultBrokerPrefix
+	}
+	switch broker := os.Getenv("BROKER"); broker {
+	case "", "none":
+		return nil, nil
+	case "nats":
+		natsURL := os.Getenv("NATS_URL")
+		if natsURL == "" {
+			return nil, fmt.Errorf("outbox: NATS_URL is not set")
+		}
+		n, err := NewNATS(natsURL, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case "kafka":
+		var brokers []string
+		for _, b := range strings.Split(os.Getenv("KAFKA_BROKERS"), ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				brokers = append(brokers, b)
+			}
+		}
+		if len(brokers) == 0 {
+			return nil, fmt.Errorf("outbox: KAFKA_BROKERS is not set")
+		}
+		k, err := NewKafka(brokers, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("outbox: unknown broker %q", broker)
+	}
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"eventplanner-backend/internal/models"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Kafka produces messages to the Kafka topic prefix + topic. Records are keyed
+// by the event id from the payload when there is one, so each event's
+// messages land on one partition in order. Publish waits until every in-sync
+// replica has the record; the producer is idempotent, so its own retries do
+// not duplicate it.
+type Kafka struct {
+	client *kgo.Client
+	prefix string
+}
+
+// NewKafka returns a producer for the cluster reachable through seedBrokers
+// ("host:9092"). Brokers are contacted on the first publish.
+func NewKafka(seedBrokers []string, topicPrefix string) (*Kafka, error) {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(seedBrokers...),
+		kgo.ClientID("eventplanner-backend"),
+		// The relay's context has no deadline; give up on records that cannot
+		// be delivered so the relay retries them with backoff.
+		kgo.RecordDeliveryTimeout(30*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: %w", err)
+	}
+	return &Kafka{client: client, prefix: topicPrefix}, nil
+}
+
+func (k *Kafka) Name() string { return "kafka" }
+
+func (k *Kafka) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	key := strconv.FormatInt(msg.ID, 10)
+	var ref struct {
+		EventID *int `json:"eventId"`
+	}
+	if json.Unmarshal(msg.Payload, &ref) == nil && ref.EventID != nil {
+		key = strconv.Itoa(*ref.EventID)
+	}
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	record := &kgo.Record{Topic: k.prefix + msg.Topic, Key: []byte(key), Value: value}
+	if err := k.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("kafka: %w", err)
+	}
+	return nil
+}
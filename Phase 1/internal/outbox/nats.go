@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes messages to a NATS server on the subject prefix + topic
+// (e.g. "eventplanner.event.created"). The connection reconnects on its own;
+// while it is down, publishing fails instead of buffering, so the relay
+// retries the message once the server is back. Core NATS does not acknowledge
+// messages: one buffered when the connection drops may be lost.
+type NATS struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATS connects to rawURL ("nats://[user:pass@]host:4222", or "tls://" for
+// TLS; a user without password is sent as a token). A server that is down at
+// startup is retried in the background.
+func NewNATS(rawURL, subjectPrefix string) (*NATS, error) {
+	if !validSubject(subjectPrefix + models.TopicInviteSent) {
+		return nil, fmt.Errorf("nats: invalid subject prefix %q", subjectPrefix)
+	}
+	conn, err := nats.Connect(rawURL,
+		nats.Name("eventplanner-backend"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &NATS{conn: conn, prefix: subjectPrefix}, nil
+}
+
+func (n *NATS) Name() string { return "nats" }
+
+func (n *NATS) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	subject := n.prefix + msg.Topic
+	if !validSubject(subject) {
+		return fmt.Errorf("nats: invalid subject %q", subject)
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return n.conn.Publish(subject, body)
+}
+
+// validSubject reports whether messages can be published on s: dot-separated
+// non-empty tokens, none of them a wildcard, without whitespace.
+func validSubject(s string) bool {
+	for _, token := range strings.Split(s, ".") {
+		if token == "" || token == "*" || token == ">" || strings.ContainsAny(token, " \t\r\n") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// Package outbox relays domain events from the outbox_events table to
+// publishers (in-process subscribers, webhooks, NATS, Kafka). Repositories write the events
+// in the same transaction as the change they describe, so an event is never
+// lost when the process crashes after committing. Delivery is at least once:
+// a publisher may see a message again if the relay stops between publishing
+// and recording it.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"eventplanner-backend/internal/locks"
+	"eventplanner-backend/internal/models"
+)
+
+// Store reads pending messages and records publishing outcomes.
+type Store interface {
+	ListPending(ctx context.Context, limit int) ([]models.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, deliveredTo []string, lastError string, retryAt time.Time) error
+}
+
+// Publisher hands messages to one destination. Name identifies it in the
+// outbox's record of deliveries, so it must stay stable across releases.
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, msg models.OutboxMessage) error
+}
+
+const (
+	pollInterval = 2 * time.Second
+	batchSize    = 100
+	retryBackoff = 5 * time.Second
+	maxBackoff   = time.Hour
+)
+
+// Relay polls the outbox and publishes pending messages to every publisher.
+// A message that fails on some publisher is retried with exponential backoff,
+// capped at an hour, on the publishers that have not received it yet.
+type Relay struct {
+	store      Store
+	locker     locks.Locker
+	publishers []Publisher
+}
+
+// NewRelay returns a relay that publishes to publishers. Only the instance
+// holding the "outbox.relay" lock publishes, so messages are not sent twice
+// when several instances run.
+func NewRelay(store Store, locker locks.Locker, publishers ...Publisher) *Relay {
+	return &Relay{store: store, locker: locker, publishers: publishers}
+}
+
+// Start polls the outbox until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.drain(ctx); err != nil {
+					log.Printf("outbox: relay failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// drain publishes due messages until none are left.
+func (r *Relay) drain(ctx context.Context) error {
+	lock, err := r.locker.TryLock(ctx, "outbox.relay")
+	if errors.Is(err, locks.ErrNotAcquired) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			log.Printf("outbox: unlock failed: %v", err)
+		}
+	}()
+
+	for {
+		pending, err := r.store.ListPending(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, msg := range pending {
+			if err := r.publish(ctx, msg); err != nil {
+				return err
+			}
+		}
+		if len(pending) < batchSize {
+			return nil
+		}
+	}
+}
+
+// publish sends msg to the publishers that have not received it and records
+// the outcome. Only failures to record are returned.
+func (r *Relay) publish(ctx context.Context, msg models.OutboxMessage) error {
+	delivered := slices.Clone(msg.DeliveredTo)
+	var errs []error
+	for _, p := range r.publishers {
+		if slices.Contains(delivered, p.Name()) {
+			continue
+		}
+		if err := p.Publish(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		delivered = append(delivered, p.Name())
+	}
+	if len(errs) == 0 {
+		return r.store.MarkPublished(ctx, msg.ID)
+	}
+	err := errors.Join(errs...)
+	delay := min(retryBackoff<<min(msg.Attempts, 20), maxBackoff)
+	log.Printf("outbox: %s %d: attempt %d failed, retrying in %s: %v", msg.Topic, msg.ID, msg.Attempts+1, delay, err)
+	return r.store.MarkFailed(ctx, msg.ID, delivered, err.Error(), time.Now().Add(delay))
+}
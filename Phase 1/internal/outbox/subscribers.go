@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"eventplanner-backend/internal/models"
+)
+
+// Subscribers is a publisher that hands messages to in-process handlers by
+// topic. Topics without handlers are acknowledged without doing anything.
+type Subscribers struct {
+	handlers map[string][]func(ctx context.Context, payload json.RawMessage) error
+}
+
+func NewSubscribers() *Subscribers {
+	return &Subscribers{handlers: map[string][]func(context.Context, json.RawMessage) error{}}
+}
+
+func (s *Subscribers) Name() string { return "subscribers" }
+
+// Publish runs every handler of the message's topic. Handlers must tolerate
+// seeing a message twice: when one of them fails, all run again on retry.
+func (s *Subscribers) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	var errs []error
+	for _, h := range s.handlers[msg.Topic] {
+		if err := h(ctx, msg.Payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers fn for messages on topic, decoding their payload into a T.
+func Subscribe[T any](s *Subscribers, topic string, fn func(ctx context.Context, payload T) error) {
+	s.handlers[topic] = append(s.handlers[topic], func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", topic, err)
+		}
+		return fn(ctx, payload)
+	})
+}
@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"eventplanner-backend/internal/models"
+)
+
+// Webhook POSTs each message as JSON to a URL. When a secret is set, requests
+// carry an X-Eventplanner-Signature header of the form "t=<unix>,v1=<hex>",
+// the HMAC-SHA256 of "<t>.<body>" under the secret.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WebhookFromEnv returns a webhook publisher for OUTBOX_WEBHOOK_URL signed
+// with OUTBOX_WEBHOOK_SECRET, or nil when no URL is set.
+func WebhookFromEnv() *Webhook {
+	url := os.Getenv("OUTBOX_WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+	return NewWebhook(url, os.Getenv("OUTBOX_WEBHOOK_SECRET"))
+}
+
+func (w *Webhook) Name() string { return "webhook" }
+
+func (w *Webhook) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Eventplanner-Topic", msg.Topic)
+	req.Header.Set("X-Eventplanner-Delivery", strconv.FormatInt(msg.ID, 10))
+	if w.secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		req.Header.Set("X-Eventplanner-Signature", "t="+ts+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Stripe's minimum session lifetime; the seat is held until it expires.
+	stripeSessionTTL = 30 * time.Minute
+	// Webhooks signed longer ago than this are rejected as replays.
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// Stripe takes payments with Stripe Checkout using the REST API directly.
+type Stripe struct {
+	secretKey     string
+	webhookSecret string
+	successURL    string
+	cancelURL     string
+	apiURL        string
+	client        *http.Client
+}
+
+func NewStripe(secretKey, webhookSecret, successURL, cancelURL string) *Stripe {
+	return &Stripe{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		successURL:    successURL,
+		cancelURL:     cancelURL,
+		apiURL:        "https://api.stripe.com/v1",
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *Stripe) CreateCheckout(ctx context.Context, c Checkout) (*Session, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("client_reference_id", c.Reference)
+	form.Set("success_url", s.successURL)
+	form.Set("cancel_url", s.cancelURL)
+	form.Set("expires_at", strconv.FormatInt(time.Now().Add(stripeSessionTTL).Unix(), 10))
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", strings.ToLower(c.Currency))
+	form.Set("line_items[0][price_data][unit_amount]", strconv.Itoa(c.AmountCents))
+	form.Set("line_items[0][price_data][product_data][name]", c.Description)
+
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := s.call(ctx, http.MethodPost, "/checkout/sessions", form, "", &created); err != nil {
+		return nil, err
+	}
+	if created.ID == "" || created.URL == "" {
+		return nil, fmt.Errorf("stripe: response has no session url")
+	}
+	return &Session{ID: created.ID, URL: created.URL}, nil
+}
+
+func (s *Stripe) Session(ctx context.Context, id string) (*SessionState, error) {
+	var cs stripeSession
+	if err := s.call(ctx, http.MethodGet, "/checkout/sessions/"+url.PathEscape(id), nil, "", &cs); err != nil {
+		return nil, err
+	}
+	return cs.state(), nil
+}
+
+// Refund is idempotent per payment, which is refunded at most once: asking
+// again, as after a timeout, returns the first refund instead of a second.
+func (s *Stripe) Refund(ctx context.Context, paymentID string, amountCents int) error {
+	form := url.Values{}
+	form.Set("payment_intent", paymentID)
+	if amountCents > 0 {
+		form.Set("amount", strconv.Itoa(amountCents))
+	}
+	var refund struct {
+		Status string `json:"status"`
+	}
+	if err := s.call(ctx, http.MethodPost, "/refunds", form, "refund-"+paymentID, &refund); err != nil {
+		return err
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return fmt.Errorf("stripe: refund %s", refund.Status)
+	}
+	return nil
+}
+
+// ParseWebhook verifies the Stripe-Signature header and maps the checkout and
+// refund events to an Event.
+func (s *Stripe) ParseWebhook(payload []byte, header http.Header) (*Event, error) {
+	if err := s.verify(payload, header.Get("Stripe-Signature"), time.Now()); err != nil {
+		return nil, err
+	}
+	var evt struct {
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		return nil, fmt.Errorf("stripe: %w", err)
+	}
+
+	switch evt.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded",
+		"checkout.session.expired", "checkout.session.async_payment_failed":
+		var cs stripeSession
+		if err := json.Unmarshal(evt.Data.Object, &cs); err != nil {
+			return nil, fmt.Errorf("stripe: %w", err)
+		}
+		out := &Event{SessionID: cs.ID, PaymentID: cs.PaymentIntent}
+		switch {
+		case evt.Type == "checkout.session.async_payment_failed" || evt.Type == "checkout.session.expired":
+			out.Kind = EventExpired
+		case cs.state().Status == StatusPaid:
+			out.Kind = EventPaid
+		}
+		// A completed session with a delayed payment method is confirmed by
+		// the async_payment_succeeded event that follows.
+		return out, nil
+	case "charge.refunded":
+		var charge struct {
+			PaymentIntent string `json:"payment_intent"`
+			Refunded      bool   `json:"refunded"`
+		}
+		if err := json.Unmarshal(evt.Data.Object, &charge); err != nil {
+			return nil, fmt.Errorf("stripe: %w", err)
+		}
+		// Partial refunds leave the ticket valid; the ones we issue under a
+		// refund policy release the ticket themselves.
+		if !charge.Refunded {
+			return &Event{}, nil
+		}
+		return &Event{Kind: EventRefunded, PaymentID: charge.PaymentIntent}, nil
+	}
+	return &Event{}, nil
+}
+
+// verify checks a "t=<unix>,v1=<hex hmac>" signature over "<t>.<payload>".
+func (s *Stripe) verify(payload []byte, sigHeader string, now time.Time) error {
+	if s.webhookSecret == "" {
+		return ErrInvalidSignature
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > stripeSignatureTolerance || d < -stripeSignatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range sigs {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// call sends a form-encoded request and decodes the JSON response into out.
+// Requests with an idempotency key are performed by Stripe at most once.
+func (s *Stripe) call(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("stripe: unexpected status %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("stripe: %w", err)
+	}
+	return nil
+}
+
+type stripeSession struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	PaymentStatus string `json:"payment_status"`
+	PaymentIntent string `json:"payment_intent"`
+}
+
+func (cs stripeSession) state() *SessionState {
+	switch {
+	case cs.PaymentStatus == "paid":
+		return &SessionState{Status: StatusPaid, PaymentID: cs.PaymentIntent}
+	case cs.Status == "expired":
+		return &SessionState{Status: StatusExpired}
+	}
+	return &SessionState{Status: StatusOpen}
+}
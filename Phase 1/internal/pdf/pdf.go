@@ -0,0 +1,138 @@
+// Package pdf writes simple text documents as PDF without external
+// dependencies. Pages hold text in the standard Helvetica fonts, which every
+// PDF reader ships, plus lines and rectangles. Text is WinAnsi encoded:
+// characters outside Latin-1 print as '?'.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Page sizes in points (1/72 inch).
+var (
+	A4          = Size{595, 842}
+	A4Landscape = Size{842, 595}
+)
+
+// Size is a page width and height in points.
+type Size struct {
+	Width, Height float64
+}
+
+// Font selects one of the built-in fonts.
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Document is a PDF being built page by page.
+type Document struct {
+	size  Size
+	title string
+	pages []*Page
+}
+
+// Page is one page of a Document. Coordinates start at the bottom left.
+type Page struct {
+	size    Size
+	content bytes.Buffer
+}
+
+func New(size Size, title string) *Document {
+	return &Document{size: size, title: title}
+}
+
+// AddPage starts a new, empty page.
+func (d *Document) AddPage() *Page {
+	p := &Page{size: d.size}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Size is the page size of the document.
+func (p *Page) Size() Size {
+	return p.size
+}
+
+// Text writes s with its baseline starting at (x, y).
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", font+1, num(size), num(x), num(y), escape(s))
+}
+
+// TextCentered writes s centered horizontally on the page.
+func (p *Page) TextCentered(y float64, font Font, size float64, s string) {
+	p.Text((p.size.Width-TextWidth(s, font, size))/2, y, font, size, s)
+}
+
+// Line draws a straight line.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Rect draws the outline of a rectangle with its bottom left corner at (x, y).
+func (p *Page) Rect(x, y, w, h, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(width), num(x), num(y), num(w), num(h))
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	// Objects 1-5 are fixed; each page then takes two: itself and its content.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (eventplanner) >>", escape(d.title)))
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(p.size.Width), num(p.size.Height), 7+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// num formats a coordinate without needless decimals.
+func num(f float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+}
+
+// escape encodes s as the contents of a PDF string literal in WinAnsi.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
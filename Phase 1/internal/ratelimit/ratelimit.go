@@ -0,0 +1,77 @@
+// Package ratelimit throttles callers with one token bucket per key, e.g. per
+// user. Buckets live in memory, so each server instance enforces its own
+// limit.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// idleTTL is how long an unused bucket is kept. A bucket idle this long is
+// full again anyway, so forgetting it changes nothing.
+const idleTTL = 10 * time.Minute
+
+// Limiter allows each key Rate requests per second on average, with bursts
+// of up to Burst requests.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter refilling rate tokens per second up to burst.
+func New(rate float64, burst int) *Limiter {
+	return &Limiter{rate: rate, burst: float64(burst), buckets: map[string]*bucket{}, lastSweep: time.Now()}
+}
+
+// SetRate changes the limiter's rate and burst. Buckets keep their tokens,
+// up to the new burst.
+func (l *Limiter) SetRate(rate float64, burst int) {
+	l.mu.Lock()
+	l.rate, l.burst = rate, float64(burst)
+	l.mu.Unlock()
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it returns
+// false and how long until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops idle buckets at most once per idleTTL.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AccommodationRepository interface {
+	CreateLodging(ctx context.Context, l models.Lodging) (*models.Lodging, error)
+	UpdateLodging(ctx context.Context, l models.Lodging) (*models.Lodging, error)
+	ListLodgings(ctx context.Context, eventID int) ([]models.Lodging, error)
+	DeleteLodging(ctx context.Context, eventID, lodgingID int) error
+	SetStay(ctx context.Context, s models.Stay) (*models.Stay, error)
+	GetStay(ctx context.Context, eventID, userID int) (*models.Stay, error)
+	DeleteStay(ctx context.Context, eventID, userID int) error
+	ListStays(ctx context.Context, eventID int) ([]models.Stay, error)
+	CountUndecided(ctx context.Context, eventID int) (int, error)
+}
+
+type accommodationRepository struct {
+	pool *database.DB
+}
+
+func NewAccommodationRepository(pool *database.DB) AccommodationRepository {
+	return &accommodationRepository{pool: pool}
+}
+
+const lodgingColumns = `l.id, l.event_id, l.name, l.address, l.url, l.notes, l.rooms, l.price_cents, l.currency, l.booking_deadline,
+	(SELECT count(*) FROM accommodation_stays st WHERE st.lodging_id = l.id), l.created_at, l.updated_at`
+
+func scanLodging(row pgx.Row) (*models.Lodging, error) {
+	var l models.Lodging
+	if err := row.Scan(&l.ID, &l.EventID, &l.Name, &l.Address, &l.URL, &l.Notes, &l.Rooms, &l.PriceCents, &l.Currency, &l.BookingDeadline,
+		&l.Guests, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *accommodationRepository) CreateLodging(ctx context.Context, l models.Lodging) (*models.Lodging, error) {
+	q := `
+		INSERT INTO lodgings AS l (event_id, name, address, url, notes, rooms, price_cents, currency, booking_deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + lodgingColumns
+	return scanLodging(r.pool.QueryRow(ctx, q, l.EventID, l.Name, l.Address, l.URL, l.Notes, l.Rooms, l.PriceCents, l.Currency, l.BookingDeadline))
+}
+
+func (r *accommodationRepository) UpdateLodging(ctx context.Context, l models.Lodging) (*models.Lodging, error) {
+	q := `
+		UPDATE lodgings AS l
+		SET name = $3, address = $4, url = $5, notes = $6, rooms = $7, price_cents = $8, currency = $9, booking_deadline = $10, updated_at = now()
+		WHERE l.id = $1 AND l.event_id = $2
+		RETURNING ` + lodgingColumns
+	return scanLodging(r.pool.QueryRow(ctx, q, l.ID, l.EventID, l.Name, l.Address, l.URL, l.Notes, l.Rooms, l.PriceCents, l.Currency, l.BookingDeadline))
+}
+
+func (r *accommodationRepository) ListLodgings(ctx context.Context, eventID int) ([]models.Lodging, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+lodgingColumns+` FROM lodgings l WHERE l.event_id = $1 ORDER BY l.name, l.id`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Lodging{}
+	for rows.Next() {
+		l, err := scanLodging(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *l)
+	}
+	return res, rows.Err()
+}
+
+// DeleteLodging removes a lodging option together with the stays chosen there.
+func (r *accommodationRepository) DeleteLodging(ctx context.Context, eventID, lodgingID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM lodgings WHERE id = $1 AND event_id = $2`, lodgingID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+const stayColumns = `st.event_id, st.user_id, u.name, st.lodging_id, l.name, st.check_in, st.check_out, st.notes, st.updated_at`
+
+const stayFrom = ` FROM accommodation_stays st
+	JOIN users u ON u.id = st.user_id
+	LEFT JOIN lodgings l ON l.id = st.lodging_id`
+
+func scanStay(row pgx.Row) (*models.Stay, error) {
+	var s models.Stay
+	if err := row.Scan(&s.EventID, &s.UserID, &s.Name, &s.LodgingID, &s.LodgingName, &s.CheckIn, &s.CheckOut, &s.Notes, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SetStay records or replaces the participant's stay. A lodging of another
+// event fails with a foreign key violation.
+func (r *accommodationRepository) SetStay(ctx context.Context, s models.Stay) (*models.Stay, error) {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO accommodation_stays (event_id, user_id, lodging_id, check_in, check_out, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (event_id, user_id) DO UPDATE
+		SET lodging_id = EXCLUDED.lodging_id, check_in = EXCLUDED.check_in, check_out = EXCLUDED.check_out,
+			notes = EXCLUDED.notes, updated_at = now()
+	`, s.EventID, s.UserID, s.LodgingID, s.CheckIn, s.CheckOut, s.Notes)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetStay(ctx, s.EventID, s.UserID)
+}
+
+func (r *accommodationRepository) GetStay(ctx context.Context, eventID, userID int) (*models.Stay, error) {
+	q := `SELECT ` + stayColumns + stayFrom + ` WHERE st.event_id = $1 AND st.user_id = $2`
+	return scanStay(r.pool.QueryRow(ctx, q, eventID, userID))
+}
+
+func (r *accommodationRepository) DeleteStay(ctx context.Context, eventID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM accommodation_stays WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListStays returns every stay for the event by lodging and check-in.
+func (r *accommodationRepository) ListStays(ctx context.Context, eventID int) ([]models.Stay, error) {
+	q := `SELECT ` + stayColumns + stayFrom + ` WHERE st.event_id = $1 ORDER BY l.name NULLS LAST, st.check_in, u.name`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Stay{}
+	for rows.Next() {
+		s, err := scanStay(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *s)
+	}
+	return res, rows.Err()
+}
+
+// CountUndecided counts the event's going participants without a stay.
+func (r *accommodationRepository) CountUndecided(ctx context.Context, eventID int) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT count(*) FROM event_participants p
+		WHERE p.event_id = $1 AND p.attendance = 'going'
+			AND NOT EXISTS (SELECT 1 FROM accommodation_stays st WHERE st.event_id = p.event_id AND st.user_id = p.user_id)
+	`, eventID).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// BlockRepository stores the users and email domains users block
+// invitations from.
+type BlockRepository interface {
+	Create(ctx context.Context, b models.UserBlock) (*models.UserBlock, error)
+	List(ctx context.Context, userID int) ([]models.UserBlock, error)
+	Delete(ctx context.Context, id, userID int) error
+	Blocks(ctx context.Context, userID, senderID int) (bool, error)
+}
+
+type blockRepository struct {
+	pool *database.DB
+}
+
+func NewBlockRepository(pool *database.DB) BlockRepository {
+	return &blockRepository{pool: pool}
+}
+
+// blockedSender is the condition that the user in %[1]s blocks the user in
+// %[2]s, by account or by the domain of their email address.
+const blockedSender = `EXISTS (
+	SELECT 1 FROM user_blocks ub JOIN users sender ON sender.id = %[2]s
+	WHERE ub.user_id = %[1]s
+		AND (ub.blocked_user_id = sender.id OR ub.blocked_domain = lower(split_part(sender.email, '@', 2)))
+)`
+
+const blockColumns = `b.id, b.user_id, b.blocked_user_id, COALESCE(u.name, ''), b.blocked_domain, b.created_at`
+
+func scanBlock(row pgx.Row, b *models.UserBlock) error {
+	return row.Scan(&b.ID, &b.UserID, &b.BlockedUserID, &b.BlockedName, &b.Domain, &b.CreatedAt)
+}
+
+func (r *blockRepository) Create(ctx context.Context, b models.UserBlock) (*models.UserBlock, error) {
+	q := `
+		WITH b AS (
+			INSERT INTO user_blocks (user_id, blocked_user_id, blocked_domain)
+			VALUES ($1, $2, $3)
+			RETURNING *
+		)
+		SELECT ` + blockColumns + `
+		FROM b LEFT JOIN users u ON u.id = b.blocked_user_id`
+	var out models.UserBlock
+	if err := scanBlock(r.pool.QueryRow(ctx, q, b.UserID, b.BlockedUserID, b.Domain), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *blockRepository) List(ctx context.Context, userID int) ([]models.UserBlock, error) {
+	q := `
+		SELECT ` + blockColumns + `
+		FROM user_blocks b LEFT JOIN users u ON u.id = b.blocked_user_id
+		WHERE b.user_id = $1
+		ORDER BY b.created_at DESC, b.id DESC`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.UserBlock{}
+	for rows.Next() {
+		var b models.UserBlock
+		if err := scanBlock(rows, &b); err != nil {
+			return nil, err
+		}
+		res = append(res, b)
+	}
+	return res, rows.Err()
+}
+
+func (r *blockRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM user_blocks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Blocks reports whether userID blocks invitations from senderID.
+func (r *blockRepository) Blocks(ctx context.Context, userID, senderID int) (bool, error) {
+	var blocked bool
+	err := r.pool.QueryRow(ctx, `SELECT `+fmt.Sprintf(blockedSender, "$1", "$2"), userID, senderID).Scan(&blocked)
+	return blocked, err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// BoardRepository stores the columns of events' task boards and which
+// column each task is in.
+type BoardRepository interface {
+	Columns(ctx context.Context, eventID int) ([]models.BoardColumn, error)
+	SetColumns(ctx context.Context, eventID int, columns []models.BoardColumnInput) (bool, error)
+	MoveTask(ctx context.Context, eventID, taskID, columnID int, position *float64) (bool, bool, error)
+}
+
+type boardRepository struct {
+	pool *database.DB
+}
+
+func NewBoardRepository(pool *database.DB) BoardRepository {
+	return &boardRepository{pool: pool}
+}
+
+// Columns returns the board's columns in order, without tasks, first
+// creating the default ones for a board never used.
+func (r *boardRepository) Columns(ctx context.Context, eventID int) ([]models.BoardColumn, error) {
+	columns, err := r.listColumns(ctx, eventID)
+	if err != nil || len(columns) > 0 {
+		return columns, err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if err := lockBoard(ctx, tx, eventID); err != nil {
+		return nil, err
+	}
+	const q = `
+		INSERT INTO board_columns (event_id, name, position)
+		SELECT $1, name, position
+		FROM unnest($2::text[]) WITH ORDINALITY AS d(name, position)
+		WHERE NOT EXISTS (SELECT 1 FROM board_columns WHERE event_id = $1)
+	`
+	if _, err := tx.Exec(ctx, q, eventID, models.DefaultBoardColumns); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.listColumns(ctx, eventID)
+}
+
+// SetColumns replaces the board's columns: those with an ID are renamed and
+// moved to their place in the list, those without one added, and the others
+// removed, which takes their tasks out of any column. It reports false,
+// changing nothing, when an ID is not a column of the board.
+func (r *boardRepository) SetColumns(ctx context.Context, eventID int, columns []models.BoardColumnInput) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	if err := lockBoard(ctx, tx, eventID); err != nil {
+		return false, err
+	}
+	kept := []int{}
+	for _, c := range columns {
+		if c.ID != 0 {
+			kept = append(kept, c.ID)
+		}
+	}
+	var found int
+	if err := tx.QueryRow(ctx, `SELECT count(*) FROM board_columns WHERE event_id = $1 AND id = ANY($2)`, eventID, kept).Scan(&found); err != nil {
+		return false, err
+	}
+	if found != len(kept) {
+		return false, nil
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM board_columns WHERE event_id = $1 AND NOT (id = ANY($2))`, eventID, kept); err != nil {
+		return false, err
+	}
+	batch := &pgx.Batch{}
+	for i, c := range columns {
+		if c.ID != 0 {
+			batch.Queue(`UPDATE board_columns SET name = $3, position = $4 WHERE id = $1 AND event_id = $2`, c.ID, eventID, c.Name, i+1)
+		} else {
+			batch.Queue(`INSERT INTO board_columns (event_id, name, position) VALUES ($1, $2, $3)`, eventID, c.Name, i+1)
+		}
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// MoveTask puts the task in the column and, when position is not nil,
+// moves it to that position. It reports whether the column and the task
+// were found; nothing is changed unless both were.
+func (r *boardRepository) MoveTask(ctx context.Context, eventID, taskID, columnID int, position *float64) (bool, bool, error) {
+	var columnFound bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM board_columns WHERE id = $1 AND event_id = $2)`, columnID, eventID).Scan(&columnFound); err != nil || !columnFound {
+		return false, false, err
+	}
+	const q = `
+		UPDATE tasks SET column_id = $3, position = COALESCE($4, position)
+		WHERE id = $1 AND event_id = $2
+	`
+	tag, err := r.pool.Exec(ctx, q, taskID, eventID, columnID, position)
+	if err != nil {
+		return true, false, err
+	}
+	return true, tag.RowsAffected() > 0, nil
+}
+
+// lockBoard serializes changes to the event's columns. NO KEY UPDATE leaves
+// inserts referencing the event unblocked.
+func lockBoard(ctx context.Context, tx pgx.Tx, eventID int) error {
+	var id int
+	return tx.QueryRow(ctx, `SELECT id FROM events WHERE id = $1 FOR NO KEY UPDATE`, eventID).Scan(&id)
+}
+
+func (r *boardRepository) listColumns(ctx context.Context, eventID int) ([]models.BoardColumn, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, name, position FROM board_columns WHERE event_id = $1 ORDER BY position, id`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []models.BoardColumn
+	for rows.Next() {
+		c := models.BoardColumn{Tasks: []models.Task{}}
+		if err := rows.Scan(&c.ID, &c.Name, &c.Position); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
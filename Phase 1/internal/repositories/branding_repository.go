@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type BrandingRepository interface {
+	Get(ctx context.Context, userID int) (*models.Branding, error)
+	Set(ctx context.Context, userID int, b models.Branding) (*models.Branding, error)
+	EventBranding(ctx context.Context, eventID int) (*models.Branding, error)
+}
+
+type brandingRepository struct {
+	pool *database.DB
+}
+
+func NewBrandingRepository(pool *database.DB) BrandingRepository {
+	return &brandingRepository{pool: pool}
+}
+
+// Get returns the user's branding, or nil when they have none.
+func (r *brandingRepository) Get(ctx context.Context, userID int) (*models.Branding, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(logo_url, ''), COALESCE(color, ''), COALESCE(reply_to, ''), updated_at
+		FROM email_branding WHERE user_id = $1
+	`, userID)
+	return scanBranding(row)
+}
+
+// Set replaces the user's branding. Empty fields are stored as NULL.
+func (r *brandingRepository) Set(ctx context.Context, userID int, b models.Branding) (*models.Branding, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO email_branding (user_id, logo_url, color, reply_to)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''))
+		ON CONFLICT (user_id) DO UPDATE
+		SET logo_url = EXCLUDED.logo_url, color = EXCLUDED.color, reply_to = EXCLUDED.reply_to, updated_at = now()
+		RETURNING COALESCE(logo_url, ''), COALESCE(color, ''), COALESCE(reply_to, ''), updated_at
+	`, userID, b.LogoURL, b.Color, b.ReplyTo)
+	return scanBranding(row)
+}
+
+// EventBranding returns the branding of the event's organizer, or nil when
+// they have none.
+func (r *brandingRepository) EventBranding(ctx context.Context, eventID int) (*models.Branding, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(b.logo_url, ''), COALESCE(b.color, ''), COALESCE(b.reply_to, ''), b.updated_at
+		FROM events e
+		JOIN email_branding b ON b.user_id = e.organizer_id
+		WHERE e.id = $1
+	`, eventID)
+	return scanBranding(row)
+}
+
+func scanBranding(row pgx.Row) (*models.Branding, error) {
+	var b models.Branding
+	if err := row.Scan(&b.LogoURL, &b.Color, &b.ReplyTo, &b.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &b, nil
+}
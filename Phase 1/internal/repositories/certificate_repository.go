@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CertificateRepository interface {
+	CheckIn(ctx context.Context, eventID, userID int) (*models.CheckIn, error)
+	UndoCheckIn(ctx context.Context, eventID, userID int) error
+	GetCheckIn(ctx context.Context, eventID, userID int) (*models.CheckIn, error)
+	ListCheckIns(ctx context.Context, eventID int) ([]models.CheckIn, error)
+	GetTemplate(ctx context.Context, eventID int) (*models.CertificateTemplate, error)
+	SetTemplate(ctx context.Context, t models.CertificateTemplate) (*models.CertificateTemplate, error)
+}
+
+type certificateRepository struct {
+	pool *database.DB
+}
+
+func NewCertificateRepository(pool *database.DB) CertificateRepository {
+	return &certificateRepository{pool: pool}
+}
+
+const checkInColumns = `p.user_id, u.name, u.email, p.checked_in_at`
+
+const checkInFrom = ` FROM event_participants p JOIN users u ON u.id = p.user_id`
+
+func scanCheckIn(row pgx.Row) (*models.CheckIn, error) {
+	var ci models.CheckIn
+	if err := row.Scan(&ci.UserID, &ci.Name, &ci.Email, &ci.CheckedInAt); err != nil {
+		return nil, err
+	}
+	return &ci, nil
+}
+
+// CheckIn marks the participant as checked in; checking in twice keeps the
+// first time. pgx.ErrNoRows if the user does not participate in the event.
+func (r *certificateRepository) CheckIn(ctx context.Context, eventID, userID int) (*models.CheckIn, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE event_participants SET checked_in_at = COALESCE(checked_in_at, now())
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetCheckIn(ctx, eventID, userID)
+}
+
+// UndoCheckIn clears a check-in; pgx.ErrNoRows if the participant was not
+// checked in.
+func (r *certificateRepository) UndoCheckIn(ctx context.Context, eventID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE event_participants SET checked_in_at = NULL
+		WHERE event_id = $1 AND user_id = $2 AND checked_in_at IS NOT NULL
+	`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetCheckIn returns the participant's check-in; pgx.ErrNoRows if they were
+// not checked in.
+func (r *certificateRepository) GetCheckIn(ctx context.Context, eventID, userID int) (*models.CheckIn, error) {
+	q := `SELECT ` + checkInColumns + checkInFrom + ` WHERE p.event_id = $1 AND p.user_id = $2 AND p.checked_in_at IS NOT NULL`
+	return scanCheckIn(r.pool.QueryRow(ctx, q, eventID, userID))
+}
+
+// ListCheckIns returns the event's checked-in participants by name.
+func (r *certificateRepository) ListCheckIns(ctx context.Context, eventID int) ([]models.CheckIn, error) {
+	q := `SELECT ` + checkInColumns + checkInFrom + ` WHERE p.event_id = $1 AND p.checked_in_at IS NOT NULL ORDER BY lower(u.name), p.user_id`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.CheckIn{}
+	for rows.Next() {
+		ci, err := scanCheckIn(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *ci)
+	}
+	return res, rows.Err()
+}
+
+// GetTemplate returns the event's certificate wording; pgx.ErrNoRows if it
+// never set one.
+func (r *certificateRepository) GetTemplate(ctx context.Context, eventID int) (*models.CertificateTemplate, error) {
+	var t models.CertificateTemplate
+	err := r.pool.QueryRow(ctx, `
+		SELECT event_id, title, body, signer_name, signer_title, updated_at
+		FROM certificate_templates WHERE event_id = $1
+	`, eventID).Scan(&t.EventID, &t.Title, &t.Body, &t.SignerName, &t.SignerTitle, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *certificateRepository) SetTemplate(ctx context.Context, t models.CertificateTemplate) (*models.CertificateTemplate, error) {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO certificate_templates (event_id, title, body, signer_name, signer_title)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_id) DO UPDATE
+		SET title = EXCLUDED.title, body = EXCLUDED.body, signer_name = EXCLUDED.signer_name,
+			signer_title = EXCLUDED.signer_title, updated_at = now()
+	`, t.EventID, t.Title, t.Body, t.SignerName, t.SignerTitle)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTemplate(ctx, t.EventID)
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/markdown"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CommentRepository stores comments on events and tasks.
+type CommentRepository interface {
+	Create(ctx context.Context, c models.Comment, mentioned []int) (*models.Comment, error)
+	List(ctx context.Context, eventID int, taskID *int) ([]models.Comment, error)
+	ListByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Comment, error)
+	Get(ctx context.Context, eventID, commentID int) (*models.Comment, error)
+	Delete(ctx context.Context, eventID, commentID, authorID int) (bool, error)
+}
+
+type commentRepository struct {
+	pool *database.DB
+}
+
+func NewCommentRepository(pool *database.DB) CommentRepository {
+	return &commentRepository{pool: pool}
+}
+
+// commentColumns lists the columns read by scanComment from comments c
+// joined with their authors u.
+const commentColumns = `c.id, c.event_id, c.task_id, c.user_id, COALESCE(u.name, ''), c.body, c.created_at,
+	COALESCE((
+		SELECT json_agg(json_build_object('userId', mu.id, 'name', mu.name) ORDER BY mu.name)
+		FROM comment_mentions m JOIN users mu ON mu.id = m.user_id
+		WHERE m.comment_id = c.id
+	), '[]')`
+
+const commentFrom = `comments c LEFT JOIN users u ON u.id = c.user_id`
+
+func scanComment(row pgx.Row) (*models.Comment, error) {
+	var c models.Comment
+	if err := row.Scan(&c.ID, &c.EventID, &c.TaskID, &c.UserID, &c.UserName, &c.Body, &c.CreatedAt, &c.Mentions); err != nil {
+		return nil, err
+	}
+	c.BodyHTML = markdown.Render(c.Body)
+	return &c, nil
+}
+
+// Create stores the comment and whom it mentions. It returns pgx.ErrNoRows
+// when the comment is on a task the event does not have.
+func (r *commentRepository) Create(ctx context.Context, c models.Comment, mentioned []int) (*models.Comment, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	const q = `
+		INSERT INTO comments (event_id, task_id, user_id, body)
+		SELECT $1, $2, $3, $4
+		WHERE $2::int IS NULL OR EXISTS (SELECT 1 FROM tasks WHERE id = $2 AND event_id = $1)
+		RETURNING id
+	`
+	var id int
+	if err := tx.QueryRow(ctx, q, c.EventID, c.TaskID, c.UserID, c.Body).Scan(&id); err != nil {
+		return nil, err
+	}
+	if len(mentioned) > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO comment_mentions (comment_id, user_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING`, id, mentioned); err != nil {
+			return nil, err
+		}
+	}
+	created, err := scanComment(tx.QueryRow(ctx, `SELECT `+commentColumns+` FROM `+commentFrom+` WHERE c.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return created, tx.Commit(ctx)
+}
+
+// List returns the comments on the event itself, or on its task when
+// taskID is set, oldest first.
+func (r *commentRepository) List(ctx context.Context, eventID int, taskID *int) ([]models.Comment, error) {
+	q := `
+		SELECT ` + commentColumns + `
+		FROM ` + commentFrom + `
+		WHERE c.event_id = $1 AND c.task_id IS NOT DISTINCT FROM $2
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := r.pool.Query(ctx, q, eventID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	comments := []models.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+	return comments, rows.Err()
+}
+
+// ListByEvents loads the comments on several events themselves, not on their
+// tasks, in one query, keyed by event ID and oldest first.
+func (r *commentRepository) ListByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Comment, error) {
+	q := `
+		SELECT ` + commentColumns + `
+		FROM ` + commentFrom + `
+		WHERE c.event_id = ANY($1) AND c.task_id IS NULL
+		ORDER BY c.event_id, c.created_at, c.id
+	`
+	rows, err := r.pool.Query(ctx, q, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[int][]models.Comment, len(eventIDs))
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		res[c.EventID] = append(res[c.EventID], *c)
+	}
+	return res, rows.Err()
+}
+
+// Get returns a comment on the event or one of its tasks, or pgx.ErrNoRows.
+func (r *commentRepository) Get(ctx context.Context, eventID, commentID int) (*models.Comment, error) {
+	return scanComment(r.pool.QueryRow(ctx, `SELECT `+commentColumns+` FROM `+commentFrom+` WHERE c.id = $1 AND c.event_id = $2`, commentID, eventID))
+}
+
+// Delete removes a comment of the event, only authorID's unless it is 0,
+// and reports whether there was one.
+func (r *commentRepository) Delete(ctx context.Context, eventID, commentID, authorID int) (bool, error) {
+	const q = `DELETE FROM comments WHERE id = $1 AND event_id = $2 AND ($3::int = 0 OR user_id = $3)`
+	tag, err := r.pool.Exec(ctx, q, commentID, eventID, authorID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
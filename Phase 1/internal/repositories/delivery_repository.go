@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// deliveryRanks orders the delivery statuses; Advance only moves a delivery
+// to a later one.
+const deliveryRanks = `ARRAY['pending', 'failed', 'sent', 'delivered', 'bounced', 'opened']`
+
+type DeliveryRepository interface {
+	StartDelivery(ctx context.Context, d models.EmailDelivery) (*models.EmailDelivery, error)
+	Advance(ctx context.Context, token, status string, at time.Time, reason string) (bool, error)
+	ListForEvent(ctx context.Context, eventID int, filter models.DeliveryFilter) ([]models.EmailDelivery, error)
+}
+
+type deliveryRepository struct {
+	pool *database.DB
+}
+
+func NewDeliveryRepository(pool *database.DB) DeliveryRepository {
+	return &deliveryRepository{pool: pool}
+}
+
+// StartDelivery records an attempt to send d: the first one creates the
+// delivery with d's token, retries of the same message to the same address
+// count up its attempts and keep the token it was created with.
+func (r *deliveryRepository) StartDelivery(ctx context.Context, d models.EmailDelivery) (*models.EmailDelivery, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO email_deliveries (message_id, token, user_id, event_id, email, kind, subject, attempts)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, 1)
+		ON CONFLICT (message_id, email) DO UPDATE
+		SET attempts = email_deliveries.attempts + 1, updated_at = now()
+		RETURNING id, token, attempts, status, created_at, updated_at
+	`, d.MessageID, d.Token, d.UserID, d.EventID, d.Email, d.Kind, d.Subject)
+	if err := row.Scan(&d.ID, &d.Token, &d.Attempts, &d.Status, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Advance records that the delivery with token reached status at the given
+// time, with reason as its error for failures and bounces. The status only
+// changes when it is later than the current one; its time is kept from the
+// first report. It reports false when no delivery has token.
+func (r *deliveryRepository) Advance(ctx context.Context, token, status string, at time.Time, reason string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE email_deliveries SET
+			status = CASE WHEN array_position(`+deliveryRanks+`, $2) > array_position(`+deliveryRanks+`, status) THEN $2 ELSE status END,
+			sent_at = CASE WHEN $2 = 'sent' THEN COALESCE(sent_at, $3) ELSE sent_at END,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN COALESCE(delivered_at, $3) ELSE delivered_at END,
+			bounced_at = CASE WHEN $2 = 'bounced' THEN COALESCE(bounced_at, $3) ELSE bounced_at END,
+			opened_at = CASE WHEN $2 = 'opened' THEN COALESCE(opened_at, $3) ELSE opened_at END,
+			last_error = CASE WHEN $4 <> '' THEN $4 WHEN $2 = 'sent' THEN NULL ELSE last_error END,
+			updated_at = now()
+		WHERE token = $1
+	`, token, status, at, reason)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListForEvent returns the emails sent about an event, newest first.
+func (r *deliveryRepository) ListForEvent(ctx context.Context, eventID int, filter models.DeliveryFilter) ([]models.EmailDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT d.id, d.event_id, d.user_id, COALESCE(u.name, ''), d.email, d.kind, d.subject, d.status, d.attempts,
+			COALESCE(d.last_error, ''), d.sent_at, d.delivered_at, d.bounced_at, d.opened_at, d.created_at, d.updated_at
+		FROM email_deliveries d
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE d.event_id = $1
+			AND ($2 = '' OR d.kind = $2)
+			AND ($3 = '' OR d.status = $3)
+			AND ($4 = 0 OR d.user_id = $4)
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT 500
+	`, eventID, filter.Kind, filter.Status, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.EmailDelivery{}
+	for rows.Next() {
+		var d models.EmailDelivery
+		if err := rows.Scan(&d.ID, &d.EventID, &d.UserID, &d.UserName, &d.Email, &d.Kind, &d.Subject, &d.Status, &d.Attempts,
+			&d.LastError, &d.SentAt, &d.DeliveredAt, &d.BouncedAt, &d.OpenedAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
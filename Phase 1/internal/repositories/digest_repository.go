@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+type DigestRepository interface {
+	AddToDigest(ctx context.Context, key string, item []byte, due time.Time) error
+	TakeDueDigests(ctx context.Context, now time.Time) ([]models.DigestBatch, error)
+}
+
+type digestRepository struct {
+	pool *database.DB
+}
+
+func NewDigestRepository(pool *database.DB) DigestRepository {
+	return &digestRepository{pool: pool}
+}
+
+func (r *digestRepository) AddToDigest(ctx context.Context, key string, item []byte, due time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO notification_digests (key, item, due_at) VALUES ($1, $2, $3)
+	`, key, item, due)
+	return err
+}
+
+// TakeDueDigests removes and returns every digest whose oldest item is due at
+// now, each with all its items, so two servers never send the same digest.
+func (r *digestRepository) TakeDueDigests(ctx context.Context, now time.Time) ([]models.DigestBatch, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH taken AS (
+			DELETE FROM notification_digests
+			WHERE key IN (SELECT key FROM notification_digests GROUP BY key HAVING min(due_at) <= $1)
+			RETURNING id, key, item
+		)
+		SELECT key, item FROM taken ORDER BY key, id
+	`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.DigestBatch
+	for rows.Next() {
+		var key string
+		var item json.RawMessage
+		if err := rows.Scan(&key, &item); err != nil {
+			return nil, err
+		}
+		if len(res) == 0 || res[len(res)-1].Key != key {
+			res = append(res, models.DigestBatch{Key: key})
+		}
+		res[len(res)-1].Items = append(res[len(res)-1].Items, item)
+	}
+	return res, rows.Err()
+}
@@ -2,28 +2,79 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/geocoding"
+	"eventplanner-backend/internal/markdown"
 	"eventplanner-backend/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type EventRepository interface {
-	Create(ctx context.Context, title, description, location string, start time.Time, organizerID int) (*models.Event, error)
-	ListByRole(ctx context.Context, userID int, role string) ([]models.Event, error)
-	DeleteIfOrganizer(ctx context.Context, eventID, organizerID int) error
-	Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string) error
+	Create(ctx context.Context, e models.Event, tasks []models.TaskInput) (*models.Event, error)
+	FindDuplicates(ctx context.Context, organizerID int, title string, start time.Time, end *time.Time, similarity float64) ([]models.DuplicateCandidate, error)
+	ListByRole(ctx context.Context, userID int, role string, filter models.EventListFilter) ([]models.EventSummary, error)
+	Delete(ctx context.Context, eventID int) error
+	SetAllowTransfers(ctx context.Context, eventID int, allowed bool) error
+	Publish(ctx context.Context, eventID int, flags []string) (*models.Event, error)
+	Unpublish(ctx context.Context, eventID int) error
+	SetArchived(ctx context.Context, eventID int, archived bool) (*models.Event, error)
+	ArchiveEnded(ctx context.Context, before time.Time) (int64, error)
+	AddSearchPartition(ctx context.Context, year int) error
+	Bulk(ctx context.Context, action string, eventIDs []int) ([]int, error)
+	AcquireEditLock(ctx context.Context, eventID, userID int, ttl time.Duration) (*models.EditLock, bool, error)
+	GetEditLock(ctx context.Context, eventID int) (*models.EditLock, error)
+	ReleaseEditLock(ctx context.Context, eventID, userID int) error
+	Update(ctx context.Context, eventID int, req models.UpdateEventRequest) (*models.Event, error)
+	GetPublished(ctx context.Context, slug string) (*models.Event, string, error)
+	GetIDBySlug(ctx context.Context, slug string) (int, error)
+	Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string, expiresAt *time.Time) error
+	InviteCandidates(ctx context.Context, eventID, inviterID int, userIDs []int, emails []string) ([]models.InviteCandidate, error)
+	InviteMany(ctx context.Context, eventID, inviterID int, inviteeIDs []int, role string, expiresAt *time.Time) (int, error)
+	RevokeInvite(ctx context.Context, eventID, inviteeID, revokedBy int) (json.RawMessage, error)
+	InviteRevoked(ctx context.Context, eventID, userID int) (bool, error)
 	ListParticipants(ctx context.Context, eventID int) ([]models.Participant, error)
-	SetAttendance(ctx context.Context, eventID, userID int, status string) error
-	Search(ctx context.Context, userID int, q string, from, to *time.Time, role string) ([]models.Event, []models.Task, error)
+	CountAttendance(ctx context.Context, eventID int) (*models.AttendanceCounts, error)
+	SetAttendance(ctx context.Context, eventID, userID int, status string, answers []models.RSVPAnswer) error
+	SetMuted(ctx context.Context, eventID, userID int, muted bool) error
+	Search(ctx context.Context, userID int, f models.SearchFilter) ([]models.Event, []models.Task, error)
 	IsOrganizer(ctx context.Context, eventID, userID int) (bool, error)
-	CreateTask(ctx context.Context, eventID int, title, description string, dueDate *time.Time, assigneeID *int) (*models.Task, error)
+	CreateTask(ctx context.Context, eventID int, title, description string, dueDate *time.Time, dueOffset *string, assigneeID *int) (*models.Task, error)
+	CreateTasks(ctx context.Context, eventID int, tasks []models.TaskInput) ([]models.Task, error)
+	UpdateTask(ctx context.Context, eventID, taskID int, patch models.TaskPatch) (*models.Task, error)
+	OrderTasks(ctx context.Context, eventID int, taskIDs []int) (bool, error)
+	PositionTasks(ctx context.Context, eventID int, positions []models.TaskPosition) (bool, error)
+	SessionSpan(ctx context.Context, eventID int) (*time.Time, *time.Time, error)
+	Reschedule(ctx context.Context, change models.EventRescheduled, dueDates map[int]time.Time) (*models.Event, error)
+	GetForParticipant(ctx context.Context, eventID, userID int) (*models.Event, error)
+	ParticipatingEventIDs(ctx context.Context, userID int, eventIDs []int, role string) ([]int, error)
+	Memberships(ctx context.Context, userID int, eventIDs []int) (map[int]models.Membership, error)
+	CreateRole(ctx context.Context, eventID int, name string, perms []models.Permission) (*models.EventRole, error)
+	GetRole(ctx context.Context, eventID int, name string) (*models.EventRole, error)
+	ListRoles(ctx context.Context, eventID int) ([]models.EventRole, error)
+	DeleteRole(ctx context.Context, eventID int, name string) error
+	RoleInUse(ctx context.Context, eventID int, name string) (bool, error)
+	CreateQuestion(ctx context.Context, q models.RSVPQuestion) (*models.RSVPQuestion, error)
+	ListQuestions(ctx context.Context, eventID int) ([]models.RSVPQuestion, error)
+	DeleteQuestion(ctx context.Context, eventID, questionID int) error
+	ListAnswers(ctx context.Context, eventID int) (map[int]map[int]string, error)
+	ListParticipantsByAttendance(ctx context.Context, eventID int, attendance []string) ([]models.Participant, error)
+	NudgePending(ctx context.Context, eventID, afterDays, maxNudges int) ([]models.Participant, error)
+	AutoNudgePending(ctx context.Context, maxNudges int) (map[int][]models.Participant, error)
+	CreateAnnouncement(ctx context.Context, a models.Announcement) (*models.Announcement, error)
+	ListAnnouncements(ctx context.Context, eventID int) ([]models.Announcement, error)
+	ListParticipantsByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Participant, error)
+	ListTasks(ctx context.Context, eventID int, labels []string) ([]models.Task, error)
+	ListTasksByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Task, error)
+	ListWithRelations(ctx context.Context, userID int, eventIDs []int, withParticipants, withTasks bool) ([]models.EventDetails, error)
+	ListInRange(ctx context.Context, userID int, from, to time.Time) ([]models.CalendarEntry, error)
+	MarkViewed(ctx context.Context, eventID, userID int) error
 }
 
 func (r *eventRepository) IsOrganizer(ctx context.Context, eventID, userID int) (bool, error) {
@@ -38,90 +89,204 @@ func (r *eventRepository) IsOrganizer(ctx context.Context, eventID, userID int)
 }
 
 type eventRepository struct {
-	pool *pgxpool.Pool
+	pool *database.DB
 }
 
-func NewEventRepository(pool *pgxpool.Pool) EventRepository {
+func NewEventRepository(pool *database.DB) EventRepository {
 	return &eventRepository{pool: pool}
 }
 
-func (r *eventRepository) checkExistingEvent(ctx context.Context, start time.Time) (bool, error) {
-    const q = `SELECT EXISTS(SELECT 1 FROM events WHERE start_time = $1)`
-    var exists bool
-    err := r.pool.QueryRow(ctx, q, start).Scan(&exists)
-    return exists, err
+// eventColumns is the column list read by scanEvent. Queries must select
+// FROM eventFrom (or alias events as e and LEFT JOIN venues as v).
+const eventColumns = `e.id, e.title, e.description, e.location, e.venue_id, e.start_time, e.end_time, e.event_type, e.meeting_url, e.allow_transfers, e.auto_nudge_days, e.require_change_approval, e.rsvp_visibility, e.slug, e.published_at, e.archived_at, e.hidden_at, e.cancelled_at, e.series_id, e.organizer_id, e.created_at, e.updated_at,
+	v.name, v.address, v.latitude, v.longitude, v.created_by, v.created_at, v.updated_at`
+
+const eventFrom = `events e LEFT JOIN venues v ON v.id = e.venue_id`
+
+// scanEvent scans a row selected with eventColumns into e, followed by any extra destinations.
+func scanEvent(row pgx.Row, e *models.Event, extra ...any) error {
+	var (
+		venueName, venueAddress    *string
+		venueLat, venueLng         *float64
+		venueCreatedBy             *int
+		venueCreated, venueUpdated *time.Time
+	)
+	dest := []any{&e.ID, &e.Title, &e.Description, &e.Location, &e.VenueID, &e.StartTime, &e.EndTime, &e.Type, &e.MeetingURL, &e.AllowTransfers, &e.AutoNudgeDays, &e.RequireChangeApproval, &e.RSVPVisibility, &e.Slug, &e.PublishedAt, &e.ArchivedAt, &e.HiddenAt, &e.CancelledAt, &e.SeriesID, &e.OrganizerID, &e.CreatedAt, &e.UpdatedAt,
+		&venueName, &venueAddress, &venueLat, &venueLng, &venueCreatedBy, &venueCreated, &venueUpdated}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	e.DescriptionHTML = markdown.Render(e.Description)
+	e.Venue = nil
+	if e.VenueID != nil && venueName != nil {
+		e.Venue = &models.Venue{
+			ID:        *e.VenueID,
+			Name:      *venueName,
+			Address:   *venueAddress,
+			Latitude:  venueLat,
+			Longitude: venueLng,
+			CreatedBy: *venueCreatedBy,
+			CreatedAt: *venueCreated,
+			UpdatedAt: *venueUpdated,
+		}
+	}
+	return nil
+}
+
+// FindDuplicates returns the organizer's events whose time overlaps start to
+// end (just start without an end) and whose title is at least similarity
+// alike by pg_trgm, most similar first.
+func (r *eventRepository) FindDuplicates(ctx context.Context, organizerID int, title string, start time.Time, end *time.Time, similarity float64) ([]models.DuplicateCandidate, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT e.id, e.title, e.slug, e.start_time, e.end_time, similarity(lower(e.title), lower($2)) AS score
+		FROM events e
+		WHERE e.organizer_id = $1 AND e.deleted_at IS NULL
+			AND tstzrange(e.start_time, COALESCE(e.end_time, e.start_time), '[]') && tstzrange($3, COALESCE($4, $3), '[]')
+			AND (lower(e.title) = lower($2) OR similarity(lower(e.title), lower($2)) >= $5)
+		ORDER BY score DESC, e.start_time, e.id
+		LIMIT 5
+	`, organizerID, title, start, end, similarity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.DuplicateCandidate{}
+	for rows.Next() {
+		var d models.DuplicateCandidate
+		if err := rows.Scan(&d.EventID, &d.Title, &d.Slug, &d.StartTime, &d.EndTime, &d.Similarity); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
 }
 
-func (r *eventRepository) Create(ctx context.Context, title, description, location string, start time.Time, organizerID int) (*models.Event, error) {
-    // Check for existing event at the same time
-    exists, err := r.checkExistingEvent(ctx, start)
+// Create stores the event with its organizer and initial tasks in one transaction.
+func (r *eventRepository) Create(ctx context.Context, e models.Event, tasks []models.TaskInput) (*models.Event, error) {
+    // Without an explicit location, the venue's name and address become the display string.
+    q := `
+        WITH e AS (
+            INSERT INTO events (title, description, location, venue_id, start_time, end_time, event_type, meeting_url, organizer_id, allow_transfers, slug)
+            VALUES ($1, $2,
+                COALESCE(NULLIF($3, ''), (SELECT name || CASE WHEN address <> '' THEN ', ' || address ELSE '' END FROM venues WHERE id = $4), ''),
+                $4, $5, $6, $7, $8, $9, $10, $11)
+            RETURNING *
+        )
+        SELECT ` + eventColumns + `
+        FROM e LEFT JOIN venues v ON v.id = e.venue_id`
+
+    tx, err := r.pool.Begin(ctx)
     if err != nil {
-        return nil, fmt.Errorf("error checking for existing event: %w", err)
-    }
-    if exists {
-        return nil, fmt.Errorf("an event already exists at this time")
+        return nil, err
     }
-
-    const q = `
-        INSERT INTO events (title, description, location, start_time, organizer_id)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id, title, description, location, start_time, organizer_id, created_at, updated_at
-    `
+    defer tx.Rollback(ctx)
 
     var event models.Event
-    err = r.pool.QueryRow(
+    err = scanEvent(tx.QueryRow(
         ctx,
         q,
-        title,
-        description,
-        location,
-        start,
-        organizerID,
-    ).Scan(
-        &event.ID,
-        &event.Title,
-        &event.Description,
-        &event.Location,
-        &event.StartTime,
-        &event.OrganizerID,
-        &event.CreatedAt,
-        &event.UpdatedAt,
-    )
+        e.Title,
+        e.Description,
+        e.Location,
+        e.VenueID,
+        e.StartTime,
+        e.EndTime,
+        e.Type,
+        e.MeetingURL,
+        e.OrganizerID,
+        e.AllowTransfers,
+        e.Slug,
+    ), &event)
 
     if err != nil {
         return nil, err
     }
 
     // Add organizer as participant
-    if _, err := r.pool.Exec(
+    if _, err := tx.Exec(
         ctx,
         `INSERT INTO event_participants (event_id, user_id, role) VALUES ($1, $2, 'organizer')`,
         event.ID,
-        organizerID,
+        event.OrganizerID,
     ); err != nil {
         return nil, err
     }
 
+    if _, err := insertTasks(ctx, tx, event.ID, tasks); err != nil {
+        return nil, err
+    }
+
+    if err := addToOutbox(ctx, tx, models.TopicEventCreated, models.EventCreated{
+        EventID:     event.ID,
+        OrganizerID: event.OrganizerID,
+        Title:       event.Title,
+        Slug:        event.Slug,
+        Type:        event.Type,
+        StartTime:   event.StartTime,
+    }); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return nil, err
+    }
     return &event, nil
 }
 
-func (r *eventRepository) ListByRole(ctx context.Context, userID int, role string) ([]models.Event, error) {
-	const q = `
-		SELECT e.id, e.title, e.description, e.location, e.start_time, e.organizer_id, e.created_at, e.updated_at
-		FROM events e
+// eventSortColumns maps the accepted sort keys to columns; anything else falls back to start_time.
+var eventSortColumns = map[string]string{
+	"start_time": "e.start_time",
+	"created_at": "e.created_at",
+	"title":      "e.title",
+}
+
+func (r *eventRepository) ListByRole(ctx context.Context, userID int, role string, filter models.EventListFilter) ([]models.EventSummary, error) {
+	conds := []string{"p.user_id = $1", "p.role = $2", "e.deleted_at IS NULL", "e.archived_at IS NULL"}
+	if filter.Archived {
+		conds[3] = "e.archived_at IS NOT NULL"
+	}
+	args := []any{userID, role}
+	switch filter.Window {
+	case "upcoming":
+		conds = append(conds, "e.start_time >= now()")
+	case "past":
+		conds = append(conds, "e.start_time < now()")
+	}
+	switch filter.Attendance {
+	case "":
+	case "pending":
+		conds = append(conds, "p.attendance IS NULL")
+	default:
+		args = append(args, strings.ToLower(filter.Attendance))
+		conds = append(conds, "p.attendance = $"+itoa(len(args)))
+	}
+	sortCol, ok := eventSortColumns[filter.Sort]
+	if !ok {
+		sortCol = "e.start_time"
+	}
+	order := "ASC"
+	if strings.EqualFold(filter.Order, "desc") {
+		order = "DESC"
+	}
+
+	q := `
+		SELECT ` + eventColumns + `,
+			(SELECT count(*) FROM event_participants ep WHERE ep.event_id = e.id) AS participant_count,
+			(SELECT count(*) FROM event_participants ep WHERE ep.event_id = e.id AND ep.attendance = 'going') AS going_count,
+			(SELECT count(*) FROM tasks t WHERE t.event_id = e.id) AS task_count
+		FROM ` + eventFrom + `
 		JOIN event_participants p ON p.event_id = e.id
-		WHERE p.user_id = $1 AND p.role = $2
-		ORDER BY e.start_time ASC
-	`
-	rows, err := r.pool.Query(ctx, q, userID, role)
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY ` + sortCol + ` ` + order + `, e.id ` + order
+	rows, err := r.pool.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var res []models.Event
+	var res []models.EventSummary
 	for rows.Next() {
-		var e models.Event
-		if err := rows.Scan(&e.ID, &e.Title, &e.Description, &e.Location, &e.StartTime, &e.OrganizerID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		var e models.EventSummary
+		if err := scanEvent(rows, &e.Event, &e.ParticipantCount, &e.GoingCount, &e.TaskCount); err != nil {
 			return nil, err
 		}
 		res = append(res, e)
@@ -129,38 +294,475 @@ func (r *eventRepository) ListByRole(ctx context.Context, userID int, role strin
 	return res, rows.Err()
 }
 
-func (r *eventRepository) DeleteIfOrganizer(ctx context.Context, eventID, organizerID int) error {
-	const check = `SELECT 1 FROM event_participants WHERE event_id=$1 AND user_id=$2 AND role='organizer'`
-	if err := r.pool.QueryRow(ctx, check, eventID, organizerID).Scan(new(int)); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return pgx.ErrNoRows
+// Delete marks the event deleted. It disappears from every listing at once
+// and is purged for good once the undo window has passed.
+func (r *eventRepository) Delete(ctx context.Context, eventID int) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE events SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetAllowTransfers turns participant spot transfers on or off for the event.
+func (r *eventRepository) SetAllowTransfers(ctx context.Context, eventID int, allowed bool) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE events SET allow_transfers = $2, updated_at = now() WHERE id = $1`, eventID, allowed)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Update changes the fields present in req. An empty meeting URL, a venue ID
+// of 0 and 0 auto nudge days are stored as NULL.
+func (r *eventRepository) Update(ctx context.Context, eventID int, req models.UpdateEventRequest) (*models.Event, error) {
+	sets := []string{"updated_at = now()"}
+	args := []any{eventID}
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, column+" = $"+itoa(len(args)))
+	}
+	if req.Title != nil {
+		set("title", *req.Title)
+	}
+	if req.Description != nil {
+		set("description", *req.Description)
+	}
+	if req.Location != nil {
+		set("location", *req.Location)
+	}
+	if req.VenueID != nil {
+		var venueID *int
+		if *req.VenueID != 0 {
+			venueID = req.VenueID
+		}
+		set("venue_id", venueID)
+	}
+	if req.Type != nil {
+		set("event_type", *req.Type)
+	}
+	if req.MeetingURL != nil {
+		var meetingURL *string
+		if *req.MeetingURL != "" {
+			meetingURL = req.MeetingURL
+		}
+		set("meeting_url", meetingURL)
+	}
+	if req.AllowTransfers != nil {
+		set("allow_transfers", *req.AllowTransfers)
+	}
+	if req.AutoNudgeDays != nil {
+		var days *int
+		if *req.AutoNudgeDays != 0 {
+			days = req.AutoNudgeDays
+		}
+		set("auto_nudge_days", days)
+	}
+	if req.RequireChangeApproval != nil {
+		set("require_change_approval", *req.RequireChangeApproval)
+	}
+	if req.RSVPVisibility != nil {
+		set("rsvp_visibility", *req.RSVPVisibility)
+	}
+	q := `
+		WITH e AS (
+			UPDATE events
+			SET ` + strings.Join(sets, ", ") + `
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + eventColumns + `
+		FROM e LEFT JOIN venues v ON v.id = e.venue_id`
+	var e models.Event
+	if err := scanEvent(r.pool.QueryRow(ctx, q, args...), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Publish marks the event as published. Publishing again keeps the original
+// publication time. The first publication ever is announced through the
+// outbox, so followers hear about the event once. With flags from content
+// moderation, the event is hidden and a report without a reporter is filed
+// for review, unless one is already pending.
+func (r *eventRepository) Publish(ctx context.Context, eventID int, flags []string) (*models.Event, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var first bool
+	if err := tx.QueryRow(ctx, `SELECT first_published_at IS NULL FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&first); err != nil {
+		return nil, err
+	}
+	if len(flags) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE events SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1`, eventID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO reports (event_id, reason, details)
+			SELECT $1, 'moderation', $2
+			WHERE NOT EXISTS (SELECT 1 FROM reports WHERE event_id = $1 AND reason = 'moderation' AND status = 'pending')
+		`, eventID, strings.Join(flags, "; ")); err != nil {
+			return nil, err
+		}
+	}
+	q := `
+		WITH e AS (
+			UPDATE events
+			SET published_at = COALESCE(published_at, now()), first_published_at = COALESCE(first_published_at, now()), updated_at = now()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + eventColumns + `
+		FROM e LEFT JOIN venues v ON v.id = e.venue_id`
+	var e models.Event
+	if err := scanEvent(tx.QueryRow(ctx, q, eventID), &e); err != nil {
+		return nil, err
+	}
+	if first {
+		if err := addToOutbox(ctx, tx, models.TopicEventPublished, models.EventPublished{
+			EventID:     e.ID,
+			OrganizerID: e.OrganizerID,
+			SeriesID:    e.SeriesID,
+			Title:       e.Title,
+			Slug:        e.Slug,
+			StartTime:   e.StartTime,
+		}); err != nil {
+			return nil, err
 		}
+	}
+	return &e, tx.Commit(ctx)
+}
+
+// Unpublish takes the event's landing page down.
+func (r *eventRepository) Unpublish(ctx context.Context, eventID int) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE events SET published_at = NULL, updated_at = now() WHERE id = $1`, eventID)
+	if err != nil {
 		return err
 	}
-	_, err := r.pool.Exec(ctx, `DELETE FROM events WHERE id=$1`, eventID)
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetArchived archives or unarchives the event. Archiving again keeps the
+// original time; unarchiving exempts the event from ArchiveEnded.
+func (r *eventRepository) SetArchived(ctx context.Context, eventID int, archived bool) (*models.Event, error) {
+	set := `archived_at = COALESCE(archived_at, now()), unarchived_at = NULL`
+	if !archived {
+		set = `archived_at = NULL, unarchived_at = now()`
+	}
+	q := `
+		WITH e AS (
+			UPDATE events SET ` + set + `, updated_at = now()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + eventColumns + `
+		FROM e LEFT JOIN venues v ON v.id = e.venue_id`
+	var e models.Event
+	if err := scanEvent(r.pool.QueryRow(ctx, q, eventID), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ArchiveEnded archives the events that ended before the given time, or
+// started before it when they have no end time. Events an organizer
+// unarchived are left alone.
+func (r *eventRepository) ArchiveEnded(ctx context.Context, before time.Time) (int64, error) {
+	const q = `
+		UPDATE events SET archived_at = now(), updated_at = now()
+		WHERE archived_at IS NULL AND unarchived_at IS NULL
+			AND COALESCE(end_time, start_time) < $1
+	`
+	tag, err := r.pool.Exec(ctx, q, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// AddSearchPartition creates the event_search partition of the year unless
+// it exists. Inserting an event creates its year's partition when missing,
+// but doing so ahead of time keeps that lock off the request.
+func (r *eventRepository) AddSearchPartition(ctx context.Context, year int) error {
+	_, err := r.pool.Exec(ctx, `SELECT event_search_partition($1)`, year)
 	return err
 }
 
-func (r *eventRepository) Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string) error {
-	const check = `SELECT 1 FROM event_participants WHERE event_id=$1 AND user_id=$2 AND role='organizer'`
-	if err := r.pool.QueryRow(ctx, check, eventID, inviterID).Scan(new(int)); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return pgx.ErrNoRows
-		}
+// bulkStatements are the statements run by Bulk, per action.
+var bulkStatements = map[string]string{
+	models.BulkDelete:  `UPDATE events SET deleted_at = now() WHERE id = ANY($1) AND deleted_at IS NULL RETURNING id`,
+	models.BulkArchive: `UPDATE events SET archived_at = COALESCE(archived_at, now()), unarchived_at = NULL, updated_at = now() WHERE id = ANY($1) RETURNING id`,
+	models.BulkCancel:  `UPDATE events SET cancelled_at = COALESCE(cancelled_at, now()), updated_at = now() WHERE id = ANY($1) RETURNING id`,
+}
+
+// Bulk deletes, archives or cancels the given events in one transaction and
+// returns the IDs of those that still existed. Deleting marks the events
+// deleted like Delete; archiving or cancelling an event again keeps the
+// original time.
+func (r *eventRepository) Bulk(ctx context.Context, action string, eventIDs []int) ([]int, error) {
+	q, ok := bulkStatements[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown bulk action %q", action)
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, q, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	done, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	return done, tx.Commit(ctx)
+}
+
+// AcquireEditLock takes the event's edit lock for userID, or renews it when
+// userID already holds it, and reports whether it did. Either way it returns
+// the lock now in place, so a caller who lost learns who holds it.
+func (r *eventRepository) AcquireEditLock(ctx context.Context, eventID, userID int, ttl time.Duration) (*models.EditLock, bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO event_edit_locks AS l (event_id, user_id, expires_at)
+		VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+		ON CONFLICT (event_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id, expires_at = EXCLUDED.expires_at,
+			acquired_at = CASE WHEN l.user_id = EXCLUDED.user_id THEN l.acquired_at ELSE now() END
+		WHERE l.user_id = EXCLUDED.user_id OR l.expires_at <= now()
+	`, eventID, userID, ttl.Milliseconds())
+	if err != nil {
+		return nil, false, err
+	}
+	lock, err := r.GetEditLock(ctx, eventID)
+	if err != nil {
+		return nil, false, err
+	}
+	return lock, tag.RowsAffected() > 0, nil
+}
+
+// GetEditLock returns the event's unexpired edit lock; pgx.ErrNoRows when
+// nobody holds one.
+func (r *eventRepository) GetEditLock(ctx context.Context, eventID int) (*models.EditLock, error) {
+	var l models.EditLock
+	err := r.pool.QueryRow(ctx, `
+		SELECT l.event_id, l.user_id, u.name, l.acquired_at, l.expires_at
+		FROM event_edit_locks l JOIN users u ON u.id = l.user_id
+		WHERE l.event_id = $1 AND l.expires_at > now()
+	`, eventID).Scan(&l.EventID, &l.UserID, &l.UserName, &l.AcquiredAt, &l.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ReleaseEditLock gives up userID's edit lock; pgx.ErrNoRows if they do not
+// hold it.
+func (r *eventRepository) ReleaseEditLock(ctx context.Context, eventID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM event_edit_locks WHERE event_id = $1 AND user_id = $2 AND expires_at > now()
+	`, eventID, userID)
+	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetPublished returns the published event with the given slug and the name
+// of its organizer. Events hidden after abuse reports are left out.
+func (r *eventRepository) GetPublished(ctx context.Context, slug string) (*models.Event, string, error) {
+	q := `
+		SELECT ` + eventColumns + `, u.name
+		FROM ` + eventFrom + `
+		JOIN users u ON u.id = e.organizer_id
+		WHERE e.slug = $1 AND e.published_at IS NOT NULL AND e.hidden_at IS NULL AND e.deleted_at IS NULL
+	`
+	var e models.Event
+	var organizer string
+	if err := scanEvent(r.pool.QueryRow(ctx, q, slug), &e, &organizer); err != nil {
+		return nil, "", err
+	}
+	return &e, organizer, nil
+}
+
+// GetIDBySlug resolves an event slug to its ID.
+func (r *eventRepository) GetIDBySlug(ctx context.Context, slug string) (int, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `SELECT id FROM events WHERE slug = $1`, slug).Scan(&id)
+	return id, err
+}
+
+// Invite adds or updates the participant and records invite.sent in the same
+// transaction.
+// Invite adds or updates a participant and lifts an earlier revocation.
+func (r *eventRepository) Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string, expiresAt *time.Time) error {
 	const insert = `
-		INSERT INTO event_participants (event_id, user_id, role, invited_by)
-		VALUES ($1,$2,$3,$4)
-		ON CONFLICT (event_id,user_id) DO UPDATE SET role=EXCLUDED.role, invited_by=EXCLUDED.invited_by, updated_at=now()
+		INSERT INTO event_participants (event_id, user_id, role, invited_by, invite_expires_at)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (event_id,user_id) DO UPDATE SET role=EXCLUDED.role, invited_by=EXCLUDED.invited_by, invite_expires_at=EXCLUDED.invite_expires_at, updated_at=now()
 	`
-	_, err := r.pool.Exec(ctx, insert, eventID, inviteeID, strings.ToLower(role), inviterID)
-	return err
+	role = strings.ToLower(role)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, insert, eventID, inviteeID, role, inviterID, expiresAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM revoked_invites WHERE event_id = $1 AND user_id = $2`, eventID, inviteeID); err != nil {
+		return err
+	}
+	if err := addToOutbox(ctx, tx, models.TopicInviteSent, models.InviteSent{
+		EventID:   eventID,
+		InviterID: inviterID,
+		InviteeID: inviteeID,
+		Role:      role,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// InviteCandidates returns the users with the given ids or (lowercase)
+// email addresses, whether they already participate in the event, and
+// whether they block the inviter.
+func (r *eventRepository) InviteCandidates(ctx context.Context, eventID, inviterID int, userIDs []int, emails []string) ([]models.InviteCandidate, error) {
+	q := `
+		SELECT u.id, lower(u.email),
+			EXISTS (SELECT 1 FROM event_participants p WHERE p.event_id = $1 AND p.user_id = u.id),
+			` + fmt.Sprintf(blockedSender, "u.id", "$2") + `
+		FROM users u
+		WHERE u.id = ANY($3) OR lower(u.email) = ANY($4)
+	`
+	rows, err := r.pool.Query(ctx, q, eventID, inviterID, userIDs, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.InviteCandidate
+	for rows.Next() {
+		var c models.InviteCandidate
+		if err := rows.Scan(&c.UserID, &c.Email, &c.Participant, &c.Blocked); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// InviteMany invites users who do not participate in the event yet, lifting
+// earlier revocations and recording invite.sent for each, in one
+// transaction. The invitees are streamed in with COPY, so thousands take one
+// round trip. Users who joined in the meantime are skipped; it returns how
+// many were invited.
+func (r *eventRepository) InviteMany(ctx context.Context, eventID, inviterID int, inviteeIDs []int, role string, expiresAt *time.Time) (int, error) {
+	role = strings.ToLower(role)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `CREATE TEMP TABLE bulk_invitees (user_id INT PRIMARY KEY) ON COMMIT DROP`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"bulk_invitees"}, []string{"user_id"}, pgx.CopyFromSlice(len(inviteeIDs), func(i int) ([]any, error) {
+		return []any{inviteeIDs[i]}, nil
+	})); err != nil {
+		return 0, err
+	}
+	rows, err := tx.Query(ctx, `
+		INSERT INTO event_participants (event_id, user_id, role, invited_by, invite_expires_at)
+		SELECT $1, b.user_id, $2, $3, $4 FROM bulk_invitees b
+		ON CONFLICT (event_id, user_id) DO NOTHING
+		RETURNING user_id
+	`, eventID, role, inviterID, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	invited, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, err
+	}
+	if len(invited) == 0 {
+		return 0, tx.Commit(ctx)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM revoked_invites WHERE event_id = $1 AND user_id = ANY($2)`, eventID, invited); err != nil {
+		return 0, err
+	}
+	payloads := make([][]byte, len(invited))
+	for i, inviteeID := range invited {
+		if payloads[i], err = json.Marshal(models.InviteSent{EventID: eventID, InviterID: inviterID, InviteeID: inviteeID, Role: role, ExpiresAt: expiresAt}); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"outbox_events"}, []string{"topic", "payload"}, pgx.CopyFromSlice(len(payloads), func(i int) ([]any, error) {
+		return []any{models.TopicInviteSent, json.RawMessage(payloads[i])}, nil
+	})); err != nil {
+		return 0, err
+	}
+	return len(invited), tx.Commit(ctx)
+}
+
+// RevokeInvite removes a pending invitation and records the revocation. It
+// returns the removed participant row as JSON, to restore it on undo, or
+// pgx.ErrNoRows when the invitee has no unanswered invitation.
+func (r *eventRepository) RevokeInvite(ctx context.Context, eventID, inviteeID, revokedBy int) (json.RawMessage, error) {
+	const record = `
+		INSERT INTO revoked_invites (event_id, user_id, revoked_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET revoked_by = EXCLUDED.revoked_by, revoked_at = now()
+	`
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	var removed json.RawMessage
+	err = tx.QueryRow(ctx, `
+		DELETE FROM event_participants
+		WHERE event_id = $1 AND user_id = $2 AND attendance IS NULL AND role <> 'organizer'
+		RETURNING to_jsonb(event_participants)
+	`, eventID, inviteeID).Scan(&removed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, record, eventID, inviteeID, revokedBy); err != nil {
+		return nil, err
+	}
+	return removed, tx.Commit(ctx)
+}
+
+// InviteRevoked reports whether the user's invitation to the event was
+// revoked and not renewed since.
+func (r *eventRepository) InviteRevoked(ctx context.Context, eventID, userID int) (bool, error) {
+	var revoked bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM revoked_invites WHERE event_id = $1 AND user_id = $2)`, eventID, userID).Scan(&revoked)
+	return revoked, err
 }
 
 func (r *eventRepository) ListParticipants(ctx context.Context, eventID int) ([]models.Participant, error) {
 	const q = `
-		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance,
+			CASE WHEN p.attendance IS NULL THEN p.invite_expires_at END
 		FROM event_participants p
 		JOIN users u ON u.id = p.user_id
 		WHERE p.event_id = $1
@@ -175,7 +777,7 @@ func (r *eventRepository) ListParticipants(ctx context.Context, eventID int) ([]
 	for rows.Next() {
 		var p models.Participant
 		var attendance *string
-		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &attendance); err != nil {
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &attendance, &p.InviteExpiresAt); err != nil {
 			return nil, err
 		}
 		p.Attendance = attendance
@@ -184,10 +786,37 @@ func (r *eventRepository) ListParticipants(ctx context.Context, eventID int) ([]
 	return res, rows.Err()
 }
 
-func (r *eventRepository) SetAttendance(ctx context.Context, eventID, userID int, status string) error {
+// CountAttendance counts the event's participants by attendance.
+func (r *eventRepository) CountAttendance(ctx context.Context, eventID int) (*models.AttendanceCounts, error) {
+	const q = `
+		SELECT
+			count(*) FILTER (WHERE attendance = 'going'),
+			count(*) FILTER (WHERE attendance = 'maybe'),
+			count(*) FILTER (WHERE attendance = 'not_going'),
+			count(*) FILTER (WHERE attendance IS NULL),
+			count(*)
+		FROM event_participants
+		WHERE event_id = $1
+	`
+	var c models.AttendanceCounts
+	if err := r.pool.QueryRow(ctx, q, eventID).Scan(&c.Going, &c.Maybe, &c.NotGoing, &c.Pending, &c.Total); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// SetAttendance records the user's attendance and, in the same transaction,
+// their answers to the event's RSVP questions.
+func (r *eventRepository) SetAttendance(ctx context.Context, eventID, userID int, status string, answers []models.RSVPAnswer) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// First check if the user is already a participant
 	var exists bool
-	err := r.pool.QueryRow(ctx, 
+	err = tx.QueryRow(ctx, 
 		`SELECT EXISTS(SELECT 1 FROM event_participants WHERE event_id=$1 AND user_id=$2)`, 
 		eventID, userID).Scan(&exists)
 	if err != nil {
@@ -196,83 +825,67 @@ func (r *eventRepository) SetAttendance(ctx context.Context, eventID, userID int
 
 	if !exists {
 		// If not a participant, insert them as an attendee with the given status
-		_, err = r.pool.Exec(ctx, `
-			INSERT INTO event_participants (event_id, user_id, role, attendance, updated_at)
-			VALUES ($1, $2, 'attendee', $3, NOW())
+		_, err = tx.Exec(ctx, `
+			INSERT INTO event_participants (event_id, user_id, role, attendance, responded_at, first_responded_at, updated_at)
+			VALUES ($1, $2, 'attendee', $3, NOW(), NOW(), NOW())
 		`, eventID, userID, strings.ToLower(status))
+	} else {
+		// Update existing attendance
+		_, err = tx.Exec(ctx, `
+			UPDATE event_participants 
+			SET attendance = $3, 
+				responded_at = CASE WHEN attendance IS DISTINCT FROM $3 THEN NOW() ELSE responded_at END,
+				first_responded_at = COALESCE(first_responded_at, NOW()),
+				updated_at = NOW()
+			WHERE event_id = $1 AND user_id = $2
+		`, eventID, userID, strings.ToLower(status))
+	}
+	if err != nil {
 		return err
 	}
 
-	// Update existing attendance
-	_, err = r.pool.Exec(ctx, `
-		UPDATE event_participants 
-		SET attendance = $3, 
-			updated_at = NOW()
-		WHERE event_id = $1 AND user_id = $2
-	`, eventID, userID, strings.ToLower(status))
-
-	return err
-}
-
-func (r *eventRepository) Search(ctx context.Context, userID int, q string, from, to *time.Time, role string) ([]models.Event, []models.Task, error) {
-	// Debug logging
-	log.Printf("Search params - userID: %d, query: '%s', from: %v, to: %v, role: '%s'", userID, q, from, to, role)
-	
-	var econds []string
-	var eargs []any
-	idx := 1
-
-	// If user is not 0 (meaning we have an authenticated user) and role is specified
-	if userID != 0 && role != "" {
-		// For authenticated users with role filter
-		if role == "organizer" {
-			econds = append(econds, "e.organizer_id = $"+itoa(idx))
-			eargs = append(eargs, userID)
-			idx++
-		} else {
-			econds = append(econds, "p.user_id = $"+itoa(idx)+" AND p.role = $"+itoa(idx+1))
-			eargs = append(eargs, userID, strings.ToLower(role))
-			idx += 2
+	for _, a := range answers {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO rsvp_answers (question_id, event_id, user_id, answer)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (question_id, user_id) DO UPDATE SET answer = EXCLUDED.answer, answered_at = now()
+		`, a.QuestionID, eventID, userID, a.Answer); err != nil {
+			return err
 		}
 	}
-	if q != "" {
-		econds = append(econds, "(e.title ILIKE '%'||$"+itoa(idx)+"||'%' OR e.description ILIKE '%'||$"+itoa(idx)+"||'%' OR e.location ILIKE '%'||$"+itoa(idx)+"||'%')")
-		eargs = append(eargs, q)
-		idx++
-	}
-	if from != nil {
-		econds = append(econds, "e.start_time >= $"+itoa(idx))
-		eargs = append(eargs, *from)
-		idx++
+	return tx.Commit(ctx)
+}
+
+// SetMuted mutes or unmutes the event for one of its participants;
+// pgx.ErrNoRows if the user does not participate.
+func (r *eventRepository) SetMuted(ctx context.Context, eventID, userID int, muted bool) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE event_participants SET muted = $3, updated_at = now() WHERE event_id = $1 AND user_id = $2`, eventID, userID, muted)
+	if err != nil {
+		return err
 	}
-	if to != nil {
-		econds = append(econds, "e.start_time <= $"+itoa(idx))
-		eargs = append(eargs, *to)
-		idx++
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
 	}
-	// Build the base query
-	baseQuery := `
-		SELECT e.id, e.title, e.description, e.location, e.start_time, e.organizer_id, e.created_at, e.updated_at 
-		FROM events e`
+	return nil
+}
 
-	// Add JOIN for participant checks if needed (for role-based filtering)
-	if userID != 0 && role != "" && role != "organizer" {
-		baseQuery += ` JOIN event_participants p ON p.event_id = e.id`
+func (r *eventRepository) Search(ctx context.Context, userID int, f models.SearchFilter) ([]models.Event, []models.Task, error) {
+	eq := newSelect(eventColumns, eventFrom)
+	dateCol, textCols := "e.start_time", []string{"e.title", "e.location", "e.description"}
+	if f.Query != "" {
+		// Match the text in event_search, partitioned by year, so the date
+		// bounds below skip the partitions of other years
+		eq.join("JOIN event_search s ON s.event_id = e.id AND s.start_time = e.start_time")
+		dateCol, textCols = "s.start_time", []string{"s.title", "s.location", "s.description"}
 	}
-
-	// Add WHERE clause if we have any conditions
-	whereClause := ""
-	if len(econds) > 0 {
-		whereClause = " WHERE " + strings.Join(econds, " AND ")
+	searchFilters(eq, userID, f, dateCol, "e.created_at", "e.id", textCols...)
+	if f.From != nil {
+		eq.where(dateCol + " >= " + eq.arg(*f.From))
 	}
-
-	// Final query with ordering
-	qe := baseQuery + whereClause + ` ORDER BY e.start_time ASC`
-	
-	log.Printf("Events query: %s", qe)
-	log.Printf("Query args: %v", eargs)
-	
-	rows, err := r.pool.Query(ctx, qe, eargs...)
+	if f.To != nil {
+		eq.where(dateCol + " <= " + eq.arg(*f.To))
+	}
+	rows, err := r.pool.Query(ctx, eq.sql(), eq.args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -280,7 +893,7 @@ func (r *eventRepository) Search(ctx context.Context, userID int, q string, from
 	var events []models.Event
 	for rows.Next() {
 		var e models.Event
-		if err := rows.Scan(&e.ID, &e.Title, &e.Description, &e.Location, &e.StartTime, &e.OrganizerID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := scanEvent(rows, &e); err != nil {
 			return nil, nil, err
 		}
 		events = append(events, e)
@@ -288,63 +901,23 @@ func (r *eventRepository) Search(ctx context.Context, userID int, q string, from
 	if err := rows.Err(); err != nil {
 		return nil, nil, err
 	}
-	// Tasks
-	idx = 1
-	var tconds []string
-	var targs []any
-	
-	// If user is not 0 (meaning we have an authenticated user) and role is specified
-	if userID != 0 && role != "" {
-		// For authenticated users with role filter
-		if role == "organizer" {
-			tconds = append(tconds, "e.organizer_id = $"+itoa(idx))
-			targs = append(targs, userID)
-			idx++
-		} else {
-			tconds = append(tconds, "p.user_id = $"+itoa(idx)+" AND p.role = $"+itoa(idx+1))
-			targs = append(targs, userID, strings.ToLower(role))
-			idx += 2
-		}
-	}
-	if q != "" {
-		tconds = append(tconds, "(t.title ILIKE '%'||$"+itoa(idx)+"||'%' OR t.description ILIKE '%'||$"+itoa(idx)+"||'%')")
-		targs = append(targs, q)
-		idx++
-	}
-	if from != nil {
-		tconds = append(tconds, "(t.due_date IS NULL OR t.due_date >= $"+itoa(idx)+")")
-		targs = append(targs, *from)
-		idx++
+
+	// Tasks without a due date match any period
+	tq := newSelect(taskColumns, "tasks t JOIN events e ON e.id = t.event_id")
+	if f.Near != nil {
+		tq.join("JOIN venues v ON v.id = e.venue_id")
 	}
-	if to != nil {
-		tconds = append(tconds, "(t.due_date IS NULL OR t.due_date <= $"+itoa(idx)+")")
-		targs = append(targs, *to)
-		idx++
+	searchFilters(tq, userID, f, "t.due_date", "t.created_at", "t.id", "t.title", "t.description")
+	if f.From != nil {
+		tq.where("(t.due_date IS NULL OR t.due_date >= " + tq.arg(*f.From) + ")")
 	}
-	// Build the base tasks query
-	taskBaseQuery := `
-		SELECT t.id, t.event_id, t.title, t.description, t.due_date, t.assignee_id, t.created_at, t.updated_at 
-		FROM tasks t 
-		JOIN events e ON e.id = t.event_id`
-
-	// Add JOIN for participant checks if needed (for role-based filtering)
-	if userID != 0 && role != "" && role != "organizer" {
-		taskBaseQuery += ` JOIN event_participants p ON p.event_id = e.id`
+	if f.To != nil {
+		tq.where("(t.due_date IS NULL OR t.due_date <= " + tq.arg(*f.To) + ")")
 	}
-
-	// Add WHERE clause if we have any conditions
-	taskWhereClause := ""
-	if len(tconds) > 0 {
-		taskWhereClause = " WHERE " + strings.Join(tconds, " AND ")
+	if len(f.Labels) > 0 {
+		tq.where(hasLabels(tq.arg(f.Labels)))
 	}
-
-	// Final tasks query with ordering
-	qt := taskBaseQuery + taskWhereClause + ` ORDER BY t.due_date NULLS LAST`
-	
-	log.Printf("Tasks query: %s", qt)
-	log.Printf("Tasks query args: %v", targs)
-	
-	rows2, err := r.pool.Query(ctx, qt, targs...)
+	rows2, err := r.pool.Query(ctx, tq.sql(), tq.args...)
 	if err != nil {
 		return events, nil, err
 	}
@@ -352,54 +925,1037 @@ func (r *eventRepository) Search(ctx context.Context, userID int, q string, from
 	var tasks []models.Task
 	for rows2.Next() {
 		var t models.Task
-		var due *time.Time
-		var assignee *int
-		if err := rows2.Scan(&t.ID, &t.EventID, &t.Title, &t.Description, &due, &assignee, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if err := scanTask(rows2, &t); err != nil {
 			return events, nil, err
 		}
-		t.DueDate = due
-		t.AssigneeID = assignee
 		tasks = append(tasks, t)
 	}
 	return events, tasks, rows2.Err()
 }
 
-func (r *eventRepository) CreateTask(ctx context.Context, eventID int, title, description string, dueDate *time.Time, assigneeID *int) (*models.Task, error) {
-	const q = `
-		INSERT INTO tasks (event_id, title, description, due_date, assignee_id)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, event_id, title, description, due_date, assignee_id, created_at, updated_at
-	`
-
-	var task models.Task
-	err := r.pool.QueryRow(
-		ctx,
-		q,
-		eventID,
-		title,
-		description,
-		dueDate,
-		assigneeID,
-	).Scan(
-		&task.ID,
-		&task.EventID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.AssigneeID,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+// hasLabels returns the condition matching tasks t that have every label
+// named in the text array param, regardless of case.
+func hasLabels(param string) string {
+	return `NOT EXISTS (
+		SELECT 1 FROM unnest(` + param + `::text[]) AS wanted(name)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM task_label_assignments a JOIN task_labels l ON l.id = a.label_id
+			WHERE a.task_id = t.id AND lower(l.name) = lower(wanted.name)
+		)
+	)`
+}
 
-	if err != nil {
-		return nil, err
+// searchFilters adds the filters events and tasks share to q, which selects
+// from events e: the caller's role, the text search on textCols, publication
+// dates and distance, and the order.
+func searchFilters(q *selectQuery, userID int, f models.SearchFilter, dateCol, createdCol, idCol string, textCols ...string) {
+	q.where("e.deleted_at IS NULL")
+	if userID != 0 && f.Role != "" {
+		if f.Role == "organizer" {
+			q.where("e.organizer_id = " + q.arg(userID))
+		} else {
+			q.join("JOIN event_participants p ON p.event_id = e.id")
+			q.where("p.user_id = " + q.arg(userID) + " AND p.role = " + q.arg(strings.ToLower(f.Role)))
+		}
 	}
-
-	return &task, nil
+	rank := ""
+	if f.Query != "" {
+		pattern, term, threshold := q.arg(containsPattern(f.Query)), "", ""
+		if f.Similarity > 0 {
+			term, threshold = q.arg(f.Query), q.arg(f.Similarity)
+		}
+		var cond string
+		cond, rank = textMatch(pattern, term, threshold, textCols...)
+		q.where(cond)
+	}
+	if f.PublishedAfter != nil {
+		q.where("e.published_at > " + q.arg(*f.PublishedAfter))
+	}
+	if f.PublishedBefore != nil {
+		q.where("e.published_at <= " + q.arg(*f.PublishedBefore))
+	}
+	if f.Near != nil {
+		q.where(nearCondition(q, *f.Near))
+	}
+	q.orderBy(searchOrder(f, dateCol, createdCol, idCol, rank))
 }
 
-func itoa(i int) string { return fmtInt(i) }
-
+// searchOrder builds the ORDER BY clause for a search. The ID column breaks
+// ties so results keep a stable order between requests. rank is the relevance
+// expression; without one, relevance falls back to date order.
+func searchOrder(f models.SearchFilter, dateCol, createdCol, idCol, rank string) string {
+	dir := "ASC"
+	if strings.EqualFold(f.Order, "desc") {
+		dir = "DESC"
+	}
+	switch {
+	case f.Sort == models.SearchSortRelevance && rank != "":
+		// Best matches first by default; order=asc reverses.
+		if f.Order == "" {
+			dir = "DESC"
+		}
+		return rank + " " + dir + ", " + dateCol + " ASC NULLS LAST, " + idCol + " ASC"
+	case f.Sort == models.SearchSortCreated:
+		return createdCol + " " + dir + ", " + idCol + " " + dir
+	default:
+		return dateCol + " " + dir + " NULLS LAST, " + idCol + " " + dir
+	}
+}
+
+// likeEscaper escapes the ILIKE wildcards (and the escape character itself)
+// so user input only ever matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns the ILIKE pattern matching values that contain term.
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
+// textMatch returns the condition matching cols against the ILIKE pattern
+// param and the expression ranking the matches. When term and threshold
+// params are given, a column also matches when its pg_trgm word similarity
+// to the raw term reaches the threshold, so typos still match. Exact matches
+// score 1 on top of their similarity, and the first column (the title)
+// weighs double.
+func textMatch(pattern, term, threshold string, cols ...string) (string, string) {
+	var conds []string
+	terms := make([]string, len(cols))
+	for i, col := range cols {
+		exact := col + " ILIKE " + pattern
+		conds = append(conds, exact)
+		score := "CASE WHEN " + exact + " THEN 1 ELSE 0 END"
+		if threshold != "" {
+			similarity := "word_similarity(" + term + ", COALESCE(" + col + ", ''))"
+			conds = append(conds, similarity+" >= "+threshold)
+			score += " + " + similarity
+		}
+		if i == 0 {
+			score = "2 * (" + score + ")"
+		}
+		terms[i] = score
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", "(" + strings.Join(terms, " + ") + ")"
+}
+
+// taskColumns lists the tasks columns read into models.Task by scanTask,
+// followed by the task's labels as a JSON array; the table must be aliased t.
+const taskColumns = `t.id, t.event_id, t.title, t.description, t.due_date, t.due_offset, t.assignee_id, t.completed_at, t.position, t.column_id, t.estimated_minutes, t.created_at, t.updated_at, ` + taskLabels
+
+const taskLabels = `COALESCE((
+	SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY lower(l.name))
+	FROM task_label_assignments a JOIN task_labels l ON l.id = a.label_id
+	WHERE a.task_id = t.id
+), '[]')`
+
+func scanTask(row pgx.Row, t *models.Task, extra ...any) error {
+	dest := []any{&t.ID, &t.EventID, &t.Title, &t.Description, &t.DueDate, &t.DueOffset, &t.AssigneeID, &t.CompletedAt, &t.Position, &t.ColumnID, &t.EstimatedMinutes, &t.CreatedAt, &t.UpdatedAt, &t.Labels}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (r *eventRepository) CreateTask(ctx context.Context, eventID int, title, description string, dueDate *time.Time, dueOffset *string, assigneeID *int) (*models.Task, error) {
+	const q = `
+		INSERT INTO tasks AS t (event_id, title, description, due_date, due_offset, assignee_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + taskColumns
+
+	var task models.Task
+	err := scanTask(r.pool.QueryRow(
+		ctx,
+		q,
+		eventID,
+		title,
+		description,
+		dueDate,
+		dueOffset,
+		assigneeID,
+	), &task)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// GetForParticipant returns the event if userID participates in it in any role.
+// CreateTasks inserts tasks for the event in one transaction and returns them
+// in the given order.
+func (r *eventRepository) CreateTasks(ctx context.Context, eventID int, tasks []models.TaskInput) ([]models.Task, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	res, err := insertTasks(ctx, tx, eventID, tasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// copyMinRows is the number of rows from which inserts stream them with
+// COPY instead of sending one statement per row.
+const copyMinRows = 50
+
+// insertTasks adds tasks to the event in a single batch on tx, or with COPY
+// when there are many.
+func insertTasks(ctx context.Context, tx pgx.Tx, eventID int, tasks []models.TaskInput) ([]models.Task, error) {
+	const q = `
+		INSERT INTO tasks AS t (event_id, title, description, due_date, due_offset, assignee_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + taskColumns
+	if len(tasks) == 0 {
+		return []models.Task{}, nil
+	}
+	if len(tasks) >= copyMinRows {
+		return copyTasks(ctx, tx, eventID, tasks)
+	}
+	batch := &pgx.Batch{}
+	for _, t := range tasks {
+		var offset *string
+		if t.DueOffset != "" {
+			offset = &t.DueOffset
+		}
+		batch.Queue(q, eventID, t.Title, t.Description, t.DueDate, offset, t.AssigneeID)
+	}
+	br := tx.SendBatch(ctx, batch)
+	res := make([]models.Task, len(tasks))
+	for i := range res {
+		if err := scanTask(br.QueryRow(), &res[i]); err != nil {
+			br.Close()
+			return nil, err
+		}
+	}
+	return res, br.Close()
+}
+
+// copyTasks inserts tasks with COPY. COPY returns no rows, so the ids are
+// drawn from the sequence first and the tasks read back by them.
+func copyTasks(ctx context.Context, tx pgx.Tx, eventID int, tasks []models.TaskInput) ([]models.Task, error) {
+	rows, err := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence('tasks', 'id')) FROM generate_series(1, $1)`, len(tasks))
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	columns := []string{"id", "event_id", "title", "description", "due_date", "due_offset", "assignee_id"}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tasks"}, columns, pgx.CopyFromSlice(len(tasks), func(i int) ([]any, error) {
+		t := tasks[i]
+		var offset *string
+		if t.DueOffset != "" {
+			offset = &t.DueOffset
+		}
+		return []any{ids[i], eventID, t.Title, t.Description, t.DueDate, offset, t.AssigneeID}, nil
+	})); err != nil {
+		return nil, err
+	}
+	rows, err = tx.Query(ctx, `SELECT `+taskColumns+` FROM tasks t WHERE t.id = ANY($1) ORDER BY array_position($1, t.id)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make([]models.Task, 0, len(tasks))
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// nudgeUpdate records a nudge for every pending invitee matching the extra
+// conditions: no answer, fewer than $1 nudges so far, the event and the
+// invitation's expiry still ahead, no invitation or nudge within the last
+// %[1]s days, and an inviter the invitee has not blocked since.
+var nudgeUpdate = `
+	UPDATE event_participants p
+	SET nudge_count = p.nudge_count + 1, last_nudged_at = now(), updated_at = now()
+	FROM events e, users u
+	WHERE e.id = p.event_id AND u.id = p.user_id AND e.deleted_at IS NULL
+		AND p.attendance IS NULL AND p.role <> 'organizer'
+		AND p.nudge_count < $1 AND e.start_time > now()
+		AND p.invited_at <= now() - make_interval(days => %[1]s)
+		AND (p.last_nudged_at IS NULL OR p.last_nudged_at <= now() - make_interval(days => %[1]s))
+		AND (p.invite_expires_at IS NULL OR p.invite_expires_at > now())
+		AND (p.invited_by IS NULL OR NOT ` + fmt.Sprintf(blockedSender, "p.user_id", "p.invited_by") + `)
+		%[2]s
+	RETURNING p.event_id, p.user_id, u.name, u.email, p.role, p.attendance`
+
+// NudgePending records a nudge for the event's invitees who have not
+// answered within afterDays of their invitation or last nudge, up to
+// maxNudges each, and returns them. Concurrent calls never nudge the same
+// invitee twice.
+func (r *eventRepository) NudgePending(ctx context.Context, eventID, afterDays, maxNudges int) ([]models.Participant, error) {
+	q := fmt.Sprintf(nudgeUpdate, "$3", "AND e.id = $2")
+	rows, err := r.pool.Query(ctx, q, maxNudges, eventID, afterDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.Participant
+	for rows.Next() {
+		var p models.Participant
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
+
+// AutoNudgePending does what NudgePending does for every upcoming event with
+// automatic nudges, after each event's auto_nudge_days. The nudged invitees
+// are keyed by event ID.
+func (r *eventRepository) AutoNudgePending(ctx context.Context, maxNudges int) (map[int][]models.Participant, error) {
+	q := fmt.Sprintf(nudgeUpdate, "e.auto_nudge_days", "AND e.auto_nudge_days IS NOT NULL")
+	rows, err := r.pool.Query(ctx, q, maxNudges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[int][]models.Participant{}
+	for rows.Next() {
+		var p models.Participant
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+			return nil, err
+		}
+		res[p.EventID] = append(res[p.EventID], p)
+	}
+	return res, rows.Err()
+}
+
+// UpdateTask changes the fields set in patch of a task of the event. It
+// returns pgx.ErrNoRows when the event has no such task.
+func (r *eventRepository) UpdateTask(ctx context.Context, eventID, taskID int, patch models.TaskPatch) (*models.Task, error) {
+	sets := []string{"updated_at = now()"}
+	args := []any{taskID, eventID}
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, column+" = $"+itoa(len(args)))
+	}
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+	if patch.Due != nil {
+		set("due_date", patch.Due.Date)
+		set("due_offset", patch.Due.Offset)
+	}
+	if patch.AssigneeID != nil {
+		var assignee *int
+		if *patch.AssigneeID != 0 {
+			assignee = patch.AssigneeID
+		}
+		set("assignee_id", assignee)
+	}
+	if patch.EstimatedMinutes != nil {
+		var estimate *int
+		if *patch.EstimatedMinutes != 0 {
+			estimate = patch.EstimatedMinutes
+		}
+		set("estimated_minutes", estimate)
+	}
+	if patch.Completed != nil {
+		// Completing a done task keeps when it was first completed.
+		args = append(args, *patch.Completed)
+		sets = append(sets, "completed_at = CASE WHEN $"+itoa(len(args))+"::boolean THEN COALESCE(t.completed_at, now()) END")
+	}
+	q := `
+		UPDATE tasks AS t
+		SET ` + strings.Join(sets, ", ") + `
+		WHERE t.id = $1 AND t.event_id = $2
+		RETURNING ` + taskColumns
+	var task models.Task
+	if err := scanTask(r.pool.QueryRow(ctx, q, args...), &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// OrderTasks puts the listed tasks first, in the order given, followed by
+// the event's other tasks in their current order, and numbers them all from
+// 1. It reports false, changing nothing, when a listed task is not the
+// event's. Positions are not content, so updated_at is left alone.
+func (r *eventRepository) OrderTasks(ctx context.Context, eventID int, taskIDs []int) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	var found int
+	if err := tx.QueryRow(ctx, `SELECT count(*) FROM tasks WHERE event_id = $1 AND id = ANY($2)`, eventID, taskIDs).Scan(&found); err != nil {
+		return false, err
+	}
+	if found != len(taskIDs) {
+		return false, nil
+	}
+	const q = `
+		UPDATE tasks t SET position = o.position
+		FROM (
+			SELECT id, row_number() OVER (
+				ORDER BY array_position($2::int[], id) NULLS LAST, position NULLS LAST, due_date NULLS LAST, id
+			) AS position
+			FROM tasks
+			WHERE event_id = $1
+		) o
+		WHERE t.id = o.id AND t.position IS DISTINCT FROM o.position
+	`
+	if _, err := tx.Exec(ctx, q, eventID, taskIDs); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// PositionTasks moves tasks to the given positions. It reports false,
+// changing nothing, when a task is not the event's.
+func (r *eventRepository) PositionTasks(ctx context.Context, eventID int, positions []models.TaskPosition) (bool, error) {
+	ids := make([]int, len(positions))
+	values := make([]float64, len(positions))
+	for i, p := range positions {
+		ids[i], values[i] = p.TaskID, p.Position
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	const q = `
+		UPDATE tasks t SET position = p.position
+		FROM unnest($2::int[], $3::float8[]) AS p(id, position)
+		WHERE t.id = p.id AND t.event_id = $1
+	`
+	tag, err := tx.Exec(ctx, q, eventID, ids, values)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() != int64(len(positions)) {
+		return false, nil
+	}
+	return true, tx.Commit(ctx)
+}
+
+// setTaskDueDates moves the due dates of the given tasks, keyed by task ID.
+func setTaskDueDates(ctx context.Context, tx pgx.Tx, dueDates map[int]time.Time) error {
+	if len(dueDates) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for id, due := range dueDates {
+		batch.Queue(`UPDATE tasks SET due_date = $2, updated_at = now() WHERE id = $1`, id, due)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// SessionSpan returns the start of the event's first session and the end of
+// its last one, or nils when it has no sessions.
+func (r *eventRepository) SessionSpan(ctx context.Context, eventID int) (*time.Time, *time.Time, error) {
+	const q = `SELECT MIN(start_time), MAX(end_time) FROM event_sessions WHERE event_id = $1`
+	var first, last *time.Time
+	if err := r.pool.QueryRow(ctx, q, eventID).Scan(&first, &last); err != nil {
+		return nil, nil, err
+	}
+	return first, last, nil
+}
+
+// Reschedule moves the event from change.OldStart to change.NewStart in one
+// transaction: sessions shift by the same amount, tasks get the given due
+// dates, attendance is cleared for everyone but organizers when
+// change.RSVPsReset is set, and an event.rescheduled message is added to the
+// outbox.
+func (r *eventRepository) Reschedule(ctx context.Context, change models.EventRescheduled, dueDates map[int]time.Time) (*models.Event, error) {
+	update := `
+		WITH e AS (
+			UPDATE events
+			SET start_time = $2, end_time = $3, updated_at = now()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + eventColumns + `
+		FROM e LEFT JOIN venues v ON v.id = e.venue_id`
+	const shiftSessions = `
+		UPDATE event_sessions
+		SET start_time = start_time + ($2::timestamptz - $3::timestamptz),
+		    end_time = end_time + ($2::timestamptz - $3::timestamptz),
+		    updated_at = now()
+		WHERE event_id = $1
+	`
+	const resetRSVPs = `
+		UPDATE event_participants
+		SET attendance = NULL, updated_at = now()
+		WHERE event_id = $1 AND role <> 'organizer' AND attendance IS NOT NULL
+	`
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	var e models.Event
+	if err := scanEvent(tx.QueryRow(ctx, update, change.EventID, change.NewStart, change.NewEnd), &e); err != nil {
+		return nil, err
+	}
+	if !change.NewStart.Equal(change.OldStart) {
+		if _, err := tx.Exec(ctx, shiftSessions, change.EventID, change.NewStart, change.OldStart); err != nil {
+			return nil, err
+		}
+	}
+	if change.RSVPsReset {
+		if _, err := tx.Exec(ctx, resetRSVPs, change.EventID); err != nil {
+			return nil, err
+		}
+	}
+	if err := setTaskDueDates(ctx, tx, dueDates); err != nil {
+		return nil, err
+	}
+	if err := addToOutbox(ctx, tx, models.TopicEventRescheduled, change); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *eventRepository) GetForParticipant(ctx context.Context, eventID, userID int) (*models.Event, error) {
+	q := `
+		SELECT ` + eventColumns + `
+		FROM ` + eventFrom + `
+		JOIN event_participants p ON p.event_id = e.id
+		WHERE e.id = $1 AND p.user_id = $2 AND e.deleted_at IS NULL
+	`
+	var e models.Event
+	if err := scanEvent(r.pool.QueryRow(ctx, q, eventID, userID), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ParticipatingEventIDs returns the subset of eventIDs the user participates in.
+// An empty role matches any role.
+func (r *eventRepository) ParticipatingEventIDs(ctx context.Context, userID int, eventIDs []int, role string) ([]int, error) {
+	const q = `
+		SELECT p.event_id
+		FROM event_participants p
+		JOIN events e ON e.id = p.event_id
+		WHERE p.user_id = $1 AND p.event_id = ANY($2) AND ($3 = '' OR p.role::text = $3) AND e.deleted_at IS NULL
+	`
+	rows, err := r.pool.Query(ctx, q, userID, eventIDs, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
+
+// Memberships returns the user's role and attendance in each of eventIDs they
+// participate in, keyed by event ID, with the permissions of custom roles.
+func (r *eventRepository) Memberships(ctx context.Context, userID int, eventIDs []int) (map[int]models.Membership, error) {
+	const q = `
+		SELECT p.event_id, p.role, p.attendance, p.invite_expires_at, er.permissions, e.rsvp_visibility
+		FROM event_participants p
+		JOIN events e ON e.id = p.event_id
+		LEFT JOIN event_roles er ON er.event_id = p.event_id AND er.name = p.role
+		WHERE p.user_id = $1 AND p.event_id = ANY($2) AND e.deleted_at IS NULL
+	`
+	rows, err := r.pool.Query(ctx, q, userID, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[int]models.Membership, len(eventIDs))
+	for rows.Next() {
+		var id int
+		var m models.Membership
+		var custom []string
+		if err := rows.Scan(&id, &m.Role, &m.Attendance, &m.InviteExpiresAt, &custom, &m.RSVPVisibility); err != nil {
+			return nil, err
+		}
+		if custom != nil {
+			m.Custom = toPermissions(custom)
+		}
+		res[id] = m
+	}
+	return res, rows.Err()
+}
+
+// ListParticipantsByEvents loads the participants of several events in one query, keyed by event ID.
+func (r *eventRepository) ListParticipantsByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Participant, error) {
+	const q = `
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		FROM event_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.event_id = ANY($1)
+		ORDER BY p.event_id, u.name
+	`
+	rows, err := r.pool.Query(ctx, q, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[int][]models.Participant, len(eventIDs))
+	for rows.Next() {
+		var p models.Participant
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+			return nil, err
+		}
+		res[p.EventID] = append(res[p.EventID], p)
+	}
+	return res, rows.Err()
+}
+
+// ListTasks returns the event's tasks in their order, only those with every
+// one of labels when any are given.
+func (r *eventRepository) ListTasks(ctx context.Context, eventID int, labels []string) ([]models.Task, error) {
+	q := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.event_id = $1 AND ` + hasLabels("$2") + `
+		ORDER BY t.position NULLS LAST, t.due_date NULLS LAST, t.id
+	`
+	if labels == nil {
+		labels = []string{}
+	}
+	rows, err := r.pool.Query(ctx, q, eventID, labels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tasks := []models.Task{}
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// ListTasksByEvents loads the tasks of several events in one query, keyed by event ID.
+func (r *eventRepository) ListTasksByEvents(ctx context.Context, eventIDs []int) (map[int][]models.Task, error) {
+	const q = `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.event_id = ANY($1)
+		ORDER BY t.event_id, t.position NULLS LAST, t.due_date NULLS LAST, t.id
+	`
+	rows, err := r.pool.Query(ctx, q, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[int][]models.Task, len(eventIDs))
+	for rows.Next() {
+		var t models.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		res[t.EventID] = append(res[t.EventID], t)
+	}
+	return res, rows.Err()
+}
+
+// ListWithRelations returns the events userID participates in (optionally limited to
+// eventIDs), hydrated with participants and/or tasks. All queries are sent as one
+// batch so the whole result costs a single round trip. Participants are loaded
+// for every event; which of them the user may see is up to the caller.
+func (r *eventRepository) ListWithRelations(ctx context.Context, userID int, eventIDs []int, withParticipants, withTasks bool) ([]models.EventDetails, error) {
+	eventsQ := `
+		SELECT ` + eventColumns + `
+		FROM ` + eventFrom + `
+		JOIN event_participants p ON p.event_id = e.id
+		WHERE p.user_id = $1 AND ($2::int[] IS NULL OR e.id = ANY($2)) AND e.deleted_at IS NULL
+		ORDER BY e.start_time ASC
+	`
+	const participantsQ = `
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		FROM event_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.event_id IN (
+			SELECT event_id FROM event_participants
+			WHERE user_id = $1 AND ($2::int[] IS NULL OR event_id = ANY($2))
+		)
+		ORDER BY p.event_id, u.name
+	`
+	const tasksQ = `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.event_id IN (
+			SELECT event_id FROM event_participants
+			WHERE user_id = $1 AND ($2::int[] IS NULL OR event_id = ANY($2))
+		)
+		ORDER BY t.event_id, t.position NULLS LAST, t.due_date NULLS LAST, t.id
+	`
+
+	batch := &pgx.Batch{}
+	batch.Queue(eventsQ, userID, eventIDs)
+	if withParticipants {
+		batch.Queue(participantsQ, userID, eventIDs)
+	}
+	if withTasks {
+		batch.Queue(tasksQ, userID, eventIDs)
+	}
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, err
+	}
+	var res []models.EventDetails
+	index := map[int]int{}
+	for rows.Next() {
+		var d models.EventDetails
+		if err := scanEvent(rows, &d.Event); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[d.ID] = len(res)
+		res = append(res, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if withParticipants {
+		rows, err := br.Query()
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var p models.Participant
+			if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if i, ok := index[p.EventID]; ok {
+				res[i].Participants = append(res[i].Participants, p)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if withTasks {
+		rows, err := br.Query()
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var t models.Task
+			if err := scanTask(rows, &t); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if i, ok := index[t.EventID]; ok {
+				res[i].Tasks = append(res[i].Tasks, t)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// ListInRange returns every event the user participates in, in any role, that
+// overlaps [from, to). Events without an end time are treated as instants.
+func (r *eventRepository) ListInRange(ctx context.Context, userID int, from, to time.Time) ([]models.CalendarEntry, error) {
+	q := `
+		SELECT ` + eventColumns + `, p.role, p.attendance
+		FROM ` + eventFrom + `
+		JOIN event_participants p ON p.event_id = e.id
+		WHERE p.user_id = $1 AND e.start_time < $3 AND COALESCE(e.end_time, e.start_time) >= $2 AND e.deleted_at IS NULL
+		ORDER BY e.start_time, e.id
+	`
+	rows, err := r.pool.Query(ctx, q, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.CalendarEntry
+	for rows.Next() {
+		var c models.CalendarEntry
+		if err := scanEvent(rows, &c.Event, &c.Role, &c.Attendance); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+const roleColumns = `id, event_id, name, permissions, created_at`
+
+func scanRole(row pgx.Row) (*models.EventRole, error) {
+	var role models.EventRole
+	var perms []string
+	if err := row.Scan(&role.ID, &role.EventID, &role.Name, &perms, &role.CreatedAt); err != nil {
+		return nil, err
+	}
+	role.Permissions = toPermissions(perms)
+	return &role, nil
+}
+
+func toPermissions(perms []string) []models.Permission {
+	res := make([]models.Permission, len(perms))
+	for i, p := range perms {
+		res[i] = models.Permission(p)
+	}
+	return res
+}
+
+// CreateRole stores a custom role for an event.
+func (r *eventRepository) CreateRole(ctx context.Context, eventID int, name string, perms []models.Permission) (*models.EventRole, error) {
+	names := make([]string, len(perms))
+	for i, p := range perms {
+		names[i] = string(p)
+	}
+	q := `
+		INSERT INTO event_roles (event_id, name, permissions)
+		VALUES ($1, $2, $3)
+		RETURNING ` + roleColumns
+	return scanRole(r.pool.QueryRow(ctx, q, eventID, name, names))
+}
+
+// GetRole returns a custom role of an event, or pgx.ErrNoRows.
+func (r *eventRepository) GetRole(ctx context.Context, eventID int, name string) (*models.EventRole, error) {
+	q := `SELECT ` + roleColumns + ` FROM event_roles WHERE event_id = $1 AND name = $2`
+	return scanRole(r.pool.QueryRow(ctx, q, eventID, name))
+}
+
+// ListRoles returns the custom roles of an event by name.
+func (r *eventRepository) ListRoles(ctx context.Context, eventID int) ([]models.EventRole, error) {
+	q := `SELECT ` + roleColumns + ` FROM event_roles WHERE event_id = $1 ORDER BY name`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.EventRole
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *role)
+	}
+	return res, rows.Err()
+}
+
+// DeleteRole removes a custom role; pgx.ErrNoRows if it does not exist.
+func (r *eventRepository) DeleteRole(ctx context.Context, eventID int, name string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM event_roles WHERE event_id = $1 AND name = $2`, eventID, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// RoleInUse reports whether any participant of the event holds the role.
+func (r *eventRepository) RoleInUse(ctx context.Context, eventID int, name string) (bool, error) {
+	var inUse bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM event_participants WHERE event_id = $1 AND role = $2)`,
+		eventID, name).Scan(&inUse)
+	return inUse, err
+}
+
+const questionColumns = `id, event_id, prompt, kind, options, required, position, created_at`
+
+func scanQuestion(row pgx.Row, q *models.RSVPQuestion) error {
+	return row.Scan(&q.ID, &q.EventID, &q.Prompt, &q.Kind, &q.Options, &q.Required, &q.Position, &q.CreatedAt)
+}
+
+// CreateQuestion appends an RSVP question after the event's existing ones.
+func (r *eventRepository) CreateQuestion(ctx context.Context, q models.RSVPQuestion) (*models.RSVPQuestion, error) {
+	query := `
+		INSERT INTO rsvp_questions (event_id, prompt, kind, options, required, position)
+		VALUES ($1, $2, $3, $4, $5, (SELECT COALESCE(MAX(position), 0) + 1 FROM rsvp_questions WHERE event_id = $1))
+		RETURNING ` + questionColumns
+	var created models.RSVPQuestion
+	if err := scanQuestion(r.pool.QueryRow(ctx, query, q.EventID, q.Prompt, q.Kind, q.Options, q.Required), &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListQuestions returns the RSVP questions of an event in display order.
+func (r *eventRepository) ListQuestions(ctx context.Context, eventID int) ([]models.RSVPQuestion, error) {
+	query := `SELECT ` + questionColumns + ` FROM rsvp_questions WHERE event_id = $1 ORDER BY position, id`
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.RSVPQuestion
+	for rows.Next() {
+		var q models.RSVPQuestion
+		if err := scanQuestion(rows, &q); err != nil {
+			return nil, err
+		}
+		res = append(res, q)
+	}
+	return res, rows.Err()
+}
+
+// DeleteQuestion removes a question and its answers; pgx.ErrNoRows if the
+// event has no such question.
+func (r *eventRepository) DeleteQuestion(ctx context.Context, eventID, questionID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM rsvp_questions WHERE id = $1 AND event_id = $2`, questionID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListAnswers returns every answer given for the event, keyed by user ID and
+// then question ID.
+func (r *eventRepository) ListAnswers(ctx context.Context, eventID int) (map[int]map[int]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, question_id, answer FROM rsvp_answers WHERE event_id = $1`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[int]map[int]string{}
+	for rows.Next() {
+		var userID, questionID int
+		var answer string
+		if err := rows.Scan(&userID, &questionID, &answer); err != nil {
+			return nil, err
+		}
+		if res[userID] == nil {
+			res[userID] = map[int]string{}
+		}
+		res[userID][questionID] = answer
+	}
+	return res, rows.Err()
+}
+
+// ListParticipantsByAttendance returns the participants whose attendance is one
+// of the given statuses, where "pending" matches no answer yet. An empty
+// filter returns every participant.
+func (r *eventRepository) ListParticipantsByAttendance(ctx context.Context, eventID int, attendance []string) ([]models.Participant, error) {
+	const q = `
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		FROM event_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.event_id = $1
+			AND (cardinality($2::text[]) = 0 OR COALESCE(p.attendance::text, 'pending') = ANY($2))
+		ORDER BY u.name
+	`
+	if attendance == nil {
+		attendance = []string{}
+	}
+	rows, err := r.pool.Query(ctx, q, eventID, attendance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.Participant
+	for rows.Next() {
+		var p models.Participant
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
+
+const announcementColumns = `id, event_id, author_id, title, body, audience, recipient_count, created_at`
+
+func scanAnnouncement(row pgx.Row, a *models.Announcement) error {
+	if err := row.Scan(&a.ID, &a.EventID, &a.AuthorID, &a.Title, &a.Body, &a.Audience, &a.RecipientCount, &a.CreatedAt); err != nil {
+		return err
+	}
+	a.BodyHTML = markdown.Render(a.Body)
+	return nil
+}
+
+func (r *eventRepository) CreateAnnouncement(ctx context.Context, a models.Announcement) (*models.Announcement, error) {
+	q := `
+		INSERT INTO announcements (event_id, author_id, title, body, audience, recipient_count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + announcementColumns
+	var out models.Announcement
+	if err := scanAnnouncement(r.pool.QueryRow(ctx, q, a.EventID, a.AuthorID, a.Title, a.Body, a.Audience, a.RecipientCount), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAnnouncements returns an event's announcements, newest first.
+func (r *eventRepository) ListAnnouncements(ctx context.Context, eventID int) ([]models.Announcement, error) {
+	q := `SELECT ` + announcementColumns + ` FROM announcements WHERE event_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.Announcement
+	for rows.Next() {
+		var a models.Announcement
+		if err := scanAnnouncement(rows, &a); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// nearCondition builds the radius filter on the venue joined as v: a bounding
+// box the (latitude, longitude) index can serve, then the exact haversine
+// distance.
+func nearCondition(q *selectQuery, g models.GeoFilter) string {
+	minLat, maxLat, minLng, maxLng, wraps := geocoding.BoundingBox(g.Latitude, g.Longitude, g.RadiusKm)
+	cond := "v.latitude BETWEEN " + q.arg(minLat) + " AND " + q.arg(maxLat)
+	if !wraps {
+		cond += " AND v.longitude BETWEEN " + q.arg(minLng) + " AND " + q.arg(maxLng)
+	}
+	lat, lng := q.arg(g.Latitude)+"::float8", q.arg(g.Longitude)+"::float8"
+	cond += " AND 6371 * 2 * asin(sqrt(power(sin(radians(v.latitude - " + lat + ") / 2), 2) + " +
+		"cos(radians(" + lat + ")) * cos(radians(v.latitude)) * power(sin(radians(v.longitude - " + lng + ") / 2), 2))) <= " + q.arg(g.RadiusKm)
+	return "(" + cond + ")"
+}
+
+func itoa(i int) string { return fmtInt(i) }
+
 func fmtInt(i int) string {
 	return fmt.Sprintf("%d", i)
 }
+
+// MarkViewed records when the participant first opened the event.
+func (r *eventRepository) MarkViewed(ctx context.Context, eventID, userID int) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE event_participants SET viewed_at = now()
+		WHERE event_id = $1 AND user_id = $2 AND viewed_at IS NULL
+	`, eventID, userID)
+	return err
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FeatureFlagRepository interface {
+	ListFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	SetFlag(ctx context.Context, f models.FeatureFlag) (*models.FeatureFlag, error)
+	DeleteFlag(ctx context.Context, name string) error
+}
+
+type featureFlagRepository struct {
+	pool *database.DB
+}
+
+func NewFeatureFlagRepository(pool *database.DB) FeatureFlagRepository {
+	return &featureFlagRepository{pool: pool}
+}
+
+func (r *featureFlagRepository) ListFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT name, enabled, percent, user_ids, updated_at FROM feature_flags ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.FeatureFlag
+	for rows.Next() {
+		f := models.FeatureFlag{Source: models.FlagSourceDatabase}
+		if err := rows.Scan(&f.Name, &f.Enabled, &f.Percent, &f.UserIDs, &f.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, f)
+	}
+	return res, rows.Err()
+}
+
+// SetFlag creates or replaces the flag.
+func (r *featureFlagRepository) SetFlag(ctx context.Context, f models.FeatureFlag) (*models.FeatureFlag, error) {
+	if f.UserIDs == nil {
+		f.UserIDs = []int{}
+	}
+	f.Source = models.FlagSourceDatabase
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO feature_flags (name, enabled, percent, user_ids) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET enabled = EXCLUDED.enabled, percent = EXCLUDED.percent, user_ids = EXCLUDED.user_ids, updated_at = now()
+		RETURNING updated_at
+	`, f.Name, f.Enabled, f.Percent, f.UserIDs).Scan(&f.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// DeleteFlag removes the flag; pgx.ErrNoRows when there is none.
+func (r *featureFlagRepository) DeleteFlag(ctx context.Context, name string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM feature_flags WHERE name = $1`, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FeedbackRepository interface {
+	CreateQuestion(ctx context.Context, q models.FeedbackQuestion) (*models.FeedbackQuestion, error)
+	ListQuestions(ctx context.Context, eventID int) ([]models.FeedbackQuestion, error)
+	DeleteQuestion(ctx context.Context, eventID, questionID int) error
+	Submit(ctx context.Context, eventID, userID int, answers []models.FeedbackAnswer) error
+	CountResponses(ctx context.Context, eventID int) (int, error)
+	ListAnswers(ctx context.Context, eventID int) ([]models.FeedbackAnswer, error)
+}
+
+type feedbackRepository struct {
+	pool *database.DB
+}
+
+func NewFeedbackRepository(pool *database.DB) FeedbackRepository {
+	return &feedbackRepository{pool: pool}
+}
+
+const feedbackQuestionColumns = `id, event_id, prompt, kind, required, position, created_at`
+
+func scanFeedbackQuestion(row pgx.Row) (*models.FeedbackQuestion, error) {
+	var q models.FeedbackQuestion
+	if err := row.Scan(&q.ID, &q.EventID, &q.Prompt, &q.Kind, &q.Required, &q.Position, &q.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// CreateQuestion appends a question after the event's existing ones.
+func (r *feedbackRepository) CreateQuestion(ctx context.Context, q models.FeedbackQuestion) (*models.FeedbackQuestion, error) {
+	query := `
+		INSERT INTO feedback_questions (event_id, prompt, kind, required, position)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), 0) + 1 FROM feedback_questions WHERE event_id = $1))
+		RETURNING ` + feedbackQuestionColumns
+	return scanFeedbackQuestion(r.pool.QueryRow(ctx, query, q.EventID, q.Prompt, q.Kind, q.Required))
+}
+
+// ListQuestions returns the event's feedback questions in display order.
+func (r *feedbackRepository) ListQuestions(ctx context.Context, eventID int) ([]models.FeedbackQuestion, error) {
+	query := `SELECT ` + feedbackQuestionColumns + ` FROM feedback_questions WHERE event_id = $1 ORDER BY position, id`
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.FeedbackQuestion{}
+	for rows.Next() {
+		q, err := scanFeedbackQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *q)
+	}
+	return res, rows.Err()
+}
+
+// DeleteQuestion removes a question and its answers; pgx.ErrNoRows if the
+// event has no such question.
+func (r *feedbackRepository) DeleteQuestion(ctx context.Context, eventID, questionID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM feedback_questions WHERE id = $1 AND event_id = $2`, questionID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Submit stores the user's answers. A second submission for the same event
+// fails with a duplicate key error.
+func (r *feedbackRepository) Submit(ctx context.Context, eventID, userID int, answers []models.FeedbackAnswer) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO feedback_responses (event_id, user_id) VALUES ($1, $2)`, eventID, userID); err != nil {
+		return err
+	}
+	for _, a := range answers {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO feedback_answers (question_id, event_id, user_id, rating, comment)
+			VALUES ($1, $2, $3, $4, $5)
+		`, a.QuestionID, eventID, userID, a.Rating, a.Comment); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *feedbackRepository) CountResponses(ctx context.Context, eventID int) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM feedback_responses WHERE event_id = $1`, eventID).Scan(&n)
+	return n, err
+}
+
+// ListAnswers returns every answer given for the event without saying who
+// gave it. Comments are sorted by text so their order does not hint at the
+// author either.
+func (r *feedbackRepository) ListAnswers(ctx context.Context, eventID int) ([]models.FeedbackAnswer, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT question_id, rating, comment FROM feedback_answers
+		WHERE event_id = $1
+		ORDER BY question_id, comment, rating
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.FeedbackAnswer{}
+	for rows.Next() {
+		var a models.FeedbackAnswer
+		if err := rows.Scan(&a.QuestionID, &a.Rating, &a.Comment); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
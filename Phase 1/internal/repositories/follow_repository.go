@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FollowRepository interface {
+	FollowOrganizer(ctx context.Context, followerID, organizerID int) error
+	UnfollowOrganizer(ctx context.Context, followerID, organizerID int) error
+	FollowSeries(ctx context.Context, followerID, seriesID int) error
+	UnfollowSeries(ctx context.Context, followerID, seriesID int) error
+	Following(ctx context.Context, userID int) (*models.Following, error)
+	CountOrganizerFollowers(ctx context.Context, organizerID int) (int, error)
+	CountSeriesFollowers(ctx context.Context, seriesID int) (int, error)
+	HasPublishedOccurrence(ctx context.Context, seriesID int) (bool, error)
+	Followers(ctx context.Context, organizerID int, seriesID *int) ([]models.UserSummary, error)
+}
+
+type followRepository struct {
+	pool *database.DB
+}
+
+func NewFollowRepository(pool *database.DB) FollowRepository {
+	return &followRepository{pool: pool}
+}
+
+// FollowOrganizer is a no-op if the user already follows the organizer. An
+// unknown organizer fails with a foreign key violation.
+func (r *followRepository) FollowOrganizer(ctx context.Context, followerID, organizerID int) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO organizer_follows (follower_id, organizer_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, followerID, organizerID)
+	return err
+}
+
+// UnfollowOrganizer returns pgx.ErrNoRows if the user did not follow them.
+func (r *followRepository) UnfollowOrganizer(ctx context.Context, followerID, organizerID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM organizer_follows WHERE follower_id = $1 AND organizer_id = $2`, followerID, organizerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// FollowSeries is a no-op if the user already follows the series.
+func (r *followRepository) FollowSeries(ctx context.Context, followerID, seriesID int) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO series_follows (follower_id, series_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, followerID, seriesID)
+	return err
+}
+
+// UnfollowSeries returns pgx.ErrNoRows if the user did not follow it.
+func (r *followRepository) UnfollowSeries(ctx context.Context, followerID, seriesID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM series_follows WHERE follower_id = $1 AND series_id = $2`, followerID, seriesID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Following returns the organizers and series the user follows, by name.
+func (r *followRepository) Following(ctx context.Context, userID int) (*models.Following, error) {
+	res := &models.Following{Organizers: []models.FollowedOrganizer{}, Series: []models.FollowedSeries{}}
+	rows, err := r.pool.Query(ctx, `
+		SELECT f.organizer_id, u.name, f.created_at
+		FROM organizer_follows f JOIN users u ON u.id = f.organizer_id
+		WHERE f.follower_id = $1
+		ORDER BY lower(u.name), f.organizer_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o models.FollowedOrganizer
+		if err := rows.Scan(&o.UserID, &o.Name, &o.FollowedAt); err != nil {
+			return nil, err
+		}
+		res.Organizers = append(res.Organizers, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT f.series_id, s.name, f.created_at
+		FROM series_follows f JOIN event_series s ON s.id = f.series_id
+		WHERE f.follower_id = $1
+		ORDER BY lower(s.name), f.series_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.FollowedSeries
+		if err := rows.Scan(&s.SeriesID, &s.Name, &s.FollowedAt); err != nil {
+			return nil, err
+		}
+		res.Series = append(res.Series, s)
+	}
+	return res, rows.Err()
+}
+
+func (r *followRepository) CountOrganizerFollowers(ctx context.Context, organizerID int) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM organizer_follows WHERE organizer_id = $1`, organizerID).Scan(&n)
+	return n, err
+}
+
+func (r *followRepository) CountSeriesFollowers(ctx context.Context, seriesID int) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM series_follows WHERE series_id = $1`, seriesID).Scan(&n)
+	return n, err
+}
+
+// HasPublishedOccurrence reports whether any event of the series has a
+// public landing page.
+func (r *followRepository) HasPublishedOccurrence(ctx context.Context, seriesID int) (bool, error) {
+	var published bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM events WHERE series_id = $1 AND published_at IS NOT NULL AND deleted_at IS NULL)
+	`, seriesID).Scan(&published)
+	return published, err
+}
+
+// Followers returns everyone following the organizer or, if given, the
+// series, each once.
+func (r *followRepository) Followers(ctx context.Context, organizerID int, seriesID *int) ([]models.UserSummary, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id, u.name, u.email FROM users u
+		WHERE u.id IN (
+			SELECT follower_id FROM organizer_follows WHERE organizer_id = $1
+			UNION
+			SELECT follower_id FROM series_follows WHERE series_id = $2
+		)
+		ORDER BY u.id
+	`, organizerID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.UserSummary{}
+	for rows.Next() {
+		var u models.UserSummary
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type InboundWebhookRepository interface {
+	Create(ctx context.Context, w models.InboundWebhook) (*models.InboundWebhook, error)
+	Update(ctx context.Context, w models.InboundWebhook) (*models.InboundWebhook, error)
+	List(ctx context.Context, userID int) ([]models.InboundWebhook, error)
+	Delete(ctx context.Context, id, userID int) error
+	GetWithSecret(ctx context.Context, id int) (*models.InboundWebhook, string, error)
+	RecordDelivery(ctx context.Context, id int, deliveryErr *string) error
+	ClaimDelivery(ctx context.Context, id int, signature string, pruneBefore time.Time) (bool, error)
+	ReleaseDelivery(ctx context.Context, id int, signature string) error
+	GetSecret(ctx context.Context, userID int) (string, error)
+	EnsureSecret(ctx context.Context, userID int, secret string) (string, error)
+	SetSecret(ctx context.Context, userID int, secret string) error
+}
+
+type inboundWebhookRepository struct {
+	pool *database.DB
+}
+
+func NewInboundWebhookRepository(pool *database.DB) InboundWebhookRepository {
+	return &inboundWebhookRepository{pool: pool}
+}
+
+const inboundWebhookColumns = `w.id, w.user_id, w.name, w.target, w.event_id, w.mapping, w.last_received_at, w.last_error, w.created_at, w.updated_at`
+
+func scanInboundWebhook(row pgx.Row, w *models.InboundWebhook, extra ...any) error {
+	dest := []any{&w.ID, &w.UserID, &w.Name, &w.Target, &w.EventID, &w.Mapping, &w.LastReceivedAt, &w.LastError, &w.CreatedAt, &w.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (r *inboundWebhookRepository) Create(ctx context.Context, w models.InboundWebhook) (*models.InboundWebhook, error) {
+	q := `
+		INSERT INTO inbound_webhooks AS w (user_id, name, target, event_id, mapping)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + inboundWebhookColumns
+	var out models.InboundWebhook
+	if err := scanInboundWebhook(r.pool.QueryRow(ctx, q, w.UserID, w.Name, w.Target, w.EventID, w.Mapping), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Update replaces a webhook owned by w.UserID.
+func (r *inboundWebhookRepository) Update(ctx context.Context, w models.InboundWebhook) (*models.InboundWebhook, error) {
+	q := `
+		UPDATE inbound_webhooks AS w
+		SET name = $3, target = $4, event_id = $5, mapping = $6, updated_at = now()
+		WHERE w.id = $1 AND w.user_id = $2
+		RETURNING ` + inboundWebhookColumns
+	var out models.InboundWebhook
+	if err := scanInboundWebhook(r.pool.QueryRow(ctx, q, w.ID, w.UserID, w.Name, w.Target, w.EventID, w.Mapping), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *inboundWebhookRepository) List(ctx context.Context, userID int) ([]models.InboundWebhook, error) {
+	q := `SELECT ` + inboundWebhookColumns + ` FROM inbound_webhooks w WHERE w.user_id = $1 ORDER BY w.name, w.id`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.InboundWebhook{}
+	for rows.Next() {
+		var w models.InboundWebhook
+		if err := scanInboundWebhook(rows, &w); err != nil {
+			return nil, err
+		}
+		res = append(res, w)
+	}
+	return res, rows.Err()
+}
+
+func (r *inboundWebhookRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM inbound_webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetWithSecret returns a webhook with its owner's signing secret.
+// pgx.ErrNoRows if either does not exist.
+func (r *inboundWebhookRepository) GetWithSecret(ctx context.Context, id int) (*models.InboundWebhook, string, error) {
+	q := `SELECT ` + inboundWebhookColumns + `, s.secret
+		FROM inbound_webhooks w
+		JOIN inbound_webhook_secrets s ON s.user_id = w.user_id
+		WHERE w.id = $1`
+	var w models.InboundWebhook
+	var secret string
+	if err := scanInboundWebhook(r.pool.QueryRow(ctx, q, id), &w, &secret); err != nil {
+		return nil, "", err
+	}
+	return &w, secret, nil
+}
+
+func (r *inboundWebhookRepository) RecordDelivery(ctx context.Context, id int, deliveryErr *string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE inbound_webhooks SET last_received_at = now(), last_error = $2 WHERE id = $1`, id, deliveryErr)
+	return err
+}
+
+// ClaimDelivery records a delivery to the webhook by its signature, reporting
+// false if one with the same signature was already recorded. Deliveries
+// recorded before pruneBefore are dropped first.
+func (r *inboundWebhookRepository) ClaimDelivery(ctx context.Context, id int, signature string, pruneBefore time.Time) (bool, error) {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM inbound_webhook_deliveries WHERE webhook_id = $1 AND received_at < $2`, id, pruneBefore); err != nil {
+		return false, err
+	}
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO inbound_webhook_deliveries (webhook_id, signature)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, id, signature)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseDelivery forgets a claimed delivery, so the same request can be
+// delivered again.
+func (r *inboundWebhookRepository) ReleaseDelivery(ctx context.Context, id int, signature string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM inbound_webhook_deliveries WHERE webhook_id = $1 AND signature = $2`, id, signature)
+	return err
+}
+
+// GetSecret returns the user's signing secret; pgx.ErrNoRows if they have
+// none yet.
+func (r *inboundWebhookRepository) GetSecret(ctx context.Context, userID int) (string, error) {
+	var secret string
+	err := r.pool.QueryRow(ctx, `SELECT secret FROM inbound_webhook_secrets WHERE user_id = $1`, userID).Scan(&secret)
+	return secret, err
+}
+
+// EnsureSecret stores secret as the user's signing secret unless they have one
+// already, and returns the one in effect.
+func (r *inboundWebhookRepository) EnsureSecret(ctx context.Context, userID int, secret string) (string, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO inbound_webhook_secrets (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = inbound_webhook_secrets.secret
+		RETURNING secret
+	`, userID, secret).Scan(&secret)
+	return secret, err
+}
+
+func (r *inboundWebhookRepository) SetSecret(ctx context.Context, userID int, secret string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO inbound_webhook_secrets (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, created_at = now()
+	`, userID, secret)
+	return err
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IntegrationRepository interface {
+	CreateAPIKey(ctx context.Context, k models.APIKey, keyHash string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id, userID int) error
+	APIKeyUser(ctx context.Context, keyHash string) (int, error)
+	NewEvents(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.Event, error)
+	NewRSVPs(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.RSVPTrigger, error)
+	CompletedTasks(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.TaskCompletedTrigger, error)
+}
+
+type integrationRepository struct {
+	pool *database.DB
+}
+
+func NewIntegrationRepository(pool *database.DB) IntegrationRepository {
+	return &integrationRepository{pool: pool}
+}
+
+const apiKeyColumns = `k.id, k.user_id, k.name, k.prefix, k.last_used_at, k.created_at`
+
+func scanAPIKey(row pgx.Row, k *models.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.LastUsedAt, &k.CreatedAt)
+}
+
+func (r *integrationRepository) CreateAPIKey(ctx context.Context, k models.APIKey, keyHash string) (*models.APIKey, error) {
+	q := `
+		INSERT INTO api_keys AS k (user_id, name, prefix, key_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + apiKeyColumns
+	var out models.APIKey
+	if err := scanAPIKey(r.pool.QueryRow(ctx, q, k.UserID, k.Name, k.Prefix, keyHash), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *integrationRepository) ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys k WHERE k.user_id = $1 ORDER BY k.created_at, k.id`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := scanAPIKey(rows, &k); err != nil {
+			return nil, err
+		}
+		res = append(res, k)
+	}
+	return res, rows.Err()
+}
+
+func (r *integrationRepository) DeleteAPIKey(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// APIKeyUser returns the owner of the key with the given hash and notes that
+// it was used, at most once a minute so polling does not write on every call.
+// pgx.ErrNoRows for unknown keys.
+func (r *integrationRepository) APIKeyUser(ctx context.Context, keyHash string) (int, error) {
+	var userID int
+	var lastUsed *time.Time
+	err := r.pool.QueryRow(ctx, `SELECT user_id, last_used_at FROM api_keys WHERE key_hash = $1`, keyHash).Scan(&userID, &lastUsed)
+	if err != nil {
+		return 0, err
+	}
+	if lastUsed == nil || time.Since(*lastUsed) > time.Minute {
+		if _, err := r.pool.Exec(ctx, `UPDATE api_keys SET last_used_at = now() WHERE key_hash = $1`, keyHash); err != nil {
+			return 0, err
+		}
+	}
+	return userID, nil
+}
+
+// organizedEvents selects the ids of the events $1 organizes.
+const organizedEvents = `SELECT event_id FROM event_participants WHERE user_id = $1 AND role = 'organizer'`
+
+// pageTrigger orders and limits q, a trigger feed query whose rows sort by
+// cols, the time first. With a cursor it selects the rows after it, oldest
+// first; without, the newest rows, which the caller reverses when the last
+// result is true.
+func pageTrigger(q string, args []any, cols []string, after *models.TriggerCursor, limit int) (string, []any, bool) {
+	dir := " DESC"
+	if after != nil {
+		vals := []any{after.At, after.A, after.B}
+		placeholders := make([]string, len(cols))
+		for i := range cols {
+			args = append(args, vals[i])
+			placeholders[i] = "$" + itoa(len(args))
+		}
+		q += ` AND (` + strings.Join(cols, ", ") + `) > (` + strings.Join(placeholders, ", ") + `)`
+		dir = " ASC"
+	}
+	order := make([]string, len(cols))
+	for i, col := range cols {
+		order[i] = col + dir
+	}
+	args = append(args, limit)
+	q += ` ORDER BY ` + strings.Join(order, ", ") + ` LIMIT $` + itoa(len(args))
+	return q, args, after == nil
+}
+
+// NewEvents returns the events userID organizes created up to until.
+func (r *integrationRepository) NewEvents(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.Event, error) {
+	q := `SELECT ` + eventColumns + ` FROM ` + eventFrom + `
+		WHERE e.id IN (` + organizedEvents + `) AND e.deleted_at IS NULL AND e.created_at <= $2`
+	q, args, reverse := pageTrigger(q, []any{userID, until}, []string{"e.created_at", "e.id"}, after, limit)
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	if reverse {
+		slices.Reverse(res)
+	}
+	return res, rows.Err()
+}
+
+// NewRSVPs returns the RSVPs to events userID organizes, by when they were
+// last changed, up to until. Organizers' own attendance is left out.
+func (r *integrationRepository) NewRSVPs(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.RSVPTrigger, error) {
+	q := `
+		SELECT p.event_id, e.title, p.user_id, u.name, u.email, p.attendance, p.responded_at
+		FROM event_participants p
+		JOIN events e ON e.id = p.event_id
+		JOIN users u ON u.id = p.user_id
+		WHERE p.event_id IN (` + organizedEvents + `) AND e.deleted_at IS NULL
+			AND p.role <> 'organizer' AND p.attendance IS NOT NULL AND p.responded_at <= $2`
+	q, args, reverse := pageTrigger(q, []any{userID, until}, []string{"p.responded_at", "p.event_id", "p.user_id"}, after, limit)
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.RSVPTrigger{}
+	for rows.Next() {
+		var rsvp models.RSVPTrigger
+		if err := rows.Scan(&rsvp.EventID, &rsvp.EventTitle, &rsvp.UserID, &rsvp.UserName, &rsvp.UserEmail, &rsvp.Attendance, &rsvp.RespondedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, rsvp)
+	}
+	if reverse {
+		slices.Reverse(res)
+	}
+	return res, rows.Err()
+}
+
+// CompletedTasks returns the tasks of events userID organizes completed up to
+// until.
+func (r *integrationRepository) CompletedTasks(ctx context.Context, userID int, after *models.TriggerCursor, until time.Time, limit int) ([]models.TaskCompletedTrigger, error) {
+	q := `
+		SELECT ` + taskColumns + `, e.title
+		FROM tasks t
+		JOIN events e ON e.id = t.event_id
+		WHERE t.event_id IN (` + organizedEvents + `) AND e.deleted_at IS NULL
+			AND t.completed_at IS NOT NULL AND t.completed_at <= $2`
+	q, args, reverse := pageTrigger(q, []any{userID, until}, []string{"t.completed_at", "t.id"}, after, limit)
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.TaskCompletedTrigger{}
+	for rows.Next() {
+		var t models.TaskCompletedTrigger
+		if err := scanTask(rows, &t.Task, &t.EventTitle); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	if reverse {
+		slices.Reverse(res)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+)
+
+// JobRepository stores background jobs that failed every attempt.
+type JobRepository interface {
+	AddDeadJob(ctx context.Context, kind string, payload []byte, attempts int, lastError string, enqueuedAt time.Time) error
+}
+
+type jobRepository struct {
+	pool *database.DB
+}
+
+func NewJobRepository(pool *database.DB) JobRepository {
+	return &jobRepository{pool: pool}
+}
+
+func (r *jobRepository) AddDeadJob(ctx context.Context, kind string, payload []byte, attempts int, lastError string, enqueuedAt time.Time) error {
+	const q = `
+		INSERT INTO dead_jobs (kind, payload, attempts, last_error, enqueued_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, q, kind, payload, attempts, lastError, enqueuedAt)
+	return err
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// LinkPreviewRepository caches the previews of linked pages.
+type LinkPreviewRepository interface {
+	Get(ctx context.Context, urls []string) (map[string]models.LinkPreview, error)
+	Claim(ctx context.Context, urls []string, ttl, failedTTL time.Duration) ([]string, error)
+	Save(ctx context.Context, url string, preview *models.LinkPreview) error
+}
+
+type linkPreviewRepository struct {
+	pool *database.DB
+}
+
+func NewLinkPreviewRepository(pool *database.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{pool: pool}
+}
+
+// Get returns the cached previews of the pages at urls that have one, by
+// URL.
+func (r *linkPreviewRepository) Get(ctx context.Context, urls []string) (map[string]models.LinkPreview, error) {
+	const q = `
+		SELECT url, title, description, image_url, site_name
+		FROM link_previews
+		WHERE url = ANY($1) AND fetched_at IS NOT NULL AND NOT failed
+	`
+	rows, err := r.pool.Query(ctx, q, urls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[string]models.LinkPreview{}
+	for rows.Next() {
+		var p models.LinkPreview
+		if err := rows.Scan(&p.URL, &p.Title, &p.Description, &p.ImageURL, &p.SiteName); err != nil {
+			return nil, err
+		}
+		res[p.URL] = p
+	}
+	return res, rows.Err()
+}
+
+// Claim returns those of the distinct urls whose page is to be fetched:
+// never fetched, or last fetched longer ago than ttl, or failedTTL when it
+// failed. The caller must then fetch them and Save the result; until then,
+// or ten minutes pass, nobody else is given them.
+func (r *linkPreviewRepository) Claim(ctx context.Context, urls []string, ttl, failedTTL time.Duration) ([]string, error) {
+	const q = `
+		INSERT INTO link_previews AS p (url, claimed_at)
+		SELECT u, now() FROM unnest($1::text[]) AS u
+		ON CONFLICT (url) DO UPDATE SET claimed_at = now()
+		WHERE (p.fetched_at IS NULL OR p.fetched_at < now() - CASE WHEN p.failed THEN $3::int ELSE $2::int END * interval '1 second')
+			AND (p.claimed_at IS NULL OR p.claimed_at < now() - interval '10 minutes')
+		RETURNING url
+	`
+	rows, err := r.pool.Query(ctx, q, urls, int(ttl.Seconds()), int(failedTTL.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var claimed []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, u)
+	}
+	return claimed, rows.Err()
+}
+
+// Save stores the preview of a claimed page, or with preview nil that it has
+// none. A failure keeps the previous preview but hides it.
+func (r *linkPreviewRepository) Save(ctx context.Context, url string, preview *models.LinkPreview) error {
+	if preview == nil {
+		_, err := r.pool.Exec(ctx, `UPDATE link_previews SET failed = true, fetched_at = now(), claimed_at = NULL WHERE url = $1`, url)
+		return err
+	}
+	const q = `
+		UPDATE link_previews
+		SET title = $2, description = $3, image_url = $4, site_name = $5, failed = false, fetched_at = now(), claimed_at = NULL
+		WHERE url = $1
+	`
+	_, err := r.pool.Exec(ctx, q, url, preview.Title, preview.Description, preview.ImageURL, preview.SiteName)
+	return err
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type NotificationRepository interface {
+	AddToInbox(ctx context.Context, userIDs []int, kind, title, body string, eventID *int) error
+	ListForUser(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkRead(ctx context.Context, userID, id int) error
+	MutedUsers(ctx context.Context, eventID int, userIDs []int) ([]int, error)
+}
+
+type notificationRepository struct {
+	pool *database.DB
+}
+
+func NewNotificationRepository(pool *database.DB) NotificationRepository {
+	return &notificationRepository{pool: pool}
+}
+
+// AddToInbox stores one notification per user in a single statement.
+func (r *notificationRepository) AddToInbox(ctx context.Context, userIDs []int, kind, title, body string, eventID *int) error {
+	const q = `
+		INSERT INTO notifications (user_id, kind, title, body, event_id)
+		SELECT u, $2, $3, $4, $5 FROM unnest($1::int[]) AS u
+	`
+	_, err := r.pool.Exec(ctx, q, userIDs, kind, title, body, eventID)
+	return err
+}
+
+func (r *notificationRepository) ListForUser(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error) {
+	const q = `
+		SELECT id, user_id, kind, title, body, event_id, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, q, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.EventID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, rows.Err()
+}
+
+// MarkRead marks one of the user's notifications read; pgx.ErrNoRows if it is not theirs.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`,
+		id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// MutedUsers returns which of the given users muted the event.
+func (r *notificationRepository) MutedUsers(ctx context.Context, eventID int, userIDs []int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id FROM event_participants WHERE event_id = $1 AND user_id = ANY($2) AND muted`, eventID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
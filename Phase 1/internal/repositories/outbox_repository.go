@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// OutboxRepository reads the outbox for the relay. Messages are written by the
+// repositories making the change they describe, through addToOutbox.
+type OutboxRepository interface {
+	ListPending(ctx context.Context, limit int) ([]models.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, deliveredTo []string, lastError string, retryAt time.Time) error
+}
+
+type outboxRepository struct {
+	pool *database.DB
+}
+
+func NewOutboxRepository(pool *database.DB) OutboxRepository {
+	return &outboxRepository{pool: pool}
+}
+
+// addToOutbox records a domain event in tx, so it is stored if and only if the
+// change it describes is committed.
+func addToOutbox(ctx context.Context, tx pgx.Tx, topic string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("outbox: encode %s: %w", topic, err)
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO outbox_events (topic, payload) VALUES ($1, $2)`, topic, raw)
+	return err
+}
+
+// ListPending returns unpublished messages that are due, oldest first.
+func (r *outboxRepository) ListPending(ctx context.Context, limit int) ([]models.OutboxMessage, error) {
+	const q = `
+		SELECT id, topic, payload, created_at, attempts, delivered_to
+		FROM outbox_events
+		WHERE published_at IS NULL AND next_attempt_at <= now()
+		ORDER BY id
+		LIMIT $1
+	`
+	rows, err := r.pool.Query(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.OutboxMessage
+	for rows.Next() {
+		var m models.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Payload, &m.CreatedAt, &m.Attempts, &m.DeliveredTo); err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE outbox_events SET published_at = now(), attempts = attempts + 1, last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed records a failed attempt and the publishers that did receive the
+// message, and schedules the next attempt.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, deliveredTo []string, lastError string, retryAt time.Time) error {
+	const q = `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, delivered_to = $2, last_error = $3, next_attempt_at = $4
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, q, id, deliveredTo, lastError, retryAt)
+	return err
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ProposalRepository interface {
+	Create(ctx context.Context, p models.ChangeProposal) (*models.ChangeProposal, error)
+	Get(ctx context.Context, eventID, proposalID int) (*models.ChangeProposal, error)
+	List(ctx context.Context, eventID int, status string) ([]models.ChangeProposal, error)
+	Decide(ctx context.Context, eventID, proposalID int, status string, decidedBy int, comment string) (*models.ChangeProposal, error)
+}
+
+type proposalRepository struct {
+	pool *database.DB
+}
+
+func NewProposalRepository(pool *database.DB) ProposalRepository {
+	return &proposalRepository{pool: pool}
+}
+
+const proposalColumns = `cp.id, cp.event_id, cp.proposed_by, u.name, cp.changes, cp.diff, cp.note, cp.status,
+	cp.decided_by, cp.decision_comment, cp.decided_at, cp.created_at`
+
+const proposalFrom = ` FROM change_proposals cp JOIN users u ON u.id = cp.proposed_by`
+
+func scanProposal(row pgx.Row) (*models.ChangeProposal, error) {
+	var p models.ChangeProposal
+	if err := row.Scan(&p.ID, &p.EventID, &p.ProposedBy, &p.ProposerName, &p.Changes, &p.Diff, &p.Note, &p.Status,
+		&p.DecidedBy, &p.DecisionComment, &p.DecidedAt, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *proposalRepository) Create(ctx context.Context, p models.ChangeProposal) (*models.ChangeProposal, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO change_proposals (event_id, proposed_by, changes, diff, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, p.EventID, p.ProposedBy, p.Changes, p.Diff, p.Note).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, p.EventID, id)
+}
+
+func (r *proposalRepository) Get(ctx context.Context, eventID, proposalID int) (*models.ChangeProposal, error) {
+	q := `SELECT ` + proposalColumns + proposalFrom + ` WHERE cp.id = $1 AND cp.event_id = $2`
+	return scanProposal(r.pool.QueryRow(ctx, q, proposalID, eventID))
+}
+
+// List returns the event's proposals, newest first, optionally only those
+// with the given status.
+func (r *proposalRepository) List(ctx context.Context, eventID int, status string) ([]models.ChangeProposal, error) {
+	q := `SELECT ` + proposalColumns + proposalFrom + `
+		WHERE cp.event_id = $1 AND ($2 = '' OR cp.status = $2)
+		ORDER BY cp.created_at DESC, cp.id DESC`
+	rows, err := r.pool.Query(ctx, q, eventID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.ChangeProposal{}
+	for rows.Next() {
+		p, err := scanProposal(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *p)
+	}
+	return res, rows.Err()
+}
+
+// Decide moves a pending proposal to status; pgx.ErrNoRows if it is no
+// longer pending.
+func (r *proposalRepository) Decide(ctx context.Context, eventID, proposalID int, status string, decidedBy int, comment string) (*models.ChangeProposal, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE change_proposals
+		SET status = $3, decided_by = $4, decision_comment = $5, decided_at = now()
+		WHERE id = $1 AND event_id = $2 AND status = 'pending'
+	`, proposalID, eventID, status, decidedBy, comment)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.Get(ctx, eventID, proposalID)
+}
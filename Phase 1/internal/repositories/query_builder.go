@@ -0,0 +1,57 @@
+package repositories
+
+import "strings"
+
+// selectQuery builds a SELECT from optional joins and conditions. Values
+// only ever travel as arguments and placeholders are numbered in the order
+// they are added, so a given combination of filters always produces the same
+// SQL text. pgx prepares each text once per connection and reuses it from its
+// statement cache, which skips parsing and planning on hot paths like search.
+type selectQuery struct {
+	columns string
+	from    string
+	joins   []string
+	conds   []string
+	order   string
+	args    []any
+}
+
+func newSelect(columns, from string) *selectQuery {
+	return &selectQuery{columns: columns, from: from}
+}
+
+// arg adds an argument and returns its placeholder.
+func (q *selectQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return "$" + itoa(len(q.args))
+}
+
+// join adds a JOIN clause, e.g. "JOIN venues v ON v.id = e.venue_id".
+func (q *selectQuery) join(clause string) {
+	q.joins = append(q.joins, clause)
+}
+
+// where adds a condition; all conditions must hold.
+func (q *selectQuery) where(cond string) {
+	q.conds = append(q.conds, cond)
+}
+
+func (q *selectQuery) orderBy(order string) {
+	q.order = order
+}
+
+// sql returns the statement, to run with q.args.
+func (q *selectQuery) sql() string {
+	var b strings.Builder
+	b.WriteString("SELECT " + q.columns + " FROM " + q.from)
+	for _, j := range q.joins {
+		b.WriteString(" " + j)
+	}
+	if len(q.conds) > 0 {
+		b.WriteString(" WHERE " + strings.Join(q.conds, " AND "))
+	}
+	if q.order != "" {
+		b.WriteString(" ORDER BY " + q.order)
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type QuotaRepository interface {
+	Overrides(ctx context.Context, userID int) (*models.QuotaOverrides, error)
+	CountActiveEvents(ctx context.Context, userID int) (int, error)
+	InvitesSince(ctx context.Context, userID int, since time.Time) (int, *time.Time, error)
+}
+
+type quotaRepository struct {
+	pool *database.DB
+}
+
+func NewQuotaRepository(pool *database.DB) QuotaRepository {
+	return &quotaRepository{pool: pool}
+}
+
+// Overrides returns the user's quota overrides; both are nil without a row.
+func (r *quotaRepository) Overrides(ctx context.Context, userID int) (*models.QuotaOverrides, error) {
+	var l models.QuotaOverrides
+	err := r.pool.QueryRow(ctx, `
+		SELECT active_events, invites_per_day FROM user_quotas WHERE user_id = $1
+	`, userID).Scan(&l.ActiveEvents, &l.InvitesPerDay)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// CountActiveEvents counts the events the user organizes that are neither
+// archived, cancelled nor deleted.
+func (r *quotaRepository) CountActiveEvents(ctx context.Context, userID int) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `
+		SELECT count(*) FROM events WHERE organizer_id = $1 AND archived_at IS NULL AND cancelled_at IS NULL AND deleted_at IS NULL
+	`, userID).Scan(&n)
+	return n, err
+}
+
+// InvitesSince counts the invitations the user sent since the given time that
+// are still on record, and returns when the oldest of them was sent.
+func (r *quotaRepository) InvitesSince(ctx context.Context, userID int, since time.Time) (int, *time.Time, error) {
+	var n int
+	var oldest *time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT count(*), min(invited_at) FROM event_participants
+		WHERE invited_by = $1 AND invited_at > $2 AND user_id <> $1
+	`, userID, since).Scan(&n, &oldest)
+	return n, oldest, err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// Reaction targets: what a reaction is to, named by the column of reactions
+// that references it.
+const (
+	ReactionComment      = "comment_id"
+	ReactionAnnouncement = "announcement_id"
+)
+
+// ReactionRepository stores emoji reactions to comments and announcements.
+type ReactionRepository interface {
+	Add(ctx context.Context, target string, id, userID int, emoji string, limit int) (bool, error)
+	Remove(ctx context.Context, target string, id, userID int, emoji string) error
+	Counts(ctx context.Context, target string, ids []int, userID int) (map[int][]models.Reaction, error)
+}
+
+type reactionRepository struct {
+	pool *database.DB
+}
+
+func NewReactionRepository(pool *database.DB) ReactionRepository {
+	return &reactionRepository{pool: pool}
+}
+
+// Add records the user's reaction with emoji to the target with id, unless
+// they already reacted with it. It reports false, adding nothing, when the
+// user already reacted to it with limit other emoji.
+func (r *reactionRepository) Add(ctx context.Context, target string, id, userID int, emoji string, limit int) (bool, error) {
+	q := `
+		INSERT INTO reactions (` + target + `, user_id, emoji)
+		SELECT $1, $2, $3
+		WHERE (SELECT count(*) FROM reactions WHERE ` + target + ` = $1 AND user_id = $2) < $4
+		ON CONFLICT DO NOTHING
+	`
+	tag, err := r.pool.Exec(ctx, q, id, userID, emoji, limit)
+	if err != nil || tag.RowsAffected() > 0 {
+		return err == nil, err
+	}
+	var exists bool
+	err = r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM reactions WHERE `+target+` = $1 AND user_id = $2 AND emoji = $3)`, id, userID, emoji).Scan(&exists)
+	return exists, err
+}
+
+// Remove takes back the user's reaction with emoji, if any.
+func (r *reactionRepository) Remove(ctx context.Context, target string, id, userID int, emoji string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM reactions WHERE `+target+` = $1 AND user_id = $2 AND emoji = $3`, id, userID, emoji)
+	return err
+}
+
+// Counts returns the reactions to each of the targets with ids, by emoji in
+// the order they were first used; ids without any are left out. Reacted
+// tells whether userID is among those who reacted.
+func (r *reactionRepository) Counts(ctx context.Context, target string, ids []int, userID int) (map[int][]models.Reaction, error) {
+	q := `
+		SELECT ` + target + `, emoji, count(*), bool_or(user_id = $2)
+		FROM reactions
+		WHERE ` + target + ` = ANY($1)
+		GROUP BY ` + target + `, emoji
+		ORDER BY ` + target + `, min(created_at), emoji
+	`
+	rows, err := r.pool.Query(ctx, q, ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[int][]models.Reaction{}
+	for rows.Next() {
+		var id int
+		var reaction models.Reaction
+		if err := rows.Scan(&id, &reaction.Emoji, &reaction.Count, &reaction.Reacted); err != nil {
+			return nil, err
+		}
+		res[id] = append(res[id], reaction)
+	}
+	return res, rows.Err()
+}
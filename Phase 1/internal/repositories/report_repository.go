@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportRepository interface {
+	CanSeeEvent(ctx context.Context, eventID, userID int) (bool, error)
+	ReportEvent(ctx context.Context, r models.Report, hideAt int) (*models.Report, error)
+	ReportUser(ctx context.Context, r models.Report) (*models.Report, error)
+	List(ctx context.Context, status string, limit int) ([]models.Report, error)
+	Review(ctx context.Context, reportID, adminID int, status string, hideAt int) (*models.Report, error)
+}
+
+type reportRepository struct {
+	pool *database.DB
+}
+
+func NewReportRepository(pool *database.DB) ReportRepository {
+	return &reportRepository{pool: pool}
+}
+
+const reportColumns = `r.id, r.reporter_id, r.event_id, r.user_id, COALESCE(e.title, u.name, ''), r.reason, r.details, r.status,
+	r.reviewed_by, r.reviewed_at, r.created_at`
+
+const reportFrom = ` FROM reports r
+	LEFT JOIN events e ON e.id = r.event_id
+	LEFT JOIN users u ON u.id = r.user_id`
+
+func scanReport(row pgx.Row) (*models.Report, error) {
+	var r models.Report
+	if err := row.Scan(&r.ID, &r.ReporterID, &r.EventID, &r.UserID, &r.TargetName, &r.Reason, &r.Details, &r.Status,
+		&r.ReviewedBy, &r.ReviewedAt, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CanSeeEvent reports whether the event is published or the user takes part
+// in it.
+func (r *reportRepository) CanSeeEvent(ctx context.Context, eventID, userID int) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM events e
+			WHERE e.id = $1 AND e.deleted_at IS NULL AND (e.published_at IS NOT NULL
+				OR EXISTS (SELECT 1 FROM event_participants p WHERE p.event_id = e.id AND p.user_id = $2))
+		)
+	`, eventID, userID).Scan(&ok)
+	return ok, err
+}
+
+// ReportEvent records the report and, in the same transaction, hides the
+// event if it is published and now has hideAt or more pending reports. A
+// second pending report by the same reporter fails with a duplicate key error.
+func (r *reportRepository) ReportEvent(ctx context.Context, rep models.Report, hideAt int) (*models.Report, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO reports (reporter_id, event_id, reason, details) VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, rep.ReporterID, rep.EventID, rep.Reason, rep.Details).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if hideAt > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE events SET hidden_at = now()
+			WHERE id = $1 AND published_at IS NOT NULL AND hidden_at IS NULL
+				AND (SELECT count(*) FROM reports WHERE event_id = $1 AND status = 'pending') >= $2
+		`, rep.EventID, hideAt); err != nil {
+			return nil, err
+		}
+	}
+	saved, err := scanReport(tx.QueryRow(ctx, `SELECT `+reportColumns+reportFrom+` WHERE r.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit(ctx)
+}
+
+// ReportUser records the report. An unknown user fails with a foreign key
+// violation, a second pending report by the same reporter with a duplicate
+// key error.
+func (r *reportRepository) ReportUser(ctx context.Context, rep models.Report) (*models.Report, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO reports (reporter_id, user_id, reason, details) VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, rep.ReporterID, rep.UserID, rep.Reason, rep.Details).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return scanReport(r.pool.QueryRow(ctx, `SELECT `+reportColumns+reportFrom+` WHERE r.id = $1`, id))
+}
+
+// List returns reports with the given status, oldest first.
+func (r *reportRepository) List(ctx context.Context, status string, limit int) ([]models.Report, error) {
+	q := `SELECT ` + reportColumns + reportFrom + ` WHERE r.status = $1 ORDER BY r.created_at, r.id LIMIT $2`
+	rows, err := r.pool.Query(ctx, q, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Report{}
+	for rows.Next() {
+		rep, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *rep)
+	}
+	return res, rows.Err()
+}
+
+// Review records the admin's decision. For an event report, actioning hides
+// the event; dismissing shows it again unless one of its reports was actioned
+// or it still has hideAt or more pending reports.
+func (r *reportRepository) Review(ctx context.Context, reportID, adminID int, status string, hideAt int) (*models.Report, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var eventID *int
+	err = tx.QueryRow(ctx, `
+		UPDATE reports SET status = $3, reviewed_by = $2, reviewed_at = now()
+		WHERE id = $1
+		RETURNING event_id
+	`, reportID, adminID, status).Scan(&eventID)
+	if err != nil {
+		return nil, err
+	}
+	if eventID != nil {
+		q := `UPDATE events SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1`
+		args := []any{*eventID}
+		if status == models.ReportDismissed {
+			q = `
+				UPDATE events SET hidden_at = NULL
+				WHERE id = $1 AND hidden_at IS NOT NULL
+					AND NOT EXISTS (SELECT 1 FROM reports WHERE event_id = $1 AND status = 'actioned')
+					AND ($2 = 0 OR (SELECT count(*) FROM reports WHERE event_id = $1 AND status = 'pending') < $2)`
+			args = append(args, hideAt)
+		}
+		if _, err := tx.Exec(ctx, q, args...); err != nil {
+			return nil, err
+		}
+	}
+	saved, err := scanReport(tx.QueryRow(ctx, `SELECT `+reportColumns+reportFrom+` WHERE r.id = $1`, reportID))
+	if err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit(ctx)
+}
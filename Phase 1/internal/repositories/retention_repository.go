@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/database"
+)
+
+// Retention targets: the kinds of rows the retention job deletes once they
+// are older than the target's window.
+const (
+	RetentionNotifications  = "notifications"
+	RetentionExpiredInvites = "expired_invites"
+	RetentionArchivedEvents = "archived_events"
+	RetentionOutbox         = "outbox_events"
+	RetentionDeadJobs       = "dead_jobs"
+	RetentionDeliveries     = "email_deliveries"
+	RetentionLinkPreviews   = "link_previews"
+)
+
+// purgeConditions selects the rows of each target that are due for deletion,
+// given the cutoff time as $1.
+var purgeConditions = map[string]struct{ table, where string }{
+	RetentionNotifications: {"notifications", "created_at < $1"},
+	// Unanswered invitations whose expiry passed before the cutoff
+	RetentionExpiredInvites: {"event_participants", "attendance IS NULL AND role <> 'organizer' AND invite_expires_at < $1"},
+	// Archived events, except those that issued receipts
+	RetentionArchivedEvents: {"events", "archived_at < $1 AND NOT EXISTS (SELECT 1 FROM receipts rc WHERE rc.event_id = events.id)"},
+	RetentionOutbox:         {"outbox_events", "published_at < $1"},
+	RetentionDeadJobs:       {"dead_jobs", "failed_at < $1"},
+	RetentionDeliveries:     {"email_deliveries", "created_at < $1"},
+	// Previews not fetched again since the cutoff, or claims never fetched
+	RetentionLinkPreviews: {"link_previews", "COALESCE(fetched_at, claimed_at) < $1"},
+}
+
+// purgeBatchSize bounds the rows one DELETE removes, so a large backlog is
+// deleted in short transactions instead of one long lock.
+const purgeBatchSize = 5000
+
+// RetentionRepository deletes old rows and records how many were deleted.
+type RetentionRepository interface {
+	Purge(ctx context.Context, target string, before time.Time) (int64, error)
+	RecordPurge(ctx context.Context, target string, before time.Time, rows int64) error
+}
+
+type retentionRepository struct {
+	pool *database.DB
+}
+
+func NewRetentionRepository(pool *database.DB) RetentionRepository {
+	return &retentionRepository{pool: pool}
+}
+
+// Purge deletes the target's rows older than before in batches and returns
+// how many it deleted. Rows deleted by earlier batches stay deleted when a
+// later batch fails.
+func (r *retentionRepository) Purge(ctx context.Context, target string, before time.Time) (int64, error) {
+	c, ok := purgeConditions[target]
+	if !ok {
+		return 0, fmt.Errorf("unknown retention target %q", target)
+	}
+	q := `DELETE FROM ` + c.table + ` WHERE ctid = ANY(ARRAY(
+		SELECT ctid FROM ` + c.table + ` WHERE ` + c.where + ` LIMIT ` + itoa(purgeBatchSize) + `
+	))`
+	var total int64
+	for {
+		tag, err := r.pool.Exec(ctx, q, before)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *retentionRepository) RecordPurge(ctx context.Context, target string, before time.Time, rows int64) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO retention_purges (target, cutoff, rows_purged) VALUES ($1, $2, $3)`, target, before, rows)
+	return err
+}
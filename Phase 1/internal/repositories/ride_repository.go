@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RideRepository interface {
+	Create(ctx context.Context, ride models.Ride) (*models.Ride, error)
+	Update(ctx context.Context, ride models.Ride) (*models.Ride, error)
+	Get(ctx context.Context, eventID, rideID int) (*models.Ride, error)
+	List(ctx context.Context, eventID int) ([]models.Ride, error)
+	Delete(ctx context.Context, eventID, rideID int) error
+	Join(ctx context.Context, eventID, rideID, userID int) error
+	Leave(ctx context.Context, eventID, rideID, userID int) error
+}
+
+type rideRepository struct {
+	pool *database.DB
+}
+
+func NewRideRepository(pool *database.DB) RideRepository {
+	return &rideRepository{pool: pool}
+}
+
+// seatsTaken counts a ride's passengers.
+const seatsTaken = `(SELECT count(*) FROM ride_passengers rp WHERE rp.ride_id = r.id)`
+
+const rideColumns = `r.id, r.event_id, r.driver_id, u.name, u.email, r.origin, r.departure_at, r.seats, r.seats - ` + seatsTaken + `, r.notes, r.created_at, r.updated_at`
+
+const rideFrom = ` FROM rides r JOIN users u ON u.id = r.driver_id`
+
+func scanRide(row pgx.Row) (*models.Ride, error) {
+	var r models.Ride
+	if err := row.Scan(&r.ID, &r.EventID, &r.DriverID, &r.DriverName, &r.DriverEmail, &r.Origin, &r.DepartureAt, &r.Seats, &r.SeatsLeft, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	r.Passengers = []models.RidePassenger{}
+	return &r, nil
+}
+
+func (r *rideRepository) Create(ctx context.Context, ride models.Ride) (*models.Ride, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO rides (event_id, driver_id, origin, departure_at, seats, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, ride.EventID, ride.DriverID, ride.Origin, ride.DepartureAt, ride.Seats, ride.Notes).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, ride.EventID, id)
+}
+
+// Update changes the driver's ride; pgx.ErrNoRows if it does not exist,
+// belongs to someone else, or the new seat count is below the passengers
+// already riding.
+func (r *rideRepository) Update(ctx context.Context, ride models.Ride) (*models.Ride, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE rides AS r
+		SET origin = $4, departure_at = $5, seats = $6, notes = $7, updated_at = now()
+		WHERE r.id = $1 AND r.event_id = $2 AND r.driver_id = $3 AND $6 >= `+seatsTaken+`
+	`, ride.ID, ride.EventID, ride.DriverID, ride.Origin, ride.DepartureAt, ride.Seats, ride.Notes)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.Get(ctx, ride.EventID, ride.ID)
+}
+
+// Get returns a ride with its passengers.
+func (r *rideRepository) Get(ctx context.Context, eventID, rideID int) (*models.Ride, error) {
+	ride, err := scanRide(r.pool.QueryRow(ctx, `SELECT `+rideColumns+rideFrom+` WHERE r.id = $1 AND r.event_id = $2`, rideID, eventID))
+	if err != nil {
+		return nil, err
+	}
+	rides := []models.Ride{*ride}
+	if err := r.withPassengers(ctx, rides); err != nil {
+		return nil, err
+	}
+	return &rides[0], nil
+}
+
+// List returns the event's rides by departure time, with their passengers.
+func (r *rideRepository) List(ctx context.Context, eventID int) ([]models.Ride, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+rideColumns+rideFrom+` WHERE r.event_id = $1 ORDER BY r.departure_at, r.id`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rides := []models.Ride{}
+	for rows.Next() {
+		ride, err := scanRide(rows)
+		if err != nil {
+			return nil, err
+		}
+		rides = append(rides, *ride)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rides, r.withPassengers(ctx, rides)
+}
+
+// withPassengers fills in the passengers of the given rides, in the order
+// they joined.
+func (r *rideRepository) withPassengers(ctx context.Context, rides []models.Ride) error {
+	if len(rides) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(rides))
+	ids := make([]int, len(rides))
+	for i, ride := range rides {
+		index[ride.ID] = i
+		ids[i] = ride.ID
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT rp.ride_id, rp.user_id, u.name, u.email, rp.created_at
+		FROM ride_passengers rp
+		JOIN users u ON u.id = rp.user_id
+		WHERE rp.ride_id = ANY($1)
+		ORDER BY rp.created_at, rp.user_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rideID int
+		var p models.RidePassenger
+		if err := rows.Scan(&rideID, &p.UserID, &p.Name, &p.Email, &p.JoinedAt); err != nil {
+			return err
+		}
+		i := index[rideID]
+		rides[i].Passengers = append(rides[i].Passengers, p)
+	}
+	return rows.Err()
+}
+
+func (r *rideRepository) Delete(ctx context.Context, eventID, rideID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM rides WHERE id = $1 AND event_id = $2`, rideID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Join takes a seat in the ride for the user. The ride row is locked while
+// its seats are counted, so concurrent joins cannot overfill it; pgx.ErrNoRows
+// means the ride is full (or missing). Taking a second seat for the same
+// event fails with a duplicate key error.
+func (r *rideRepository) Join(ctx context.Context, eventID, rideID, userID int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var seats int
+	err = tx.QueryRow(ctx, `
+		SELECT seats FROM rides WHERE id = $1 AND event_id = $2 FOR UPDATE
+	`, rideID, eventID).Scan(&seats)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO ride_passengers (ride_id, event_id, user_id)
+		SELECT $1, $2, $3
+		WHERE (SELECT count(*) FROM ride_passengers WHERE ride_id = $1) < $4
+	`, rideID, eventID, userID, seats)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+// Leave gives up the user's seat; pgx.ErrNoRows if they had none in the ride.
+func (r *rideRepository) Leave(ctx context.Context, eventID, rideID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM ride_passengers WHERE ride_id = $1 AND event_id = $2 AND user_id = $3
+	`, rideID, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
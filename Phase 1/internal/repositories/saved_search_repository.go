@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, s models.SavedSearch) (*models.SavedSearch, error)
+	Update(ctx context.Context, s models.SavedSearch) (*models.SavedSearch, error)
+	Get(ctx context.Context, id, userID int) (*models.SavedSearch, error)
+	List(ctx context.Context, userID int) ([]models.SavedSearch, error)
+	Delete(ctx context.Context, id, userID int) error
+	ListAlerting(ctx context.Context) ([]models.SavedSearchAlert, error)
+	MarkChecked(ctx context.Context, id int, at time.Time) error
+}
+
+type savedSearchRepository struct {
+	pool *database.DB
+}
+
+func NewSavedSearchRepository(pool *database.DB) SavedSearchRepository {
+	return &savedSearchRepository{pool: pool}
+}
+
+const savedSearchColumns = `s.id, s.user_id, s.name, s.query, s.from_date, s.to_date, s.role, s.alerts, s.created_at, s.updated_at`
+
+func scanSavedSearch(row pgx.Row, s *models.SavedSearch, extra ...any) error {
+	dest := []any{&s.ID, &s.UserID, &s.Name, &s.Query, &s.From, &s.To, &s.Role, &s.Alerts, &s.CreatedAt, &s.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, s models.SavedSearch) (*models.SavedSearch, error) {
+	q := `
+		INSERT INTO saved_searches AS s (user_id, name, query, from_date, to_date, role, alerts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + savedSearchColumns
+	var out models.SavedSearch
+	if err := scanSavedSearch(r.pool.QueryRow(ctx, q, s.UserID, s.Name, s.Query, s.From, s.To, s.Role, s.Alerts), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Update replaces the filter of a search owned by s.UserID. Turning alerts on
+// starts matching from now, so events published before are not reported.
+func (r *savedSearchRepository) Update(ctx context.Context, s models.SavedSearch) (*models.SavedSearch, error) {
+	q := `
+		UPDATE saved_searches AS s
+		SET name = $3, query = $4, from_date = $5, to_date = $6, role = $7, alerts = $8,
+			last_checked_at = CASE WHEN $8 AND NOT s.alerts THEN now() ELSE s.last_checked_at END,
+			updated_at = now()
+		WHERE s.id = $1 AND s.user_id = $2
+		RETURNING ` + savedSearchColumns
+	var out models.SavedSearch
+	if err := scanSavedSearch(r.pool.QueryRow(ctx, q, s.ID, s.UserID, s.Name, s.Query, s.From, s.To, s.Role, s.Alerts), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *savedSearchRepository) Get(ctx context.Context, id, userID int) (*models.SavedSearch, error) {
+	q := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.id = $1 AND s.user_id = $2`
+	var s models.SavedSearch
+	if err := scanSavedSearch(r.pool.QueryRow(ctx, q, id, userID), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *savedSearchRepository) List(ctx context.Context, userID int) ([]models.SavedSearch, error) {
+	q := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.user_id = $1 ORDER BY s.name`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.SavedSearch{}
+	for rows.Next() {
+		var s models.SavedSearch
+		if err := scanSavedSearch(rows, &s); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+func (r *savedSearchRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListAlerting returns every saved search with alerts on.
+func (r *savedSearchRepository) ListAlerting(ctx context.Context) ([]models.SavedSearchAlert, error) {
+	q := `
+		SELECT ` + savedSearchColumns + `, u.name, u.email, s.last_checked_at
+		FROM saved_searches s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.alerts
+		ORDER BY s.id
+	`
+	rows, err := r.pool.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.SavedSearchAlert
+	for rows.Next() {
+		var a models.SavedSearchAlert
+		if err := scanSavedSearch(rows, &a.SavedSearch, &a.UserName, &a.UserEmail, &a.LastCheckedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// MarkChecked records that events published up to at were matched.
+func (r *savedSearchRepository) MarkChecked(ctx context.Context, id int, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `UPDATE saved_searches SET last_checked_at = $2 WHERE id = $1`, id, at)
+	return err
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+)
+
+// ScheduleRepository records runs of scheduled tasks. The primary key on
+// (name, scheduled_for) is what lets only one instance claim an occurrence.
+type ScheduleRepository interface {
+	ClaimRun(ctx context.Context, name string, scheduledFor time.Time) (bool, error)
+	FinishRun(ctx context.Context, name string, scheduledFor time.Time, lastError string) error
+	PruneRuns(ctx context.Context, before time.Time) (int64, error)
+}
+
+type scheduleRepository struct {
+	pool *database.DB
+}
+
+func NewScheduleRepository(pool *database.DB) ScheduleRepository {
+	return &scheduleRepository{pool: pool}
+}
+
+func (r *scheduleRepository) ClaimRun(ctx context.Context, name string, scheduledFor time.Time) (bool, error) {
+	const q = `
+		INSERT INTO scheduled_runs (name, scheduled_for)
+		VALUES ($1, $2)
+		ON CONFLICT (name, scheduled_for) DO NOTHING
+	`
+	tag, err := r.pool.Exec(ctx, q, name, scheduledFor)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *scheduleRepository) FinishRun(ctx context.Context, name string, scheduledFor time.Time, lastError string) error {
+	const q = `
+		UPDATE scheduled_runs
+		SET finished_at = now(), error = NULLIF($3, '')
+		WHERE name = $1 AND scheduled_for = $2
+	`
+	_, err := r.pool.Exec(ctx, q, name, scheduledFor, lastError)
+	return err
+}
+
+// PruneRuns deletes run records scheduled before the given time.
+func (r *scheduleRepository) PruneRuns(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM scheduled_runs WHERE scheduled_for < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SeriesRepository interface {
+	Create(ctx context.Context, s models.Series) (*models.Series, error)
+	Update(ctx context.Context, s models.Series) (*models.Series, error)
+	Get(ctx context.Context, seriesID, userID int) (*models.Series, error)
+	ListForUser(ctx context.Context, userID int) ([]models.Series, error)
+	Delete(ctx context.Context, seriesID, ownerID int) error
+	Occurrences(ctx context.Context, seriesID int) ([]models.SeriesOccurrence, error)
+	IsMember(ctx context.Context, seriesID, userID int) (bool, error)
+	Link(ctx context.Context, eventID, seriesID int) error
+	Unlink(ctx context.Context, eventID int) error
+	Subscribe(ctx context.Context, seriesID, userID int) error
+	Unsubscribe(ctx context.Context, seriesID, userID int) error
+	Subscribers(ctx context.Context, seriesID int) ([]models.SeriesSubscriber, error)
+	Uninvited(ctx context.Context, seriesID, eventID int) ([]int, error)
+}
+
+type seriesRepository struct {
+	pool *database.DB
+}
+
+func NewSeriesRepository(pool *database.DB) SeriesRepository {
+	return &seriesRepository{pool: pool}
+}
+
+// seriesColumns reads a series as seen by the user in parameter $2.
+const seriesColumns = `s.id, s.owner_id, s.name, s.description, s.default_role, s.invite_expiry_days,
+	(SELECT count(*) FROM series_subscriptions ss WHERE ss.series_id = s.id),
+	EXISTS (SELECT 1 FROM series_subscriptions ss WHERE ss.series_id = s.id AND ss.user_id = $2),
+	s.created_at, s.updated_at`
+
+func scanSeries(row pgx.Row) (*models.Series, error) {
+	var s models.Series
+	if err := row.Scan(&s.ID, &s.OwnerID, &s.Name, &s.Description, &s.DefaultRole, &s.InviteExpiryDays,
+		&s.Subscribers, &s.Subscribed, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *seriesRepository) Create(ctx context.Context, s models.Series) (*models.Series, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO event_series (owner_id, name, description, default_role, invite_expiry_days)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, s.OwnerID, s.Name, s.Description, s.DefaultRole, s.InviteExpiryDays).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id, s.OwnerID)
+}
+
+// Update replaces the series' details; pgx.ErrNoRows unless s.OwnerID owns it.
+func (r *seriesRepository) Update(ctx context.Context, s models.Series) (*models.Series, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE event_series
+		SET name = $3, description = $4, default_role = $5, invite_expiry_days = $6, updated_at = now()
+		WHERE id = $1 AND owner_id = $2
+	`, s.ID, s.OwnerID, s.Name, s.Description, s.DefaultRole, s.InviteExpiryDays)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.Get(ctx, s.ID, s.OwnerID)
+}
+
+// Get returns the series with userID's subscription state.
+func (r *seriesRepository) Get(ctx context.Context, seriesID, userID int) (*models.Series, error) {
+	return scanSeries(r.pool.QueryRow(ctx, `SELECT `+seriesColumns+` FROM event_series s WHERE s.id = $1`, seriesID, userID))
+}
+
+// ListForUser returns the series the user owns or subscribes to, by name.
+func (r *seriesRepository) ListForUser(ctx context.Context, userID int) ([]models.Series, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+seriesColumns+` FROM event_series s
+		WHERE s.owner_id = $1
+			OR EXISTS (SELECT 1 FROM series_subscriptions ss WHERE ss.series_id = s.id AND ss.user_id = $2)
+		ORDER BY lower(s.name), s.id
+	`, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Series{}
+	for rows.Next() {
+		s, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *s)
+	}
+	return res, rows.Err()
+}
+
+// Delete removes the series; its events are kept. pgx.ErrNoRows unless
+// ownerID owns it.
+func (r *seriesRepository) Delete(ctx context.Context, seriesID, ownerID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM event_series WHERE id = $1 AND owner_id = $2`, seriesID, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Occurrences returns the series' events by start time.
+func (r *seriesRepository) Occurrences(ctx context.Context, seriesID int) ([]models.SeriesOccurrence, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, slug, start_time, end_time FROM events
+		WHERE series_id = $1 AND deleted_at IS NULL
+		ORDER BY start_time, id
+	`, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.SeriesOccurrence{}
+	for rows.Next() {
+		var o models.SeriesOccurrence
+		if err := rows.Scan(&o.EventID, &o.Title, &o.Slug, &o.StartTime, &o.EndTime); err != nil {
+			return nil, err
+		}
+		res = append(res, o)
+	}
+	return res, rows.Err()
+}
+
+// IsMember reports whether the user owns the series, subscribes to it or
+// participates in one of its events.
+func (r *seriesRepository) IsMember(ctx context.Context, seriesID, userID int) (bool, error) {
+	var member bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM event_series WHERE id = $1 AND owner_id = $2)
+			OR EXISTS (SELECT 1 FROM series_subscriptions WHERE series_id = $1 AND user_id = $2)
+			OR EXISTS (
+				SELECT 1 FROM event_participants p JOIN events e ON e.id = p.event_id
+				WHERE e.series_id = $1 AND p.user_id = $2
+			)
+	`, seriesID, userID).Scan(&member)
+	return member, err
+}
+
+// Link puts the event in the series, moving it out of any other.
+func (r *seriesRepository) Link(ctx context.Context, eventID, seriesID int) error {
+	_, err := r.pool.Exec(ctx, `UPDATE events SET series_id = $2, updated_at = now() WHERE id = $1`, eventID, seriesID)
+	return err
+}
+
+// Unlink takes the event out of its series; pgx.ErrNoRows if it had none.
+func (r *seriesRepository) Unlink(ctx context.Context, eventID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE events SET series_id = NULL, updated_at = now() WHERE id = $1 AND series_id IS NOT NULL
+	`, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Subscribe is a no-op if the user already subscribes.
+func (r *seriesRepository) Subscribe(ctx context.Context, seriesID, userID int) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO series_subscriptions (series_id, user_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, seriesID, userID)
+	return err
+}
+
+// Unsubscribe returns pgx.ErrNoRows if the user did not subscribe.
+func (r *seriesRepository) Unsubscribe(ctx context.Context, seriesID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM series_subscriptions WHERE series_id = $1 AND user_id = $2`, seriesID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Subscribers returns the series' subscribers in the order they subscribed.
+func (r *seriesRepository) Subscribers(ctx context.Context, seriesID int) ([]models.SeriesSubscriber, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ss.user_id, u.name, u.email, ss.subscribed_at
+		FROM series_subscriptions ss JOIN users u ON u.id = ss.user_id
+		WHERE ss.series_id = $1
+		ORDER BY ss.subscribed_at, ss.user_id
+	`, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.SeriesSubscriber{}
+	for rows.Next() {
+		var s models.SeriesSubscriber
+		if err := rows.Scan(&s.UserID, &s.Name, &s.Email, &s.SubscribedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// Uninvited returns the subscribers of the series who are not participants
+// of the event and whose invitation to it was not revoked.
+func (r *seriesRepository) Uninvited(ctx context.Context, seriesID, eventID int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT ss.user_id FROM series_subscriptions ss
+		WHERE ss.series_id = $1
+			AND NOT EXISTS (SELECT 1 FROM event_participants p WHERE p.event_id = $2 AND p.user_id = ss.user_id)
+			AND NOT EXISTS (SELECT 1 FROM revoked_invites ri WHERE ri.event_id = $2 AND ri.user_id = ss.user_id)
+		ORDER BY ss.subscribed_at, ss.user_id
+	`, seriesID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
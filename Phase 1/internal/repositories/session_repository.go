@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, s models.EventSession) (*models.EventSession, error)
+	Update(ctx context.Context, s models.EventSession) (*models.EventSession, error)
+	Get(ctx context.Context, eventID, sessionID int) (*models.EventSession, error)
+	Delete(ctx context.Context, eventID, sessionID int) error
+	List(ctx context.Context, eventID, userID int, agendaOnly bool) ([]models.EventSession, error)
+	Attend(ctx context.Context, eventID, sessionID, userID int) error
+	Leave(ctx context.Context, eventID, sessionID, userID int) error
+}
+
+type sessionRepository struct {
+	pool *database.DB
+}
+
+func NewSessionRepository(pool *database.DB) SessionRepository {
+	return &sessionRepository{pool: pool}
+}
+
+// attendeeCount counts a session's attendees; it is computed rather than
+// stored so that participants removed from the event stop counting.
+const attendeeCount = `(SELECT count(*) FROM session_attendees sa WHERE sa.session_id = s.id)`
+
+const sessionColumns = `s.id, s.event_id, s.title, s.description, s.start_time, s.end_time, s.room, s.speakers, s.capacity, ` + attendeeCount + `, s.created_at, s.updated_at`
+
+func scanSession(row pgx.Row, extra ...any) (*models.EventSession, error) {
+	var s models.EventSession
+	dest := []any{&s.ID, &s.EventID, &s.Title, &s.Description, &s.StartTime, &s.EndTime, &s.Room, &s.Speakers, &s.Capacity, &s.AttendeeCount, &s.CreatedAt, &s.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *sessionRepository) Create(ctx context.Context, s models.EventSession) (*models.EventSession, error) {
+	q := `
+		INSERT INTO event_sessions AS s (event_id, title, description, start_time, end_time, room, speakers, capacity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + sessionColumns
+	return scanSession(r.pool.QueryRow(ctx, q, s.EventID, s.Title, s.Description, s.StartTime, s.EndTime, s.Room, s.Speakers, s.Capacity))
+}
+
+// Update changes a session; pgx.ErrNoRows if it does not exist or the new
+// capacity is below the number of attendees.
+func (r *sessionRepository) Update(ctx context.Context, s models.EventSession) (*models.EventSession, error) {
+	q := `
+		UPDATE event_sessions AS s
+		SET title = $3, description = $4, start_time = $5, end_time = $6, room = $7, speakers = $8, capacity = $9, updated_at = now()
+		WHERE s.id = $1 AND s.event_id = $2 AND ($9::int IS NULL OR $9::int >= ` + attendeeCount + `)
+		RETURNING ` + sessionColumns
+	return scanSession(r.pool.QueryRow(ctx, q, s.ID, s.EventID, s.Title, s.Description, s.StartTime, s.EndTime, s.Room, s.Speakers, s.Capacity))
+}
+
+func (r *sessionRepository) Get(ctx context.Context, eventID, sessionID int) (*models.EventSession, error) {
+	q := `SELECT ` + sessionColumns + ` FROM event_sessions s WHERE s.id = $1 AND s.event_id = $2`
+	return scanSession(r.pool.QueryRow(ctx, q, sessionID, eventID))
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, eventID, sessionID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM event_sessions WHERE id = $1 AND event_id = $2`, sessionID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// List returns the event's sessions in agenda order, flagging those userID
+// attends. With agendaOnly, only those sessions are returned.
+func (r *sessionRepository) List(ctx context.Context, eventID, userID int, agendaOnly bool) ([]models.EventSession, error) {
+	q := `
+		SELECT ` + sessionColumns + `, sa.user_id IS NOT NULL
+		FROM event_sessions s
+		LEFT JOIN session_attendees sa ON sa.session_id = s.id AND sa.user_id = $2
+		WHERE s.event_id = $1 AND (NOT $3 OR sa.user_id IS NOT NULL)
+		ORDER BY s.start_time, s.id
+	`
+	rows, err := r.pool.Query(ctx, q, eventID, userID, agendaOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.EventSession{}
+	for rows.Next() {
+		var attending bool
+		s, err := scanSession(rows, &attending)
+		if err != nil {
+			return nil, err
+		}
+		s.Attending = attending
+		res = append(res, *s)
+	}
+	return res, rows.Err()
+}
+
+// Attend adds the session to the user's agenda. The session row is locked
+// while its seats are counted, so concurrent joins cannot exceed capacity;
+// pgx.ErrNoRows means the session is full (or missing). Adding a session
+// twice fails with a duplicate key error.
+func (r *sessionRepository) Attend(ctx context.Context, eventID, sessionID, userID int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var capacity *int
+	err = tx.QueryRow(ctx, `
+		SELECT capacity FROM event_sessions WHERE id = $1 AND event_id = $2 FOR UPDATE
+	`, sessionID, eventID).Scan(&capacity)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO session_attendees (session_id, event_id, user_id)
+		SELECT $1, $2, $3
+		WHERE $4::int IS NULL OR (SELECT count(*) FROM session_attendees WHERE session_id = $1) < $4::int
+	`, sessionID, eventID, userID, capacity)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+// Leave removes the session from the user's agenda; pgx.ErrNoRows if it was
+// not on the agenda.
+func (r *sessionRepository) Leave(ctx context.Context, eventID, sessionID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM session_attendees WHERE session_id = $1 AND event_id = $2 AND user_id = $3
+	`, sessionID, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
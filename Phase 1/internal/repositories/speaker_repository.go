@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SpeakerRepository interface {
+	Create(ctx context.Context, s models.Speaker) (*models.Speaker, error)
+	Update(ctx context.Context, s models.Speaker) (*models.Speaker, error)
+	GetByID(ctx context.Context, id int) (*models.Speaker, error)
+	ListByCreator(ctx context.Context, userID int) ([]models.Speaker, error)
+	Delete(ctx context.Context, id, userID int) error
+	SetEventSpeakers(ctx context.Context, eventID int, speakerIDs []int) error
+	ListEventSpeakers(ctx context.Context, eventID int) ([]models.Speaker, error)
+	SetSessionSpeakers(ctx context.Context, sessionID int, speakerIDs []int) error
+	ListSessionSpeakers(ctx context.Context, sessionIDs []int) (map[int][]models.Speaker, error)
+}
+
+type speakerRepository struct {
+	pool *database.DB
+}
+
+func NewSpeakerRepository(pool *database.DB) SpeakerRepository {
+	return &speakerRepository{pool: pool}
+}
+
+const speakerColumns = `sp.id, sp.name, sp.bio, sp.photo_url, sp.links, sp.created_by, sp.created_at, sp.updated_at`
+
+func scanSpeaker(row pgx.Row, extra ...any) (*models.Speaker, error) {
+	var s models.Speaker
+	dest := []any{&s.ID, &s.Name, &s.Bio, &s.PhotoURL, &s.Links, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if s.Links == nil {
+		s.Links = []models.SpeakerLink{}
+	}
+	return &s, nil
+}
+
+func collectSpeakers(rows pgx.Rows) ([]models.Speaker, error) {
+	defer rows.Close()
+	res := []models.Speaker{}
+	for rows.Next() {
+		s, err := scanSpeaker(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *s)
+	}
+	return res, rows.Err()
+}
+
+func (r *speakerRepository) Create(ctx context.Context, s models.Speaker) (*models.Speaker, error) {
+	q := `
+		INSERT INTO speakers AS sp (name, bio, photo_url, links, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + speakerColumns
+	return scanSpeaker(r.pool.QueryRow(ctx, q, s.Name, s.Bio, s.PhotoURL, s.Links, s.CreatedBy))
+}
+
+// Update changes a speaker profile; only its creator may, pgx.ErrNoRows otherwise.
+func (r *speakerRepository) Update(ctx context.Context, s models.Speaker) (*models.Speaker, error) {
+	q := `
+		UPDATE speakers AS sp SET name = $3, bio = $4, photo_url = $5, links = $6, updated_at = now()
+		WHERE sp.id = $1 AND sp.created_by = $2
+		RETURNING ` + speakerColumns
+	return scanSpeaker(r.pool.QueryRow(ctx, q, s.ID, s.CreatedBy, s.Name, s.Bio, s.PhotoURL, s.Links))
+}
+
+func (r *speakerRepository) GetByID(ctx context.Context, id int) (*models.Speaker, error) {
+	q := `SELECT ` + speakerColumns + ` FROM speakers sp WHERE sp.id = $1`
+	return scanSpeaker(r.pool.QueryRow(ctx, q, id))
+}
+
+func (r *speakerRepository) ListByCreator(ctx context.Context, userID int) ([]models.Speaker, error) {
+	q := `SELECT ` + speakerColumns + ` FROM speakers sp WHERE sp.created_by = $1 ORDER BY sp.name, sp.id`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	return collectSpeakers(rows)
+}
+
+// Delete removes a speaker profile from every event and session; only its
+// creator may, pgx.ErrNoRows otherwise.
+func (r *speakerRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM speakers WHERE id = $1 AND created_by = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetEventSpeakers replaces the event's speakers; the slice order is the display order.
+func (r *speakerRepository) SetEventSpeakers(ctx context.Context, eventID int, speakerIDs []int) error {
+	return r.replaceLinks(ctx, "event_speakers", "event_id", eventID, speakerIDs)
+}
+
+// SetSessionSpeakers replaces the session's speakers; the slice order is the display order.
+func (r *speakerRepository) SetSessionSpeakers(ctx context.Context, sessionID int, speakerIDs []int) error {
+	return r.replaceLinks(ctx, "session_speakers", "session_id", sessionID, speakerIDs)
+}
+
+// replaceLinks rewrites the speaker list of one owner row of table in a
+// transaction. table and column are constants from this file.
+func (r *speakerRepository) replaceLinks(ctx context.Context, table, column string, ownerID int, speakerIDs []int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE `+column+` = $1`, ownerID); err != nil {
+		return err
+	}
+	if len(speakerIDs) > 0 {
+		q := `
+			INSERT INTO ` + table + ` (` + column + `, speaker_id, position)
+			SELECT $1, id, pos FROM unnest($2::int[]) WITH ORDINALITY AS t(id, pos)
+		`
+		if _, err := tx.Exec(ctx, q, ownerID, speakerIDs); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *speakerRepository) ListEventSpeakers(ctx context.Context, eventID int) ([]models.Speaker, error) {
+	q := `
+		SELECT ` + speakerColumns + `
+		FROM event_speakers es
+		JOIN speakers sp ON sp.id = es.speaker_id
+		WHERE es.event_id = $1
+		ORDER BY es.position
+	`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return collectSpeakers(rows)
+}
+
+// ListSessionSpeakers returns the speakers of each session, in order, with a
+// single query.
+func (r *speakerRepository) ListSessionSpeakers(ctx context.Context, sessionIDs []int) (map[int][]models.Speaker, error) {
+	res := make(map[int][]models.Speaker, len(sessionIDs))
+	if len(sessionIDs) == 0 {
+		return res, nil
+	}
+	q := `
+		SELECT ` + speakerColumns + `, ss.session_id
+		FROM session_speakers ss
+		JOIN speakers sp ON sp.id = ss.speaker_id
+		WHERE ss.session_id = ANY($1)
+		ORDER BY ss.session_id, ss.position
+	`
+	rows, err := r.pool.Query(ctx, q, sessionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sessionID int
+		s, err := scanSpeaker(rows, &sessionID)
+		if err != nil {
+			return nil, err
+		}
+		res[sessionID] = append(res[sessionID], *s)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// trackedEvents are the events whose attendance is known: they have ended,
+// were neither cancelled nor deleted, and checked in at least one
+// participant.
+const trackedEvents = `
+	SELECT e.id, e.organizer_id FROM events e
+	WHERE e.deleted_at IS NULL AND e.cancelled_at IS NULL
+		AND COALESCE(e.end_time, e.start_time) < now()
+		AND EXISTS (SELECT 1 FROM event_participants c WHERE c.event_id = e.id AND c.checked_in_at IS NOT NULL)
+`
+
+type StatsRepository interface {
+	UserStats(ctx context.Context, userID int) (*models.UserStats, error)
+	Reliability(ctx context.Context, eventID int) ([]models.Reliability, error)
+	Funnel(ctx context.Context, eventID int, interval string, maxPoints int) ([]models.FunnelPoint, error)
+}
+
+type statsRepository struct {
+	pool *database.DB
+}
+
+func NewStatsRepository(pool *database.DB) StatsRepository {
+	return &statsRepository{pool: pool}
+}
+
+// UserStats counts the user's RSVPs and check-ins at events they did not
+// organize, the events they organize and the tasks assigned to them. Deleted
+// events are left out. Rates are left to the caller.
+func (r *statsRepository) UserStats(ctx context.Context, userID int) (*models.UserStats, error) {
+	var s models.UserStats
+	err := r.pool.QueryRow(ctx, `
+		WITH tracked AS (`+trackedEvents+`),
+		invited AS (
+			SELECT p.attendance, p.checked_in_at, t.id IS NOT NULL AS tracked
+			FROM event_participants p
+			JOIN events e ON e.id = p.event_id
+			LEFT JOIN tracked t ON t.id = p.event_id
+			WHERE p.user_id = $1 AND e.organizer_id <> $1 AND e.deleted_at IS NULL
+		)
+		SELECT
+			count(*),
+			count(*) FILTER (WHERE attendance = 'going'),
+			count(*) FILTER (WHERE attendance = 'maybe'),
+			count(*) FILTER (WHERE attendance = 'not_going'),
+			count(*) FILTER (WHERE attendance IS NULL),
+			count(*) FILTER (WHERE tracked AND attendance = 'going'),
+			count(*) FILTER (WHERE tracked AND attendance = 'going' AND checked_in_at IS NOT NULL),
+			count(*) FILTER (WHERE tracked AND attendance IS DISTINCT FROM 'going' AND checked_in_at IS NOT NULL),
+			(SELECT count(*) FROM events WHERE organizer_id = $1 AND deleted_at IS NULL),
+			(SELECT count(*) FROM tasks t JOIN events e ON e.id = t.event_id WHERE t.assignee_id = $1 AND e.deleted_at IS NULL),
+			(SELECT count(*) FROM tasks t JOIN events e ON e.id = t.event_id WHERE t.assignee_id = $1 AND e.deleted_at IS NULL AND t.completed_at IS NOT NULL)
+		FROM invited
+	`, userID).Scan(
+		&s.RSVPs.Invited, &s.RSVPs.Going, &s.RSVPs.Maybe, &s.RSVPs.NotGoing, &s.RSVPs.Pending,
+		&s.Attendance.Going, &s.Attendance.Attended, &s.Attendance.WalkIns,
+		&s.OrganizedEvents, &s.Tasks.Assigned, &s.Tasks.Completed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.Attendance.NoShows = s.Attendance.Going - s.Attendance.Attended
+	return &s, nil
+}
+
+// Reliability counts, for each participant of the event but its organizer,
+// the other tracked events they said they were going to and those they were
+// checked in at. Events they organized themselves are left out.
+func (r *statsRepository) Reliability(ctx context.Context, eventID int) ([]models.Reliability, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH tracked AS (`+trackedEvents+`)
+		SELECT p.user_id, u.name,
+			count(o.event_id) FILTER (WHERE o.attendance = 'going'),
+			count(o.event_id) FILTER (WHERE o.attendance = 'going' AND o.checked_in_at IS NOT NULL)
+		FROM event_participants p
+		JOIN events e ON e.id = p.event_id
+		JOIN users u ON u.id = p.user_id
+		LEFT JOIN event_participants o ON o.user_id = p.user_id AND o.event_id <> p.event_id
+			AND o.event_id IN (SELECT id FROM tracked WHERE organizer_id <> p.user_id)
+		WHERE p.event_id = $1 AND p.user_id <> e.organizer_id
+		GROUP BY p.user_id, u.name
+		ORDER BY lower(u.name), p.user_id
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Reliability{}
+	for rows.Next() {
+		var rel models.Reliability
+		if err := rows.Scan(&rel.UserID, &rel.UserName, &rel.Going, &rel.Attended); err != nil {
+			return nil, err
+		}
+		res = append(res, rel)
+	}
+	return res, rows.Err()
+}
+
+// Funnel counts, at the end of each day or week (interval), the invitees of
+// the event who had been invited, viewed it, answered and been checked in.
+// The series runs from the first invitation to the last change, keeping the
+// latest maxPoints intervals.
+func (r *statsRepository) Funnel(ctx context.Context, eventID int, interval string, maxPoints int) ([]models.FunnelPoint, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH p AS (
+			SELECT p.invited_at, LEAST(p.viewed_at, p.first_responded_at) AS viewed_at,
+				p.first_responded_at AS responded_at, p.checked_in_at
+			FROM event_participants p
+			JOIN events e ON e.id = p.event_id
+			WHERE p.event_id = $1 AND p.user_id <> e.organizer_id
+		),
+		span AS (
+			SELECT date_trunc($2, min(invited_at)) AS first_at,
+				date_trunc($2, GREATEST(max(invited_at), max(viewed_at), max(responded_at), max(checked_in_at))) AS last_at
+			FROM p
+		),
+		buckets AS (
+			SELECT generate_series(GREATEST(first_at, last_at - ($3::int - 1) * ('1 ' || $2)::interval), last_at, ('1 ' || $2)::interval) AS start
+			FROM span WHERE first_at IS NOT NULL
+		)
+		SELECT b.start,
+			count(*) FILTER (WHERE p.invited_at < b.start + ('1 ' || $2)::interval),
+			count(*) FILTER (WHERE p.viewed_at < b.start + ('1 ' || $2)::interval),
+			count(*) FILTER (WHERE p.responded_at < b.start + ('1 ' || $2)::interval),
+			count(*) FILTER (WHERE p.checked_in_at < b.start + ('1 ' || $2)::interval)
+		FROM buckets b CROSS JOIN p
+		GROUP BY b.start
+		ORDER BY b.start
+	`, eventID, interval, maxPoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.FunnelPoint{}
+	for rows.Next() {
+		var pt models.FunnelPoint
+		if err := rows.Scan(&pt.Date, &pt.Invited, &pt.Viewed, &pt.Responded, &pt.Attended); err != nil {
+			return nil, err
+		}
+		res = append(res, pt)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SupplyRepository interface {
+	Create(ctx context.Context, s models.Supply, createdBy int) (*models.Supply, error)
+	Update(ctx context.Context, s models.Supply) (*models.Supply, error)
+	Get(ctx context.Context, eventID, supplyID int) (*models.Supply, error)
+	List(ctx context.Context, eventID int) ([]models.Supply, error)
+	Delete(ctx context.Context, eventID, supplyID int) error
+	Claim(ctx context.Context, eventID, supplyID, userID int) error
+	Unclaim(ctx context.Context, eventID, supplyID int) error
+	SetPurchased(ctx context.Context, eventID, supplyID int, purchased bool) error
+}
+
+type supplyRepository struct {
+	pool *database.DB
+}
+
+func NewSupplyRepository(pool *database.DB) SupplyRepository {
+	return &supplyRepository{pool: pool}
+}
+
+const supplyColumns = `s.id, s.event_id, s.name, s.quantity, s.notes, s.claimed_by, u.name, s.claimed_at, s.purchased, s.created_at, s.updated_at`
+
+const supplyFrom = ` FROM supplies s LEFT JOIN users u ON u.id = s.claimed_by`
+
+func scanSupply(row pgx.Row) (*models.Supply, error) {
+	var s models.Supply
+	if err := row.Scan(&s.ID, &s.EventID, &s.Name, &s.Quantity, &s.Notes, &s.ClaimedBy, &s.ClaimedByName, &s.ClaimedAt, &s.Purchased, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *supplyRepository) Create(ctx context.Context, s models.Supply, createdBy int) (*models.Supply, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO supplies (event_id, name, quantity, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, s.EventID, s.Name, s.Quantity, s.Notes, createdBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, s.EventID, id)
+}
+
+// Update changes an item's name, quantity and notes, leaving its claim alone.
+func (r *supplyRepository) Update(ctx context.Context, s models.Supply) (*models.Supply, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE supplies SET name = $3, quantity = $4, notes = $5, updated_at = now()
+		WHERE id = $1 AND event_id = $2
+	`, s.ID, s.EventID, s.Name, s.Quantity, s.Notes)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.Get(ctx, s.EventID, s.ID)
+}
+
+func (r *supplyRepository) Get(ctx context.Context, eventID, supplyID int) (*models.Supply, error) {
+	q := `SELECT ` + supplyColumns + supplyFrom + ` WHERE s.id = $1 AND s.event_id = $2`
+	return scanSupply(r.pool.QueryRow(ctx, q, supplyID, eventID))
+}
+
+// List returns the event's checklist, unclaimed items first.
+func (r *supplyRepository) List(ctx context.Context, eventID int) ([]models.Supply, error) {
+	q := `SELECT ` + supplyColumns + supplyFrom + ` WHERE s.event_id = $1 ORDER BY s.claimed_by IS NOT NULL, s.purchased, s.id`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Supply{}
+	for rows.Next() {
+		s, err := scanSupply(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *s)
+	}
+	return res, rows.Err()
+}
+
+func (r *supplyRepository) Delete(ctx context.Context, eventID, supplyID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM supplies WHERE id = $1 AND event_id = $2`, supplyID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Claim assigns an unclaimed item to the user in a single conditional
+// UPDATE, so two participants cannot claim the same item; pgx.ErrNoRows if
+// the item is missing or already claimed.
+func (r *supplyRepository) Claim(ctx context.Context, eventID, supplyID, userID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE supplies SET claimed_by = $3, claimed_at = now(), updated_at = now()
+		WHERE id = $1 AND event_id = $2 AND claimed_by IS NULL
+	`, supplyID, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Unclaim frees an item again; it is no longer marked purchased.
+func (r *supplyRepository) Unclaim(ctx context.Context, eventID, supplyID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE supplies SET claimed_by = NULL, claimed_at = NULL, purchased = false, updated_at = now()
+		WHERE id = $1 AND event_id = $2
+	`, supplyID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *supplyRepository) SetPurchased(ctx context.Context, eventID, supplyID int, purchased bool) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE supplies SET purchased = $3, updated_at = now() WHERE id = $1 AND event_id = $2
+	`, supplyID, eventID, purchased)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
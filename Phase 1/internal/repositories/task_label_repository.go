@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// TaskLabelRepository stores events' task labels and which tasks have them.
+type TaskLabelRepository interface {
+	List(ctx context.Context, eventID int) ([]models.TaskLabel, error)
+	Create(ctx context.Context, eventID int, name, color string) (*models.TaskLabel, error)
+	Update(ctx context.Context, eventID, labelID int, patch models.TaskLabelPatch) (*models.TaskLabel, error)
+	Delete(ctx context.Context, eventID, labelID int) (bool, error)
+	SetTaskLabels(ctx context.Context, eventID, taskID int, names []string) ([]models.TaskLabel, error)
+}
+
+type taskLabelRepository struct {
+	pool *database.DB
+}
+
+func NewTaskLabelRepository(pool *database.DB) TaskLabelRepository {
+	return &taskLabelRepository{pool: pool}
+}
+
+// List returns the event's labels by name, each with its number of tasks.
+func (r *taskLabelRepository) List(ctx context.Context, eventID int) ([]models.TaskLabel, error) {
+	const q = `
+		SELECT l.id, l.name, l.color, (SELECT count(*) FROM task_label_assignments a WHERE a.label_id = l.id)
+		FROM task_labels l
+		WHERE l.event_id = $1
+		ORDER BY lower(l.name), l.id
+	`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	labels := []models.TaskLabel{}
+	for rows.Next() {
+		var l models.TaskLabel
+		l.TaskCount = new(int)
+		if err := rows.Scan(&l.ID, &l.Name, &l.Color, l.TaskCount); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+func (r *taskLabelRepository) Create(ctx context.Context, eventID int, name, color string) (*models.TaskLabel, error) {
+	l := models.TaskLabel{TaskCount: new(int)}
+	err := r.pool.QueryRow(ctx, `INSERT INTO task_labels (event_id, name, color) VALUES ($1, $2, $3) RETURNING id, name, color`, eventID, name, color).
+		Scan(&l.ID, &l.Name, &l.Color)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Update changes the fields set in patch. It returns pgx.ErrNoRows when the
+// label is not the event's.
+func (r *taskLabelRepository) Update(ctx context.Context, eventID, labelID int, patch models.TaskLabelPatch) (*models.TaskLabel, error) {
+	const q = `
+		UPDATE task_labels l SET name = COALESCE($3, l.name), color = COALESCE($4, l.color)
+		WHERE l.id = $1 AND l.event_id = $2
+		RETURNING l.id, l.name, l.color, (SELECT count(*) FROM task_label_assignments a WHERE a.label_id = l.id)
+	`
+	l := models.TaskLabel{TaskCount: new(int)}
+	if err := r.pool.QueryRow(ctx, q, labelID, eventID, patch.Name, patch.Color).Scan(&l.ID, &l.Name, &l.Color, l.TaskCount); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Delete removes the label from the event and from its tasks, reporting
+// whether it existed.
+func (r *taskLabelRepository) Delete(ctx context.Context, eventID, labelID int) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM task_labels WHERE id = $1 AND event_id = $2`, labelID, eventID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetTaskLabels replaces the task's labels with those named, first adding
+// the names the event has no label for in models.DefaultLabelColor. It
+// returns the task's labels, or pgx.ErrNoRows when the task is not the
+// event's.
+func (r *taskLabelRepository) SetTaskLabels(ctx context.Context, eventID, taskID int, names []string) ([]models.TaskLabel, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	var id int
+	if err := tx.QueryRow(ctx, `SELECT id FROM tasks WHERE id = $1 AND event_id = $2 FOR UPDATE`, taskID, eventID).Scan(&id); err != nil {
+		return nil, err
+	}
+	const create = `
+		INSERT INTO task_labels (event_id, name, color)
+		SELECT $1, name, $3 FROM unnest($2::text[]) AS n(name)
+		ON CONFLICT (event_id, lower(name)) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, create, eventID, names, models.DefaultLabelColor); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM task_label_assignments WHERE task_id = $1`, taskID); err != nil {
+		return nil, err
+	}
+	const assign = `
+		INSERT INTO task_label_assignments (task_id, label_id)
+		SELECT $1, l.id FROM task_labels l
+		WHERE l.event_id = $2 AND lower(l.name) IN (SELECT lower(n) FROM unnest($3::text[]) AS n)
+	`
+	if _, err := tx.Exec(ctx, assign, taskID, eventID, names); err != nil {
+		return nil, err
+	}
+	var labels []models.TaskLabel
+	if err := tx.QueryRow(ctx, `SELECT `+taskLabels+` FROM tasks t WHERE t.id = $1`, taskID).Scan(&labels); err != nil {
+		return nil, err
+	}
+	return labels, tx.Commit(ctx)
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TaskTemplateRepository interface {
+	Create(ctx context.Context, t models.TaskTemplate) (*models.TaskTemplate, error)
+	Update(ctx context.Context, t models.TaskTemplate) (*models.TaskTemplate, error)
+	Get(ctx context.Context, id, userID int) (*models.TaskTemplate, error)
+	List(ctx context.Context, userID int) ([]models.TaskTemplate, error)
+	Delete(ctx context.Context, id, userID int) error
+}
+
+type taskTemplateRepository struct {
+	pool *database.DB
+}
+
+func NewTaskTemplateRepository(pool *database.DB) TaskTemplateRepository {
+	return &taskTemplateRepository{pool: pool}
+}
+
+const taskTemplateColumns = `t.id, t.user_id, t.name, t.description, t.tasks, t.created_at, t.updated_at`
+
+func scanTaskTemplate(row pgx.Row, t *models.TaskTemplate) error {
+	return row.Scan(&t.ID, &t.UserID, &t.Name, &t.Description, &t.Tasks, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *taskTemplateRepository) Create(ctx context.Context, t models.TaskTemplate) (*models.TaskTemplate, error) {
+	q := `
+		INSERT INTO task_templates AS t (user_id, name, description, tasks)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + taskTemplateColumns
+	var out models.TaskTemplate
+	if err := scanTaskTemplate(r.pool.QueryRow(ctx, q, t.UserID, t.Name, t.Description, t.Tasks), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *taskTemplateRepository) Update(ctx context.Context, t models.TaskTemplate) (*models.TaskTemplate, error) {
+	q := `
+		UPDATE task_templates AS t
+		SET name = $3, description = $4, tasks = $5, updated_at = now()
+		WHERE t.id = $1 AND t.user_id = $2
+		RETURNING ` + taskTemplateColumns
+	var out models.TaskTemplate
+	if err := scanTaskTemplate(r.pool.QueryRow(ctx, q, t.ID, t.UserID, t.Name, t.Description, t.Tasks), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *taskTemplateRepository) Get(ctx context.Context, id, userID int) (*models.TaskTemplate, error) {
+	q := `SELECT ` + taskTemplateColumns + ` FROM task_templates t WHERE t.id = $1 AND t.user_id = $2`
+	var t models.TaskTemplate
+	if err := scanTaskTemplate(r.pool.QueryRow(ctx, q, id, userID), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *taskTemplateRepository) List(ctx context.Context, userID int) ([]models.TaskTemplate, error) {
+	q := `SELECT ` + taskTemplateColumns + ` FROM task_templates t WHERE t.user_id = $1 ORDER BY t.name`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.TaskTemplate{}
+	for rows.Next() {
+		var t models.TaskTemplate
+		if err := scanTaskTemplate(rows, &t); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (r *taskTemplateRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM task_templates WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
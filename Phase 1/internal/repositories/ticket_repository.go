@@ -0,0 +1,686 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TicketRepository interface {
+	CreateTier(ctx context.Context, t models.TicketTier) (*models.TicketTier, error)
+	UpdateTier(ctx context.Context, t models.TicketTier) (*models.TicketTier, error)
+	GetTier(ctx context.Context, eventID, tierID int) (*models.TicketTier, error)
+	ListTiers(ctx context.Context, eventID int) ([]models.TicketTier, error)
+	Claim(ctx context.Context, eventID, tierID, userID int, code string, promoCodeID *int) (*models.Ticket, error)
+	SetCheckout(ctx context.Context, ticketID int, sessionID, url string) error
+	ConfirmPayment(ctx context.Context, ticketID int, paymentID string) error
+	Release(ctx context.Context, ticketID int, from, to string) error
+	BeginRefund(ctx context.Context, ticketID, refundedCents int) error
+	AbortRefund(ctx context.Context, ticketID int) error
+	RecordRefund(ctx context.Context, ticketID int) error
+	Cancel(ctx context.Context, ticketID, userID int) error
+	GetTicket(ctx context.Context, ticketID int) (*models.Ticket, error)
+	GetActive(ctx context.Context, eventID, userID int) (*models.Ticket, error)
+	Transfer(ctx context.Context, eventID, fromUserID, toUserID int, newCode string) (*models.Transfer, error)
+	GetBySession(ctx context.Context, sessionID string) (*models.Ticket, error)
+	GetByPayment(ctx context.Context, paymentID string) (*models.Ticket, error)
+	ListPending(ctx context.Context, before time.Time) ([]models.Ticket, error)
+	ListRefunding(ctx context.Context, before time.Time) ([]models.Ticket, error)
+	ListByUser(ctx context.Context, userID int) ([]models.Ticket, error)
+	CreatePromoCode(ctx context.Context, p models.PromoCode) (*models.PromoCode, error)
+	UpdatePromoCode(ctx context.Context, p models.PromoCode) (*models.PromoCode, error)
+	GetPromoCode(ctx context.Context, eventID int, code string) (*models.PromoCode, error)
+	ListPromoCodes(ctx context.Context, eventID int) ([]models.PromoCode, error)
+	DeletePromoCode(ctx context.Context, eventID, promoCodeID int) error
+	ListRedemptions(ctx context.Context, eventID, promoCodeID int) ([]models.PromoRedemption, error)
+	SalesByCurrency(ctx context.Context, eventID int) ([]models.SalesAmounts, error)
+	GetReceipt(ctx context.Context, ticketID int) (*models.Receipt, error)
+	SetRefundRules(ctx context.Context, eventID int, rules []models.RefundRule) error
+	ListRefundRules(ctx context.Context, eventID int) ([]models.RefundRule, error)
+}
+
+type ticketRepository struct {
+	pool *database.DB
+}
+
+func NewTicketRepository(pool *database.DB) TicketRepository {
+	return &ticketRepository{pool: pool}
+}
+
+const tierColumns = `id, event_id, name, price_cents, currency, quantity, remaining, created_at, updated_at`
+
+func scanTier(row pgx.Row) (*models.TicketTier, error) {
+	var t models.TicketTier
+	if err := row.Scan(&t.ID, &t.EventID, &t.Name, &t.PriceCents, &t.Currency, &t.Quantity, &t.Remaining, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *ticketRepository) CreateTier(ctx context.Context, t models.TicketTier) (*models.TicketTier, error) {
+	q := `
+		INSERT INTO ticket_tiers (event_id, name, price_cents, currency, quantity, remaining)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING ` + tierColumns
+	return scanTier(r.pool.QueryRow(ctx, q, t.EventID, t.Name, t.PriceCents, t.Currency, t.Quantity))
+}
+
+// UpdateTier changes a tier's details. Changing the quantity shifts remaining
+// by the same amount; pgx.ErrNoRows is returned when the tier does not exist
+// or the new quantity is below the number of tickets already claimed.
+func (r *ticketRepository) UpdateTier(ctx context.Context, t models.TicketTier) (*models.TicketTier, error) {
+	q := `
+		UPDATE ticket_tiers
+		SET name = $3, price_cents = $4, currency = $5,
+			remaining = remaining + ($6 - quantity), quantity = $6, updated_at = now()
+		WHERE id = $1 AND event_id = $2 AND remaining + ($6 - quantity) >= 0
+		RETURNING ` + tierColumns
+	return scanTier(r.pool.QueryRow(ctx, q, t.ID, t.EventID, t.Name, t.PriceCents, t.Currency, t.Quantity))
+}
+
+func (r *ticketRepository) GetTier(ctx context.Context, eventID, tierID int) (*models.TicketTier, error) {
+	q := `SELECT ` + tierColumns + ` FROM ticket_tiers WHERE id = $1 AND event_id = $2`
+	return scanTier(r.pool.QueryRow(ctx, q, tierID, eventID))
+}
+
+func (r *ticketRepository) ListTiers(ctx context.Context, eventID int) ([]models.TicketTier, error) {
+	q := `SELECT ` + tierColumns + ` FROM ticket_tiers WHERE event_id = $1 ORDER BY price_cents, id`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.TicketTier{}
+	for rows.Next() {
+		t, err := scanTier(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *t)
+	}
+	return res, rows.Err()
+}
+
+// Claim takes one seat from the tier and issues a ticket in one transaction.
+// The seat is taken with a conditional decrement, so concurrent claims can
+// never oversell; pgx.ErrNoRows means the tier is sold out (or missing).
+// A promo code, if given, is redeemed in the same transaction with a
+// conditional increment of its uses; pgx.ErrNoRows is also returned when it
+// expired or was used up meanwhile.
+// Tickets that cost nothing are claimed at once and the claimant's attendance
+// is set to going; others stay pending until ConfirmPayment.
+func (r *ticketRepository) Claim(ctx context.Context, eventID, tierID, userID int, code string, promoCodeID *int) (*models.Ticket, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	t := models.Ticket{TierID: tierID, EventID: eventID, UserID: userID}
+	err = tx.QueryRow(ctx, `
+		UPDATE ticket_tiers SET remaining = remaining - 1, updated_at = now()
+		WHERE id = $1 AND event_id = $2 AND remaining > 0
+		RETURNING name, price_cents, currency
+	`, tierID, eventID).Scan(&t.TierName, &t.AmountCents, &t.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if promoCodeID != nil {
+		var p models.PromoCode
+		err = tx.QueryRow(ctx, `
+			UPDATE promo_codes SET uses = uses + 1
+			WHERE id = $1 AND event_id = $2
+				AND (max_uses IS NULL OR uses < max_uses)
+				AND (expires_at IS NULL OR expires_at > now())
+			RETURNING kind, amount
+		`, *promoCodeID, eventID).Scan(&p.Kind, &p.Amount)
+		if err != nil {
+			return nil, err
+		}
+		t.DiscountCents = p.Discount(t.AmountCents)
+		t.AmountCents -= t.DiscountCents
+	}
+
+	status := models.TicketClaimed
+	if t.AmountCents > 0 {
+		status = models.TicketPending
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO tickets (tier_id, event_id, user_id, code, status, amount_cents, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, code, status, created_at
+	`, tierID, eventID, userID, code, status, t.AmountCents, t.Currency).Scan(&t.ID, &t.Code, &t.Status, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if promoCodeID != nil {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO promo_redemptions (promo_code_id, ticket_id, user_id, discount_cents)
+			VALUES ($1, $2, $3, $4)
+		`, *promoCodeID, t.ID, userID, t.DiscountCents); err != nil {
+			return nil, err
+		}
+	}
+
+	if status == models.TicketClaimed {
+		if err := setGoing(ctx, tx, eventID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return &t, tx.Commit(ctx)
+}
+
+func setGoing(ctx context.Context, tx pgx.Tx, eventID, userID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE event_participants
+		SET attendance = 'going',
+			responded_at = CASE WHEN attendance IS DISTINCT FROM 'going' THEN now() ELSE responded_at END,
+			first_responded_at = COALESCE(first_responded_at, now()),
+			updated_at = now()
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, userID)
+	return err
+}
+
+// SetCheckout records the payment provider's checkout session for a pending ticket.
+func (r *ticketRepository) SetCheckout(ctx context.Context, ticketID int, sessionID, url string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE tickets SET checkout_session_id = $2, checkout_url = $3, updated_at = now()
+		WHERE id = $1
+	`, ticketID, sessionID, url)
+	return err
+}
+
+// ConfirmPayment marks a pending ticket as paid and claimed and sets the
+// holder's attendance to going; pgx.ErrNoRows if the ticket is not pending.
+func (r *ticketRepository) ConfirmPayment(ctx context.Context, ticketID int, paymentID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var eventID, userID int
+	err = tx.QueryRow(ctx, `
+		UPDATE tickets SET status = 'claimed', payment_id = $2, checkout_url = NULL, updated_at = now()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING event_id, user_id
+	`, ticketID, paymentID).Scan(&eventID, &userID)
+	if err != nil {
+		return err
+	}
+	if err := setGoing(ctx, tx, eventID, userID); err != nil {
+		return err
+	}
+	receipt, err := issueReceipt(ctx, tx, ticketID)
+	if err != nil {
+		return err
+	}
+	if err := addToOutbox(ctx, tx, models.TopicTicketPaid, models.TicketPaid{
+		TicketID:      ticketID,
+		EventID:       eventID,
+		UserID:        userID,
+		InvoiceNumber: receipt.InvoiceNumber,
+		AmountCents:   receipt.AmountCents,
+		Currency:      receipt.Currency,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const receiptColumns = `id, ticket_id, event_id, user_id, invoice_number, seller_name, buyer_name, buyer_email,
+	event_title, event_start, tier_name, amount_cents, discount_cents, currency, payment_id, issued_at`
+
+func scanReceipt(row pgx.Row) (*models.Receipt, error) {
+	var r models.Receipt
+	if err := row.Scan(&r.ID, &r.TicketID, &r.EventID, &r.UserID, &r.InvoiceNumber, &r.SellerName, &r.BuyerName, &r.BuyerEmail,
+		&r.EventTitle, &r.EventStart, &r.TierName, &r.AmountCents, &r.DiscountCents, &r.Currency, &r.PaymentID, &r.IssuedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// issueReceipt numbers and stores the receipt of a paid ticket in tx. The
+// organizer's counter row stays locked until tx ends, so concurrent payments
+// for the same organizer get consecutive numbers.
+func issueReceipt(ctx context.Context, tx pgx.Tx, ticketID int) (*models.Receipt, error) {
+	const q = `
+		WITH t AS (
+			SELECT t.id, t.event_id, t.user_id, t.amount_cents, COALESCE(pr.discount_cents, 0) AS discount_cents,
+				t.currency, t.payment_id, e.organizer_id, e.title, e.start_time, tt.name AS tier_name
+			FROM tickets t
+			JOIN events e ON e.id = t.event_id
+			JOIN ticket_tiers tt ON tt.id = t.tier_id
+			LEFT JOIN promo_redemptions pr ON pr.ticket_id = t.id
+			WHERE t.id = $1
+		), n AS (
+			INSERT INTO invoice_counters (organizer_id, last_number)
+			SELECT organizer_id, 1 FROM t
+			ON CONFLICT (organizer_id) DO UPDATE SET last_number = invoice_counters.last_number + 1
+			RETURNING organizer_id, last_number
+		)
+		INSERT INTO receipts (ticket_id, event_id, user_id, organizer_id, number, invoice_number,
+			seller_name, buyer_name, buyer_email, event_title, event_start, tier_name,
+			amount_cents, discount_cents, currency, payment_id)
+		SELECT t.id, t.event_id, t.user_id, n.organizer_id, n.last_number,
+			n.organizer_id || '-' || lpad(n.last_number::text, 6, '0'),
+			seller.name, buyer.name, buyer.email, t.title, t.start_time, t.tier_name,
+			t.amount_cents, t.discount_cents, t.currency, t.payment_id
+		FROM t
+		JOIN n ON n.organizer_id = t.organizer_id
+		JOIN users seller ON seller.id = t.organizer_id
+		JOIN users buyer ON buyer.id = t.user_id
+		RETURNING ` + receiptColumns
+	return scanReceipt(tx.QueryRow(ctx, q, ticketID))
+}
+
+// GetReceipt returns the receipt issued for a ticket; pgx.ErrNoRows if the
+// ticket has none.
+func (r *ticketRepository) GetReceipt(ctx context.Context, ticketID int) (*models.Receipt, error) {
+	return scanReceipt(r.pool.QueryRow(ctx, `SELECT `+receiptColumns+` FROM receipts WHERE ticket_id = $1`, ticketID))
+}
+
+// Release moves a ticket from one status to another (e.g. pending to
+// cancelled, claimed to refunded) and returns its seat to the tier;
+// pgx.ErrNoRows if the ticket is not in the expected status.
+func (r *ticketRepository) Release(ctx context.Context, ticketID int, from, to string) error {
+	return r.release(ctx, ticketID, from, to, nil)
+}
+
+// BeginRefund moves a claimed ticket to refunding, recording the amount to
+// pay back; pgx.ErrNoRows if the ticket is not claimed, such as when another
+// refund of it began first.
+func (r *ticketRepository) BeginRefund(ctx context.Context, ticketID, refundedCents int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE tickets SET status = 'refunding', refunded_cents = $2, updated_at = now()
+		WHERE id = $1 AND status = 'claimed'
+	`, ticketID, refundedCents)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// AbortRefund moves a refunding ticket back to claimed; pgx.ErrNoRows if it
+// is not refunding.
+func (r *ticketRepository) AbortRefund(ctx context.Context, ticketID int) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE tickets SET status = 'claimed', refunded_cents = NULL, updated_at = now()
+		WHERE id = $1 AND status = 'refunding'
+	`, ticketID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// RecordRefund marks a refunding ticket refunded and releases its seat.
+func (r *ticketRepository) RecordRefund(ctx context.Context, ticketID int) error {
+	return r.release(ctx, ticketID, models.TicketRefunding, models.TicketRefunded, nil)
+}
+
+func (r *ticketRepository) release(ctx context.Context, ticketID int, from, to string, refundedCents *int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var tierID int
+	err = tx.QueryRow(ctx, `
+		UPDATE tickets SET status = $3, checkout_url = NULL, refunded_cents = COALESCE($4, refunded_cents), updated_at = now()
+		WHERE id = $1 AND status = $2
+		RETURNING tier_id
+	`, ticketID, from, to, refundedCents).Scan(&tierID)
+	if err != nil {
+		return err
+	}
+	if err := returnSeat(ctx, tx, ticketID, tierID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// returnSeat gives a released ticket's seat back to its tier, and the use of
+// any promo code it redeemed back to the code.
+func returnSeat(ctx context.Context, tx pgx.Tx, ticketID, tierID int) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE ticket_tiers SET remaining = remaining + 1, updated_at = now() WHERE id = $1
+	`, tierID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		WITH released AS (
+			UPDATE promo_redemptions SET released_at = now()
+			WHERE ticket_id = $1 AND released_at IS NULL
+			RETURNING promo_code_id
+		)
+		UPDATE promo_codes SET uses = uses - 1 WHERE id IN (SELECT promo_code_id FROM released)
+	`, ticketID)
+	return err
+}
+
+// Cancel releases the user's pending or free ticket and returns its seat to
+// the tier; pgx.ErrNoRows if the user has no such ticket. Paid tickets are
+// refunded instead.
+func (r *ticketRepository) Cancel(ctx context.Context, ticketID, userID int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var tierID int
+	err = tx.QueryRow(ctx, `
+		UPDATE tickets SET status = 'cancelled', checkout_url = NULL, updated_at = now()
+		WHERE id = $1 AND user_id = $2
+			AND (status = 'pending' OR (status = 'claimed' AND payment_id IS NULL))
+		RETURNING tier_id
+	`, ticketID, userID).Scan(&tierID)
+	if err != nil {
+		return err
+	}
+	if err := returnSeat(ctx, tx, ticketID, tierID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const ticketColumns = `t.id, t.tier_id, tt.name, t.event_id, t.user_id, t.code, t.status,
+	t.amount_cents, COALESCE(pr.discount_cents, 0), t.currency, t.checkout_url, t.refunded_cents, t.checkout_session_id, t.payment_id, t.created_at`
+
+const ticketFrom = ` FROM tickets t
+	JOIN ticket_tiers tt ON tt.id = t.tier_id
+	LEFT JOIN promo_redemptions pr ON pr.ticket_id = t.id`
+
+func scanTicket(row pgx.Row) (*models.Ticket, error) {
+	var t models.Ticket
+	if err := row.Scan(&t.ID, &t.TierID, &t.TierName, &t.EventID, &t.UserID, &t.Code, &t.Status,
+		&t.AmountCents, &t.DiscountCents, &t.Currency, &t.CheckoutURL, &t.RefundedCents, &t.SessionID, &t.PaymentID, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *ticketRepository) getTicketWhere(ctx context.Context, cond string, arg any) (*models.Ticket, error) {
+	q := `SELECT ` + ticketColumns + ticketFrom + ` WHERE ` + cond
+	return scanTicket(r.pool.QueryRow(ctx, q, arg))
+}
+
+func (r *ticketRepository) GetTicket(ctx context.Context, ticketID int) (*models.Ticket, error) {
+	return r.getTicketWhere(ctx, `t.id = $1`, ticketID)
+}
+
+// GetActive returns the user's pending or claimed ticket for the event.
+func (r *ticketRepository) GetActive(ctx context.Context, eventID, userID int) (*models.Ticket, error) {
+	q := `SELECT ` + ticketColumns + ticketFrom + ` WHERE t.event_id = $1 AND t.user_id = $2 AND t.status IN ('pending', 'claimed')`
+	return scanTicket(r.pool.QueryRow(ctx, q, eventID, userID))
+}
+
+func (r *ticketRepository) GetBySession(ctx context.Context, sessionID string) (*models.Ticket, error) {
+	return r.getTicketWhere(ctx, `t.checkout_session_id = $1`, sessionID)
+}
+
+func (r *ticketRepository) GetByPayment(ctx context.Context, paymentID string) (*models.Ticket, error) {
+	return r.getTicketWhere(ctx, `t.payment_id = $1 ORDER BY t.id DESC LIMIT 1`, paymentID)
+}
+
+func (r *ticketRepository) listTickets(ctx context.Context, cond string, args ...any) ([]models.Ticket, error) {
+	q := `SELECT ` + ticketColumns + ticketFrom + ` WHERE ` + cond
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Ticket{}
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *t)
+	}
+	return res, rows.Err()
+}
+
+// ListPending returns tickets still awaiting payment that were claimed before the given time.
+func (r *ticketRepository) ListPending(ctx context.Context, before time.Time) ([]models.Ticket, error) {
+	return r.listTickets(ctx, `t.status = 'pending' AND t.created_at < $1 ORDER BY t.created_at`, before)
+}
+
+// ListRefunding returns tickets whose refund began before the given time and
+// was not recorded.
+func (r *ticketRepository) ListRefunding(ctx context.Context, before time.Time) ([]models.Ticket, error) {
+	return r.listTickets(ctx, `t.status = 'refunding' AND t.updated_at < $1 ORDER BY t.updated_at`, before)
+}
+
+// ListByUser returns the user's tickets, newest first.
+func (r *ticketRepository) ListByUser(ctx context.Context, userID int) ([]models.Ticket, error) {
+	return r.listTickets(ctx, `t.user_id = $1 ORDER BY t.created_at DESC, t.id DESC`, userID)
+}
+
+const promoColumns = `id, event_id, code, kind, amount, max_uses, uses, expires_at, created_at`
+
+func scanPromoCode(row pgx.Row) (*models.PromoCode, error) {
+	var p models.PromoCode
+	if err := row.Scan(&p.ID, &p.EventID, &p.Code, &p.Kind, &p.Amount, &p.MaxUses, &p.Uses, &p.ExpiresAt, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ticketRepository) CreatePromoCode(ctx context.Context, p models.PromoCode) (*models.PromoCode, error) {
+	q := `
+		INSERT INTO promo_codes (event_id, code, kind, amount, max_uses, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + promoColumns
+	return scanPromoCode(r.pool.QueryRow(ctx, q, p.EventID, p.Code, p.Kind, p.Amount, p.MaxUses, p.ExpiresAt))
+}
+
+// UpdatePromoCode changes a code's terms. Lowering max_uses below the current
+// uses only stops further redemptions.
+func (r *ticketRepository) UpdatePromoCode(ctx context.Context, p models.PromoCode) (*models.PromoCode, error) {
+	q := `
+		UPDATE promo_codes SET code = $3, kind = $4, amount = $5, max_uses = $6, expires_at = $7
+		WHERE id = $1 AND event_id = $2
+		RETURNING ` + promoColumns
+	return scanPromoCode(r.pool.QueryRow(ctx, q, p.ID, p.EventID, p.Code, p.Kind, p.Amount, p.MaxUses, p.ExpiresAt))
+}
+
+func (r *ticketRepository) GetPromoCode(ctx context.Context, eventID int, code string) (*models.PromoCode, error) {
+	q := `SELECT ` + promoColumns + ` FROM promo_codes WHERE event_id = $1 AND code = $2`
+	return scanPromoCode(r.pool.QueryRow(ctx, q, eventID, code))
+}
+
+func (r *ticketRepository) ListPromoCodes(ctx context.Context, eventID int) ([]models.PromoCode, error) {
+	q := `SELECT ` + promoColumns + ` FROM promo_codes WHERE event_id = $1 ORDER BY created_at, id`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.PromoCode{}
+	for rows.Next() {
+		p, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *p)
+	}
+	return res, rows.Err()
+}
+
+// DeletePromoCode removes a code and its redemption history; tickets keep
+// their discounted price. pgx.ErrNoRows if the code does not exist.
+func (r *ticketRepository) DeletePromoCode(ctx context.Context, eventID, promoCodeID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM promo_codes WHERE id = $1 AND event_id = $2`, promoCodeID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListRedemptions returns the code's redemptions, newest first.
+func (r *ticketRepository) ListRedemptions(ctx context.Context, eventID, promoCodeID int) ([]models.PromoRedemption, error) {
+	const q = `
+		SELECT pr.id, pr.promo_code_id, pr.ticket_id, pr.user_id, u.name, pr.discount_cents, pr.redeemed_at, pr.released_at
+		FROM promo_redemptions pr
+		JOIN promo_codes pc ON pc.id = pr.promo_code_id
+		JOIN users u ON u.id = pr.user_id
+		WHERE pr.promo_code_id = $1 AND pc.event_id = $2
+		ORDER BY pr.redeemed_at DESC, pr.id DESC
+	`
+	rows, err := r.pool.Query(ctx, q, promoCodeID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.PromoRedemption{}
+	for rows.Next() {
+		var p models.PromoRedemption
+		if err := rows.Scan(&p.ID, &p.PromoCodeID, &p.TicketID, &p.UserID, &p.UserName, &p.DiscountCents, &p.RedeemedAt, &p.ReleasedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
+
+// Transfer hands fromUserID's spot in the event to toUserID in one
+// transaction: the participant row moves to the recipient as an attendee with
+// the same attendance, the sender's RSVP answers are dropped and a claimed
+// ticket moves too, under a new door code. pgx.ErrNoRows if the sender is not
+// a participant.
+func (r *ticketRepository) Transfer(ctx context.Context, eventID, fromUserID, toUserID int, newCode string) (*models.Transfer, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM rsvp_answers WHERE event_id = $1 AND user_id = $2`, eventID, fromUserID); err != nil {
+		return nil, err
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE event_participants SET user_id = $3, role = 'attendee', invited_by = $2, updated_at = now()
+		WHERE event_id = $1 AND user_id = $2
+	`, eventID, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	t := models.Transfer{EventID: eventID, FromUserID: fromUserID, ToUserID: toUserID}
+	var ticketID int
+	err = tx.QueryRow(ctx, `
+		UPDATE tickets SET user_id = $3, code = $4, updated_at = now()
+		WHERE event_id = $1 AND user_id = $2 AND status = 'claimed'
+		RETURNING id
+	`, eventID, fromUserID, toUserID, newCode).Scan(&ticketID)
+	switch {
+	case err == nil:
+		t.TicketID = &ticketID
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO spot_transfers (event_id, from_user_id, to_user_id, ticket_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, eventID, fromUserID, toUserID, t.TicketID).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, tx.Commit(ctx)
+}
+
+// SalesByCurrency totals the event's tickets per currency and status.
+func (r *ticketRepository) SalesByCurrency(ctx context.Context, eventID int) ([]models.SalesAmounts, error) {
+	const q = `
+		SELECT currency,
+			count(*) FILTER (WHERE status = 'claimed'),
+			COALESCE(sum(amount_cents) FILTER (WHERE status = 'claimed'), 0),
+			COALESCE(sum(amount_cents) FILTER (WHERE status = 'pending'), 0),
+			COALESCE(sum(COALESCE(refunded_cents, amount_cents)) FILTER (WHERE status = 'refunded'), 0)
+		FROM tickets
+		WHERE event_id = $1
+		GROUP BY currency
+		ORDER BY currency
+	`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.SalesAmounts{}
+	for rows.Next() {
+		var s models.SalesAmounts
+		if err := rows.Scan(&s.Currency, &s.Tickets, &s.RevenueCents, &s.PendingCents, &s.RefundedCents); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// SetRefundRules replaces the event's refund rules.
+func (r *ticketRepository) SetRefundRules(ctx context.Context, eventID int, rules []models.RefundRule) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM refund_rules WHERE event_id = $1`, eventID); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO refund_rules (event_id, days_before, percent) VALUES ($1, $2, $3)
+		`, eventID, rule.DaysBefore, rule.Percent); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ListRefundRules returns the event's refund rules, most days before first.
+func (r *ticketRepository) ListRefundRules(ctx context.Context, eventID int) ([]models.RefundRule, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT days_before, percent FROM refund_rules WHERE event_id = $1 ORDER BY days_before DESC
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.RefundRule{}
+	for rows.Next() {
+		var rule models.RefundRule
+		if err := rows.Scan(&rule.DaysBefore, &rule.Percent); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
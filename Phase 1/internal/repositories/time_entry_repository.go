@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TimeEntryRepository stores the time users track on tasks.
+type TimeEntryRepository interface {
+	TaskAssignee(ctx context.Context, eventID, taskID int) (*int, error)
+	StartTimer(ctx context.Context, taskID, userID int, note string) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, taskID, userID int) (*models.TimeEntry, error)
+	Add(ctx context.Context, taskID, userID int, startedAt time.Time, minutes int, note string) (*models.TimeEntry, error)
+	List(ctx context.Context, taskID int) ([]models.TimeEntry, error)
+	Delete(ctx context.Context, taskID, entryID, ownerID int) (bool, error)
+	Totals(ctx context.Context, eventID int) (*models.EventTimeTotals, error)
+}
+
+type timeEntryRepository struct {
+	pool *database.DB
+}
+
+func NewTimeEntryRepository(pool *database.DB) TimeEntryRepository {
+	return &timeEntryRepository{pool: pool}
+}
+
+// timeEntryColumns lists the columns read by scanTimeEntry. Minutes are
+// whole minutes, up to now for running timers.
+const timeEntryColumns = `id, task_id, user_id, started_at, ended_at,
+	floor(extract(epoch FROM COALESCE(ended_at, now()) - started_at) / 60)::int, note`
+
+func scanTimeEntry(row pgx.Row) (*models.TimeEntry, error) {
+	var e models.TimeEntry
+	if err := row.Scan(&e.ID, &e.TaskID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Minutes, &e.Note); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// TaskAssignee returns the assignee of the event's task, nil when it has
+// none, or pgx.ErrNoRows when the event has no such task.
+func (r *timeEntryRepository) TaskAssignee(ctx context.Context, eventID, taskID int) (*int, error) {
+	var assignee *int
+	err := r.pool.QueryRow(ctx, `SELECT assignee_id FROM tasks WHERE id = $1 AND event_id = $2`, taskID, eventID).Scan(&assignee)
+	return assignee, err
+}
+
+// StartTimer starts a timer for the user on the task, stopping the one the
+// user had running, if any.
+func (r *timeEntryRepository) StartTimer(ctx context.Context, taskID, userID int, note string) (*models.TimeEntry, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `UPDATE task_time_entries SET ended_at = now() WHERE user_id = $1 AND ended_at IS NULL`, userID); err != nil {
+		return nil, err
+	}
+	const q = `
+		INSERT INTO task_time_entries (task_id, user_id, started_at, note)
+		VALUES ($1, $2, now(), $3)
+		RETURNING ` + timeEntryColumns
+	entry, err := scanTimeEntry(tx.QueryRow(ctx, q, taskID, userID, note))
+	if err != nil {
+		return nil, err
+	}
+	return entry, tx.Commit(ctx)
+}
+
+// StopTimer stops the user's timer on the task. It returns pgx.ErrNoRows
+// when none is running.
+func (r *timeEntryRepository) StopTimer(ctx context.Context, taskID, userID int) (*models.TimeEntry, error) {
+	const q = `
+		UPDATE task_time_entries SET ended_at = now()
+		WHERE task_id = $1 AND user_id = $2 AND ended_at IS NULL
+		RETURNING ` + timeEntryColumns
+	return scanTimeEntry(r.pool.QueryRow(ctx, q, taskID, userID))
+}
+
+func (r *timeEntryRepository) Add(ctx context.Context, taskID, userID int, startedAt time.Time, minutes int, note string) (*models.TimeEntry, error) {
+	const q = `
+		INSERT INTO task_time_entries (task_id, user_id, started_at, ended_at, note)
+		VALUES ($1, $2, $3, $3 + make_interval(mins => $4), $5)
+		RETURNING ` + timeEntryColumns
+	return scanTimeEntry(r.pool.QueryRow(ctx, q, taskID, userID, startedAt, minutes, note))
+}
+
+// List returns the task's entries, most recent first.
+func (r *timeEntryRepository) List(ctx context.Context, taskID int) ([]models.TimeEntry, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+timeEntryColumns+` FROM task_time_entries WHERE task_id = $1 ORDER BY started_at DESC, id DESC`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := []models.TimeEntry{}
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// Delete removes an entry of the task, only ownerID's unless it is 0, and
+// reports whether there was one.
+func (r *timeEntryRepository) Delete(ctx context.Context, taskID, entryID, ownerID int) (bool, error) {
+	const q = `DELETE FROM task_time_entries WHERE id = $1 AND task_id = $2 AND ($3::int = 0 OR user_id = $3)`
+	tag, err := r.pool.Exec(ctx, q, entryID, taskID, ownerID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Totals sums the event's estimates and tracked time, per task in the
+// tasks' order and per user with the most time first.
+func (r *timeEntryRepository) Totals(ctx context.Context, eventID int) (*models.EventTimeTotals, error) {
+	const tracked = `COALESCE(floor(extract(epoch FROM sum(COALESCE(te.ended_at, now()) - te.started_at)) / 60)::int, 0)`
+	const tasksQ = `
+		SELECT t.id, t.title, t.estimated_minutes, ` + tracked + `
+		FROM tasks t
+		LEFT JOIN task_time_entries te ON te.task_id = t.id
+		WHERE t.event_id = $1
+		GROUP BY t.id
+		ORDER BY t.position NULLS LAST, t.due_date NULLS LAST, t.id
+	`
+	const usersQ = `
+		SELECT u.id, u.name, ` + tracked + `
+		FROM task_time_entries te
+		JOIN tasks t ON t.id = te.task_id
+		JOIN users u ON u.id = te.user_id
+		WHERE t.event_id = $1
+		GROUP BY u.id
+		ORDER BY 3 DESC, u.id
+	`
+	totals := &models.EventTimeTotals{EventID: eventID, Tasks: []models.TaskTimeTotal{}, Users: []models.UserTimeTotal{}}
+	rows, err := r.pool.Query(ctx, tasksQ, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t models.TaskTimeTotal
+		if err := rows.Scan(&t.TaskID, &t.Title, &t.EstimatedMinutes, &t.TrackedMinutes); err != nil {
+			return nil, err
+		}
+		if t.EstimatedMinutes != nil {
+			totals.EstimatedMinutes += *t.EstimatedMinutes
+		}
+		totals.TrackedMinutes += t.TrackedMinutes
+		totals.Tasks = append(totals.Tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows, err = r.pool.Query(ctx, usersQ, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u models.UserTimeTotal
+		if err := rows.Scan(&u.UserID, &u.Name, &u.TrackedMinutes); err != nil {
+			return nil, err
+		}
+		totals.Users = append(totals.Users, u)
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+type UndoRepository interface {
+	Create(ctx context.Context, tokenHash string, a models.UndoAction) error
+	Undo(ctx context.Context, tokenHash string, userID int) (*models.UndoAction, int64, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+type undoRepository struct {
+	pool *database.DB
+}
+
+func NewUndoRepository(pool *database.DB) UndoRepository {
+	return &undoRepository{pool: pool}
+}
+
+func (r *undoRepository) Create(ctx context.Context, tokenHash string, a models.UndoAction) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO undo_actions (token_hash, user_id, action, event_ids, payload, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, tokenHash, a.UserID, a.Action, a.EventIDs, a.Payload, a.ExpiresAt)
+	return err
+}
+
+// Undo uses up the user's unexpired token and reverses its action in the same
+// transaction, so a token works once, and returns how many rows it restored:
+// none when the action was overtaken, e.g. a revoked invitee was invited
+// again meanwhile. pgx.ErrNoRows means there is no such token for the user,
+// or it expired.
+func (r *undoRepository) Undo(ctx context.Context, tokenHash string, userID int) (*models.UndoAction, int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	a := models.UndoAction{UserID: userID}
+	err = tx.QueryRow(ctx, `
+		DELETE FROM undo_actions
+		WHERE token_hash = $1 AND user_id = $2 AND expires_at > now()
+		RETURNING action, event_ids, payload, expires_at
+	`, tokenHash, userID).Scan(&a.Action, &a.EventIDs, &a.Payload, &a.ExpiresAt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var restored int64
+	switch a.Action {
+	case models.UndoDeleteEvents:
+		tag, err := tx.Exec(ctx, `UPDATE events SET deleted_at = NULL WHERE id = ANY($1) AND deleted_at IS NOT NULL`, a.EventIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		restored = tag.RowsAffected()
+	case models.UndoCancelEvents:
+		tag, err := tx.Exec(ctx, `UPDATE events SET cancelled_at = NULL, updated_at = now() WHERE id = ANY($1) AND cancelled_at IS NOT NULL`, a.EventIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		restored = tag.RowsAffected()
+	case models.UndoRevokeInvite:
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO event_participants
+			SELECT * FROM jsonb_populate_record(NULL::event_participants, $1)
+			ON CONFLICT DO NOTHING
+		`, a.Payload)
+		if err != nil {
+			return nil, 0, err
+		}
+		restored = tag.RowsAffected()
+		if restored > 0 {
+			if _, err := tx.Exec(ctx, `
+				DELETE FROM revoked_invites WHERE event_id = $1 AND user_id = ($2::jsonb ->> 'user_id')::int
+			`, a.EventIDs[0], a.Payload); err != nil {
+				return nil, 0, err
+			}
+		}
+	default:
+		return nil, 0, fmt.Errorf("unknown undo action %q", a.Action)
+	}
+	return &a, restored, tx.Commit(ctx)
+}
+
+// Purge removes expired undo tokens and the deleted events whose undo window
+// ended before deletedBefore, and returns how many events it removed. Events
+// that issued receipts stay deleted but are kept, as the invoices' record.
+func (r *undoRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM undo_actions WHERE expires_at <= now()`); err != nil {
+		return 0, err
+	}
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM events e
+		WHERE e.deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM receipts rc WHERE rc.event_id = e.id)
+	`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
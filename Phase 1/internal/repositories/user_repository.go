@@ -5,22 +5,27 @@ import (
 	"errors"
 	"time"
 
+	"eventplanner-backend/internal/database"
 	"eventplanner-backend/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, name, email, passwordHash string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Search(ctx context.Context, requesterID int, q string, limit int) ([]models.UserSummary, error)
+	SetPreferredCurrency(ctx context.Context, userID int, currency string) error
+	PreferredCurrency(ctx context.Context, userID int) (string, error)
+	SetLocale(ctx context.Context, userID int, locale string) error
+	Locales(ctx context.Context, userIDs []int) (map[int]string, error)
 }
 
 type userRepository struct {
-	pool *pgxpool.Pool
+	pool *database.DB
 }
 
-func NewUserRepository(pool *pgxpool.Pool) UserRepository {
+func NewUserRepository(pool *database.DB) UserRepository {
 	return &userRepository{pool: pool}
 }
 
@@ -56,7 +61,94 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	return &u, nil
 }
 
+// Search finds users other than the requester whose email is q, ignoring
+// case, or whose name contains q and who share an event with the requester.
+// Email matches come first, then names starting with q.
+func (r *userRepository) Search(ctx context.Context, requesterID int, q string, limit int) ([]models.UserSummary, error) {
+	const query = `
+		SELECT u.id, u.name, CASE WHEN lower(u.email) = lower($1) THEN u.email ELSE '' END
+		FROM users u
+		WHERE u.id <> $2 AND (
+			lower(u.email) = lower($1)
+			OR (u.name ILIKE $3 AND EXISTS (
+				SELECT 1
+				FROM event_participants mine
+				JOIN event_participants theirs ON theirs.event_id = mine.event_id
+				WHERE mine.user_id = $2 AND theirs.user_id = u.id
+			))
+		)
+		ORDER BY lower(u.email) = lower($1) DESC, u.name ILIKE $4 DESC, u.name, u.id
+		LIMIT $5
+	`
+	prefix := likeEscaper.Replace(q) + "%"
+	rows, err := r.pool.Query(ctx, query, q, requesterID, containsPattern(q), prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.UserSummary{}
+	for rows.Next() {
+		var u models.UserSummary
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
+
 // Utility to set a default timeout on queries
 func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, 5*time.Second)
 }
+
+func (r *userRepository) SetPreferredCurrency(ctx context.Context, userID int, currency string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET preferred_currency = $2, updated_at = now() WHERE id = $1`, userID, currency)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// PreferredCurrency returns the user's report currency, or "" when unset.
+func (r *userRepository) PreferredCurrency(ctx context.Context, userID int) (string, error) {
+	var currency *string
+	err := r.pool.QueryRow(ctx, `SELECT preferred_currency FROM users WHERE id = $1`, userID).Scan(&currency)
+	if err != nil || currency == nil {
+		return "", err
+	}
+	return *currency, nil
+}
+
+func (r *userRepository) SetLocale(ctx context.Context, userID int, locale string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET locale = $2, updated_at = now() WHERE id = $1`, userID, locale)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Locales returns the languages of the users who set one.
+func (r *userRepository) Locales(ctx context.Context, userIDs []int) (map[int]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, locale FROM users WHERE id = ANY($1) AND locale IS NOT NULL`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := map[int]string{}
+	for rows.Next() {
+		var id int
+		var locale string
+		if err := rows.Scan(&id, &locale); err != nil {
+			return nil, err
+		}
+		res[id] = locale
+	}
+	return res, rows.Err()
+}
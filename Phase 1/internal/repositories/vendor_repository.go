@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type VendorRepository interface {
+	Create(ctx context.Context, v models.Vendor) (*models.Vendor, error)
+	Update(ctx context.Context, v models.Vendor) (*models.Vendor, error)
+	Get(ctx context.Context, eventID, vendorID int) (*models.Vendor, error)
+	List(ctx context.Context, eventID int) ([]models.Vendor, error)
+	Delete(ctx context.Context, eventID, vendorID int) error
+}
+
+type vendorRepository struct {
+	pool *database.DB
+}
+
+func NewVendorRepository(pool *database.DB) VendorRepository {
+	return &vendorRepository{pool: pool}
+}
+
+// vendorTaskIDs lists a vendor's linked tasks.
+const vendorTaskIDs = `COALESCE((SELECT array_agg(vt.task_id ORDER BY vt.task_id) FROM vendor_tasks vt WHERE vt.vendor_id = v.id), '{}')`
+
+const vendorColumns = `v.id, v.event_id, v.name, v.category, v.contact_name, v.email, v.phone, v.website, v.notes,
+	v.contract_status, v.contract_url, v.amount_cents, v.paid_cents, v.currency, v.payment_status, v.payment_due_at,
+	` + vendorTaskIDs + `, v.created_by, v.created_at, v.updated_at`
+
+func scanVendor(row pgx.Row) (*models.Vendor, error) {
+	var v models.Vendor
+	if err := row.Scan(&v.ID, &v.EventID, &v.Name, &v.Category, &v.ContactName, &v.Email, &v.Phone, &v.Website, &v.Notes,
+		&v.ContractStatus, &v.ContractURL, &v.AmountCents, &v.PaidCents, &v.Currency, &v.PaymentStatus, &v.PaymentDueAt,
+		&v.TaskIDs, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Create adds a vendor and links its tasks. Linking a task of another event
+// fails with a foreign key violation.
+func (r *vendorRepository) Create(ctx context.Context, v models.Vendor) (*models.Vendor, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO vendors (event_id, name, category, contact_name, email, phone, website, notes,
+			contract_status, contract_url, amount_cents, paid_cents, currency, payment_status, payment_due_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`, v.EventID, v.Name, v.Category, v.ContactName, v.Email, v.Phone, v.Website, v.Notes,
+		v.ContractStatus, v.ContractURL, v.AmountCents, v.PaidCents, v.Currency, v.PaymentStatus, v.PaymentDueAt, v.CreatedBy).Scan(&v.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.finish(ctx, tx, v)
+}
+
+// Update replaces a vendor's details and linked tasks; pgx.ErrNoRows if the
+// vendor does not belong to the event.
+func (r *vendorRepository) Update(ctx context.Context, v models.Vendor) (*models.Vendor, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE vendors
+		SET name = $3, category = $4, contact_name = $5, email = $6, phone = $7, website = $8, notes = $9,
+			contract_status = $10, contract_url = $11, amount_cents = $12, paid_cents = $13, currency = $14,
+			payment_status = $15, payment_due_at = $16, updated_at = now()
+		WHERE id = $1 AND event_id = $2
+	`, v.ID, v.EventID, v.Name, v.Category, v.ContactName, v.Email, v.Phone, v.Website, v.Notes,
+		v.ContractStatus, v.ContractURL, v.AmountCents, v.PaidCents, v.Currency, v.PaymentStatus, v.PaymentDueAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM vendor_tasks WHERE vendor_id = $1`, v.ID); err != nil {
+		return nil, err
+	}
+	return r.finish(ctx, tx, v)
+}
+
+// finish links the vendor's tasks, commits and returns the stored vendor.
+func (r *vendorRepository) finish(ctx context.Context, tx pgx.Tx, v models.Vendor) (*models.Vendor, error) {
+	if len(v.TaskIDs) > 0 {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO vendor_tasks (vendor_id, task_id, event_id)
+			SELECT $1, id, $2 FROM unnest($3::int[]) AS t(id)
+		`, v.ID, v.EventID, v.TaskIDs); err != nil {
+			return nil, err
+		}
+	}
+	saved, err := scanVendor(tx.QueryRow(ctx, `SELECT `+vendorColumns+` FROM vendors v WHERE v.id = $1`, v.ID))
+	if err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit(ctx)
+}
+
+func (r *vendorRepository) Get(ctx context.Context, eventID, vendorID int) (*models.Vendor, error) {
+	q := `SELECT ` + vendorColumns + ` FROM vendors v WHERE v.id = $1 AND v.event_id = $2`
+	return scanVendor(r.pool.QueryRow(ctx, q, vendorID, eventID))
+}
+
+// List returns the event's vendors by category and name.
+func (r *vendorRepository) List(ctx context.Context, eventID int) ([]models.Vendor, error) {
+	q := `SELECT ` + vendorColumns + ` FROM vendors v WHERE v.event_id = $1 ORDER BY v.category, lower(v.name), v.id`
+	rows, err := r.pool.Query(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []models.Vendor{}
+	for rows.Next() {
+		v, err := scanVendor(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *v)
+	}
+	return res, rows.Err()
+}
+
+func (r *vendorRepository) Delete(ctx context.Context, eventID, vendorID int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM vendors WHERE id = $1 AND event_id = $2`, vendorID, eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+type VenueRepository interface {
+	Create(ctx context.Context, v models.Venue) (*models.Venue, error)
+	GetByID(ctx context.Context, id int) (*models.Venue, error)
+	ListByCreator(ctx context.Context, userID int) ([]models.Venue, error)
+}
+
+type venueRepository struct {
+	pool *database.DB
+}
+
+func NewVenueRepository(pool *database.DB) VenueRepository {
+	return &venueRepository{pool: pool}
+}
+
+func (r *venueRepository) Create(ctx context.Context, v models.Venue) (*models.Venue, error) {
+	const q = `
+		INSERT INTO venues (name, address, latitude, longitude, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, name, address, latitude, longitude, created_by, created_at, updated_at
+	`
+	var out models.Venue
+	if err := r.pool.QueryRow(ctx, q, v.Name, v.Address, v.Latitude, v.Longitude, v.CreatedBy).Scan(
+		&out.ID, &out.Name, &out.Address, &out.Latitude, &out.Longitude, &out.CreatedBy, &out.CreatedAt, &out.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *venueRepository) GetByID(ctx context.Context, id int) (*models.Venue, error) {
+	const q = `
+		SELECT id, name, address, latitude, longitude, created_by, created_at, updated_at
+		FROM venues
+		WHERE id = $1
+	`
+	var v models.Venue
+	if err := r.pool.QueryRow(ctx, q, id).Scan(&v.ID, &v.Name, &v.Address, &v.Latitude, &v.Longitude, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *venueRepository) ListByCreator(ctx context.Context, userID int) ([]models.Venue, error) {
+	const q = `
+		SELECT id, name, address, latitude, longitude, created_by, created_at, updated_at
+		FROM venues
+		WHERE created_by = $1
+		ORDER BY name
+	`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.Venue
+	for rows.Next() {
+		var v models.Venue
+		if err := rows.Scan(&v.ID, &v.Name, &v.Address, &v.Latitude, &v.Longitude, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, rows.Err()
+}
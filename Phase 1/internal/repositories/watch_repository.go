@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/models"
+)
+
+// WatchRepository stores who watches which events and tasks.
+type WatchRepository interface {
+	WatchEvent(ctx context.Context, eventID, userID int, watch bool) error
+	WatchTask(ctx context.Context, eventID, taskID, userID int, watch bool) (bool, error)
+	Watching(ctx context.Context, eventID, userID int) (*models.Watching, error)
+	EventWatchers(ctx context.Context, eventID int) ([]models.Participant, error)
+	TaskWatchers(ctx context.Context, eventID, taskID int) ([]models.Participant, error)
+}
+
+type watchRepository struct {
+	pool *database.DB
+}
+
+func NewWatchRepository(pool *database.DB) WatchRepository {
+	return &watchRepository{pool: pool}
+}
+
+// WatchEvent starts or stops the user watching the event.
+func (r *watchRepository) WatchEvent(ctx context.Context, eventID, userID int, watch bool) error {
+	q := `DELETE FROM event_watchers WHERE event_id = $1 AND user_id = $2`
+	if watch {
+		q = `INSERT INTO event_watchers (event_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	_, err := r.pool.Exec(ctx, q, eventID, userID)
+	return err
+}
+
+// WatchTask starts or stops the user watching the event's task, reporting
+// whether the event has the task.
+func (r *watchRepository) WatchTask(ctx context.Context, eventID, taskID, userID int, watch bool) (bool, error) {
+	var found bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND event_id = $2)`, taskID, eventID).Scan(&found); err != nil || !found {
+		return false, err
+	}
+	q := `DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2`
+	if watch {
+		q = `INSERT INTO task_watchers (task_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	_, err := r.pool.Exec(ctx, q, taskID, userID)
+	return true, err
+}
+
+// Watching returns whether the user watches the event and which of its
+// tasks.
+func (r *watchRepository) Watching(ctx context.Context, eventID, userID int) (*models.Watching, error) {
+	const q = `
+		SELECT
+			EXISTS (SELECT 1 FROM event_watchers WHERE event_id = $1 AND user_id = $2),
+			COALESCE((
+				SELECT array_agg(w.task_id ORDER BY w.task_id)
+				FROM task_watchers w JOIN tasks t ON t.id = w.task_id
+				WHERE t.event_id = $1 AND w.user_id = $2
+			), '{}')
+	`
+	w := models.Watching{EventID: eventID}
+	if err := r.pool.QueryRow(ctx, q, eventID, userID).Scan(&w.Event, &w.TaskIDs); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// EventWatchers returns the event's watchers who still participate in it.
+func (r *watchRepository) EventWatchers(ctx context.Context, eventID int) ([]models.Participant, error) {
+	const q = `
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		FROM event_watchers w
+		JOIN event_participants p ON p.event_id = w.event_id AND p.user_id = w.user_id
+		JOIN users u ON u.id = w.user_id
+		WHERE w.event_id = $1
+	`
+	return r.participants(ctx, q, eventID)
+}
+
+// TaskWatchers returns the watchers of the event's task, and its assignee,
+// who still participate in the event.
+func (r *watchRepository) TaskWatchers(ctx context.Context, eventID, taskID int) ([]models.Participant, error) {
+	const q = `
+		SELECT p.event_id, p.user_id, u.name, u.email, p.role, p.attendance
+		FROM event_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.event_id = $1 AND p.user_id IN (
+			SELECT user_id FROM task_watchers WHERE task_id = $2
+			UNION
+			SELECT assignee_id FROM tasks WHERE id = $2 AND event_id = $1
+		)
+	`
+	return r.participants(ctx, q, eventID, taskID)
+}
+
+func (r *watchRepository) participants(ctx context.Context, q string, args ...any) ([]models.Participant, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []models.Participant
+	for rows.Next() {
+		var p models.Participant
+		if err := rows.Scan(&p.EventID, &p.UserID, &p.UserName, &p.UserEmail, &p.Role, &p.Attendance); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
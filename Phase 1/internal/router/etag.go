@@ -0,0 +1,80 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// conditional answers GET requests with a weak ETag computed from the
+// response body, and with 304 Not Modified when it matches the request's
+// If-None-Match. The body is still built on every request, but polling
+// clients only download it when it changed. The tag is the same whatever
+// the content encoding, which weak ETags allow.
+func conditional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.Status() != http.StatusOK {
+			w.ResponseWriter.Write(w.body)
+			return
+		}
+		sum := sha256.Sum256(w.body)
+		etag := `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+		h := w.Header()
+		h.Set("ETag", etag)
+		if h.Get("Cache-Control") == "" {
+			// Responses depend on the caller, and clients should check back
+			// every time.
+			h.Set("Cache-Control", "private, no-cache")
+		}
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			h.Del("Content-Type")
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		w.ResponseWriter.Write(w.body)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison of RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the whole response body back until the handler is
+// done.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body []byte
+}
+
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	w.body = append(w.body, p...)
+	return len(p), nil
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush does nothing, since the body is only sent once it is complete.
+func (w *bufferedWriter) Flush() {}
@@ -0,0 +1,74 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"eventplanner-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localize answers in the language the client prefers by its Accept-Language
+// header, which handlers find as "locale" in the context. Error messages are
+// translated on their way out, so handlers keep writing them in English.
+func localize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set("locale", locale)
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		if locale == i18n.Default {
+			c.Next()
+			return
+		}
+		w := &errorWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		if w.held {
+			w.ResponseWriter.Write(translateError(locale, w.body))
+		}
+	}
+}
+
+// translateError translates the "error" field of a JSON error response.
+// Bodies of another shape are returned as they are.
+func translateError(locale string, body []byte) []byte {
+	var res map[string]any
+	if err := json.Unmarshal(body, &res); err != nil {
+		return body
+	}
+	msg, ok := res["error"].(string)
+	if !ok {
+		return body
+	}
+	res["error"] = i18n.Translate(locale, msg)
+	out, err := json.Marshal(res)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// errorWriter holds back the body of JSON error responses until the handler
+// is done; other responses are written through.
+type errorWriter struct {
+	gin.ResponseWriter
+	body []byte
+	held bool
+}
+
+func (w *errorWriter) Write(p []byte) (int, error) {
+	if !w.held && (w.Status() < http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")) {
+		return w.ResponseWriter.Write(p)
+	}
+	w.held = true
+	w.body = append(w.body, p...)
+	return len(p), nil
+}
+
+func (w *errorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
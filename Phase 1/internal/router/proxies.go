@@ -0,0 +1,66 @@
+package router
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyConfig says which reverse proxies and load balancers may report the
+// client's address. Requests from anywhere else keep their connection's
+// address, so clients cannot spoof their IP with X-Forwarded-For.
+type ProxyConfig struct {
+	// Trusted are the proxies' IPs or CIDR ranges.
+	Trusted []string
+	// Headers carry the client IP from a trusted proxy, first match wins.
+	Headers []string
+	// Platform is a header set by the hosting platform that is trusted
+	// regardless of Trusted, e.g. CF-Connecting-IP behind Cloudflare.
+	Platform string
+}
+
+// ProxyConfigFromEnv reads TRUSTED_PROXIES and REMOTE_IP_HEADERS (both
+// separated by commas, headers defaulting to X-Forwarded-For and X-Real-IP)
+// and TRUSTED_PLATFORM ("cloudflare" or "appengine"). No proxy is trusted
+// unless configured.
+func ProxyConfigFromEnv() ProxyConfig {
+	cfg := ProxyConfig{
+		Trusted: splitList(os.Getenv("TRUSTED_PROXIES")),
+		Headers: splitList(os.Getenv("REMOTE_IP_HEADERS")),
+	}
+	if len(cfg.Headers) == 0 {
+		cfg.Headers = []string{"X-Forwarded-For", "X-Real-IP"}
+	}
+	switch platform := os.Getenv("TRUSTED_PLATFORM"); platform {
+	case "":
+	case "cloudflare":
+		cfg.Platform = gin.PlatformCloudflare
+	case "appengine":
+		cfg.Platform = gin.PlatformGoogleAppEngine
+	default:
+		log.Printf("invalid TRUSTED_PLATFORM %q, ignoring it", platform)
+	}
+	return cfg
+}
+
+// TrustProxies makes c.ClientIP, and with it the request log and per-client
+// rate limits, resolve the client's address as cfg says. It fails for a
+// malformed proxy address.
+func TrustProxies(r *gin.Engine, cfg ProxyConfig) error {
+	r.RemoteIPHeaders = cfg.Headers
+	r.TrustedPlatform = cfg.Platform
+	return r.SetTrustedProxies(cfg.Trusted)
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -1,27 +1,35 @@
 package router
 
 import (
+	"math"
+	"net/http"
 	"strconv"
 	"time"
 
+	"eventplanner-backend/internal/database"
 	"eventplanner-backend/internal/handlers"
+	"eventplanner-backend/internal/ratelimit"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func New(auth *handlers.AuthHandler, events *handlers.EventHandler, search *handlers.SearchHandler) *gin.Engine {
+func New(live *Live, breaker *database.Breaker, auth *handlers.AuthHandler, users *handlers.UserHandler, events *handlers.EventHandler, taskTemplates *handlers.TaskTemplateHandler, search *handlers.SearchHandler, savedSearches *handlers.SavedSearchHandler, venues *handlers.VenueHandler, notifications *handlers.NotificationHandler, tickets *handlers.TicketHandler, sessions *handlers.SessionHandler, speakers *handlers.SpeakerHandler, vendors *handlers.VendorHandler, supplies *handlers.SupplyHandler, boards *handlers.BoardHandler, taskLabels *handlers.TaskLabelHandler, timeEntries *handlers.TimeEntryHandler, watches *handlers.WatchHandler, comments *handlers.CommentHandler, rides *handlers.RideHandler, accommodation *handlers.AccommodationHandler, feedback *handlers.FeedbackHandler, certificates *handlers.CertificateHandler, series *handlers.SeriesHandler, follows *handlers.FollowHandler, quotas *handlers.QuotaHandler, reports *handlers.ReportHandler, undo *handlers.UndoHandler, proposals *handlers.ProposalHandler, inboundWebhooks *handlers.InboundWebhookHandler, integrations *handlers.IntegrationHandler, mailIn *handlers.MailInHandler, branding *handlers.BrandingHandler, deliveries *handlers.DeliveryHandler, stats *handlers.StatsHandler, featureFlags *handlers.FeatureFlagHandler, runtimeConfig *handlers.ConfigHandler, database *handlers.DatabaseHandler, public *handlers.PublicHandler, graphql *handlers.GraphQLHandler, docs *handlers.DocsHandler) *gin.Engine {
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-User-ID"},
+		AllowOriginFunc:  live.allowOrigin,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-User-ID", handlers.APIKeyHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	r.Use(compress())
+	r.Use(localize())
+	r.Use(shedWhileDown(breaker))
+
 	r.Use(func(c *gin.Context) {
 		if h := c.GetHeader("X-User-ID"); h != "" {
 			if id, err := strconv.Atoi(h); err == nil {
@@ -30,22 +38,322 @@ func New(auth *handlers.AuthHandler, events *handlers.EventHandler, search *hand
 		}
 		c.Next()
 	})
+	// API keys sign in integrations as the key's owner
+	r.Use(integrations.Authenticate)
 
-	r.POST("/signup", auth.Signup)
-	r.POST("/login", auth.Login)
+	authLimit := perClient(live.limiter(limitAuth))
+	r.POST("/signup", authLimit, auth.Signup)
+	r.POST("/login", authLimit, auth.Login)
 	r.GET("/health", auth.Health)
+	r.GET("/metrics", handlers.NewMetricsHandler(live.metricsToken).Metrics)
+	// Users
+	r.GET("/users/search", perUser(live.limiter(limitUserSearch)), users.Search)
+	r.GET("/users/me/blocks", users.ListBlocks)
+	r.POST("/users/me/blocks", users.Block)
+	r.DELETE("/users/me/blocks/:id", users.Unblock)
+	r.PUT("/users/me/currency", users.SetCurrency)
+	r.PUT("/users/me/locale", users.SetLocale)
+	r.GET("/users/me/branding", branding.Get)
+	r.PUT("/users/me/branding", branding.Set)
+	r.GET("/users/me/following", follows.Following)
+	r.PUT("/users/me/following/organizers/:id", follows.FollowOrganizer)
+	r.DELETE("/users/me/following/organizers/:id", follows.UnfollowOrganizer)
+	r.PUT("/users/me/following/series/:id", follows.FollowSeries)
+	r.DELETE("/users/me/following/series/:id", follows.UnfollowSeries)
+	r.GET("/users/me/followers", follows.Followers)
+	r.GET("/users/me/quotas", quotas.Usage)
+	r.GET("/users/me/stats", stats.Me)
+	r.GET("/users/me/flags", featureFlags.Mine)
 	// Events
 	r.POST("/events", events.Create)
-	r.GET("/events/organized", events.ListOrganized)
-	r.GET("/events/invited", events.ListInvited)
+	r.GET("/events", conditional(), events.List)
+	r.GET("/events/organized", conditional(), events.ListOrganized)
+	r.GET("/events/invited", conditional(), events.ListInvited)
+	r.GET("/events/by-slug/:slug", events.GetBySlug)
+	r.POST("/events/bulk", events.Bulk)
 	r.POST("/events/:id/invite", events.Invite)
+	r.POST("/events/:id/invites/bulk", events.InviteMany)
+	r.GET("/events/:id/invitation-preview", branding.PreviewInvitation)
+	r.GET("/events/:id/deliveries", deliveries.List)
+	r.DELETE("/events/:id/invites/:userId", events.RevokeInvite)
+	r.PATCH("/events/:id", events.Update)
+	r.POST("/events/:id/lock", events.Lock)
+	r.GET("/events/:id/lock", events.GetLock)
+	r.DELETE("/events/:id/lock", events.Unlock)
 	r.DELETE("/events/:id", events.Delete)
-	r.GET("/events/:id/attendees", events.Participants)
+	r.POST("/events/:id/publish", events.Publish)
+	r.DELETE("/events/:id/publish", events.Unpublish)
+	r.POST("/events/:id/archive", events.Archive)
+	r.DELETE("/events/:id/archive", events.Unarchive)
+	r.POST("/events/:id/reschedule", events.Reschedule)
+	r.GET("/events/:id/attendees", conditional(), events.Participants)
+	r.GET("/events/:id/attendees/reliability", stats.Reliability)
+	r.GET("/events/:id/attendees/counts", events.AttendanceCounts)
+	r.GET("/events/:id/funnel", stats.Funnel)
 	r.PUT("/events/:id/attendance", events.SetAttendance)
 	r.PUT("/events/:id/accept", events.AcceptInvite)
+	r.PUT("/events/:id/mute", events.Mute)
+	r.DELETE("/events/:id/mute", events.Unmute)
+	r.POST("/events/:id/nudges", events.Nudge)
+	r.GET("/events/:id/tasks", events.ListTasks)
 	r.POST("/events/:id/tasks", events.CreateTask)
+	r.POST("/events/:id/tasks/bulk", events.CreateTasks)
+	r.POST("/events/:id/tasks/import", events.ImportTasks)
+	r.PUT("/events/:id/tasks/order", events.OrderTasks)
+	r.PATCH("/events/:id/tasks/:taskId", events.UpdateTask)
+	r.PUT("/events/:id/tasks/:taskId/labels", taskLabels.SetTaskLabels)
+	r.POST("/events/:id/tasks/:taskId/timer/start", timeEntries.StartTimer)
+	r.POST("/events/:id/tasks/:taskId/timer/stop", timeEntries.StopTimer)
+	r.GET("/events/:id/tasks/:taskId/time-entries", timeEntries.List)
+	r.POST("/events/:id/tasks/:taskId/time-entries", timeEntries.Add)
+	r.DELETE("/events/:id/tasks/:taskId/time-entries/:entryId", timeEntries.Delete)
+	r.GET("/events/:id/time", timeEntries.Totals)
+	r.GET("/events/:id/watch", watches.Watching)
+	r.PUT("/events/:id/watch", watches.WatchEvent)
+	r.DELETE("/events/:id/watch", watches.UnwatchEvent)
+	r.PUT("/events/:id/tasks/:taskId/watch", watches.WatchTask)
+	r.DELETE("/events/:id/tasks/:taskId/watch", watches.UnwatchTask)
+	r.GET("/events/:id/comments", comments.List)
+	r.POST("/events/:id/comments", comments.Create)
+	r.GET("/events/:id/comments/:commentId", comments.Get)
+	r.DELETE("/events/:id/comments/:commentId", comments.Delete)
+	r.PUT("/events/:id/comments/:commentId/reactions/:emoji", comments.AddReaction)
+	r.DELETE("/events/:id/comments/:commentId/reactions/:emoji", comments.RemoveReaction)
+	r.GET("/events/:id/tasks/:taskId/comments", comments.ListTask)
+	r.POST("/events/:id/tasks/:taskId/comments", comments.CreateTask)
+	r.GET("/events/:id/labels", taskLabels.List)
+	r.POST("/events/:id/labels", taskLabels.Create)
+	r.PATCH("/events/:id/labels/:labelId", taskLabels.Update)
+	r.DELETE("/events/:id/labels/:labelId", taskLabels.Delete)
+	r.GET("/events/:id/board", boards.Get)
+	r.PUT("/events/:id/board/columns", boards.SetColumns)
+	r.PUT("/events/:id/board/tasks/:taskId", boards.MoveTask)
+	r.POST("/users/me/task-templates", taskTemplates.Create)
+	r.GET("/users/me/task-templates", taskTemplates.List)
+	r.GET("/users/me/task-templates/:id", taskTemplates.Get)
+	r.PUT("/users/me/task-templates/:id", taskTemplates.Update)
+	r.DELETE("/users/me/task-templates/:id", taskTemplates.Delete)
+	r.GET("/events/:id/roles", events.ListRoles)
+	r.POST("/events/:id/roles", events.CreateRole)
+	r.DELETE("/events/:id/roles/:name", events.DeleteRole)
+	r.GET("/events/:id/questions", events.ListQuestions)
+	r.POST("/events/:id/questions", events.CreateQuestion)
+	r.DELETE("/events/:id/questions/:questionId", events.DeleteQuestion)
+	r.GET("/events/:id/responses", events.ExportResponses)
+	r.POST("/events/:id/announcements", events.Announce)
+	r.GET("/events/:id/announcements", events.ListAnnouncements)
+	r.PUT("/events/:id/announcements/:announcementId/reactions/:emoji", events.AddAnnouncementReaction)
+	r.DELETE("/events/:id/announcements/:announcementId/reactions/:emoji", events.RemoveAnnouncementReaction)
+	r.GET("/calendar", conditional(), events.Calendar)
+	// Ticketing
+	r.POST("/events/:id/tiers", tickets.CreateTier)
+	r.GET("/events/:id/tiers", tickets.ListTiers)
+	r.PUT("/events/:id/tiers/:tierId", tickets.UpdateTier)
+	r.GET("/events/:id/tickets/summary", tickets.SalesSummary)
+	r.POST("/events/:id/tiers/:tierId/claim", tickets.Claim)
+	r.POST("/events/:id/promo-codes", tickets.CreatePromoCode)
+	r.GET("/events/:id/promo-codes", tickets.ListPromoCodes)
+	r.PUT("/events/:id/promo-codes/:codeId", tickets.UpdatePromoCode)
+	r.DELETE("/events/:id/promo-codes/:codeId", tickets.DeletePromoCode)
+	r.GET("/events/:id/promo-codes/:codeId/redemptions", tickets.ListRedemptions)
+	r.PUT("/events/:id/transfers", tickets.SetTransferPolicy)
+	r.PUT("/events/:id/refund-policy", tickets.SetRefundPolicy)
+	r.GET("/events/:id/refund-policy", tickets.RefundPolicy)
+	r.POST("/events/:id/transfer", tickets.Transfer)
+	r.GET("/tickets", tickets.ListMine)
+	r.GET("/tickets/:id/receipt", tickets.Receipt)
+	r.DELETE("/tickets/:id", tickets.Cancel)
+	r.POST("/tickets/:id/refund", tickets.Refund)
+	r.GET("/tickets/:id/refund-quote", tickets.RefundQuote)
+	r.POST("/payments/webhook", tickets.PaymentWebhook)
+	// Agenda
+	r.POST("/events/:id/sessions", sessions.Create)
+	r.GET("/events/:id/sessions", sessions.List)
+	r.PUT("/events/:id/sessions/:sessionId", sessions.Update)
+	r.DELETE("/events/:id/sessions/:sessionId", sessions.Delete)
+	r.GET("/events/:id/agenda", sessions.Agenda)
+	r.PUT("/events/:id/agenda/:sessionId", sessions.Attend)
+	r.DELETE("/events/:id/agenda/:sessionId", sessions.Leave)
+	// Speakers
+	r.POST("/speakers", speakers.Create)
+	r.GET("/speakers", speakers.List)
+	r.GET("/speakers/:id", speakers.Get)
+	r.PUT("/speakers/:id", speakers.Update)
+	r.DELETE("/speakers/:id", speakers.Delete)
+	r.GET("/events/:id/speakers", speakers.ListForEvent)
+	r.PUT("/events/:id/speakers", speakers.SetEventSpeakers)
+	r.PUT("/events/:id/sessions/:sessionId/speakers", speakers.SetSessionSpeakers)
+	// Vendors
+	r.POST("/events/:id/vendors", vendors.Create)
+	r.GET("/events/:id/vendors", vendors.List)
+	r.GET("/events/:id/vendors/:vendorId", vendors.Get)
+	r.PUT("/events/:id/vendors/:vendorId", vendors.Update)
+	r.DELETE("/events/:id/vendors/:vendorId", vendors.Delete)
+	// Supplies
+	r.POST("/events/:id/supplies", supplies.Create)
+	r.GET("/events/:id/supplies", supplies.List)
+	r.PUT("/events/:id/supplies/:supplyId", supplies.Update)
+	r.DELETE("/events/:id/supplies/:supplyId", supplies.Delete)
+	r.POST("/events/:id/supplies/:supplyId/claim", supplies.Claim)
+	r.DELETE("/events/:id/supplies/:supplyId/claim", supplies.Unclaim)
+	r.PUT("/events/:id/supplies/:supplyId/purchased", supplies.SetPurchased)
+	// Rides
+	r.POST("/events/:id/rides", rides.Offer)
+	r.GET("/events/:id/rides", rides.List)
+	r.PUT("/events/:id/rides/:rideId", rides.Update)
+	r.DELETE("/events/:id/rides/:rideId", rides.Delete)
+	r.POST("/events/:id/rides/:rideId/seat", rides.Join)
+	r.DELETE("/events/:id/rides/:rideId/seat", rides.Leave)
+	// Accommodation
+	r.POST("/events/:id/lodgings", accommodation.CreateLodging)
+	r.GET("/events/:id/lodgings", accommodation.ListLodgings)
+	r.PUT("/events/:id/lodgings/:lodgingId", accommodation.UpdateLodging)
+	r.DELETE("/events/:id/lodgings/:lodgingId", accommodation.DeleteLodging)
+	r.GET("/events/:id/accommodation", accommodation.GetStay)
+	r.PUT("/events/:id/accommodation", accommodation.SetStay)
+	r.DELETE("/events/:id/accommodation", accommodation.DeleteStay)
+	r.GET("/events/:id/accommodation/occupancy", accommodation.Occupancy)
+	// Feedback
+	r.POST("/events/:id/feedback/questions", feedback.CreateQuestion)
+	r.GET("/events/:id/feedback/questions", feedback.ListQuestions)
+	r.DELETE("/events/:id/feedback/questions/:questionId", feedback.DeleteQuestion)
+	r.POST("/events/:id/feedback", feedback.Submit)
+	r.GET("/events/:id/feedback/summary", feedback.Summary)
+	// Check-ins and certificates
+	r.GET("/events/:id/check-ins", certificates.ListCheckIns)
+	r.PUT("/events/:id/check-ins/:userId", certificates.CheckIn)
+	r.DELETE("/events/:id/check-ins/:userId", certificates.UndoCheckIn)
+	r.PUT("/events/:id/certificate-template", certificates.SetTemplate)
+	r.GET("/events/:id/certificate-template", certificates.Template)
+	r.GET("/events/:id/certificate", certificates.Certificate)
+	r.GET("/events/:id/certificates", certificates.Certificates)
+	r.GET("/events/:id/report.pdf", stats.Report)
+	// Series
+	r.POST("/series", series.Create)
+	r.GET("/series", series.List)
+	r.GET("/series/:id", series.Get)
+	r.PUT("/series/:id", series.Update)
+	r.DELETE("/series/:id", series.Delete)
+	r.PUT("/series/:id/subscription", series.Subscribe)
+	r.DELETE("/series/:id/subscription", series.Unsubscribe)
+	r.GET("/series/:id/subscribers", series.Subscribers)
+	r.GET("/series/:id/followers", follows.SeriesFollowers)
+	r.PUT("/events/:id/series", series.Link)
+	r.DELETE("/events/:id/series", series.Unlink)
+	// Abuse reports
+	r.POST("/events/:id/report", reports.ReportEvent)
+	r.POST("/users/:id/report", reports.ReportUser)
+	r.GET("/admin/reports", reports.Queue)
+	r.PUT("/admin/reports/:id", reports.Review)
+	// Feature flags
+	r.GET("/admin/flags", featureFlags.List)
+	r.PUT("/admin/flags/:name", featureFlags.Set)
+	r.DELETE("/admin/flags/:name", featureFlags.Delete)
+	// Configuration
+	r.POST("/admin/config/reload", runtimeConfig.Reload)
+	r.GET("/admin/database/pool", database.PoolStats)
+	// Undo of destructive actions
+	r.POST("/undo/:token", undo.Undo)
+	// Change proposals
+	r.POST("/events/:id/proposals", proposals.Propose)
+	r.GET("/events/:id/proposals", proposals.List)
+	r.GET("/events/:id/proposals/:proposalId", proposals.Get)
+	r.POST("/events/:id/proposals/:proposalId/approve", proposals.Approve)
+	r.POST("/events/:id/proposals/:proposalId/reject", proposals.Reject)
+	r.DELETE("/events/:id/proposals/:proposalId", proposals.Withdraw)
+	// Inbound webhooks
+	r.POST("/users/me/inbound-webhooks", inboundWebhooks.Create)
+	r.GET("/users/me/inbound-webhooks", inboundWebhooks.List)
+	r.PUT("/users/me/inbound-webhooks/:id", inboundWebhooks.Update)
+	r.DELETE("/users/me/inbound-webhooks/:id", inboundWebhooks.Delete)
+	r.GET("/users/me/webhook-secret", inboundWebhooks.Secret)
+	r.POST("/users/me/webhook-secret", inboundWebhooks.RotateSecret)
+	r.POST("/hooks/:id", perClient(live.limiter(limitHooks)), inboundWebhooks.Receive)
+	// Email-in
+	r.POST("/inbound/email", perClient(live.limiter(limitHooks)), mailIn.Receive)
+	// Email delivery tracking
+	r.GET("/email/open/:token", deliveries.Open)
+	r.POST("/email/reports", perClient(live.limiter(limitHooks)), deliveries.Report)
+	// Integrations
+	r.POST("/users/me/api-keys", integrations.CreateAPIKey)
+	r.GET("/users/me/api-keys", integrations.ListAPIKeys)
+	r.DELETE("/users/me/api-keys/:id", integrations.DeleteAPIKey)
+	r.GET("/integrations/triggers", integrations.Triggers)
+	r.GET("/integrations/triggers/:key", integrations.Poll)
+	// Public landing pages
+	r.GET("/public/events/:slug", public.Event)
+	// Venues
+	r.POST("/venues", venues.Create)
+	r.GET("/venues", venues.List)
+	r.GET("/venues/:id", venues.Get)
+	// Notifications
+	r.GET("/notifications", notifications.List)
+	r.PUT("/notifications/:id/read", notifications.MarkRead)
 
 	r.GET("/search", search.Search)
+	r.POST("/users/me/saved-searches", savedSearches.Create)
+	r.GET("/users/me/saved-searches", savedSearches.List)
+	r.GET("/users/me/saved-searches/:id", savedSearches.Get)
+	r.PUT("/users/me/saved-searches/:id", savedSearches.Update)
+	r.DELETE("/users/me/saved-searches/:id", savedSearches.Delete)
+	r.GET("/users/me/saved-searches/:id/results", savedSearches.Run)
+
+	r.POST("/graphql", graphql.Query)
+
+	// API documentation
+	r.GET("/openapi.json", docs.OpenAPI)
+	r.GET("/docs", docs.UI)
 
 	return r
 }
+
+// perClient rejects requests from a client IP that exceeds the limiter's rate
+// with 429 and a Retry-After header. Behind a load balancer, the client IP
+// is only right if its address is in TRUSTED_PROXIES.
+func perClient(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, wait := limiter.Allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, try again later"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// shedWhileDown answers 503 with a Retry-After header while the database
+// circuit breaker is open, so requests fail at once instead of queueing for
+// connections. Health checks and metrics still answer.
+func shedWhileDown(breaker *database.Breaker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path := c.Request.URL.Path; path == "/health" || path == "/metrics" {
+			c.Next()
+			return
+		}
+		if ok, wait := breaker.Allow(); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service unavailable, try again later"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// perUser rejects requests from a user who exceeds the limiter's rate with
+// 429 and a Retry-After header. Anonymous requests pass through, for the
+// handler to reject.
+func perUser(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt("userID")
+		if userID == 0 {
+			c.Next()
+			return
+		}
+		if ok, wait := limiter.Allow(strconv.Itoa(userID)); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, try again later"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/ratelimit"
+)
+
+// Rate limits by name, as set in RATE_LIMITS.
+const (
+	// User search is a typeahead, so it allows short bursts of keystrokes
+	// but not scraping the user table.
+	limitUserSearch = "user_search"
+	// Signup and login are limited per client IP, which slows down password
+	// guessing without getting in the way of someone mistyping a few times.
+	limitAuth = "auth"
+	// Inbound webhooks, email-in and delivery reports are called by servers,
+	// which may send batches of deliveries, but an unauthenticated flood is
+	// cut off before it reaches the database.
+	limitHooks = "hooks"
+)
+
+var defaultRateLimits = map[string]models.RateLimit{
+	limitUserSearch: {PerSecond: 2, Burst: 20},
+	limitAuth:       {PerSecond: 0.2, Burst: 10}, // one every 5 seconds
+	limitHooks:      {PerSecond: 5, Burst: 50},
+}
+
+const defaultCORSOrigin = "http://localhost:3000"
+
+// Settings are the router settings that can change while the server runs.
+type Settings struct {
+	CORSOrigins  []string
+	RateLimits   map[string]models.RateLimit
+	MetricsToken string
+}
+
+// SettingsFromEnv reads CORS_ORIGINS, separated by commas (default
+// http://localhost:3000, * for any origin), and RATE_LIMITS, which
+// overrides the default rates by name, e.g. auth=0.5/20,hooks=10/100 for
+// requests per second and burst, and METRICS_TOKEN, which GET /metrics
+// requires when set.
+func SettingsFromEnv() (*Settings, error) {
+	s := &Settings{CORSOrigins: splitList(os.Getenv("CORS_ORIGINS")), RateLimits: map[string]models.RateLimit{}, MetricsToken: os.Getenv("METRICS_TOKEN")}
+	if len(s.CORSOrigins) == 0 {
+		s.CORSOrigins = []string{defaultCORSOrigin}
+	}
+	for name, rate := range defaultRateLimits {
+		s.RateLimits[name] = rate
+	}
+	for _, pair := range splitList(os.Getenv("RATE_LIMITS")) {
+		name, value, _ := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if _, ok := defaultRateLimits[name]; !ok {
+			return nil, fmt.Errorf("unknown rate limit %q", name)
+		}
+		perSecond, burst, _ := strings.Cut(value, "/")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(perSecond), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("rate limit %s: invalid rate %q", name, perSecond)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(burst))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("rate limit %s: invalid burst %q", name, burst)
+		}
+		s.RateLimits[name] = models.RateLimit{PerSecond: rate, Burst: n}
+	}
+	return s, nil
+}
+
+// Live holds the current settings. Apply swaps them in while requests are
+// served, which read them without locking.
+type Live struct {
+	current atomic.Pointer[Settings]
+
+	mu       sync.Mutex
+	limiters map[string][]*ratelimit.Limiter
+}
+
+// NewLive starts with s.
+func NewLive(s *Settings) *Live {
+	l := &Live{limiters: map[string][]*ratelimit.Limiter{}}
+	l.current.Store(s)
+	return l
+}
+
+// Current returns the settings in use.
+func (l *Live) Current() *Settings {
+	return l.current.Load()
+}
+
+// Apply replaces the settings. Rate limiters keep their buckets at the new
+// rates.
+func (l *Live) Apply(s *Settings) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current.Store(s)
+	for name, limiters := range l.limiters {
+		rate := s.RateLimits[name]
+		for _, limiter := range limiters {
+			limiter.SetRate(rate.PerSecond, rate.Burst)
+		}
+	}
+}
+
+// limiter returns a new limiter at the named rate, following it as the
+// settings change.
+func (l *Live) limiter(name string) *ratelimit.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rate := l.current.Load().RateLimits[name]
+	limiter := ratelimit.New(rate.PerSecond, rate.Burst)
+	l.limiters[name] = append(l.limiters[name], limiter)
+	return limiter
+}
+
+func (l *Live) metricsToken() string {
+	return l.current.Load().MetricsToken
+}
+
+// allowOrigin reports whether CORS requests from origin are allowed.
+func (l *Live) allowOrigin(origin string) bool {
+	origins := l.current.Load().CORSOrigins
+	return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+}
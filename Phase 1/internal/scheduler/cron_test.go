@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"", "expected 5 fields, got 0"},
+		{"* * * *", "expected 5 fields, got 4"},
+		{"* * * * * *", "expected 5 fields, got 6"},
+		{"@reboot", "expected 5 fields, got 1"},
+		{"60 * * * *", `minute: "60" out of range 0-59`},
+		{"* 24 * * *", `hour: "24" out of range 0-23`},
+		{"* * 0 * *", `day of month: "0" out of range 1-31`},
+		{"* * 32 * *", `day of month: "32" out of range 1-31`},
+		{"* * * 13 *", `month: "13" out of range 1-12`},
+		{"* * * * 8", `day of week: "8" out of range 0-7`},
+		{"5-1 * * * *", `"5-1" out of range`},
+		{"1- * * * *", `invalid range "1-"`},
+		{"-1 * * * *", `invalid range "-1"`},
+		{"*/0 * * * *", `invalid step in "*/0"`},
+		{"*/5/2 * * * *", `invalid step in "*/5/2"`},
+		{"a * * * *", `invalid value "a"`},
+		{"1,,2 * * * *", `invalid value ""`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.spec)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) = %v, want an error containing %q", tt.spec, err, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Santiago moves its clocks forward at midnight, so 2026-09-06 starts at 01:00.
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"strictly after", "* * * * *", utc(2027, 1, 1, 0, 0), utc(2027, 1, 1, 0, 1)},
+		{"seconds dropped", "59 23 31 12 *", time.Date(2027, 12, 31, 23, 59, 30, 0, time.UTC), utc(2028, 12, 31, 23, 59)},
+		{"macro", "@monthly", utc(2027, 12, 15, 0, 0), utc(2028, 1, 1, 0, 0)},
+
+		// Month ends.
+		{"31st skips short months", "0 0 31 * *", utc(2027, 1, 31, 12, 0), utc(2027, 3, 31, 0, 0)},
+		{"29 february waits for a leap year", "0 0 29 2 *", utc(2027, 1, 1, 0, 0), utc(2028, 2, 29, 0, 0)},
+		{"30 february never comes", "0 0 30 2 *", utc(2027, 1, 1, 0, 0), time.Time{}},
+
+		// Ranges, lists and steps.
+		{"range with step", "*/20 9-17/4 * * 1-5", utc(2027, 1, 1, 17, 45), utc(2027, 1, 4, 9, 0)},
+		{"value with step", "5/15 * * * *", utc(2027, 1, 1, 0, 51), utc(2027, 1, 1, 1, 5)},
+		{"list", "0 8,12,18 * * *", utc(2027, 1, 1, 12, 0), utc(2027, 1, 1, 18, 0)},
+		{"7 is sunday", "0 0 * * 7", utc(2027, 1, 1, 0, 0), utc(2027, 1, 3, 0, 0)},
+		{"either day field", "0 0 1,15 * 5", utc(2027, 1, 2, 0, 0), utc(2027, 1, 8, 0, 0)},
+		{"both day fields when one is *", "0 0 1 * *", utc(2027, 1, 2, 0, 0), utc(2027, 2, 1, 0, 0)},
+
+		// DST: New York moves its clocks forward at 02:00 on 2026-03-08 and
+		// back at 02:00 on 2026-11-01.
+		{"daily across spring forward", "0 9 * * *", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), time.Date(2026, 3, 8, 9, 0, 0, 0, newYork)},
+		{"skipped time does not run", "30 2 * * *", time.Date(2026, 3, 7, 3, 0, 0, 0, newYork), time.Date(2026, 3, 9, 2, 30, 0, 0, newYork)},
+		{"hourly across spring forward", "0 * * * *", time.Date(2026, 3, 8, 1, 30, 0, 0, newYork), time.Date(2026, 3, 8, 3, 0, 0, 0, newYork)},
+		{"repeated time, first", "30 1 * * *", time.Date(2026, 11, 1, 0, 0, 0, 0, newYork), time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"repeated time, again", "30 1 * * *", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(newYork), time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)},
+		{"day starting after a gap", "0 12 * * 0", time.Date(2026, 9, 5, 12, 0, 0, 0, santiago), time.Date(2026, 9, 6, 12, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := s.Next(tt.from)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) of %q = %s, want %s", tt.name, tt.from, tt.spec, got, tt.want)
+		}
+		if !got.IsZero() && got.Location() != tt.from.Location() {
+			t.Errorf("%s: Next returned a time in %s, want %s", tt.name, got.Location(), tt.from.Location())
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package scheduler runs periodic maintenance tasks on cron schedules. Each
+// occurrence of a schedule is claimed in a shared RunStore before it runs, so
+// when several server instances register the same schedules only one of them
+// executes any given occurrence. With a Locker, a run that is still going also
+// keeps every other instance from starting the next occurrence.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"eventplanner-backend/internal/locks"
+)
+
+// Task is the work done on each occurrence of a schedule.
+type Task func(ctx context.Context) error
+
+// RunStore records scheduled runs. ClaimRun reports whether the caller won the
+// occurrence of name due at scheduledFor; it must return true to exactly one
+// caller per (name, scheduledFor) pair.
+type RunStore interface {
+	ClaimRun(ctx context.Context, name string, scheduledFor time.Time) (bool, error)
+	FinishRun(ctx context.Context, name string, scheduledFor time.Time, lastError string) error
+}
+
+// DefaultTimeout bounds a single run unless Options.Timeout says otherwise.
+const DefaultTimeout = 10 * time.Minute
+
+// Options tune a Scheduler. Zero values fall back to UTC and DefaultTimeout.
+type Options struct {
+	Location *time.Location
+	Timeout  time.Duration
+	// Locker, when set, holds the lock "scheduler:<name>" for the length of
+	// each run.
+	Locker locks.Locker
+}
+
+type entry struct {
+	name     string
+	schedule Schedule
+	task     Task
+}
+
+// Scheduler fires registered tasks at the times given by their schedules.
+type Scheduler struct {
+	runs    RunStore
+	opts    Options
+	entries []entry
+}
+
+// New returns a scheduler that claims runs in runs. Register tasks with Add
+// before calling Start.
+func New(runs RunStore, opts Options) *Scheduler {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &Scheduler{runs: runs, opts: opts}
+}
+
+// Add registers task under name to run on the cron expression spec. Names
+// identify a schedule across instances and must be unique.
+func (s *Scheduler) Add(name, spec string, task Task) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	for _, e := range s.entries {
+		if e.name == name {
+			return fmt.Errorf("scheduler: %s is already registered", name)
+		}
+	}
+	s.entries = append(s.entries, entry{name: name, schedule: schedule, task: task})
+	return nil
+}
+
+// Start runs every registered schedule until ctx is cancelled. Runs of the
+// same schedule never overlap within one instance: an occurrence that falls
+// due while the previous run is still going is skipped.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, e := range s.entries {
+		go s.loop(ctx, e)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	for {
+		next := e.schedule.Next(time.Now().In(s.opts.Location))
+		if next.IsZero() {
+			log.Printf("scheduler: %s never fires again, stopping", e.name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.run(ctx, e, next)
+	}
+}
+
+// run executes the occurrence of e due at scheduledFor if this instance
+// claims it first. Occurrences that fall due while another instance is still
+// running e are skipped.
+func (s *Scheduler) run(ctx context.Context, e entry, scheduledFor time.Time) {
+	if s.opts.Locker != nil {
+		lock, err := s.opts.Locker.TryLock(ctx, "scheduler:"+e.name)
+		if errors.Is(err, locks.ErrNotAcquired) {
+			// Another instance is running this occurrence or an earlier one.
+			return
+		}
+		if err != nil {
+			log.Printf("scheduler: %s: lock failed: %v", e.name, err)
+			return
+		}
+		defer func() {
+			if err := lock.Unlock(); err != nil {
+				log.Printf("scheduler: %s: unlock failed: %v", e.name, err)
+			}
+		}()
+	}
+
+	claimed, err := s.runs.ClaimRun(ctx, e.name, scheduledFor)
+	if err != nil {
+		log.Printf("scheduler: %s: claim failed: %v", e.name, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	err = e.task(runCtx)
+	cancel()
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+		log.Printf("scheduler: %s failed: %v", e.name, err)
+	}
+
+	finishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.runs.FinishRun(finishCtx, e.name, scheduledFor, lastError); err != nil {
+		log.Printf("scheduler: %s: recording run failed: %v", e.name, err)
+	}
+}
@@ -0,0 +1,103 @@
+// Package server serves the API over HTTP, or over HTTPS with HTTP/2 for
+// deployments without a TLS-terminating proxy in front.
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Config selects how the server listens. With CertFile and KeyFile it serves
+// HTTPS with that certificate; with Domains it obtains certificates from
+// Let's Encrypt for them. Otherwise it serves plain HTTP.
+type Config struct {
+	Addr     string
+	CertFile string
+	KeyFile  string
+
+	Domains       []string
+	Email         string
+	CacheDir      string
+	ChallengeAddr string
+}
+
+// ConfigFromEnv reads SERVER_ADDR (default ":8080"), TLS_CERT_FILE and
+// TLS_KEY_FILE, or AUTOCERT_DOMAINS (separated by commas) with
+// AUTOCERT_EMAIL, AUTOCERT_CACHE_DIR (default "autocert-cache") and
+// AUTOCERT_HTTP_ADDR (default ":80").
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Addr:          os.Getenv("SERVER_ADDR"),
+		CertFile:      os.Getenv("TLS_CERT_FILE"),
+		KeyFile:       os.Getenv("TLS_KEY_FILE"),
+		Email:         os.Getenv("AUTOCERT_EMAIL"),
+		CacheDir:      os.Getenv("AUTOCERT_CACHE_DIR"),
+		ChallengeAddr: os.Getenv("AUTOCERT_HTTP_ADDR"),
+	}
+	for _, d := range strings.Split(os.Getenv("AUTOCERT_DOMAINS"), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			cfg.Domains = append(cfg.Domains, d)
+		}
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = ":8080"
+	}
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = "autocert-cache"
+	}
+	if cfg.ChallengeAddr == "" {
+		cfg.ChallengeAddr = ":80"
+	}
+	return cfg
+}
+
+// Run serves handler until the listener fails. HTTPS connections negotiate
+// HTTP/2 when the client supports it.
+func Run(handler http.Handler, cfg Config) error {
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	switch {
+	case len(cfg.Domains) > 0:
+		if cfg.CertFile != "" || cfg.KeyFile != "" {
+			return errors.New("set either TLS_CERT_FILE and TLS_KEY_FILE or AUTOCERT_DOMAINS, not both")
+		}
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Cache:      autocert.DirCache(cfg.CacheDir),
+			Email:      cfg.Email,
+		}
+		// Answers Let's Encrypt's HTTP-01 challenges and redirects everything
+		// else to HTTPS.
+		go func() {
+			challenge := &http.Server{Addr: cfg.ChallengeAddr, Handler: m.HTTPHandler(nil), ReadHeaderTimeout: 10 * time.Second}
+			if err := challenge.ListenAndServe(); err != nil {
+				log.Printf("autocert challenge listener on %s stopped: %v", cfg.ChallengeAddr, err)
+			}
+		}()
+		srv.TLSConfig = m.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+		log.Printf("serving HTTPS on %s for %s", cfg.Addr, strings.Join(cfg.Domains, ", "))
+		return srv.ListenAndServeTLS("", "")
+	case cfg.CertFile != "" || cfg.KeyFile != "":
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+		}
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		log.Printf("serving HTTPS on %s", cfg.Addr)
+		return srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+	default:
+		log.Printf("serving HTTP on %s", cfg.Addr)
+		return srv.ListenAndServe()
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type AccommodationService interface {
+	CreateLodging(ctx context.Context, eventID, userID int, req models.LodgingRequest) (*models.Lodging, error)
+	UpdateLodging(ctx context.Context, eventID, lodgingID, userID int, req models.LodgingRequest) (*models.Lodging, error)
+	ListLodgings(ctx context.Context, eventID, userID int) ([]models.Lodging, error)
+	DeleteLodging(ctx context.Context, eventID, lodgingID, userID int) error
+	SetStay(ctx context.Context, eventID, userID int, req models.StayRequest) (*models.Stay, error)
+	GetStay(ctx context.Context, eventID, userID int) (*models.Stay, error)
+	DeleteStay(ctx context.Context, eventID, userID int) error
+	Occupancy(ctx context.Context, eventID, userID int) (*models.OccupancySummary, error)
+}
+
+type accommodationService struct {
+	accommodation repositories.AccommodationRepository
+	events        repositories.EventRepository
+}
+
+func NewAccommodationService(accommodation repositories.AccommodationRepository, events repositories.EventRepository) AccommodationService {
+	return &accommodationService{accommodation: accommodation, events: events}
+}
+
+func lodgingFromRequest(eventID int, req models.LodgingRequest) models.Lodging {
+	l := models.Lodging{
+		EventID:    eventID,
+		Name:       strings.TrimSpace(req.Name),
+		Address:    strings.TrimSpace(req.Address),
+		Notes:      strings.TrimSpace(req.Notes),
+		Rooms:      req.Rooms,
+		PriceCents: req.PriceCents,
+		Currency:   strings.ToUpper(req.Currency),
+	}
+	if req.URL != "" {
+		l.URL = &req.URL
+	}
+	if l.Currency == "" {
+		l.Currency = defaultCurrency
+	}
+	// The request binding already checked the date format.
+	if req.BookingDeadline != "" {
+		deadline, _ := time.Parse("2006-01-02", req.BookingDeadline)
+		l.BookingDeadline = &deadline
+	}
+	return l
+}
+
+// CreateLodging adds a lodging option to the event (requires edit_event).
+func (s *accommodationService) CreateLodging(ctx context.Context, eventID, userID int, req models.LodgingRequest) (*models.Lodging, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.accommodation.CreateLodging(ctx, lodgingFromRequest(eventID, req))
+}
+
+// UpdateLodging replaces a lodging option (requires edit_event).
+func (s *accommodationService) UpdateLodging(ctx context.Context, eventID, lodgingID, userID int, req models.LodgingRequest) (*models.Lodging, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	l := lodgingFromRequest(eventID, req)
+	l.ID = lodgingID
+	return s.accommodation.UpdateLodging(ctx, l)
+}
+
+// ListLodgings returns the event's lodging options to any participant.
+func (s *accommodationService) ListLodgings(ctx context.Context, eventID, userID int) ([]models.Lodging, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.accommodation.ListLodgings(ctx, eventID)
+}
+
+// DeleteLodging removes a lodging option and the stays chosen there (requires
+// edit_event).
+func (s *accommodationService) DeleteLodging(ctx context.Context, eventID, lodgingID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.accommodation.DeleteLodging(ctx, eventID, lodgingID)
+}
+
+// SetStay records where and when the caller stays, replacing any earlier
+// choice. Without a lodging the caller makes their own arrangements.
+func (s *accommodationService) SetStay(ctx context.Context, eventID, userID int, req models.StayRequest) (*models.Stay, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	// The request binding already checked the date formats.
+	checkIn, _ := time.Parse("2006-01-02", req.CheckIn)
+	checkOut, _ := time.Parse("2006-01-02", req.CheckOut)
+	if !checkOut.After(checkIn) {
+		return nil, ErrInvalidStay
+	}
+	stay, err := s.accommodation.SetStay(ctx, models.Stay{
+		EventID:   eventID,
+		UserID:    userID,
+		LodgingID: req.LodgingID,
+		CheckIn:   checkIn,
+		CheckOut:  checkOut,
+		Notes:     strings.TrimSpace(req.Notes),
+	})
+	if isForeignKeyViolation(err, "accommodation_stays_lodging_id_event_id_fkey") {
+		return nil, ErrUnknownLodging
+	}
+	return stay, err
+}
+
+func (s *accommodationService) GetStay(ctx context.Context, eventID, userID int) (*models.Stay, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.accommodation.GetStay(ctx, eventID, userID)
+}
+
+func (s *accommodationService) DeleteStay(ctx context.Context, eventID, userID int) error {
+	return s.accommodation.DeleteStay(ctx, eventID, userID)
+}
+
+// Occupancy summarizes the event's accommodation for organizers (requires
+// edit_event): guests per lodging and per night, every stay, and how many
+// going participants have not chosen yet.
+func (s *accommodationService) Occupancy(ctx context.Context, eventID, userID int) (*models.OccupancySummary, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	lodgings, err := s.accommodation.ListLodgings(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	stays, err := s.accommodation.ListStays(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	undecided, err := s.accommodation.CountUndecided(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.OccupancySummary{EventID: eventID, Lodgings: []models.LodgingOccupancy{}, Stays: stays, Undecided: undecided}
+	index := map[int]int{}
+	for _, l := range lodgings {
+		index[l.ID] = len(summary.Lodgings)
+		summary.Lodgings = append(summary.Lodgings, models.LodgingOccupancy{LodgingID: &l.ID, Name: l.Name, Rooms: l.Rooms})
+	}
+	// Stays without a lodging are grouped last, as own arrangements.
+	own := -1
+	nights := map[int]map[time.Time]int{}
+	for _, st := range stays {
+		i := own
+		if st.LodgingID != nil {
+			i = index[*st.LodgingID]
+		} else if own < 0 {
+			own = len(summary.Lodgings)
+			summary.Lodgings = append(summary.Lodgings, models.LodgingOccupancy{Name: "Own arrangements"})
+			i = own
+		}
+		summary.Lodgings[i].Guests++
+		if nights[i] == nil {
+			nights[i] = map[time.Time]int{}
+		}
+		for night := st.CheckIn; night.Before(st.CheckOut); night = night.AddDate(0, 0, 1) {
+			nights[i][night]++
+		}
+	}
+	for i := range summary.Lodgings {
+		occ := &summary.Lodgings[i]
+		occ.Nights = nightsInOrder(nights[i])
+		for _, n := range occ.Nights {
+			occ.PeakGuests = max(occ.PeakGuests, n.Guests)
+		}
+	}
+	return summary, nil
+}
+
+// nightsInOrder turns per-night guest counts into a list sorted by date.
+func nightsInOrder(counts map[time.Time]int) []models.NightOccupancy {
+	out := make([]models.NightOccupancy, 0, len(counts))
+	for date, guests := range counts {
+		out = append(out, models.NightOccupancy{Date: date, Guests: guests})
+	}
+	slices.SortFunc(out, func(a, b models.NightOccupancy) int { return a.Date.Compare(b.Date) })
+	return out
+}
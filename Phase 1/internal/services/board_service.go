@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type BoardService interface {
+	Get(ctx context.Context, eventID, userID int) (*models.Board, error)
+	SetColumns(ctx context.Context, eventID, userID int, req models.BoardColumnsRequest) (*models.Board, error)
+	MoveTask(ctx context.Context, eventID, taskID, userID int, req models.BoardMoveRequest) (*models.Board, error)
+}
+
+type boardService struct {
+	boards repositories.BoardRepository
+	events repositories.EventRepository
+}
+
+func NewBoardService(boards repositories.BoardRepository, events repositories.EventRepository) BoardService {
+	return &boardService{boards: boards, events: events}
+}
+
+// Get returns the event's task board to any participant. Tasks in no
+// column are shown in the first one, or in the last once completed.
+func (s *boardService) Get(ctx context.Context, eventID, userID int) (*models.Board, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.board(ctx, eventID)
+}
+
+// SetColumns renames, reorders, adds and removes the board's columns
+// (requires manage_tasks).
+func (s *boardService) SetColumns(ctx context.Context, eventID, userID int, req models.BoardColumnsRequest) (*models.Board, error) {
+	seen := map[string]bool{}
+	for i, c := range req.Columns {
+		name := strings.TrimSpace(c.Name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return nil, ErrInvalidColumns
+		}
+		seen[key] = true
+		req.Columns[i].Name = name
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	ok, err := s.boards.SetColumns(ctx, eventID, req.Columns)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrColumnNotFound
+	}
+	return s.board(ctx, eventID)
+}
+
+// MoveTask puts a task in a column, and at a position when one is given
+// (requires manage_tasks).
+func (s *boardService) MoveTask(ctx context.Context, eventID, taskID, userID int, req models.BoardMoveRequest) (*models.Board, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	columnFound, taskFound, err := s.boards.MoveTask(ctx, eventID, taskID, req.ColumnID, req.Position)
+	if err != nil {
+		return nil, err
+	}
+	if !columnFound {
+		return nil, ErrColumnNotFound
+	}
+	if !taskFound {
+		return nil, ErrTaskNotFound
+	}
+	return s.board(ctx, eventID)
+}
+
+// board puts the event's tasks, in their order, into its columns.
+func (s *boardService) board(ctx context.Context, eventID int) (*models.Board, error) {
+	columns, err := s.boards.Columns(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	byEvent, err := s.events.ListTasksByEvents(ctx, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[int]int, len(columns))
+	for i, c := range columns {
+		index[c.ID] = i
+	}
+	for _, t := range byEvent[eventID] {
+		i, ok := 0, false
+		if t.ColumnID != nil {
+			i, ok = index[*t.ColumnID]
+		}
+		if !ok && t.CompletedAt != nil {
+			i = len(columns) - 1
+		}
+		columns[i].Tasks = append(columns[i].Tasks, t)
+	}
+	return &models.Board{EventID: eventID, Columns: columns}, nil
+}
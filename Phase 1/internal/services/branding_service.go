@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/i18n"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+)
+
+// previewInviteeName stands in for the invitee in invitation previews.
+const previewInviteeName = "Alex"
+
+type BrandingService interface {
+	Get(ctx context.Context, userID int) (*models.Branding, error)
+	Set(ctx context.Context, userID int, req models.BrandingRequest) (*models.Branding, error)
+	PreviewInvitation(ctx context.Context, eventID, userID int, role, locale string) (*models.EmailPreview, error)
+}
+
+type brandingService struct {
+	branding repositories.BrandingRepository
+	events   repositories.EventRepository
+}
+
+func NewBrandingService(branding repositories.BrandingRepository, events repositories.EventRepository) BrandingService {
+	return &brandingService{branding: branding, events: events}
+}
+
+// Get returns the user's branding, empty when they have none.
+func (s *brandingService) Get(ctx context.Context, userID int) (*models.Branding, error) {
+	b, err := s.branding.Get(ctx, userID)
+	if err != nil || b != nil {
+		return b, err
+	}
+	return &models.Branding{}, nil
+}
+
+func (s *brandingService) Set(ctx context.Context, userID int, req models.BrandingRequest) (*models.Branding, error) {
+	return s.branding.Set(ctx, userID, models.Branding{LogoURL: req.LogoURL, Color: req.Color, ReplyTo: req.ReplyTo})
+}
+
+// PreviewInvitation renders the email an invitation to the event sent by the
+// caller would be, in locale and with the branding of the event's organizer.
+// Like inviting, it needs manage_participants.
+func (s *brandingService) PreviewInvitation(ctx context.Context, eventID, userID int, role, locale string) (*models.EmailPreview, error) {
+	if !i18n.IsSupported(locale) {
+		return nil, ErrUnsupportedLocale
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.events.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	inviter := "Someone"
+	for _, p := range participants {
+		if p.UserID == userID {
+			inviter = p.UserName
+		}
+	}
+	role = normalizeRole(role)
+	if role == "" {
+		role = "attendee"
+	}
+	msg := inviteMessage(inviter, event, role, nil)
+	msg.Subject = i18n.Translate(locale, msg.Subject)
+	msg.Body = i18n.Translate(locale, msg.Body)
+	msg.Locale = locale
+	branding, err := s.branding.EventBranding(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	mail, err := notifications.RenderEmail(notifications.Recipient{Name: previewInviteeName}, msg, branding)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EmailPreview{Subject: mail.Subject, ReplyTo: mail.ReplyTo, Text: mail.Text, HTML: mail.HTML}, nil
+}
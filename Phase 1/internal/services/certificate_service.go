@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CertificateService interface {
+	CheckIn(ctx context.Context, eventID, participantID, userID int) (*models.CheckIn, error)
+	UndoCheckIn(ctx context.Context, eventID, participantID, userID int) error
+	ListCheckIns(ctx context.Context, eventID, userID int) ([]models.CheckIn, error)
+	SetTemplate(ctx context.Context, eventID, userID int, req models.CertificateTemplateRequest) (*models.CertificateTemplate, error)
+	Template(ctx context.Context, eventID, userID int) (*models.CertificateTemplate, error)
+	Certificate(ctx context.Context, eventID, userID int) ([]byte, error)
+	Certificates(ctx context.Context, eventID, userID int) ([]byte, error)
+}
+
+type certificateService struct {
+	certificates repositories.CertificateRepository
+	events       repositories.EventRepository
+}
+
+func NewCertificateService(certificates repositories.CertificateRepository, events repositories.EventRepository) CertificateService {
+	return &certificateService{certificates: certificates, events: events}
+}
+
+// CheckIn marks a participant as present at the event (requires
+// manage_participants).
+func (s *certificateService) CheckIn(ctx context.Context, eventID, participantID, userID int) (*models.CheckIn, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	return s.certificates.CheckIn(ctx, eventID, participantID)
+}
+
+// UndoCheckIn clears a participant's check-in (requires manage_participants).
+func (s *certificateService) UndoCheckIn(ctx context.Context, eventID, participantID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return err
+	}
+	return s.certificates.UndoCheckIn(ctx, eventID, participantID)
+}
+
+func (s *certificateService) ListCheckIns(ctx context.Context, eventID, userID int) ([]models.CheckIn, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	return s.certificates.ListCheckIns(ctx, eventID)
+}
+
+// SetTemplate replaces the event's certificate wording (requires edit_event).
+func (s *certificateService) SetTemplate(ctx context.Context, eventID, userID int, req models.CertificateTemplateRequest) (*models.CertificateTemplate, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.certificates.SetTemplate(ctx, models.CertificateTemplate{
+		EventID:     eventID,
+		Title:       strings.TrimSpace(req.Title),
+		Body:        strings.TrimSpace(req.Body),
+		SignerName:  strings.TrimSpace(req.SignerName),
+		SignerTitle: strings.TrimSpace(req.SignerTitle),
+	})
+}
+
+// Template returns the event's certificate wording, or the default one
+// (requires edit_event).
+func (s *certificateService) Template(ctx context.Context, eventID, userID int) (*models.CertificateTemplate, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.template(ctx, eventID)
+}
+
+func (s *certificateService) template(ctx context.Context, eventID int) (*models.CertificateTemplate, error) {
+	t, err := s.certificates.GetTemplate(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return defaultCertificateTemplate(eventID), nil
+	}
+	return t, err
+}
+
+// Certificate renders the caller's own certificate as a PDF. Only
+// participants who were checked in get one.
+func (s *certificateService) Certificate(ctx context.Context, eventID, userID int) ([]byte, error) {
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkIn, err := s.certificates.GetCheckIn(ctx, eventID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotCheckedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.template(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return RenderCertificates(t, event, []models.CheckIn{*checkIn}), nil
+}
+
+// Certificates renders the certificates of every checked-in participant as
+// one PDF with a page each (requires edit_event).
+func (s *certificateService) Certificates(ctx context.Context, eventID, userID int) ([]byte, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	people, err := s.certificates.ListCheckIns(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(people) == 0 {
+		return nil, ErrNoCheckIns
+	}
+	t, err := s.template(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return RenderCertificates(t, event, people), nil
+}
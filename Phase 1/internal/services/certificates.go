@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/pdf"
+)
+
+// Wording used until organizers set their own certificate template.
+const (
+	defaultCertificateTitle = "Certificate of Attendance"
+	defaultCertificateBody  = "For attending {event} on {date}."
+)
+
+// RenderCertificates returns one landscape A4 page per participant: the
+// title, the participant's name, the body and, if set, the signer.
+func RenderCertificates(t *models.CertificateTemplate, event *models.Event, people []models.CheckIn) []byte {
+	doc := pdf.New(pdf.A4Landscape, event.Title+" certificates")
+	date := certificateDate(event)
+	for _, person := range people {
+		fill := strings.NewReplacer("{name}", person.Name, "{event}", event.Title, "{date}", date)
+		page := doc.AddPage()
+		size := page.Size()
+		page.Rect(24, 24, size.Width-48, size.Height-48, 2)
+		page.Rect(32, 32, size.Width-64, size.Height-64, 0.5)
+		page.TextCentered(440, pdf.Bold, 34, fill.Replace(t.Title))
+		page.TextCentered(360, pdf.Bold, 28, person.Name)
+		y := 310.0
+		for _, line := range pdf.Wrap(fill.Replace(t.Body), pdf.Regular, 16, size.Width-200) {
+			page.TextCentered(y, pdf.Regular, 16, line)
+			y -= 24
+		}
+		if t.SignerName != "" {
+			center := size.Width * 3 / 4
+			page.Line(center-110, 130, center+110, 130, 0.75)
+			page.Text(center-pdf.TextWidth(t.SignerName, pdf.Regular, 12)/2, 112, pdf.Regular, 12, t.SignerName)
+			page.Text(center-pdf.TextWidth(t.SignerTitle, pdf.Regular, 10)/2, 97, pdf.Regular, 10, t.SignerTitle)
+		}
+	}
+	return doc.Bytes()
+}
+
+// certificateDate is the event's date, or its first and last day when it
+// spans several.
+func certificateDate(event *models.Event) string {
+	const layout = "January 2, 2006"
+	start := event.StartTime.Format(layout)
+	if event.EndTime == nil {
+		return start
+	}
+	if end := event.EndTime.Format(layout); end != start {
+		return start + " - " + end
+	}
+	return start
+}
+
+// defaultCertificateTemplate is the wording of events without a template.
+func defaultCertificateTemplate(eventID int) *models.CertificateTemplate {
+	return &models.CertificateTemplate{EventID: eventID, Title: defaultCertificateTitle, Body: defaultCertificateBody}
+}
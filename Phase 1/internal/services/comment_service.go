@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, eventID int, taskID *int, userID int, req models.CommentRequest) (*models.Comment, error)
+	List(ctx context.Context, eventID int, taskID *int, userID int) ([]models.Comment, error)
+	CommentsByEvents(ctx context.Context, userID int, eventIDs []int) (map[int][]models.Comment, error)
+	Get(ctx context.Context, eventID, commentID, userID int) (*models.Comment, error)
+	Delete(ctx context.Context, eventID, commentID, userID int) error
+	AddReaction(ctx context.Context, eventID, commentID, userID int, emoji string) ([]models.Reaction, error)
+	RemoveReaction(ctx context.Context, eventID, commentID, userID int, emoji string) ([]models.Reaction, error)
+}
+
+type commentService struct {
+	comments  repositories.CommentRepository
+	events    repositories.EventRepository
+	blocks    repositories.BlockRepository
+	reactions repositories.ReactionRepository
+	previews  LinkPreviewService
+	notifier  *notifications.Dispatcher
+}
+
+func NewCommentService(comments repositories.CommentRepository, events repositories.EventRepository, blocks repositories.BlockRepository, reactions repositories.ReactionRepository, previews LinkPreviewService, notifier *notifications.Dispatcher) CommentService {
+	return &commentService{comments: comments, events: events, blocks: blocks, reactions: reactions, previews: previews, notifier: notifier}
+}
+
+// mentionPattern finds @email and @name mentions. The @ must not follow a
+// letter or digit, so email addresses in the text are not read as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_])@([\p{L}\p{N}_.+-]+(?:@[\p{L}\p{N}.-]+)?)`)
+
+// mentionExcerpt is how much of a comment a mention notice quotes.
+const mentionExcerpt = 200
+
+// Create posts the caller's comment, any participant's, on the event or
+// one of its tasks, and notifies the participants it mentions.
+func (s *commentService) Create(ctx context.Context, eventID int, taskID *int, userID int, req models.CommentRequest) (*models.Comment, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrInvalidComment
+	}
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	participants, err := s.events.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	mentioned := mentions(body, participants)
+	ids := make([]int, 0, len(mentioned))
+	for _, p := range mentioned {
+		ids = append(ids, p.UserID)
+	}
+	comment, err := s.comments.Create(ctx, models.Comment{EventID: eventID, TaskID: taskID, UserID: &userID, Body: body}, ids)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.notifyMentioned(ctx, *comment, mentioned)
+	comment.Reactions = []models.Reaction{}
+	return comment, s.withPreviews(ctx, []*models.Comment{comment})
+}
+
+// List returns the comments on the event, or on one of its tasks, oldest
+// first.
+func (s *commentService) List(ctx context.Context, eventID int, taskID *int, userID int) ([]models.Comment, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	comments, err := s.comments.List(ctx, eventID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	ptrs := make([]*models.Comment, len(comments))
+	for i := range comments {
+		ptrs[i] = &comments[i]
+	}
+	if err := s.withReactions(ctx, userID, ptrs); err != nil {
+		return nil, err
+	}
+	return comments, s.withPreviews(ctx, ptrs)
+}
+
+// CommentsByEvents returns the comments on those of eventIDs the user
+// participates in, keyed by event ID, with their reactions but without link
+// previews. Comments on tasks are not included.
+func (s *commentService) CommentsByEvents(ctx context.Context, userID int, eventIDs []int) (map[int][]models.Comment, error) {
+	allowed, err := s.events.ParticipatingEventIDs(ctx, userID, eventIDs, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return map[int][]models.Comment{}, nil
+	}
+	res, err := s.comments.ListByEvents(ctx, allowed)
+	if err != nil {
+		return nil, err
+	}
+	var ptrs []*models.Comment
+	for _, id := range allowed {
+		comments := res[id]
+		if comments == nil {
+			comments = []models.Comment{}
+		}
+		for i := range comments {
+			ptrs = append(ptrs, &comments[i])
+		}
+		res[id] = comments
+	}
+	if len(ptrs) == 0 {
+		return res, nil
+	}
+	return res, s.withReactions(ctx, userID, ptrs)
+}
+
+// Get returns one comment on the event or its tasks.
+func (s *commentService) Get(ctx context.Context, eventID, commentID, userID int) (*models.Comment, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	comment, err := s.comments.Get(ctx, eventID, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.withReactions(ctx, userID, []*models.Comment{comment}); err != nil {
+		return nil, err
+	}
+	return comment, s.withPreviews(ctx, []*models.Comment{comment})
+}
+
+// Delete removes a comment: the caller's own, or anyone's with edit_event.
+func (s *commentService) Delete(ctx context.Context, eventID, commentID, userID int) error {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	m, ok := members[eventID]
+	if !ok {
+		return ErrForbidden
+	}
+	authorID := userID
+	if m.Has(models.PermEditEvent) {
+		authorID = 0
+	}
+	deleted, err := s.comments.Delete(ctx, eventID, commentID, authorID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		if authorID != 0 {
+			// someone else's comment, or none at all; say which only to
+			// those who may see it
+			if _, err := s.comments.Get(ctx, eventID, commentID); err == nil {
+				return ErrForbidden
+			}
+		}
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// AddReaction reacts to a comment on the event or its tasks with emoji
+// (any participant) and returns the comment's reactions.
+func (s *commentService) AddReaction(ctx context.Context, eventID, commentID, userID int, emoji string) ([]models.Reaction, error) {
+	return s.react(ctx, eventID, commentID, userID, emoji, true)
+}
+
+// RemoveReaction takes back the caller's reaction with emoji, if any, and
+// returns the comment's reactions.
+func (s *commentService) RemoveReaction(ctx context.Context, eventID, commentID, userID int, emoji string) ([]models.Reaction, error) {
+	return s.react(ctx, eventID, commentID, userID, emoji, false)
+}
+
+func (s *commentService) react(ctx context.Context, eventID, commentID, userID int, emoji string, add bool) ([]models.Reaction, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	_, err := s.comments.Get(ctx, eventID, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return react(ctx, s.reactions, repositories.ReactionComment, commentID, userID, emoji, add)
+}
+
+// withReactions sets the comments' reactions as userID sees them.
+func (s *commentService) withReactions(ctx context.Context, userID int, comments []*models.Comment) error {
+	ids := make([]int, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	counts, err := s.reactions.Counts(ctx, repositories.ReactionComment, ids, userID)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		c.Reactions = reactionsOf(counts, c.ID)
+	}
+	return nil
+}
+
+// withPreviews sets the previews of the pages the comments link to.
+func (s *commentService) withPreviews(ctx context.Context, comments []*models.Comment) error {
+	bodies := make([]string, len(comments))
+	for i, c := range comments {
+		bodies[i] = c.Body
+	}
+	previews, err := s.previews.Previews(ctx, bodies)
+	if err != nil {
+		return err
+	}
+	for i, c := range comments {
+		c.LinkPreviews = previews[i]
+	}
+	return nil
+}
+
+// mentions returns the participants body mentions, each once. A mention is
+// an @ followed by a participant's email, or by their name written without
+// spaces, in any case. Names shared by several participants and unknown
+// names mention nobody.
+func mentions(body string, participants []models.Participant) []models.Participant {
+	byEmail := map[string]models.Participant{}
+	byName := map[string][]models.Participant{}
+	for _, p := range participants {
+		byEmail[strings.ToLower(p.UserEmail)] = p
+		name := strings.ToLower(strings.Join(strings.Fields(p.UserName), ""))
+		byName[name] = append(byName[name], p)
+	}
+	var res []models.Participant
+	seen := map[int]bool{}
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// sentence punctuation right after a mention is not part of it
+		token := strings.ToLower(strings.TrimRight(m[1], ".-"))
+		p, ok := byEmail[token]
+		if !ok && len(byName[token]) == 1 {
+			p, ok = byName[token][0], true
+		}
+		if ok && !seen[p.UserID] {
+			seen[p.UserID] = true
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// notifyMentioned tells the participants a comment mentions, other than
+// its author and those who block the author, with a link to the comment.
+// Delivery failures are logged, the comment stands.
+func (s *commentService) notifyMentioned(ctx context.Context, comment models.Comment, mentioned []models.Participant) {
+	var to []notifications.Recipient
+	for _, p := range mentioned {
+		if p.UserID == *comment.UserID {
+			continue
+		}
+		blocked, err := s.blocks.Blocks(ctx, p.UserID, *comment.UserID)
+		if err != nil {
+			log.Printf("comment %d: checking blocks to notify mentions: %v", comment.ID, err)
+			return
+		}
+		if !blocked {
+			to = append(to, notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail})
+		}
+	}
+	if len(to) == 0 {
+		return
+	}
+	event, err := s.events.GetForParticipant(ctx, comment.EventID, *comment.UserID)
+	if err != nil {
+		log.Printf("event %d: loading event to notify mentions: %v", comment.EventID, err)
+		return
+	}
+	excerpt := comment.Body
+	if utf8.RuneCountInString(excerpt) > mentionExcerpt {
+		excerpt = string([]rune(excerpt)[:mentionExcerpt]) + "…"
+	}
+	intro := fmt.Sprintf("%s mentioned you in a comment on %s:", comment.UserName, event.Title)
+	if comment.TaskID != nil {
+		intro = fmt.Sprintf("%s mentioned you in a comment on a task of %s:", comment.UserName, event.Title)
+	}
+	// the quote gets a paragraph of its own so that translating the notice
+	// leaves it alone
+	err = s.notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "mention",
+		EventID: &comment.EventID,
+		Subject: fmt.Sprintf("%s mentioned you in %s", comment.UserName, event.Title),
+		Body:    fmt.Sprintf("%s\n\n%s\n\nSee the comment at /events/%d/comments/%d.", intro, excerpt, comment.EventID, comment.ID),
+	})
+	if err != nil {
+		log.Printf("comment %d: mention notice failed: %v", comment.ID, err)
+	}
+}
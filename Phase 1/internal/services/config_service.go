@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/broadcast"
+	"eventplanner-backend/internal/models"
+)
+
+// ReloadFunc reads the reloadable configuration again and applies it.
+type ReloadFunc func(ctx context.Context) (*models.RuntimeConfig, error)
+
+type ConfigService interface {
+	Reload(ctx context.Context, userID int) (*models.RuntimeConfig, error)
+}
+
+type configService struct {
+	reload      ReloadFunc
+	broadcaster *broadcast.Broadcaster
+	admins      map[int]bool
+}
+
+func NewConfigService(reload ReloadFunc, broadcaster *broadcast.Broadcaster, admins map[int]bool) ConfigService {
+	return &configService{reload: reload, broadcaster: broadcaster, admins: admins}
+}
+
+// Reload applies changed rate limits, CORS origins and feature flags without
+// restarting the server (admins only). Once this instance has reloaded, the
+// others are asked to reload too; the returned configuration is this
+// instance's.
+func (s *configService) Reload(ctx context.Context, userID int) (*models.RuntimeConfig, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	cfg, err := s.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.broadcaster.Broadcast(ctx, broadcast.TopicConfigReload, nil)
+	return cfg, nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+)
+
+type DatabaseService interface {
+	PoolStats(ctx context.Context, userID int) (*models.PoolStats, error)
+}
+
+type databaseService struct {
+	stats  func() models.PoolStats
+	admins map[int]bool
+}
+
+// NewDatabaseService reports the connection pool statistics read by stats.
+func NewDatabaseService(stats func() models.PoolStats, admins map[int]bool) DatabaseService {
+	return &databaseService{stats: stats, admins: admins}
+}
+
+// PoolStats returns this instance's connection pool statistics (admins
+// only).
+func (s *databaseService) PoolStats(ctx context.Context, userID int) (*models.PoolStats, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	stats := s.stats()
+	return &stats, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"os"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// DeliveryReportTokenFromEnv returns EMAIL_WEBHOOK_TOKEN, which the mail
+// provider passes when reporting what became of emails. Empty turns the
+// reports off.
+func DeliveryReportTokenFromEnv() string {
+	return os.Getenv("EMAIL_WEBHOOK_TOKEN")
+}
+
+type DeliveryService interface {
+	ListForEvent(ctx context.Context, eventID, userID int, filter models.DeliveryFilter) ([]models.EmailDelivery, error)
+	Opened(ctx context.Context, token string) error
+	Report(ctx context.Context, token string, reports []models.DeliveryReport) (int, error)
+}
+
+type deliveryService struct {
+	repo        repositories.DeliveryRepository
+	events      repositories.EventRepository
+	reportToken string
+}
+
+func NewDeliveryService(repo repositories.DeliveryRepository, events repositories.EventRepository, reportToken string) DeliveryService {
+	return &deliveryService{repo: repo, events: events, reportToken: reportToken}
+}
+
+// ListForEvent returns the emails sent about an event, so the people managing
+// its participants can tell whether invitees got them.
+func (s *deliveryService) ListForEvent(ctx context.Context, eventID, userID int, filter models.DeliveryFilter) ([]models.EmailDelivery, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	return s.repo.ListForEvent(ctx, eventID, filter)
+}
+
+// Opened records that the email with the pixel token was opened. Unknown
+// tokens are ignored.
+func (s *deliveryService) Opened(ctx context.Context, token string) error {
+	_, err := s.repo.Advance(ctx, token, models.DeliveryOpened, time.Now(), "")
+	return err
+}
+
+// Report applies the mail provider's reports and returns how many of them
+// were about a known email. Reports of emails sent before tracking, or by
+// another system using the same provider, are skipped.
+func (s *deliveryService) Report(ctx context.Context, token string, reports []models.DeliveryReport) (int, error) {
+	if s.reportToken == "" {
+		return 0, ErrTrackingDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.reportToken)) != 1 {
+		return 0, ErrInvalidReportToken
+	}
+	matched := 0
+	for _, r := range reports {
+		at := time.Now()
+		if r.At != nil {
+			at = *r.At
+		}
+		ok, err := s.repo.Advance(ctx, deliveryToken(r.ID), r.Type, at, r.Reason)
+		if err != nil {
+			return matched, err
+		}
+		if ok {
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+// deliveryToken is the token of an email from the id the provider reports
+// it by: the token itself, or a Message-ID of the form <token@domain>.
+func deliveryToken(id string) string {
+	id = strings.Trim(strings.TrimSpace(id), "<>")
+	token, _, _ := strings.Cut(id, "@")
+	return token
+}
@@ -1,10 +1,150 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 var (
 	ErrUserExists         = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidTimeRange   = errors.New("end time must be after start time")
+	ErrForbidden          = errors.New("you do not have permission to perform this action")
+	ErrUnknownRole        = errors.New("unknown role for this event")
+	ErrRoleExists         = errors.New("role already exists")
+	ErrRoleInUse          = errors.New("role is assigned to participants")
+	ErrInvalidQuestion    = errors.New("invalid RSVP question")
+	ErrInvalidAnswer      = errors.New("invalid RSVP answers")
+	ErrTierExists         = errors.New("a ticket tier with this name already exists")
+	ErrQuantityBelowSold  = errors.New("quantity cannot be lower than the number of tickets claimed")
+	ErrSoldOut            = errors.New("ticket tier is sold out")
+	ErrAlreadyHasTicket   = errors.New("you already have a ticket for this event")
+	ErrPromoCodeExists    = errors.New("a promo code with this code already exists")
+	ErrInvalidDiscount    = errors.New("percentage discounts must be between 1 and 100")
+	ErrPromoCodeInvalid   = errors.New("promo code is not valid for this event")
+	ErrPromoCodeExpired   = errors.New("promo code has expired")
+	ErrPromoCodeUsedUp    = errors.New("promo code has reached its usage limit")
+	ErrTransfersDisabled  = errors.New("spot transfers are not allowed for this event")
+	ErrOrganizerTransfer  = errors.New("the organizer cannot transfer their spot")
+	ErrTransferToSelf     = errors.New("you cannot transfer your spot to yourself")
+	ErrUserNotFound       = errors.New("no user with this email")
+	ErrAlreadyParticipant = errors.New("user already participates in this event")
+	ErrTicketPending      = errors.New("complete or cancel the pending ticket payment first")
+	ErrSessionOutOfRange  = errors.New("session must take place within the event's time")
+	ErrCapacityTooLow     = errors.New("capacity cannot be lower than the number of attendees")
+	ErrSessionFull        = errors.New("session is full")
+	ErrAlreadyInAgenda    = errors.New("session is already in your agenda")
+	ErrUnknownSpeaker     = errors.New("unknown speaker")
+	ErrSavedSearchExists  = errors.New("a saved search with this name already exists")
+	ErrRefundRequired     = errors.New("this event has no refund policy, ask an organizer for a refund")
+	ErrNotRefundable      = errors.New("ticket has no completed payment to refund")
+	ErrRefundClosed       = errors.New("the refund deadline for this ticket has passed")
+	ErrDuplicateRefundDay = errors.New("each refund rule needs a different daysBefore")
+	ErrPaymentFailed      = errors.New("payment provider request failed")
+	ErrMeetingNotAllowed  = errors.New("meeting links are only allowed for virtual or hybrid events")
+	ErrMeetingCreation    = errors.New("failed to create meeting")
+	ErrUnknownTemplate    = errors.New("unknown task template")
+	ErrNoTasks            = errors.New("no tasks to create")
+	ErrTaskTitleRequired  = errors.New("task title is required")
+	ErrTemplateExists     = errors.New("a task template with this name already exists")
+	ErrDueDateConflict    = errors.New("give either dueDate or dueOffset, not both")
+	ErrInvalidDueOffset   = errors.New(`invalid dueOffset, use days, hours and minutes relative to the event start, e.g. "-14d" or "-1d12h"`)
+	ErrTooManyTasks       = errors.New("too many tasks in one request")
+	ErrTitleRequired      = errors.New("title cannot be empty")
+	ErrInvalidEventType   = errors.New("type must be in_person, virtual or hybrid")
+	ErrExpiryInPast       = errors.New("expiresAt must be in the future")
+	ErrInviteExpired      = errors.New("this invitation has expired")
+	ErrInviteRevoked      = errors.New("this invitation was revoked")
+	ErrNoPendingInvite    = errors.New("user has no pending invitation to this event")
+	ErrInviteAnswered     = errors.New("the invitation was already answered, remove the participant instead")
+	ErrInvalidUserQuery   = errors.New("q must be between 2 and 100 characters")
+	ErrInvalidBlock       = errors.New("give either userId or a valid email domain, not both")
+	ErrBlockSelf          = errors.New("you cannot block yourself")
+	ErrBlockExists        = errors.New("this user or domain is already blocked")
+	ErrUnknownUser        = errors.New("unknown user")
+	ErrUnknownTask        = errors.New("unknown task, link tasks of the same event")
+	ErrPaidOverAmount     = errors.New("paidCents cannot exceed amountCents")
+	ErrSupplyClaimed      = errors.New("someone else already claimed this item")
+	ErrNotClaimer         = errors.New("only whoever claimed this item can change its claim")
+	ErrRideFull           = errors.New("ride is full")
+	ErrAlreadyRiding      = errors.New("you already have a seat in a ride to this event")
+	ErrOwnRide            = errors.New("you cannot take a seat in your own ride")
+	ErrSeatsBelowTaken    = errors.New("seats cannot be lower than the number of passengers")
+	ErrUnknownLodging     = errors.New("unknown lodging for this event")
+	ErrInvalidStay        = errors.New("checkOut must be after checkIn")
+	ErrPromptRequired     = errors.New("prompt is required")
+	ErrInvalidFeedback    = errors.New("invalid feedback answers")
+	ErrFeedbackNotOpen    = errors.New("feedback opens once the event has ended")
+	ErrFeedbackGiven      = errors.New("you already gave feedback for this event")
+	ErrNotAttendee        = errors.New("only attendees can give feedback")
+	ErrNotCheckedIn       = errors.New("certificates are only issued to checked-in participants")
+	ErrNoCheckIns         = errors.New("no participant has been checked in yet")
+	ErrNameRequired       = errors.New("name cannot be empty")
+	ErrFollowSelf         = errors.New("you cannot follow yourself")
+	ErrEventQuota         = errors.New("you have reached your limit of active events, archive or delete one first")
+	ErrInviteQuota        = errors.New("you have reached your daily limit of invitations")
+	ErrAlreadyReported    = errors.New("you already reported this and it is awaiting review")
+	ErrReportSelf         = errors.New("you cannot report yourself")
+	ErrContentBlocked     = errors.New("the event contains prohibited content")
+	ErrModerationFailed   = errors.New("content moderation failed")
+	ErrUndoConflict       = errors.New("this action can no longer be undone")
+	ErrEventLocked        = errors.New("the event is being edited by someone else")
+	ErrApprovalRequired   = errors.New("changes to this event need an organizer's approval, propose them instead")
+	ErrNoChanges          = errors.New("the proposal does not change anything")
+	ErrProposalDecided    = errors.New("this proposal was already decided")
+	ErrNotProposable      = errors.New("requireChangeApproval cannot be proposed")
+	ErrInvalidMapping     = errors.New("invalid webhook mapping")
+	ErrInvalidSignature   = errors.New("invalid webhook signature")
+	ErrDuplicateDelivery  = errors.New("this webhook delivery was already received")
+	ErrInvalidPayload     = errors.New("payload does not match the webhook mapping")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+	ErrUnknownTrigger     = errors.New("unknown trigger")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrMailInDisabled     = errors.New("email-in is not configured")
+	ErrInvalidMailInToken = errors.New("invalid inbound email token")
+	ErrUnsupportedLocale  = errors.New("unsupported locale")
+	ErrTrackingDisabled   = errors.New("email delivery reports are not configured")
+	ErrInvalidReportToken = errors.New("invalid delivery report token")
+	ErrPossibleDuplicate  = errors.New("this looks like an event you already have, set allowDuplicate to create it anyway")
+	ErrInvalidInterval    = errors.New("interval must be day or week")
+	ErrInvalidFlagName    = errors.New("invalid flag name")
+	ErrPaymentsDisabled   = errors.New("paid tickets are not available for your account yet")
+	ErrNoInvitees         = errors.New("no users to invite")
+	ErrTooManyInvitees    = errors.New("too many users in one invitation")
+	ErrInvalidTaskOrder   = errors.New("give either taskIds or positions, each task once")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrInvalidColumns     = errors.New("column names must not be empty or repeat")
+	ErrColumnNotFound     = errors.New("board column not found")
+	ErrInvalidLabelName   = errors.New("label names must not be empty, contain commas or repeat")
+	ErrInvalidLabelColor  = errors.New("label color must be a hex color such as #e53935")
+	ErrLabelExists        = errors.New("a label with this name already exists")
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrTimerRunning       = errors.New("a timer is already running")
+	ErrTimerNotRunning    = errors.New("no timer running on this task")
+	ErrInvalidTimeEntry   = errors.New("time entries cannot end in the future")
+	ErrTimeEntryNotFound  = errors.New("time entry not found")
+	ErrInvalidComment     = errors.New("comment must not be empty")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrNoAnnouncement     = errors.New("announcement not found")
+	ErrInvalidEmoji       = errors.New("reactions must be an emoji")
+	ErrTooManyReactions   = errors.New("you cannot react with more emoji to this")
 )
 
+// isUniqueViolation reports whether err is a unique_violation (23505) of one
+// of the named constraints or unique indexes.
+func isUniqueViolation(err error, constraints ...string) bool {
+	return isViolation(err, "23505", constraints)
+}
+
+// isForeignKeyViolation reports whether err is a foreign_key_violation (23503)
+// of one of the named constraints.
+func isForeignKeyViolation(err error, constraints ...string) bool {
+	return isViolation(err, "23503", constraints)
+}
 
+func isViolation(err error, code string, constraints []string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code && slices.Contains(constraints, pgErr.ConstraintName)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/pdf"
+)
+
+// eventReport is what the post-event report says about an event.
+type eventReport struct {
+	event       *models.Event
+	attendance  eventAttendance
+	budget      []budgetLine
+	sales       []models.SalesAmounts
+	tasks       models.TaskStats
+	overdue     int
+	feedback    *models.FeedbackSummary
+	generatedAt time.Time
+}
+
+// eventAttendance compares the invitees' RSVPs with the check-ins at the
+// door. Rate is the share of going invitees who were checked in.
+type eventAttendance struct {
+	Invited, Going, Maybe, NotGoing, Pending int
+	CheckedIn, NoShows, WalkIns              int
+	Rate                                     *float64
+}
+
+// budgetLine totals the vendors paid in one currency: what was contracted,
+// for those with an agreed price, against what was paid.
+type budgetLine struct {
+	Currency        string
+	Vendors         int
+	Unpriced        int
+	ContractedCents int
+	PaidCents       int
+}
+
+// Layout of the report pages, in points.
+const (
+	reportMargin  = 56.0
+	reportLine    = 16.0
+	reportValueAt = 340.0
+)
+
+// reportWriter lays out the report top to bottom, starting a new page when
+// the current one is full.
+type reportWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+func (w *reportWriter) room(height float64) {
+	if w.page != nil && w.y-height >= reportMargin {
+		return
+	}
+	w.page = w.doc.AddPage()
+	w.y = w.page.Size().Height - reportMargin
+}
+
+func (w *reportWriter) title(s string) {
+	w.room(28)
+	w.page.Text(reportMargin, w.y-20, pdf.Bold, 20, s)
+	w.y -= 30
+}
+
+func (w *reportWriter) heading(s string) {
+	w.room(3 * reportLine)
+	w.y -= reportLine
+	w.page.Text(reportMargin, w.y-13, pdf.Bold, 13, s)
+	w.y -= 18
+	w.page.Line(reportMargin, w.y, pdf.A4.Width-reportMargin, w.y, 0.5)
+	w.y -= 6
+}
+
+// text writes a paragraph wrapped to the page width.
+func (w *reportWriter) text(s string) {
+	for _, line := range pdf.Wrap(s, pdf.Regular, 10, pdf.A4.Width-2*reportMargin) {
+		w.room(reportLine)
+		w.page.Text(reportMargin, w.y-11, pdf.Regular, 10, line)
+		w.y -= reportLine
+	}
+}
+
+// row writes a label with its value aligned in a second column.
+func (w *reportWriter) row(label, value string) {
+	lines := pdf.Wrap(label, pdf.Regular, 10, reportValueAt-reportMargin-12)
+	w.room(float64(len(lines)) * reportLine)
+	w.page.Text(reportValueAt, w.y-11, pdf.Bold, 10, value)
+	for _, line := range lines {
+		w.page.Text(reportMargin, w.y-11, pdf.Regular, 10, line)
+		w.y -= reportLine
+	}
+}
+
+// renderEventReport returns the post-event report as A4 pages: attendance,
+// budget against actual spending, ticket sales, task completion and feedback
+// scores.
+func renderEventReport(r *eventReport) []byte {
+	w := &reportWriter{doc: pdf.New(pdf.A4, r.event.Title+" report")}
+	w.title(r.event.Title)
+	when := certificateDate(r.event)
+	if r.event.Location != "" {
+		when += ", " + r.event.Location
+	}
+	w.text(when)
+	if r.event.CancelledAt != nil {
+		w.text("This event was cancelled on " + r.event.CancelledAt.Format("January 2, 2006") + ".")
+	}
+	w.text("Report generated on " + r.generatedAt.UTC().Format("January 2, 2006 at 15:04 UTC") + ".")
+
+	a := r.attendance
+	w.heading("Attendance")
+	w.row("Invited", fmt.Sprint(a.Invited))
+	w.row("Going / maybe / not going / no answer", fmt.Sprintf("%d / %d / %d / %d", a.Going, a.Maybe, a.NotGoing, a.Pending))
+	w.row("Checked in", fmt.Sprint(a.CheckedIn))
+	w.row("Going but not checked in", fmt.Sprint(a.NoShows))
+	w.row("Checked in without saying they were going", fmt.Sprint(a.WalkIns))
+	w.row("Attendance rate of going invitees", percent(a.Rate))
+	if a.CheckedIn == 0 {
+		w.text("Nobody was checked in, so actual attendance is not known.")
+	}
+
+	w.heading("Budget")
+	if len(r.budget) == 0 {
+		w.text("No vendors were recorded.")
+	}
+	for _, b := range r.budget {
+		w.row(fmt.Sprintf("Vendors paid in %s", b.Currency), fmt.Sprint(b.Vendors))
+		w.row("Contracted", formatAmount(b.ContractedCents, b.Currency))
+		w.row("Paid", formatAmount(b.PaidCents, b.Currency))
+		w.row("Left to pay", formatAmount(b.ContractedCents-b.PaidCents, b.Currency))
+		if b.Unpriced > 0 {
+			w.text(fmt.Sprintf("%d of these vendors have no agreed price; what was paid to them counts as paid but not as contracted.", b.Unpriced))
+		}
+	}
+
+	if len(r.sales) > 0 {
+		w.heading("Ticket sales")
+		for _, s := range r.sales {
+			w.row(fmt.Sprintf("Tickets sold in %s", s.Currency), fmt.Sprint(s.Tickets))
+			w.row("Revenue", formatAmount(int(s.RevenueCents), s.Currency))
+			if s.RefundedCents > 0 {
+				w.row("Refunded", formatAmount(int(s.RefundedCents), s.Currency))
+			}
+			if s.PendingCents > 0 {
+				w.row("Pending payment", formatAmount(int(s.PendingCents), s.Currency))
+			}
+		}
+	}
+
+	w.heading("Tasks")
+	w.row("Tasks", fmt.Sprint(r.tasks.Assigned))
+	w.row("Completed", fmt.Sprint(r.tasks.Completed))
+	w.row("Completion rate", percent(r.tasks.CompletionRate))
+	if r.overdue > 0 {
+		w.row("Open past their due date", fmt.Sprint(r.overdue))
+	}
+
+	w.heading("Feedback")
+	if r.feedback == nil || len(r.feedback.Questions) == 0 {
+		w.text("The event had no feedback survey.")
+	} else {
+		w.row("Responses", fmt.Sprint(r.feedback.Responses))
+		for _, q := range r.feedback.Questions {
+			switch {
+			case q.Kind != models.FeedbackKindRating:
+				w.row(q.Prompt, fmt.Sprintf("%d comments", len(q.Comments)))
+			case q.Average == nil:
+				w.row(q.Prompt, "no ratings")
+			default:
+				w.row(q.Prompt, fmt.Sprintf("%.1f / 5 (%d ratings)", *q.Average, q.Answers))
+			}
+		}
+	}
+	return w.doc.Bytes()
+}
+
+// percent formats a rate between 0 and 1, or "n/a" without one.
+func percent(rate *float64) string {
+	if rate == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", *rate*100)
+}
@@ -2,95 +2,1970 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"eventplanner-backend/internal/meetings"
 	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/moderation"
+	"eventplanner-backend/internal/notifications"
 	"eventplanner-backend/internal/repositories"
+
 	"github.com/jackc/pgx/v5"
 )
 
 type EventService interface {
-	Create(ctx context.Context, title, description, location string, start time.Time, organizerID int) (*models.Event, error)
-	ListOrganized(ctx context.Context, userID int) ([]models.Event, error)
-	ListInvited(ctx context.Context, userID int) ([]models.Event, error)
-	Delete(ctx context.Context, eventID, organizerID int) error
-	Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string) error
+	Create(ctx context.Context, e models.Event, createMeeting bool, template models.TaskTemplateRef, allowDuplicate bool) (*models.Event, error)
+	ListOrganized(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error)
+	ListInvited(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error)
+	Delete(ctx context.Context, eventID, userID int) (*models.Undo, error)
+	Publish(ctx context.Context, eventID, userID int) (*models.Event, error)
+	Unpublish(ctx context.Context, eventID, userID int) error
+	SetArchived(ctx context.Context, eventID, userID int, archived bool) (*models.Event, error)
+	ArchiveEnded(ctx context.Context, after time.Duration) (int64, error)
+	Bulk(ctx context.Context, userID int, req models.BulkEventRequest) (*models.BulkEventResponse, error)
+	LockForEditing(ctx context.Context, eventID, userID int, ttl time.Duration) (*models.EditLock, error)
+	EditLock(ctx context.Context, eventID, userID int) (*models.EditLock, error)
+	Unlock(ctx context.Context, eventID, userID int) error
+	Update(ctx context.Context, eventID, userID int, req models.UpdateEventRequest) (*models.Event, error)
+	Reschedule(ctx context.Context, eventID, userID int, start time.Time, end *time.Time, resetRSVPs bool) (*models.Event, error)
+	Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string, expiresAt *time.Time) error
+	InviteMany(ctx context.Context, eventID, inviterID int, req models.BulkInviteRequest) (*models.BulkInviteResult, error)
+	RevokeInvite(ctx context.Context, eventID, userID, inviteeID int) (*models.Undo, error)
 	Participants(ctx context.Context, eventID, requesterID int) ([]models.Participant, error)
-	SetAttendance(ctx context.Context, eventID, userID int, status string) error
+	AttendanceCounts(ctx context.Context, eventID, requesterID int) (*models.AttendanceCounts, error)
+	SetAttendance(ctx context.Context, eventID, userID int, status string, answers []models.RSVPAnswer) error
+	SetMuted(ctx context.Context, eventID, userID int, muted bool) error
 	IsOrganizer(ctx context.Context, eventID, userID int) (bool, error)
-	CreateTask(ctx context.Context, eventID, userID int, title, description string, dueDate *time.Time, assigneeID *int) (*models.Task, error)
+	CreateTask(ctx context.Context, eventID, userID int, title, description string, dueDate *time.Time, dueOffset string, assigneeID *int) (*models.Task, error)
+	CreateTasks(ctx context.Context, eventID, userID int, req models.BulkTaskRequest) ([]models.Task, error)
+	ImportTasks(ctx context.Context, eventID, userID int, tasks []models.TaskInput) ([]models.Task, error)
+	UpdateTask(ctx context.Context, eventID, taskID, userID int, patch models.TaskPatch) (*models.Task, error)
+	OrderTasks(ctx context.Context, eventID, userID int, req models.TaskOrderRequest) ([]models.Task, error)
+	ListTasks(ctx context.Context, eventID, userID int, labels []string) ([]models.Task, error)
+	Get(ctx context.Context, eventID, userID int) (*models.Event, error)
+	GetBySlug(ctx context.Context, slug string, userID int) (*models.Event, error)
+	ParticipantsByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Participant, error)
+	TasksByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Task, error)
+	List(ctx context.Context, userID int, eventIDs []int, includeParticipants, includeTasks, includeLinks bool) ([]models.EventDetails, error)
+	Calendar(ctx context.Context, userID int, from, to time.Time) ([]models.CalendarDay, error)
+	ListRoles(ctx context.Context, eventID, userID int) ([]models.EventRole, error)
+	CreateRole(ctx context.Context, eventID, userID int, req models.EventRoleRequest) (*models.EventRole, error)
+	DeleteRole(ctx context.Context, eventID, userID int, name string) error
+	ListQuestions(ctx context.Context, eventID, userID int) ([]models.RSVPQuestion, error)
+	CreateQuestion(ctx context.Context, eventID, userID int, req models.RSVPQuestionRequest) (*models.RSVPQuestion, error)
+	DeleteQuestion(ctx context.Context, eventID, userID, questionID int) error
+	ExportResponses(ctx context.Context, eventID, userID int) (*models.RSVPExport, error)
+	Announce(ctx context.Context, eventID, userID int, req models.AnnouncementRequest) (*models.Announcement, error)
+	Nudge(ctx context.Context, eventID, userID, afterDays int) ([]models.Participant, error)
+	SendAutoNudges(ctx context.Context) (int, error)
+	ListAnnouncements(ctx context.Context, eventID, userID int) ([]models.Announcement, error)
+	AddAnnouncementReaction(ctx context.Context, eventID, announcementID, userID int, emoji string) ([]models.Reaction, error)
+	RemoveAnnouncementReaction(ctx context.Context, eventID, announcementID, userID int, emoji string) ([]models.Reaction, error)
 }
 
 type eventService struct {
-	repo repositories.EventRepository
+	repo      repositories.EventRepository
+	templates repositories.TaskTemplateRepository
+	blocks    repositories.BlockRepository
+	watches   repositories.WatchRepository
+	reactions repositories.ReactionRepository
+	previews  LinkPreviewService
+	quotas    QuotaService
+	undo      UndoService
+	meetings  meetings.Provider
+	moderator moderation.Moderator
+	notifier  *notifications.Dispatcher
+}
+
+func NewEventService(repo repositories.EventRepository, templates repositories.TaskTemplateRepository, blocks repositories.BlockRepository, watches repositories.WatchRepository, reactions repositories.ReactionRepository, previews LinkPreviewService, quotas QuotaService, undo UndoService, meetingProvider meetings.Provider, moderator moderation.Moderator, notifier *notifications.Dispatcher) EventService {
+	return &eventService{repo: repo, templates: templates, blocks: blocks, watches: watches, reactions: reactions, previews: previews, quotas: quotas, undo: undo, meetings: meetingProvider, moderator: moderator, notifier: notifier}
+}
+
+// duplicateTitleSimilarity is the pg_trgm similarity from which an event
+// overlapping a new one of the same organizer counts as a likely duplicate.
+const duplicateTitleSimilarity = 0.5
+
+// DuplicateEventError is returned by Create for an event that looks like one
+// the organizer already has. It matches ErrPossibleDuplicate.
+type DuplicateEventError struct {
+	Candidates []models.DuplicateCandidate
+}
+
+func (e *DuplicateEventError) Error() string { return ErrPossibleDuplicate.Error() }
+
+func (e *DuplicateEventError) Unwrap() error { return ErrPossibleDuplicate }
+
+// Create stores a new event organized by e.OrganizerID. With createMeeting,
+// a virtual or hybrid event without a meeting URL gets one from the meeting
+// provider.
+// The event's slug is derived from its title, with a suffix when taken.
+// The tasks of the given template are created with the event, due relative to
+// its start. Unless allowDuplicate is set, an event overlapping one of the
+// organizer's with a similar title fails with a DuplicateEventError.
+// Organizers at their active event quota get a QuotaError.
+func (s *eventService) Create(ctx context.Context, e models.Event, createMeeting bool, template models.TaskTemplateRef, allowDuplicate bool) (*models.Event, error) {
+	if e.EndTime != nil && !e.EndTime.After(e.StartTime) {
+		return nil, ErrInvalidTimeRange
+	}
+	if err := s.quotas.CheckActiveEvents(ctx, e.OrganizerID); err != nil {
+		return nil, err
+	}
+	if !allowDuplicate {
+		candidates, err := s.repo.FindDuplicates(ctx, e.OrganizerID, strings.TrimSpace(e.Title), e.StartTime, e.EndTime, duplicateTitleSimilarity)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return nil, &DuplicateEventError{Candidates: candidates}
+		}
+	}
+	templateTasks, err := resolveTaskTemplate(ctx, s.templates, e.OrganizerID, template)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := instantiateTemplate(templateTasks, e.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	if e.Type == "" {
+		e.Type = models.EventTypeInPerson
+	}
+	if e.Type == models.EventTypeInPerson && (e.MeetingURL != nil || createMeeting) {
+		return nil, ErrMeetingNotAllowed
+	}
+	if createMeeting && e.MeetingURL == nil {
+		link, err := s.meetings.CreateMeeting(ctx, meetings.Meeting{Topic: e.Title, Start: e.StartTime, End: e.EndTime})
+		if errors.Is(err, meetings.ErrNotConfigured) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMeetingCreation, err)
+		}
+		e.MeetingURL = &link
+	}
+	base := slugify(e.Title)
+	for attempt := 1; ; attempt++ {
+		slug, err := slugCandidate(base, attempt)
+		if err != nil {
+			return nil, err
+		}
+		e.Slug = slug
+		created, err := s.repo.Create(ctx, e, tasks)
+		if attempt < maxSlugAttempts && isUniqueViolation(err, "idx_events_slug") {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created.Permissions = models.PermissionsFor("organizer")
+		return created, nil
+	}
 }
 
-func NewEventService(repo repositories.EventRepository) EventService {
-	return &eventService{repo: repo}
+// GetBySlug returns an event the user participates in by its slug.
+func (s *eventService) GetBySlug(ctx context.Context, slug string, userID int) (*models.Event, error) {
+	eventID, err := s.repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, eventID, userID)
 }
 
-func (s *eventService) Create(ctx context.Context, title, description, location string, start time.Time, organizerID int) (*models.Event, error) {
-	return s.repo.Create(ctx, title, description, location, start, organizerID)
+func (s *eventService) ListOrganized(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error) {
+	res, err := s.repo.ListByRole(ctx, userID, "organizer", filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		res[i].Permissions = models.PermissionsFor("organizer")
+	}
+	return res, nil
 }
 
-func (s *eventService) ListOrganized(ctx context.Context, userID int) ([]models.Event, error) {
-	return s.repo.ListByRole(ctx, userID, "organizer")
+func (s *eventService) ListInvited(ctx context.Context, userID int, filter models.EventListFilter) ([]models.EventSummary, error) {
+	res, err := s.repo.ListByRole(ctx, userID, "attendee", filter)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*models.Event, len(res))
+	for i := range res {
+		events[i] = &res[i].Event
+	}
+	members, err := s.viewer(ctx, userID, events)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		if !members[res[i].ID].SeesAttendanceCounts() {
+			res[i].ParticipantCount, res[i].GoingCount = nil, nil
+		}
+	}
+	return res, nil
 }
 
-func (s *eventService) ListInvited(ctx context.Context, userID int) ([]models.Event, error) {
-	return s.repo.ListByRole(ctx, userID, "attendee")
+// Delete removes the event and returns a token to restore it within the undo
+// window, after which it is purged.
+func (s *eventService) Delete(ctx context.Context, eventID, userID int) (*models.Undo, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermDeleteEvent); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.recordUndo(ctx, userID, models.UndoDeleteEvents, []int{eventID}, nil), nil
 }
 
-func (s *eventService) Delete(ctx context.Context, eventID, organizerID int) error {
-	return s.repo.DeleteIfOrganizer(ctx, eventID, organizerID)
+// recordUndo hands out an undo token for an action that already happened. A
+// failure is logged and leaves the action without one.
+func (s *eventService) recordUndo(ctx context.Context, userID int, action string, eventIDs []int, payload json.RawMessage) *models.Undo {
+	undo, err := s.undo.Record(ctx, userID, action, eventIDs, payload)
+	if err != nil {
+		log.Printf("%s %v: recording undo token: %v", action, eventIDs, err)
+		return nil
+	}
+	return undo
 }
 
-func (s *eventService) Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string) error {
-	return s.repo.Invite(ctx, eventID, inviterID, inviteeID, role)
+// Publish gives the event a public landing page at its slug, once its
+// content passed moderation. Blocked content is not published; flagged
+// content is published but hidden until an admin reviews it.
+func (s *eventService) Publish(ctx context.Context, eventID, userID int) (*models.Event, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	decision, err := s.moderator.Review(ctx, moderation.Content{Title: event.Title, Description: event.Description, Location: event.Location})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrModerationFailed, err)
+	}
+	var flags []string
+	switch decision.Verdict {
+	case moderation.Block:
+		return nil, fmt.Errorf("%w: %s", ErrContentBlocked, strings.Join(decision.Reasons, ", "))
+	case moderation.Flag:
+		flags = decision.Reasons
+		if len(flags) == 0 {
+			flags = []string{"flagged by content moderation"}
+		}
+	}
+	published, err := s.repo.Publish(ctx, eventID, flags)
+	if err != nil {
+		return nil, err
+	}
+	return published, s.applyViewer(ctx, userID, []*models.Event{published})
 }
 
-func (s *eventService) Participants(ctx context.Context, eventID, requesterID int) ([]models.Participant, error) {
-    ok, err := s.repo.IsOrganizer(ctx, eventID, requesterID)
-    if err != nil {
-        return nil, err
-    }
-    if !ok {
-        return nil, pgx.ErrNoRows
-    }
-    return s.repo.ListParticipants(ctx, eventID)
+// Unpublish takes the event's landing page down.
+func (s *eventService) Unpublish(ctx context.Context, eventID, userID int) error {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.repo.Unpublish(ctx, eventID)
 }
 
-func (s *eventService) SetAttendance(ctx context.Context, eventID, userID int, status string) error {
-	return s.repo.SetAttendance(ctx, eventID, userID, status)
+// DefaultArchiveAfterDays is how long after their end events are archived
+// unless EVENT_ARCHIVE_AFTER_DAYS says otherwise.
+const DefaultArchiveAfterDays = 30
+
+// ArchiveAfterFromEnv reads how long after their end events are archived
+// from EVENT_ARCHIVE_AFTER_DAYS, falling back to DefaultArchiveAfterDays.
+func ArchiveAfterFromEnv() time.Duration {
+	days := DefaultArchiveAfterDays
+	if raw := os.Getenv("EVENT_ARCHIVE_AFTER_DAYS"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			log.Printf("invalid EVENT_ARCHIVE_AFTER_DAYS %q, using %d", raw, DefaultArchiveAfterDays)
+		} else {
+			days = v
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
-func (s *eventService) IsOrganizer(ctx context.Context, eventID, userID int) (bool, error) {
-	return s.repo.IsOrganizer(ctx, eventID, userID)
+// SetArchived archives the event, hiding it from the default listings, or
+// brings it back. An event brought back is not archived automatically again,
+// and counts against its organizer's active event quota.
+func (s *eventService) SetArchived(ctx context.Context, eventID, userID int, archived bool) (*models.Event, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if !archived {
+		event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if event.ArchivedAt != nil && event.CancelledAt == nil {
+			if err := s.quotas.CheckActiveEvents(ctx, event.OrganizerID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	event, err := s.repo.SetArchived(ctx, eventID, archived)
+	if err != nil {
+		return nil, err
+	}
+	return event, s.applyViewer(ctx, userID, []*models.Event{event})
 }
 
-func (s *eventService) CreateTask(ctx context.Context, eventID, userID int, title, description string, dueDate *time.Time, assigneeID *int) (*models.Task, error) {
-	// Check if the user is an organizer of the event
-	isOrganizer, err := s.repo.IsOrganizer(ctx, eventID, userID)
+// ArchiveEnded archives the events that ended more than after ago.
+func (s *eventService) ArchiveEnded(ctx context.Context, after time.Duration) (int64, error) {
+	return s.repo.ArchiveEnded(ctx, time.Now().Add(-after))
+}
+
+// Bulk deletes, archives or cancels several events at once. Each event is
+// authorized on its own (delete_event to delete, edit_event otherwise); the
+// permitted ones are changed together in one transaction, and the result
+// reports the outcome per requested ID. Participants of newly cancelled
+// events are notified. Deleting and cancelling return one undo token for
+// every event changed; archiving is undone by unarchiving.
+func (s *eventService) Bulk(ctx context.Context, userID int, req models.BulkEventRequest) (*models.BulkEventResponse, error) {
+	perm := models.PermEditEvent
+	if req.Action == models.BulkDelete {
+		perm = models.PermDeleteEvent
+	}
+	ids := make([]int, 0, len(req.IDs))
+	seen := map[int]bool{}
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	members, err := s.repo.Memberships(ctx, userID, ids)
 	if err != nil {
 		return nil, err
 	}
+	var permitted []int
+	for _, id := range ids {
+		if m, ok := members[id]; ok && m.Has(perm) {
+			permitted = append(permitted, id)
+		}
+	}
 
-	// Only allow organizers to create tasks
-	if !isOrganizer {
-		return nil, fmt.Errorf("only organizers can create tasks")
+	var cancelled []models.EventDetails
+	if req.Action == models.BulkCancel && len(permitted) > 0 {
+		if cancelled, err = s.repo.ListWithRelations(ctx, userID, permitted, false, false); err != nil {
+			return nil, err
+		}
+	}
+	done := map[int]bool{}
+	if len(permitted) > 0 {
+		changed, err := s.repo.Bulk(ctx, req.Action, permitted)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range changed {
+			done[id] = true
+		}
+	}
+	var undone []int
+	switch req.Action {
+	case models.BulkDelete:
+		undone = slices.DeleteFunc(slices.Clone(permitted), func(id int) bool { return !done[id] })
+	case models.BulkCancel:
+		for _, e := range cancelled {
+			if e.CancelledAt == nil && done[e.ID] {
+				undone = append(undone, e.ID)
+				s.notifyCancelled(ctx, userID, e.Event)
+			}
+		}
 	}
 
-	// Validate required fields
-	if title == "" {
-		return nil, fmt.Errorf("task title is required")
+	res := &models.BulkEventResponse{Action: req.Action, Results: make([]models.BulkEventResult, len(ids))}
+	if len(undone) > 0 {
+		action := models.UndoDeleteEvents
+		if req.Action == models.BulkCancel {
+			action = models.UndoCancelEvents
+		}
+		res.Undo = s.recordUndo(ctx, userID, action, undone, nil)
 	}
+	for i, id := range ids {
+		r := models.BulkEventResult{ID: id, Status: models.BulkDone}
+		switch {
+		case done[id]:
+			res.Succeeded++
+		case slices.Contains(permitted, id):
+			r.Status, r.Error = models.BulkNotFound, "event not found"
+		default:
+			r.Status, r.Error = models.BulkForbidden, ErrForbidden.Error()
+		}
+		if r.Status != models.BulkDone {
+			res.Failed++
+		}
+		res.Results[i] = r
+	}
+	return res, nil
+}
 
-	// Create the task
-	task, err := s.repo.CreateTask(ctx, eventID, title, description, dueDate, assigneeID)
+// notifyCancelled tells the event's other participants that it was cancelled.
+// Delivery failures are logged, the cancellation stands.
+func (s *eventService) notifyCancelled(ctx context.Context, userID int, event models.Event) {
+	byEvent, err := s.repo.ListParticipantsByEvents(ctx, []int{event.ID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+		log.Printf("event %d: loading participants to notify of cancellation: %v", event.ID, err)
+		return
+	}
+	var to []notifications.Recipient
+	for _, p := range byEvent[event.ID] {
+		if p.UserID != userID {
+			to = append(to, notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail})
+		}
+	}
+	if len(to) == 0 {
+		return
 	}
+	err = s.notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "event_cancelled",
+		EventID: &event.ID,
+		Subject: event.Title + " is cancelled",
+		Body:    fmt.Sprintf("%s on %s has been cancelled by the organizers.", event.Title, event.StartTime.Format(timeFormat)),
+	})
+	if err != nil {
+		log.Printf("event %d: cancellation notice failed: %v", event.ID, err)
+	}
+}
 
-	return task, nil
+// DefaultEditLockTTL is how long an edit lock lasts unless its holder asks
+// for another duration.
+const DefaultEditLockTTL = 2 * time.Minute
+
+// EditLockedError is returned when someone else holds the event's edit lock.
+// It matches ErrEventLocked.
+type EditLockedError struct {
+	Lock *models.EditLock
+}
+
+func (e *EditLockedError) Error() string {
+	return fmt.Sprintf("the event is being edited by %s", e.Lock.UserName)
+}
+
+func (e *EditLockedError) Unwrap() error { return ErrEventLocked }
+
+// LockForEditing takes the event's edit lock for ttl (requires edit_event),
+// or renews it for its holder. While the lock lasts, co-organizers see who is
+// editing and their changes to the event are refused with an
+// EditLockedError.
+func (s *eventService) LockForEditing(ctx context.Context, eventID, userID int, ttl time.Duration) (*models.EditLock, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultEditLockTTL
+	}
+	lock, acquired, err := s.repo.AcquireEditLock(ctx, eventID, userID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, &EditLockedError{Lock: lock}
+	}
+	return lock, nil
+}
+
+// EditLock returns the event's current edit lock (requires edit_event);
+// pgx.ErrNoRows when nobody is editing.
+func (s *eventService) EditLock(ctx context.Context, eventID, userID int) (*models.EditLock, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEditLock(ctx, eventID)
+}
+
+// Unlock releases the caller's edit lock; pgx.ErrNoRows if they hold none.
+func (s *eventService) Unlock(ctx context.Context, eventID, userID int) error {
+	return s.repo.ReleaseEditLock(ctx, eventID, userID)
+}
+
+// checkEditLock returns an EditLockedError when someone other than userID
+// holds the event's edit lock.
+func (s *eventService) checkEditLock(ctx context.Context, eventID, userID int) error {
+	lock, err := s.repo.GetEditLock(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lock.UserID != userID {
+		return &EditLockedError{Lock: lock}
+	}
+	return nil
+}
+
+// Update changes the event fields present in req. The slug stays the same
+// when the title changes, so links to the landing page keep working. While a
+// co-organizer holds the edit lock, it fails with an EditLockedError. On
+// events that require change approval only organizers edit directly; others
+// get ErrApprovalRequired and propose their changes instead. Changing the RSVP
+// visibility takes manage_participants.
+func (s *eventService) Update(ctx context.Context, eventID, userID int, req models.UpdateEventRequest) (*models.Event, error) {
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		return nil, ErrTitleRequired
+	}
+	if req.Type != nil && *req.Type == "" {
+		return nil, ErrInvalidEventType
+	}
+	members, err := s.repo.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := members[eventID]
+	if !ok || !m.Has(models.PermEditEvent) || (req.RequireChangeApproval != nil && m.Role != "organizer") ||
+		(req.RSVPVisibility != nil && !m.Has(models.PermManageParticipants)) {
+		return nil, ErrForbidden
+	}
+	if err := s.checkEditLock(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if event.RequireChangeApproval && m.Role != "organizer" {
+		return nil, ErrApprovalRequired
+	}
+	if req.Type != nil || req.MeetingURL != nil {
+		eventType, hasMeeting := event.Type, event.MeetingURL != nil
+		if req.Type != nil {
+			eventType = *req.Type
+		}
+		if req.MeetingURL != nil {
+			hasMeeting = *req.MeetingURL != ""
+		}
+		if eventType == models.EventTypeInPerson && hasMeeting {
+			return nil, ErrMeetingNotAllowed
+		}
+	}
+	updated, err := s.repo.Update(ctx, eventID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyEventChanged(ctx, userID, *updated, req)
+	return updated, s.applyViewer(ctx, userID, []*models.Event{updated})
+}
+
+// Reschedule moves the event to a new time. Sessions move with it and tasks
+// due relative to the start get new due dates; with resetRSVPs every
+// attendee's attendance is cleared. Participants are told about the move
+// through the event.rescheduled domain event. Like Update, it respects the
+// edit lock.
+func (s *eventService) Reschedule(ctx context.Context, eventID, userID int, start time.Time, end *time.Time, resetRSVPs bool) (*models.Event, error) {
+	if end != nil && !end.After(start) {
+		return nil, ErrInvalidTimeRange
+	}
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if err := s.checkEditLock(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	shift := start.Sub(event.StartTime)
+	first, last, err := s.repo.SessionSpan(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if first != nil && (first.Add(shift).Before(start) || (end != nil && last.Add(shift).After(*end))) {
+		return nil, ErrSessionOutOfRange
+	}
+	byEvent, err := s.repo.ListTasksByEvents(ctx, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	dueDates, err := relativeDueDates(byEvent[eventID], start)
+	if err != nil {
+		return nil, err
+	}
+	moved, err := s.repo.Reschedule(ctx, models.EventRescheduled{
+		EventID:       eventID,
+		RescheduledBy: userID,
+		Title:         event.Title,
+		OldStart:      event.StartTime,
+		OldEnd:        event.EndTime,
+		NewStart:      start,
+		NewEnd:        end,
+		RSVPsReset:    resetRSVPs,
+	}, dueDates)
+	if err != nil {
+		return nil, err
+	}
+	return moved, s.applyViewer(ctx, userID, []*models.Event{moved})
+}
+
+// Invite adds or updates a participant with a built-in or custom role.
+// Besides manage_participants, the inviter must hold every permission of the
+// role being granted and of the invitee's current role, so nobody can hand
+// out or take away more than they have. New invitations count against the
+// inviter's daily invitation quota; those to users who block the inviter are
+// silently dropped.
+func (s *eventService) Invite(ctx context.Context, eventID, inviterID, inviteeID int, role string, expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return ErrExpiryInPast
+	}
+	role = normalizeRole(role)
+	members, err := s.repo.Memberships(ctx, inviterID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	inviter, ok := members[eventID]
+	if !ok || !inviter.Has(models.PermManageParticipants) {
+		return ErrForbidden
+	}
+	granted, err := s.rolePermissions(ctx, eventID, role)
+	if err != nil {
+		return err
+	}
+	if !covers(inviter.Permissions(), granted) {
+		return ErrForbidden
+	}
+	members, err = s.repo.Memberships(ctx, inviteeID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	current, isMember := members[eventID]
+	if isMember && !covers(inviter.Permissions(), current.Permissions()) {
+		return ErrForbidden
+	}
+	if !isMember {
+		// Invitations to someone who blocks the inviter are dropped, and the
+		// inviter is not told either way.
+		blocked, err := s.blocks.Blocks(ctx, inviteeID, inviterID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return nil
+		}
+		if err := s.quotas.CheckInvites(ctx, inviterID, 1); err != nil {
+			return err
+		}
+	}
+	return s.repo.Invite(ctx, eventID, inviterID, inviteeID, role, expiresAt)
+}
+
+// maxBulkInvites caps the users invited by one bulk invitation.
+const maxBulkInvites = 5000
+
+// InviteMany invites up to maxBulkInvites users at once with the same role,
+// under the same rules as Invite. Users who already participate keep their
+// role and are listed in the result, as are ids and emails of no account.
+// Invitations to users who block the inviter are dropped but counted as
+// sent, so the inviter cannot tell. The new invitations must all fit in the
+// daily invitation quota.
+func (s *eventService) InviteMany(ctx context.Context, eventID, inviterID int, req models.BulkInviteRequest) (*models.BulkInviteResult, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiryInPast
+	}
+	if len(req.UserIDs)+len(req.Emails) == 0 {
+		return nil, ErrNoInvitees
+	}
+	if len(req.UserIDs)+len(req.Emails) > maxBulkInvites {
+		return nil, ErrTooManyInvitees
+	}
+	role := normalizeRole(req.Role)
+	members, err := s.repo.Memberships(ctx, inviterID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	inviter, ok := members[eventID]
+	if !ok || !inviter.Has(models.PermManageParticipants) {
+		return nil, ErrForbidden
+	}
+	granted, err := s.rolePermissions(ctx, eventID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !covers(inviter.Permissions(), granted) {
+		return nil, ErrForbidden
+	}
+
+	emails := make([]string, len(req.Emails))
+	for i, e := range req.Emails {
+		emails[i] = strings.ToLower(strings.TrimSpace(e))
+	}
+	candidates, err := s.repo.InviteCandidates(ctx, eventID, inviterID, req.UserIDs, emails)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.BulkInviteResult{AlreadyParticipating: []int{}, UnknownUserIDs: []int{}, UnknownEmails: []string{}}
+	knownIDs, knownEmails := map[int]bool{}, map[string]bool{}
+	var invitees []int
+	blocked := 0
+	for _, c := range candidates {
+		knownIDs[c.UserID], knownEmails[c.Email] = true, true
+		switch {
+		case c.Participant:
+			res.AlreadyParticipating = append(res.AlreadyParticipating, c.UserID)
+		case c.Blocked:
+			blocked++
+		default:
+			invitees = append(invitees, c.UserID)
+		}
+	}
+	for _, id := range req.UserIDs {
+		if !knownIDs[id] {
+			knownIDs[id] = true // reported once
+			res.UnknownUserIDs = append(res.UnknownUserIDs, id)
+		}
+	}
+	for _, e := range emails {
+		if !knownEmails[e] {
+			knownEmails[e] = true
+			res.UnknownEmails = append(res.UnknownEmails, e)
+		}
+	}
+	if len(invitees) > 0 {
+		if err := s.quotas.CheckInvites(ctx, inviterID, len(invitees)); err != nil {
+			return nil, err
+		}
+		if res.Invited, err = s.repo.InviteMany(ctx, eventID, inviterID, invitees, role, req.ExpiresAt); err != nil {
+			return nil, err
+		}
+	}
+	res.Invited += blocked
+	return res, nil
+}
+
+// RevokeInvite withdraws an unanswered invitation. As with Invite, the caller
+// needs manage_participants and every permission of the invitee's role. The
+// invitee can no longer join through the attendance endpoints until invited
+// again, or until the revocation is undone with the returned token.
+func (s *eventService) RevokeInvite(ctx context.Context, eventID, userID, inviteeID int) (*models.Undo, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	revoker := members[eventID]
+	members, err = s.repo.Memberships(ctx, inviteeID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	invitee, ok := members[eventID]
+	if !ok {
+		return nil, ErrNoPendingInvite
+	}
+	if invitee.Attendance != nil || invitee.Role == "organizer" {
+		return nil, ErrInviteAnswered
+	}
+	if !covers(revoker.Permissions(), invitee.Permissions()) {
+		return nil, ErrForbidden
+	}
+	removed, err := s.repo.RevokeInvite(ctx, eventID, inviteeID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Answered or removed since the check above
+		return nil, ErrNoPendingInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.recordUndo(ctx, userID, models.UndoRevokeInvite, []int{eventID}, removed), nil
+}
+
+// Participants returns the event's participants to those who may see them:
+// participants who manage them, and everyone else when the event's RSVP
+// visibility is full, without emails then.
+func (s *eventService) Participants(ctx context.Context, eventID, requesterID int) ([]models.Participant, error) {
+	members, err := s.repo.Memberships(ctx, requesterID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := members[eventID]
+	if !ok || !m.SeesParticipants() {
+		return nil, ErrForbidden
+	}
+	participants, err := s.repo.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleParticipants(m, participants), nil
+}
+
+// AttendanceCounts returns how many of the event's participants gave each
+// answer, unless its RSVP visibility is none and the requester does not
+// manage participants.
+func (s *eventService) AttendanceCounts(ctx context.Context, eventID, requesterID int) (*models.AttendanceCounts, error) {
+	members, err := s.repo.Memberships(ctx, requesterID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := members[eventID]; !ok || !m.SeesAttendanceCounts() {
+		return nil, ErrForbidden
+	}
+	return s.repo.CountAttendance(ctx, eventID)
+}
+
+// visibleParticipants returns participants as m sees them: in full when m
+// manages participants, otherwise without emails and invitation expiries.
+func visibleParticipants(m models.Membership, participants []models.Participant) []models.Participant {
+	if m.Has(models.PermManageParticipants) {
+		return participants
+	}
+	res := make([]models.Participant, len(participants))
+	for i, p := range participants {
+		p.UserEmail, p.InviteExpiresAt = "", nil
+		res[i] = p
+	}
+	return res
+}
+
+// SetAttendance records the user's attendance along with their answers to the
+// event's RSVP questions. Going requires every required question to be answered.
+func (s *eventService) SetAttendance(ctx context.Context, eventID, userID int, status string, answers []models.RSVPAnswer) error {
+	members, err := s.repo.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	if m, ok := members[eventID]; ok && m.InviteExpired(time.Now()) {
+		return ErrInviteExpired
+	} else if !ok {
+		revoked, err := s.repo.InviteRevoked(ctx, eventID, userID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrInviteRevoked
+		}
+	}
+	questions, err := s.repo.ListQuestions(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]models.RSVPQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	answered := map[int]bool{}
+	clean := make([]models.RSVPAnswer, 0, len(answers))
+	for _, a := range answers {
+		q, ok := byID[a.QuestionID]
+		if !ok {
+			return fmt.Errorf("%w: unknown question %d", ErrInvalidAnswer, a.QuestionID)
+		}
+		a.Answer = strings.TrimSpace(a.Answer)
+		if a.Answer == "" {
+			continue
+		}
+		if q.Kind == models.QuestionKindChoice && !containsString(q.Options, a.Answer) {
+			return fmt.Errorf("%w: %q is not an option of question %d", ErrInvalidAnswer, a.Answer, q.ID)
+		}
+		answered[q.ID] = true
+		clean = append(clean, a)
+	}
+	if status == "going" {
+		for _, q := range questions {
+			if q.Required && !answered[q.ID] {
+				return fmt.Errorf("%w: question %d requires an answer", ErrInvalidAnswer, q.ID)
+			}
+		}
+	}
+	return s.repo.SetAttendance(ctx, eventID, userID, status, clean)
+}
+
+// SetMuted mutes or unmutes the event for the caller. Muted participants keep
+// their place and attendance but get no announcements or reminders about the
+// event; invitations, schedule changes and transfers still reach them.
+func (s *eventService) SetMuted(ctx context.Context, eventID, userID int, muted bool) error {
+	return s.repo.SetMuted(ctx, eventID, userID, muted)
+}
+
+// Slug generation limits. maxSlugLength bounds the title part of a slug.
+const (
+	maxSlugLength   = 60
+	maxSlugAttempts = 12
+)
+
+// slugify turns a title into a lowercase, URL-safe slug of ASCII letters,
+// digits and single hyphens.
+func slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "event"
+	}
+	return b.String()
+}
+
+// slugCandidate returns the slug to try on the given attempt: the bare slug
+// first, then numeric suffixes ("-2", "-3", ...), and finally a random suffix
+// for very common titles.
+func slugCandidate(base string, attempt int) (string, error) {
+	switch {
+	case attempt == 1:
+		return base, nil
+	case attempt <= 10:
+		return fmt.Sprintf("%s-%d", base, attempt), nil
+	}
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base + "-" + hex.EncodeToString(b), nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *eventService) IsOrganizer(ctx context.Context, eventID, userID int) (bool, error) {
+	return s.repo.IsOrganizer(ctx, eventID, userID)
+}
+
+func (s *eventService) CreateTask(ctx context.Context, eventID, userID int, title, description string, dueDate *time.Time, dueOffset string, assigneeID *int) (*models.Task, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	if title == "" {
+		return nil, fmt.Errorf("task title is required")
+	}
+
+	// A relative due date is computed from the event start and kept so it
+	// can follow the event when it moves.
+	var offset *string
+	if dueOffset = strings.ReplaceAll(dueOffset, " ", ""); dueOffset != "" {
+		if dueDate != nil {
+			return nil, ErrDueDateConflict
+		}
+		event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		due, err := dueFromOffset(event.StartTime, dueOffset)
+		if err != nil {
+			return nil, err
+		}
+		dueDate, offset = &due, &dueOffset
+	}
+
+	// Create the task
+	task, err := s.repo.CreateTask(ctx, eventID, title, description, dueDate, offset, assigneeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	s.notifyAssigned(ctx, eventID, userID, []models.Task{*task})
+
+	return task, nil
+}
+
+// UpdateTask changes the task fields set in patch. A due offset is turned
+// into the due date that far from the event start; an empty one clears the
+// due date.
+func (s *eventService) UpdateTask(ctx context.Context, eventID, taskID, userID int, patch models.TaskPatch) (*models.Task, error) {
+	if patch.Title != nil && strings.TrimSpace(*patch.Title) == "" {
+		return nil, ErrTaskTitleRequired
+	}
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	if patch.Due != nil && patch.Due.Offset != nil {
+		if patch.Due.Date != nil {
+			return nil, ErrDueDateConflict
+		}
+		offset := strings.ReplaceAll(*patch.Due.Offset, " ", "")
+		patch.Due = &models.TaskDue{}
+		if offset != "" {
+			event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+			if err != nil {
+				return nil, err
+			}
+			due, err := dueFromOffset(event.StartTime, offset)
+			if err != nil {
+				return nil, err
+			}
+			patch.Due = &models.TaskDue{Date: &due, Offset: &offset}
+		}
+	}
+	task, err := s.repo.UpdateTask(ctx, eventID, taskID, patch)
+	if err != nil {
+		return nil, err
+	}
+	if patch.AssigneeID != nil {
+		s.notifyAssigned(ctx, eventID, userID, []models.Task{*task})
+	}
+	s.notifyTaskChanged(ctx, eventID, userID, *task, patch)
+	return task, nil
+}
+
+// OrderTasks arranges the event's tasks (requires manage_tasks) and returns
+// all of them in their new order.
+func (s *eventService) OrderTasks(ctx context.Context, eventID, userID int, req models.TaskOrderRequest) ([]models.Task, error) {
+	if (len(req.TaskIDs) == 0) == (len(req.Positions) == 0) {
+		return nil, ErrInvalidTaskOrder
+	}
+	if len(req.TaskIDs)+len(req.Positions) > maxImportTasks {
+		return nil, ErrTooManyTasks
+	}
+	seen := map[int]bool{}
+	for _, id := range req.TaskIDs {
+		if seen[id] {
+			return nil, ErrInvalidTaskOrder
+		}
+		seen[id] = true
+	}
+	for _, p := range req.Positions {
+		if seen[p.TaskID] {
+			return nil, ErrInvalidTaskOrder
+		}
+		seen[p.TaskID] = true
+	}
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	var ok bool
+	var err error
+	if len(req.TaskIDs) > 0 {
+		ok, err = s.repo.OrderTasks(ctx, eventID, req.TaskIDs)
+	} else {
+		ok, err = s.repo.PositionTasks(ctx, eventID, req.Positions)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	byEvent, err := s.repo.ListTasksByEvents(ctx, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	tasks := byEvent[eventID]
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, nil
+}
+
+// CreateTasks creates many tasks at once (requires manage_tasks): the tasks of
+// the requested template, if any, due relative to the event start, followed by
+// req.Tasks. Either all are created or none.
+func (s *eventService) CreateTasks(ctx context.Context, eventID, userID int, req models.BulkTaskRequest) ([]models.Task, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	templateTasks, err := resolveTaskTemplate(ctx, s.templates, userID, models.TaskTemplateRef{Name: req.Template, ID: req.TemplateID})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := instantiateTemplate(templateTasks, event.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	given, err := prepareTasks(req.Tasks, event.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	tasks = append(tasks, given...)
+	if len(tasks) == 0 {
+		return nil, ErrNoTasks
+	}
+	if len(tasks) > maxBulkTasks {
+		return nil, ErrTooManyTasks
+	}
+	created, err := s.repo.CreateTasks(ctx, eventID, tasks)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyAssigned(ctx, eventID, userID, created)
+	return created, nil
+}
+
+// ImportTasks creates up to maxImportTasks tasks at once, e.g. read from a
+// spreadsheet (requires manage_tasks). Either all are created or none.
+func (s *eventService) ImportTasks(ctx context.Context, eventID, userID int, inputs []models.TaskInput) ([]models.Task, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, ErrNoTasks
+	}
+	if len(inputs) > maxImportTasks {
+		return nil, ErrTooManyTasks
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := prepareTasks(inputs, event.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.CreateTasks(ctx, eventID, tasks)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyAssigned(ctx, eventID, userID, created)
+	return created, nil
+}
+
+// prepareTasks trims the titles of the tasks and turns due offsets into due
+// dates relative to the event start.
+func prepareTasks(inputs []models.TaskInput, start time.Time) ([]models.TaskInput, error) {
+	tasks := make([]models.TaskInput, 0, len(inputs))
+	for _, t := range inputs {
+		t.Title = strings.TrimSpace(t.Title)
+		if t.Title == "" {
+			return nil, ErrTaskTitleRequired
+		}
+		if t.DueOffset = strings.ReplaceAll(t.DueOffset, " ", ""); t.DueOffset != "" {
+			if t.DueDate != nil {
+				return nil, ErrDueDateConflict
+			}
+			due, err := dueFromOffset(start, t.DueOffset)
+			if err != nil {
+				return nil, err
+			}
+			t.DueDate = &due
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// notifyAssigned tells the assignees of tasks, other than the user who
+// assigned them, about their new tasks. The emails go out in digests, so
+// assigning many tasks at once sends each assignee one email. Delivery
+// failures are logged, the tasks stand.
+func (s *eventService) notifyAssigned(ctx context.Context, eventID, userID int, tasks []models.Task) {
+	var assigned []models.Task
+	for _, t := range tasks {
+		if t.AssigneeID != nil && *t.AssigneeID != userID {
+			assigned = append(assigned, t)
+		}
+	}
+	if len(assigned) == 0 {
+		return
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		log.Printf("event %d: loading event to notify task assignees: %v", eventID, err)
+		return
+	}
+	byEvent, err := s.repo.ListParticipantsByEvents(ctx, []int{eventID})
+	if err != nil {
+		log.Printf("event %d: loading participants to notify task assignees: %v", eventID, err)
+		return
+	}
+	assigner := "Someone"
+	people := map[int]models.Participant{}
+	for _, p := range byEvent[eventID] {
+		people[p.UserID] = p
+		if p.UserID == userID {
+			assigner = p.UserName
+		}
+	}
+	for _, t := range assigned {
+		p, ok := people[*t.AssigneeID]
+		if !ok {
+			continue
+		}
+		err := s.notifier.Dispatch(ctx, []notifications.Recipient{{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail}}, notifications.Message{
+			Kind:    "task_assigned",
+			EventID: &eventID,
+			Subject: "New task: " + t.Title,
+			Body:    fmt.Sprintf("%s assigned you \"%s\" in %s.", assigner, t.Title, event.Title),
+			Mutable: true,
+			Digest:  true,
+		})
+		if err != nil {
+			log.Printf("task %d: assignment notice failed: %v", t.ID, err)
+		}
+	}
+}
+
+// notifyTaskChanged tells the task's watchers and its assignee, other than
+// the user who changed it, what changed. Someone just assigned the task
+// hears about it from notifyAssigned instead. Delivery failures are logged,
+// the change stands.
+func (s *eventService) notifyTaskChanged(ctx context.Context, eventID, userID int, task models.Task, patch models.TaskPatch) {
+	watchers, err := s.watches.TaskWatchers(ctx, eventID, task.ID)
+	if err != nil {
+		log.Printf("task %d: loading watchers to notify of changes: %v", task.ID, err)
+		return
+	}
+	var to []notifications.Recipient
+	for _, w := range watchers {
+		if w.UserID == userID || (patch.AssigneeID != nil && w.UserID == *patch.AssigneeID) {
+			continue
+		}
+		to = append(to, notifications.Recipient{UserID: w.UserID, Name: w.UserName, Email: w.UserEmail})
+	}
+	if len(to) == 0 {
+		return
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		log.Printf("event %d: loading event to notify task watchers: %v", eventID, err)
+		return
+	}
+	byEvent, err := s.repo.ListParticipantsByEvents(ctx, []int{eventID})
+	if err != nil {
+		log.Printf("event %d: loading participants to notify task watchers: %v", eventID, err)
+		return
+	}
+	editor, assignee := "Someone", ""
+	for _, p := range byEvent[eventID] {
+		if p.UserID == userID {
+			editor = p.UserName
+		}
+		if task.AssigneeID != nil && p.UserID == *task.AssigneeID {
+			assignee = p.UserName
+		}
+	}
+	var sentences []string
+	if patch.Title != nil || patch.Description != nil || patch.Due != nil || patch.AssigneeID != nil || patch.EstimatedMinutes != nil {
+		sentences = append(sentences, fmt.Sprintf("%s updated \"%s\" in %s.", editor, task.Title, event.Title))
+	}
+	if patch.Completed != nil {
+		if *patch.Completed {
+			sentences = append(sentences, fmt.Sprintf("%s marked \"%s\" in %s as done.", editor, task.Title, event.Title))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("%s reopened \"%s\" in %s.", editor, task.Title, event.Title))
+		}
+	}
+	if patch.Due != nil && task.DueDate != nil {
+		sentences = append(sentences, fmt.Sprintf("It is now due %s.", task.DueDate.Format(timeFormat)))
+	}
+	if patch.AssigneeID != nil {
+		if assignee != "" {
+			sentences = append(sentences, fmt.Sprintf("It is now assigned to %s.", assignee))
+		} else {
+			sentences = append(sentences, "It is now unassigned.")
+		}
+	}
+	if len(sentences) == 0 {
+		return
+	}
+	err = s.notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "task_changed",
+		EventID: &eventID,
+		Subject: "Task updated: " + task.Title,
+		Body:    strings.Join(sentences, " "),
+		Mutable: true,
+		Digest:  true,
+	})
+	if err != nil {
+		log.Printf("task %d: change notice failed: %v", task.ID, err)
+	}
+}
+
+// notifyEventChanged tells the event's watchers, other than the user who
+// edited it, that its details changed. Moves and cancellations reach every
+// participant through their own notices. Delivery failures are logged, the
+// change stands.
+func (s *eventService) notifyEventChanged(ctx context.Context, userID int, event models.Event, req models.UpdateEventRequest) {
+	watchers, err := s.watches.EventWatchers(ctx, event.ID)
+	if err != nil {
+		log.Printf("event %d: loading watchers to notify of changes: %v", event.ID, err)
+		return
+	}
+	var to []notifications.Recipient
+	editor := "Someone"
+	for _, w := range watchers {
+		if w.UserID == userID {
+			editor = w.UserName
+			continue
+		}
+		to = append(to, notifications.Recipient{UserID: w.UserID, Name: w.UserName, Email: w.UserEmail})
+	}
+	if len(to) == 0 {
+		return
+	}
+	if editor == "Someone" {
+		byEvent, err := s.repo.ListParticipantsByEvents(ctx, []int{event.ID})
+		if err != nil {
+			log.Printf("event %d: loading participants to notify watchers: %v", event.ID, err)
+			return
+		}
+		for _, p := range byEvent[event.ID] {
+			if p.UserID == userID {
+				editor = p.UserName
+			}
+		}
+	}
+	sentences := []string{fmt.Sprintf("%s updated the details of %s.", editor, event.Title)}
+	if req.Location != nil && event.Location != "" {
+		sentences = append(sentences, fmt.Sprintf("It now takes place at %s.", event.Location))
+	}
+	if req.MeetingURL != nil && event.MeetingURL != nil {
+		sentences = append(sentences, fmt.Sprintf("Join online at %s.", *event.MeetingURL))
+	}
+	err = s.notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "event_changed",
+		EventID: &event.ID,
+		Subject: event.Title + " was updated",
+		Body:    strings.Join(sentences, " "),
+		Mutable: true,
+		Digest:  true,
+	})
+	if err != nil {
+		log.Printf("event %d: change notice failed: %v", event.ID, err)
+	}
+}
+
+// Get returns an event the user participates in.
+func (s *eventService) Get(ctx context.Context, eventID, userID int) (*models.Event, error) {
+	e, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkViewed(ctx, eventID, userID); err != nil {
+		log.Printf("event %d: recording view by user %d: %v", eventID, userID, err)
+	}
+	return e, s.applyViewer(ctx, userID, []*models.Event{e})
+}
+
+// ParticipantsByEvents returns participants for the given events, keyed by event ID.
+// As with Participants, only events whose participants the requester may see are
+// included; every included event has an entry, even when it has no participants.
+func (s *eventService) ParticipantsByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Participant, error) {
+	members, err := s.repo.Memberships(ctx, requesterID, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	var allowed []int
+	for id, m := range members {
+		if m.SeesParticipants() {
+			allowed = append(allowed, id)
+		}
+	}
+	if len(allowed) == 0 {
+		return map[int][]models.Participant{}, nil
+	}
+	res, err := s.repo.ListParticipantsByEvents(ctx, allowed)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range allowed {
+		res[id] = visibleParticipants(members[id], res[id])
+		if res[id] == nil {
+			res[id] = []models.Participant{}
+		}
+	}
+	return res, nil
+}
+
+// ListTasks returns the event's tasks in their order to any participant,
+// only those with all of labels when any are given.
+func (s *eventService) ListTasks(ctx context.Context, eventID, userID int, labels []string) ([]models.Task, error) {
+	if err := requireParticipant(ctx, s.repo, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTasks(ctx, eventID, labels)
+}
+
+// TasksByEvents returns tasks for the given events, keyed by event ID.
+// Only events the requester participates in are included, each with an entry.
+func (s *eventService) TasksByEvents(ctx context.Context, requesterID int, eventIDs []int) (map[int][]models.Task, error) {
+	allowed, err := s.repo.ParticipatingEventIDs(ctx, requesterID, eventIDs, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return map[int][]models.Task{}, nil
+	}
+	res, err := s.repo.ListTasksByEvents(ctx, allowed)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range allowed {
+		if _, ok := res[id]; !ok {
+			res[id] = []models.Task{}
+		}
+	}
+	return res, nil
+}
+
+// List returns the events the user participates in, optionally restricted to
+// eventIDs and hydrated with participants (where the user may manage them) and tasks.
+func (s *eventService) List(ctx context.Context, userID int, eventIDs []int, includeParticipants, includeTasks, includeLinks bool) ([]models.EventDetails, error) {
+	res, err := s.repo.ListWithRelations(ctx, userID, eventIDs, includeParticipants, includeTasks)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*models.Event, len(res))
+	for i := range res {
+		events[i] = &res[i].Event
+	}
+	members, err := s.viewer(ctx, userID, events)
+	if err != nil {
+		return nil, err
+	}
+	if includeParticipants {
+		for i := range res {
+			if m := members[res[i].ID]; m.SeesParticipants() {
+				res[i].Participants = visibleParticipants(m, res[i].Participants)
+			} else {
+				res[i].Participants = nil
+			}
+		}
+	}
+	if includeLinks {
+		descriptions := make([]string, len(res))
+		for i := range res {
+			descriptions[i] = res[i].Description
+		}
+		previews, err := s.previews.Previews(ctx, descriptions)
+		if err != nil {
+			return nil, err
+		}
+		for i := range res {
+			res[i].LinkPreviews = previews[i]
+		}
+	}
+	return res, nil
+}
+
+// Calendar returns one bucket per day in [from, to), where from and to are
+// midnights in the caller's time zone. Each bucket lists the caller's events
+// (organized or invited, any attendance) that touch that day.
+func (s *eventService) Calendar(ctx context.Context, userID int, from, to time.Time) ([]models.CalendarDay, error) {
+	if !to.After(from) {
+		return nil, ErrInvalidTimeRange
+	}
+	entries, err := s.repo.ListInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*models.Event, len(entries))
+	for i := range entries {
+		events[i] = &entries[i].Event
+	}
+	if err := s.applyViewer(ctx, userID, events); err != nil {
+		return nil, err
+	}
+
+	loc := from.Location()
+	var days []models.CalendarDay
+	index := map[string]int{}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		index[key] = len(days)
+		days = append(days, models.CalendarDay{Date: key, Events: []models.CalendarEntry{}})
+	}
+
+	for _, e := range entries {
+		start := e.StartTime.In(loc)
+		last := start
+		if e.EndTime != nil && e.EndTime.After(e.StartTime) {
+			// An event ending exactly at midnight does not occupy the next day.
+			last = e.EndTime.In(loc).Add(-time.Nanosecond)
+		}
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		if day.Before(from) {
+			day = from
+		}
+		for ; !day.After(last) && day.Before(to); day = day.AddDate(0, 0, 1) {
+			if i, ok := index[day.Format("2006-01-02")]; ok {
+				days[i].Events = append(days[i].Events, e)
+			}
+		}
+	}
+	return days, nil
+}
+
+// authorize returns ErrForbidden unless userID participates in the event with
+// a role that grants perm. It is the single place event permissions are checked.
+func authorize(ctx context.Context, events repositories.EventRepository, eventID, userID int, perm models.Permission) error {
+	members, err := events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	if m, ok := members[eventID]; !ok || !m.Has(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// requireParticipant returns ErrForbidden unless userID participates in the
+// event, in any role.
+func requireParticipant(ctx context.Context, events repositories.EventRepository, eventID, userID int) error {
+	members, err := events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	if _, ok := members[eventID]; !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// covers reports whether have includes every permission in want.
+func covers(have, want []models.Permission) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// canJoin reports whether a participant may see the meeting URL: those who can
+// edit the event, and everyone whose attendance is going.
+func canJoin(m models.Membership) bool {
+	return m.Has(models.PermEditEvent) || (m.Attendance != nil && *m.Attendance == "going")
+}
+
+// applyViewer fills in the user's permissions on each event and clears the
+// meeting URL of events they may not join yet.
+func (s *eventService) applyViewer(ctx context.Context, userID int, events []*models.Event) error {
+	_, err := s.viewer(ctx, userID, events)
+	return err
+}
+
+// viewer is applyViewer, also returning the user's memberships of the events.
+func (s *eventService) viewer(ctx context.Context, userID int, events []*models.Event) (map[int]models.Membership, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	ids := make([]int, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	members, err := s.repo.Memberships(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		m := members[e.ID]
+		e.Permissions = m.Permissions()
+		if !canJoin(m) {
+			e.MeetingURL = nil
+		}
+	}
+	return members, nil
+}
+
+func normalizeRole(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// rolePermissions returns the permissions of a built-in role or of a custom
+// role of the event, or ErrUnknownRole.
+func (s *eventService) rolePermissions(ctx context.Context, eventID int, role string) ([]models.Permission, error) {
+	if models.IsBuiltInRole(role) {
+		return models.PermissionsFor(role), nil
+	}
+	custom, err := s.repo.GetRole(ctx, eventID, role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUnknownRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	return custom.Permissions, nil
+}
+
+// ListRoles returns the built-in roles followed by the event's custom roles.
+// Any participant may list them.
+func (s *eventService) ListRoles(ctx context.Context, eventID, userID int) ([]models.EventRole, error) {
+	if err := requireParticipant(ctx, s.repo, eventID, userID); err != nil {
+		return nil, err
+	}
+	var roles []models.EventRole
+	for _, name := range []string{"organizer", "collaborator", "attendee"} {
+		roles = append(roles, models.EventRole{EventID: eventID, Name: name, Permissions: models.PermissionsFor(name), BuiltIn: true})
+	}
+	custom, err := s.repo.ListRoles(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return append(roles, custom...), nil
+}
+
+// CreateRole defines a custom role on the event. It requires edit_event, and
+// the caller may only grant permissions they hold themselves.
+func (s *eventService) CreateRole(ctx context.Context, eventID, userID int, req models.EventRoleRequest) (*models.EventRole, error) {
+	name := normalizeRole(req.Name)
+	if name == "" {
+		return nil, ErrUnknownRole
+	}
+	if models.IsBuiltInRole(name) {
+		return nil, ErrRoleExists
+	}
+	members, err := s.repo.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := members[eventID]
+	if !ok || !m.Has(models.PermEditEvent) || !covers(m.Permissions(), req.Permissions) {
+		return nil, ErrForbidden
+	}
+	perms := []models.Permission{}
+	for _, p := range req.Permissions {
+		if !covers(perms, []models.Permission{p}) {
+			perms = append(perms, p)
+		}
+	}
+	role, err := s.repo.CreateRole(ctx, eventID, name, perms)
+	if isUniqueViolation(err, "event_roles_event_id_name_key") {
+		return nil, ErrRoleExists
+	}
+	return role, err
+}
+
+// DeleteRole removes a custom role that no participant holds.
+func (s *eventService) DeleteRole(ctx context.Context, eventID, userID int, name string) error {
+	name = normalizeRole(name)
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	if models.IsBuiltInRole(name) {
+		return ErrForbidden
+	}
+	inUse, err := s.repo.RoleInUse(ctx, eventID, name)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrRoleInUse
+	}
+	return s.repo.DeleteRole(ctx, eventID, name)
+}
+
+// ListQuestions returns the event's RSVP questions to any participant.
+func (s *eventService) ListQuestions(ctx context.Context, eventID, userID int) ([]models.RSVPQuestion, error) {
+	if err := requireParticipant(ctx, s.repo, eventID, userID); err != nil {
+		return nil, err
+	}
+	questions, err := s.repo.ListQuestions(ctx, eventID)
+	if questions == nil && err == nil {
+		questions = []models.RSVPQuestion{}
+	}
+	return questions, err
+}
+
+// CreateQuestion attaches an RSVP question to the event (requires edit_event).
+// Choice questions need at least two distinct options; text questions take none.
+func (s *eventService) CreateQuestion(ctx context.Context, eventID, userID int, req models.RSVPQuestionRequest) (*models.RSVPQuestion, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	q := models.RSVPQuestion{
+		EventID:  eventID,
+		Prompt:   strings.TrimSpace(req.Prompt),
+		Kind:     req.Kind,
+		Options:  []string{},
+		Required: req.Required == nil || *req.Required,
+	}
+	for _, opt := range req.Options {
+		opt = strings.TrimSpace(opt)
+		if opt != "" && !containsString(q.Options, opt) {
+			q.Options = append(q.Options, opt)
+		}
+	}
+	switch {
+	case q.Prompt == "":
+		return nil, fmt.Errorf("%w: prompt is required", ErrInvalidQuestion)
+	case q.Kind == models.QuestionKindChoice && len(q.Options) < 2:
+		return nil, fmt.Errorf("%w: choice questions need at least two options", ErrInvalidQuestion)
+	case q.Kind == models.QuestionKindText && len(q.Options) > 0:
+		return nil, fmt.Errorf("%w: text questions take no options", ErrInvalidQuestion)
+	}
+	return s.repo.CreateQuestion(ctx, q)
+}
+
+// DeleteQuestion removes an RSVP question and its answers (requires edit_event).
+func (s *eventService) DeleteQuestion(ctx context.Context, eventID, userID, questionID int) error {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.repo.DeleteQuestion(ctx, eventID, questionID)
+}
+
+// ExportResponses returns every participant with their answers (requires
+// manage_participants).
+func (s *eventService) ExportResponses(ctx context.Context, eventID, userID int) (*models.RSVPExport, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	questions, err := s.repo.ListQuestions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.repo.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.repo.ListAnswers(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	export := &models.RSVPExport{
+		Questions: questions,
+		Responses: make([]models.RSVPResponse, 0, len(participants)),
+	}
+	if export.Questions == nil {
+		export.Questions = []models.RSVPQuestion{}
+	}
+	for _, p := range participants {
+		a := answers[p.UserID]
+		if a == nil {
+			a = map[int]string{}
+		}
+		export.Responses = append(export.Responses, models.RSVPResponse{
+			UserID:     p.UserID,
+			UserName:   p.UserName,
+			UserEmail:  p.UserEmail,
+			Role:       p.Role,
+			Attendance: p.Attendance,
+			Answers:    a,
+		})
+	}
+	return export, nil
+}
+
+// Announce stores an announcement and notifies the participants whose
+// attendance matches req.Attendance (everyone when empty), except the author.
+// Requires manage_participants. Delivery runs in the background so slow mail
+// servers do not hold up the request; failures are logged.
+func (s *eventService) Announce(ctx context.Context, eventID, userID int, req models.AnnouncementRequest) (*models.Announcement, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	audience := []string{}
+	for _, a := range req.Attendance {
+		if !containsString(audience, a) {
+			audience = append(audience, a)
+		}
+	}
+	participants, err := s.repo.ListParticipantsByAttendance(ctx, eventID, audience)
+	if err != nil {
+		return nil, err
+	}
+	var recipients []notifications.Recipient
+	for _, p := range participants {
+		if p.UserID != userID {
+			recipients = append(recipients, notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail})
+		}
+	}
+
+	a, err := s.repo.CreateAnnouncement(ctx, models.Announcement{
+		EventID:        eventID,
+		AuthorID:       userID,
+		Title:          strings.TrimSpace(req.Title),
+		Body:           req.Body,
+		Audience:       audience,
+		RecipientCount: len(recipients),
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Reactions = []models.Reaction{}
+
+	msg := notifications.Message{
+		Kind:    "announcement",
+		EventID: &eventID,
+		Subject: event.Title + ": " + a.Title,
+		Body:    a.Body,
+		Mutable: true,
+	}
+	if err := s.notifier.Dispatch(ctx, recipients, msg); err != nil {
+		log.Printf("announcement %d: delivery failed: %v", a.ID, err)
+	}
+	return a, nil
+}
+
+// ListAnnouncements returns the event's announcement history with its
+// reactions.
+func (s *eventService) ListAnnouncements(ctx context.Context, eventID, userID int) ([]models.Announcement, error) {
+	res, err := s.visibleAnnouncements(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(res))
+	for i, a := range res {
+		ids[i] = a.ID
+	}
+	counts, err := s.reactions.Counts(ctx, repositories.ReactionAnnouncement, ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		res[i].Reactions = reactionsOf(counts, res[i].ID)
+	}
+	return res, nil
+}
+
+// AddAnnouncementReaction reacts to an announcement the caller was sent, or
+// can see as a manager, with emoji and returns its reactions.
+func (s *eventService) AddAnnouncementReaction(ctx context.Context, eventID, announcementID, userID int, emoji string) ([]models.Reaction, error) {
+	return s.reactToAnnouncement(ctx, eventID, announcementID, userID, emoji, true)
+}
+
+// RemoveAnnouncementReaction takes back the caller's reaction with emoji, if
+// any, and returns the announcement's reactions.
+func (s *eventService) RemoveAnnouncementReaction(ctx context.Context, eventID, announcementID, userID int, emoji string) ([]models.Reaction, error) {
+	return s.reactToAnnouncement(ctx, eventID, announcementID, userID, emoji, false)
+}
+
+func (s *eventService) reactToAnnouncement(ctx context.Context, eventID, announcementID, userID int, emoji string, add bool) ([]models.Reaction, error) {
+	visible, err := s.visibleAnnouncements(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range visible {
+		if a.ID == announcementID {
+			return react(ctx, s.reactions, repositories.ReactionAnnouncement, announcementID, userID, emoji, add)
+		}
+	}
+	return nil, ErrNoAnnouncement
+}
+
+// visibleAnnouncements returns the event's announcements the user can see.
+// Participants who can manage participants see every announcement; others
+// only those that targeted their attendance.
+func (s *eventService) visibleAnnouncements(ctx context.Context, eventID, userID int) ([]models.Announcement, error) {
+	members, err := s.repo.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := members[eventID]
+	if !ok {
+		return nil, ErrForbidden
+	}
+	all, err := s.repo.ListAnnouncements(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	attendance := "pending"
+	if m.Attendance != nil {
+		attendance = *m.Attendance
+	}
+	res := []models.Announcement{}
+	for _, a := range all {
+		if m.Has(models.PermManageParticipants) || len(a.Audience) == 0 || containsString(a.Audience, attendance) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+// Nudge limits. MaxNudges caps how often one invitee is reminded about the
+// same event; organizers nudge after DefaultNudgeAfterDays unless they say
+// otherwise.
+const (
+	MaxNudges             = 3
+	DefaultNudgeAfterDays = 3
+)
+
+// Nudge reminds the event's invitees who have not responded within afterDays
+// of their invitation or previous nudge (requires manage_participants). It
+// returns the invitees nudged.
+func (s *eventService) Nudge(ctx context.Context, eventID, userID, afterDays int) ([]models.Participant, error) {
+	if err := authorize(ctx, s.repo, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	if afterDays <= 0 {
+		afterDays = DefaultNudgeAfterDays
+	}
+	event, err := s.repo.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	nudged, err := s.repo.NudgePending(ctx, eventID, afterDays, MaxNudges)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sendNudges(ctx, event, nudged); err != nil {
+		return nil, err
+	}
+	if nudged == nil {
+		nudged = []models.Participant{}
+	}
+	return nudged, nil
+}
+
+// SendAutoNudges nudges pending invitees of every upcoming event that has
+// automatic nudges turned on and returns how many were nudged.
+func (s *eventService) SendAutoNudges(ctx context.Context) (int, error) {
+	byEvent, err := s.repo.AutoNudgePending(ctx, MaxNudges)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var errs []error
+	for eventID, nudged := range byEvent {
+		event, err := s.repo.GetForParticipant(ctx, eventID, nudged[0].UserID)
+		if err == nil {
+			err = s.sendNudges(ctx, event, nudged)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event %d: %w", eventID, err))
+			continue
+		}
+		sent += len(nudged)
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (s *eventService) sendNudges(ctx context.Context, event *models.Event, nudged []models.Participant) error {
+	if len(nudged) == 0 {
+		return nil
+	}
+	to := make([]notifications.Recipient, len(nudged))
+	for i, p := range nudged {
+		to[i] = notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail}
+	}
+	return s.notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "nudge",
+		EventID: &event.ID,
+		Subject: "Will you attend " + event.Title + "?",
+		Body:    fmt.Sprintf("You're invited to %s on %s and haven't responded yet. Let the organizers know whether you're going.", event.Title, event.StartTime.Format(timeFormat)),
+		Mutable: true,
+	})
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/broadcast"
+	"eventplanner-backend/internal/flags"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type FeatureFlagService interface {
+	List(ctx context.Context, userID int) ([]models.FeatureFlag, error)
+	Set(ctx context.Context, userID int, name string, req models.FeatureFlagRequest) (*models.FeatureFlag, error)
+	Delete(ctx context.Context, userID int, name string) error
+	Mine(ctx context.Context, userID int) (map[string]bool, error)
+}
+
+type featureFlagService struct {
+	repo        repositories.FeatureFlagRepository
+	flags       *flags.Flags
+	broadcaster *broadcast.Broadcaster
+	admins      map[int]bool
+}
+
+func NewFeatureFlagService(repo repositories.FeatureFlagRepository, f *flags.Flags, broadcaster *broadcast.Broadcaster, admins map[int]bool) FeatureFlagService {
+	return &featureFlagService{repo: repo, flags: f, broadcaster: broadcaster, admins: admins}
+}
+
+// List returns every flag with where its settings come from (admins only).
+func (s *featureFlagService) List(ctx context.Context, userID int) ([]models.FeatureFlag, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	return s.flags.List(ctx)
+}
+
+// Set stores the flag in the database, where it overrides the configured
+// one (admins only). Other instances are told to read the flags again.
+func (s *featureFlagService) Set(ctx context.Context, userID int, name string, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	if !flags.ValidName(name) {
+		return nil, ErrInvalidFlagName
+	}
+	f, err := s.repo.SetFlag(ctx, models.FeatureFlag{Name: name, Enabled: req.Enabled, Percent: req.Percent, UserIDs: req.UserIDs})
+	if err != nil {
+		return nil, err
+	}
+	s.flags.Invalidate()
+	s.broadcaster.Broadcast(ctx, broadcast.TopicFlags, nil)
+	return f, nil
+}
+
+// Delete removes the flag from the database, so the configured one or the
+// default applies again (admins only).
+func (s *featureFlagService) Delete(ctx context.Context, userID int, name string) error {
+	if !s.admins[userID] {
+		return ErrForbidden
+	}
+	if err := s.repo.DeleteFlag(ctx, name); err != nil {
+		return err
+	}
+	s.flags.Invalidate()
+	s.broadcaster.Broadcast(ctx, broadcast.TopicFlags, nil)
+	return nil
+}
+
+// Mine says which flags are on for the caller, so clients can show or hide
+// the features behind them.
+func (s *featureFlagService) Mine(ctx context.Context, userID int) (map[string]bool, error) {
+	return s.flags.For(ctx, userID)
+}
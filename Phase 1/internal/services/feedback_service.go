@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FeedbackService interface {
+	CreateQuestion(ctx context.Context, eventID, userID int, req models.FeedbackQuestionRequest) (*models.FeedbackQuestion, error)
+	ListQuestions(ctx context.Context, eventID, userID int) ([]models.FeedbackQuestion, error)
+	DeleteQuestion(ctx context.Context, eventID, questionID, userID int) error
+	Submit(ctx context.Context, eventID, userID int, req models.FeedbackRequest) error
+	Summary(ctx context.Context, eventID, userID int) (*models.FeedbackSummary, error)
+}
+
+type feedbackService struct {
+	feedback repositories.FeedbackRepository
+	events   repositories.EventRepository
+}
+
+func NewFeedbackService(feedback repositories.FeedbackRepository, events repositories.EventRepository) FeedbackService {
+	return &feedbackService{feedback: feedback, events: events}
+}
+
+// CreateQuestion adds a question to the event's survey (requires edit_event).
+// Questions are optional unless marked required.
+func (s *feedbackService) CreateQuestion(ctx context.Context, eventID, userID int, req models.FeedbackQuestionRequest) (*models.FeedbackQuestion, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	q := models.FeedbackQuestion{
+		EventID:  eventID,
+		Prompt:   strings.TrimSpace(req.Prompt),
+		Kind:     req.Kind,
+		Required: req.Required != nil && *req.Required,
+	}
+	if q.Prompt == "" {
+		return nil, ErrPromptRequired
+	}
+	return s.feedback.CreateQuestion(ctx, q)
+}
+
+// ListQuestions returns the survey to any participant.
+func (s *feedbackService) ListQuestions(ctx context.Context, eventID, userID int) ([]models.FeedbackQuestion, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.feedback.ListQuestions(ctx, eventID)
+}
+
+// DeleteQuestion removes a question and its answers (requires edit_event).
+func (s *feedbackService) DeleteQuestion(ctx context.Context, eventID, questionID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.feedback.DeleteQuestion(ctx, eventID, questionID)
+}
+
+// Submit records the caller's answers once the event has ended (its start
+// when it has no end time). Only participants going to the event can answer,
+// and only once.
+func (s *feedbackService) Submit(ctx context.Context, eventID, userID int, req models.FeedbackRequest) error {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return err
+	}
+	m, ok := members[eventID]
+	if !ok {
+		return ErrForbidden
+	}
+	if m.Attendance == nil || *m.Attendance != "going" {
+		return ErrNotAttendee
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	end := event.StartTime
+	if event.EndTime != nil {
+		end = *event.EndTime
+	}
+	if time.Now().Before(end) {
+		return ErrFeedbackNotOpen
+	}
+
+	questions, err := s.feedback.ListQuestions(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	answers, err := checkFeedback(questions, req.Answers)
+	if err != nil {
+		return err
+	}
+	err = s.feedback.Submit(ctx, eventID, userID, answers)
+	if isUniqueViolation(err, "feedback_responses_pkey") {
+		return ErrFeedbackGiven
+	}
+	return err
+}
+
+// checkFeedback validates answers against the survey and returns them
+// normalized: rating questions need a rating and text questions a non-blank
+// comment, every required question must be answered, and each question at
+// most once. Blank optional text answers are dropped.
+func checkFeedback(questions []models.FeedbackQuestion, answers []models.FeedbackAnswer) ([]models.FeedbackAnswer, error) {
+	byID := make(map[int]models.FeedbackQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	answered := map[int]bool{}
+	var out []models.FeedbackAnswer
+	for _, a := range answers {
+		q, ok := byID[a.QuestionID]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown question %d", ErrInvalidFeedback, a.QuestionID)
+		}
+		if answered[q.ID] {
+			return nil, fmt.Errorf("%w: question %d answered twice", ErrInvalidFeedback, q.ID)
+		}
+		a.Comment = strings.TrimSpace(a.Comment)
+		switch q.Kind {
+		case models.FeedbackKindRating:
+			if a.Rating == nil {
+				return nil, fmt.Errorf("%w: question %d needs a rating from 1 to 5", ErrInvalidFeedback, q.ID)
+			}
+			if a.Comment != "" {
+				return nil, fmt.Errorf("%w: question %d takes a rating, not a comment", ErrInvalidFeedback, q.ID)
+			}
+		case models.FeedbackKindText:
+			if a.Rating != nil {
+				return nil, fmt.Errorf("%w: question %d takes a comment, not a rating", ErrInvalidFeedback, q.ID)
+			}
+			if a.Comment == "" {
+				continue
+			}
+		}
+		answered[q.ID] = true
+		out = append(out, a)
+	}
+	for _, q := range questions {
+		if q.Required && !answered[q.ID] {
+			return nil, fmt.Errorf("%w: question %d is required", ErrInvalidFeedback, q.ID)
+		}
+	}
+	return out, nil
+}
+
+// Summary aggregates the survey for organizers (requires edit_event): the
+// average and score distribution of each rating question, and the comments
+// of each text question without their authors.
+func (s *feedbackService) Summary(ctx context.Context, eventID, userID int) (*models.FeedbackSummary, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	questions, err := s.feedback.ListQuestions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.feedback.ListAnswers(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.feedback.CountResponses(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.FeedbackSummary{EventID: eventID, Responses: responses, Questions: make([]models.FeedbackQuestionSummary, len(questions))}
+	index := make(map[int]int, len(questions))
+	for i, q := range questions {
+		index[q.ID] = i
+		qs := models.FeedbackQuestionSummary{QuestionID: q.ID, Prompt: q.Prompt, Kind: q.Kind}
+		if q.Kind == models.FeedbackKindRating {
+			qs.Distribution = make([]int, 5)
+		} else {
+			qs.Comments = []string{}
+		}
+		summary.Questions[i] = qs
+	}
+	totals := make([]int, len(questions))
+	for _, a := range answers {
+		i, ok := index[a.QuestionID]
+		if !ok {
+			continue
+		}
+		qs := &summary.Questions[i]
+		qs.Answers++
+		if a.Rating != nil {
+			qs.Distribution[*a.Rating-1]++
+			totals[i] += *a.Rating
+		} else {
+			qs.Comments = append(qs.Comments, a.Comment)
+		}
+	}
+	for i := range summary.Questions {
+		qs := &summary.Questions[i]
+		if qs.Kind == models.FeedbackKindRating && qs.Answers > 0 {
+			avg := float64(totals[i]) / float64(qs.Answers)
+			qs.Average = &avg
+		}
+	}
+	return summary, nil
+}
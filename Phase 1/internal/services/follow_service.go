@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FollowService interface {
+	FollowOrganizer(ctx context.Context, userID, organizerID int) error
+	UnfollowOrganizer(ctx context.Context, userID, organizerID int) error
+	FollowSeries(ctx context.Context, userID, seriesID int) error
+	UnfollowSeries(ctx context.Context, userID, seriesID int) error
+	Following(ctx context.Context, userID int) (*models.Following, error)
+	Followers(ctx context.Context, userID int) (*models.FollowerCount, error)
+	SeriesFollowers(ctx context.Context, seriesID, userID int) (*models.FollowerCount, error)
+}
+
+type followService struct {
+	follows repositories.FollowRepository
+	series  repositories.SeriesRepository
+}
+
+func NewFollowService(follows repositories.FollowRepository, series repositories.SeriesRepository) FollowService {
+	return &followService{follows: follows, series: series}
+}
+
+// FollowOrganizer subscribes the caller to the organizer's newly published
+// events.
+func (s *followService) FollowOrganizer(ctx context.Context, userID, organizerID int) error {
+	if organizerID == userID {
+		return ErrFollowSelf
+	}
+	err := s.follows.FollowOrganizer(ctx, userID, organizerID)
+	if isForeignKeyViolation(err, "organizer_follows_organizer_id_fkey") {
+		return ErrUnknownUser
+	}
+	return err
+}
+
+func (s *followService) UnfollowOrganizer(ctx context.Context, userID, organizerID int) error {
+	return s.follows.UnfollowOrganizer(ctx, userID, organizerID)
+}
+
+// FollowSeries subscribes the caller to the series' newly published events.
+// Series are found through their published occurrences, so others can only
+// be followed by members once one was published; pgx.ErrNoRows otherwise.
+func (s *followService) FollowSeries(ctx context.Context, userID, seriesID int) error {
+	if _, err := s.series.Get(ctx, seriesID, userID); err != nil {
+		return err
+	}
+	member, err := s.series.IsMember(ctx, seriesID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		published, err := s.follows.HasPublishedOccurrence(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		if !published {
+			return pgx.ErrNoRows
+		}
+	}
+	return s.follows.FollowSeries(ctx, userID, seriesID)
+}
+
+func (s *followService) UnfollowSeries(ctx context.Context, userID, seriesID int) error {
+	return s.follows.UnfollowSeries(ctx, userID, seriesID)
+}
+
+func (s *followService) Following(ctx context.Context, userID int) (*models.Following, error) {
+	return s.follows.Following(ctx, userID)
+}
+
+// Followers counts who follows the caller as an organizer.
+func (s *followService) Followers(ctx context.Context, userID int) (*models.FollowerCount, error) {
+	n, err := s.follows.CountOrganizerFollowers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.FollowerCount{Followers: n}, nil
+}
+
+// SeriesFollowers counts who follows the series (owner only).
+func (s *followService) SeriesFollowers(ctx context.Context, seriesID, userID int) (*models.FollowerCount, error) {
+	series, err := s.series.Get(ctx, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	n, err := s.follows.CountSeriesFollowers(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.FollowerCount{Followers: n}, nil
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// InboundSignatureHeader carries the signature of an inbound webhook request,
+// in the same "t=<unix>,v1=<hex>" form as the outbox webhook sends.
+const InboundSignatureHeader = "X-Eventplanner-Signature"
+
+// inboundSignatureTolerance is how far a signature's timestamp may be from
+// now; older requests are rejected as replays. Newer ones are recorded, and
+// rejected when they arrive again.
+const inboundSignatureTolerance = 5 * time.Minute
+
+// inboundFields are the fields each target can map, true for required ones.
+var inboundFields = map[string]map[string]bool{
+	models.InboundTargetEvent: {
+		"title": true, "startTime": true, "endTime": false, "description": false,
+		"location": false, "type": false, "meetingUrl": false,
+	},
+	models.InboundTargetTask: {
+		"title": true, "description": false, "dueDate": false,
+	},
+}
+
+type InboundWebhookService interface {
+	Create(ctx context.Context, userID int, req models.InboundWebhookRequest) (*models.InboundWebhook, error)
+	Update(ctx context.Context, id, userID int, req models.InboundWebhookRequest) (*models.InboundWebhook, error)
+	List(ctx context.Context, userID int) ([]models.InboundWebhook, error)
+	Delete(ctx context.Context, id, userID int) error
+	Secret(ctx context.Context, userID int) (string, error)
+	RotateSecret(ctx context.Context, userID int) (string, error)
+	Receive(ctx context.Context, id int, payload []byte, header http.Header) (*models.InboundDelivery, error)
+}
+
+type inboundWebhookService struct {
+	webhooks  repositories.InboundWebhookRepository
+	events    repositories.EventRepository
+	eventsSvc EventService
+}
+
+func NewInboundWebhookService(webhooks repositories.InboundWebhookRepository, events repositories.EventRepository, eventsSvc EventService) InboundWebhookService {
+	return &inboundWebhookService{webhooks: webhooks, events: events, eventsSvc: eventsSvc}
+}
+
+// webhookFromRequest checks the mapping against the target. Task webhooks
+// need an event the user may manage tasks of.
+func (s *inboundWebhookService) webhookFromRequest(ctx context.Context, userID int, req models.InboundWebhookRequest) (models.InboundWebhook, error) {
+	w := models.InboundWebhook{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Target:  req.Target,
+		Mapping: map[string]string{},
+	}
+	fields := inboundFields[req.Target]
+	for field, path := range req.Mapping {
+		if _, ok := fields[field]; !ok {
+			return w, fmt.Errorf("%w: %s webhooks cannot set %q", ErrInvalidMapping, req.Target, field)
+		}
+		if path = strings.TrimSpace(path); path == "" {
+			return w, fmt.Errorf("%w: empty path for %q", ErrInvalidMapping, field)
+		}
+		w.Mapping[field] = path
+	}
+	var missing []string
+	for field, required := range fields {
+		if _, ok := w.Mapping[field]; required && !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return w, fmt.Errorf("%w: %s webhooks must map %s", ErrInvalidMapping, req.Target, strings.Join(missing, ", "))
+	}
+
+	switch {
+	case req.Target == models.InboundTargetEvent && req.EventID != nil:
+		return w, fmt.Errorf("%w: eventId is only for task webhooks", ErrInvalidMapping)
+	case req.Target == models.InboundTargetTask && req.EventID == nil:
+		return w, fmt.Errorf("%w: task webhooks need an eventId", ErrInvalidMapping)
+	case req.Target == models.InboundTargetTask:
+		if err := authorize(ctx, s.events, *req.EventID, userID, models.PermManageTasks); err != nil {
+			return w, err
+		}
+		w.EventID = req.EventID
+	}
+	return w, nil
+}
+
+// Create adds an inbound webhook for the user and, on their first, their
+// signing secret.
+func (s *inboundWebhookService) Create(ctx context.Context, userID int, req models.InboundWebhookRequest) (*models.InboundWebhook, error) {
+	w, err := s.webhookFromRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webhooks.EnsureSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return s.webhooks.Create(ctx, w)
+}
+
+func (s *inboundWebhookService) Update(ctx context.Context, id, userID int, req models.InboundWebhookRequest) (*models.InboundWebhook, error) {
+	w, err := s.webhookFromRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	w.ID = id
+	return s.webhooks.Update(ctx, w)
+}
+
+func (s *inboundWebhookService) List(ctx context.Context, userID int) ([]models.InboundWebhook, error) {
+	return s.webhooks.List(ctx, userID)
+}
+
+func (s *inboundWebhookService) Delete(ctx context.Context, id, userID int) error {
+	return s.webhooks.Delete(ctx, id, userID)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Secret returns the user's signing secret; pgx.ErrNoRows until their first
+// webhook or rotation creates one.
+func (s *inboundWebhookService) Secret(ctx context.Context, userID int) (string, error) {
+	return s.webhooks.GetSecret(ctx, userID)
+}
+
+// RotateSecret replaces the user's signing secret, or creates their first.
+// Requests signed with the old one fail from then on.
+func (s *inboundWebhookService) RotateSecret(ctx context.Context, userID int) (string, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	return secret, s.webhooks.SetSecret(ctx, userID, secret)
+}
+
+// verifyInboundSignature checks a "t=<unix>,v1=<hex hmac>" signature over
+// "<t>.<payload>" and returns the HMAC, which identifies the request.
+func verifyInboundSignature(secret string, payload []byte, sigHeader string, now time.Time) (string, error) {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return "", ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > inboundSignatureTolerance || d < -inboundSignatureTolerance {
+		return "", ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range sigs {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			return hex.EncodeToString(expected), nil
+		}
+	}
+	return "", ErrInvalidSignature
+}
+
+// Receive verifies a delivery to the webhook and creates what it maps to, as
+// the webhook's owner, so the owner's permissions and quotas apply. Every
+// verified delivery is recorded on the webhook with its error, if any.
+//
+// A request whose signature was already delivered is a replay and fails with
+// ErrDuplicateDelivery. A delivery that fails is forgotten again, so the
+// sender can retry the same request.
+func (s *inboundWebhookService) Receive(ctx context.Context, id int, payload []byte, header http.Header) (*models.InboundDelivery, error) {
+	w, secret, err := s.webhooks.GetWithSecret(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	signature, err := verifyInboundSignature(secret, payload, header.Get(InboundSignatureHeader), now)
+	if err != nil {
+		return nil, err
+	}
+	// A request is accepted until tolerance past its timestamp, which may be
+	// tolerance ahead of when it first arrived, so records of deliveries
+	// older than twice the tolerance are no longer needed.
+	claimed, err := s.webhooks.ClaimDelivery(ctx, w.ID, signature, now.Add(-2*inboundSignatureTolerance))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrDuplicateDelivery
+	}
+	res, err := s.deliver(ctx, w, payload)
+	var deliveryErr *string
+	if err != nil {
+		msg := err.Error()
+		deliveryErr = &msg
+		if rerr := s.webhooks.ReleaseDelivery(ctx, w.ID, signature); rerr != nil {
+			log.Printf("inbound webhook %d: releasing delivery: %v", w.ID, rerr)
+		}
+	}
+	if rerr := s.webhooks.RecordDelivery(ctx, w.ID, deliveryErr); rerr != nil {
+		log.Printf("inbound webhook %d: recording delivery: %v", w.ID, rerr)
+	}
+	return res, err
+}
+
+func (s *inboundWebhookService) deliver(ctx context.Context, w *models.InboundWebhook, payload []byte) (*models.InboundDelivery, error) {
+	values, err := mapPayload(payload, w.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	for field, required := range inboundFields[w.Target] {
+		if required && strings.TrimSpace(values[field]) == "" {
+			return nil, fmt.Errorf("%w: no %s at %q", ErrInvalidPayload, field, w.Mapping[field])
+		}
+	}
+
+	switch w.Target {
+	case models.InboundTargetEvent:
+		start, err := time.Parse(time.RFC3339, values["startTime"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: startTime must be RFC3339", ErrInvalidPayload)
+		}
+		switch values["type"] {
+		case "", models.EventTypeInPerson, models.EventTypeVirtual, models.EventTypeHybrid:
+		default:
+			return nil, fmt.Errorf("%w: type must be in_person, virtual or hybrid", ErrInvalidPayload)
+		}
+		e := models.Event{
+			Title:       strings.TrimSpace(values["title"]),
+			Description: values["description"],
+			Location:    values["location"],
+			StartTime:   start,
+			Type:        values["type"],
+			OrganizerID: w.UserID,
+		}
+		if v := values["endTime"]; v != "" {
+			end, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: endTime must be RFC3339", ErrInvalidPayload)
+			}
+			e.EndTime = &end
+		}
+		if v := values["meetingUrl"]; v != "" {
+			e.MeetingURL = &v
+		}
+		// Duplicate detection stays on, so a retried delivery does not create
+		// the event twice.
+		created, err := s.eventsSvc.Create(ctx, e, false, models.TaskTemplateRef{}, false)
+		if err != nil {
+			return nil, err
+		}
+		return &models.InboundDelivery{Target: w.Target, ID: created.ID}, nil
+	case models.InboundTargetTask:
+		var due *time.Time
+		if v := values["dueDate"]; v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: dueDate must be RFC3339", ErrInvalidPayload)
+			}
+			due = &t
+		}
+		task, err := s.eventsSvc.CreateTask(ctx, *w.EventID, w.UserID, strings.TrimSpace(values["title"]), values["description"], due, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		return &models.InboundDelivery{Target: w.Target, ID: task.ID}, nil
+	}
+	return nil, fmt.Errorf("unknown inbound webhook target %q", w.Target)
+}
+
+// mapPayload reads the mapped fields from a JSON payload. Paths are dotted,
+// with numbers indexing arrays, e.g. "answers.0.text". Missing paths give no
+// value; strings, numbers and booleans are taken as text.
+func mapPayload(payload []byte, mapping map[string]string) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: body is not JSON", ErrInvalidPayload)
+	}
+	values := make(map[string]string, len(mapping))
+	for field, path := range mapping {
+		v, ok := lookupPath(doc, path)
+		if !ok || v == nil {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			values[field] = v
+		case json.Number:
+			values[field] = v.String()
+		case bool:
+			values[field] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("%w: %q is not a string, number or boolean", ErrInvalidPayload, path)
+		}
+	}
+	return values, nil
+}
+
+func lookupPath(doc any, path string) (any, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// apiKeyPrefixLen is how much of a key stays readable to tell keys apart.
+	apiKeyPrefixLen = 10
+	// triggerSettle keeps trigger feeds this far behind now, so rows written
+	// by transactions still in flight are not skipped by a cursor that has
+	// already moved past their time.
+	triggerSettle = 5 * time.Second
+	// DefaultTriggerLimit and MaxTriggerLimit bound the items of one poll.
+	DefaultTriggerLimit = 50
+	MaxTriggerLimit     = 100
+)
+
+// Triggers is the catalog of trigger feeds.
+var Triggers = []models.Trigger{
+	{Key: models.TriggerNewEvent, Name: "New event", Description: "An event you organize was created"},
+	{Key: models.TriggerNewRSVP, Name: "New RSVP", Description: "Someone answered or changed their answer to an invitation to an event you organize"},
+	{Key: models.TriggerTaskCompleted, Name: "Task completed", Description: "A task of an event you organize was marked done"},
+}
+
+type IntegrationService interface {
+	CreateAPIKey(ctx context.Context, userID int, req models.APIKeyRequest) (*models.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id, userID int) error
+	Authenticate(ctx context.Context, key string) (int, error)
+	Poll(ctx context.Context, userID int, trigger, cursor string, limit int) (*models.TriggerPage, error)
+}
+
+type integrationService struct {
+	repo repositories.IntegrationRepository
+}
+
+func NewIntegrationService(repo repositories.IntegrationRepository) IntegrationService {
+	return &integrationService{repo: repo}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey issues a key for the user. The key is only returned here;
+// afterwards only its prefix is known.
+func (s *integrationService) CreateAPIKey(ctx context.Context, userID int, req models.APIKeyRequest) (*models.CreatedAPIKey, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key := "ep_" + hex.EncodeToString(b)
+	k, err := s.repo.CreateAPIKey(ctx, models.APIKey{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Prefix: key[:apiKeyPrefixLen],
+	}, hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIKey{APIKey: *k, Key: key}, nil
+}
+
+func (s *integrationService) ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error) {
+	return s.repo.ListAPIKeys(ctx, userID)
+}
+
+func (s *integrationService) DeleteAPIKey(ctx context.Context, id, userID int) error {
+	return s.repo.DeleteAPIKey(ctx, id, userID)
+}
+
+// Authenticate returns the owner of an API key.
+func (s *integrationService) Authenticate(ctx context.Context, key string) (int, error) {
+	userID, err := s.repo.APIKeyUser(ctx, hashAPIKey(key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInvalidAPIKey
+	}
+	return userID, err
+}
+
+// encodeTriggerCursor and decodeTriggerCursor turn a cursor into the opaque
+// string clients pass back and the other way around.
+func encodeTriggerCursor(c models.TriggerCursor) string {
+	raw := fmt.Sprintf("%d.%d.%d", c.At.UnixMicro(), c.A, c.B)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTriggerCursor(s string) (*models.TriggerCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	var n [3]int64
+	for i, p := range parts {
+		if n[i], err = strconv.ParseInt(p, 10, 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &models.TriggerCursor{At: time.UnixMicro(n[0]), A: int(n[1]), B: int(n[2])}, nil
+}
+
+// Poll returns the trigger's items after the cursor, oldest first. Without a
+// cursor, it returns the newest items so integrations have samples to set up
+// with, and polls from there on. Feeds stay a few seconds behind now.
+func (s *integrationService) Poll(ctx context.Context, userID int, trigger, cursor string, limit int) (*models.TriggerPage, error) {
+	var after *models.TriggerCursor
+	if cursor != "" {
+		c, err := decodeTriggerCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = c
+	}
+	if limit <= 0 || limit > MaxTriggerLimit {
+		limit = DefaultTriggerLimit
+	}
+	until := time.Now().Add(-triggerSettle)
+
+	// One more than asked tells whether there is more.
+	var items []models.TriggerItem
+	var cursors []models.TriggerCursor
+	switch trigger {
+	case models.TriggerNewEvent:
+		events, err := s.repo.NewEvents(ctx, userID, after, until, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			items = append(items, models.TriggerItem{ID: fmt.Sprintf("event-%d", e.ID), OccurredAt: e.CreatedAt, Data: e})
+			cursors = append(cursors, models.TriggerCursor{At: e.CreatedAt, A: e.ID})
+		}
+	case models.TriggerNewRSVP:
+		rsvps, err := s.repo.NewRSVPs(ctx, userID, after, until, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rsvps {
+			id := fmt.Sprintf("rsvp-%d-%d-%d", r.EventID, r.UserID, r.RespondedAt.UnixMicro())
+			items = append(items, models.TriggerItem{ID: id, OccurredAt: r.RespondedAt, Data: r})
+			cursors = append(cursors, models.TriggerCursor{At: r.RespondedAt, A: r.EventID, B: r.UserID})
+		}
+	case models.TriggerTaskCompleted:
+		tasks, err := s.repo.CompletedTasks(ctx, userID, after, until, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			id := fmt.Sprintf("task-%d-%d", t.ID, t.CompletedAt.UnixMicro())
+			items = append(items, models.TriggerItem{ID: id, OccurredAt: *t.CompletedAt, Data: t})
+			cursors = append(cursors, models.TriggerCursor{At: *t.CompletedAt, A: t.ID})
+		}
+	default:
+		return nil, ErrUnknownTrigger
+	}
+
+	page := &models.TriggerPage{Items: []models.TriggerItem{}, Cursor: cursor}
+	if len(items) > limit {
+		if after == nil {
+			// The newest items: the one too many is the oldest.
+			items, cursors = items[1:], cursors[1:]
+		} else {
+			items, cursors = items[:limit], cursors[:limit]
+			page.HasMore = true
+		}
+	}
+	if len(items) > 0 {
+		page.Items = items
+		page.Cursor = encodeTriggerCursor(cursors[len(cursors)-1])
+	} else if after == nil {
+		page.Cursor = encodeTriggerCursor(models.TriggerCursor{At: until})
+	}
+	return page, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"eventplanner-backend/internal/jobs"
+	"eventplanner-backend/internal/linkpreview"
+	"eventplanner-backend/internal/markdown"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+const (
+	// maxLinkPreviews bounds the previews of one text.
+	maxLinkPreviews = 5
+	// linkPreviewTTL is how long a preview is shown before the page is
+	// fetched again, and failedLinkPreviewTTL how long until a page without
+	// one is tried again.
+	linkPreviewTTL       = 24 * time.Hour
+	failedLinkPreviewTTL = time.Hour
+)
+
+var linkPreviewJob = jobs.NewType[string]("linkpreview.fetch")
+
+type LinkPreviewService interface {
+	Previews(ctx context.Context, texts []string) ([][]models.LinkPreview, error)
+}
+
+type linkPreviewService struct {
+	previews repositories.LinkPreviewRepository
+	fetcher  linkpreview.Fetcher
+	queue    jobs.Queue
+}
+
+func NewLinkPreviewService(previews repositories.LinkPreviewRepository, fetcher linkpreview.Fetcher, queue jobs.Queue) LinkPreviewService {
+	s := &linkPreviewService{previews: previews, fetcher: fetcher, queue: queue}
+	linkPreviewJob.Handle(queue, s.fetch)
+	return s
+}
+
+// Previews returns, for each of texts, the previews of the first pages its
+// Markdown links to, in order. Pages are fetched in the background: those
+// not fetched yet, or not lately, are queued and show up in later calls,
+// and pages without a preview are left out.
+func (s *linkPreviewService) Previews(ctx context.Context, texts []string) ([][]models.LinkPreview, error) {
+	res := make([][]models.LinkPreview, len(texts))
+	links := make([][]string, len(texts))
+	var urls []string
+	seen := map[string]bool{}
+	for i, text := range texts {
+		res[i] = []models.LinkPreview{}
+		links[i] = markdown.Links(text)
+		if len(links[i]) > maxLinkPreviews {
+			links[i] = links[i][:maxLinkPreviews]
+		}
+		for _, u := range links[i] {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return res, nil
+	}
+	cached, err := s.previews.Get(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.previews.Claim(ctx, urls, linkPreviewTTL, failedLinkPreviewTTL)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range claimed {
+		// A URL not queued is claimed again once its claim expires.
+		if err := linkPreviewJob.Enqueue(ctx, s.queue, u); err != nil {
+			log.Printf("link preview %s: %v", u, err)
+		}
+	}
+	for i := range texts {
+		for _, u := range links[i] {
+			if p, ok := cached[u]; ok {
+				res[i] = append(res[i], p)
+			}
+		}
+	}
+	return res, nil
+}
+
+// fetch reads and stores the preview of a claimed page. Pages that cannot
+// be previewed are stored as such rather than retried; only failures to
+// store are.
+func (s *linkPreviewService) fetch(ctx context.Context, url string) error {
+	p, err := s.fetcher.Fetch(ctx, url)
+	if err != nil {
+		if !errors.Is(err, linkpreview.ErrNoPreview) && !errors.Is(err, linkpreview.ErrDisabled) {
+			log.Printf("link preview %s: %v", url, err)
+		}
+		return s.previews.Save(ctx, url, nil)
+	}
+	return s.previews.Save(ctx, url, &models.LinkPreview{URL: url, Title: p.Title, Description: p.Description, ImageURL: p.ImageURL, SiteName: p.SiteName})
+}
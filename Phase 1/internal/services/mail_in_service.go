@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/jobs"
+	"eventplanner-backend/internal/mailin"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+)
+
+// inboundEmail is a received email waiting to become an event.
+type inboundEmail struct {
+	Message    mailin.Message `json:"message"`
+	ReceivedAt time.Time      `json:"receivedAt"`
+}
+
+// inboundEmailJob creates the event of a received email in the background.
+var inboundEmailJob = jobs.NewType[inboundEmail]("mailin.email")
+
+type MailInService interface {
+	Receive(ctx context.Context, token string, raw []byte) error
+}
+
+type mailInService struct {
+	config    mailin.Config
+	users     repositories.UserRepository
+	eventsSvc EventService
+	notifier  *notifications.Dispatcher
+	queue     jobs.Queue
+}
+
+func NewMailInService(config mailin.Config, users repositories.UserRepository, eventsSvc EventService, notifier *notifications.Dispatcher, queue jobs.Queue) MailInService {
+	s := &mailInService{config: config, users: users, eventsSvc: eventsSvc, notifier: notifier, queue: queue}
+	inboundEmailJob.Handle(queue, s.createFromEmail)
+	return s
+}
+
+// Receive checks the provider's token and queues the email to be turned into
+// an event. Only the parsed text is queued, not attachments.
+func (s *mailInService) Receive(ctx context.Context, token string, raw []byte) error {
+	if !s.config.Enabled() {
+		return ErrMailInDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+		return ErrInvalidMailInToken
+	}
+	m, err := mailin.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return inboundEmailJob.Enqueue(ctx, s.queue, inboundEmail{Message: *m, ReceivedAt: time.Now()})
+}
+
+// createFromEmail creates an event organized by the sender of an email: the
+// subject is the title, the first date and time in the body (or else the
+// subject) is the start, and the rest of the body is the description. The
+// sender is told either way. Mail from addresses no user has, or that the
+// provider did not authenticate, is dropped without a reply so forged senders
+// get no backscatter.
+func (s *mailInService) createFromEmail(ctx context.Context, email inboundEmail) error {
+	m := email.Message
+	if !s.config.AddressedTo(&m) {
+		log.Printf("email-in: dropping mail from %s not sent to %s", m.From, s.config.Address)
+		return nil
+	}
+	if !s.config.Authenticated(&m) {
+		log.Printf("email-in: dropping mail from %s without a DMARC or DKIM pass from %s", m.From, s.config.AuthServID)
+		return nil
+	}
+	user, err := s.users.GetByEmail(ctx, m.From)
+	if err == nil && user == nil && strings.ToLower(m.From) != m.From {
+		user, err = s.users.GetByEmail(ctx, strings.ToLower(m.From))
+	}
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		log.Printf("email-in: dropping mail from unknown sender %s", m.From)
+		return nil
+	}
+
+	if m.Subject == "" {
+		s.reply(ctx, user, "email_in_failed", nil, "Your email didn't become an event", "Emails become events titled after their subject, and yours had none.")
+		return nil
+	}
+	start, line, ok := mailin.FindTime(m.Text, s.config.Location, email.ReceivedAt)
+	if !ok {
+		if start, _, ok = mailin.FindTime(m.Subject, s.config.Location, email.ReceivedAt); !ok {
+			s.reply(ctx, user, "email_in_failed", nil, fmt.Sprintf("%q didn't become an event", m.Subject),
+				`We couldn't find when it starts. Put the date and time on a line of their own, e.g. "When: October 20 2027 at 7pm".`)
+			return nil
+		}
+	}
+
+	created, err := s.eventsSvc.Create(ctx, models.Event{
+		Title:       m.Subject,
+		Description: emailDescription(m.Text, line),
+		StartTime:   start,
+		OrganizerID: user.ID,
+	}, false, models.TaskTemplateRef{}, false)
+	var duplicate *DuplicateEventError
+	var quota *QuotaError
+	switch {
+	case errors.As(err, &duplicate):
+		// Also what a retry of a job that created the event sees.
+		s.reply(ctx, user, "email_in_failed", &duplicate.Candidates[0].EventID, fmt.Sprintf("%q didn't become an event", m.Subject),
+			"It looks like an event you already have, so we didn't create it again.")
+		return nil
+	case errors.As(err, &quota), errors.Is(err, ErrInvalidTimeRange):
+		s.reply(ctx, user, "email_in_failed", nil, fmt.Sprintf("%q didn't become an event", m.Subject), err.Error())
+		return nil
+	case err != nil:
+		return err
+	}
+	when := created.StartTime.In(s.config.Location).Format("Monday, January 2 2006 at 15:04 MST")
+	s.reply(ctx, user, "email_in_created", &created.ID, "Event created: "+created.Title,
+		fmt.Sprintf("We created %s on %s from your email. Open it to add details and invite people.", created.Title, when))
+	return nil
+}
+
+// emailDescription is the body of an email without the line the start was
+// read from and the signature.
+func emailDescription(text, when string) string {
+	if sig := strings.Index(text, "\n-- \n"); sig >= 0 {
+		text = text[:sig]
+	}
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		if when != "" && l == when {
+			when = ""
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// reply notifies the sender of an email about what became of it. Delivery
+// failures are logged; the email is handled either way.
+func (s *mailInService) reply(ctx context.Context, user *models.User, kind string, eventID *int, subject, body string) {
+	err := s.notifier.Dispatch(ctx, []notifications.Recipient{{UserID: user.ID, Name: user.Name, Email: user.Email}}, notifications.Message{
+		Kind:    kind,
+		EventID: eventID,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("email-in: notifying user %d: %v", user.ID, err)
+	}
+}
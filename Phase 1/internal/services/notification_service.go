@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type NotificationService interface {
+	List(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkRead(ctx context.Context, userID, id int) error
+}
+
+type notificationService struct {
+	repo repositories.NotificationRepository
+}
+
+func NewNotificationService(repo repositories.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+func (s *notificationService) List(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error) {
+	return s.repo.ListForUser(ctx, userID, unreadOnly, limit)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id int) error {
+	return s.repo.MarkRead(ctx, userID, id)
+}
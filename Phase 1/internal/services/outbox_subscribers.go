@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/outbox"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SubscribeNotifications registers the outbox subscribers that notify users
+// about domain events.
+func SubscribeNotifications(subs *outbox.Subscribers, events repositories.EventRepository, blocks repositories.BlockRepository, tickets repositories.TicketRepository, follows repositories.FollowRepository, notifier *notifications.Dispatcher) {
+	outbox.Subscribe(subs, models.TopicInviteSent, func(ctx context.Context, inv models.InviteSent) error {
+		return notifyInvite(ctx, events, blocks, notifier, inv)
+	})
+	outbox.Subscribe(subs, models.TopicEventRescheduled, func(ctx context.Context, change models.EventRescheduled) error {
+		return notifyRescheduled(ctx, events, notifier, change)
+	})
+	outbox.Subscribe(subs, models.TopicTicketPaid, func(ctx context.Context, paid models.TicketPaid) error {
+		return sendReceipt(ctx, tickets, notifier, paid)
+	})
+	outbox.Subscribe(subs, models.TopicEventPublished, func(ctx context.Context, pub models.EventPublished) error {
+		return notifyFollowers(ctx, events, follows, blocks, notifier, pub)
+	})
+}
+
+// timeFormat is how notifications spell out event times.
+const timeFormat = "Monday, January 2, 2006 at 15:04 MST"
+
+// notifyInvite tells the invitee about their invitation. Invitations to
+// deleted events, that were withdrawn before delivery, or from an inviter the
+// invitee has blocked since, are dropped.
+func notifyInvite(ctx context.Context, events repositories.EventRepository, blocks repositories.BlockRepository, notifier *notifications.Dispatcher, inv models.InviteSent) error {
+	blocked, err := blocks.Blocks(ctx, inv.InviteeID, inv.InviterID)
+	if err != nil || blocked {
+		return err
+	}
+	event, err := events.GetForParticipant(ctx, inv.EventID, inv.InviteeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	participants, err := events.ListParticipants(ctx, inv.EventID)
+	if err != nil {
+		return err
+	}
+	var invitee *models.Participant
+	inviter := "Someone"
+	for i, p := range participants {
+		switch p.UserID {
+		case inv.InviteeID:
+			invitee = &participants[i]
+		case inv.InviterID:
+			inviter = p.UserName
+		}
+	}
+	if invitee == nil {
+		return nil
+	}
+	return notifier.Dispatch(ctx, []notifications.Recipient{{UserID: invitee.UserID, Name: invitee.UserName, Email: invitee.UserEmail}},
+		inviteMessage(inviter, event, inv.Role, inv.ExpiresAt))
+}
+
+// inviteMessage is the notification inviting someone to event as role.
+func inviteMessage(inviter string, event *models.Event, role string, expiresAt *time.Time) notifications.Message {
+	body := fmt.Sprintf("%s invited you to %s on %s as %s.", inviter, event.Title, event.StartTime.Format(timeFormat), role)
+	if expiresAt != nil {
+		body += " Please respond by " + expiresAt.Format(timeFormat) + "."
+	}
+	return notifications.Message{
+		Kind:    "invite",
+		EventID: &event.ID,
+		Subject: "You're invited to " + event.Title,
+		Body:    body,
+	}
+}
+
+// notifyRescheduled tells every participant but the one who moved the event
+// about its old and new times.
+func notifyRescheduled(ctx context.Context, events repositories.EventRepository, notifier *notifications.Dispatcher, change models.EventRescheduled) error {
+	participants, err := events.ListParticipants(ctx, change.EventID)
+	if err != nil {
+		return err
+	}
+	var to []notifications.Recipient
+	for _, p := range participants {
+		if p.UserID != change.RescheduledBy {
+			to = append(to, notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail})
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	body := fmt.Sprintf("%s has moved.\n\nWas: %s\nNow: %s", change.Title, formatSpan(change.OldStart, change.OldEnd), formatSpan(change.NewStart, change.NewEnd))
+	if change.RSVPsReset {
+		body += "\n\nPlease confirm your attendance again for the new time."
+	}
+	return notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "event_moved",
+		EventID: &change.EventID,
+		Subject: change.Title + " has moved to " + change.NewStart.Format("January 2"),
+		Body:    body,
+	})
+}
+
+func formatSpan(start time.Time, end *time.Time) string {
+	if end == nil {
+		return start.Format(timeFormat)
+	}
+	return start.Format(timeFormat) + " until " + end.Format(timeFormat)
+}
+
+// sendReceipt confirms a ticket purchase to the buyer, with the receipt
+// attached to the email.
+func sendReceipt(ctx context.Context, tickets repositories.TicketRepository, notifier *notifications.Dispatcher, paid models.TicketPaid) error {
+	r, err := tickets.GetReceipt(ctx, paid.TicketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	doc, err := RenderReceipt(r)
+	if err != nil {
+		return err
+	}
+	return notifier.Dispatch(ctx, []notifications.Recipient{{UserID: r.UserID, Name: r.BuyerName, Email: r.BuyerEmail}}, notifications.Message{
+		Kind:    "ticket_paid",
+		EventID: &r.EventID,
+		Subject: "Your ticket for " + r.EventTitle,
+		Body: fmt.Sprintf("Thanks for your purchase. Your %s ticket for %s on %s is confirmed.\n\nInvoice %s, total paid %s. The receipt is attached.",
+			r.TierName, r.EventTitle, r.EventStart.Format(timeFormat), r.InvoiceNumber, formatAmount(r.AmountCents, r.Currency)),
+		Attachments: []notifications.Attachment{{Filename: ReceiptFilename(r), ContentType: "text/html; charset=utf-8", Data: doc}},
+	})
+}
+
+// notifyFollowers tells the followers of an event's organizer and series that
+// it was published. Followers who block the organizer are skipped.
+func notifyFollowers(ctx context.Context, events repositories.EventRepository, follows repositories.FollowRepository, blocks repositories.BlockRepository, notifier *notifications.Dispatcher, pub models.EventPublished) error {
+	followers, err := follows.Followers(ctx, pub.OrganizerID, pub.SeriesID)
+	if err != nil {
+		return err
+	}
+	var to []notifications.Recipient
+	for _, f := range followers {
+		if f.ID == pub.OrganizerID {
+			continue
+		}
+		blocked, err := blocks.Blocks(ctx, f.ID, pub.OrganizerID)
+		if err != nil {
+			return err
+		}
+		if !blocked {
+			to = append(to, notifications.Recipient{UserID: f.ID, Name: f.Name, Email: f.Email})
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	event, err := events.GetForParticipant(ctx, pub.EventID, pub.OrganizerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Deleted or taken over by someone else before delivery
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if event.PublishedAt == nil || event.HiddenAt != nil {
+		// Unpublished again, or held back for review
+		return nil
+	}
+	organizer := "An organizer you follow"
+	participants, err := events.ListParticipants(ctx, pub.EventID)
+	if err != nil {
+		return err
+	}
+	for _, p := range participants {
+		if p.UserID == pub.OrganizerID {
+			organizer = p.UserName
+		}
+	}
+	return notifier.Dispatch(ctx, to, notifications.Message{
+		Kind:    "followed_event",
+		EventID: &pub.EventID,
+		Subject: "New event: " + event.Title,
+		Body: fmt.Sprintf("%s published %s on %s.\n\nSee the event page at /public/events/%s.",
+			organizer, event.Title, event.StartTime.Format(timeFormat), event.Slug),
+	})
+}
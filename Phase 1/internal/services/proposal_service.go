@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ProposalService interface {
+	Propose(ctx context.Context, eventID, userID int, req models.ProposalRequest) (*models.ChangeProposal, error)
+	List(ctx context.Context, eventID, userID int, status string) ([]models.ChangeProposal, error)
+	Get(ctx context.Context, eventID, proposalID, userID int) (*models.ChangeProposal, error)
+	Approve(ctx context.Context, eventID, proposalID, userID int, comment string) (*models.ChangeProposal, error)
+	Reject(ctx context.Context, eventID, proposalID, userID int, comment string) (*models.ChangeProposal, error)
+	Withdraw(ctx context.Context, eventID, proposalID, userID int) (*models.ChangeProposal, error)
+}
+
+type proposalService struct {
+	proposals repositories.ProposalRepository
+	events    repositories.EventRepository
+	eventsSvc EventService
+	notifier  *notifications.Dispatcher
+}
+
+func NewProposalService(proposals repositories.ProposalRepository, events repositories.EventRepository, eventsSvc EventService, notifier *notifications.Dispatcher) ProposalService {
+	return &proposalService{proposals: proposals, events: events, eventsSvc: eventsSvc, notifier: notifier}
+}
+
+// proposalDiff lists the fields c changes on event, with their current and
+// proposed values. Fields set to their current value are left out.
+func proposalDiff(event *models.Event, c models.UpdateEventRequest) []models.FieldChange {
+	diff := []models.FieldChange{}
+	add := func(field string, from, to any) {
+		if !reflect.DeepEqual(from, to) {
+			diff = append(diff, models.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	// optional turns the "remove" values of UpdateEventRequest into nil.
+	optionalInt := func(v *int) any {
+		if v == nil || *v == 0 {
+			return nil
+		}
+		return *v
+	}
+	optionalString := func(v *string) any {
+		if v == nil || *v == "" {
+			return nil
+		}
+		return *v
+	}
+	if c.Title != nil {
+		add("title", event.Title, *c.Title)
+	}
+	if c.Description != nil {
+		add("description", event.Description, *c.Description)
+	}
+	if c.Location != nil {
+		add("location", event.Location, *c.Location)
+	}
+	if c.VenueID != nil {
+		add("venueId", optionalInt(event.VenueID), optionalInt(c.VenueID))
+	}
+	if c.Type != nil {
+		add("type", event.Type, *c.Type)
+	}
+	if c.MeetingURL != nil {
+		add("meetingUrl", optionalString(event.MeetingURL), optionalString(c.MeetingURL))
+	}
+	if c.AllowTransfers != nil {
+		add("allowTransfers", event.AllowTransfers, *c.AllowTransfers)
+	}
+	if c.AutoNudgeDays != nil {
+		add("autoNudgeDays", optionalInt(event.AutoNudgeDays), optionalInt(c.AutoNudgeDays))
+	}
+	if c.RSVPVisibility != nil {
+		add("rsvpVisibility", event.RSVPVisibility, *c.RSVPVisibility)
+	}
+	return diff
+}
+
+// Propose records a change to the event for an organizer to approve
+// (requires edit_event), along with what it changes, and lets the
+// organizers know.
+func (s *proposalService) Propose(ctx context.Context, eventID, userID int, req models.ProposalRequest) (*models.ChangeProposal, error) {
+	c := req.Changes
+	switch {
+	case c.RequireChangeApproval != nil:
+		return nil, ErrNotProposable
+	case c.Title != nil && strings.TrimSpace(*c.Title) == "":
+		return nil, ErrTitleRequired
+	case c.Type != nil && *c.Type == "":
+		return nil, ErrInvalidEventType
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	diff := proposalDiff(event, c)
+	if len(diff) == 0 {
+		return nil, ErrNoChanges
+	}
+	p, err := s.proposals.Create(ctx, models.ChangeProposal{
+		EventID:    eventID,
+		ProposedBy: userID,
+		Changes:    c,
+		Diff:       diff,
+		Note:       strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, len(diff))
+	for i, d := range diff {
+		fields[i] = d.Field
+	}
+	s.notify(ctx, event, p, func(part models.Participant) bool {
+		return part.Role == "organizer" && part.UserID != userID
+	}, notifications.Message{
+		Kind:    "change_proposed",
+		Subject: fmt.Sprintf("%s: %s proposed a change", event.Title, p.ProposerName),
+		Body:    fmt.Sprintf("%s proposed to change %s of %s. Approve or reject it in the event's proposals.", p.ProposerName, strings.Join(fields, ", "), event.Title),
+	})
+	return p, nil
+}
+
+// List returns the event's proposals, optionally by status (requires
+// edit_event).
+func (s *proposalService) List(ctx context.Context, eventID, userID int, status string) ([]models.ChangeProposal, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.proposals.List(ctx, eventID, status)
+}
+
+func (s *proposalService) Get(ctx context.Context, eventID, proposalID, userID int) (*models.ChangeProposal, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.proposals.Get(ctx, eventID, proposalID)
+}
+
+// pending returns the proposal if the caller organizes the event and it still
+// waits for a decision.
+func (s *proposalService) pending(ctx context.Context, eventID, proposalID, userID int) (*models.ChangeProposal, error) {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := members[eventID]; !ok || m.Role != "organizer" {
+		return nil, ErrForbidden
+	}
+	p, err := s.proposals.Get(ctx, eventID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.ProposalPending {
+		return nil, ErrProposalDecided
+	}
+	return p, nil
+}
+
+// decide records the decision; ErrProposalDecided if someone decided first.
+func (s *proposalService) decide(ctx context.Context, eventID, proposalID int, status string, userID int, comment string) (*models.ChangeProposal, error) {
+	p, err := s.proposals.Decide(ctx, eventID, proposalID, status, userID, strings.TrimSpace(comment))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProposalDecided
+	}
+	return p, err
+}
+
+// Approve applies the proposed changes to the event as the approving
+// organizer and tells the proposer. Changes that no longer apply, e.g. a
+// meeting link for an event that became in-person, fail like the equivalent
+// PATCH and leave the proposal pending.
+func (s *proposalService) Approve(ctx context.Context, eventID, proposalID, userID int, comment string) (*models.ChangeProposal, error) {
+	p, err := s.pending(ctx, eventID, proposalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.eventsSvc.Update(ctx, eventID, userID, p.Changes)
+	if err != nil {
+		return nil, err
+	}
+	if p, err = s.decide(ctx, eventID, proposalID, models.ProposalApproved, userID, comment); err != nil {
+		return nil, err
+	}
+	s.notifyProposer(ctx, event, p, "approved")
+	return p, nil
+}
+
+// Reject turns the proposal down and tells the proposer.
+func (s *proposalService) Reject(ctx context.Context, eventID, proposalID, userID int, comment string) (*models.ChangeProposal, error) {
+	if _, err := s.pending(ctx, eventID, proposalID, userID); err != nil {
+		return nil, err
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.decide(ctx, eventID, proposalID, models.ProposalRejected, userID, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyProposer(ctx, event, p, "rejected")
+	return p, nil
+}
+
+// Withdraw lets the proposer take back a pending proposal.
+func (s *proposalService) Withdraw(ctx context.Context, eventID, proposalID, userID int) (*models.ChangeProposal, error) {
+	p, err := s.proposals.Get(ctx, eventID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if p.ProposedBy != userID {
+		return nil, ErrForbidden
+	}
+	if p.Status != models.ProposalPending {
+		return nil, ErrProposalDecided
+	}
+	return s.decide(ctx, eventID, proposalID, models.ProposalWithdrawn, userID, "")
+}
+
+func (s *proposalService) notifyProposer(ctx context.Context, event *models.Event, p *models.ChangeProposal, decision string) {
+	body := fmt.Sprintf("Your proposed change to %s was %s.", event.Title, decision)
+	if p.DecisionComment != "" {
+		body += "\n\n" + p.DecisionComment
+	}
+	s.notify(ctx, event, p, func(part models.Participant) bool {
+		return part.UserID == p.ProposedBy
+	}, notifications.Message{
+		Kind:    "proposal_" + decision,
+		Subject: fmt.Sprintf("%s: your change was %s", event.Title, decision),
+		Body:    body,
+	})
+}
+
+// notify sends msg to the event's participants matched by to. Delivery
+// failures are logged; the proposal stands.
+func (s *proposalService) notify(ctx context.Context, event *models.Event, p *models.ChangeProposal, to func(models.Participant) bool, msg notifications.Message) {
+	participants, err := s.events.ListParticipants(ctx, event.ID)
+	if err != nil {
+		log.Printf("proposal %d: loading participants: %v", p.ID, err)
+		return
+	}
+	var recipients []notifications.Recipient
+	for _, part := range participants {
+		if to(part) {
+			recipients = append(recipients, notifications.Recipient{UserID: part.UserID, Name: part.UserName, Email: part.UserEmail})
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	msg.EventID = &event.ID
+	if err := s.notifier.Dispatch(ctx, recipients, msg); err != nil {
+		log.Printf("proposal %d: delivery failed: %v", p.ID, err)
+	}
+}
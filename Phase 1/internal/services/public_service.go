@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// PublicService serves the unauthenticated views of published events.
+type PublicService interface {
+	Event(ctx context.Context, slug string) (*models.PublicEvent, error)
+}
+
+type publicService struct {
+	events   repositories.EventRepository
+	sessions repositories.SessionRepository
+	speakers repositories.SpeakerRepository
+	tickets  repositories.TicketRepository
+}
+
+func NewPublicService(events repositories.EventRepository, sessions repositories.SessionRepository, speakers repositories.SpeakerRepository, tickets repositories.TicketRepository) PublicService {
+	return &publicService{events: events, sessions: sessions, speakers: speakers, tickets: tickets}
+}
+
+// Event returns the landing page of the published event with the given slug.
+// Unknown and unpublished slugs both fail with pgx.ErrNoRows.
+func (s *publicService) Event(ctx context.Context, slug string) (*models.PublicEvent, error) {
+	e, organizer, err := s.events.GetPublished(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	page := &models.PublicEvent{
+		Slug:            e.Slug,
+		Title:           e.Title,
+		Description:     e.Description,
+		DescriptionHTML: e.DescriptionHTML,
+		Location:        e.Location,
+		StartTime:       e.StartTime,
+		EndTime:         e.EndTime,
+		Type:            e.Type,
+		Organizer:       organizer,
+		Speakers:        []models.PublicSpeaker{},
+		Agenda:          []models.PublicSession{},
+		Tiers:           []models.PublicTier{},
+		PublishedAt:     *e.PublishedAt,
+	}
+	if e.Venue != nil {
+		page.Venue = &models.PublicVenue{
+			Name:      e.Venue.Name,
+			Address:   e.Venue.Address,
+			Latitude:  e.Venue.Latitude,
+			Longitude: e.Venue.Longitude,
+		}
+	}
+
+	speakers, err := s.speakers.ListEventSpeakers(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sp := range speakers {
+		page.Speakers = append(page.Speakers, models.NewPublicSpeaker(sp))
+	}
+
+	sessions, err := s.sessions.List(ctx, e.ID, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(sessions))
+	for i, sess := range sessions {
+		ids[i] = sess.ID
+	}
+	bySession, err := s.speakers.ListSessionSpeakers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, sess := range sessions {
+		ps := models.PublicSession{
+			ID:              sess.ID,
+			Title:           sess.Title,
+			Description:     sess.Description,
+			StartTime:       sess.StartTime,
+			EndTime:         sess.EndTime,
+			Room:            sess.Room,
+			Speakers:        sess.Speakers,
+			SpeakerProfiles: []models.PublicSpeaker{},
+			Capacity:        sess.Capacity,
+		}
+		if sess.Capacity != nil {
+			remaining := max(*sess.Capacity-sess.AttendeeCount, 0)
+			ps.Remaining = &remaining
+		}
+		for _, sp := range bySession[sess.ID] {
+			ps.SpeakerProfiles = append(ps.SpeakerProfiles, models.NewPublicSpeaker(sp))
+		}
+		page.Agenda = append(page.Agenda, ps)
+	}
+
+	tiers, err := s.tickets.ListTiers(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tiers {
+		page.Tiers = append(page.Tiers, models.PublicTier{
+			ID:         t.ID,
+			Name:       t.Name,
+			PriceCents: t.PriceCents,
+			Currency:   t.Currency,
+			Quantity:   t.Quantity,
+			Remaining:  t.Remaining,
+		})
+		if page.Remaining == nil {
+			page.Remaining = new(int)
+		}
+		*page.Remaining += t.Remaining
+	}
+	return page, nil
+}
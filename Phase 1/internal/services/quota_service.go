@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// QuotaLimits are the deployment-wide quotas of every user. 0 means unlimited.
+type QuotaLimits struct {
+	ActiveEvents  int
+	InvitesPerDay int
+}
+
+// QuotaLimitsFromEnv reads the quotas from QUOTA_ACTIVE_EVENTS and
+// QUOTA_INVITES_PER_DAY. Unset, both are unlimited.
+func QuotaLimitsFromEnv() QuotaLimits {
+	return QuotaLimits{
+		ActiveEvents:  quotaFromEnv("QUOTA_ACTIVE_EVENTS"),
+		InvitesPerDay: quotaFromEnv("QUOTA_INVITES_PER_DAY"),
+	}
+}
+
+func quotaFromEnv(name string) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		log.Printf("invalid %s %q, using 0 (unlimited)", name, raw)
+		return 0
+	}
+	return v
+}
+
+// QuotaError is returned when a user has used up a quota. It matches
+// ErrEventQuota or ErrInviteQuota. RetryAfter is set when the quota frees up
+// by itself.
+type QuotaError struct {
+	Limit      int
+	RetryAfter time.Duration
+	err        error
+}
+
+func (e *QuotaError) Error() string { return e.err.Error() }
+
+func (e *QuotaError) Unwrap() error { return e.err }
+
+type QuotaService interface {
+	CheckActiveEvents(ctx context.Context, userID int) error
+	CheckInvites(ctx context.Context, userID, n int) error
+	Usage(ctx context.Context, userID int) (*models.Quotas, error)
+}
+
+type quotaService struct {
+	repo     repositories.QuotaRepository
+	defaults QuotaLimits
+}
+
+func NewQuotaService(repo repositories.QuotaRepository, defaults QuotaLimits) QuotaService {
+	return &quotaService{repo: repo, defaults: defaults}
+}
+
+// limits applies the user's overrides to the deployment defaults.
+func (s *quotaService) limits(ctx context.Context, userID int) (QuotaLimits, error) {
+	limits := s.defaults
+	overrides, err := s.repo.Overrides(ctx, userID)
+	if err != nil {
+		return limits, err
+	}
+	if overrides.ActiveEvents != nil {
+		limits.ActiveEvents = *overrides.ActiveEvents
+	}
+	if overrides.InvitesPerDay != nil {
+		limits.InvitesPerDay = *overrides.InvitesPerDay
+	}
+	return limits, nil
+}
+
+// CheckActiveEvents fails with a QuotaError if the user cannot organize
+// another event that is not archived.
+func (s *quotaService) CheckActiveEvents(ctx context.Context, userID int) error {
+	limits, err := s.limits(ctx, userID)
+	if err != nil || limits.ActiveEvents == 0 {
+		return err
+	}
+	n, err := s.repo.CountActiveEvents(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n >= limits.ActiveEvents {
+		return &QuotaError{Limit: limits.ActiveEvents, err: ErrEventQuota}
+	}
+	return nil
+}
+
+// CheckInvites fails with a QuotaError if sending n more invitations would
+// exceed the invitations allowed during the last 24 hours. RetryAfter is when
+// the oldest of them leaves the window.
+func (s *quotaService) CheckInvites(ctx context.Context, userID, n int) error {
+	limits, err := s.limits(ctx, userID)
+	if err != nil || limits.InvitesPerDay == 0 {
+		return err
+	}
+	now := time.Now()
+	sent, oldest, err := s.repo.InvitesSince(ctx, userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent+n > limits.InvitesPerDay {
+		qe := &QuotaError{Limit: limits.InvitesPerDay, err: ErrInviteQuota}
+		if oldest != nil {
+			qe.RetryAfter = oldest.Add(24 * time.Hour).Sub(now)
+		}
+		return qe
+	}
+	return nil
+}
+
+// Usage reports the user's limits and how much of them is used.
+func (s *quotaService) Usage(ctx context.Context, userID int) (*models.Quotas, error) {
+	limits, err := s.limits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.CountActiveEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invites, oldest, err := s.repo.InvitesSince(ctx, userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	q := &models.Quotas{
+		ActiveEvents:  models.QuotaUsage{Used: events, Limit: limits.ActiveEvents},
+		InvitesPerDay: models.QuotaUsage{Used: invites, Limit: limits.InvitesPerDay},
+	}
+	if limits.InvitesPerDay > 0 && invites >= limits.InvitesPerDay && oldest != nil {
+		resets := oldest.Add(24 * time.Hour)
+		q.ResetsAt = &resets
+	}
+	return q, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"unicode"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+const (
+	// maxUserReactions is how many different emoji one user can react to a
+	// comment or announcement with.
+	maxUserReactions = 20
+	// maxEmojiBytes bounds a reaction's emoji; the longest sequences, such
+	// as families and flags of subdivisions, take up to 28 bytes.
+	maxEmojiBytes = 32
+)
+
+// validEmoji reports whether s is an emoji, including sequences such as
+// 👍🏽, 👩‍💻, 🇩🇪 or 1️⃣: symbols, optionally with skin tone modifiers,
+// variation selectors and tags, and joined by zero width joiners.
+func validEmoji(s string) bool {
+	if s == "" || len(s) > maxEmojiBytes {
+		return false
+	}
+	symbols := 0
+	for _, r := range s {
+		switch {
+		case r == '\u200d', r == '\ufe0f', r >= 0x1f3fb && r <= 0x1f3ff, r >= 0xe0020 && r <= 0xe007f:
+		case r == '#', r == '*', r >= '0' && r <= '9':
+			// keycap bases, counted by the keycap that follows them
+		case r == '\u20e3', unicode.Is(unicode.So, r):
+			symbols++
+		default:
+			return false
+		}
+	}
+	return symbols > 0
+}
+
+// react adds or removes the user's reaction with emoji to the target with
+// id and returns its reactions afterwards. The caller checks that the user
+// may see the target.
+func react(ctx context.Context, reactions repositories.ReactionRepository, target string, id, userID int, emoji string, add bool) ([]models.Reaction, error) {
+	if !validEmoji(emoji) {
+		return nil, ErrInvalidEmoji
+	}
+	if add {
+		ok, err := reactions.Add(ctx, target, id, userID, emoji, maxUserReactions)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrTooManyReactions
+		}
+	} else if err := reactions.Remove(ctx, target, id, userID, emoji); err != nil {
+		return nil, err
+	}
+	counts, err := reactions.Counts(ctx, target, []int{id}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return reactionsOf(counts, id), nil
+}
+
+// reactionsOf returns the reactions to id in counts, an empty list when
+// there are none.
+func reactionsOf(counts map[int][]models.Reaction, id int) []models.Reaction {
+	if res := counts[id]; res != nil {
+		return res
+	}
+	return []models.Reaction{}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"eventplanner-backend/internal/models"
+)
+
+// receiptTemplate renders a receipt as a standalone HTML page that prints
+// cleanly, so buyers can save it as a PDF from any browser.
+var receiptTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"amount":   formatAmount,
+	"date":     func(t time.Time) string { return t.Format("January 2, 2006") },
+	"subtotal": func(r *models.Receipt) int { return r.AmountCents + r.DiscountCents },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.InvoiceNumber}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; color: #222; }
+table { width: 100%; border-collapse: collapse; margin: 1.5em 0; }
+th, td { text-align: left; padding: .4em 0; border-bottom: 1px solid #ddd; }
+td.num, th.num { text-align: right; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>Receipt</h1>
+<p class="meta">Invoice {{.InvoiceNumber}} &middot; Issued {{date .IssuedAt}}{{with .PaymentID}} &middot; Payment {{.}}{{end}}</p>
+<p><strong>Seller</strong><br>{{.SellerName}}</p>
+<p><strong>Billed to</strong><br>{{.BuyerName}}<br>{{.BuyerEmail}}</p>
+<table>
+<tr><th>Item</th><th class="num">Amount</th></tr>
+<tr><td>{{.TierName}} ticket: {{.EventTitle}}, {{date .EventStart}}</td><td class="num">{{amount (subtotal .) .Currency}}</td></tr>
+{{- if .DiscountCents}}
+<tr><td>Discount</td><td class="num">-{{amount .DiscountCents .Currency}}</td></tr>
+{{- end}}
+<tr><th>Total paid</th><th class="num">{{amount .AmountCents .Currency}}</th></tr>
+</table>
+</body>
+</html>
+`))
+
+// RenderReceipt returns the receipt as an HTML document.
+func RenderReceipt(r *models.Receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := receiptTemplate.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReceiptFilename is the name receipts are downloaded and attached as.
+func ReceiptFilename(r *models.Receipt) string {
+	return "receipt-" + r.InvoiceNumber + ".html"
+}
+
+// formatAmount writes an amount in minor units with two decimals and the
+// currency code, e.g. "12.50 EUR".
+func formatAmount(cents int, currency string) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, cents/100, cents%100, currency)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultReportHideThreshold is how many pending reports hide a published
+// event unless REPORT_HIDE_THRESHOLD says otherwise.
+const DefaultReportHideThreshold = 5
+
+// defaultReportQueueLimit is the page size of the review queue.
+const defaultReportQueueLimit = 50
+
+// ReportHideThresholdFromEnv reads REPORT_HIDE_THRESHOLD. 0 turns automatic
+// hiding off.
+func ReportHideThresholdFromEnv() int {
+	raw := os.Getenv("REPORT_HIDE_THRESHOLD")
+	if raw == "" {
+		return DefaultReportHideThreshold
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		log.Printf("invalid REPORT_HIDE_THRESHOLD %q, using %d", raw, DefaultReportHideThreshold)
+		return DefaultReportHideThreshold
+	}
+	return v
+}
+
+// AdminIDsFromEnv reads the ids of the users allowed to review reports from
+// ADMIN_USER_IDS, separated by commas.
+func AdminIDsFromEnv() map[int]bool {
+	admins := map[int]bool{}
+	for _, raw := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			log.Printf("invalid id %q in ADMIN_USER_IDS, ignoring it", raw)
+			continue
+		}
+		admins[id] = true
+	}
+	return admins
+}
+
+type ReportService interface {
+	ReportEvent(ctx context.Context, eventID, userID int, req models.ReportRequest) (*models.Report, error)
+	ReportUser(ctx context.Context, reportedID, userID int, req models.ReportRequest) (*models.Report, error)
+	Queue(ctx context.Context, userID int, filter models.ReportQueueFilter) ([]models.Report, error)
+	Review(ctx context.Context, reportID, userID int, review models.ReportReview) (*models.Report, error)
+}
+
+type reportService struct {
+	repo          repositories.ReportRepository
+	admins        map[int]bool
+	hideThreshold int
+}
+
+func NewReportService(repo repositories.ReportRepository, admins map[int]bool, hideThreshold int) ReportService {
+	return &reportService{repo: repo, admins: admins, hideThreshold: hideThreshold}
+}
+
+// ReportEvent reports an event the caller can see: a published one or one
+// they take part in. Others are reported as not found. A published event with
+// hideThreshold pending reports is hidden from its public page.
+func (s *reportService) ReportEvent(ctx context.Context, eventID, userID int, req models.ReportRequest) (*models.Report, error) {
+	visible, err := s.repo.CanSeeEvent(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, pgx.ErrNoRows
+	}
+	report, err := s.repo.ReportEvent(ctx, models.Report{
+		ReporterID: &userID,
+		EventID:    &eventID,
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+	}, s.hideThreshold)
+	if isUniqueViolation(err, "idx_reports_pending_event") {
+		return nil, ErrAlreadyReported
+	}
+	return report, err
+}
+
+func (s *reportService) ReportUser(ctx context.Context, reportedID, userID int, req models.ReportRequest) (*models.Report, error) {
+	if reportedID == userID {
+		return nil, ErrReportSelf
+	}
+	report, err := s.repo.ReportUser(ctx, models.Report{
+		ReporterID: &userID,
+		UserID:     &reportedID,
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+	})
+	if err != nil {
+		switch {
+		case isUniqueViolation(err, "idx_reports_pending_user"):
+			return nil, ErrAlreadyReported
+		case isForeignKeyViolation(err, "reports_user_id_fkey"):
+			return nil, ErrUnknownUser
+		}
+	}
+	return report, err
+}
+
+// Queue lists reports for admins, pending ones by default, oldest first.
+func (s *reportService) Queue(ctx context.Context, userID int, filter models.ReportQueueFilter) ([]models.Report, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	if filter.Status == "" {
+		filter.Status = models.ReportPending
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultReportQueueLimit
+	}
+	return s.repo.List(ctx, filter.Status, filter.Limit)
+}
+
+// Review dismisses or actions a report (admins only).
+func (s *reportService) Review(ctx context.Context, reportID, userID int, review models.ReportReview) (*models.Report, error) {
+	if !s.admins[userID] {
+		return nil, ErrForbidden
+	}
+	return s.repo.Review(ctx, reportID, userID, review.Status, s.hideThreshold)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/repositories"
+)
+
+// RetentionPolicy maps retention targets to how long their rows are kept.
+// Targets with no window, or a zero one, are never purged.
+type RetentionPolicy map[string]time.Duration
+
+// DefaultRetentionDays are the windows used unless RETENTION_<TARGET>_DAYS
+// says otherwise. Archived events are only purged when configured.
+var DefaultRetentionDays = map[string]int{
+	repositories.RetentionNotifications:  180,
+	repositories.RetentionExpiredInvites: 30,
+	repositories.RetentionArchivedEvents: 0,
+	repositories.RetentionOutbox:         30,
+	repositories.RetentionDeadJobs:       90,
+	repositories.RetentionDeliveries:     180,
+	repositories.RetentionLinkPreviews:   30,
+}
+
+// RetentionPolicyFromEnv reads each target's window in days from
+// RETENTION_<TARGET>_DAYS, e.g. RETENTION_NOTIFICATIONS_DAYS, falling back to
+// DefaultRetentionDays. 0 turns purging of a target off.
+func RetentionPolicyFromEnv() RetentionPolicy {
+	policy := RetentionPolicy{}
+	for target, days := range DefaultRetentionDays {
+		name := "RETENTION_" + strings.ToUpper(target) + "_DAYS"
+		if raw := os.Getenv(name); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 0 {
+				log.Printf("invalid %s %q, using %d", name, raw, days)
+			} else {
+				days = v
+			}
+		}
+		policy[target] = time.Duration(days) * 24 * time.Hour
+	}
+	return policy
+}
+
+type RetentionService interface {
+	Purge(ctx context.Context) (map[string]int64, error)
+}
+
+type retentionService struct {
+	repo   repositories.RetentionRepository
+	policy RetentionPolicy
+}
+
+func NewRetentionService(repo repositories.RetentionRepository, policy RetentionPolicy) RetentionService {
+	return &retentionService{repo: repo, policy: policy}
+}
+
+// Purge deletes the rows of every target that are older than its window and
+// records the count per target in retention_purges. A failing target does not
+// stop the others; the counts include rows deleted before a failure.
+func (s *retentionService) Purge(ctx context.Context) (map[string]int64, error) {
+	targets := make([]string, 0, len(s.policy))
+	for target, window := range s.policy {
+		if window > 0 {
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+
+	purged := map[string]int64{}
+	var errs []error
+	for _, target := range targets {
+		before := time.Now().Add(-s.policy[target])
+		n, err := s.repo.Purge(ctx, target, before)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target, err))
+		}
+		purged[target] = n
+		if err := s.repo.RecordPurge(ctx, target, before, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: recording purge: %w", target, err))
+		}
+	}
+	return purged, errors.Join(errs...)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RideService interface {
+	Offer(ctx context.Context, eventID, userID int, req models.RideRequest) (*models.Ride, error)
+	Update(ctx context.Context, eventID, rideID, userID int, req models.RideRequest) (*models.Ride, error)
+	Delete(ctx context.Context, eventID, rideID, userID int) error
+	List(ctx context.Context, eventID, userID int) ([]models.Ride, error)
+	Join(ctx context.Context, eventID, rideID, userID int) (*models.Ride, error)
+	Leave(ctx context.Context, eventID, rideID, userID int) error
+}
+
+type rideService struct {
+	rides    repositories.RideRepository
+	events   repositories.EventRepository
+	notifier *notifications.Dispatcher
+}
+
+func NewRideService(rides repositories.RideRepository, events repositories.EventRepository, notifier *notifications.Dispatcher) RideService {
+	return &rideService{rides: rides, events: events, notifier: notifier}
+}
+
+// event returns the event if userID participates in it, else ErrForbidden.
+func (s *rideService) event(ctx context.Context, eventID, userID int) (*models.Event, error) {
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrForbidden
+	}
+	return event, err
+}
+
+func rideFromRequest(eventID, userID int, req models.RideRequest) models.Ride {
+	return models.Ride{
+		EventID:     eventID,
+		DriverID:    userID,
+		Origin:      strings.TrimSpace(req.Origin),
+		DepartureAt: req.DepartureAt,
+		Seats:       req.Seats,
+		Notes:       strings.TrimSpace(req.Notes),
+	}
+}
+
+// Offer lists a ride to the event driven by the caller (participants).
+func (s *rideService) Offer(ctx context.Context, eventID, userID int, req models.RideRequest) (*models.Ride, error) {
+	if _, err := s.event(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.rides.Create(ctx, rideFromRequest(eventID, userID, req))
+}
+
+// Update changes the caller's ride. Seats cannot drop below the passengers
+// already riding, who are told about the change.
+func (s *rideService) Update(ctx context.Context, eventID, rideID, userID int, req models.RideRequest) (*models.Ride, error) {
+	event, err := s.event(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ride := rideFromRequest(eventID, userID, req)
+	ride.ID = rideID
+	updated, err := s.rides.Update(ctx, ride)
+	if errors.Is(err, pgx.ErrNoRows) {
+		current, getErr := s.rides.Get(ctx, eventID, rideID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if current.DriverID != userID {
+			return nil, ErrForbidden
+		}
+		return nil, ErrSeatsBelowTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.notifyPassengers(ctx, event, updated, "changed", fmt.Sprintf(
+		"%s changed their ride to %s: leaving from %s at %s.",
+		updated.DriverName, event.Title, updated.Origin, updated.DepartureAt.Format("Mon 2 Jan 15:04 MST")))
+	return updated, nil
+}
+
+// Delete cancels a ride (its driver, or manage_participants) and tells its
+// passengers they need another way to get there.
+func (s *rideService) Delete(ctx context.Context, eventID, rideID, userID int) error {
+	event, err := s.event(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	ride, err := s.rides.Get(ctx, eventID, rideID)
+	if err != nil {
+		return err
+	}
+	if ride.DriverID != userID {
+		if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+			return err
+		}
+	}
+	if err := s.rides.Delete(ctx, eventID, rideID); err != nil {
+		return err
+	}
+	s.notifyPassengers(ctx, event, ride, "cancelled", fmt.Sprintf(
+		"%s's ride to %s from %s was cancelled. You no longer have a seat.",
+		ride.DriverName, event.Title, ride.Origin))
+	return nil
+}
+
+// List returns the event's rides with their passengers to any participant.
+func (s *rideService) List(ctx context.Context, eventID, userID int) ([]models.Ride, error) {
+	if _, err := s.event(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.rides.List(ctx, eventID)
+}
+
+// Join takes a seat in a ride for the caller, one ride per event, and lets
+// the driver know.
+func (s *rideService) Join(ctx context.Context, eventID, rideID, userID int) (*models.Ride, error) {
+	event, err := s.event(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ride, err := s.rides.Get(ctx, eventID, rideID)
+	if err != nil {
+		return nil, err
+	}
+	if ride.DriverID == userID {
+		return nil, ErrOwnRide
+	}
+	err = s.rides.Join(ctx, eventID, rideID, userID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if _, getErr := s.rides.Get(ctx, eventID, rideID); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrRideFull
+	case isUniqueViolation(err, "ride_passengers_pkey", "ride_passengers_event_id_user_id_key"):
+		return nil, ErrAlreadyRiding
+	case err != nil:
+		return nil, err
+	}
+
+	ride, err = s.rides.Get(ctx, eventID, rideID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyDriver(ctx, event, ride, userID, "joined", "took a seat in")
+	return ride, nil
+}
+
+// Leave gives up the caller's seat in a ride and lets the driver know.
+func (s *rideService) Leave(ctx context.Context, eventID, rideID, userID int) error {
+	event, err := s.event(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	ride, err := s.rides.Get(ctx, eventID, rideID)
+	if err != nil {
+		return err
+	}
+	if err := s.rides.Leave(ctx, eventID, rideID, userID); err != nil {
+		return err
+	}
+	ride.SeatsLeft++
+	s.notifyDriver(ctx, event, ride, userID, "left", "gave up their seat in")
+	return nil
+}
+
+// notifyDriver tells the driver that a passenger joined or left their ride.
+func (s *rideService) notifyDriver(ctx context.Context, event *models.Event, ride *models.Ride, passengerID int, what, action string) {
+	name := "A participant"
+	for _, p := range ride.Passengers {
+		if p.UserID == passengerID {
+			name = p.Name
+		}
+	}
+	msg := notifications.Message{
+		Kind:    "ride",
+		EventID: &event.ID,
+		Subject: fmt.Sprintf("%s: a passenger %s your ride", event.Title, what),
+		Body:    fmt.Sprintf("%s %s your ride from %s. Seats left: %d.", name, action, ride.Origin, ride.SeatsLeft),
+	}
+	driver := notifications.Recipient{UserID: ride.DriverID, Name: ride.DriverName, Email: ride.DriverEmail}
+	if err := s.notifier.Dispatch(ctx, []notifications.Recipient{driver}, msg); err != nil {
+		log.Printf("ride %d: delivery failed: %v", ride.ID, err)
+	}
+}
+
+// notifyPassengers tells a ride's passengers that it changed or was cancelled.
+func (s *rideService) notifyPassengers(ctx context.Context, event *models.Event, ride *models.Ride, what, body string) {
+	if len(ride.Passengers) == 0 {
+		return
+	}
+	recipients := make([]notifications.Recipient, len(ride.Passengers))
+	for i, p := range ride.Passengers {
+		recipients[i] = notifications.Recipient{UserID: p.UserID, Name: p.Name, Email: p.Email}
+	}
+	msg := notifications.Message{
+		Kind:    "ride",
+		EventID: &event.ID,
+		Subject: fmt.Sprintf("%s: your ride was %s", event.Title, what),
+		Body:    body,
+	}
+	if err := s.notifier.Dispatch(ctx, recipients, msg); err != nil {
+		log.Printf("ride %d: delivery failed: %v", ride.ID, err)
+	}
+}
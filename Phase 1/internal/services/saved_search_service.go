@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/repositories"
+)
+
+// maxAlertTitles caps the event titles listed in one saved search alert.
+const maxAlertTitles = 10
+
+type SavedSearchService interface {
+	Create(ctx context.Context, userID int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	Update(ctx context.Context, id, userID int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	Get(ctx context.Context, id, userID int) (*models.SavedSearch, error)
+	List(ctx context.Context, userID int) ([]models.SavedSearch, error)
+	Delete(ctx context.Context, id, userID int) error
+	Run(ctx context.Context, id, userID int) (*models.SearchResults, error)
+	MatchAlerts(ctx context.Context) (int, error)
+}
+
+type savedSearchService struct {
+	searches repositories.SavedSearchRepository
+	search   SearchService
+	notifier *notifications.Dispatcher
+}
+
+func NewSavedSearchService(searches repositories.SavedSearchRepository, search SearchService, notifier *notifications.Dispatcher) SavedSearchService {
+	return &savedSearchService{searches: searches, search: search, notifier: notifier}
+}
+
+func savedSearchFromRequest(userID int, req models.SavedSearchRequest) (models.SavedSearch, error) {
+	s := models.SavedSearch{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Query:  strings.TrimSpace(req.Query),
+		Role:   req.Role,
+		Alerts: req.Alerts,
+	}
+	// The request binding already checked the date format.
+	if req.From != "" {
+		from, _ := time.Parse("2006-01-02", req.From)
+		s.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.Parse("2006-01-02", req.To)
+		s.To = &to
+	}
+	if s.From != nil && s.To != nil && s.To.Before(*s.From) {
+		return s, ErrInvalidTimeRange
+	}
+	return s, nil
+}
+
+func (s *savedSearchService) Create(ctx context.Context, userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := savedSearchFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.searches.Create(ctx, search)
+	if isUniqueViolation(err, "saved_searches_user_id_name_key") {
+		return nil, ErrSavedSearchExists
+	}
+	return created, err
+}
+
+func (s *savedSearchService) Update(ctx context.Context, id, userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := savedSearchFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	search.ID = id
+	updated, err := s.searches.Update(ctx, search)
+	if isUniqueViolation(err, "saved_searches_user_id_name_key") {
+		return nil, ErrSavedSearchExists
+	}
+	return updated, err
+}
+
+func (s *savedSearchService) Get(ctx context.Context, id, userID int) (*models.SavedSearch, error) {
+	return s.searches.Get(ctx, id, userID)
+}
+
+func (s *savedSearchService) List(ctx context.Context, userID int) ([]models.SavedSearch, error) {
+	return s.searches.List(ctx, userID)
+}
+
+func (s *savedSearchService) Delete(ctx context.Context, id, userID int) error {
+	return s.searches.Delete(ctx, id, userID)
+}
+
+// filter turns a saved search into search criteria. The end date includes
+// the whole day, as on GET /search.
+func (s *savedSearchService) filter(search models.SavedSearch) models.SearchFilter {
+	f := models.SearchFilter{Query: search.Query, From: search.From, Role: search.Role}
+	if search.To != nil {
+		to := search.To.Add(24*time.Hour - time.Second)
+		f.To = &to
+	}
+	return f
+}
+
+// Run executes a saved search for its owner.
+func (s *savedSearchService) Run(ctx context.Context, id, userID int) (*models.SearchResults, error) {
+	search, err := s.searches.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, tasks, err := s.search.Search(ctx, userID, s.filter(*search))
+	if err != nil {
+		return nil, err
+	}
+	res := &models.SearchResults{Events: events, Tasks: tasks}
+	if res.Events == nil {
+		res.Events = []models.Event{}
+	}
+	if res.Tasks == nil {
+		res.Tasks = []models.Task{}
+	}
+	return res, nil
+}
+
+// MatchAlerts notifies the owners of alerting saved searches about events
+// published since the previous run that match their query and dates. The
+// role filter only applies when a search is run, since alerts are about
+// public events the owner does not take part in yet. It returns the number
+// of alerts sent.
+func (s *savedSearchService) MatchAlerts(ctx context.Context) (int, error) {
+	alerts, err := s.searches.ListAlerting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var errs []error
+	for _, a := range alerts {
+		until := time.Now()
+		f := s.filter(a.SavedSearch)
+		f.Role = ""
+		f.PublishedAfter, f.PublishedBefore = &a.LastCheckedAt, &until
+		events, _, err := s.search.Search(ctx, 0, f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("saved search %d: %w", a.ID, err))
+			continue
+		}
+		var matches []models.Event
+		for _, e := range events {
+			if e.OrganizerID != a.UserID {
+				matches = append(matches, e)
+			}
+		}
+		if len(matches) > 0 {
+			recipient := notifications.Recipient{UserID: a.UserID, Name: a.UserName, Email: a.UserEmail}
+			if err := s.notifier.Dispatch(ctx, []notifications.Recipient{recipient}, alertMessage(a.Name, matches)); err != nil {
+				log.Printf("saved search %d: alert delivery failed: %v", a.ID, err)
+			}
+			sent++
+		}
+		if err := s.searches.MarkChecked(ctx, a.ID, until); err != nil {
+			errs = append(errs, fmt.Errorf("saved search %d: %w", a.ID, err))
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+func alertMessage(name string, events []models.Event) notifications.Message {
+	msg := notifications.Message{Kind: "saved_search"}
+	if len(events) == 1 {
+		msg.EventID = &events[0].ID
+		msg.Subject = fmt.Sprintf("New event for \"%s\": %s", name, events[0].Title)
+	} else {
+		msg.Subject = fmt.Sprintf("%d new events for \"%s\"", len(events), name)
+	}
+	var b strings.Builder
+	for i, e := range events {
+		if i == maxAlertTitles {
+			fmt.Fprintf(&b, "and %d more\n", len(events)-i)
+			break
+		}
+		fmt.Fprintf(&b, "%s (%s) /public/events/%s\n", e.Title, e.StartTime.Format("2006-01-02"), e.Slug)
+	}
+	msg.Body = strings.TrimSuffix(b.String(), "\n")
+	return msg
+}
@@ -2,24 +2,54 @@ package services
 
 import (
 	"context"
-	"time"
+	"log"
+	"os"
+	"strconv"
 
+	"eventplanner-backend/internal/flags"
 	"eventplanner-backend/internal/models"
 	"eventplanner-backend/internal/repositories"
 )
 
+// DefaultSearchSimilarity is the pg_trgm word similarity above which a
+// misspelled search term still matches ("birhtday" finds "birthday").
+const DefaultSearchSimilarity = 0.4
+
 type SearchService interface {
-	Search(ctx context.Context, userID int, q string, from, to *time.Time, role string) ([]models.Event, []models.Task, error)
+	Search(ctx context.Context, userID int, f models.SearchFilter) ([]models.Event, []models.Task, error)
 }
 
 type searchService struct {
-	events repositories.EventRepository
+	events     repositories.EventRepository
+	similarity float64
+	flags      *flags.Flags
+}
+
+// NewSearchService returns a search service matching query terms fuzzily at
+// the given similarity threshold, for users with the fuzzy search feature; 0
+// turns fuzzy matching off for everyone.
+func NewSearchService(events repositories.EventRepository, similarity float64, featureFlags *flags.Flags) SearchService {
+	return &searchService{events: events, similarity: similarity, flags: featureFlags}
 }
 
-func NewSearchService(events repositories.EventRepository) SearchService {
-	return &searchService{events: events}
+// SearchSimilarityFromEnv reads the fuzzy matching threshold from
+// SEARCH_SIMILARITY, falling back to DefaultSearchSimilarity.
+func SearchSimilarityFromEnv() float64 {
+	raw := os.Getenv("SEARCH_SIMILARITY")
+	if raw == "" {
+		return DefaultSearchSimilarity
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 || v > 1 {
+		log.Printf("invalid SEARCH_SIMILARITY %q, using %v", raw, DefaultSearchSimilarity)
+		return DefaultSearchSimilarity
+	}
+	return v
 }
 
-func (s *searchService) Search(ctx context.Context, userID int, q string, from, to *time.Time, role string) ([]models.Event, []models.Task, error) {
-	return s.events.Search(ctx, userID, q, from, to, role)
+func (s *searchService) Search(ctx context.Context, userID int, f models.SearchFilter) ([]models.Event, []models.Task, error) {
+	if s.flags.Enabled(ctx, flags.FuzzySearch, userID) {
+		f.Similarity = s.similarity
+	}
+	return s.events.Search(ctx, userID, f)
 }
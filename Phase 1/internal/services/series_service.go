@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type SeriesService interface {
+	Create(ctx context.Context, userID int, req models.SeriesRequest) (*models.Series, error)
+	Update(ctx context.Context, seriesID, userID int, req models.SeriesRequest) (*models.Series, error)
+	Get(ctx context.Context, seriesID, userID int) (*models.Series, error)
+	List(ctx context.Context, userID int) ([]models.Series, error)
+	Delete(ctx context.Context, seriesID, userID int) error
+	Link(ctx context.Context, eventID, userID, seriesID int) (*models.Series, error)
+	Unlink(ctx context.Context, eventID, userID int) error
+	Subscribe(ctx context.Context, seriesID, userID int) (*models.Series, error)
+	Unsubscribe(ctx context.Context, seriesID, userID int) error
+	Subscribers(ctx context.Context, seriesID, userID int) ([]models.SeriesSubscriber, error)
+}
+
+type seriesService struct {
+	series repositories.SeriesRepository
+	events repositories.EventRepository
+	blocks repositories.BlockRepository
+}
+
+func NewSeriesService(series repositories.SeriesRepository, events repositories.EventRepository, blocks repositories.BlockRepository) SeriesService {
+	return &seriesService{series: series, events: events, blocks: blocks}
+}
+
+func seriesFromRequest(req models.SeriesRequest) models.Series {
+	s := models.Series{
+		Name:             strings.TrimSpace(req.Name),
+		Description:      strings.TrimSpace(req.Description),
+		DefaultRole:      req.DefaultRole,
+		InviteExpiryDays: req.InviteExpiryDays,
+	}
+	if s.DefaultRole == "" {
+		s.DefaultRole = "attendee"
+	}
+	return s
+}
+
+// Create starts a series owned by the caller.
+func (s *seriesService) Create(ctx context.Context, userID int, req models.SeriesRequest) (*models.Series, error) {
+	series := seriesFromRequest(req)
+	if series.Name == "" {
+		return nil, ErrNameRequired
+	}
+	series.OwnerID = userID
+	return s.series.Create(ctx, series)
+}
+
+// Update replaces the series' details and defaults (owner only).
+func (s *seriesService) Update(ctx context.Context, seriesID, userID int, req models.SeriesRequest) (*models.Series, error) {
+	if err := s.requireOwner(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	series := seriesFromRequest(req)
+	if series.Name == "" {
+		return nil, ErrNameRequired
+	}
+	series.ID, series.OwnerID = seriesID, userID
+	return s.series.Update(ctx, series)
+}
+
+// requireOwner returns pgx.ErrNoRows for a missing series and ErrForbidden
+// unless userID owns it.
+func (s *seriesService) requireOwner(ctx context.Context, seriesID, userID int) error {
+	series, err := s.series.Get(ctx, seriesID, userID)
+	if err != nil {
+		return err
+	}
+	if series.OwnerID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// requireMember returns ErrForbidden unless userID owns the series,
+// subscribes to it or participates in one of its events.
+func (s *seriesService) requireMember(ctx context.Context, seriesID, userID int) error {
+	member, err := s.series.IsMember(ctx, seriesID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Get returns the series with its occurrences to its members.
+func (s *seriesService) Get(ctx context.Context, seriesID, userID int) (*models.Series, error) {
+	if err := s.requireMember(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	series, err := s.series.Get(ctx, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+	series.Occurrences, err = s.series.Occurrences(ctx, seriesID)
+	return series, err
+}
+
+// List returns the series the caller owns or subscribes to.
+func (s *seriesService) List(ctx context.Context, userID int) ([]models.Series, error) {
+	return s.series.ListForUser(ctx, userID)
+}
+
+// Delete removes the series (owner only). Its events are kept.
+func (s *seriesService) Delete(ctx context.Context, seriesID, userID int) error {
+	if err := s.requireOwner(ctx, seriesID, userID); err != nil {
+		return err
+	}
+	return s.series.Delete(ctx, seriesID, userID)
+}
+
+// Link makes the event an occurrence of the series. The caller needs
+// edit_event on the event, must own the series and hold every permission of
+// its default role, since subscribers are invited with that role on their
+// behalf. Subscribers who block the caller are skipped, as with Invite.
+func (s *seriesService) Link(ctx context.Context, eventID, userID, seriesID int) (*models.Series, error) {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := members[eventID]
+	if !ok || !m.Has(models.PermEditEvent) {
+		return nil, ErrForbidden
+	}
+	series, err := s.series.Get(ctx, seriesID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	if !covers(m.Permissions(), models.PermissionsFor(series.DefaultRole)) {
+		return nil, ErrForbidden
+	}
+	if err := s.series.Link(ctx, eventID, seriesID); err != nil {
+		return nil, err
+	}
+
+	invitees, err := s.series.Uninvited(ctx, seriesID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	var expiresAt *time.Time
+	if series.InviteExpiryDays != nil {
+		t := time.Now().AddDate(0, 0, *series.InviteExpiryDays)
+		expiresAt = &t
+	}
+	for _, inviteeID := range invitees {
+		blocked, err := s.blocks.Blocks(ctx, inviteeID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			continue
+		}
+		if err := s.events.Invite(ctx, eventID, userID, inviteeID, series.DefaultRole, expiresAt); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, seriesID, userID)
+}
+
+// Unlink takes the event out of its series (requires edit_event). Its
+// participants stay invited.
+func (s *seriesService) Unlink(ctx context.Context, eventID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.series.Unlink(ctx, eventID)
+}
+
+// Subscribe signs the caller up for invitations to future occurrences. Only
+// members of the series, such as participants of an earlier occurrence, can
+// subscribe.
+func (s *seriesService) Subscribe(ctx context.Context, seriesID, userID int) (*models.Series, error) {
+	if _, err := s.series.Get(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.requireMember(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.series.Subscribe(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, seriesID, userID)
+}
+
+func (s *seriesService) Unsubscribe(ctx context.Context, seriesID, userID int) error {
+	return s.series.Unsubscribe(ctx, seriesID, userID)
+}
+
+// Subscribers lists who subscribes to the series (owner only).
+func (s *seriesService) Subscribers(ctx context.Context, seriesID, userID int) ([]models.SeriesSubscriber, error) {
+	if err := s.requireOwner(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	return s.series.Subscribers(ctx, seriesID)
+}
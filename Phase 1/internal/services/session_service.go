@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionService interface {
+	Create(ctx context.Context, eventID, userID int, req models.EventSessionRequest) (*models.EventSession, error)
+	Update(ctx context.Context, eventID, sessionID, userID int, req models.EventSessionRequest) (*models.EventSession, error)
+	Delete(ctx context.Context, eventID, sessionID, userID int) error
+	List(ctx context.Context, eventID, userID int) ([]models.EventSession, error)
+	Agenda(ctx context.Context, eventID, userID int) ([]models.EventSession, error)
+	Attend(ctx context.Context, eventID, sessionID, userID int) error
+	Leave(ctx context.Context, eventID, sessionID, userID int) error
+}
+
+type sessionService struct {
+	sessions repositories.SessionRepository
+	events   repositories.EventRepository
+	speakers repositories.SpeakerRepository
+}
+
+func NewSessionService(sessions repositories.SessionRepository, events repositories.EventRepository, speakers repositories.SpeakerRepository) SessionService {
+	return &sessionService{sessions: sessions, events: events, speakers: speakers}
+}
+
+// sessionFromRequest validates the request against the event's time span:
+// sessions may not start before the event, nor end after it when the event
+// has an end time.
+func (s *sessionService) sessionFromRequest(ctx context.Context, eventID, userID int, req models.EventSessionRequest) (models.EventSession, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return models.EventSession{}, ErrInvalidTimeRange
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return models.EventSession{}, err
+	}
+	if req.StartTime.Before(event.StartTime) || (event.EndTime != nil && req.EndTime.After(*event.EndTime)) {
+		return models.EventSession{}, ErrSessionOutOfRange
+	}
+	speakers := []string{}
+	for _, sp := range req.Speakers {
+		if sp = strings.TrimSpace(sp); sp != "" {
+			speakers = append(speakers, sp)
+		}
+	}
+	return models.EventSession{
+		EventID:     eventID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Room:        strings.TrimSpace(req.Room),
+		Speakers:    speakers,
+		Capacity:    req.Capacity,
+	}, nil
+}
+
+// Create adds a session to the event's agenda (requires edit_event).
+func (s *sessionService) Create(ctx context.Context, eventID, userID int, req models.EventSessionRequest) (*models.EventSession, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	session, err := s.sessionFromRequest(ctx, eventID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessions.Create(ctx, session)
+}
+
+// Update changes a session (requires edit_event). The capacity cannot drop
+// below the number of participants who already added it to their agenda.
+func (s *sessionService) Update(ctx context.Context, eventID, sessionID, userID int, req models.EventSessionRequest) (*models.EventSession, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	session, err := s.sessionFromRequest(ctx, eventID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	session.ID = sessionID
+	updated, err := s.sessions.Update(ctx, session)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, getErr := s.sessions.Get(ctx, eventID, sessionID); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrCapacityTooLow
+	}
+	return updated, err
+}
+
+func (s *sessionService) Delete(ctx context.Context, eventID, sessionID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.sessions.Delete(ctx, eventID, sessionID)
+}
+
+// List returns the event's full agenda to any participant.
+func (s *sessionService) List(ctx context.Context, eventID, userID int) ([]models.EventSession, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.List(ctx, eventID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSpeakers(ctx, sessions)
+}
+
+// Agenda returns the sessions the caller added to their personal agenda.
+func (s *sessionService) Agenda(ctx context.Context, eventID, userID int) ([]models.EventSession, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.List(ctx, eventID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSpeakers(ctx, sessions)
+}
+
+// withSpeakers fills in the speaker profiles of the listed sessions.
+func (s *sessionService) withSpeakers(ctx context.Context, sessions []models.EventSession) ([]models.EventSession, error) {
+	ids := make([]int, len(sessions))
+	for i, sess := range sessions {
+		ids[i] = sess.ID
+	}
+	bySession, err := s.speakers.ListSessionSpeakers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].SpeakerProfiles = bySession[sessions[i].ID]
+		if sessions[i].SpeakerProfiles == nil {
+			sessions[i].SpeakerProfiles = []models.Speaker{}
+		}
+	}
+	return sessions, nil
+}
+
+// Attend adds a session to the caller's personal agenda, subject to its capacity.
+func (s *sessionService) Attend(ctx context.Context, eventID, sessionID, userID int) error {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return err
+	}
+	err := s.sessions.Attend(ctx, eventID, sessionID, userID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if _, getErr := s.sessions.Get(ctx, eventID, sessionID); getErr != nil {
+			return getErr
+		}
+		return ErrSessionFull
+	case isUniqueViolation(err, "session_attendees_pkey"):
+		return ErrAlreadyInAgenda
+	}
+	return err
+}
+
+func (s *sessionService) Leave(ctx context.Context, eventID, sessionID, userID int) error {
+	return s.sessions.Leave(ctx, eventID, sessionID, userID)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SpeakerService interface {
+	Create(ctx context.Context, userID int, req models.SpeakerRequest) (*models.Speaker, error)
+	Update(ctx context.Context, id, userID int, req models.SpeakerRequest) (*models.Speaker, error)
+	Get(ctx context.Context, id int) (*models.Speaker, error)
+	ListMine(ctx context.Context, userID int) ([]models.Speaker, error)
+	Delete(ctx context.Context, id, userID int) error
+	ListForEvent(ctx context.Context, eventID, userID int) ([]models.Speaker, error)
+	SetEventSpeakers(ctx context.Context, eventID, userID int, speakerIDs []int) ([]models.Speaker, error)
+	SetSessionSpeakers(ctx context.Context, eventID, sessionID, userID int, speakerIDs []int) error
+}
+
+type speakerService struct {
+	speakers repositories.SpeakerRepository
+	sessions repositories.SessionRepository
+	events   repositories.EventRepository
+}
+
+func NewSpeakerService(speakers repositories.SpeakerRepository, sessions repositories.SessionRepository, events repositories.EventRepository) SpeakerService {
+	return &speakerService{speakers: speakers, sessions: sessions, events: events}
+}
+
+func speakerFromRequest(userID int, req models.SpeakerRequest) models.Speaker {
+	s := models.Speaker{
+		Name:      strings.TrimSpace(req.Name),
+		Bio:       strings.TrimSpace(req.Bio),
+		Links:     req.Links,
+		CreatedBy: userID,
+	}
+	if req.PhotoURL != "" {
+		s.PhotoURL = &req.PhotoURL
+	}
+	if s.Links == nil {
+		s.Links = []models.SpeakerLink{}
+	}
+	return s
+}
+
+func (s *speakerService) Create(ctx context.Context, userID int, req models.SpeakerRequest) (*models.Speaker, error) {
+	return s.speakers.Create(ctx, speakerFromRequest(userID, req))
+}
+
+// Update changes a speaker profile. Only its creator may edit it.
+func (s *speakerService) Update(ctx context.Context, id, userID int, req models.SpeakerRequest) (*models.Speaker, error) {
+	sp := speakerFromRequest(userID, req)
+	sp.ID = id
+	updated, err := s.speakers.Update(ctx, sp)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, getErr := s.speakers.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrForbidden
+	}
+	return updated, err
+}
+
+func (s *speakerService) Get(ctx context.Context, id int) (*models.Speaker, error) {
+	return s.speakers.GetByID(ctx, id)
+}
+
+func (s *speakerService) ListMine(ctx context.Context, userID int) ([]models.Speaker, error) {
+	return s.speakers.ListByCreator(ctx, userID)
+}
+
+// Delete removes a speaker profile, detaching it everywhere. Only its creator may delete it.
+func (s *speakerService) Delete(ctx context.Context, id, userID int) error {
+	err := s.speakers.Delete(ctx, id, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, getErr := s.speakers.GetByID(ctx, id); getErr != nil {
+			return getErr
+		}
+		return ErrForbidden
+	}
+	return err
+}
+
+// ListForEvent returns the event's speakers in display order to any participant.
+func (s *speakerService) ListForEvent(ctx context.Context, eventID, userID int) ([]models.Speaker, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.speakers.ListEventSpeakers(ctx, eventID)
+}
+
+// SetEventSpeakers replaces the event's speakers, in the given order (requires edit_event).
+func (s *speakerService) SetEventSpeakers(ctx context.Context, eventID, userID int, speakerIDs []int) ([]models.Speaker, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if err := speakerLinkError(s.speakers.SetEventSpeakers(ctx, eventID, uniqueInts(speakerIDs))); err != nil {
+		return nil, err
+	}
+	return s.speakers.ListEventSpeakers(ctx, eventID)
+}
+
+// SetSessionSpeakers replaces a session's speakers, in the given order (requires edit_event).
+func (s *speakerService) SetSessionSpeakers(ctx context.Context, eventID, sessionID, userID int, speakerIDs []int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	if _, err := s.sessions.Get(ctx, eventID, sessionID); err != nil {
+		return err
+	}
+	return speakerLinkError(s.speakers.SetSessionSpeakers(ctx, sessionID, uniqueInts(speakerIDs)))
+}
+
+// speakerLinkError maps a reference to a missing speaker to ErrUnknownSpeaker.
+func speakerLinkError(err error) error {
+	if isForeignKeyViolation(err, "event_speakers_speaker_id_fkey", "session_speakers_speaker_id_fkey") {
+		return ErrUnknownSpeaker
+	}
+	return err
+}
+
+// uniqueInts drops repeated values, keeping the first occurrence.
+func uniqueInts(in []int) []int {
+	seen := make(map[int]bool, len(in))
+	out := make([]int, 0, len(in))
+	for _, v := range in {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// Reliability thresholds: an invitee needs minTrackedRSVPs past going RSVPs
+// at tracked events before they get a level, and shows up at least
+// highReliability (medium: mediumReliability) of the time for a high one.
+const (
+	minTrackedRSVPs   = 3
+	highReliability   = 0.8
+	mediumReliability = 0.5
+)
+
+// maxFunnelPoints caps the funnel series; longer ones keep their latest
+// intervals.
+const maxFunnelPoints = 366
+
+type StatsService interface {
+	UserStats(ctx context.Context, userID int) (*models.UserStats, error)
+	Reliability(ctx context.Context, eventID, userID int) ([]models.Reliability, error)
+	EventReport(ctx context.Context, eventID, userID int) ([]byte, error)
+	Funnel(ctx context.Context, eventID, userID int, interval string) (*models.Funnel, error)
+}
+
+type statsService struct {
+	stats        repositories.StatsRepository
+	events       repositories.EventRepository
+	certificates repositories.CertificateRepository
+	vendors      repositories.VendorRepository
+	tickets      repositories.TicketRepository
+	feedback     FeedbackService
+}
+
+func NewStatsService(stats repositories.StatsRepository, events repositories.EventRepository, certificates repositories.CertificateRepository, vendors repositories.VendorRepository, tickets repositories.TicketRepository, feedback FeedbackService) StatsService {
+	return &statsService{stats: stats, events: events, certificates: certificates, vendors: vendors, tickets: tickets, feedback: feedback}
+}
+
+func (s *statsService) UserStats(ctx context.Context, userID int) (*models.UserStats, error) {
+	stats, err := s.stats.UserStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats.Attendance.Rate = ratio(stats.Attendance.Attended, stats.Attendance.Going)
+	stats.Tasks.CompletionRate = ratio(stats.Tasks.Completed, stats.Tasks.Assigned)
+	return stats, nil
+}
+
+// Reliability rates how reliably each invitee of the event showed up at
+// other events (requires manage_participants).
+func (s *statsService) Reliability(ctx context.Context, eventID, userID int) ([]models.Reliability, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	items, err := s.stats.Reliability(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		r := &items[i]
+		r.Rate = ratio(r.Attended, r.Going)
+		switch {
+		case r.Going < minTrackedRSVPs:
+			r.Level = models.ReliabilityUnknown
+		case *r.Rate >= highReliability:
+			r.Level = models.ReliabilityHigh
+		case *r.Rate >= mediumReliability:
+			r.Level = models.ReliabilityMedium
+		default:
+			r.Level = models.ReliabilityLow
+		}
+	}
+	return items, nil
+}
+
+// EventReport renders the post-event report organizers hand to sponsors or
+// management as a PDF (requires edit_event).
+func (s *statsService) EventReport(ctx context.Context, eventID, userID int) ([]byte, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	report := &eventReport{event: event, generatedAt: time.Now()}
+
+	participants, err := s.events.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	checkIns, err := s.certificates.ListCheckIns(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	report.attendance = attendanceOf(event, participants, checkIns)
+
+	vendors, err := s.vendors.List(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	report.budget = budgetOf(vendors)
+	if report.sales, err = s.tickets.SalesByCurrency(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.events.ListTasksByEvents(ctx, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks[eventID] {
+		report.tasks.Assigned++
+		if t.CompletedAt != nil {
+			report.tasks.Completed++
+		} else if t.DueDate != nil && t.DueDate.Before(report.generatedAt) {
+			report.overdue++
+		}
+	}
+	report.tasks.CompletionRate = ratio(report.tasks.Completed, report.tasks.Assigned)
+
+	if report.feedback, err = s.feedback.Summary(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	return renderEventReport(report), nil
+}
+
+// attendanceOf compares the RSVPs of the event's invitees, everyone but the
+// organizer, with who was checked in.
+func attendanceOf(event *models.Event, participants []models.Participant, checkIns []models.CheckIn) eventAttendance {
+	checkedIn := make(map[int]bool, len(checkIns))
+	for _, c := range checkIns {
+		checkedIn[c.UserID] = true
+	}
+	var a eventAttendance
+	for _, p := range participants {
+		if p.UserID == event.OrganizerID {
+			continue
+		}
+		a.Invited++
+		going := p.Attendance != nil && *p.Attendance == "going"
+		switch {
+		case p.Attendance == nil:
+			a.Pending++
+		case going:
+			a.Going++
+		case *p.Attendance == "maybe":
+			a.Maybe++
+		default:
+			a.NotGoing++
+		}
+		switch {
+		case checkedIn[p.UserID] && going:
+			a.CheckedIn++
+		case checkedIn[p.UserID]:
+			a.CheckedIn++
+			a.WalkIns++
+		case going:
+			a.NoShows++
+		}
+	}
+	a.Rate = ratio(a.Going-a.NoShows, a.Going)
+	return a
+}
+
+// budgetOf totals the vendors per currency. Cancelled contracts only count
+// with what was paid on them.
+func budgetOf(vendors []models.Vendor) []budgetLine {
+	var lines []budgetLine
+	index := map[string]int{}
+	for _, v := range vendors {
+		i, ok := index[v.Currency]
+		if !ok {
+			i = len(lines)
+			index[v.Currency] = i
+			lines = append(lines, budgetLine{Currency: v.Currency})
+		}
+		b := &lines[i]
+		b.Vendors++
+		b.PaidCents += v.PaidCents
+		switch {
+		case v.ContractStatus == models.ContractCancelled:
+		case v.AmountCents == nil:
+			b.Unpriced++
+		default:
+			b.ContractedCents += *v.AmountCents
+		}
+	}
+	return lines
+}
+
+// Funnel returns the event's RSVP funnel, totals and daily or weekly series
+// (requires manage_participants). The interval defaults to days.
+func (s *statsService) Funnel(ctx context.Context, eventID, userID int, interval string) (*models.Funnel, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageParticipants); err != nil {
+		return nil, err
+	}
+	switch interval {
+	case "":
+		interval = models.FunnelDaily
+	case models.FunnelDaily, models.FunnelWeekly:
+	default:
+		return nil, ErrInvalidInterval
+	}
+	series, err := s.stats.Funnel(ctx, eventID, interval, maxFunnelPoints)
+	if err != nil {
+		return nil, err
+	}
+	funnel := &models.Funnel{EventID: eventID, Interval: interval, Series: series}
+	if len(series) > 0 {
+		funnel.Totals = series[len(series)-1].FunnelCounts
+	}
+	t := funnel.Totals
+	funnel.Conversion = models.FunnelConversion{
+		Viewed:    ratio(t.Viewed, t.Invited),
+		Responded: ratio(t.Responded, t.Viewed),
+		Attended:  ratio(t.Attended, t.Responded),
+	}
+	return funnel, nil
+}
+
+// ratio returns n/d rounded to two decimals, or nil when d is 0.
+func ratio(n, d int) *float64 {
+	if d == 0 {
+		return nil
+	}
+	v := math.Round(float64(n)/float64(d)*100) / 100
+	return &v
+}
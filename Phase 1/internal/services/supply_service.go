@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SupplyService interface {
+	Create(ctx context.Context, eventID, userID int, req models.SupplyRequest) (*models.Supply, error)
+	Update(ctx context.Context, eventID, supplyID, userID int, req models.SupplyRequest) (*models.Supply, error)
+	Delete(ctx context.Context, eventID, supplyID, userID int) error
+	List(ctx context.Context, eventID, userID int) ([]models.Supply, error)
+	Claim(ctx context.Context, eventID, supplyID, userID int) (*models.Supply, error)
+	Unclaim(ctx context.Context, eventID, supplyID, userID int) (*models.Supply, error)
+	SetPurchased(ctx context.Context, eventID, supplyID, userID int, purchased bool) (*models.Supply, error)
+}
+
+type supplyService struct {
+	supplies repositories.SupplyRepository
+	events   repositories.EventRepository
+}
+
+func NewSupplyService(supplies repositories.SupplyRepository, events repositories.EventRepository) SupplyService {
+	return &supplyService{supplies: supplies, events: events}
+}
+
+func supplyFromRequest(eventID int, req models.SupplyRequest) models.Supply {
+	s := models.Supply{
+		EventID:  eventID,
+		Name:     strings.TrimSpace(req.Name),
+		Quantity: req.Quantity,
+		Notes:    strings.TrimSpace(req.Notes),
+	}
+	if s.Quantity == 0 {
+		s.Quantity = 1
+	}
+	return s
+}
+
+// Create adds an item to the event's supplies checklist (requires manage_tasks).
+func (s *supplyService) Create(ctx context.Context, eventID, userID int, req models.SupplyRequest) (*models.Supply, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	return s.supplies.Create(ctx, supplyFromRequest(eventID, req), userID)
+}
+
+// Update changes an item (requires manage_tasks). Its claim is kept.
+func (s *supplyService) Update(ctx context.Context, eventID, supplyID, userID int, req models.SupplyRequest) (*models.Supply, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	item := supplyFromRequest(eventID, req)
+	item.ID = supplyID
+	return s.supplies.Update(ctx, item)
+}
+
+func (s *supplyService) Delete(ctx context.Context, eventID, supplyID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return err
+	}
+	return s.supplies.Delete(ctx, eventID, supplyID)
+}
+
+// membership returns the caller's membership of the event, or ErrForbidden
+// when they do not participate.
+func (s *supplyService) membership(ctx context.Context, eventID, userID int) (models.Membership, error) {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return models.Membership{}, err
+	}
+	m, ok := members[eventID]
+	if !ok {
+		return models.Membership{}, ErrForbidden
+	}
+	return m, nil
+}
+
+// List returns the event's checklist to any participant.
+func (s *supplyService) List(ctx context.Context, eventID, userID int) ([]models.Supply, error) {
+	if _, err := s.membership(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.supplies.List(ctx, eventID)
+}
+
+// Claim signs the caller up to bring an item. Claiming an item the caller
+// already claimed is a no-op.
+func (s *supplyService) Claim(ctx context.Context, eventID, supplyID, userID int) (*models.Supply, error) {
+	if _, err := s.membership(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	err := s.supplies.Claim(ctx, eventID, supplyID, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	item, getErr := s.supplies.Get(ctx, eventID, supplyID)
+	if getErr != nil {
+		return nil, getErr
+	}
+	if err != nil && (item.ClaimedBy == nil || *item.ClaimedBy != userID) {
+		return nil, ErrSupplyClaimed
+	}
+	return item, nil
+}
+
+// Unclaim frees an item. Only its claimer, or anyone with manage_tasks, may
+// release a claim.
+func (s *supplyService) Unclaim(ctx context.Context, eventID, supplyID, userID int) (*models.Supply, error) {
+	if err := s.requireClaimer(ctx, eventID, supplyID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.supplies.Unclaim(ctx, eventID, supplyID); err != nil {
+		return nil, err
+	}
+	return s.supplies.Get(ctx, eventID, supplyID)
+}
+
+// SetPurchased marks an item bought, or not. Only its claimer, or anyone
+// with manage_tasks, may do so.
+func (s *supplyService) SetPurchased(ctx context.Context, eventID, supplyID, userID int, purchased bool) (*models.Supply, error) {
+	if err := s.requireClaimer(ctx, eventID, supplyID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.supplies.SetPurchased(ctx, eventID, supplyID, purchased); err != nil {
+		return nil, err
+	}
+	return s.supplies.Get(ctx, eventID, supplyID)
+}
+
+// requireClaimer returns ErrNotClaimer unless the caller claimed the item or
+// has manage_tasks on the event.
+func (s *supplyService) requireClaimer(ctx context.Context, eventID, supplyID, userID int) error {
+	m, err := s.membership(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	item, err := s.supplies.Get(ctx, eventID, supplyID)
+	if err != nil {
+		return err
+	}
+	if (item.ClaimedBy == nil || *item.ClaimedBy != userID) && !m.Has(models.PermManageTasks) {
+		return ErrNotClaimer
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type TaskLabelService interface {
+	List(ctx context.Context, eventID, userID int) ([]models.TaskLabel, error)
+	Create(ctx context.Context, eventID, userID int, req models.TaskLabelRequest) (*models.TaskLabel, error)
+	Update(ctx context.Context, eventID, labelID, userID int, patch models.TaskLabelPatch) (*models.TaskLabel, error)
+	Delete(ctx context.Context, eventID, labelID, userID int) error
+	SetTaskLabels(ctx context.Context, eventID, taskID, userID int, req models.TaskLabelsRequest) ([]models.TaskLabel, error)
+}
+
+type taskLabelService struct {
+	labels repositories.TaskLabelRepository
+	events repositories.EventRepository
+}
+
+func NewTaskLabelService(labels repositories.TaskLabelRepository, events repositories.EventRepository) TaskLabelService {
+	return &taskLabelService{labels: labels, events: events}
+}
+
+// List returns the event's labels to any participant.
+func (s *taskLabelService) List(ctx context.Context, eventID, userID int) ([]models.TaskLabel, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.labels.List(ctx, eventID)
+}
+
+// Create adds a label to the event (requires manage_tasks).
+func (s *taskLabelService) Create(ctx context.Context, eventID, userID int, req models.TaskLabelRequest) (*models.TaskLabel, error) {
+	name, color, err := labelFields(req.Name, req.Color)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	label, err := s.labels.Create(ctx, eventID, name, color)
+	if isUniqueViolation(err, "idx_task_labels_event_name") {
+		return nil, ErrLabelExists
+	}
+	return label, err
+}
+
+// Update renames or recolors a label (requires manage_tasks). Tasks keep
+// it under its new name.
+func (s *taskLabelService) Update(ctx context.Context, eventID, labelID, userID int, patch models.TaskLabelPatch) (*models.TaskLabel, error) {
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name)
+		if !validLabelName(name) {
+			return nil, ErrInvalidLabelName
+		}
+		patch.Name = &name
+	}
+	if patch.Color != nil {
+		if !labelColorPattern.MatchString(*patch.Color) {
+			return nil, ErrInvalidLabelColor
+		}
+		color := strings.ToLower(*patch.Color)
+		patch.Color = &color
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	label, err := s.labels.Update(ctx, eventID, labelID, patch)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrLabelNotFound
+	case isUniqueViolation(err, "idx_task_labels_event_name"):
+		return nil, ErrLabelExists
+	}
+	return label, err
+}
+
+// Delete removes a label from the event and its tasks (requires
+// manage_tasks).
+func (s *taskLabelService) Delete(ctx context.Context, eventID, labelID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return err
+	}
+	found, err := s.labels.Delete(ctx, eventID, labelID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+// SetTaskLabels replaces a task's labels (requires manage_tasks). Names the
+// event has no label for yet become new labels in the default color.
+func (s *taskLabelService) SetTaskLabels(ctx context.Context, eventID, taskID, userID int, req models.TaskLabelsRequest) ([]models.TaskLabel, error) {
+	names := make([]string, 0, len(req.Labels))
+	seen := map[string]bool{}
+	for _, n := range req.Labels {
+		name := strings.TrimSpace(n)
+		key := strings.ToLower(name)
+		if !validLabelName(name) || seen[key] {
+			return nil, ErrInvalidLabelName
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	labels, err := s.labels.SetTaskLabels(ctx, eventID, taskID, names)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	return labels, err
+}
+
+// labelFields trims the name and checks both fields, returning the color in
+// lower case.
+func labelFields(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if !validLabelName(name) {
+		return "", "", ErrInvalidLabelName
+	}
+	if !labelColorPattern.MatchString(color) {
+		return "", "", ErrInvalidLabelColor
+	}
+	return name, strings.ToLower(color), nil
+}
+
+// validLabelName reports whether a trimmed name can be a label. Commas
+// separate the labels filtered by in listings and search.
+func validLabelName(name string) bool {
+	return name != "" && !strings.Contains(name, ",")
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxBulkTasks caps the tasks created by one bulk request.
+const maxBulkTasks = 200
+
+// maxImportTasks caps the tasks created by one import.
+const maxImportTasks = 5000
+
+// builtinTaskTemplates are the checklists that can be applied to an event by
+// name when creating it or its tasks in bulk.
+var builtinTaskTemplates = map[string][]models.TemplateTask{
+	"meetup": {
+		{Title: "Book the venue", DueOffset: "-30d"},
+		{Title: "Confirm speakers", DueOffset: "-21d"},
+		{Title: "Publish the event page", DueOffset: "-21d"},
+		{Title: "Order food and drinks", DueOffset: "-3d"},
+		{Title: "Prepare name tags", DueOffset: "-1d"},
+		{Title: "Send reminder to attendees", DueOffset: "-1d"},
+		{Title: "Thank speakers and share slides", DueOffset: "2d"},
+	},
+	"conference": {
+		{Title: "Book the venue", DueOffset: "-180d"},
+		{Title: "Open the call for papers", DueOffset: "-150d"},
+		{Title: "Select talks and publish the agenda", DueOffset: "-90d"},
+		{Title: "Set up ticket tiers", DueOffset: "-90d"},
+		{Title: "Sign sponsors", DueOffset: "-60d"},
+		{Title: "Book speaker travel and hotels", DueOffset: "-45d"},
+		{Title: "Order catering", DueOffset: "-21d"},
+		{Title: "Arrange audio/video and recording", DueOffset: "-14d"},
+		{Title: "Print badges and signage", DueOffset: "-7d"},
+		{Title: "Brief volunteers", DueOffset: "-2d"},
+		{Title: "Send the attendee information pack", DueOffset: "-2d"},
+		{Title: "Publish recordings and send the feedback survey", DueOffset: "7d"},
+	},
+	"wedding": {
+		{Title: "Set the budget", DueOffset: "-365d"},
+		{Title: "Book the ceremony and reception venues", DueOffset: "-300d"},
+		{Title: "Send save-the-dates", DueOffset: "-240d"},
+		{Title: "Book the caterer", DueOffset: "-240d"},
+		{Title: "Book the photographer", DueOffset: "-240d"},
+		{Title: "Book music", DueOffset: "-180d"},
+		{Title: "Order the cake", DueOffset: "-90d"},
+		{Title: "Send invitations", DueOffset: "-60d"},
+		{Title: "Finalize the seating plan", DueOffset: "-14d"},
+		{Title: "Confirm timings with all vendors", DueOffset: "-7d"},
+	},
+}
+
+var dueOffsetPattern = regexp.MustCompile(`^([+-]?)(?:(\d+)d)?(?:(\d+)h)?(?:(\d+)m)?$`)
+
+// parseDueOffset reads a template due offset such as "-14d" or "-1d12h30m"
+// into whole days and the remaining duration.
+func parseDueOffset(s string) (days int, rest time.Duration, err error) {
+	m := dueOffsetPattern.FindStringSubmatch(strings.ReplaceAll(s, " ", ""))
+	if m == nil || m[2]+m[3]+m[4] == "" {
+		return 0, 0, ErrInvalidDueOffset
+	}
+	atoi := func(v string) int {
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	days = atoi(m[2])
+	rest = time.Duration(atoi(m[3]))*time.Hour + time.Duration(atoi(m[4]))*time.Minute
+	if days > 3650 || rest > 3650*24*time.Hour {
+		return 0, 0, ErrInvalidDueOffset
+	}
+	if m[1] == "-" {
+		days, rest = -days, -rest
+	}
+	return days, rest, nil
+}
+
+// normalizeTemplateTasks trims the tasks of a template and checks their offsets.
+func normalizeTemplateTasks(tasks []models.TemplateTask) ([]models.TemplateTask, error) {
+	out := make([]models.TemplateTask, len(tasks))
+	for i, t := range tasks {
+		t.Title = strings.TrimSpace(t.Title)
+		t.Description = strings.TrimSpace(t.Description)
+		t.DueOffset = strings.ReplaceAll(t.DueOffset, " ", "")
+		if t.Title == "" {
+			return nil, ErrTaskTitleRequired
+		}
+		if t.DueOffset != "" {
+			if _, _, err := parseDueOffset(t.DueOffset); err != nil {
+				return nil, err
+			}
+		}
+		out[i] = t
+	}
+	return out, nil
+}
+
+// resolveTaskTemplate returns the tasks of a built-in template or of one of
+// userID's saved templates. A zero ref yields no tasks.
+func resolveTaskTemplate(ctx context.Context, templates repositories.TaskTemplateRepository, userID int, ref models.TaskTemplateRef) ([]models.TemplateTask, error) {
+	switch {
+	case ref.ID != nil:
+		t, err := templates.Get(ctx, *ref.ID, userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUnknownTemplate
+		}
+		if err != nil {
+			return nil, err
+		}
+		return t.Tasks, nil
+	case ref.Name != "":
+		tasks, ok := builtinTaskTemplates[strings.ToLower(strings.TrimSpace(ref.Name))]
+		if !ok {
+			return nil, ErrUnknownTemplate
+		}
+		return tasks, nil
+	}
+	return nil, nil
+}
+
+// dueFromOffset returns the due date offset from an event starting at start.
+// Offsets in days keep the event's wall-clock time across DST changes.
+func dueFromOffset(start time.Time, offset string) (time.Time, error) {
+	days, rest, err := parseDueOffset(offset)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.AddDate(0, 0, days).Add(rest), nil
+}
+
+// instantiateTemplate turns template tasks into tasks of an event starting at
+// start. The tasks keep their offsets, so their due dates follow the event.
+func instantiateTemplate(tasks []models.TemplateTask, start time.Time) ([]models.TaskInput, error) {
+	out := make([]models.TaskInput, len(tasks))
+	for i, t := range tasks {
+		out[i] = models.TaskInput{Title: t.Title, Description: t.Description, DueOffset: t.DueOffset}
+		if t.DueOffset == "" {
+			continue
+		}
+		due, err := dueFromOffset(start, t.DueOffset)
+		if err != nil {
+			return nil, err
+		}
+		out[i].DueDate = &due
+	}
+	return out, nil
+}
+
+// relativeDueDates recalculates the due dates of the tasks that have a due
+// offset for an event starting at start, keyed by task ID.
+func relativeDueDates(tasks []models.Task, start time.Time) (map[int]time.Time, error) {
+	dueDates := map[int]time.Time{}
+	for _, t := range tasks {
+		if t.DueOffset == nil {
+			continue
+		}
+		due, err := dueFromOffset(start, *t.DueOffset)
+		if err != nil {
+			return nil, err
+		}
+		dueDates[t.ID] = due
+	}
+	return dueDates, nil
+}
+
+// TaskTemplateService manages users' reusable task checklists.
+type TaskTemplateService interface {
+	Create(ctx context.Context, userID int, req models.TaskTemplateRequest) (*models.TaskTemplate, error)
+	Update(ctx context.Context, id, userID int, req models.TaskTemplateRequest) (*models.TaskTemplate, error)
+	Get(ctx context.Context, id, userID int) (*models.TaskTemplate, error)
+	List(ctx context.Context, userID int) ([]models.TaskTemplate, error)
+	Delete(ctx context.Context, id, userID int) error
+}
+
+type taskTemplateService struct {
+	templates repositories.TaskTemplateRepository
+}
+
+func NewTaskTemplateService(templates repositories.TaskTemplateRepository) TaskTemplateService {
+	return &taskTemplateService{templates: templates}
+}
+
+func taskTemplateFromRequest(userID int, req models.TaskTemplateRequest) (models.TaskTemplate, error) {
+	tasks, err := normalizeTemplateTasks(req.Tasks)
+	if err != nil {
+		return models.TaskTemplate{}, err
+	}
+	return models.TaskTemplate{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Tasks:       tasks,
+	}, nil
+}
+
+func (s *taskTemplateService) Create(ctx context.Context, userID int, req models.TaskTemplateRequest) (*models.TaskTemplate, error) {
+	t, err := taskTemplateFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.templates.Create(ctx, t)
+	if isUniqueViolation(err, "task_templates_user_id_name_key") {
+		return nil, ErrTemplateExists
+	}
+	return created, err
+}
+
+func (s *taskTemplateService) Update(ctx context.Context, id, userID int, req models.TaskTemplateRequest) (*models.TaskTemplate, error) {
+	t, err := taskTemplateFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	t.ID = id
+	updated, err := s.templates.Update(ctx, t)
+	if isUniqueViolation(err, "task_templates_user_id_name_key") {
+		return nil, ErrTemplateExists
+	}
+	return updated, err
+}
+
+func (s *taskTemplateService) Get(ctx context.Context, id, userID int) (*models.TaskTemplate, error) {
+	return s.templates.Get(ctx, id, userID)
+}
+
+func (s *taskTemplateService) List(ctx context.Context, userID int) ([]models.TaskTemplate, error) {
+	return s.templates.List(ctx, userID)
+}
+
+func (s *taskTemplateService) Delete(ctx context.Context, id, userID int) error {
+	return s.templates.Delete(ctx, id, userID)
+}
@@ -0,0 +1,804 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/flags"
+	"eventplanner-backend/internal/fx"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/payments"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TicketService interface {
+	CreateTier(ctx context.Context, eventID, userID int, req models.TicketTierRequest) (*models.TicketTier, error)
+	UpdateTier(ctx context.Context, eventID, tierID, userID int, req models.TicketTierRequest) (*models.TicketTier, error)
+	ListTiers(ctx context.Context, eventID, userID int) ([]models.TicketTier, error)
+	Claim(ctx context.Context, eventID, tierID, userID int, promoCode string) (*models.Ticket, error)
+	Cancel(ctx context.Context, ticketID, userID int) error
+	Refund(ctx context.Context, ticketID, userID int) error
+	RefundQuote(ctx context.Context, ticketID, userID int) (*models.RefundQuote, error)
+	SetRefundPolicy(ctx context.Context, eventID, userID int, rules []models.RefundRule) (*models.RefundPolicy, error)
+	RefundPolicy(ctx context.Context, eventID, userID int) (*models.RefundPolicy, error)
+	ListMine(ctx context.Context, userID int) ([]models.Ticket, error)
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+	ReconcilePayments(ctx context.Context) (int, error)
+	CreatePromoCode(ctx context.Context, eventID, userID int, req models.PromoCodeRequest) (*models.PromoCode, error)
+	UpdatePromoCode(ctx context.Context, eventID, promoCodeID, userID int, req models.PromoCodeRequest) (*models.PromoCode, error)
+	ListPromoCodes(ctx context.Context, eventID, userID int) ([]models.PromoCode, error)
+	DeletePromoCode(ctx context.Context, eventID, promoCodeID, userID int) error
+	ListRedemptions(ctx context.Context, eventID, promoCodeID, userID int) ([]models.PromoRedemption, error)
+	SalesSummary(ctx context.Context, eventID, userID int, currency string) (*models.SalesSummary, error)
+	Receipt(ctx context.Context, ticketID, userID int) (*models.Receipt, error)
+	SetTransferPolicy(ctx context.Context, eventID, userID int, allowed bool) error
+	Transfer(ctx context.Context, eventID, userID int, email string) (*models.Transfer, error)
+}
+
+// Pending tickets younger than this are left alone by reconciliation; their
+// checkout is most likely still in progress.
+const reconcileGracePeriod = 5 * time.Minute
+
+type ticketService struct {
+	tickets  repositories.TicketRepository
+	events   repositories.EventRepository
+	users    repositories.UserRepository
+	payments payments.Provider
+	rates    fx.Provider
+	notifier *notifications.Dispatcher
+	flags    *flags.Flags
+}
+
+func NewTicketService(tickets repositories.TicketRepository, events repositories.EventRepository, users repositories.UserRepository, paymentProvider payments.Provider, rates fx.Provider, notifier *notifications.Dispatcher, featureFlags *flags.Flags) TicketService {
+	return &ticketService{tickets: tickets, events: events, users: users, payments: paymentProvider, rates: rates, notifier: notifier, flags: featureFlags}
+}
+
+// defaultCurrency prices tiers created without a currency.
+const defaultCurrency = "USD"
+
+func tierFromRequest(eventID int, req models.TicketTierRequest) models.TicketTier {
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	return models.TicketTier{
+		EventID:    eventID,
+		Name:       strings.TrimSpace(req.Name),
+		PriceCents: req.PriceCents,
+		Currency:   currency,
+		Quantity:   req.Quantity,
+	}
+}
+
+// CreateTier adds a ticket tier to the event (requires edit_event). Paid
+// tiers need the payments feature to be on for the caller.
+func (s *ticketService) CreateTier(ctx context.Context, eventID, userID int, req models.TicketTierRequest) (*models.TicketTier, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if req.PriceCents > 0 && !s.flags.Enabled(ctx, flags.Payments, userID) {
+		return nil, ErrPaymentsDisabled
+	}
+	tier, err := s.tickets.CreateTier(ctx, tierFromRequest(eventID, req))
+	if isUniqueViolation(err, "ticket_tiers_event_id_name_key") {
+		return nil, ErrTierExists
+	}
+	return tier, err
+}
+
+// UpdateTier changes a tier (requires edit_event). The quantity cannot drop
+// below the number of tickets already claimed. Paid tiers need the payments
+// feature to be on for the caller.
+func (s *ticketService) UpdateTier(ctx context.Context, eventID, tierID, userID int, req models.TicketTierRequest) (*models.TicketTier, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	if req.PriceCents > 0 && !s.flags.Enabled(ctx, flags.Payments, userID) {
+		return nil, ErrPaymentsDisabled
+	}
+	t := tierFromRequest(eventID, req)
+	t.ID = tierID
+	tier, err := s.tickets.UpdateTier(ctx, t)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if _, getErr := s.tickets.GetTier(ctx, eventID, tierID); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrQuantityBelowSold
+	case isUniqueViolation(err, "ticket_tiers_event_id_name_key"):
+		return nil, ErrTierExists
+	}
+	return tier, err
+}
+
+// ListTiers returns the event's tiers with remaining capacity to any participant.
+func (s *ticketService) ListTiers(ctx context.Context, eventID, userID int) ([]models.TicketTier, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.tickets.ListTiers(ctx, eventID)
+}
+
+// Claim issues the caller a ticket in the tier, discounted by the optional
+// promo code. Only participants of the event may claim, one active ticket each.
+func (s *ticketService) Claim(ctx context.Context, eventID, tierID, userID int, promoCode string) (*models.Ticket, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	var promoID *int
+	if promoCode = normalizePromoCode(promoCode); promoCode != "" {
+		p, err := s.usablePromoCode(ctx, eventID, promoCode)
+		if err != nil {
+			return nil, err
+		}
+		promoID = &p.ID
+	}
+	code, err := ticketCode()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.tickets.Claim(ctx, eventID, tierID, userID, code, promoID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// A conditional update matched nothing: the tier is missing or sold
+		// out, or the promo code ran out since it was checked.
+		tier, getErr := s.tickets.GetTier(ctx, eventID, tierID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if tier.Remaining == 0 || promoID == nil {
+			return nil, ErrSoldOut
+		}
+		if _, err := s.usablePromoCode(ctx, eventID, promoCode); err != nil {
+			return nil, err
+		}
+		return nil, ErrPromoCodeUsedUp
+	case isUniqueViolation(err, "idx_tickets_one_per_user"):
+		return nil, ErrAlreadyHasTicket
+	case err != nil:
+		return nil, err
+	}
+	if t.Status != models.TicketPending {
+		return t, nil
+	}
+
+	// Paid tier: the seat is held by the pending ticket while the buyer pays.
+	session, err := s.payments.CreateCheckout(ctx, payments.Checkout{
+		Reference:   strconv.Itoa(t.ID),
+		Description: t.TierName + " ticket",
+		AmountCents: t.AmountCents,
+		Currency:    t.Currency,
+	})
+	if err != nil {
+		if relErr := s.tickets.Release(ctx, t.ID, models.TicketPending, models.TicketCancelled); relErr != nil {
+			log.Printf("tickets: releasing ticket %d after failed checkout: %v", t.ID, relErr)
+		}
+		if errors.Is(err, payments.ErrNotConfigured) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+	}
+	if err := s.tickets.SetCheckout(ctx, t.ID, session.ID, session.URL); err != nil {
+		return nil, err
+	}
+	t.CheckoutURL = &session.URL
+	return t, nil
+}
+
+// Cancel releases the caller's pending or free ticket. Paid tickets are
+// refunded as far as the event's refund policy allows.
+func (s *ticketService) Cancel(ctx context.Context, ticketID, userID int) error {
+	t, err := s.tickets.GetTicket(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	if t.UserID != userID {
+		return pgx.ErrNoRows
+	}
+	if t.Status == models.TicketClaimed && t.PaymentID != nil {
+		return s.refundByPolicy(ctx, t)
+	}
+	return s.tickets.Cancel(ctx, ticketID, userID)
+}
+
+// Refund returns a paid ticket's money through the payment provider and
+// releases its seat. Callers with edit_event refund the full amount; the
+// ticket holder gets what the event's refund policy allows.
+func (s *ticketService) Refund(ctx context.Context, ticketID, userID int) error {
+	t, err := s.tickets.GetTicket(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	err = authorize(ctx, s.events, t.EventID, userID, models.PermEditEvent)
+	if errors.Is(err, ErrForbidden) && t.UserID == userID {
+		return s.refundByPolicy(ctx, t)
+	}
+	if err != nil {
+		return err
+	}
+	if t.Status != models.TicketClaimed || t.PaymentID == nil {
+		return ErrNotRefundable
+	}
+	return s.issueRefund(ctx, t, t.AmountCents)
+}
+
+// refundByPolicy refunds the holder's paid ticket by the event's refund
+// policy. Events without a policy leave refunds to organizers.
+func (s *ticketService) refundByPolicy(ctx context.Context, t *models.Ticket) error {
+	quote, err := s.quote(ctx, t)
+	if err != nil {
+		return err
+	}
+	if !quote.Eligible {
+		return ErrRefundClosed
+	}
+	return s.issueRefund(ctx, t, quote.RefundCents)
+}
+
+// issueRefund pays back refundCents of the ticket's payment and releases its
+// seat. The ticket moves to refunding first, so of concurrent refunds only one
+// reaches the provider; the others find it no longer refundable.
+func (s *ticketService) issueRefund(ctx context.Context, t *models.Ticket, refundCents int) error {
+	err := s.tickets.BeginRefund(ctx, t.ID, refundCents)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotRefundable
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.refund(ctx, t, refundCents); err != nil {
+		// Should the refund have gone through after all, its webhook
+		// releases the ticket.
+		if abortErr := s.tickets.AbortRefund(ctx, t.ID); abortErr != nil {
+			log.Printf("tickets: reverting refund of ticket %d: %v", t.ID, abortErr)
+		}
+		if errors.Is(err, payments.ErrNotConfigured) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+	}
+	return s.recordRefund(ctx, t.ID)
+}
+
+// refund asks the provider to pay back refundCents of the ticket's payment.
+func (s *ticketService) refund(ctx context.Context, t *models.Ticket, refundCents int) error {
+	amount := refundCents
+	if refundCents == t.AmountCents {
+		amount = 0
+	}
+	return s.payments.Refund(ctx, *t.PaymentID, amount)
+}
+
+func (s *ticketService) recordRefund(ctx context.Context, ticketID int) error {
+	err := s.tickets.RecordRefund(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Already released by the provider's refund webhook.
+		return nil
+	}
+	return err
+}
+
+// RefundQuote tells the holder of a paid ticket what cancelling it now would
+// refund. Tickets of other users are reported as not found.
+func (s *ticketService) RefundQuote(ctx context.Context, ticketID, userID int) (*models.RefundQuote, error) {
+	t, err := s.tickets.GetTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if t.UserID != userID {
+		return nil, pgx.ErrNoRows
+	}
+	return s.quote(ctx, t)
+}
+
+// quote applies the event's refund rules to a claimed, paid ticket. The rule
+// with the most days before the start that has not passed yet decides the
+// percentage.
+func (s *ticketService) quote(ctx context.Context, t *models.Ticket) (*models.RefundQuote, error) {
+	if t.Status != models.TicketClaimed || t.PaymentID == nil {
+		return nil, ErrNotRefundable
+	}
+	rules, err := s.tickets.ListRefundRules(ctx, t.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, ErrRefundRequired
+	}
+	event, err := s.events.GetForParticipant(ctx, t.EventID, t.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &models.RefundQuote{TicketID: t.ID, AmountCents: t.AmountCents, Currency: t.Currency}
+	now := time.Now()
+	for _, rule := range rules {
+		deadline := event.StartTime.AddDate(0, 0, -rule.DaysBefore)
+		if now.After(deadline) {
+			continue
+		}
+		q.Rule, q.Deadline = &rule, &deadline
+		q.Percent = rule.Percent
+		q.RefundCents = t.AmountCents * rule.Percent / 100
+		q.Eligible = q.RefundCents > 0
+		break
+	}
+	return q, nil
+}
+
+// SetRefundPolicy replaces the event's refund rules (requires edit_event).
+// Tickets already refunded are not affected.
+func (s *ticketService) SetRefundPolicy(ctx context.Context, eventID, userID int, rules []models.RefundRule) (*models.RefundPolicy, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	seen := map[int]bool{}
+	for _, rule := range rules {
+		if seen[rule.DaysBefore] {
+			return nil, ErrDuplicateRefundDay
+		}
+		seen[rule.DaysBefore] = true
+	}
+	if err := s.tickets.SetRefundRules(ctx, eventID, rules); err != nil {
+		return nil, err
+	}
+	return s.refundPolicy(ctx, eventID)
+}
+
+// RefundPolicy returns the event's refund rules to any participant.
+func (s *ticketService) RefundPolicy(ctx context.Context, eventID, userID int) (*models.RefundPolicy, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.refundPolicy(ctx, eventID)
+}
+
+func (s *ticketService) refundPolicy(ctx context.Context, eventID int) (*models.RefundPolicy, error) {
+	rules, err := s.tickets.ListRefundRules(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RefundPolicy{EventID: eventID, Rules: rules}, nil
+}
+
+// HandleWebhook verifies a payment provider event and applies it to the
+// ticket it concerns before returning, so that an event is acknowledged only
+// once it is stored; on error the provider delivers it again. Events we don't
+// react to are dropped.
+func (s *ticketService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	evt, err := s.payments.ParseWebhook(payload, header)
+	if err != nil {
+		return err
+	}
+	if evt.Kind == "" {
+		return nil
+	}
+	return s.applyPaymentEvent(ctx, *evt)
+}
+
+// applyPaymentEvent applies a verified payment provider event to the ticket
+// it concerns. Unknown sessions and repeated deliveries are ignored.
+func (s *ticketService) applyPaymentEvent(ctx context.Context, evt payments.Event) error {
+	switch evt.Kind {
+	case payments.EventPaid, payments.EventExpired:
+		t, err := s.tickets.GetBySession(ctx, evt.SessionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		state := &payments.SessionState{Status: payments.StatusExpired}
+		if evt.Kind == payments.EventPaid {
+			state = &payments.SessionState{Status: payments.StatusPaid, PaymentID: evt.PaymentID}
+		}
+		return s.settle(ctx, t, state)
+	case payments.EventRefunded:
+		// Refunds issued from the provider's dashboard release the seat too,
+		// as do ours that were not recorded.
+		t, err := s.tickets.GetByPayment(ctx, evt.PaymentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if t.Status != models.TicketClaimed && t.Status != models.TicketRefunding {
+			return nil
+		}
+		err = s.tickets.Release(ctx, t.ID, t.Status, models.TicketRefunded)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ReconcilePayments brings pending tickets in line with their checkout
+// sessions, for when webhooks were missed: paid sessions confirm the ticket
+// and expired ones (or tickets whose checkout was never created) release the
+// seat. Refunds left unfinished, as by a crash, are asked for again, which
+// the provider's idempotency makes safe. It returns the number of tickets
+// settled.
+func (s *ticketService) ReconcilePayments(ctx context.Context) (int, error) {
+	before := time.Now().Add(-reconcileGracePeriod)
+	pending, err := s.tickets.ListPending(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	refunding, err := s.tickets.ListRefunding(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	settled := 0
+	for i := range refunding {
+		t := &refunding[i]
+		if t.PaymentID == nil || t.RefundedCents == nil {
+			continue
+		}
+		if err := s.refund(ctx, t, *t.RefundedCents); err != nil {
+			log.Printf("tickets: reconciling refund of ticket %d: %v", t.ID, err)
+			continue
+		}
+		if err := s.recordRefund(ctx, t.ID); err != nil {
+			log.Printf("tickets: reconciling refund of ticket %d: %v", t.ID, err)
+			continue
+		}
+		settled++
+	}
+	for i := range pending {
+		t := &pending[i]
+		state := &payments.SessionState{Status: payments.StatusExpired}
+		if t.SessionID != nil {
+			state, err = s.payments.Session(ctx, *t.SessionID)
+			if err != nil {
+				log.Printf("tickets: reconciling ticket %d: %v", t.ID, err)
+				continue
+			}
+		}
+		if state.Status == payments.StatusOpen {
+			continue
+		}
+		if err := s.settle(ctx, t, state); err != nil {
+			log.Printf("tickets: reconciling ticket %d: %v", t.ID, err)
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+// settle applies a final checkout state to a ticket. A payment that arrives
+// for a ticket which is no longer pending (cancelled by its holder, or
+// released after its session expired) is refunded, since its seat is gone.
+func (s *ticketService) settle(ctx context.Context, t *models.Ticket, state *payments.SessionState) error {
+	switch state.Status {
+	case payments.StatusPaid:
+		err := s.tickets.ConfirmPayment(ctx, t.ID, state.PaymentID)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		current, err := s.tickets.GetTicket(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status == models.TicketCancelled && state.PaymentID != "" {
+			log.Printf("tickets: refunding payment for cancelled ticket %d", t.ID)
+			return s.payments.Refund(ctx, state.PaymentID, 0)
+		}
+		return nil
+	case payments.StatusExpired:
+		err := s.tickets.Release(ctx, t.ID, models.TicketPending, models.TicketCancelled)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ticketService) ListMine(ctx context.Context, userID int) ([]models.Ticket, error) {
+	return s.tickets.ListByUser(ctx, userID)
+}
+
+// Receipt returns the receipt of a paid ticket to its buyer or to anyone with
+// edit_event on the event; pgx.ErrNoRows if there is none they may see.
+// Receipts stay with the buyer when the ticket is transferred.
+func (s *ticketService) Receipt(ctx context.Context, ticketID, userID int) (*models.Receipt, error) {
+	r, err := s.tickets.GetReceipt(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if r.UserID == userID {
+		return r, nil
+	}
+	if err := authorize(ctx, s.events, r.EventID, userID, models.PermEditEvent); err != nil {
+		return nil, pgx.ErrNoRows
+	}
+	return r, nil
+}
+
+// ticketCode returns a random, hard to guess code for door check-in.
+func ticketCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// usablePromoCode returns the event's promo code if it can still be redeemed.
+func (s *ticketService) usablePromoCode(ctx context.Context, eventID int, code string) (*models.PromoCode, error) {
+	p, err := s.tickets.GetPromoCode(ctx, eventID, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromoCodeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return nil, ErrPromoCodeExpired
+	}
+	if p.MaxUses != nil && p.Uses >= *p.MaxUses {
+		return nil, ErrPromoCodeUsedUp
+	}
+	return p, nil
+}
+
+func promoFromRequest(eventID int, req models.PromoCodeRequest) (models.PromoCode, error) {
+	if req.Kind == models.PromoPercent && req.Amount > 100 {
+		return models.PromoCode{}, ErrInvalidDiscount
+	}
+	code := normalizePromoCode(req.Code)
+	if code == "" {
+		return models.PromoCode{}, ErrPromoCodeInvalid
+	}
+	return models.PromoCode{
+		EventID:   eventID,
+		Code:      code,
+		Kind:      req.Kind,
+		Amount:    req.Amount,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+	}, nil
+}
+
+// CreatePromoCode adds a discount code to the event (requires edit_event).
+// Codes are case-insensitive and stored upper-case.
+func (s *ticketService) CreatePromoCode(ctx context.Context, eventID, userID int, req models.PromoCodeRequest) (*models.PromoCode, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	p, err := promoFromRequest(eventID, req)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.tickets.CreatePromoCode(ctx, p)
+	if isUniqueViolation(err, "promo_codes_event_id_code_key") {
+		return nil, ErrPromoCodeExists
+	}
+	return created, err
+}
+
+// UpdatePromoCode changes a code's terms (requires edit_event). Tickets that
+// already redeemed it keep their discount.
+func (s *ticketService) UpdatePromoCode(ctx context.Context, eventID, promoCodeID, userID int, req models.PromoCodeRequest) (*models.PromoCode, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	p, err := promoFromRequest(eventID, req)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = promoCodeID
+	updated, err := s.tickets.UpdatePromoCode(ctx, p)
+	if isUniqueViolation(err, "promo_codes_event_id_code_key") {
+		return nil, ErrPromoCodeExists
+	}
+	return updated, err
+}
+
+func (s *ticketService) ListPromoCodes(ctx context.Context, eventID, userID int) ([]models.PromoCode, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.tickets.ListPromoCodes(ctx, eventID)
+}
+
+func (s *ticketService) DeletePromoCode(ctx context.Context, eventID, promoCodeID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.tickets.DeletePromoCode(ctx, eventID, promoCodeID)
+}
+
+func (s *ticketService) ListRedemptions(ctx context.Context, eventID, promoCodeID, userID int) ([]models.PromoRedemption, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.tickets.ListRedemptions(ctx, eventID, promoCodeID)
+}
+
+// SetTransferPolicy allows or forbids spot transfers for the event (requires edit_event).
+func (s *ticketService) SetTransferPolicy(ctx context.Context, eventID, userID int, allowed bool) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.events.SetAllowTransfers(ctx, eventID, allowed)
+}
+
+// Transfer hands the caller's spot, and claimed ticket if any, to the user
+// with the given email, who joins as an attendee. The event must allow
+// transfers and the recipient must not already participate. A transfer moves
+// an existing seat, so tier capacity is not checked again. Both parties are
+// notified.
+func (s *ticketService) Transfer(ctx context.Context, eventID, userID int, email string) (*models.Transfer, error) {
+	event, err := s.events.GetForParticipant(ctx, eventID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if event.OrganizerID == userID {
+		return nil, ErrOrganizerTransfer
+	}
+	if !event.AllowTransfers {
+		return nil, ErrTransfersDisabled
+	}
+	if t, err := s.tickets.GetActive(ctx, eventID, userID); err == nil && t.Status == models.TicketPending {
+		return nil, ErrTicketPending
+	} else if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	recipient, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, ErrUserNotFound
+	}
+	if recipient.ID == userID {
+		return nil, ErrTransferToSelf
+	}
+	members, err := s.events.Memberships(ctx, recipient.ID, []int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := members[eventID]; ok {
+		return nil, ErrAlreadyParticipant
+	}
+
+	// Look the sender up before their participant row moves.
+	var sender notifications.Recipient
+	participants, err := s.events.ListParticipants(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		if p.UserID == userID {
+			sender = notifications.Recipient{UserID: p.UserID, Name: p.UserName, Email: p.UserEmail}
+		}
+	}
+
+	code, err := ticketCode()
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := s.tickets.Transfer(ctx, eventID, userID, recipient.ID, code)
+	if isUniqueViolation(err, "event_participants_pkey", "idx_tickets_one_per_user") {
+		return nil, ErrAlreadyParticipant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	what := "spot"
+	if transfer.TicketID != nil {
+		what = "ticket"
+	}
+	toSender := notifications.Message{
+		Kind:    "transfer",
+		EventID: &eventID,
+		Subject: event.Title + ": your " + what + " was transferred",
+		Body:    fmt.Sprintf("Your %s for %s now belongs to %s.", what, event.Title, recipient.Name),
+	}
+	toRecipient := notifications.Message{
+		Kind:    "transfer",
+		EventID: &eventID,
+		Subject: event.Title + ": you received a " + what,
+		Body:    fmt.Sprintf("%s transferred their %s for %s to you.", sender.Name, what, event.Title),
+	}
+	if err := s.notifier.Dispatch(ctx, []notifications.Recipient{sender}, toSender); err != nil {
+		log.Printf("transfer %d: delivery failed: %v", transfer.ID, err)
+	}
+	to := notifications.Recipient{UserID: recipient.ID, Name: recipient.Name, Email: recipient.Email}
+	if err := s.notifier.Dispatch(ctx, []notifications.Recipient{to}, toRecipient); err != nil {
+		log.Printf("transfer %d: delivery failed: %v", transfer.ID, err)
+	}
+	return transfer, nil
+}
+
+// SalesSummary totals the event's ticket sales per currency and converted
+// into one currency: the given one, else the organizer's preferred currency,
+// else the only currency sold in, else the default tier currency. Without
+// exchange rates for every currency involved the summary has no total, only
+// the per-currency amounts.
+func (s *ticketService) SalesSummary(ctx context.Context, eventID, userID int, currency string) (*models.SalesSummary, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	byCurrency, err := s.tickets.SalesByCurrency(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &models.SalesSummary{EventID: eventID, ByCurrency: byCurrency}
+
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		event, err := s.events.GetForParticipant(ctx, eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if currency, err = s.users.PreferredCurrency(ctx, event.OrganizerID); err != nil {
+			return nil, err
+		}
+	}
+	if currency == "" && len(byCurrency) == 1 {
+		currency = byCurrency[0].Currency
+	}
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	total := &models.SalesAmounts{Currency: currency}
+	var rates *fx.Rates
+	for _, amounts := range byCurrency {
+		rate := 1.0
+		if amounts.Currency != currency {
+			if rates == nil {
+				if rates, err = s.rates.Latest(ctx); err != nil {
+					summary.ConversionError = err.Error()
+					return summary, nil
+				}
+				summary.Conversion = &models.Conversion{AsOf: rates.AsOf, Source: rates.Source}
+			}
+			if rate, err = rates.Rate(amounts.Currency, currency); err != nil {
+				summary.Conversion = nil
+				summary.ConversionError = err.Error()
+				return summary, nil
+			}
+			summary.Conversion.Rates = append(summary.Conversion.Rates, models.FXRate{From: amounts.Currency, To: currency, Rate: rate})
+		}
+		total.Tickets += amounts.Tickets
+		total.RevenueCents += convertCents(amounts.RevenueCents, rate)
+		total.PendingCents += convertCents(amounts.PendingCents, rate)
+		total.RefundedCents += convertCents(amounts.RefundedCents, rate)
+	}
+	summary.Total = total
+	return summary, nil
+}
+
+// convertCents converts an amount in minor units at rate, rounding to the
+// nearest unit. Both currencies are assumed to have two decimals.
+func convertCents(cents int64, rate float64) int64 {
+	return int64(math.Round(float64(cents) * rate))
+}
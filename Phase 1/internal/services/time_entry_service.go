@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TimeEntryService interface {
+	StartTimer(ctx context.Context, eventID, taskID, userID int, req models.TimerRequest) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, eventID, taskID, userID int) (*models.TimeEntry, error)
+	Add(ctx context.Context, eventID, taskID, userID int, req models.TimeEntryRequest) (*models.TimeEntry, error)
+	List(ctx context.Context, eventID, taskID, userID int) ([]models.TimeEntry, error)
+	Delete(ctx context.Context, eventID, taskID, entryID, userID int) error
+	Totals(ctx context.Context, eventID, userID int) (*models.EventTimeTotals, error)
+}
+
+type timeEntryService struct {
+	entries repositories.TimeEntryRepository
+	events  repositories.EventRepository
+}
+
+func NewTimeEntryService(entries repositories.TimeEntryRepository, events repositories.EventRepository) TimeEntryService {
+	return &timeEntryService{entries: entries, events: events}
+}
+
+// StartTimer starts the caller's timer on a task, stopping the one they had
+// running on any task.
+func (s *timeEntryService) StartTimer(ctx context.Context, eventID, taskID, userID int, req models.TimerRequest) (*models.TimeEntry, error) {
+	if _, err := s.member(ctx, eventID, taskID, userID, true); err != nil {
+		return nil, err
+	}
+	entry, err := s.entries.StartTimer(ctx, taskID, userID, strings.TrimSpace(req.Note))
+	if isUniqueViolation(err, "idx_task_time_entries_running") {
+		// another request started a timer at the same moment
+		return nil, ErrTimerRunning
+	}
+	return entry, err
+}
+
+// StopTimer stops the caller's timer on a task.
+func (s *timeEntryService) StopTimer(ctx context.Context, eventID, taskID, userID int) (*models.TimeEntry, error) {
+	if _, err := s.member(ctx, eventID, taskID, userID, true); err != nil {
+		return nil, err
+	}
+	entry, err := s.entries.StopTimer(ctx, taskID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTimerNotRunning
+	}
+	return entry, err
+}
+
+// Add logs time the caller spent on a task.
+func (s *timeEntryService) Add(ctx context.Context, eventID, taskID, userID int, req models.TimeEntryRequest) (*models.TimeEntry, error) {
+	duration := time.Duration(req.Minutes) * time.Minute
+	startedAt := time.Now().Add(-duration)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	if startedAt.Add(duration).After(time.Now()) {
+		return nil, ErrInvalidTimeEntry
+	}
+	if _, err := s.member(ctx, eventID, taskID, userID, true); err != nil {
+		return nil, err
+	}
+	return s.entries.Add(ctx, taskID, userID, startedAt, req.Minutes, strings.TrimSpace(req.Note))
+}
+
+// List returns a task's time entries to any participant.
+func (s *timeEntryService) List(ctx context.Context, eventID, taskID, userID int) ([]models.TimeEntry, error) {
+	if _, err := s.member(ctx, eventID, taskID, userID, false); err != nil {
+		return nil, err
+	}
+	return s.entries.List(ctx, taskID)
+}
+
+// Delete removes a time entry: the caller's own, or anyone's with
+// manage_tasks.
+func (s *timeEntryService) Delete(ctx context.Context, eventID, taskID, entryID, userID int) error {
+	m, err := s.member(ctx, eventID, taskID, userID, false)
+	if err != nil {
+		return err
+	}
+	ownerID := userID
+	if m.Has(models.PermManageTasks) {
+		ownerID = 0
+	}
+	found, err := s.entries.Delete(ctx, taskID, entryID, ownerID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+// Totals sums up the event's estimated and tracked time (requires
+// manage_tasks).
+func (s *timeEntryService) Totals(ctx context.Context, eventID, userID int) (*models.EventTimeTotals, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermManageTasks); err != nil {
+		return nil, err
+	}
+	return s.entries.Totals(ctx, eventID)
+}
+
+// member returns the caller's membership of the event after checking the
+// task is the event's. With tracking, the caller must also be allowed to
+// track time on the task: its assignee, or anyone with manage_tasks.
+func (s *timeEntryService) member(ctx context.Context, eventID, taskID, userID int, tracking bool) (models.Membership, error) {
+	members, err := s.events.Memberships(ctx, userID, []int{eventID})
+	if err != nil {
+		return models.Membership{}, err
+	}
+	m, ok := members[eventID]
+	if !ok {
+		return m, ErrForbidden
+	}
+	assignee, err := s.entries.TaskAssignee(ctx, eventID, taskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return m, ErrTaskNotFound
+	}
+	if err != nil {
+		return m, err
+	}
+	if tracking && !m.Has(models.PermManageTasks) && (assignee == nil || *assignee != userID) {
+		return m, ErrForbidden
+	}
+	return m, nil
+}
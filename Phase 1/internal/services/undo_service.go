@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+// DefaultUndoWindowMinutes is how long destructive actions can be undone
+// unless UNDO_WINDOW_MINUTES says otherwise.
+const DefaultUndoWindowMinutes = 10
+
+// UndoWindowFromEnv reads how long destructive actions can be undone from
+// UNDO_WINDOW_MINUTES, falling back to DefaultUndoWindowMinutes.
+func UndoWindowFromEnv() time.Duration {
+	minutes := DefaultUndoWindowMinutes
+	if raw := os.Getenv("UNDO_WINDOW_MINUTES"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			log.Printf("invalid UNDO_WINDOW_MINUTES %q, using %d", raw, DefaultUndoWindowMinutes)
+		} else {
+			minutes = v
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+type UndoService interface {
+	Record(ctx context.Context, userID int, action string, eventIDs []int, payload json.RawMessage) (*models.Undo, error)
+	Undo(ctx context.Context, userID int, token string) (*models.UndoResult, error)
+	Purge(ctx context.Context) (int64, error)
+}
+
+type undoService struct {
+	repo   repositories.UndoRepository
+	window time.Duration
+}
+
+func NewUndoService(repo repositories.UndoRepository, window time.Duration) UndoService {
+	return &undoService{repo: repo, window: window}
+}
+
+// hashUndoToken is how tokens are stored, so a leaked table cannot be used
+// to undo anything.
+func hashUndoToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record hands out a token that lets userID reverse the action for the
+// undo window.
+func (s *undoService) Record(ctx context.Context, userID int, action string, eventIDs []int, payload json.RawMessage) (*models.Undo, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b)
+	expires := time.Now().Add(s.window)
+	err := s.repo.Create(ctx, hashUndoToken(token), models.UndoAction{
+		UserID:    userID,
+		Action:    action,
+		EventIDs:  eventIDs,
+		Payload:   payload,
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Undo{Token: token, ExpiresAt: expires}, nil
+}
+
+// Undo reverses the action behind the caller's token. Tokens work once and
+// only for whoever received them; pgx.ErrNoRows means the token is unknown or
+// expired, ErrUndoConflict that the action was overtaken by later changes.
+func (s *undoService) Undo(ctx context.Context, userID int, token string) (*models.UndoResult, error) {
+	a, restored, err := s.repo.Undo(ctx, hashUndoToken(token), userID)
+	if err != nil {
+		return nil, err
+	}
+	if restored == 0 {
+		return nil, ErrUndoConflict
+	}
+	return &models.UndoResult{Action: a.Action, EventIDs: a.EventIDs}, nil
+}
+
+// Purge removes expired tokens and purges deleted events for good once
+// their undo window has passed.
+func (s *undoService) Purge(ctx context.Context) (int64, error) {
+	return s.repo.Purge(ctx, time.Now().Add(-s.window))
+}
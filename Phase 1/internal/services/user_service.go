@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"strings"
+	"unicode/utf8"
 
+	"eventplanner-backend/internal/i18n"
 	"eventplanner-backend/internal/models"
 	"eventplanner-backend/internal/repositories"
 
@@ -12,14 +15,21 @@ import (
 type UserService interface {
 	Signup(ctx context.Context, name, email, password string) (*models.User, error)
 	Login(ctx context.Context, email, password string) (*models.User, error)
+	Search(ctx context.Context, requesterID int, q string, limit int) ([]models.UserSummary, error)
+	Block(ctx context.Context, userID int, req models.BlockRequest) (*models.UserBlock, error)
+	ListBlocks(ctx context.Context, userID int) ([]models.UserBlock, error)
+	Unblock(ctx context.Context, id, userID int) error
+	SetPreferredCurrency(ctx context.Context, userID int, currency string) (string, error)
+	SetLocale(ctx context.Context, userID int, locale string) (string, error)
 }
 
 type userService struct {
-	repo repositories.UserRepository
+	repo   repositories.UserRepository
+	blocks repositories.BlockRepository
 }
 
-func NewUserService(repo repositories.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repositories.UserRepository, blocks repositories.BlockRepository) UserService {
+	return &userService{repo: repo, blocks: blocks}
 }
 
 func (s *userService) Signup(ctx context.Context, name, email, password string) (*models.User, error) {
@@ -54,3 +64,82 @@ func (s *userService) Login(ctx context.Context, email, password string) (*model
 	}
 	return user, nil
 }
+
+// Search limits for the invite dialog's typeahead.
+const (
+	MinUserQueryLength = 2
+	MaxUserQueryLength = 100
+)
+
+// Search finds users to invite by exact email, or by name among the people
+// the requester already shares an event with.
+func (s *userService) Search(ctx context.Context, requesterID int, q string, limit int) ([]models.UserSummary, error) {
+	q = strings.TrimSpace(q)
+	if n := utf8.RuneCountInString(q); n < MinUserQueryLength || n > MaxUserQueryLength {
+		return nil, ErrInvalidUserQuery
+	}
+	return s.repo.Search(ctx, requesterID, q, limit)
+}
+
+// Block stops invitations from a user, or from everyone whose email address
+// is at a domain. Invitations from blocked senders are dropped without
+// telling the sender.
+func (s *userService) Block(ctx context.Context, userID int, req models.BlockRequest) (*models.UserBlock, error) {
+	b := models.UserBlock{UserID: userID}
+	domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Domain), "@"))
+	switch {
+	case req.UserID != nil && domain == "":
+		if *req.UserID == userID {
+			return nil, ErrBlockSelf
+		}
+		b.BlockedUserID = req.UserID
+	case req.UserID == nil && validDomain(domain):
+		b.Domain = &domain
+	default:
+		return nil, ErrInvalidBlock
+	}
+	created, err := s.blocks.Create(ctx, b)
+	if err != nil {
+		switch {
+		case isUniqueViolation(err, "user_blocks_user_id_blocked_user_id_key", "user_blocks_user_id_blocked_domain_key"):
+			return nil, ErrBlockExists
+		case isForeignKeyViolation(err, "user_blocks_blocked_user_id_fkey"):
+			return nil, ErrUnknownUser
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// validDomain is a loose check for a host name such as "example.com".
+func validDomain(domain string) bool {
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return false
+	}
+	return !strings.ContainsAny(domain, "@/ \t")
+}
+
+func (s *userService) ListBlocks(ctx context.Context, userID int) ([]models.UserBlock, error) {
+	return s.blocks.List(ctx, userID)
+}
+
+func (s *userService) Unblock(ctx context.Context, id, userID int) error {
+	return s.blocks.Delete(ctx, id, userID)
+}
+
+// SetPreferredCurrency sets the currency ticket sales of the user's events are
+// reported in.
+func (s *userService) SetPreferredCurrency(ctx context.Context, userID int, currency string) (string, error) {
+	currency = strings.ToUpper(currency)
+	return currency, s.repo.SetPreferredCurrency(ctx, userID, currency)
+}
+
+// SetLocale sets the language the user's notifications and emails are sent
+// in.
+func (s *userService) SetLocale(ctx context.Context, userID int, locale string) (string, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if !i18n.IsSupported(locale) {
+		return "", ErrUnsupportedLocale
+	}
+	return locale, s.repo.SetLocale(ctx, userID, locale)
+}
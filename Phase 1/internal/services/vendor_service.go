@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type VendorService interface {
+	Create(ctx context.Context, eventID, userID int, req models.VendorRequest) (*models.Vendor, error)
+	Update(ctx context.Context, eventID, vendorID, userID int, req models.VendorRequest) (*models.Vendor, error)
+	Get(ctx context.Context, eventID, vendorID, userID int) (*models.Vendor, error)
+	List(ctx context.Context, eventID, userID int) ([]models.Vendor, error)
+	Delete(ctx context.Context, eventID, vendorID, userID int) error
+}
+
+type vendorService struct {
+	vendors repositories.VendorRepository
+	events  repositories.EventRepository
+}
+
+func NewVendorService(vendors repositories.VendorRepository, events repositories.EventRepository) VendorService {
+	return &vendorService{vendors: vendors, events: events}
+}
+
+func vendorFromRequest(eventID int, req models.VendorRequest) (models.Vendor, error) {
+	if req.AmountCents != nil && req.PaidCents > *req.AmountCents {
+		return models.Vendor{}, ErrPaidOverAmount
+	}
+	v := models.Vendor{
+		EventID:        eventID,
+		Name:           strings.TrimSpace(req.Name),
+		Category:       strings.ToLower(strings.TrimSpace(req.Category)),
+		ContactName:    strings.TrimSpace(req.ContactName),
+		Email:          strings.TrimSpace(req.Email),
+		Phone:          strings.TrimSpace(req.Phone),
+		Website:        req.Website,
+		Notes:          req.Notes,
+		ContractStatus: req.ContractStatus,
+		AmountCents:    req.AmountCents,
+		PaidCents:      req.PaidCents,
+		Currency:       strings.ToUpper(req.Currency),
+		PaymentStatus:  req.PaymentStatus,
+		PaymentDueAt:   req.PaymentDueAt,
+		TaskIDs:        uniqueInts(req.TaskIDs),
+	}
+	if req.ContractURL != "" {
+		v.ContractURL = &req.ContractURL
+	}
+	if v.ContractStatus == "" {
+		v.ContractStatus = models.ContractNone
+	}
+	if v.PaymentStatus == "" {
+		v.PaymentStatus = models.VendorUnpaid
+	}
+	if v.Currency == "" {
+		v.Currency = defaultCurrency
+	}
+	return v, nil
+}
+
+// Create adds a vendor to the event (requires edit_event).
+func (s *vendorService) Create(ctx context.Context, eventID, userID int, req models.VendorRequest) (*models.Vendor, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	v, err := vendorFromRequest(eventID, req)
+	if err != nil {
+		return nil, err
+	}
+	v.CreatedBy = &userID
+	created, err := s.vendors.Create(ctx, v)
+	return created, taskLinkError(err)
+}
+
+// Update replaces a vendor's details and linked tasks (requires edit_event).
+func (s *vendorService) Update(ctx context.Context, eventID, vendorID, userID int, req models.VendorRequest) (*models.Vendor, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	v, err := vendorFromRequest(eventID, req)
+	if err != nil {
+		return nil, err
+	}
+	v.ID = vendorID
+	updated, err := s.vendors.Update(ctx, v)
+	return updated, taskLinkError(err)
+}
+
+// Get returns one vendor (requires edit_event, as vendors carry contract
+// amounts).
+func (s *vendorService) Get(ctx context.Context, eventID, vendorID, userID int) (*models.Vendor, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.vendors.Get(ctx, eventID, vendorID)
+}
+
+// List returns the event's vendors (requires edit_event).
+func (s *vendorService) List(ctx context.Context, eventID, userID int) ([]models.Vendor, error) {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return nil, err
+	}
+	return s.vendors.List(ctx, eventID)
+}
+
+// Delete removes a vendor; its linked tasks are kept (requires edit_event).
+func (s *vendorService) Delete(ctx context.Context, eventID, vendorID, userID int) error {
+	if err := authorize(ctx, s.events, eventID, userID, models.PermEditEvent); err != nil {
+		return err
+	}
+	return s.vendors.Delete(ctx, eventID, vendorID)
+}
+
+// taskLinkError maps a link to a task missing from the event to ErrUnknownTask.
+func taskLinkError(err error) error {
+	if isForeignKeyViolation(err, "vendor_tasks_task_id_event_id_fkey") {
+		return ErrUnknownTask
+	}
+	return err
+}
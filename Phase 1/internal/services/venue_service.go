@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"eventplanner-backend/internal/geocoding"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type VenueService interface {
+	Create(ctx context.Context, userID int, req models.VenueRequest) (*models.Venue, error)
+	Get(ctx context.Context, id int) (*models.Venue, error)
+	ListMine(ctx context.Context, userID int) ([]models.Venue, error)
+}
+
+type venueService struct {
+	repo     repositories.VenueRepository
+	geocoder geocoding.Provider
+}
+
+func NewVenueService(repo repositories.VenueRepository, geocoder geocoding.Provider) VenueService {
+	return &venueService{repo: repo, geocoder: geocoder}
+}
+
+// Create stores a venue. When the client supplies an address but no
+// coordinates, the geocoder fills them in; geocoding failures are logged and
+// the venue is saved without coordinates.
+func (s *venueService) Create(ctx context.Context, userID int, req models.VenueRequest) (*models.Venue, error) {
+	v := models.Venue{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		CreatedBy: userID,
+	}
+	if (v.Latitude == nil || v.Longitude == nil) && v.Address != "" {
+		res, err := s.geocoder.Geocode(ctx, v.Address)
+		switch {
+		case err == nil:
+			v.Latitude, v.Longitude = &res.Latitude, &res.Longitude
+		case err != geocoding.ErrNotFound:
+			log.Printf("geocoding venue %q failed: %v", v.Address, err)
+		}
+	}
+	return s.repo.Create(ctx, v)
+}
+
+func (s *venueService) Get(ctx context.Context, id int) (*models.Venue, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *venueService) ListMine(ctx context.Context, userID int) ([]models.Venue, error) {
+	return s.repo.ListByCreator(ctx, userID)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/repositories"
+)
+
+type WatchService interface {
+	Watching(ctx context.Context, eventID, userID int) (*models.Watching, error)
+	WatchEvent(ctx context.Context, eventID, userID int, watch bool) (*models.Watching, error)
+	WatchTask(ctx context.Context, eventID, taskID, userID int, watch bool) (*models.Watching, error)
+}
+
+type watchService struct {
+	watches repositories.WatchRepository
+	events  repositories.EventRepository
+}
+
+func NewWatchService(watches repositories.WatchRepository, events repositories.EventRepository) WatchService {
+	return &watchService{watches: watches, events: events}
+}
+
+// Watching returns what the caller watches in the event.
+func (s *watchService) Watching(ctx context.Context, eventID, userID int) (*models.Watching, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	return s.watches.Watching(ctx, eventID, userID)
+}
+
+// WatchEvent starts or stops the caller, any participant, watching the
+// event's changes.
+func (s *watchService) WatchEvent(ctx context.Context, eventID, userID int, watch bool) (*models.Watching, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.watches.WatchEvent(ctx, eventID, userID, watch); err != nil {
+		return nil, err
+	}
+	return s.watches.Watching(ctx, eventID, userID)
+}
+
+// WatchTask starts or stops the caller, any participant, watching a task's
+// changes.
+func (s *watchService) WatchTask(ctx context.Context, eventID, taskID, userID int, watch bool) (*models.Watching, error) {
+	if err := requireParticipant(ctx, s.events, eventID, userID); err != nil {
+		return nil, err
+	}
+	found, err := s.watches.WatchTask(ctx, eventID, taskID, userID, watch)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTaskNotFound
+	}
+	return s.watches.Watching(ctx, eventID, userID)
+}
@@ -1,17 +1,53 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"eventplanner-backend/internal/broadcast"
+	"eventplanner-backend/internal/config"
 	"eventplanner-backend/internal/database"
+	"eventplanner-backend/internal/flags"
+	"eventplanner-backend/internal/fx"
+	"eventplanner-backend/internal/geocoding"
+	"eventplanner-backend/internal/graph"
 	"eventplanner-backend/internal/handlers"
+	"eventplanner-backend/internal/jobs"
+	"eventplanner-backend/internal/linkpreview"
+	"eventplanner-backend/internal/locks"
+	"eventplanner-backend/internal/mailin"
+	"eventplanner-backend/internal/meetings"
+	"eventplanner-backend/internal/models"
+	"eventplanner-backend/internal/moderation"
+	"eventplanner-backend/internal/notifications"
+	"eventplanner-backend/internal/outbox"
+	"eventplanner-backend/internal/payments"
 	"eventplanner-backend/internal/repositories"
 	"eventplanner-backend/internal/router"
+	"eventplanner-backend/internal/scheduler"
+	"eventplanner-backend/internal/server"
 	"eventplanner-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Fill in the environment from the profile and the secrets it references
+	cfg, err := config.Load(context.Background())
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+	if mode := os.Getenv("GIN_MODE"); mode != "" {
+		gin.SetMode(mode)
+	}
+	log.Printf("using the %s profile", cfg.Profile)
+
 	// Read database URL from environment
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
@@ -19,27 +55,294 @@ func main() {
 	}
 
 	// Initialize database connection pool
-	pool, err := database.NewPostgresPool(databaseURL)
+	poolOptions, err := database.PoolOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("invalid pool settings: %v", err)
+	}
+	pool, err := database.NewPostgresPool(databaseURL, poolOptions)
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 	defer pool.Close()
+	database.RegisterPoolMetrics(pool)
+
+	// Repositories retry transient errors and stop calling a database that is down
+	resilience, err := database.ResilienceOptionsFromEnv()
+	if err != nil {
+		log.Fatalf("invalid retry settings: %v", err)
+	}
+	db := database.NewDB(pool, resilience)
+
+	// Advisory locks keep instances from running the same exclusive work at once
+	locker := locks.NewPostgres(pool)
+
+	// Side effects (notification delivery, webhook processing) run on the job queue
+	jobQueue := jobs.NewPool(jobs.Options{}, repositories.NewJobRepository(db))
+	jobQueue.Start(context.Background())
+
+	// Feature flags roll risky features out gradually
+	configuredFlags, err := flags.ConfiguredFromEnv()
+	if err != nil {
+		log.Fatalf("invalid FEATURE_FLAGS: %v", err)
+	}
+	flagRepo := repositories.NewFeatureFlagRepository(db)
+	featureFlags := flags.New(configuredFlags, flagRepo)
+
+	// Rate limits, CORS origins and feature flags reload on SIGHUP or from the admin endpoint
+	routerSettings, err := router.SettingsFromEnv()
+	if err != nil {
+		log.Fatalf("invalid router settings: %v", err)
+	}
+	live := router.NewLive(routerSettings)
+	reloadConfig := func(ctx context.Context) (*models.RuntimeConfig, error) {
+		if err := cfg.Reload(ctx); err != nil {
+			return nil, err
+		}
+		settings, err := router.SettingsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		configured, err := flags.ConfiguredFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("invalid FEATURE_FLAGS: %w", err)
+		}
+		live.Apply(settings)
+		featureFlags.Configure(configured)
+		return &models.RuntimeConfig{CORSOrigins: settings.CORSOrigins, RateLimits: settings.RateLimits, FeatureFlags: configured}, nil
+	}
+	// Instances tell each other to drop cached flags and to reload, so an admin's change applies everywhere
+	broadcaster := broadcast.New(pool)
+	broadcaster.Subscribe(broadcast.TopicFlags, func(context.Context, json.RawMessage) {
+		featureFlags.Invalidate()
+	})
+	broadcaster.Subscribe(broadcast.TopicConfigReload, func(ctx context.Context, _ json.RawMessage) {
+		if _, err := reloadConfig(ctx); err != nil {
+			log.Printf("failed to reload configuration: %v", err)
+			return
+		}
+		log.Print("configuration reloaded on request of another instance")
+	})
+	broadcaster.Start(context.Background())
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			if _, err := reloadConfig(context.Background()); err != nil {
+				log.Printf("failed to reload configuration: %v", err)
+				continue
+			}
+			log.Print("configuration reloaded")
+		}
+	}()
 
 	// Wire dependencies
-	userRepo := repositories.NewUserRepository(pool)
-	userService := services.NewUserService(userRepo)
+	userRepo := repositories.NewUserRepository(db)
+	blockRepo := repositories.NewBlockRepository(db)
+	watchRepo := repositories.NewWatchRepository(db)
+	reactionRepo := repositories.NewReactionRepository(db)
+	userService := services.NewUserService(userRepo, blockRepo)
 	authHandler := handlers.NewAuthHandler(userService)
+	userHandler := handlers.NewUserHandler(userService)
 
-	eventRepo := repositories.NewEventRepository(pool)
-	eventService := services.NewEventService(eventRepo)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	brandingRepo := repositories.NewBrandingRepository(db)
+	mailer, err := notifications.NewMailerFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure email: %v", err)
+	}
+	email := notifications.NewEmail(mailer)
+	email.UseBranding(brandingRepo)
+	deliveryRepo := repositories.NewDeliveryRepository(db)
+	email.UseTracking(deliveryRepo, notifications.TrackingPixelURLFromEnv())
+	dispatcher := notifications.NewDispatcher(
+		notifications.NewInApp(notificationRepo),
+		email,
+	)
+	dispatcher.UseQueue(jobQueue)
+	dispatcher.UseMutes(notificationRepo)
+	dispatcher.UseLocales(userRepo)
+	dispatcher.UseDigests(repositories.NewDigestRepository(db), notifications.DigestOptionsFromEnv())
+
+	eventRepo := repositories.NewEventRepository(db)
+	taskTemplateRepo := repositories.NewTaskTemplateRepository(db)
+	quotaService := services.NewQuotaService(repositories.NewQuotaRepository(db), services.QuotaLimitsFromEnv())
+	undoService := services.NewUndoService(repositories.NewUndoRepository(db), services.UndoWindowFromEnv())
+	linkPreviewService := services.NewLinkPreviewService(repositories.NewLinkPreviewRepository(db), linkpreview.NewFromEnv(), jobQueue)
+	eventService := services.NewEventService(eventRepo, taskTemplateRepo, blockRepo, watchRepo, reactionRepo, linkPreviewService, quotaService, undoService, meetings.NewFromEnv(), moderation.NewFromEnv(), dispatcher)
 	eventHandler := handlers.NewEventHandler(eventService)
+	taskTemplateHandler := handlers.NewTaskTemplateHandler(services.NewTaskTemplateService(taskTemplateRepo))
+
+	ticketRepo := repositories.NewTicketRepository(db)
+	followRepo := repositories.NewFollowRepository(db)
+
+	// Relay domain events written to the outbox to in-process subscribers, the outbox webhook and the message broker
+	subscribers := outbox.NewSubscribers()
+	services.SubscribeNotifications(subscribers, eventRepo, blockRepo, ticketRepo, followRepo, dispatcher)
+	publishers := []outbox.Publisher{subscribers}
+	if webhook := outbox.WebhookFromEnv(); webhook != nil {
+		publishers = append(publishers, webhook)
+	}
+	broker, err := outbox.BrokerFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure message broker: %v", err)
+	}
+	if broker != nil {
+		publishers = append(publishers, broker)
+	}
+	outbox.NewRelay(repositories.NewOutboxRepository(db), locker, publishers...).Start(context.Background())
 
-	searchService := services.NewSearchService(eventRepo)
+	ticketService := services.NewTicketService(ticketRepo, eventRepo, userRepo, payments.NewFromEnv(), fx.NewFromEnv(), dispatcher, featureFlags)
+	ticketHandler := handlers.NewTicketHandler(ticketService)
+
+	speakerRepo := repositories.NewSpeakerRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+	sessionService := services.NewSessionService(sessionRepo, eventRepo, speakerRepo)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	speakerService := services.NewSpeakerService(speakerRepo, sessionRepo, eventRepo)
+	speakerHandler := handlers.NewSpeakerHandler(speakerService)
+	publicService := services.NewPublicService(eventRepo, sessionRepo, speakerRepo, ticketRepo)
+	publicHandler := handlers.NewPublicHandler(publicService)
+	vendorRepo := repositories.NewVendorRepository(db)
+	vendorHandler := handlers.NewVendorHandler(services.NewVendorService(vendorRepo, eventRepo))
+	supplyHandler := handlers.NewSupplyHandler(services.NewSupplyService(repositories.NewSupplyRepository(db), eventRepo))
+	boardHandler := handlers.NewBoardHandler(services.NewBoardService(repositories.NewBoardRepository(db), eventRepo))
+	taskLabelHandler := handlers.NewTaskLabelHandler(services.NewTaskLabelService(repositories.NewTaskLabelRepository(db), eventRepo))
+	timeEntryHandler := handlers.NewTimeEntryHandler(services.NewTimeEntryService(repositories.NewTimeEntryRepository(db), eventRepo))
+	watchHandler := handlers.NewWatchHandler(services.NewWatchService(watchRepo, eventRepo))
+	commentService := services.NewCommentService(repositories.NewCommentRepository(db), eventRepo, blockRepo, reactionRepo, linkPreviewService, dispatcher)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	rideHandler := handlers.NewRideHandler(services.NewRideService(repositories.NewRideRepository(db), eventRepo, dispatcher))
+	accommodationHandler := handlers.NewAccommodationHandler(services.NewAccommodationService(repositories.NewAccommodationRepository(db), eventRepo))
+	feedbackService := services.NewFeedbackService(repositories.NewFeedbackRepository(db), eventRepo)
+	feedbackHandler := handlers.NewFeedbackHandler(feedbackService)
+	certificateRepo := repositories.NewCertificateRepository(db)
+	certificateHandler := handlers.NewCertificateHandler(services.NewCertificateService(certificateRepo, eventRepo))
+	seriesRepo := repositories.NewSeriesRepository(db)
+	seriesHandler := handlers.NewSeriesHandler(services.NewSeriesService(seriesRepo, eventRepo, blockRepo))
+	followHandler := handlers.NewFollowHandler(services.NewFollowService(followRepo, seriesRepo))
+	quotaHandler := handlers.NewQuotaHandler(quotaService)
+	undoHandler := handlers.NewUndoHandler(undoService)
+	inboundWebhookHandler := handlers.NewInboundWebhookHandler(services.NewInboundWebhookService(repositories.NewInboundWebhookRepository(db), eventRepo, eventService))
+	mailInConfig, err := mailin.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure email-in: %v", err)
+	}
+	mailInHandler := handlers.NewMailInHandler(services.NewMailInService(mailInConfig, userRepo, eventService, dispatcher, jobQueue))
+	deliveryHandler := handlers.NewDeliveryHandler(services.NewDeliveryService(deliveryRepo, eventRepo, services.DeliveryReportTokenFromEnv()))
+	brandingHandler := handlers.NewBrandingHandler(services.NewBrandingService(brandingRepo, eventRepo))
+	statsHandler := handlers.NewStatsHandler(services.NewStatsService(repositories.NewStatsRepository(db), eventRepo, certificateRepo, vendorRepo, ticketRepo, feedbackService))
+	integrationHandler := handlers.NewIntegrationHandler(services.NewIntegrationService(repositories.NewIntegrationRepository(db)))
+	proposalHandler := handlers.NewProposalHandler(services.NewProposalService(repositories.NewProposalRepository(db), eventRepo, eventService, dispatcher))
+	admins := services.AdminIDsFromEnv()
+	reportHandler := handlers.NewReportHandler(services.NewReportService(repositories.NewReportRepository(db), admins, services.ReportHideThresholdFromEnv()))
+	featureFlagHandler := handlers.NewFeatureFlagHandler(services.NewFeatureFlagService(flagRepo, featureFlags, broadcaster, admins))
+	configHandler := handlers.NewConfigHandler(services.NewConfigService(reloadConfig, broadcaster, admins))
+	databaseHandler := handlers.NewDatabaseHandler(services.NewDatabaseService(func() models.PoolStats { return database.Stats(pool) }, admins))
+
+	venueRepo := repositories.NewVenueRepository(db)
+	venueService := services.NewVenueService(venueRepo, geocoding.NewFromEnv())
+	venueHandler := handlers.NewVenueHandler(venueService)
+
+	searchService := services.NewSearchService(eventRepo, services.SearchSimilarityFromEnv(), featureFlags)
 	searchHandler := handlers.NewSearchHandler(searchService)
+	savedSearchService := services.NewSavedSearchService(repositories.NewSavedSearchRepository(db), searchService, dispatcher)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
+
+	// Periodic maintenance; each run is claimed in scheduled_runs so only one instance executes it
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	archiveAfter := services.ArchiveAfterFromEnv()
+	retentionService := services.NewRetentionService(repositories.NewRetentionRepository(db), services.RetentionPolicyFromEnv())
+	cron := scheduler.New(scheduleRepo, scheduler.Options{Locker: locker})
+	schedules := []struct {
+		name, spec string
+		task       scheduler.Task
+	}{
+		// Email the notification digests that are due
+		{"notifications.digests", "* * * * *", func(ctx context.Context) error {
+			n, err := dispatcher.FlushDigests(ctx)
+			if n > 0 {
+				log.Printf("sent %d notification digests", n)
+			}
+			return err
+		}},
+		// Settle pending ticket payments whose webhooks never arrived
+		{"payments.reconcile", "*/5 * * * *", func(ctx context.Context) error {
+			n, err := ticketService.ReconcilePayments(ctx)
+			if n > 0 {
+				log.Printf("payment reconciliation settled %d tickets", n)
+			}
+			return err
+		}},
+		// Alert saved search owners about newly published matching events
+		{"saved_searches.alerts", "*/15 * * * *", func(ctx context.Context) error {
+			n, err := savedSearchService.MatchAlerts(ctx)
+			if n > 0 {
+				log.Printf("saved search alerts sent %d notifications", n)
+			}
+			return err
+		}},
+		// Remind invitees of events with automatic nudges who have not responded
+		{"rsvp.nudges", "0 * * * *", func(ctx context.Context) error {
+			n, err := eventService.SendAutoNudges(ctx)
+			if n > 0 {
+				log.Printf("automatic nudges sent to %d invitees", n)
+			}
+			return err
+		}},
+		// Archive events some days after they end
+		{"events.archive", "30 3 * * *", func(ctx context.Context) error {
+			n, err := eventService.ArchiveEnded(ctx, archiveAfter)
+			if n > 0 {
+				log.Printf("archived %d ended events", n)
+			}
+			return err
+		}},
+		// Create next year's search partition before events of that year arrive
+		{"events.search_partitions", "0 2 1 * *", func(ctx context.Context) error {
+			return eventRepo.AddSearchPartition(ctx, time.Now().UTC().Year()+1)
+		}},
+		// Purge deleted events once they can no longer be undone
+		{"undo.purge", "*/5 * * * *", func(ctx context.Context) error {
+			n, err := undoService.Purge(ctx)
+			if n > 0 {
+				log.Printf("purged %d deleted events", n)
+			}
+			return err
+		}},
+		// Delete old notifications, expired invitations and other data past its retention window
+		{"retention.purge", "0 4 * * *", func(ctx context.Context) error {
+			purged, err := retentionService.Purge(ctx)
+			for target, n := range purged {
+				if n > 0 {
+					log.Printf("retention purged %d %s", n, target)
+				}
+			}
+			return err
+		}},
+		{"scheduler.prune", "@daily", func(ctx context.Context) error {
+			_, err := scheduleRepo.PruneRuns(ctx, time.Now().AddDate(0, 0, -30))
+			return err
+		}},
+	}
+	for _, sc := range schedules {
+		if err := cron.Add(sc.name, sc.spec, sc.task); err != nil {
+			log.Fatalf("failed to schedule %s: %v", sc.name, err)
+		}
+	}
+	cron.Start(context.Background())
+
+	graphqlHandler := handlers.NewGraphQLHandler(graph.NewSchema(eventService, commentService))
+
+	docsHandler := handlers.NewDocsHandler()
 
 	// Build router and start server
-	r := router.New(authHandler, eventHandler, searchHandler)
-	if err := r.Run(":8080"); err != nil {
+	r := router.New(live, db.Breaker(), authHandler, userHandler, eventHandler, taskTemplateHandler, searchHandler, savedSearchHandler, venueHandler, notificationHandler, ticketHandler, sessionHandler, speakerHandler, vendorHandler, supplyHandler, boardHandler, taskLabelHandler, timeEntryHandler, watchHandler, commentHandler, rideHandler, accommodationHandler, feedbackHandler, certificateHandler, seriesHandler, followHandler, quotaHandler, reportHandler, undoHandler, proposalHandler, inboundWebhookHandler, integrationHandler, mailInHandler, brandingHandler, deliveryHandler, statsHandler, featureFlagHandler, configHandler, databaseHandler, publicHandler, graphqlHandler, docsHandler)
+	if err := router.TrustProxies(r, router.ProxyConfigFromEnv()); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	if err := server.Run(r, server.ConfigFromEnv()); err != nil {
 		log.Fatalf("server exited: %v", err)
 	}
 }
@@ -0,0 +1,11 @@
+-- Optional end time for multi-day events
+ALTER TABLE events ADD COLUMN IF NOT EXISTS end_time TIMESTAMPTZ;
+
+DO $$ BEGIN
+    ALTER TABLE events ADD CONSTRAINT events_end_after_start CHECK (end_time IS NULL OR end_time > start_time);
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Supports the calendar range lookup
+CREATE INDEX IF NOT EXISTS idx_events_time_range ON events (start_time, end_time);
@@ -0,0 +1,3 @@
+-- Supports the bounding-box prefilter of nearby search (lat/lng/radius)
+CREATE INDEX IF NOT EXISTS idx_venues_lat_lng ON venues (latitude, longitude)
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
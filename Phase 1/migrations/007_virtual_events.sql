@@ -0,0 +1,7 @@
+-- Virtual and hybrid events with an online meeting link
+DO $$ BEGIN
+    CREATE TYPE event_type AS ENUM ('in_person','virtual','hybrid');
+EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_type event_type NOT NULL DEFAULT 'in_person';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS meeting_url TEXT;
@@ -0,0 +1,13 @@
+-- Custom participant roles per event, each granting a subset of the permission
+-- matrix (manage_participants, manage_tasks, edit_event, delete_event)
+CREATE TABLE IF NOT EXISTS event_roles (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (event_id, name)
+);
+
+-- Participants may now hold custom role names, so role is no longer an enum
+ALTER TABLE event_participants ALTER COLUMN role TYPE TEXT USING role::text;
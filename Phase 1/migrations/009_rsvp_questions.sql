@@ -0,0 +1,26 @@
+-- Questions invitees answer when accepting an event (dietary needs, t-shirt size, ...)
+CREATE TABLE IF NOT EXISTS rsvp_questions (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    prompt TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('text','choice')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT true,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rsvp_questions_event_id ON rsvp_questions (event_id, position);
+
+-- One answer per participant and question; removed with the participant
+CREATE TABLE IF NOT EXISTS rsvp_answers (
+    question_id INTEGER NOT NULL REFERENCES rsvp_questions(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    answer TEXT NOT NULL,
+    answered_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (question_id, user_id),
+    FOREIGN KEY (event_id, user_id) REFERENCES event_participants(event_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_rsvp_answers_event_id ON rsvp_answers (event_id);
@@ -0,0 +1,28 @@
+-- Ticket tiers with per-tier capacity
+CREATE TABLE IF NOT EXISTS ticket_tiers (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price_cents INTEGER NOT NULL DEFAULT 0 CHECK (price_cents >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (event_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS tickets (
+    id SERIAL PRIMARY KEY,
+    tier_id INTEGER NOT NULL REFERENCES ticket_tiers(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'claimed' CHECK (status IN ('claimed','cancelled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One active ticket per user and event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_one_per_user ON tickets (event_id, user_id) WHERE status = 'claimed';
+CREATE INDEX IF NOT EXISTS idx_tickets_user_id ON tickets (user_id);
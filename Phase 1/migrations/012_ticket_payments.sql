@@ -0,0 +1,17 @@
+-- Payments for paid ticket tiers: tickets record what was charged and the
+-- provider's checkout session and payment
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS amount_cents INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS checkout_session_id TEXT UNIQUE;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS checkout_url TEXT;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS payment_id TEXT;
+
+-- Pending tickets hold a seat until paid; refunded tickets release it
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_check;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_check
+    CHECK (status IN ('pending','claimed','cancelled','refunded'));
+
+DROP INDEX IF EXISTS idx_tickets_one_per_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_one_per_user ON tickets (event_id, user_id) WHERE status IN ('pending','claimed');
+CREATE INDEX IF NOT EXISTS idx_tickets_pending ON tickets (created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_tickets_payment_id ON tickets (payment_id);
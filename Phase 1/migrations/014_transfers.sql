@@ -0,0 +1,14 @@
+-- Participants may hand their spot (and ticket) to another user when the
+-- organizer allows it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS allow_transfers BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS spot_transfers (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ticket_id INTEGER REFERENCES tickets(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_spot_transfers_event_id ON spot_transfers (event_id);
@@ -0,0 +1,29 @@
+-- Agenda sessions within an event, and the sessions each participant plans to attend
+CREATE TABLE IF NOT EXISTS event_sessions (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    room TEXT NOT NULL DEFAULT '',
+    speakers TEXT[] NOT NULL DEFAULT '{}',
+    capacity INTEGER CHECK (capacity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_sessions_event_id ON event_sessions (event_id, start_time);
+
+-- Removed with the participant; a spot transfer carries the agenda over
+CREATE TABLE IF NOT EXISTS session_attendees (
+    session_id INTEGER NOT NULL REFERENCES event_sessions(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (session_id, user_id),
+    FOREIGN KEY (event_id, user_id) REFERENCES event_participants(event_id, user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_attendees_user ON session_attendees (event_id, user_id);
@@ -0,0 +1,30 @@
+-- Reusable speaker/host profiles, attached in order to events and sessions
+CREATE TABLE IF NOT EXISTS speakers (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    photo_url TEXT,
+    links JSONB NOT NULL DEFAULT '[]',
+    created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_speakers_created_by ON speakers (created_by);
+
+CREATE TABLE IF NOT EXISTS event_speakers (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    speaker_id INTEGER NOT NULL REFERENCES speakers(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (event_id, speaker_id)
+);
+
+CREATE TABLE IF NOT EXISTS session_speakers (
+    session_id INTEGER NOT NULL REFERENCES event_sessions(id) ON DELETE CASCADE,
+    speaker_id INTEGER NOT NULL REFERENCES speakers(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (session_id, speaker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_speakers_speaker ON event_speakers (speaker_id);
+CREATE INDEX IF NOT EXISTS idx_session_speakers_speaker ON session_speakers (speaker_id);
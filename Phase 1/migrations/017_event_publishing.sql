@@ -0,0 +1,7 @@
+-- Published events get a public landing page addressed by a URL-safe slug.
+-- The slug is generated on first publish and kept when the event is
+-- unpublished, so share links stay stable.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS slug TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_slug ON events (slug);
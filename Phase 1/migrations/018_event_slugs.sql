@@ -0,0 +1,22 @@
+-- Every event gets a slug at creation time, not only when published. Existing
+-- events are backfilled from their titles; titles that slugify to the same
+-- value get the event ID appended.
+UPDATE events e
+SET slug = s.slug
+FROM (
+    SELECT id,
+        CASE WHEN row_number() OVER (PARTITION BY base ORDER BY id) = 1
+                AND NOT EXISTS (SELECT 1 FROM events x WHERE x.slug = base)
+            THEN base
+            ELSE base || '-' || id
+        END AS slug
+    FROM (
+        SELECT id,
+            COALESCE(NULLIF(trim(both '-' from left(trim(both '-' from lower(regexp_replace(title, '[^a-zA-Z0-9]+', '-', 'g'))), 60)), ''), 'event') AS base
+        FROM events
+        WHERE slug IS NULL
+    ) b
+) s
+WHERE e.id = s.id;
+
+ALTER TABLE events ALTER COLUMN slug SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Fuzzy search: pg_trgm provides word_similarity() for typo-tolerant
+-- matching, and trigram indexes speed up the ILIKE '%term%' substring match
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_events_title_trgm ON events USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_events_location_trgm ON events USING gin (location gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_title_trgm ON tasks USING gin (title gin_trgm_ops);
@@ -0,0 +1,18 @@
+-- Named search filters users can re-run, optionally alerting them when newly
+-- published events match. last_checked_at is how far the alert matcher got.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    from_date DATE,
+    to_date DATE,
+    role TEXT NOT NULL DEFAULT '',
+    alerts BOOLEAN NOT NULL DEFAULT false,
+    last_checked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_alerts ON saved_searches (id) WHERE alerts;
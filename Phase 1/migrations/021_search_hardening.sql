@@ -0,0 +1,9 @@
+-- Search hardening: trigram indexes on the remaining ILIKE columns so
+-- substring matches never fall back to scanning every description, plus
+-- indexes for the joins, filters and sort keys search uses
+CREATE INDEX IF NOT EXISTS idx_events_description_trgm ON events USING gin (description gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_description_trgm ON tasks USING gin (description gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_event_id ON tasks (event_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks (due_date, id);
+CREATE INDEX IF NOT EXISTS idx_events_organizer_id ON events (organizer_id);
+CREATE INDEX IF NOT EXISTS idx_events_published_at ON events (published_at) WHERE published_at IS NOT NULL;
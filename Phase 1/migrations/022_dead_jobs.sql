@@ -0,0 +1,12 @@
+-- Background jobs that failed every attempt, kept for inspection and replay
+CREATE TABLE IF NOT EXISTS dead_jobs (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    enqueued_at TIMESTAMPTZ NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_kind ON dead_jobs (kind, failed_at DESC);
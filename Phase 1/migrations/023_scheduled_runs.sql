@@ -0,0 +1,10 @@
+-- One row per occurrence of a scheduled task; the primary key makes sure only
+-- one server instance runs each occurrence
+CREATE TABLE IF NOT EXISTS scheduled_runs (
+    name TEXT NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ,
+    error TEXT,
+    PRIMARY KEY (name, scheduled_for)
+);
@@ -0,0 +1,16 @@
+-- Domain events written in the same transaction as the change they describe,
+-- relayed to subscribers and webhooks by a background worker
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- Publishers that already received the message, skipped on retries
+    delivered_to TEXT[] NOT NULL DEFAULT '{}',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error TEXT,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at, id) WHERE published_at IS NULL;
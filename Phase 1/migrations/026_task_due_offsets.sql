@@ -0,0 +1,3 @@
+-- Due dates relative to the event start (e.g. "-7d"); tasks with an offset
+-- have their due_date recalculated when the event is rescheduled
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_offset TEXT;
@@ -0,0 +1,8 @@
+-- Reminders sent to invitees who have not responded yet; the count caps how
+-- often one invitee is nudged about the same event
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS nudge_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS last_nudged_at TIMESTAMPTZ;
+
+-- Days without a response after which invitees are nudged automatically;
+-- NULL leaves nudging to the organizers
+ALTER TABLE events ADD COLUMN IF NOT EXISTS auto_nudge_days INTEGER CHECK (auto_nudge_days > 0);
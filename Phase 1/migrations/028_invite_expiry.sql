@@ -0,0 +1,13 @@
+-- Pending invitations can expire; once a participant has answered the expiry
+-- no longer applies
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS invite_expires_at TIMESTAMPTZ;
+
+-- Revoked invitations. The participant row is removed; this row keeps the
+-- user from joining through the attendance endpoints until invited again
+CREATE TABLE IF NOT EXISTS revoked_invites (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revoked_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
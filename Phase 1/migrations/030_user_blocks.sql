@@ -0,0 +1,11 @@
+-- Users a user does not want invitations from, by account or by email domain
+CREATE TABLE IF NOT EXISTS user_blocks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    blocked_domain TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((blocked_user_id IS NULL) <> (blocked_domain IS NULL)),
+    UNIQUE (user_id, blocked_user_id),
+    UNIQUE (user_id, blocked_domain)
+);
@@ -0,0 +1,3 @@
+-- Participants who muted an event stay on it but get no announcements or
+-- reminders about it
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,8 @@
+-- Finished events are archived and left out of the default listings.
+-- unarchived_at records that an organizer brought the event back, which keeps
+-- it out of automatic archiving
+ALTER TABLE events ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS unarchived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_events_archive_due ON events (COALESCE(end_time, start_time))
+    WHERE archived_at IS NULL AND unarchived_at IS NULL;
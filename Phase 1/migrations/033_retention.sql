@@ -0,0 +1,15 @@
+-- Rows deleted by the retention job, per table and run
+CREATE TABLE IF NOT EXISTS retention_purges (
+    id BIGSERIAL PRIMARY KEY,
+    target TEXT NOT NULL,
+    cutoff TIMESTAMPTZ NOT NULL,
+    rows_purged BIGINT NOT NULL,
+    purged_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_purges_target ON retention_purges (target, purged_at DESC);
+
+-- Let the purge statements find old rows without scanning whole tables
+CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications (created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_failed_at ON dead_jobs (failed_at);
@@ -0,0 +1,2 @@
+-- Currency ticket sales are reported in for events the user organizes
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_currency CHAR(3);
@@ -0,0 +1,30 @@
+-- Last invoice number issued per organizer. The row lock taken while issuing
+-- keeps numbers sequential without gaps
+CREATE TABLE IF NOT EXISTS invoice_counters (
+    organizer_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_number INTEGER NOT NULL
+);
+
+-- Receipts for paid tickets. Names, titles and amounts are copied at issue
+-- time so a receipt never changes afterwards
+CREATE TABLE IF NOT EXISTS receipts (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL UNIQUE REFERENCES tickets(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organizer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    invoice_number TEXT NOT NULL,
+    seller_name TEXT NOT NULL,
+    buyer_name TEXT NOT NULL,
+    buyer_email TEXT NOT NULL,
+    event_title TEXT NOT NULL,
+    event_start TIMESTAMPTZ NOT NULL,
+    tier_name TEXT NOT NULL,
+    amount_cents INTEGER NOT NULL,
+    discount_cents INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL,
+    payment_id TEXT,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organizer_id, number)
+);
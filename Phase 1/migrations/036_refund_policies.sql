@@ -0,0 +1,12 @@
+-- Refund rules per event: a ticket cancelled at least days_before days before
+-- the event starts gets percent of its price back. The rule with the most days
+-- that still applies wins; after the last one, nothing is refunded
+CREATE TABLE IF NOT EXISTS refund_rules (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    days_before INTEGER NOT NULL CHECK (days_before >= 0),
+    percent INTEGER NOT NULL CHECK (percent BETWEEN 0 AND 100),
+    PRIMARY KEY (event_id, days_before)
+);
+
+-- What was actually paid back for a refunded ticket; NULL means the full amount
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS refunded_cents INTEGER;
@@ -0,0 +1,43 @@
+-- Vendors and suppliers (caterers, AV, ...) an event works with, with their
+-- contact details, contract and payment status
+CREATE TABLE IF NOT EXISTS vendors (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    contact_name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    contract_status TEXT NOT NULL DEFAULT 'none'
+        CHECK (contract_status IN ('none', 'draft', 'sent', 'signed', 'cancelled')),
+    contract_url TEXT,
+    amount_cents INTEGER CHECK (amount_cents >= 0),
+    paid_cents INTEGER NOT NULL DEFAULT 0 CHECK (paid_cents >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    payment_status TEXT NOT NULL DEFAULT 'unpaid'
+        CHECK (payment_status IN ('unpaid', 'deposit_paid', 'paid')),
+    payment_due_at TIMESTAMPTZ,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendors_event ON vendors (event_id);
+
+-- Lets vendor_tasks require a task of the vendor's own event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_id_event ON tasks (id, event_id);
+
+-- Tasks linked to a vendor, e.g. "confirm menu" for the caterer
+CREATE TABLE IF NOT EXISTS vendor_tasks (
+    vendor_id INTEGER NOT NULL,
+    task_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    PRIMARY KEY (vendor_id, task_id),
+    FOREIGN KEY (vendor_id, event_id) REFERENCES vendors (id, event_id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id, event_id) REFERENCES tasks (id, event_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_tasks_task ON vendor_tasks (task_id);
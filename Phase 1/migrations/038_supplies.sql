@@ -0,0 +1,17 @@
+-- Supplies checklist per event (drinks, chairs, a salad for the potluck).
+-- Each item is claimed by at most one participant, who marks it purchased
+CREATE TABLE IF NOT EXISTS supplies (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity >= 1),
+    notes TEXT NOT NULL DEFAULT '',
+    claimed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMPTZ,
+    purchased BOOLEAN NOT NULL DEFAULT false,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplies_event ON supplies (event_id);
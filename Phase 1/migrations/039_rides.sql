@@ -0,0 +1,24 @@
+-- Carpooling: participants offer seats in their car to an event
+CREATE TABLE IF NOT EXISTS rides (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    driver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    origin TEXT NOT NULL,
+    departure_at TIMESTAMPTZ NOT NULL,
+    seats INTEGER NOT NULL CHECK (seats >= 1),
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rides_event ON rides (event_id, departure_at);
+
+-- Seats taken in a ride. A participant rides with at most one driver per event
+CREATE TABLE IF NOT EXISTS ride_passengers (
+    ride_id INTEGER NOT NULL REFERENCES rides(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (ride_id, user_id),
+    UNIQUE (event_id, user_id)
+);
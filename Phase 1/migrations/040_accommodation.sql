@@ -0,0 +1,33 @@
+-- Lodging options for an event (hotels with a room block, a shared house)
+CREATE TABLE IF NOT EXISTS lodgings (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    url TEXT,
+    notes TEXT NOT NULL DEFAULT '',
+    rooms INTEGER CHECK (rooms >= 1),
+    price_cents INTEGER CHECK (price_cents >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    booking_deadline DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (id, event_id)
+);
+
+-- Where and when each participant stays. A NULL lodging_id means they make
+-- their own arrangements
+CREATE TABLE IF NOT EXISTS accommodation_stays (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lodging_id INTEGER,
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id),
+    FOREIGN KEY (lodging_id, event_id) REFERENCES lodgings (id, event_id) ON DELETE CASCADE,
+    CHECK (check_out > check_in)
+);
+
+CREATE INDEX IF NOT EXISTS idx_accommodation_stays_lodging ON accommodation_stays (lodging_id);
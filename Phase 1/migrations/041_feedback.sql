@@ -0,0 +1,35 @@
+-- Post-event survey questions: a 1-5 rating or a free text comment
+CREATE TABLE IF NOT EXISTS feedback_questions (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    prompt TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('rating','text')),
+    required BOOLEAN NOT NULL DEFAULT false,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feedback_questions_event_id ON feedback_questions (event_id, position);
+
+-- Who submitted feedback, so nobody answers twice. Summaries never report
+-- answers together with the user
+CREATE TABLE IF NOT EXISTS feedback_responses (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS feedback_answers (
+    question_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (question_id, user_id),
+    FOREIGN KEY (question_id, event_id) REFERENCES feedback_questions (id, event_id) ON DELETE CASCADE,
+    FOREIGN KEY (event_id, user_id) REFERENCES feedback_responses (event_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_feedback_answers_event_id ON feedback_answers (event_id);
@@ -0,0 +1,13 @@
+-- When a participant was checked in at the door
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
+
+-- Wording of an event's attendance certificates. {name}, {event} and {date}
+-- are filled in per participant
+CREATE TABLE IF NOT EXISTS certificate_templates (
+    event_id INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    signer_name TEXT NOT NULL DEFAULT '',
+    signer_title TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
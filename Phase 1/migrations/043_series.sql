@@ -0,0 +1,26 @@
+-- Named groups of related events (a meetup's monthly occurrences). New
+-- occurrences invite the series' subscribers with default_role; their
+-- invitations expire invite_expiry_days after being sent, if set
+CREATE TABLE IF NOT EXISTS event_series (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    default_role TEXT NOT NULL DEFAULT 'attendee',
+    invite_expiry_days INTEGER CHECK (invite_expiry_days >= 1),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_series_owner_id ON event_series (owner_id);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS series_id INTEGER REFERENCES event_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_series_id ON events (series_id) WHERE series_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS series_subscriptions (
+    series_id INTEGER NOT NULL REFERENCES event_series(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subscribed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (series_id, user_id)
+);
@@ -0,0 +1,25 @@
+-- Users following an organizer or a series, to hear about their newly
+-- published events
+CREATE TABLE IF NOT EXISTS organizer_follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organizer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, organizer_id),
+    CHECK (follower_id <> organizer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organizer_follows_organizer_id ON organizer_follows (organizer_id);
+
+CREATE TABLE IF NOT EXISTS series_follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    series_id INTEGER NOT NULL REFERENCES event_series(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, series_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_follows_series_id ON series_follows (series_id);
+
+-- Followers hear about an event only the first time it is published, not
+-- again after it was unpublished and published back
+ALTER TABLE events ADD COLUMN IF NOT EXISTS first_published_at TIMESTAMPTZ;
+UPDATE events SET first_published_at = published_at WHERE first_published_at IS NULL AND published_at IS NOT NULL;
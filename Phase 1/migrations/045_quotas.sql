@@ -0,0 +1,13 @@
+-- Per-user overrides of the deployment-wide quotas (QUOTA_* settings). A NULL
+-- limit falls back to the deployment default, 0 means unlimited.
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    active_events INTEGER CHECK (active_events >= 0),
+    invites_per_day INTEGER CHECK (invites_per_day >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Counting the invitations a user sent during the last day.
+CREATE INDEX IF NOT EXISTS idx_event_participants_invited_by
+    ON event_participants (invited_by, invited_at)
+    WHERE invited_by IS NOT NULL;
@@ -0,0 +1,27 @@
+-- Reports of spam and abuse against events or users, reviewed by admins
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    reporter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INTEGER REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'scam', 'harassment', 'inappropriate', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed', 'actioned')),
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((event_id IS NULL) <> (user_id IS NULL))
+);
+
+-- One open report per reporter and target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_pending_event
+    ON reports (reporter_id, event_id) WHERE status = 'pending' AND event_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_pending_user
+    ON reports (reporter_id, user_id) WHERE status = 'pending' AND user_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_reports_status_created_at ON reports (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_event_id ON reports (event_id) WHERE event_id IS NOT NULL;
+
+-- Published events are hidden from their public page once enough users report
+-- them, until an admin dismisses the reports
+ALTER TABLE events ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
@@ -0,0 +1,7 @@
+-- Events flagged by content moderation on publish wait in the report review
+-- queue as reports without a reporter
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'scam', 'harassment', 'inappropriate', 'other', 'moderation'));
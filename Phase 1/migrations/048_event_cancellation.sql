@@ -0,0 +1,3 @@
+-- Cancelled events stay on record for their participants but no longer count
+-- as active
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
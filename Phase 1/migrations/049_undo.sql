@@ -0,0 +1,17 @@
+-- Deleted events are kept for the undo window and purged afterwards
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Undo tokens handed out by destructive actions. Only the SHA-256 hash of a
+-- token is stored; payload holds what is needed to reverse the action
+CREATE TABLE IF NOT EXISTS undo_actions (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('delete_events', 'cancel_events', 'revoke_invite')),
+    event_ids INTEGER[] NOT NULL,
+    payload JSONB,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_undo_actions_expires_at ON undo_actions (expires_at);
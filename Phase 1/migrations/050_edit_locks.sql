@@ -0,0 +1,8 @@
+-- Short-lived locks co-organizers take while editing an event. A lock past
+-- expires_at is free to take over
+CREATE TABLE IF NOT EXISTS event_edit_locks (
+    event_id INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
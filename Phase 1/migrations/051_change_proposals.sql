@@ -0,0 +1,20 @@
+-- Events where co-organizers propose changes for an organizer to approve
+ALTER TABLE events ADD COLUMN IF NOT EXISTS require_change_approval BOOLEAN NOT NULL DEFAULT false;
+
+-- Proposed changes to an event. changes holds the requested fields as sent to
+-- PATCH /events/{id}; diff the values they replace at proposal time
+CREATE TABLE IF NOT EXISTS change_proposals (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    proposed_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changes JSONB NOT NULL,
+    diff JSONB NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decision_comment TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_proposals_event_status ON change_proposals (event_id, status, created_at);
@@ -0,0 +1,25 @@
+-- Each organizer's secret for signing requests to their inbound webhooks.
+-- Stored as is, since verifying an HMAC needs the secret itself
+CREATE TABLE IF NOT EXISTS inbound_webhook_secrets (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Endpoints external systems (form builders, CRMs, ...) call to create events
+-- or tasks of an event. mapping maps the created fields to paths in the payload
+CREATE TABLE IF NOT EXISTS inbound_webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    target TEXT NOT NULL CHECK (target IN ('event', 'task')),
+    event_id INTEGER REFERENCES events(id) ON DELETE CASCADE,
+    mapping JSONB NOT NULL,
+    last_received_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((target = 'task') = (event_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_webhooks_user ON inbound_webhooks (user_id);
@@ -0,0 +1,23 @@
+-- When a task was marked done, NULL while it is open
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+-- When a participant last changed their RSVP
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS responded_at TIMESTAMPTZ;
+UPDATE event_participants SET responded_at = updated_at WHERE attendance IS NOT NULL AND responded_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks (completed_at, id) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_event_participants_responded ON event_participants (responded_at) WHERE responded_at IS NOT NULL;
+
+-- API keys integrations (no-code tools, scripts) call the API with. Only
+-- hashes of the keys are stored; prefix identifies a key to its owner
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
@@ -0,0 +1,2 @@
+-- Language notifications and emails are sent to the user in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale TEXT;
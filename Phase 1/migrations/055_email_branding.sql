@@ -0,0 +1,9 @@
+-- How emails about the events a user organizes look: their logo and accent
+-- color, and where replies go
+CREATE TABLE IF NOT EXISTS email_branding (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    logo_url TEXT,
+    color TEXT,
+    reply_to TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
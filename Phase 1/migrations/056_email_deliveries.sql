@@ -0,0 +1,26 @@
+-- Every email sent for a notification, one row per message and recipient,
+-- with its attempts and what became of it. token identifies the email to the
+-- open-tracking pixel and the mail provider's delivery webhooks
+CREATE TABLE IF NOT EXISTS email_deliveries (
+    id SERIAL PRIMARY KEY,
+    message_id TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    event_id INTEGER REFERENCES events(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    bounced_at TIMESTAMPTZ,
+    opened_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (message_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_event ON email_deliveries (event_id, created_at) WHERE event_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_created ON email_deliveries (created_at);
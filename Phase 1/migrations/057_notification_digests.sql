@@ -0,0 +1,13 @@
+-- Notifications waiting to be emailed together as one digest. key is the
+-- channel and recipient the digest goes to; the digest is sent once its
+-- oldest item is due
+CREATE TABLE IF NOT EXISTS notification_digests (
+    id SERIAL PRIMARY KEY,
+    key TEXT NOT NULL,
+    item JSONB NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digests_key ON notification_digests (key, id);
+CREATE INDEX IF NOT EXISTS idx_notification_digests_due ON notification_digests (due_at);
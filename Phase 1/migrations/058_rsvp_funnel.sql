@@ -0,0 +1,5 @@
+-- When an invitee first opened the event and first answered its invitation,
+-- for the RSVP funnel. responded_at only keeps their latest answer
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS viewed_at TIMESTAMPTZ;
+ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS first_responded_at TIMESTAMPTZ;
+UPDATE event_participants SET first_responded_at = responded_at WHERE first_responded_at IS NULL AND responded_at IS NOT NULL;
@@ -0,0 +1,68 @@
+-- Text search runs on a copy of the events' searchable columns partitioned by
+-- the year of start_time (UTC), so a search bounded by date only scans the
+-- partitions, and trigram indexes, of those years; past years stop slowing
+-- down searches for upcoming events. events itself cannot be partitioned:
+-- its id would no longer be unique on its own, which the many foreign keys
+-- referencing it need. A trigger keeps the copy in sync.
+CREATE TABLE IF NOT EXISTS event_search (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    start_time TIMESTAMPTZ NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    location TEXT,
+    PRIMARY KEY (event_id, start_time)
+) PARTITION BY RANGE (start_time);
+
+-- Indexes on the parent are created on every partition
+CREATE INDEX IF NOT EXISTS idx_event_search_start_time ON event_search (start_time);
+CREATE INDEX IF NOT EXISTS idx_event_search_title_trgm ON event_search USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_event_search_location_trgm ON event_search USING gin (location gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_event_search_description_trgm ON event_search USING gin (description gin_trgm_ops);
+
+-- Creates the partition of a year unless it exists. The advisory lock keeps
+-- concurrent inserts from creating it twice.
+CREATE OR REPLACE FUNCTION event_search_partition(search_year INTEGER) RETURNS void AS $$
+DECLARE
+    partition_name TEXT := 'event_search_' || search_year;
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN;
+    END IF;
+    PERFORM pg_advisory_xact_lock(hashtext('event_search_partition'));
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN;
+    END IF;
+    EXECUTE format('CREATE TABLE %I PARTITION OF event_search FOR VALUES FROM (%L) TO (%L)',
+        partition_name,
+        make_timestamptz(search_year, 1, 1, 0, 0, 0, 'UTC'),
+        make_timestamptz(search_year + 1, 1, 1, 0, 0, 0, 'UTC'));
+END $$ LANGUAGE plpgsql;
+
+-- A changed start time moves the row to its new year's partition
+CREATE OR REPLACE FUNCTION sync_event_search() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        DELETE FROM event_search WHERE event_id = OLD.id AND start_time = OLD.start_time;
+    END IF;
+    PERFORM event_search_partition(extract(year FROM NEW.start_time AT TIME ZONE 'UTC')::int);
+    INSERT INTO event_search (event_id, start_time, title, description, location)
+    VALUES (NEW.id, NEW.start_time, NEW.title, NEW.description, NEW.location);
+    RETURN NULL;
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS events_sync_search ON events;
+CREATE TRIGGER events_sync_search
+    AFTER INSERT OR UPDATE OF title, description, location, start_time ON events
+    FOR EACH ROW EXECUTE FUNCTION sync_event_search();
+
+-- Partitions for the years with events, this year and the next
+SELECT event_search_partition(y)
+FROM (
+    SELECT DISTINCT extract(year FROM start_time AT TIME ZONE 'UTC')::int AS y FROM events
+    UNION
+    SELECT extract(year FROM now() AT TIME ZONE 'UTC')::int + n FROM generate_series(0, 1) n
+) years;
+
+INSERT INTO event_search (event_id, start_time, title, description, location)
+SELECT id, start_time, title, description, location FROM events
+ON CONFLICT DO NOTHING;
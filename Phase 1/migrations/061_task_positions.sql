@@ -0,0 +1,6 @@
+-- Tasks in the order organizers arranged them. Positions are fractional so
+-- a task can be moved between two others without renumbering; tasks never
+-- arranged have none and are listed after the others by due date
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_event_position ON tasks (event_id, position);
@@ -0,0 +1,15 @@
+-- Task boards: each event's columns, in order, and the column each task is
+-- in. Boards get the default columns when first used. Tasks in no column,
+-- e.g. after theirs was removed, show in the first column, or in the last
+-- once completed
+CREATE TABLE IF NOT EXISTS board_columns (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_board_columns_event_id ON board_columns (event_id, position);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS column_id INTEGER REFERENCES board_columns(id) ON DELETE SET NULL;
@@ -0,0 +1,20 @@
+-- Task labels: free-form names, e.g. "catering" or "blocked", each event
+-- keeps with a color and puts on its tasks. Names are unique per event
+-- regardless of case
+CREATE TABLE IF NOT EXISTS task_labels (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_labels_event_name ON task_labels (event_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_label_assignments (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id INTEGER NOT NULL REFERENCES task_labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_label_assignments_label_id ON task_label_assignments (label_id);
@@ -0,0 +1,18 @@
+-- Time estimates and tracking for tasks. Entries are either timers, running
+-- while ended_at is NULL, or logged by hand; a user runs at most one timer
+-- at a time
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimated_minutes INTEGER CHECK (estimated_minutes > 0);
+
+CREATE TABLE IF NOT EXISTS task_time_entries (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_time_entries_task_id ON task_time_entries (task_id, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_time_entries_running ON task_time_entries (user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,13 @@
+-- Cached previews of the pages linked from event descriptions and comments,
+-- fetched in the background. failed marks pages without a usable preview;
+-- claimed_at is set while a fetch is queued.
+CREATE TABLE IF NOT EXISTS link_previews (
+    url TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    image_url TEXT NOT NULL DEFAULT '',
+    site_name TEXT NOT NULL DEFAULT '',
+    failed BOOLEAN NOT NULL DEFAULT false,
+    fetched_at TIMESTAMPTZ,
+    claimed_at TIMESTAMPTZ
+);
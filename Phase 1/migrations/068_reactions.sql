@@ -0,0 +1,14 @@
+-- Emoji reactions to comments and announcements, one row per user and emoji.
+-- Exactly one of comment_id and announcement_id is set.
+CREATE TABLE IF NOT EXISTS reactions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    announcement_id INTEGER REFERENCES announcements(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((comment_id IS NULL) <> (announcement_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_comment ON reactions (comment_id, user_id, emoji) WHERE comment_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_announcement ON reactions (announcement_id, user_id, emoji) WHERE announcement_id IS NOT NULL;
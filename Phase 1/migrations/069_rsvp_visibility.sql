@@ -0,0 +1,5 @@
+-- What participants who cannot manage participants see of an event's RSVPs:
+-- the participant list (full), how many gave each answer (counts), or nothing
+-- (none). counts is what they saw before this setting.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS rsvp_visibility TEXT NOT NULL DEFAULT 'counts'
+    CHECK (rsvp_visibility IN ('full', 'counts', 'none'));
@@ -0,0 +1,10 @@
+-- Tickets move to refunding, still holding their seat, before the payment
+-- provider is asked for a refund, so that concurrent refunds of the same
+-- ticket cannot both reach the provider
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_check;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_check
+    CHECK (status IN ('pending','claimed','refunding','cancelled','refunded'));
+
+DROP INDEX IF EXISTS idx_tickets_one_per_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_one_per_user ON tickets (event_id, user_id) WHERE status IN ('pending','claimed','refunding');
+CREATE INDEX IF NOT EXISTS idx_tickets_refunding ON tickets (updated_at) WHERE status = 'refunding';
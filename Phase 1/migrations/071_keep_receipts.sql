@@ -0,0 +1,22 @@
+-- Receipts are invoices and must outlive their ticket, event, buyer and
+-- organizer: they copy everything they show, so deleting those only unlinks
+-- them, and the organizer's invoice numbers keep no gaps
+ALTER TABLE receipts ALTER COLUMN ticket_id DROP NOT NULL;
+ALTER TABLE receipts ALTER COLUMN event_id DROP NOT NULL;
+ALTER TABLE receipts ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE receipts ALTER COLUMN organizer_id DROP NOT NULL;
+
+ALTER TABLE receipts DROP CONSTRAINT IF EXISTS receipts_ticket_id_fkey;
+ALTER TABLE receipts ADD CONSTRAINT receipts_ticket_id_fkey
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE SET NULL;
+ALTER TABLE receipts DROP CONSTRAINT IF EXISTS receipts_event_id_fkey;
+ALTER TABLE receipts ADD CONSTRAINT receipts_event_id_fkey
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE SET NULL;
+ALTER TABLE receipts DROP CONSTRAINT IF EXISTS receipts_user_id_fkey;
+ALTER TABLE receipts ADD CONSTRAINT receipts_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE receipts DROP CONSTRAINT IF EXISTS receipts_organizer_id_fkey;
+ALTER TABLE receipts ADD CONSTRAINT receipts_organizer_id_fkey
+    FOREIGN KEY (organizer_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_receipts_event_id ON receipts (event_id);
@@ -0,0 +1,11 @@
+-- Signatures of recent deliveries to each inbound webhook, so a captured
+-- request replayed while its timestamp is still accepted is not applied again.
+-- Rows are pruned once their signature's timestamp would be rejected anyway
+CREATE TABLE IF NOT EXISTS inbound_webhook_deliveries (
+    webhook_id INTEGER NOT NULL REFERENCES inbound_webhooks(id) ON DELETE CASCADE,
+    signature TEXT NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (webhook_id, signature)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_webhook_deliveries_received ON inbound_webhook_deliveries (webhook_id, received_at);